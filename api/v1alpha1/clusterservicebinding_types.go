@@ -0,0 +1,265 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1alpha1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Instance",type=string,JSONPath=`.spec.serviceInstanceRef.name`
+// +kubebuilder:printcolumn:name="Secret Namespace",type=string,JSONPath=`.spec.secretNamespace`
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +genclient
+// +genclient:nonNamespaced
+
+// ClusterServiceBinding is the Schema for the clusterservicebindings API
+type ClusterServiceBinding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterServiceBindingSpec `json:"spec,omitempty"`
+
+	// +kubebuilder:default={"observedGeneration":-1}
+	Status ClusterServiceBindingStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterServiceBindingList contains a list of ClusterServiceBinding
+type ClusterServiceBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterServiceBinding `json:"items"`
+}
+
+// ClusterServiceBindingSpec defines the desired state of ClusterServiceBinding
+type ClusterServiceBindingSpec struct {
+	// Name of the service binding (service key) in Cloud Foundry; if unspecified, metadata.name will be used.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name,omitempty"`
+
+	// Reference to a ServiceInstance resource, identifying the Cloud Foundry service instance this binding refers to;
+	// the ServiceInstance must belong to a ClusterSpace.
+	ServiceInstanceRef ServiceInstanceReference `json:"serviceInstanceRef"`
+
+	// Binding parameters.
+	// Since cluster service bindings have no namespace, parameters cannot be read from secrets or config maps;
+	// do not provide any sensitive data here.
+	// +optional
+	Parameters *apiextensionsv1.JSON `json:"parameters,omitempty"`
+
+	// Labels and annotations to be set on the Cloud Foundry binding (in addition to the ones maintained by the operator).
+	// +optional
+	Metadata *CFMetadata `json:"metadata,omitempty"`
+
+	// Secret name where the binding credentials shall be stored (in the namespace given by SecretNamespace).
+	// If unspecified, metadata.name will be used.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName,omitempty"`
+
+	// Namespace where the binding credentials shall be stored. The binding secret is owned by the cluster service binding.
+	// Must be the namespace of the referenced service instance, or one of the namespaces allowed by the operator configuration (secretNamespaces).
+	// +kubebuilder:validation:MinLength=1
+	SecretNamespace string `json:"secretNamespace"`
+
+	// Secret key (referring to SecretName) where the binding credentials will be stored.
+	// If unspecified, the top level keys of the binding credentials will become the secret keys.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	SecretKey string `json:"secretKey,omitempty"`
+
+	// Type of the binding secret (for example kubernetes.io/basic-auth, or a custom type); defaults to Opaque.
+	// Note that some types require certain keys to be present (which then must be provided by the binding credentials).
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	SecretType string `json:"secretType,omitempty"`
+
+	// SAP binding metadata added to the binding secret, overriding the operator default (sapBindingMetadata).
+	// +optional
+	SAPBindingMetadata *SAPBindingMetadata `json:"sapBindingMetadata,omitempty"`
+}
+
+// ServiceInstanceReference references a ServiceInstance resource in some namespace.
+type ServiceInstanceReference struct {
+	// Namespace of the ServiceInstance.
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// Name of the ServiceInstance.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// ClusterServiceBindingStatus defines the observed state of ClusterServiceBinding
+type ClusterServiceBindingStatus struct {
+	// Observed generation; this is the last generation which was successfully applied (that is, for which
+	// the Ready condition became True), so it lags behind metadata.generation while changes are in progress
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Whether the object is ready, that is, whether the Ready condition is True; note that this refers
+	// to status.observedGeneration, which must therefore be compared with metadata.generation
+	// +optional
+	Ready bool `json:"ready"`
+
+	// Last reconciliation timestamp
+	// +optional
+	LastReconciledAt *metav1.Time `json:"lastReconciledAt,omitempty"`
+
+	// Last modification timestamp (when the last create/update/delete request was sent to Cloud Foundry)
+	// +optional
+	LastModifiedAt *metav1.Time `json:"lastModifiedAt,omitempty"`
+
+	// Cloud Foundry space guid
+	// +optional
+	SpaceGuid string `json:"spaceGuid,omitempty"`
+
+	// Cloud Foundry service instance guid
+	// +optional
+	ServiceInstanceGuid string `json:"serviceInstanceGuid,omitempty"`
+
+	// Digest identifying the current target state of the service instance (including parameters)
+	// +optional
+	ServiceInstanceDigest string `json:"serviceInstanceDigest,omitempty"`
+
+	// Cloud Foundry service binding guid
+	// +optional
+	ServiceBindingGuid string `json:"serviceBindingGuid,omitempty"`
+
+	// Digest identifying the credentials last written to the binding secret
+	// +optional
+	CredentialsDigest string `json:"credentialsDigest,omitempty"`
+
+	// Names of the (top level) keys of the binding credentials last written (not their values)
+	// +optional
+	CredentialKeys []string `json:"credentialKeys,omitempty"`
+
+	// URL of the Cloud Foundry API endpoint used by the most recent reconciliation
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Last operation performed by Cloud Foundry on the service binding (as of the most recent reconciliation);
+	// the timestamps allow to tell for how long an operation has been running
+	// +optional
+	LastOperation *LastOperation `json:"lastOperation,omitempty"`
+
+	// List of status conditions to indicate the status of a ClusterServiceBinding.
+	// Known condition types are `Ready`, `Synced`, `CredentialsReady`, `DeletionBlocked`, `CFReachable`, `Paused`, `Timeout`.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []ClusterServiceBindingCondition `json:"conditions,omitempty"`
+
+	// Readable form of the state.
+	// +optional
+	State ClusterServiceBindingState `json:"state,omitempty"`
+}
+
+// ClusterServiceBindingCondition contains condition information for a ClusterServiceBinding.
+type ClusterServiceBindingCondition struct {
+	// Type of the condition, known values are ('Ready', 'Synced', 'CredentialsReady', 'DeletionBlocked', 'CFReachable').
+	Type ClusterServiceBindingConditionType `json:"type"`
+
+	// Status of the condition, one of ('True', 'False', 'Unknown').
+	Status ConditionStatus `json:"status"`
+
+	// LastTransitionTime is the timestamp corresponding to the last status
+	// change of this condition.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Reason is a brief machine readable explanation for the condition's last
+	// transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human readable description of the details of the last
+	// transition, complementing reason.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the metadata.generation of the object the condition was set for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// ClusterServiceBindingConditionType represents a ClusterServiceBinding condition value.
+type ClusterServiceBindingConditionType string
+
+const (
+	// ClusterServiceBindingConditionReady represents the fact that a given cluster service binding is ready.
+	ClusterServiceBindingConditionReady ClusterServiceBindingConditionType = "Ready"
+	// ClusterServiceBindingConditionSynced represents the fact that the Cloud Foundry binding reflects the current spec;
+	// it is False if applying the spec failed, and Unknown while an operation is in progress.
+	ClusterServiceBindingConditionSynced ClusterServiceBindingConditionType = "Synced"
+	// ClusterServiceBindingConditionCredentialsReady represents the fact that the binding credentials were written to the binding secret.
+	ClusterServiceBindingConditionCredentialsReady ClusterServiceBindingConditionType = "CredentialsReady"
+	// ClusterServiceBindingConditionDeletionBlocked represents the fact that the deletion of the cluster service binding
+	// is blocked (e.g. by foreign finalizers); it is only present while the cluster service binding is being deleted.
+	ClusterServiceBindingConditionDeletionBlocked ClusterServiceBindingConditionType = "DeletionBlocked"
+	// ClusterServiceBindingConditionCFReachable represents the fact that the Cloud Foundry API was reachable during the last reconciliation.
+	ClusterServiceBindingConditionCFReachable ClusterServiceBindingConditionType = "CFReachable"
+	// ClusterServiceBindingConditionPaused represents the fact that reconciliation is paused by the annotation service-operator.cf.cs.sap.com/paused;
+	// it is only present while the annotation is set.
+	ClusterServiceBindingConditionPaused ClusterServiceBindingConditionType = "Paused"
+	// ClusterServiceBindingConditionTimeout represents the fact that the last reconciliation did not complete within the maximum reconcile duration
+	// (see annotation service-operator.cf.cs.sap.com/reconcile-call-timeout); it is only present while this is the case.
+	ClusterServiceBindingConditionTimeout ClusterServiceBindingConditionType = "Timeout"
+)
+
+// ClusterServiceBindingState represents a condition state in a readable form
+// +kubebuilder:validation:Enum=Processing;Deleting;Ready;Error
+type ClusterServiceBindingState string
+
+// These are valid condition states
+const (
+	// ClusterServiceBindingStateProcessing represents the fact that the cluster service binding is reconciling
+	ClusterServiceBindingStateProcessing ClusterServiceBindingState = "Processing"
+
+	// ClusterServiceBindingStateDeleting represents the fact that the cluster service binding is being deleted
+	ClusterServiceBindingStateDeleting ClusterServiceBindingState = "Deleting"
+
+	// ClusterServiceBindingStateReady represents the fact that the cluster service binding is ready
+	ClusterServiceBindingStateReady ClusterServiceBindingState = "Ready"
+
+	// ClusterServiceBindingStateError represents the fact that the cluster service binding is not ready resp. has an error
+	ClusterServiceBindingStateError ClusterServiceBindingState = "Error"
+)
+
+func (clusterServiceBinding *ClusterServiceBinding) SetReadyCondition(conditionStatus ConditionStatus, reason, message string) {
+	setClusterServiceBindingReadyCondition(clusterServiceBinding, conditionStatus, reason, message)
+}
+
+func (clusterServiceBinding *ClusterServiceBinding) GetReadyCondition() *ClusterServiceBindingCondition {
+	return getClusterServiceBindingReadyCondition(clusterServiceBinding)
+}
+
+func (clusterServiceBinding *ClusterServiceBinding) SetCondition(conditionType ClusterServiceBindingConditionType, conditionStatus ConditionStatus, reason, message string) {
+	setClusterServiceBindingCondition(clusterServiceBinding, conditionType, conditionStatus, reason, message)
+}
+
+func (clusterServiceBinding *ClusterServiceBinding) GetCondition(conditionType ClusterServiceBindingConditionType) *ClusterServiceBindingCondition {
+	return getClusterServiceBindingCondition(clusterServiceBinding, conditionType)
+}
+
+func (clusterServiceBinding *ClusterServiceBinding) RemoveCondition(conditionType ClusterServiceBindingConditionType) {
+	removeClusterServiceBindingCondition(clusterServiceBinding, conditionType)
+}
+
+func (clusterServiceBinding *ClusterServiceBinding) IsReady() bool {
+	return isClusterServiceBindingReady(clusterServiceBinding)
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterServiceBinding{}, &ClusterServiceBindingList{})
+}
@@ -0,0 +1,93 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func setClusterServiceBindingReadyCondition(clusterServiceBinding *ClusterServiceBinding, conditionStatus ConditionStatus, reason, message string) {
+	setClusterServiceBindingCondition(clusterServiceBinding, ClusterServiceBindingConditionReady, conditionStatus, reason, message)
+
+	status := &clusterServiceBinding.Status
+	// the observed generation is only bumped after a successful sync, such that health checks (e.g. of GitOps tools)
+	// do not consider the object ready while changes are still being applied
+	status.Ready = conditionStatus == ConditionTrue
+	switch conditionStatus {
+	case ConditionTrue:
+		status.ObservedGeneration = clusterServiceBinding.Generation
+		status.State = ClusterServiceBindingStateReady
+	case ConditionFalse:
+		status.State = ClusterServiceBindingStateError
+	default:
+		if clusterServiceBinding.DeletionTimestamp.IsZero() {
+			status.State = ClusterServiceBindingStateProcessing
+		} else {
+			status.State = ClusterServiceBindingStateDeleting
+		}
+	}
+}
+
+func getClusterServiceBindingReadyCondition(clusterServiceBinding *ClusterServiceBinding) *ClusterServiceBindingCondition {
+	return getClusterServiceBindingCondition(clusterServiceBinding, ClusterServiceBindingConditionReady)
+}
+
+// setClusterServiceBindingCondition adds or updates the condition of the given type; the transition time is only updated if the status changes.
+func setClusterServiceBindingCondition(clusterServiceBinding *ClusterServiceBinding, conditionType ClusterServiceBindingConditionType, conditionStatus ConditionStatus, reason, message string) {
+	status := &clusterServiceBinding.Status
+	condition := getClusterServiceBindingCondition(clusterServiceBinding, conditionType)
+	if condition == nil {
+		condition = &ClusterServiceBindingCondition{
+			Type: conditionType,
+		}
+		status.Conditions = append(status.Conditions, *condition)
+	}
+	if condition.Status != conditionStatus {
+		condition.Status = conditionStatus
+		now := metav1.Now()
+		condition.LastTransitionTime = &now
+	}
+	condition.Reason = reason
+	condition.Message = message
+	condition.ObservedGeneration = clusterServiceBinding.GetGeneration()
+
+	for i, c := range status.Conditions {
+		if c.Type == conditionType {
+			status.Conditions[i] = *condition
+			break
+		}
+	}
+}
+
+func getClusterServiceBindingCondition(clusterServiceBinding *ClusterServiceBinding, conditionType ClusterServiceBindingConditionType) *ClusterServiceBindingCondition {
+	status := &clusterServiceBinding.Status
+	for _, c := range status.Conditions {
+		if c.Type == conditionType {
+			return &c
+		}
+	}
+	return nil
+}
+
+func removeClusterServiceBindingCondition(clusterServiceBinding *ClusterServiceBinding, conditionType ClusterServiceBindingConditionType) {
+	status := &clusterServiceBinding.Status
+	for i, c := range status.Conditions {
+		if c.Type == conditionType {
+			status.Conditions = append(status.Conditions[:i], status.Conditions[i+1:]...)
+			return
+		}
+	}
+}
+
+func isClusterServiceBindingReady(clusterServiceBinding *ClusterServiceBinding) bool {
+	if clusterServiceBinding.Status.ObservedGeneration != clusterServiceBinding.Generation {
+		return false
+	}
+	if c := getClusterServiceBindingReadyCondition(clusterServiceBinding); c != nil {
+		return c.Status == ConditionTrue
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var clusterservicebindinglog = logf.Log.WithName("clusterservicebinding-resource")
+
+// SetupWebhookWithManager registers the webhooks for ClusterServiceBinding; spec.secretNamespace must be one of the given
+// secretNamespaces (or the namespace of the referenced service instance).
+func (r *ClusterServiceBinding) SetupWebhookWithManager(mgr ctrl.Manager, secretNamespaces []string) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&clusterServiceBindingValidator{secretNamespaces: secretNamespaces}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-cf-cs-sap-com-v1alpha1-clusterservicebinding,mutating=true,failurePolicy=fail,sideEffects=None,groups=cf.cs.sap.com,resources=clusterservicebindings,verbs=create;update,versions=v1alpha1,name=mclusterservicebinding.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &ClusterServiceBinding{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *ClusterServiceBinding) Default() {
+	clusterservicebindinglog.V(2).Info("Default", "name", r.Name)
+
+	if r.Spec.Name == "" {
+		r.Spec.Name = r.Name
+	}
+	if r.Spec.SecretName == "" {
+		r.Spec.SecretName = r.Name
+	}
+}
+
+// +kubebuilder:webhook:path=/validate-cf-cs-sap-com-v1alpha1-clusterservicebinding,mutating=false,failurePolicy=fail,sideEffects=None,groups=cf.cs.sap.com,resources=clusterservicebindings,verbs=create;update,versions=v1alpha1,name=vclusterservicebinding.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &ClusterServiceBinding{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterServiceBinding) ValidateCreate() (admission.Warnings, error) {
+	return r.validateCreate(nil)
+}
+
+func (r *ClusterServiceBinding) validateCreate(secretNamespaces []string) (admission.Warnings, error) {
+	clusterservicebindinglog.V(2).Info("Validate create", "name", r.Name)
+
+	// the secret namespace is immutable, so it is only checked on creation (otherwise, changes of the configuration would block updates,
+	// including the removal of finalizers)
+	if err := r.Spec.validateSecretNamespace(secretNamespaces); err != nil {
+		return nil, err
+	}
+
+	if err := validateCFMetadata(r.Spec.Metadata, "spec.metadata"); err != nil {
+		return nil, err
+	}
+
+	return annotationWarnings(r.Annotations), nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterServiceBinding) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	clusterservicebindinglog.V(2).Info("Validate update", "name", r.Name)
+	s := old.(*ClusterServiceBinding)
+	// Call the defaulting webhook logic for the old object (because defaulting through the webhook might be incomplete in case of generateName usage)
+	s.Name = r.Name
+	s.Default()
+
+	if r.Spec.Name != s.Spec.Name {
+		return nil, immutableFieldError("spec.name", s.Spec.Name, r.Spec.Name)
+	}
+
+	if r.Spec.ServiceInstanceRef.Namespace != s.Spec.ServiceInstanceRef.Namespace {
+		return nil, immutableFieldError("spec.serviceInstanceRef.namespace", s.Spec.ServiceInstanceRef.Namespace, r.Spec.ServiceInstanceRef.Namespace)
+	}
+
+	if r.Spec.ServiceInstanceRef.Name != s.Spec.ServiceInstanceRef.Name {
+		return nil, immutableFieldError("spec.serviceInstanceRef.name", s.Spec.ServiceInstanceRef.Name, r.Spec.ServiceInstanceRef.Name)
+	}
+
+	// moving the binding secret is not supported (the secret would have to be deleted from the old location)
+	if r.Spec.SecretName != s.Spec.SecretName {
+		return nil, immutableFieldError("spec.secretName", s.Spec.SecretName, r.Spec.SecretName)
+	}
+
+	if r.Spec.SecretNamespace != s.Spec.SecretNamespace {
+		return nil, immutableFieldError("spec.secretNamespace", s.Spec.SecretNamespace, r.Spec.SecretNamespace)
+	}
+
+	if r.Spec.SecretKey != s.Spec.SecretKey {
+		return nil, immutableFieldError("spec.secretKey", s.Spec.SecretKey, r.Spec.SecretKey)
+	}
+
+	if err := validateCFMetadata(r.Spec.Metadata, "spec.metadata"); err != nil {
+		return nil, err
+	}
+
+	return updateWarnings(annotationWarnings(r.Annotations), annotationWarnings(s.Annotations)), nil
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterServiceBinding) ValidateDelete() (admission.Warnings, error) {
+	clusterservicebindinglog.V(2).Info("Validate delete", "name", r.Name)
+
+	return nil, nil
+}
+
+// validateSecretNamespace checks that the binding secret is stored in the namespace of the referenced service instance,
+// or in one of the given secretNamespaces.
+func (s *ClusterServiceBindingSpec) validateSecretNamespace(secretNamespaces []string) error {
+	if s.SecretNamespace != s.ServiceInstanceRef.Namespace && !slices.Contains(secretNamespaces, s.SecretNamespace) {
+		return fmt.Errorf("invalid spec.secretNamespace %s: namespace not allowed by the operator configuration (secretNamespaces)", s.SecretNamespace)
+	}
+	return nil
+}
+
+// clusterServiceBindingValidator extends the validation implemented by ClusterServiceBinding by the namespaces binding secrets may be written to.
+type clusterServiceBindingValidator struct {
+	secretNamespaces []string
+}
+
+var _ admission.CustomValidator = &clusterServiceBindingValidator{}
+
+func (v *clusterServiceBindingValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return obj.(*ClusterServiceBinding).validateCreate(v.secretNamespaces)
+}
+
+func (v *clusterServiceBindingValidator) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	return newObj.(*ClusterServiceBinding).ValidateUpdate(oldObj)
+}
+
+func (v *clusterServiceBindingValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return obj.(*ClusterServiceBinding).ValidateDelete()
+}
@@ -0,0 +1,44 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package v1alpha1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Note: these tests check pure functions; they do not need the envtest based webhook suite, and therefore run standalone.
+
+func TestClusterServiceBindingSecretNamespace(t *testing.T) {
+	g := NewWithT(t)
+
+	newClusterServiceBinding := func(secretNamespace string) *ClusterServiceBinding {
+		return &ClusterServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "binding"},
+			Spec: ClusterServiceBindingSpec{
+				ServiceInstanceRef: ServiceInstanceReference{Namespace: "platform", Name: "instance"},
+				SecretNamespace:    secretNamespace,
+			},
+		}
+	}
+	validator := &clusterServiceBindingValidator{secretNamespaces: []string{"kube-system"}}
+
+	_, err := validator.ValidateCreate(nil, newClusterServiceBinding("kube-system"))
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = validator.ValidateCreate(nil, newClusterServiceBinding("platform"))
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = validator.ValidateCreate(nil, newClusterServiceBinding("other"))
+	g.Expect(err).To(MatchError(ContainSubstring("invalid spec.secretNamespace other")))
+	_, err = newClusterServiceBinding("kube-system").ValidateCreate()
+	g.Expect(err).To(MatchError(ContainSubstring("namespace not allowed")))
+
+	// existing bindings can still be updated (e.g. to remove finalizers), even if the configuration changed
+	old := newClusterServiceBinding("other")
+	old.Default()
+	_, err = validator.ValidateUpdate(nil, old, old.DeepCopy())
+	g.Expect(err).ToNot(HaveOccurred())
+}
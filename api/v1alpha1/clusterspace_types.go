@@ -13,6 +13,9 @@ import (
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
 // +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.status.managementMode`,priority=1
+// +kubebuilder:printcolumn:name="Instances",type=integer,JSONPath=`.status.usage.serviceInstances`,priority=1
+// +kubebuilder:printcolumn:name="Limit",type=integer,JSONPath=`.status.usage.serviceInstancesLimit`,priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +genclient
 // +genclient:nonNamespaced
@@ -67,6 +70,21 @@ func (clusterSpace *ClusterSpace) GetReadyCondition() *SpaceCondition {
 	return getSpaceReadyCondition(clusterSpace)
 }
 
+// Set condition of the given type
+func (clusterSpace *ClusterSpace) SetCondition(conditionType SpaceConditionType, conditionStatus ConditionStatus, reason, message string) {
+	setSpaceCondition(clusterSpace, conditionType, conditionStatus, reason, message)
+}
+
+// Get condition of the given type
+func (clusterSpace *ClusterSpace) GetCondition(conditionType SpaceConditionType) *SpaceCondition {
+	return getSpaceCondition(clusterSpace, conditionType)
+}
+
+// Remove condition of the given type
+func (clusterSpace *ClusterSpace) RemoveCondition(conditionType SpaceConditionType) {
+	removeSpaceCondition(clusterSpace, conditionType)
+}
+
 // Check if space is in a ready state
 func (clusterSpace *ClusterSpace) IsReady() bool {
 	return isSpaceReady(clusterSpace)
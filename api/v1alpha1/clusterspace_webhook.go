@@ -55,7 +55,27 @@ func (r *ClusterSpace) ValidateCreate() (admission.Warnings, error) {
 		return nil, fmt.Errorf("exactly one of spec.guid or spec.name plus spec.organizationName must be specified")
 	}
 
-	return nil, nil
+	if r.Spec.Guid != "" && (len(r.Spec.Developers) > 0 || len(r.Spec.Auditors) > 0 || len(r.Spec.Managers) > 0) {
+		return nil, fmt.Errorf("spec.developers, spec.auditors and spec.managers must not be specified if spec.guid is present")
+	}
+
+	if err := r.Spec.validateAuthSecret(false, "", nil); err != nil {
+		return nil, err
+	}
+
+	if err := r.Spec.validateConfigOverrides(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Spec.validateDeletionPolicy(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Spec.validateCredentialRotationPolicy(); err != nil {
+		return nil, err
+	}
+
+	return spaceWarnings(r), nil
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -67,19 +87,39 @@ func (r *ClusterSpace) ValidateUpdate(old runtime.Object) (admission.Warnings, e
 	s.Default()
 
 	if r.Spec.Guid != s.Spec.Guid {
-		return nil, fmt.Errorf("spec.guid is immutable")
+		return nil, immutableFieldError("spec.guid", s.Spec.Guid, r.Spec.Guid)
 	}
 
 	// TODO: why not to allow name updates ?
 	if r.Spec.Name != s.Spec.Name {
-		return nil, fmt.Errorf("spec.name is immutable")
+		return nil, immutableFieldError("spec.name", s.Spec.Name, r.Spec.Name)
 	}
 
 	if r.Spec.OrganizationName != s.Spec.OrganizationName {
-		return nil, fmt.Errorf("spec.organizationName is immutable")
+		return nil, immutableFieldError("spec.organizationName", s.Spec.OrganizationName, r.Spec.OrganizationName)
 	}
 
-	return nil, nil
+	if r.Spec.Guid != "" && (len(r.Spec.Developers) > 0 || len(r.Spec.Auditors) > 0 || len(r.Spec.Managers) > 0) {
+		return nil, fmt.Errorf("spec.developers, spec.auditors and spec.managers must not be specified if spec.guid is present")
+	}
+
+	if err := r.Spec.validateAuthSecret(false, "", nil); err != nil {
+		return nil, err
+	}
+
+	if err := r.Spec.validateConfigOverrides(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Spec.validateDeletionPolicy(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Spec.validateCredentialRotationPolicy(); err != nil {
+		return nil, err
+	}
+
+	return updateWarnings(spaceWarnings(r), spaceWarnings(s)), nil
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
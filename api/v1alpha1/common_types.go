@@ -5,13 +5,46 @@ SPDX-License-Identifier: Apache-2.0
 
 package v1alpha1
 
-// ParametersFromSource represents the source of a set of Parameters
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ParametersFromSource represents the source of a set of Parameters;
+// exactly one of SecretKeyRef, ConfigMapKeyRef or FieldRef must be specified.
 type ParametersFromSource struct {
 	// The Secret key to select from.
 	// +optional
 	SecretKeyRef *SecretKeyReference `json:"secretKeyRef,omitempty"`
+	// The ConfigMap key to select from.
+	// Do not reference sensitive data here; use SecretKeyRef for such data.
+	// +optional
+	ConfigMapKeyRef *ConfigMapKeyReference `json:"configMapKeyRef,omitempty"`
+	// A field of the object itself, whose value is passed as a (top level) parameter.
+	// +optional
+	FieldRef *ParameterFieldReference `json:"fieldRef,omitempty"`
+	// Priority of this source when merging parameters (see ParametersMergeStrategy): parameters are merged in ascending order
+	// of priority, where the inline parameters have priority 0, and precede sources of the same priority; sources of the same priority
+	// are merged in the order listed. Has no effect with merge strategy Error.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
 }
 
+// ParametersMergeStrategy defines how the inline parameters and the parameter sources of an object are merged.
+// +kubebuilder:validation:Enum=Error;Shallow;Deep;JSONPatch
+type ParametersMergeStrategy string
+
+const (
+	// Top level keys must occur only once across all parameters (default).
+	ParametersMergeStrategyError ParametersMergeStrategy = "Error"
+	// Top level keys of later parameters replace the ones of earlier parameters.
+	ParametersMergeStrategyShallow ParametersMergeStrategy = "Shallow"
+	// Objects are merged recursively; other values (including arrays) of later parameters replace the ones of earlier parameters.
+	ParametersMergeStrategyDeep ParametersMergeStrategy = "Deep"
+	// Secrets and config maps may contain JSON patches (RFC 6902), which are applied to the parameters merged so far;
+	// objects (including field references) are merged as with strategy Shallow.
+	ParametersMergeStrategyJSONPatch ParametersMergeStrategy = "JSONPatch"
+)
+
 // SecretKeyReference references a key of a Secret.
 type SecretKeyReference struct {
 	// The name of the secret in the current namespace to select from.
@@ -20,6 +53,82 @@ type SecretKeyReference struct {
 	Key string `json:"key"`
 }
 
+// SecretReference references a Secret, possibly in another namespace.
+type SecretReference struct {
+	// The name of the secret.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// The namespace of the secret; defaults to the namespace of the referencing object.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ConfigMapKeyReference references a key of a ConfigMap.
+type ConfigMapKeyReference struct {
+	// The name of the config map in the current namespace to select from.
+	Name string `json:"name"`
+	// The key of the config map to select from.
+	Key string `json:"key"`
+}
+
+// ParameterFieldReference selects a field of the object, whose value is passed as a parameter.
+type ParameterFieldReference struct {
+	// Path of the selected field; supported are metadata.name, metadata.namespace
+	// and metadata.labels['<KEY>'].
+	FieldPath string `json:"fieldPath"`
+	// Top level parameter key receiving the value of the selected field.
+	Parameter string `json:"parameter"`
+}
+
+// CFMetadata specifies labels and annotations of a Cloud Foundry resource; keys follow the Cloud Foundry metadata rules
+// (which match the Kubernetes rules), and the prefix service-operator.cf.cs.sap.com is reserved for the operator.
+type CFMetadata struct {
+	// Labels of the Cloud Foundry resource.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations of the Cloud Foundry resource.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// CFError is an error returned by the Cloud Foundry API.
+type CFError struct {
+	// Error code, such as 60005
+	Code int `json:"code"`
+	// Error title, such as CF-ServiceInstanceQuotaExceeded
+	Title string `json:"title"`
+	// Error detail
+	// +optional
+	Detail string `json:"detail,omitempty"`
+}
+
+// MaintenanceInfo identifies a maintenance version of a service plan, as published by the service broker.
+type MaintenanceInfo struct {
+	// Maintenance version (semantic version)
+	Version string `json:"version"`
+	// Description of the changes in this version, as provided by the service broker
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// LastOperation describes the last operation performed by Cloud Foundry on a service instance or binding,
+// as reported by the Cloud Foundry API.
+type LastOperation struct {
+	// Operation type, such as create, update or delete
+	Type string `json:"type"`
+	// Operation state, such as in progress, succeeded or failed
+	State string `json:"state"`
+	// Description of the operation, as provided by the service broker
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Time when the operation was started
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	// Time when the operation was last updated by Cloud Foundry
+	// +optional
+	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+}
+
 // ConditionStatus represents a condition's status.
 // +kubebuilder:validation:Enum=True;False;Unknown
 type ConditionStatus string
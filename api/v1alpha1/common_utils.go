@@ -0,0 +1,207 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// prefix of the Cloud Foundry labels and annotations maintained by the operator
+const cfMetadataReservedPrefix = "service-operator.cf.cs.sap.com/"
+
+// maximum length of Cloud Foundry annotation values
+const cfAnnotationValueMaxLength = 5000
+
+// (positive) polling intervals below this value are accepted, but cause a warning (since they put load on Cloud Foundry)
+const minRecommendedPollingInterval = 10 * time.Second
+
+// supported field paths of parameter field references; the second submatch is the label key (if any)
+var parameterFieldPathPattern = regexp.MustCompile(`^metadata\.(name|namespace|labels\['([^']+)'\])$`)
+
+// Resolve returns the value of the referenced field of the given object.
+func (r *ParameterFieldReference) Resolve(obj metav1.Object) (string, error) {
+	match := parameterFieldPathPattern.FindStringSubmatch(r.FieldPath)
+	switch {
+	case match == nil:
+		return "", fmt.Errorf("unsupported field path: %s", r.FieldPath)
+	case match[1] == "name":
+		return obj.GetName(), nil
+	case match[1] == "namespace":
+		return obj.GetNamespace(), nil
+	default:
+		value, ok := obj.GetLabels()[match[2]]
+		if !ok {
+			return "", fmt.Errorf("label %s not found", match[2])
+		}
+		return value, nil
+	}
+}
+
+// validateParametersFrom checks that every parameter source specifies exactly one of its alternatives, and that
+// field references are supported.
+func validateParametersFrom(parametersFrom []ParametersFromSource, path string) error {
+	for i, pf := range parametersFrom {
+		count := 0
+		if pf.SecretKeyRef != nil {
+			count++
+		}
+		if pf.ConfigMapKeyRef != nil {
+			count++
+		}
+		if pf.FieldRef != nil {
+			count++
+		}
+		if count != 1 {
+			return fmt.Errorf("exactly one of %s[%d].secretKeyRef, %s[%d].configMapKeyRef or %s[%d].fieldRef must be specified", path, i, path, i, path, i)
+		}
+		if pf.FieldRef != nil {
+			if !parameterFieldPathPattern.MatchString(pf.FieldRef.FieldPath) {
+				return fmt.Errorf("%s[%d].fieldRef.fieldPath must be one of metadata.name, metadata.namespace or metadata.labels['<KEY>']", path, i)
+			}
+			if pf.FieldRef.Parameter == "" {
+				return fmt.Errorf("%s[%d].fieldRef.parameter must not be empty", path, i)
+			}
+		}
+	}
+	return nil
+}
+
+// validateCFMetadata checks that the given Cloud Foundry labels and annotations are valid, and do not use the reserved prefix.
+func validateCFMetadata(metadata *CFMetadata, path string) error {
+	if metadata == nil {
+		return nil
+	}
+	for key, value := range metadata.Labels {
+		if err := validateCFMetadataKey(key); err != nil {
+			return fmt.Errorf("invalid key %s in %s.labels: %s", key, path, err)
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("invalid value of %s.labels[%s]: %s", path, key, strings.Join(errs, "; "))
+		}
+	}
+	for key, value := range metadata.Annotations {
+		if err := validateCFMetadataKey(key); err != nil {
+			return fmt.Errorf("invalid key %s in %s.annotations: %s", key, path, err)
+		}
+		if len(value) > cfAnnotationValueMaxLength {
+			return fmt.Errorf("invalid value of %s.annotations[%s]: must be no more than %d characters", path, key, cfAnnotationValueMaxLength)
+		}
+	}
+	return nil
+}
+
+func validateCFMetadataKey(key string) error {
+	if strings.HasPrefix(key, cfMetadataReservedPrefix) {
+		return fmt.Errorf("prefix %s is reserved", strings.TrimSuffix(cfMetadataReservedPrefix, "/"))
+	}
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// immutableFieldError returns the error reported if the given immutable field (such as spec.spaceName) was changed.
+func immutableFieldError(path string, oldValue string, newValue string) error {
+	return fmt.Errorf("%s is immutable; it cannot be changed from %q to %q after creation", path, oldValue, newValue)
+}
+
+// validateAdoptLabelSelector checks that the label selector given by annotation AnnotationAdoptCFLabelSelector (if any) is valid,
+// and does not select by the labels maintained by the operator.
+func validateAdoptLabelSelector(annotations map[string]string) error {
+	value, ok := annotations[AnnotationAdoptCFLabelSelector]
+	if !ok {
+		return nil
+	}
+	selector, err := labels.Parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid value of annotation %s: %s", AnnotationAdoptCFLabelSelector, err)
+	}
+	requirements, _ := selector.Requirements()
+	if len(requirements) == 0 {
+		return fmt.Errorf("invalid value of annotation %s: selector must not be empty", AnnotationAdoptCFLabelSelector)
+	}
+	for _, requirement := range requirements {
+		if strings.HasPrefix(requirement.Key(), cfMetadataReservedPrefix) {
+			return fmt.Errorf("invalid value of annotation %s: prefix %s is reserved", AnnotationAdoptCFLabelSelector, strings.TrimSuffix(cfMetadataReservedPrefix, "/"))
+		}
+	}
+	return nil
+}
+
+// parametersWarnings returns warnings about parameter source priorities which have no effect with the given merge strategy.
+func parametersWarnings(strategy ParametersMergeStrategy, parametersFrom []ParametersFromSource, path string) admission.Warnings {
+	if strategy != "" && strategy != ParametersMergeStrategyError {
+		return nil
+	}
+	for _, pf := range parametersFrom {
+		if pf.Priority != 0 {
+			return admission.Warnings{fmt.Sprintf("priorities of %s have no effect unless spec.parametersMergeStrategy is set to a strategy other than %s", path, ParametersMergeStrategyError)}
+		}
+	}
+	return nil
+}
+
+// annotationWarnings returns warnings about risky (or ineffective) values of the annotations common to all kinds,
+// such as very small polling intervals.
+func annotationWarnings(annotations map[string]string) admission.Warnings {
+	var warnings admission.Warnings
+	for _, key := range []string{AnnotationPollingIntervalReady, AnnotationPollingIntervalFail} {
+		value, ok := annotations[key]
+		if !ok {
+			continue
+		}
+		interval, err := time.ParseDuration(value)
+		switch {
+		case err != nil:
+			warnings = append(warnings, fmt.Sprintf("annotation %s has an invalid value (%s); the default polling interval is used instead", key, value))
+		case interval > 0 && interval < minRecommendedPollingInterval:
+			warnings = append(warnings, fmt.Sprintf("annotation %s specifies a very small polling interval (%s); intervals below %s put unnecessary load on Cloud Foundry", key, value, minRecommendedPollingInterval))
+		}
+	}
+	if value, ok := annotations[AnnotationReconcileCallTimeout]; ok {
+		if duration, err := time.ParseDuration(value); err != nil || duration < 0 {
+			warnings = append(warnings, fmt.Sprintf("annotation %s has an invalid value (%s); the configured reconcile timeout is used instead", AnnotationReconcileCallTimeout, value))
+		}
+	}
+	return warnings
+}
+
+// updateWarnings returns those of the given warnings which did not apply to the previous version of the object already;
+// so settings which were admitted with a warning before are not reported again on every update.
+func updateWarnings(warnings admission.Warnings, previousWarnings admission.Warnings) admission.Warnings {
+	var result admission.Warnings
+	for _, warning := range warnings {
+		if !slices.Contains(previousWarnings, warning) {
+			result = append(result, warning)
+		}
+	}
+	return result
+}
+
+// adoptionWarnings returns warnings about an adopt annotation (AnnotationAdoptCFResources) of the given object which will not have the
+// intended effect; orphaned Cloud Foundry resources are adopted by metadata.name, so a differing Cloud Foundry name (spec.name) is not matched.
+func adoptionWarnings(obj metav1.Object, cfName string) admission.Warnings {
+	value, ok := obj.GetAnnotations()[AnnotationAdoptCFResources]
+	if !ok {
+		return nil
+	}
+	var warnings admission.Warnings
+	if value != "adopt" {
+		warnings = append(warnings, fmt.Sprintf("annotation %s has no effect unless set to 'adopt'", AnnotationAdoptCFResources))
+	} else if cfName != "" && cfName != obj.GetName() {
+		warnings = append(warnings, fmt.Sprintf("annotation %s adopts Cloud Foundry resources named %s (metadata.name), not %s (spec.name)", AnnotationAdoptCFResources, obj.GetName(), cfName))
+	}
+	return warnings
+}
@@ -0,0 +1,168 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package v1alpha1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServiceInstanceConditions(t *testing.T) {
+	g := NewWithT(t)
+
+	serviceInstance := &ServiceInstance{ObjectMeta: metav1.ObjectMeta{Generation: 1}}
+	g.Expect(serviceInstance.GetCondition(ServiceInstanceConditionSynced)).To(BeNil())
+
+	serviceInstance.SetCondition(ServiceInstanceConditionSynced, ConditionUnknown, "Pending", "pending")
+	serviceInstance.SetCondition(ServiceInstanceConditionCFReachable, ConditionTrue, "Reachable", "reachable")
+	g.Expect(serviceInstance.Status.Conditions).To(HaveLen(2))
+	condition := serviceInstance.GetCondition(ServiceInstanceConditionSynced)
+	g.Expect(condition).NotTo(BeNil())
+	g.Expect(condition.Status).To(Equal(ConditionUnknown))
+	g.Expect(condition.ObservedGeneration).To(Equal(int64(1)))
+	g.Expect(condition.LastTransitionTime).NotTo(BeNil())
+	transitionTime := condition.LastTransitionTime
+
+	// the transition time is kept as long as the status does not change; reason, message and observed generation are updated
+	serviceInstance.Generation = 2
+	serviceInstance.SetCondition(ServiceInstanceConditionSynced, ConditionUnknown, "StillPending", "still pending")
+	g.Expect(serviceInstance.Status.Conditions).To(HaveLen(2))
+	condition = serviceInstance.GetCondition(ServiceInstanceConditionSynced)
+	g.Expect(condition.LastTransitionTime).To(BeIdenticalTo(transitionTime))
+	g.Expect(condition.Reason).To(Equal("StillPending"))
+	g.Expect(condition.Message).To(Equal("still pending"))
+	g.Expect(condition.ObservedGeneration).To(Equal(int64(2)))
+
+	serviceInstance.SetCondition(ServiceInstanceConditionSynced, ConditionTrue, "Synced", "synced")
+	condition = serviceInstance.GetCondition(ServiceInstanceConditionSynced)
+	g.Expect(condition.Status).To(Equal(ConditionTrue))
+	g.Expect(condition.LastTransitionTime).NotTo(BeIdenticalTo(transitionTime))
+
+	serviceInstance.RemoveCondition(ServiceInstanceConditionSynced)
+	g.Expect(serviceInstance.GetCondition(ServiceInstanceConditionSynced)).To(BeNil())
+	g.Expect(serviceInstance.GetCondition(ServiceInstanceConditionCFReachable)).NotTo(BeNil())
+	serviceInstance.RemoveCondition(ServiceInstanceConditionSynced)
+	g.Expect(serviceInstance.Status.Conditions).To(HaveLen(1))
+}
+
+func TestServiceInstanceReadyCondition(t *testing.T) {
+	g := NewWithT(t)
+
+	serviceInstance := &ServiceInstance{ObjectMeta: metav1.ObjectMeta{Generation: 3}}
+	serviceInstance.SetReadyCondition(ConditionUnknown, "Processing", "processing")
+	g.Expect(serviceInstance.IsReady()).To(BeFalse())
+	g.Expect(serviceInstance.Status.State).To(Equal(ServiceInstanceStateProcessing))
+	g.Expect(serviceInstance.Status.ObservedGeneration).To(BeZero())
+
+	serviceInstance.SetReadyCondition(ConditionFalse, "Error", "error")
+	g.Expect(serviceInstance.IsReady()).To(BeFalse())
+	g.Expect(serviceInstance.Status.State).To(Equal(ServiceInstanceStateError))
+	g.Expect(serviceInstance.Status.ObservedGeneration).To(BeZero())
+
+	serviceInstance.SetReadyCondition(ConditionTrue, "Succeeded", "success")
+	g.Expect(serviceInstance.IsReady()).To(BeTrue())
+	g.Expect(serviceInstance.Status.State).To(Equal(ServiceInstanceStateReady))
+	g.Expect(serviceInstance.Status.ObservedGeneration).To(Equal(int64(3)))
+	g.Expect(serviceInstance.GetReadyCondition().Reason).To(Equal("Succeeded"))
+
+	now := metav1.Now()
+	serviceInstance.DeletionTimestamp = &now
+	serviceInstance.SetReadyCondition(ConditionUnknown, "Deleting", "deleting")
+	g.Expect(serviceInstance.Status.State).To(Equal(ServiceInstanceStateDeleting))
+}
+
+func TestServiceBindingConditions(t *testing.T) {
+	g := NewWithT(t)
+
+	serviceBinding := &ServiceBinding{ObjectMeta: metav1.ObjectMeta{Generation: 1}}
+	serviceBinding.SetCondition(ServiceBindingConditionCredentialsReady, ConditionFalse, "Error", "error")
+	serviceBinding.SetCondition(ServiceBindingConditionCredentialsReady, ConditionTrue, "SecretStored", "stored")
+	g.Expect(serviceBinding.Status.Conditions).To(HaveLen(1))
+	condition := serviceBinding.GetCondition(ServiceBindingConditionCredentialsReady)
+	g.Expect(condition.Status).To(Equal(ConditionTrue))
+	g.Expect(condition.Reason).To(Equal("SecretStored"))
+	g.Expect(condition.ObservedGeneration).To(Equal(int64(1)))
+
+	serviceBinding.SetReadyCondition(ConditionTrue, "Succeeded", "success")
+	g.Expect(serviceBinding.IsReady()).To(BeTrue())
+	g.Expect(serviceBinding.Status.State).To(Equal(ServiceBindingStateReady))
+	g.Expect(serviceBinding.Status.Conditions).To(HaveLen(2))
+
+	serviceBinding.RemoveCondition(ServiceBindingConditionCredentialsReady)
+	g.Expect(serviceBinding.GetCondition(ServiceBindingConditionCredentialsReady)).To(BeNil())
+	g.Expect(serviceBinding.GetReadyCondition()).NotTo(BeNil())
+}
+
+func TestSpaceConditions(t *testing.T) {
+	g := NewWithT(t)
+
+	for _, space := range []GenericSpace{&Space{}, &ClusterSpace{}} {
+		space.SetGeneration(4)
+		space.SetCondition(SpaceConditionDeletionBlocked, ConditionTrue, "DependentsExist", "dependents exist")
+		condition := space.GetCondition(SpaceConditionDeletionBlocked)
+		g.Expect(condition).NotTo(BeNil())
+		g.Expect(condition.Status).To(Equal(ConditionTrue))
+		g.Expect(condition.ObservedGeneration).To(Equal(int64(4)))
+
+		space.SetReadyCondition(ConditionFalse, "Error", "error")
+		g.Expect(space.IsReady()).To(BeFalse())
+		g.Expect(space.GetStatus().State).To(Equal(SpaceStateError))
+		g.Expect(space.GetStatus().Conditions).To(HaveLen(2))
+
+		space.RemoveCondition(SpaceConditionDeletionBlocked)
+		g.Expect(space.GetCondition(SpaceConditionDeletionBlocked)).To(BeNil())
+		g.Expect(space.GetStatus().Conditions).To(HaveLen(1))
+	}
+}
+
+func TestObservedGeneration(t *testing.T) {
+	g := NewWithT(t)
+
+	serviceInstance := &ServiceInstance{}
+	serviceBinding := &ServiceBinding{}
+	clusterServiceBinding := &ClusterServiceBinding{}
+	space := &Space{}
+	clusterSpace := &ClusterSpace{}
+	route := &Route{}
+	routeBinding := &RouteBinding{}
+	objects := []struct {
+		object interface {
+			SetGeneration(int64)
+			SetReadyCondition(ConditionStatus, string, string)
+		}
+		observedGeneration func() int64
+		ready              func() bool
+	}{
+		{serviceInstance, func() int64 { return serviceInstance.Status.ObservedGeneration }, func() bool { return serviceInstance.Status.Ready }},
+		{serviceBinding, func() int64 { return serviceBinding.Status.ObservedGeneration }, func() bool { return serviceBinding.Status.Ready }},
+		{clusterServiceBinding, func() int64 { return clusterServiceBinding.Status.ObservedGeneration }, func() bool { return clusterServiceBinding.Status.Ready }},
+		{space, func() int64 { return space.Status.ObservedGeneration }, func() bool { return space.Status.Ready }},
+		{clusterSpace, func() int64 { return clusterSpace.Status.ObservedGeneration }, func() bool { return clusterSpace.Status.Ready }},
+		{route, func() int64 { return route.Status.ObservedGeneration }, func() bool { return route.Status.Ready }},
+		{routeBinding, func() int64 { return routeBinding.Status.ObservedGeneration }, func() bool { return routeBinding.Status.Ready }},
+	}
+
+	for _, o := range objects {
+		o.object.SetGeneration(1)
+		o.object.SetReadyCondition(ConditionTrue, "Succeeded", "success")
+		g.Expect(o.observedGeneration()).To(Equal(int64(1)))
+		g.Expect(o.ready()).To(BeTrue())
+
+		// while a new generation is being applied, the observed generation still refers to the last successfully applied one
+		o.object.SetGeneration(2)
+		o.object.SetReadyCondition(ConditionUnknown, "Processing", "processing")
+		g.Expect(o.observedGeneration()).To(Equal(int64(1)))
+		g.Expect(o.ready()).To(BeFalse())
+		o.object.SetReadyCondition(ConditionFalse, "Error", "error")
+		g.Expect(o.observedGeneration()).To(Equal(int64(1)))
+		g.Expect(o.ready()).To(BeFalse())
+
+		o.object.SetReadyCondition(ConditionTrue, "Succeeded", "success")
+		g.Expect(o.observedGeneration()).To(Equal(int64(2)))
+		g.Expect(o.ready()).To(BeTrue())
+	}
+}
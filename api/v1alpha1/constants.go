@@ -10,13 +10,25 @@ const (
 	LabelKeyClusterSpace    = "service-operator.cf.cs.sap.com/cluster-space"
 	LabelKeyServiceInstance = "service-operator.cf.cs.sap.com/service-instance"
 	LabelKeyServiceBinding  = "service-operator.cf.cs.sap.com/service-binding"
+	LabelKeyRoute           = "service-operator.cf.cs.sap.com/route"
+
+	// label on binding secrets of cluster service bindings, identifying the cluster service binding
+	LabelKeyClusterServiceBinding = "service-operator.cf.cs.sap.com/cluster-service-binding"
+
+	// label on binding secrets, identifying the namespace of the service binding (along with LabelKeyServiceBinding)
+	LabelKeyServiceBindingNamespace = "service-operator.cf.cs.sap.com/service-binding-namespace"
 
 	// annotation on custom resources
 	AnnotationRecreate = "service-operator.cf.cs.sap.com/recreate-on-creation-failure"
-	// annotation max number of retries for a failed operation on a service instance
+	// annotation max number of retries for a failed operation on a service instance, service binding or (cluster) space
 	AnnotationMaxRetries = "service-operator.cf.cs.sap.com/max-retries"
-	// annotation to hold the reconciliation timeout value
+	// annotation to hold the reconciliation timeout value, that is, the time after which a service instance which is still not ready
+	// is considered failed (spanning all reconcile calls); not to be confused with AnnotationReconcileCallTimeout
 	AnnotationReconcileTimeout = "service-operator.cf.cs.sap.com/timeout-on-reconcile"
+	// annotation to override the maximum duration of a single reconcile call (configuration key reconcileTimeout), given as duration;
+	// "0" disables the timeout. Reconcile calls exceeding it are aborted, reported by the Timeout condition, and retried with backoff.
+	// Ex. "service-operator.cf.cs.sap.com/reconcile-call-timeout"="10m"
+	AnnotationReconcileCallTimeout = "service-operator.cf.cs.sap.com/reconcile-call-timeout"
 	// annotation to increase or decrease the requeue interval at which the operator polls the status of CR after final state ready.
 	AnnotationPollingIntervalReady = "service-operator.cf.cs.sap.com/polling-interval-ready"
 	// annotation to increase or decrease the requeue interval at which the operator polls the status of CR after final state failed.
@@ -24,4 +36,88 @@ const (
 	// annotation to adopt orphan CF resources. If set to 'adopt', the operator will adopt orphan CF resource.
 	// Ex. "service-operator.cf.cs.sap.com/adopt-cf-resources"="adopt"
 	AnnotationAdoptCFResources = "service-operator.cf.cs.sap.com/adopt-cf-resources"
+	// annotation to adopt the orphan CF service instance with the given guid (instead of looking it up by name);
+	// the instance must exist in the space of the ServiceInstance.
+	// Ex. "service-operator.cf.cs.sap.com/adopt-cf-instance-guid"="8a5d6f5e-..."
+	AnnotationAdoptCFInstanceGuid = "service-operator.cf.cs.sap.com/adopt-cf-instance-guid"
+	// annotation to adopt the orphan CF service binding with the given guid (instead of looking it up by name);
+	// the binding must belong to the CF instance of the referenced ServiceInstance.
+	// Ex. "service-operator.cf.cs.sap.com/adopt-cf-binding-guid"="1c7e2a9b-..."
+	AnnotationAdoptCFBindingGuid = "service-operator.cf.cs.sap.com/adopt-cf-binding-guid"
+	// annotation to take over a CF service instance or binding created by another tool (such as Terraform, or a legacy broker),
+	// identified by the given label selector (in Cloud Foundry syntax); only resources without owner label are matched, and the
+	// selector must match exactly one of them (instances in the space of the ServiceInstance, bindings of the CF instance of the
+	// referenced ServiceInstance). The labels used by the selector are removed from the adopted resource.
+	// Ex. "service-operator.cf.cs.sap.com/adopt-cf-label-selector"="managed-by=terraform,terraform-id=db"
+	AnnotationAdoptCFLabelSelector = "service-operator.cf.cs.sap.com/adopt-cf-label-selector"
+	// annotation to periodically re-read the credentials of a service binding (for brokers rotating credentials on the server side),
+	// given as duration; if the credentials changed, the binding secret is updated accordingly.
+	// Ex. "service-operator.cf.cs.sap.com/refresh-credentials-interval"="1h"
+	AnnotationRefreshCredentialsInterval = "service-operator.cf.cs.sap.com/refresh-credentials-interval"
+	// annotation to apply a maintenance upgrade of a service instance with upgrade policy Manual; the value must match
+	// the version offered in status.availableUpgrade.
+	// Ex. "service-operator.cf.cs.sap.com/upgrade-to-version"="1.2.0"
+	AnnotationUpgradeToVersion = "service-operator.cf.cs.sap.com/upgrade-to-version"
+	// annotation to override the time after which an operation on a Cloud Foundry instance which is still in progress
+	// is considered stalled (configuration key stalledOperationTimeout), given as duration; "0" disables the detection.
+	// Ex. "service-operator.cf.cs.sap.com/stalled-operation-timeout"="2h"
+	AnnotationStalledOperationTimeout = "service-operator.cf.cs.sap.com/stalled-operation-timeout"
+	// annotation to reconcile an object in observe-only mode: the Cloud Foundry resource is only read (and reflected in the status),
+	// but never created, updated or deleted, and no finalizers are added.
+	// Ex. "service-operator.cf.cs.sap.com/observe-only"="true"
+	AnnotationObserveOnly = "service-operator.cf.cs.sap.com/observe-only"
+	// annotation to reconcile a service instance in dry-run mode: the changes which would be applied to the Cloud Foundry instance
+	// are written to status.pendingChanges, but not applied.
+	// Ex. "service-operator.cf.cs.sap.com/dry-run"="true"
+	AnnotationDryRun = "service-operator.cf.cs.sap.com/dry-run"
+	// annotation to pause the reconciliation of an object (for example for break-glass or maintenance operations): nothing is
+	// read from or changed in Cloud Foundry, and the object is not requeued, until the annotation is removed (or set to another value);
+	// the pause is reported by the Paused condition.
+	// Ex. "service-operator.cf.cs.sap.com/paused"="true"
+	AnnotationPaused = "service-operator.cf.cs.sap.com/paused"
+	// annotation to pin the Cloud Foundry API endpoint used for an object, overriding the endpoint selected by the space
+	// (see status.endpoint of Space and ClusterSpace); one of CFEndpointPrimary (the url of the space secret)
+	// or CFEndpointFailover (the failoverUrl of the space secret).
+	// Ex. "service-operator.cf.cs.sap.com/cf-endpoint"="failover"
+	AnnotationCFEndpoint = "service-operator.cf.cs.sap.com/cf-endpoint"
+	// annotation to check a service instance for parameter drift: the parameters of the Cloud Foundry instance are retrieved
+	// from the service broker (which must support this), and compared with the specified parameters (condition ParameterDrift).
+	// Ex. "service-operator.cf.cs.sap.com/check-parameter-drift"="true"
+	AnnotationCheckParameterDrift = "service-operator.cf.cs.sap.com/check-parameter-drift"
+	// annotation naming who changed an object (for example set by a deployment pipeline); recorded with the changes
+	// in the status of the object (such as status.parameterHistory of ServiceInstance), instead of the field manager.
+	// Ex. "service-operator.cf.cs.sap.com/changed-by"="pipeline/release-42"
+	AnnotationChangedBy = "service-operator.cf.cs.sap.com/changed-by"
+	// annotation to protect a service instance against deletion: while set to DeletionProtectionEnabled, the Cloud Foundry instance
+	// is not deleted (even if the service instance is being deleted, e.g. along with its namespace), and the deletion is reported
+	// as blocked by the DeletionBlocked condition; removing the annotation lets the deletion proceed.
+	// Ex. "service-operator.cf.cs.sap.com/deletion-protection"="enabled"
+	AnnotationDeletionProtection = "service-operator.cf.cs.sap.com/deletion-protection"
+
+	// annotation on namespaces, naming the Space (of that namespace) used by new service instances of the namespace
+	// which specify neither spec.spaceName nor spec.clusterSpaceName; applied by the mutating webhook.
+	// Ex. "service-operator.cf.cs.sap.com/default-space"="dev"
+	AnnotationDefaultSpace = "service-operator.cf.cs.sap.com/default-space"
+	// annotation on namespaces, naming the ClusterSpace used by new service instances of the namespace
+	// which specify neither spec.spaceName nor spec.clusterSpaceName (mutually exclusive with AnnotationDefaultSpace).
+	// Ex. "service-operator.cf.cs.sap.com/default-cluster-space"="shared"
+	AnnotationDefaultClusterSpace = "service-operator.cf.cs.sap.com/default-cluster-space"
+	// annotation on immutable binding secrets superseded by a binding secret with new content, recording when the secret was superseded (RFC 3339);
+	// the secret is deleted once the grace period configured by supersededSecretGracePeriod has passed. Set by the operator.
+	AnnotationSupersededAt = "service-operator.cf.cs.sap.com/superseded-at"
+)
+
+// value of AnnotationDeletionProtection enabling the protection
+const DeletionProtectionEnabled = "enabled"
+
+// values of AnnotationCFEndpoint
+const (
+	CFEndpointPrimary  = "primary"
+	CFEndpointFailover = "failover"
+)
+
+// keys of the space secret holding the Cloud Foundry API endpoints
+const (
+	SpaceSecretKeyURL         = "url"
+	SpaceSecretKeyFailoverURL = "failoverUrl"
 )
@@ -18,6 +18,9 @@ type GenericSpace interface {
 	GetStatus() *SpaceStatus
 	SetReadyCondition(ConditionStatus, string, string)
 	GetReadyCondition() *SpaceCondition
+	SetCondition(SpaceConditionType, ConditionStatus, string, string)
+	GetCondition(SpaceConditionType) *SpaceCondition
+	RemoveCondition(SpaceConditionType)
 	IsReady() bool
 	Default()
 }
@@ -6,35 +6,146 @@ SPDX-License-Identifier: Apache-2.0
 package v1alpha1
 
 import (
+	"fmt"
+	"slices"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
-func setSpaceReadyCondition(space GenericSpace, conditionStatus ConditionStatus, reason, message string) {
-	status := space.GetStatus()
-	ready := getSpaceReadyCondition(space)
-	if ready == nil {
-		ready = &SpaceCondition{
-			Type: SpaceConditionReady,
+// GetManagementMode returns whether the Cloud Foundry space is managed by the operator, or externally (if spec.guid is set).
+func (spec *SpaceSpec) GetManagementMode() SpaceManagementMode {
+	if spec.Guid != "" {
+		return SpaceManagementModeExternal
+	}
+	return SpaceManagementModeManaged
+}
+
+// GetAuthSecretName returns the name of the secret containing the space credentials; namespace is the namespace of the secret
+// unless specified by spec.authSecretRef (that is, the namespace of a Space, or the cluster resource namespace for a ClusterSpace).
+func (spec *SpaceSpec) GetAuthSecretName(namespace string) types.NamespacedName {
+	if spec.AuthSecretRef == nil {
+		return types.NamespacedName{Namespace: namespace, Name: spec.AuthSecretName}
+	}
+	if spec.AuthSecretRef.Namespace != "" {
+		namespace = spec.AuthSecretRef.Namespace
+	}
+	return types.NamespacedName{Namespace: namespace, Name: spec.AuthSecretRef.Name}
+}
+
+// GetEndpoint returns the Cloud Foundry API endpoint to be used for objects in the space, given the data of the space secret
+// and the annotations of the object; that is, the endpoint pinned by the annotations (if set), or the endpoint selected by the space
+// (status.endpoint; status may be nil), or else the url of the space secret. The failoverUrl of the space secret is only used if it is still present.
+func (status *SpaceStatus) GetEndpoint(secretData map[string][]byte, annotations map[string]string) string {
+	url := string(secretData[SpaceSecretKeyURL])
+	failoverURL := string(secretData[SpaceSecretKeyFailoverURL])
+	if failoverURL == "" {
+		return url
+	}
+	switch annotations[AnnotationCFEndpoint] {
+	case CFEndpointPrimary:
+		return url
+	case CFEndpointFailover:
+		return failoverURL
+	}
+	if status != nil && status.Endpoint == failoverURL {
+		return failoverURL
+	}
+	return url
+}
+
+// validateAuthSecret checks that exactly one of spec.authSecretName and spec.authSecretRef is specified; the secret may only reside
+// in another namespace than the given namespace of the object if the object is namespaced, and the namespace is one of credentialNamespaces.
+func (spec *SpaceSpec) validateAuthSecret(namespaced bool, namespace string, credentialNamespaces []string) error {
+	if (spec.AuthSecretName == "") == (spec.AuthSecretRef == nil) {
+		return fmt.Errorf("exactly one of spec.authSecretName or spec.authSecretRef must be specified")
+	}
+	if spec.AuthSecretRef == nil || spec.AuthSecretRef.Namespace == "" || spec.AuthSecretRef.Namespace == namespace {
+		return nil
+	}
+	if !namespaced {
+		return fmt.Errorf("spec.authSecretRef.namespace must not be specified for cluster-scoped objects")
+	}
+	if !slices.Contains(credentialNamespaces, spec.AuthSecretRef.Namespace) {
+		return fmt.Errorf("invalid spec.authSecretRef.namespace %s: namespace not allowed by the operator configuration (credentialNamespaces)", spec.AuthSecretRef.Namespace)
+	}
+	return nil
+}
+
+// validateDeletionPolicy checks that spec.deletionPolicy Cascade is only specified for spaces managed by the operator.
+func (spec *SpaceSpec) validateDeletionPolicy() error {
+	if spec.Guid != "" && spec.DeletionPolicy == SpaceDeletionPolicyCascade {
+		return fmt.Errorf("spec.deletionPolicy must not be %s if spec.guid is present", SpaceDeletionPolicyCascade)
+	}
+	return nil
+}
+
+// validateConfigOverrides checks that the durations given in spec.configOverrides are positive.
+func (spec *SpaceSpec) validateConfigOverrides() error {
+	overrides := spec.ConfigOverrides
+	if overrides == nil {
+		return nil
+	}
+	for _, d := range []struct {
+		name     string
+		duration *metav1.Duration
+	}{
+		{"resourceCacheTimeout", overrides.ResourceCacheTimeout},
+		{"pollingIntervalReady", overrides.PollingIntervalReady},
+		{"pollingIntervalFail", overrides.PollingIntervalFail},
+	} {
+		if d.duration != nil && d.duration.Duration <= 0 {
+			return fmt.Errorf("spec.configOverrides.%s must be positive", d.name)
 		}
-		status.Conditions = append(status.Conditions, *ready)
 	}
-	if ready.Status != conditionStatus {
-		ready.Status = conditionStatus
-		now := metav1.Now()
-		ready.LastTransitionTime = &now
+	return nil
+}
+
+// validateCredentialRotationPolicy checks that spec.credentialRotationPolicy is only specified for spaces managed by the operator.
+func (spec *SpaceSpec) validateCredentialRotationPolicy() error {
+	if spec.Guid != "" && spec.CredentialRotationPolicy != "" {
+		return fmt.Errorf("spec.credentialRotationPolicy must not be specified if spec.guid is present")
 	}
-	ready.Reason = reason
-	ready.Message = message
+	return nil
+}
 
-	for i, c := range status.Conditions {
-		if c.Type == SpaceConditionReady {
-			status.Conditions[i] = *ready
-			break
+// spaceWarnings returns warnings about risky (but valid) settings of the given space.
+func spaceWarnings(space GenericSpace) admission.Warnings {
+	return append(space.GetSpec().configOverridesWarnings(), annotationWarnings(space.GetAnnotations())...)
+}
+
+// configOverridesWarnings returns warnings about very small polling intervals given in spec.configOverrides.
+func (spec *SpaceSpec) configOverridesWarnings() admission.Warnings {
+	overrides := spec.ConfigOverrides
+	if overrides == nil {
+		return nil
+	}
+	var warnings admission.Warnings
+	for _, d := range []struct {
+		name     string
+		duration *metav1.Duration
+	}{
+		{"pollingIntervalReady", overrides.PollingIntervalReady},
+		{"pollingIntervalFail", overrides.PollingIntervalFail},
+	} {
+		if d.duration != nil && d.duration.Duration < minRecommendedPollingInterval {
+			warnings = append(warnings, fmt.Sprintf("spec.configOverrides.%s specifies a very small polling interval (%s); intervals below %s put unnecessary load on Cloud Foundry", d.name, d.duration.Duration, minRecommendedPollingInterval))
 		}
 	}
+	return warnings
+}
 
+func setSpaceReadyCondition(space GenericSpace, conditionStatus ConditionStatus, reason, message string) {
+	setSpaceCondition(space, SpaceConditionReady, conditionStatus, reason, message)
+
+	status := space.GetStatus()
+	// the observed generation is only bumped after a successful sync, such that health checks (e.g. of GitOps tools)
+	// do not consider the object ready while changes are still being applied
+	status.Ready = conditionStatus == ConditionTrue
 	switch conditionStatus {
 	case ConditionTrue:
+		status.ObservedGeneration = space.GetGeneration()
 		status.State = SpaceStateReady
 	case ConditionFalse:
 		status.State = SpaceStateError
@@ -48,15 +159,56 @@ func setSpaceReadyCondition(space GenericSpace, conditionStatus ConditionStatus,
 }
 
 func getSpaceReadyCondition(space GenericSpace) *SpaceCondition {
+	return getSpaceCondition(space, SpaceConditionReady)
+}
+
+// setSpaceCondition adds or updates the condition of the given type; the transition time is only updated if the status changes.
+func setSpaceCondition(space GenericSpace, conditionType SpaceConditionType, conditionStatus ConditionStatus, reason, message string) {
+	status := space.GetStatus()
+	condition := getSpaceCondition(space, conditionType)
+	if condition == nil {
+		condition = &SpaceCondition{
+			Type: conditionType,
+		}
+		status.Conditions = append(status.Conditions, *condition)
+	}
+	if condition.Status != conditionStatus {
+		condition.Status = conditionStatus
+		now := metav1.Now()
+		condition.LastTransitionTime = &now
+	}
+	condition.Reason = reason
+	condition.Message = message
+	condition.ObservedGeneration = space.GetGeneration()
+
+	for i, c := range status.Conditions {
+		if c.Type == conditionType {
+			status.Conditions[i] = *condition
+			break
+		}
+	}
+}
+
+func getSpaceCondition(space GenericSpace, conditionType SpaceConditionType) *SpaceCondition {
 	status := space.GetStatus()
 	for _, c := range status.Conditions {
-		if c.Type == SpaceConditionReady {
+		if c.Type == conditionType {
 			return &c
 		}
 	}
 	return nil
 }
 
+func removeSpaceCondition(space GenericSpace, conditionType SpaceConditionType) {
+	status := space.GetStatus()
+	for i, c := range status.Conditions {
+		if c.Type == conditionType {
+			status.Conditions = append(status.Conditions[:i], status.Conditions[i+1:]...)
+			return
+		}
+	}
+}
+
 func isSpaceReady(space GenericSpace) bool {
 	if space.GetStatus().ObservedGeneration != space.GetGeneration() {
 		return false
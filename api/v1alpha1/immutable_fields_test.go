@@ -0,0 +1,128 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package v1alpha1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Note: ValidateUpdate is called directly (on defaulted objects, as the API server would do);
+// so these tests do not need the envtest based webhook suite, and run standalone.
+
+func TestServiceInstanceImmutableFields(t *testing.T) {
+	g := NewWithT(t)
+
+	serviceInstance := &ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "instance"},
+		Spec:       ServiceInstanceSpec{SpaceName: "space", ServiceOfferingName: "offering", ServicePlanName: "plan"},
+	}
+	serviceInstance.Default()
+	validateUpdate := func(changed *ServiceInstance) error {
+		changed.Default()
+		_, err := changed.ValidateUpdate(serviceInstance.DeepCopy())
+		return err
+	}
+
+	changed := serviceInstance.DeepCopy()
+	changed.Spec.SpaceName = "other-space"
+	g.Expect(validateUpdate(changed)).To(MatchError(`spec.spaceName is immutable; it cannot be changed from "space" to "other-space" after creation`))
+
+	changed = serviceInstance.DeepCopy()
+	changed.Spec.SpaceName = ""
+	changed.Spec.ClusterSpaceName = "cluster-space"
+	g.Expect(validateUpdate(changed)).To(MatchError(ContainSubstring("spec.clusterSpaceName is immutable")))
+
+	changed = serviceInstance.DeepCopy()
+	changed.Spec.ServiceOfferingName = "other-offering"
+	g.Expect(validateUpdate(changed)).To(MatchError(ContainSubstring("spec.serviceOfferingName is immutable")))
+
+	// the plan may only be changed if allowed
+	changed = serviceInstance.DeepCopy()
+	changed.Spec.ServicePlanName = "other-plan"
+	g.Expect(validateUpdate(changed)).To(MatchError(`spec.servicePlanName is immutable; it cannot be changed from "plan" to "other-plan" after creation, unless spec.allowPlanChange is true`))
+	changed.Spec.AllowPlanChange = true
+	g.Expect(validateUpdate(changed)).To(Succeed())
+
+	// mutable fields can still be changed
+	changed = serviceInstance.DeepCopy()
+	changed.Spec.Tags = []string{"tag"}
+	g.Expect(validateUpdate(changed)).To(Succeed())
+}
+
+func TestServiceBindingImmutableFields(t *testing.T) {
+	g := NewWithT(t)
+
+	serviceBinding := &ServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "binding"},
+		Spec:       ServiceBindingSpec{ServiceInstanceName: "instance", SecretKey: "credentials"},
+	}
+	serviceBinding.Default()
+	validateUpdate := func(changed *ServiceBinding) error {
+		changed.Default()
+		_, err := changed.ValidateUpdate(serviceBinding.DeepCopy())
+		return err
+	}
+
+	changed := serviceBinding.DeepCopy()
+	changed.Spec.ServiceInstanceName = "other-instance"
+	g.Expect(validateUpdate(changed)).To(MatchError(`spec.serviceInstanceName is immutable; it cannot be changed from "instance" to "other-instance" after creation`))
+
+	changed = serviceBinding.DeepCopy()
+	changed.Spec.SecretKey = "other-key"
+	g.Expect(validateUpdate(changed)).To(MatchError(ContainSubstring("spec.secretKey is immutable")))
+
+	changed = serviceBinding.DeepCopy()
+	changed.Spec.SecretName = "other-secret"
+	g.Expect(validateUpdate(changed)).To(Succeed())
+}
+
+func TestRouteImmutableFields(t *testing.T) {
+	g := NewWithT(t)
+
+	route := &Route{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route"},
+		Spec:       RouteSpec{SpaceName: "space", Domain: "example.com", Host: "app"},
+	}
+	route.Default()
+
+	changed := route.DeepCopy()
+	changed.Spec.SpaceName = "other-space"
+	_, err := changed.ValidateUpdate(route.DeepCopy())
+	g.Expect(err).To(MatchError(ContainSubstring("spec.spaceName is immutable")))
+}
+
+func TestClusterServiceBindingImmutableFields(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterServiceBinding := &ClusterServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "binding"},
+		Spec: ClusterServiceBindingSpec{
+			ServiceInstanceRef: ServiceInstanceReference{Namespace: "default", Name: "instance"},
+			SecretNamespace:    "ingress",
+		},
+	}
+	clusterServiceBinding.Default()
+	g.Expect(clusterServiceBinding.Spec.SecretName).To(Equal("binding"))
+	validateUpdate := func(changed *ClusterServiceBinding) error {
+		changed.Default()
+		_, err := changed.ValidateUpdate(clusterServiceBinding.DeepCopy())
+		return err
+	}
+
+	changed := clusterServiceBinding.DeepCopy()
+	changed.Spec.ServiceInstanceRef.Namespace = "other"
+	g.Expect(validateUpdate(changed)).To(MatchError(ContainSubstring("spec.serviceInstanceRef.namespace is immutable")))
+
+	changed = clusterServiceBinding.DeepCopy()
+	changed.Spec.SecretNamespace = "logging"
+	g.Expect(validateUpdate(changed)).To(MatchError(ContainSubstring("spec.secretNamespace is immutable")))
+
+	changed = clusterServiceBinding.DeepCopy()
+	changed.Spec.SecretType = "kubernetes.io/basic-auth"
+	g.Expect(validateUpdate(changed)).To(Succeed())
+}
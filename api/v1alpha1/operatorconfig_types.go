@@ -0,0 +1,173 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OperatorConfigName is the name of the OperatorConfig object read by the operator; objects with other names are ignored.
+const OperatorConfigName = "cf-service-operator"
+
+// OperatorConfigSpec defines the desired state of OperatorConfig.
+// Unset fields keep the value of the operator configuration (file, environment or flags).
+type OperatorConfigSpec struct {
+	// Whether Cloud Foundry resources (spaces, service instances, service bindings) are cached in memory
+	// +optional
+	ResourceCacheEnabled *bool `json:"resourceCacheEnabled,omitempty"`
+
+	// Time after which cached Cloud Foundry resources expire
+	// +optional
+	ResourceCacheTimeout *metav1.Duration `json:"resourceCacheTimeout,omitempty"`
+
+	// Maximum number of concurrent reconciles per controller
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrentReconciles *int `json:"maxConcurrentReconciles,omitempty"`
+
+	// Maximum number of concurrent reconciles per controller for objects targeting the same Cloud Foundry API endpoint; zero means no limit
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxConcurrentReconcilesPerEndpoint *int `json:"maxConcurrentReconcilesPerEndpoint,omitempty"`
+
+	// Default intervals in which ready objects are polled, by kind (such as ServiceInstance)
+	// +optional
+	PollingIntervalsReady map[string]metav1.Duration `json:"pollingIntervalsReady,omitempty"`
+
+	// Default intervals in which failed objects (which exceeded their maximum number of retries) are polled, by kind
+	// +optional
+	PollingIntervalsFail map[string]metav1.Duration `json:"pollingIntervalsFail,omitempty"`
+
+	// Maximum number of retries of a Cloud Foundry API request answered with 429 Too Many Requests
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRetriesOnTooManyRequests *int `json:"maxRetriesOnTooManyRequests,omitempty"`
+
+	// Maximum number of requests per second sent to a Cloud Foundry API endpoint (such as 10 or 500m); zero means no limit
+	// +optional
+	MaxRequestsPerSecond *resource.Quantity `json:"maxRequestsPerSecond,omitempty"`
+
+	// Number of requests which may exceed maxRequestsPerSecond in a short burst
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Burst *int `json:"burst,omitempty"`
+
+	// Rate limits overriding maxRequestsPerSecond and burst for specific Cloud Foundry API endpoints, by API URL;
+	// if set, replaces the endpoint rate limits of the operator configuration
+	// +optional
+	EndpointRateLimits map[string]EndpointRateLimit `json:"endpointRateLimits,omitempty"`
+}
+
+// EndpointRateLimit limits the requests sent to a Cloud Foundry API endpoint.
+type EndpointRateLimit struct {
+	// Maximum number of requests per second (such as 10 or 500m); zero means no limit
+	// +optional
+	MaxRequestsPerSecond *resource.Quantity `json:"maxRequestsPerSecond,omitempty"`
+
+	// Number of requests which may exceed maxRequestsPerSecond in a short burst
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Burst int `json:"burst,omitempty"`
+}
+
+// OperatorConfigStatus defines the observed state of OperatorConfig
+type OperatorConfigStatus struct {
+	// Observed generation
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// List of status conditions to indicate the status of the OperatorConfig.
+	// Known condition types are `Applied`.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []OperatorConfigCondition `json:"conditions,omitempty"`
+
+	// Timestamp of the last change of the applied configuration
+	// +optional
+	LastAppliedAt *metav1.Time `json:"lastAppliedAt,omitempty"`
+}
+
+// OperatorConfigCondition contains condition information for an OperatorConfig.
+type OperatorConfigCondition struct {
+	// Type of the condition, known values are ('Applied').
+	Type OperatorConfigConditionType `json:"type"`
+
+	// Status of the condition, one of ('True', 'False', 'Unknown').
+	Status ConditionStatus `json:"status"`
+
+	// LastTransitionTime is the timestamp corresponding to the last status
+	// change of this condition.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Reason is a brief machine readable explanation for the condition's last
+	// transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human readable description of the details of the last
+	// transition, complementing reason.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the metadata.generation of the object the condition was set for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// OperatorConfigConditionType represents an OperatorConfig condition value.
+type OperatorConfigConditionType string
+
+const (
+	// OperatorConfigConditionApplied represents the fact that the spec of the OperatorConfig was applied (True), or rejected (False).
+	OperatorConfigConditionApplied OperatorConfigConditionType = "Applied"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Applied",type=string,JSONPath=`.status.conditions[?(@.type=="Applied")].status`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +genclient
+// +genclient:nonNamespaced
+
+// OperatorConfig is the Schema for the operatorconfigs API.
+// The operator reads the object named cf-service-operator, and applies its settings at runtime,
+// taking precedence over the operator configuration (file, environment or flags).
+type OperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperatorConfigSpec   `json:"spec,omitempty"`
+	Status OperatorConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperatorConfigList contains a list of OperatorConfig
+type OperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperatorConfig `json:"items"`
+}
+
+func (operatorConfig *OperatorConfig) SetCondition(conditionType OperatorConfigConditionType, conditionStatus ConditionStatus, reason, message string) {
+	setOperatorConfigCondition(operatorConfig, conditionType, conditionStatus, reason, message)
+}
+
+func (operatorConfig *OperatorConfig) GetCondition(conditionType OperatorConfigConditionType) *OperatorConfigCondition {
+	return getOperatorConfigCondition(operatorConfig, conditionType)
+}
+
+func (operatorConfig *OperatorConfig) RemoveCondition(conditionType OperatorConfigConditionType) {
+	removeOperatorConfigCondition(operatorConfig, conditionType)
+}
+
+func init() {
+	SchemeBuilder.Register(&OperatorConfig{}, &OperatorConfigList{})
+}
@@ -0,0 +1,57 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// setOperatorConfigCondition adds or updates the condition of the given type; the transition time is only updated if the status changes.
+func setOperatorConfigCondition(operatorConfig *OperatorConfig, conditionType OperatorConfigConditionType, conditionStatus ConditionStatus, reason, message string) {
+	status := &operatorConfig.Status
+	condition := getOperatorConfigCondition(operatorConfig, conditionType)
+	if condition == nil {
+		condition = &OperatorConfigCondition{
+			Type: conditionType,
+		}
+		status.Conditions = append(status.Conditions, *condition)
+	}
+	if condition.Status != conditionStatus {
+		condition.Status = conditionStatus
+		now := metav1.Now()
+		condition.LastTransitionTime = &now
+	}
+	condition.Reason = reason
+	condition.Message = message
+	condition.ObservedGeneration = operatorConfig.GetGeneration()
+
+	for i, c := range status.Conditions {
+		if c.Type == conditionType {
+			status.Conditions[i] = *condition
+			break
+		}
+	}
+}
+
+func getOperatorConfigCondition(operatorConfig *OperatorConfig, conditionType OperatorConfigConditionType) *OperatorConfigCondition {
+	status := &operatorConfig.Status
+	for _, c := range status.Conditions {
+		if c.Type == conditionType {
+			return &c
+		}
+	}
+	return nil
+}
+
+func removeOperatorConfigCondition(operatorConfig *OperatorConfig, conditionType OperatorConfigConditionType) {
+	status := &operatorConfig.Status
+	for i, c := range status.Conditions {
+		if c.Type == conditionType {
+			status.Conditions = append(status.Conditions[:i], status.Conditions[i+1:]...)
+			return
+		}
+	}
+}
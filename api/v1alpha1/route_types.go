@@ -0,0 +1,213 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.status.url`
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +genclient
+
+// Route is the Schema for the routes API
+type Route struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RouteSpec `json:"spec,omitempty"`
+
+	// +kubebuilder:default={"observedGeneration":-1}
+	Status RouteStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RouteList contains a list of Route
+type RouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Route `json:"items"`
+}
+
+// RouteSpec defines the desired state of Route
+type RouteSpec struct {
+	// Name of a Space resource in the same namespace,
+	// identifying the Cloud Foundry space where the route will be created.
+	// Exactly one of SpaceName and ClusterSpaceName have to be specified.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	SpaceName string `json:"spaceName,omitempty"`
+
+	// Name of a ClusterSpace resource,
+	// identifying the Cloud Foundry space where the route will be created.
+	// Exactly one of SpaceName and ClusterSpaceName have to be specified.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	ClusterSpaceName string `json:"clusterSpaceName,omitempty"`
+
+	// Name of the Cloud Foundry domain of the route (e.g. apps.example.com).
+	// +kubebuilder:validation:MinLength=1
+	Domain string `json:"domain"`
+
+	// Host name of the route; if unspecified, the route refers to the domain itself.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host,omitempty"`
+
+	// Path of the route; must start with a slash.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^/.+`
+	Path string `json:"path,omitempty"`
+}
+
+// RouteStatus defines the observed state of Route
+type RouteStatus struct {
+	// Observed generation; this is the last generation which was successfully applied (that is, for which
+	// the Ready condition became True), so it lags behind metadata.generation while changes are in progress
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Whether the object is ready, that is, whether the Ready condition is True; note that this refers
+	// to status.observedGeneration, which must therefore be compared with metadata.generation
+	// +optional
+	Ready bool `json:"ready"`
+
+	// Last reconciliation timestamp
+	// +optional
+	LastReconciledAt *metav1.Time `json:"lastReconciledAt,omitempty"`
+
+	// Last modification timestamp (when the last create/update/delete request was sent to Cloud Foundry)
+	// +optional
+	LastModifiedAt *metav1.Time `json:"lastModifiedAt,omitempty"`
+
+	// Cloud Foundry space guid
+	// +optional
+	SpaceGuid string `json:"spaceGuid,omitempty"`
+
+	// Cloud Foundry domain guid
+	// +optional
+	DomainGuid string `json:"domainGuid,omitempty"`
+
+	// Cloud Foundry route guid
+	// +optional
+	RouteGuid string `json:"routeGuid,omitempty"`
+
+	// URL of the route, as reported by Cloud Foundry
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// List of status conditions to indicate the status of a Route.
+	// Known condition types are `Ready`, `Synced`, `DeletionBlocked`, `CFReachable`, `Paused`, `Timeout`.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []RouteCondition `json:"conditions,omitempty"`
+
+	// Readable form of the state.
+	// +optional
+	State RouteState `json:"state,omitempty"`
+}
+
+// RouteCondition contains condition information for a Route.
+type RouteCondition struct {
+	// Type of the condition, known values are ('Ready', 'Synced', 'DeletionBlocked', 'CFReachable').
+	Type RouteConditionType `json:"type"`
+
+	// Status of the condition, one of ('True', 'False', 'Unknown').
+	Status ConditionStatus `json:"status"`
+
+	// LastTransitionTime is the timestamp corresponding to the last status
+	// change of this condition.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Reason is a brief machine readable explanation for the condition's last
+	// transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human readable description of the details of the last
+	// transition, complementing reason.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the metadata.generation of the object the condition was set for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// RouteConditionType represents a Route condition value.
+type RouteConditionType string
+
+const (
+	// RouteConditionReady represents the fact that a given route is ready.
+	RouteConditionReady RouteConditionType = "Ready"
+	// RouteConditionSynced represents the fact that the Cloud Foundry route reflects the current spec;
+	// it is False if applying the spec failed.
+	RouteConditionSynced RouteConditionType = "Synced"
+	// RouteConditionDeletionBlocked represents the fact that the deletion of the route is blocked
+	// (e.g. by depending route bindings); it is only present while the route is being deleted.
+	RouteConditionDeletionBlocked RouteConditionType = "DeletionBlocked"
+	// RouteConditionCFReachable represents the fact that the Cloud Foundry API was reachable during the last reconciliation.
+	RouteConditionCFReachable RouteConditionType = "CFReachable"
+	// RouteConditionPaused represents the fact that reconciliation is paused by the annotation service-operator.cf.cs.sap.com/paused;
+	// it is only present while the annotation is set.
+	RouteConditionPaused RouteConditionType = "Paused"
+	// RouteConditionTimeout represents the fact that the last reconciliation did not complete within the maximum reconcile duration
+	// (see annotation service-operator.cf.cs.sap.com/reconcile-call-timeout); it is only present while this is the case.
+	RouteConditionTimeout RouteConditionType = "Timeout"
+)
+
+// RouteState represents a condition state in a readable form
+// +kubebuilder:validation:Enum=Processing;Deleting;Ready;Error
+type RouteState string
+
+// These are valid condition states
+const (
+	// RouteStateProcessing represents the fact that the route is reconciling
+	RouteStateProcessing RouteState = "Processing"
+
+	// RouteStateDeleting represents the fact that the route is being deleted
+	RouteStateDeleting RouteState = "Deleting"
+
+	// RouteStateReady represents the fact that the route is ready
+	RouteStateReady RouteState = "Ready"
+
+	// RouteStateError represents the fact that the route is not ready resp. has an error
+	RouteStateError RouteState = "Error"
+)
+
+func (route *Route) SetReadyCondition(conditionStatus ConditionStatus, reason, message string) {
+	setRouteReadyCondition(route, conditionStatus, reason, message)
+}
+
+func (route *Route) GetReadyCondition() *RouteCondition {
+	return getRouteReadyCondition(route)
+}
+
+func (route *Route) SetCondition(conditionType RouteConditionType, conditionStatus ConditionStatus, reason, message string) {
+	setRouteCondition(route, conditionType, conditionStatus, reason, message)
+}
+
+func (route *Route) GetCondition(conditionType RouteConditionType) *RouteCondition {
+	return getRouteCondition(route, conditionType)
+}
+
+func (route *Route) RemoveCondition(conditionType RouteConditionType) {
+	removeRouteCondition(route, conditionType)
+}
+
+func (route *Route) IsReady() bool {
+	return isRouteReady(route)
+}
+
+func init() {
+	SchemeBuilder.Register(&Route{}, &RouteList{})
+}
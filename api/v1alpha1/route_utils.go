@@ -0,0 +1,93 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func setRouteReadyCondition(route *Route, conditionStatus ConditionStatus, reason, message string) {
+	setRouteCondition(route, RouteConditionReady, conditionStatus, reason, message)
+
+	status := &route.Status
+	// the observed generation is only bumped after a successful sync, such that health checks (e.g. of GitOps tools)
+	// do not consider the object ready while changes are still being applied
+	status.Ready = conditionStatus == ConditionTrue
+	switch conditionStatus {
+	case ConditionTrue:
+		status.ObservedGeneration = route.Generation
+		status.State = RouteStateReady
+	case ConditionFalse:
+		status.State = RouteStateError
+	default:
+		if route.DeletionTimestamp.IsZero() {
+			status.State = RouteStateProcessing
+		} else {
+			status.State = RouteStateDeleting
+		}
+	}
+}
+
+func getRouteReadyCondition(route *Route) *RouteCondition {
+	return getRouteCondition(route, RouteConditionReady)
+}
+
+// setRouteCondition adds or updates the condition of the given type; the transition time is only updated if the status changes.
+func setRouteCondition(route *Route, conditionType RouteConditionType, conditionStatus ConditionStatus, reason, message string) {
+	status := &route.Status
+	condition := getRouteCondition(route, conditionType)
+	if condition == nil {
+		condition = &RouteCondition{
+			Type: conditionType,
+		}
+		status.Conditions = append(status.Conditions, *condition)
+	}
+	if condition.Status != conditionStatus {
+		condition.Status = conditionStatus
+		now := metav1.Now()
+		condition.LastTransitionTime = &now
+	}
+	condition.Reason = reason
+	condition.Message = message
+	condition.ObservedGeneration = route.GetGeneration()
+
+	for i, c := range status.Conditions {
+		if c.Type == conditionType {
+			status.Conditions[i] = *condition
+			break
+		}
+	}
+}
+
+func getRouteCondition(route *Route, conditionType RouteConditionType) *RouteCondition {
+	status := &route.Status
+	for _, c := range status.Conditions {
+		if c.Type == conditionType {
+			return &c
+		}
+	}
+	return nil
+}
+
+func removeRouteCondition(route *Route, conditionType RouteConditionType) {
+	status := &route.Status
+	for i, c := range status.Conditions {
+		if c.Type == conditionType {
+			status.Conditions = append(status.Conditions[:i], status.Conditions[i+1:]...)
+			return
+		}
+	}
+}
+
+func isRouteReady(route *Route) bool {
+	if route.Status.ObservedGeneration != route.Generation {
+		return false
+	}
+	if c := getRouteReadyCondition(route); c != nil {
+		return c.Status == ConditionTrue
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var routelog = logf.Log.WithName("route-resource")
+
+func (r *Route) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-cf-cs-sap-com-v1alpha1-route,mutating=true,failurePolicy=fail,sideEffects=None,groups=cf.cs.sap.com,resources=routes,verbs=create;update,versions=v1alpha1,name=mroute.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &Route{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *Route) Default() {
+	routelog.V(2).Info("Default", "name", r.Name)
+
+	if r.Labels == nil {
+		r.Labels = make(map[string]string)
+	}
+	if r.Spec.ClusterSpaceName != "" {
+		r.Labels[LabelKeyClusterSpace] = r.Spec.ClusterSpaceName
+	}
+	if r.Spec.SpaceName != "" {
+		r.Labels[LabelKeySpace] = r.Spec.SpaceName
+	}
+}
+
+// +kubebuilder:webhook:path=/validate-cf-cs-sap-com-v1alpha1-route,mutating=false,failurePolicy=fail,sideEffects=None,groups=cf.cs.sap.com,resources=routes,verbs=create;update,versions=v1alpha1,name=vroute.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Route{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Route) ValidateCreate() (admission.Warnings, error) {
+	routelog.V(2).Info("Validate create", "name", r.Name)
+
+	if !(r.Spec.SpaceName != "" && r.Spec.ClusterSpaceName == "" ||
+		r.Spec.SpaceName == "" && r.Spec.ClusterSpaceName != "") {
+		return nil, fmt.Errorf("exactly one of spec.spaceName or spec.clusterSpaceName must be specified")
+	}
+
+	return annotationWarnings(r.Annotations), nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Route) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	routelog.V(2).Info("Validate update", "name", r.Name)
+	s := old.(*Route)
+
+	// routes cannot be changed in Cloud Foundry (other than their metadata)
+	if r.Spec.ClusterSpaceName != s.Spec.ClusterSpaceName {
+		return nil, immutableFieldError("spec.clusterSpaceName", s.Spec.ClusterSpaceName, r.Spec.ClusterSpaceName)
+	}
+
+	if r.Spec.SpaceName != s.Spec.SpaceName {
+		return nil, immutableFieldError("spec.spaceName", s.Spec.SpaceName, r.Spec.SpaceName)
+	}
+
+	if r.Spec.Domain != s.Spec.Domain {
+		return nil, immutableFieldError("spec.domain", s.Spec.Domain, r.Spec.Domain)
+	}
+
+	if r.Spec.Host != s.Spec.Host {
+		return nil, immutableFieldError("spec.host", s.Spec.Host, r.Spec.Host)
+	}
+
+	if r.Spec.Path != s.Spec.Path {
+		return nil, immutableFieldError("spec.path", s.Spec.Path, r.Spec.Path)
+	}
+
+	return updateWarnings(annotationWarnings(r.Annotations), annotationWarnings(s.Annotations)), nil
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *Route) ValidateDelete() (admission.Warnings, error) {
+	routelog.V(2).Info("Validate delete", "name", r.Name)
+
+	return nil, nil
+}
@@ -0,0 +1,215 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1alpha1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Route",type=string,JSONPath=`.spec.routeName`
+// +kubebuilder:printcolumn:name="Instance",type=string,JSONPath=`.spec.serviceInstanceName`
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +genclient
+
+// RouteBinding is the Schema for the routebindings API
+type RouteBinding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RouteBindingSpec `json:"spec,omitempty"`
+
+	// +kubebuilder:default={"observedGeneration":-1}
+	Status RouteBindingStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RouteBindingList contains a list of RouteBinding
+type RouteBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RouteBinding `json:"items"`
+}
+
+// RouteBindingSpec defines the desired state of RouteBinding
+type RouteBindingSpec struct {
+	// Name of a Route resource in the same namespace,
+	// identifying the Cloud Foundry route the service instance will be bound to.
+	// +kubebuilder:validation:MinLength=1
+	RouteName string `json:"routeName"`
+
+	// Name of a ServiceInstance resource in the same namespace,
+	// identifying the Cloud Foundry route service instance which will be bound to the route.
+	// +kubebuilder:validation:MinLength=1
+	ServiceInstanceName string `json:"serviceInstanceName"`
+
+	// Binding parameters.
+	// Do not provide any sensitve data here; instead use ParametersFrom for such data.
+	// +optional
+	Parameters *apiextensionsv1.JSON `json:"parameters,omitempty"`
+
+	// References to secrets, config maps or fields of this object containing binding parameters.
+	// Top level keys must occur only once across Parameters and the sources listed here, unless another ParametersMergeStrategy is specified.
+	// +optional
+	ParametersFrom []ParametersFromSource `json:"parametersFrom,omitempty"`
+
+	// How Parameters and the sources listed in ParametersFrom are merged (ordered by their priority); defaults to Error.
+	// +optional
+	ParametersMergeStrategy ParametersMergeStrategy `json:"parametersMergeStrategy,omitempty"`
+}
+
+// RouteBindingStatus defines the observed state of RouteBinding
+type RouteBindingStatus struct {
+	// Observed generation; this is the last generation which was successfully applied (that is, for which
+	// the Ready condition became True), so it lags behind metadata.generation while changes are in progress
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Whether the object is ready, that is, whether the Ready condition is True; note that this refers
+	// to status.observedGeneration, which must therefore be compared with metadata.generation
+	// +optional
+	Ready bool `json:"ready"`
+
+	// Last reconciliation timestamp
+	// +optional
+	LastReconciledAt *metav1.Time `json:"lastReconciledAt,omitempty"`
+
+	// Last modification timestamp (when the last create/update/delete request was sent to Cloud Foundry)
+	// +optional
+	LastModifiedAt *metav1.Time `json:"lastModifiedAt,omitempty"`
+
+	// Cloud Foundry space guid
+	// +optional
+	SpaceGuid string `json:"spaceGuid,omitempty"`
+
+	// Cloud Foundry route guid
+	// +optional
+	RouteGuid string `json:"routeGuid,omitempty"`
+
+	// Cloud Foundry service instance guid
+	// +optional
+	ServiceInstanceGuid string `json:"serviceInstanceGuid,omitempty"`
+
+	// Cloud Foundry route binding guid
+	// +optional
+	RouteBindingGuid string `json:"routeBindingGuid,omitempty"`
+
+	// URL of the route service, as reported by Cloud Foundry
+	// +optional
+	RouteServiceURL string `json:"routeServiceUrl,omitempty"`
+
+	// List of status conditions to indicate the status of a RouteBinding.
+	// Known condition types are `Ready`, `Synced`, `DeletionBlocked`, `CFReachable`, `Paused`, `Timeout`.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []RouteBindingCondition `json:"conditions,omitempty"`
+
+	// Readable form of the state.
+	// +optional
+	State RouteBindingState `json:"state,omitempty"`
+}
+
+// RouteBindingCondition contains condition information for a RouteBinding.
+type RouteBindingCondition struct {
+	// Type of the condition, known values are ('Ready', 'Synced', 'DeletionBlocked', 'CFReachable').
+	Type RouteBindingConditionType `json:"type"`
+
+	// Status of the condition, one of ('True', 'False', 'Unknown').
+	Status ConditionStatus `json:"status"`
+
+	// LastTransitionTime is the timestamp corresponding to the last status
+	// change of this condition.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Reason is a brief machine readable explanation for the condition's last
+	// transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human readable description of the details of the last
+	// transition, complementing reason.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the metadata.generation of the object the condition was set for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// RouteBindingConditionType represents a RouteBinding condition value.
+type RouteBindingConditionType string
+
+const (
+	// RouteBindingConditionReady represents the fact that a given route binding is ready.
+	RouteBindingConditionReady RouteBindingConditionType = "Ready"
+	// RouteBindingConditionSynced represents the fact that the Cloud Foundry route binding reflects the current spec;
+	// it is False if applying the spec failed, and Unknown while an operation is in progress.
+	RouteBindingConditionSynced RouteBindingConditionType = "Synced"
+	// RouteBindingConditionDeletionBlocked represents the fact that the deletion of the route binding is blocked
+	// (e.g. by foreign finalizers); it is only present while the route binding is being deleted.
+	RouteBindingConditionDeletionBlocked RouteBindingConditionType = "DeletionBlocked"
+	// RouteBindingConditionCFReachable represents the fact that the Cloud Foundry API was reachable during the last reconciliation.
+	RouteBindingConditionCFReachable RouteBindingConditionType = "CFReachable"
+	// RouteBindingConditionPaused represents the fact that reconciliation is paused by the annotation service-operator.cf.cs.sap.com/paused;
+	// it is only present while the annotation is set.
+	RouteBindingConditionPaused RouteBindingConditionType = "Paused"
+	// RouteBindingConditionTimeout represents the fact that the last reconciliation did not complete within the maximum reconcile duration
+	// (see annotation service-operator.cf.cs.sap.com/reconcile-call-timeout); it is only present while this is the case.
+	RouteBindingConditionTimeout RouteBindingConditionType = "Timeout"
+)
+
+// RouteBindingState represents a condition state in a readable form
+// +kubebuilder:validation:Enum=Processing;Deleting;Ready;Error
+type RouteBindingState string
+
+// These are valid condition states
+const (
+	// RouteBindingStateProcessing represents the fact that the route binding is reconciling
+	RouteBindingStateProcessing RouteBindingState = "Processing"
+
+	// RouteBindingStateDeleting represents the fact that the route binding is being deleted
+	RouteBindingStateDeleting RouteBindingState = "Deleting"
+
+	// RouteBindingStateReady represents the fact that the route binding is ready
+	RouteBindingStateReady RouteBindingState = "Ready"
+
+	// RouteBindingStateError represents the fact that the route binding is not ready resp. has an error
+	RouteBindingStateError RouteBindingState = "Error"
+)
+
+func (routeBinding *RouteBinding) SetReadyCondition(conditionStatus ConditionStatus, reason, message string) {
+	setRouteBindingReadyCondition(routeBinding, conditionStatus, reason, message)
+}
+
+func (routeBinding *RouteBinding) GetReadyCondition() *RouteBindingCondition {
+	return getRouteBindingReadyCondition(routeBinding)
+}
+
+func (routeBinding *RouteBinding) SetCondition(conditionType RouteBindingConditionType, conditionStatus ConditionStatus, reason, message string) {
+	setRouteBindingCondition(routeBinding, conditionType, conditionStatus, reason, message)
+}
+
+func (routeBinding *RouteBinding) GetCondition(conditionType RouteBindingConditionType) *RouteBindingCondition {
+	return getRouteBindingCondition(routeBinding, conditionType)
+}
+
+func (routeBinding *RouteBinding) RemoveCondition(conditionType RouteBindingConditionType) {
+	removeRouteBindingCondition(routeBinding, conditionType)
+}
+
+func (routeBinding *RouteBinding) IsReady() bool {
+	return isRouteBindingReady(routeBinding)
+}
+
+func init() {
+	SchemeBuilder.Register(&RouteBinding{}, &RouteBindingList{})
+}
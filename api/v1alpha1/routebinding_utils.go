@@ -0,0 +1,93 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func setRouteBindingReadyCondition(routeBinding *RouteBinding, conditionStatus ConditionStatus, reason, message string) {
+	setRouteBindingCondition(routeBinding, RouteBindingConditionReady, conditionStatus, reason, message)
+
+	status := &routeBinding.Status
+	// the observed generation is only bumped after a successful sync, such that health checks (e.g. of GitOps tools)
+	// do not consider the object ready while changes are still being applied
+	status.Ready = conditionStatus == ConditionTrue
+	switch conditionStatus {
+	case ConditionTrue:
+		status.ObservedGeneration = routeBinding.Generation
+		status.State = RouteBindingStateReady
+	case ConditionFalse:
+		status.State = RouteBindingStateError
+	default:
+		if routeBinding.DeletionTimestamp.IsZero() {
+			status.State = RouteBindingStateProcessing
+		} else {
+			status.State = RouteBindingStateDeleting
+		}
+	}
+}
+
+func getRouteBindingReadyCondition(routeBinding *RouteBinding) *RouteBindingCondition {
+	return getRouteBindingCondition(routeBinding, RouteBindingConditionReady)
+}
+
+// setRouteBindingCondition adds or updates the condition of the given type; the transition time is only updated if the status changes.
+func setRouteBindingCondition(routeBinding *RouteBinding, conditionType RouteBindingConditionType, conditionStatus ConditionStatus, reason, message string) {
+	status := &routeBinding.Status
+	condition := getRouteBindingCondition(routeBinding, conditionType)
+	if condition == nil {
+		condition = &RouteBindingCondition{
+			Type: conditionType,
+		}
+		status.Conditions = append(status.Conditions, *condition)
+	}
+	if condition.Status != conditionStatus {
+		condition.Status = conditionStatus
+		now := metav1.Now()
+		condition.LastTransitionTime = &now
+	}
+	condition.Reason = reason
+	condition.Message = message
+	condition.ObservedGeneration = routeBinding.GetGeneration()
+
+	for i, c := range status.Conditions {
+		if c.Type == conditionType {
+			status.Conditions[i] = *condition
+			break
+		}
+	}
+}
+
+func getRouteBindingCondition(routeBinding *RouteBinding, conditionType RouteBindingConditionType) *RouteBindingCondition {
+	status := &routeBinding.Status
+	for _, c := range status.Conditions {
+		if c.Type == conditionType {
+			return &c
+		}
+	}
+	return nil
+}
+
+func removeRouteBindingCondition(routeBinding *RouteBinding, conditionType RouteBindingConditionType) {
+	status := &routeBinding.Status
+	for i, c := range status.Conditions {
+		if c.Type == conditionType {
+			status.Conditions = append(status.Conditions[:i], status.Conditions[i+1:]...)
+			return
+		}
+	}
+}
+
+func isRouteBindingReady(routeBinding *RouteBinding) bool {
+	if routeBinding.Status.ObservedGeneration != routeBinding.Generation {
+		return false
+	}
+	if c := getRouteBindingReadyCondition(routeBinding); c != nil {
+		return c.Status == ConditionTrue
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var routebindinglog = logf.Log.WithName("routebinding-resource")
+
+func (r *RouteBinding) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-cf-cs-sap-com-v1alpha1-routebinding,mutating=true,failurePolicy=fail,sideEffects=None,groups=cf.cs.sap.com,resources=routebindings,verbs=create;update,versions=v1alpha1,name=mroutebinding.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &RouteBinding{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *RouteBinding) Default() {
+	routebindinglog.V(2).Info("Default", "name", r.Name)
+
+	if r.Labels == nil {
+		r.Labels = make(map[string]string)
+	}
+	r.Labels[LabelKeyRoute] = r.Spec.RouteName
+	r.Labels[LabelKeyServiceInstance] = r.Spec.ServiceInstanceName
+}
+
+// +kubebuilder:webhook:path=/validate-cf-cs-sap-com-v1alpha1-routebinding,mutating=false,failurePolicy=fail,sideEffects=None,groups=cf.cs.sap.com,resources=routebindings,verbs=create;update,versions=v1alpha1,name=vroutebinding.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &RouteBinding{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *RouteBinding) ValidateCreate() (admission.Warnings, error) {
+	routebindinglog.V(2).Info("Validate create", "name", r.Name)
+
+	if err := validateParametersFrom(r.Spec.ParametersFrom, "spec.parametersFrom"); err != nil {
+		return nil, err
+	}
+
+	return r.validationWarnings(), nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *RouteBinding) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	routebindinglog.V(2).Info("Validate update", "name", r.Name)
+	s := old.(*RouteBinding)
+
+	if r.Spec.RouteName != s.Spec.RouteName {
+		return nil, immutableFieldError("spec.routeName", s.Spec.RouteName, r.Spec.RouteName)
+	}
+
+	if r.Spec.ServiceInstanceName != s.Spec.ServiceInstanceName {
+		return nil, immutableFieldError("spec.serviceInstanceName", s.Spec.ServiceInstanceName, r.Spec.ServiceInstanceName)
+	}
+
+	if err := validateParametersFrom(r.Spec.ParametersFrom, "spec.parametersFrom"); err != nil {
+		return nil, err
+	}
+
+	return updateWarnings(r.validationWarnings(), s.validationWarnings()), nil
+}
+
+// validationWarnings returns warnings about risky (but valid) settings of the route binding.
+func (r *RouteBinding) validationWarnings() admission.Warnings {
+	return append(parametersWarnings(r.Spec.ParametersMergeStrategy, r.Spec.ParametersFrom, "spec.parametersFrom"), annotationWarnings(r.Annotations)...)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *RouteBinding) ValidateDelete() (admission.Warnings, error) {
+	routebindinglog.V(2).Info("Validate delete", "name", r.Name)
+
+	return nil, nil
+}
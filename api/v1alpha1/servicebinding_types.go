@@ -12,6 +12,7 @@ import (
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`,priority=1
 // +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +genclient
@@ -45,38 +46,248 @@ type ServiceBindingSpec struct {
 
 	// Name of a ServiceInstance resource in the same namespace,
 	// identifying the Cloud Foundry service instance this binding refers to.
+	// Exactly one of ServiceInstanceName and ServiceInstanceGuid have to be specified.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	ServiceInstanceName string `json:"serviceInstanceName,omitempty"`
+
+	// Guid of a Cloud Foundry service instance which is not managed by this operator (such as a manually provisioned, shared instance),
+	// identifying the Cloud Foundry service instance this binding refers to; the space of the instance has to be given by SpaceName or ClusterSpaceName.
+	// Exactly one of ServiceInstanceName and ServiceInstanceGuid have to be specified.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	ServiceInstanceGuid string `json:"serviceInstanceGuid,omitempty"`
+
+	// Name of a Space resource in the same namespace,
+	// identifying the Cloud Foundry space of the service instance given by ServiceInstanceGuid.
+	// If ServiceInstanceGuid is specified, exactly one of SpaceName and ClusterSpaceName have to be specified.
+	// +optional
 	// +kubebuilder:validation:MinLength=1
-	ServiceInstanceName string `json:"serviceInstanceName"`
+	SpaceName string `json:"spaceName,omitempty"`
+
+	// Name of a ClusterSpace resource,
+	// identifying the Cloud Foundry space of the service instance given by ServiceInstanceGuid.
+	// If ServiceInstanceGuid is specified, exactly one of SpaceName and ClusterSpaceName have to be specified.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	ClusterSpaceName string `json:"clusterSpaceName,omitempty"`
+
+	// Guid of a Cloud Foundry application (in the space of the service instance) the service instance shall be bound to;
+	// if specified, an app binding is created instead of a service key. Mutually exclusive with AppName.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	AppGuid string `json:"appGuid,omitempty"`
+
+	// Name of a Cloud Foundry application (in the space of the service instance) the service instance shall be bound to;
+	// if specified, an app binding is created instead of a service key. Mutually exclusive with AppGuid.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	AppName string `json:"appName,omitempty"`
+
+	// Type of the Cloud Foundry binding: key (a service key) or app (an app binding, requiring AppGuid or AppName).
+	// If unspecified, it is app if AppGuid or AppName is specified, and key otherwise.
+	// +optional
+	Type ServiceBindingType `json:"type,omitempty"`
 
 	// Binding parameters.
 	// Do not provide any sensitve data here; instead use ParametersFrom for such data.
 	// +optional
 	Parameters *apiextensionsv1.JSON `json:"parameters,omitempty"`
 
-	// References to secrets containing binding parameters.
-	// Top level keys must occur only once across Parameters and the secrest listed here.
+	// References to secrets, config maps or fields of this object containing binding parameters.
+	// Top level keys must occur only once across Parameters and the sources listed here, unless another ParametersMergeStrategy is specified.
 	// +optional
 	ParametersFrom []ParametersFromSource `json:"parametersFrom,omitempty"`
 
-	// Secret name where the binding credentials shall be stored (in the same namespace where the binding exists).
+	// How Parameters and the sources listed in ParametersFrom are merged (ordered by their priority); defaults to Error.
+	// +optional
+	ParametersMergeStrategy ParametersMergeStrategy `json:"parametersMergeStrategy,omitempty"`
+
+	// Labels and annotations to be set on the Cloud Foundry binding (in addition to the ones maintained by the operator).
+	// +optional
+	Metadata *CFMetadata `json:"metadata,omitempty"`
+
+	// Secret name where the binding credentials shall be stored (in the namespace given by SecretNamespace).
 	// If unspecified, metadata.name will be used.
 	// +optional
 	// +kubebuilder:validation:MinLength=1
 	SecretName string `json:"secretName,omitempty"`
 
+	// Namespace where the binding credentials shall be stored.
+	// If unspecified, the namespace of the binding will be used; other namespaces must be allowed by the operator configuration (secretNamespaces).
+	// Binding secrets in other namespaces are not owned by the binding (since owner references cannot cross namespaces),
+	// but are deleted by the operator when the binding is deleted or the secret moves.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	SecretNamespace string `json:"secretNamespace,omitempty"`
+
 	// Secret key (referring to SecretName) where the binding credentials will be stored.
 	// If unspecified, the top level keys of the binding credentials will become the secret keys.
 	// +optional
 	// +kubebuilder:validation:MinLength=1
 	SecretKey string `json:"secretKey,omitempty"`
+
+	// Top level keys of the binding credentials which shall be stored (for example, to omit certificates which are not needed);
+	// if unspecified, all keys are stored. All listed keys must be present in the binding credentials.
+	// With SecretKey, the selected keys are stored as JSON object under that key.
+	// +optional
+	// +listType=set
+	// +kubebuilder:validation:items:MinLength=1
+	SecretKeys []string `json:"secretKeys,omitempty"`
+
+	// Type of the binding secret (for example kubernetes.io/basic-auth, or a custom type); defaults to Opaque.
+	// Note that some types require certain keys to be present (which then must be provided by the binding credentials).
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	SecretType string `json:"secretType,omitempty"`
+
+	// Whether the binding secret shall be immutable. Since immutable secrets cannot be updated, the name of the binding secret
+	// is then suffixed with a hash of its content; whenever the content changes (e.g. by credentials rotation or recreation of the binding),
+	// a new secret is created, and the previous one is deleted. The current name is reported in status.secretName.
+	// +optional
+	SecretImmutable bool `json:"secretImmutable,omitempty"`
+
+	// Additional binding secrets, each holding selected keys of the binding credentials (for example, TLS material in a secret
+	// of type kubernetes.io/tls, and the connection string in another one); they are written to the namespace of the binding secret,
+	// which is maintained as well. Not supported together with SecretStoreRef or SecretImmutable.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Secrets []BindingSecret `json:"secrets,omitempty"`
+
+	// SAP binding metadata added to the binding secret, overriding the operator default (sapBindingMetadata)
+	// and the annotation service-operator.cf.cs.sap.com/with-sap-binding-metadata.
+	// +optional
+	SAPBindingMetadata *SAPBindingMetadata `json:"sapBindingMetadata,omitempty"`
+
+	// External secret store to which the binding credentials shall be written.
+	// With type Vault, the credentials are written to HashiCorp Vault only (no secret is created);
+	// with type PushSecret, the binding secret is created as usual, and pushed to an external-secrets SecretStore.
+	// Immutable; mutually exclusive with SecretNamespace.
+	// +optional
+	SecretStoreRef *SecretStoreReference `json:"secretStoreRef,omitempty"`
+}
+
+// BindingSecret describes an additional binding secret, holding selected keys of the binding credentials.
+type BindingSecret struct {
+	// Name of the secret; must differ from the name of the binding secret (SecretName).
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Keys of the binding credentials stored in the secret; all listed keys must be present in the binding credentials.
+	// +listType=map
+	// +listMapKey=key
+	// +kubebuilder:validation:MinItems=1
+	Keys []BindingSecretKey `json:"keys"`
+
+	// How the keys are stored; with Flat, every key becomes a secret key, with JSON, the keys are stored as JSON object
+	// under the secret key credentials. Defaults to Flat.
+	// +optional
+	// +kubebuilder:validation:Enum=Flat;JSON
+	Format BindingSecretFormat `json:"format,omitempty"`
+
+	// Type of the secret (for example kubernetes.io/tls); defaults to Opaque.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	Type string `json:"type,omitempty"`
+}
+
+// BindingSecretKey selects a key of the binding credentials for an additional binding secret.
+type BindingSecretKey struct {
+	// Top level key of the binding credentials.
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+
+	// Name under which the key is stored (for example tls.crt); defaults to Key.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	SecretKey string `json:"secretKey,omitempty"`
+}
+
+// BindingSecretFormat defines how the keys of an additional binding secret are stored.
+type BindingSecretFormat string
+
+const (
+	// BindingSecretFormatFlat stores every key as secret key.
+	BindingSecretFormatFlat BindingSecretFormat = "Flat"
+	// BindingSecretFormatJSON stores the keys as JSON object under the secret key credentials.
+	BindingSecretFormatJSON BindingSecretFormat = "JSON"
+)
+
+// SAPBindingMetadataField is a metadata field added to binding secrets according to the SAP binding metadata specification.
+// +kubebuilder:validation:Enum=type;label;plan;tags;instance_name;instance_guid
+type SAPBindingMetadataField string
+
+const (
+	SAPBindingMetadataFieldType         SAPBindingMetadataField = "type"
+	SAPBindingMetadataFieldLabel        SAPBindingMetadataField = "label"
+	SAPBindingMetadataFieldPlan         SAPBindingMetadataField = "plan"
+	SAPBindingMetadataFieldTags         SAPBindingMetadataField = "tags"
+	SAPBindingMetadataFieldInstanceName SAPBindingMetadataField = "instance_name"
+	SAPBindingMetadataFieldInstanceGuid SAPBindingMetadataField = "instance_guid"
+)
+
+// SAPBindingMetadata controls the SAP binding metadata added to a binding secret.
+type SAPBindingMetadata struct {
+	// Whether SAP binding metadata are added to the binding secret; if unspecified, the operator default
+	// (resp. the annotation service-operator.cf.cs.sap.com/with-sap-binding-metadata) applies.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Metadata fields added to the binding secret; if unspecified, all fields are added.
+	// The .metadata key describing the secret is always added (listing the added fields only).
+	// +optional
+	// +listType=set
+	Fields []SAPBindingMetadataField `json:"fields,omitempty"`
+}
+
+// SecretStoreType is the type of an external secret store.
+// +kubebuilder:validation:Enum=Vault;PushSecret
+type SecretStoreType string
+
+const (
+	// HashiCorp Vault (KV secrets engine, version 2).
+	SecretStoreTypeVault SecretStoreType = "Vault"
+	// A SecretStore or ClusterSecretStore of the external-secrets operator, to which the binding secret is pushed by a PushSecret object.
+	SecretStoreTypePushSecret SecretStoreType = "PushSecret"
+)
+
+// SecretStoreReference references an external secret store for binding credentials.
+type SecretStoreReference struct {
+	// Type of the secret store.
+	Type SecretStoreType `json:"type"`
+
+	// For type Vault, the name of a secret (in the namespace of the binding) containing the Vault connection settings
+	// (keys address, and token or role; optionally mount, authMount, namespace, ca.crt).
+	// For type PushSecret, the name of the SecretStore (or ClusterSecretStore).
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// For type PushSecret, the kind of the secret store; defaults to SecretStore.
+	// +optional
+	// +kubebuilder:validation:Enum=SecretStore;ClusterSecretStore
+	Kind string `json:"kind,omitempty"`
+
+	// Path (Vault) resp. remote key (PushSecret) under which the credentials are stored; Vault paths are relative to
+	// the prefix <binding namespace>/, and must not contain . or .. segments.
+	// If unspecified, <binding namespace>/<secret name> will be used.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path,omitempty"`
 }
 
 // ServiceBindingStatus defines the observed state of ServiceBinding
 type ServiceBindingStatus struct {
-	// Observed generation
+	// Observed generation; this is the last generation which was successfully applied (that is, for which
+	// the Ready condition became True), so it lags behind metadata.generation while changes are in progress
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
+	// Whether the object is ready, that is, whether the Ready condition is True; note that this refers
+	// to status.observedGeneration, which must therefore be compared with metadata.generation
+	// +optional
+	Ready bool `json:"ready"`
+
 	// Last reconciliation timestamp
 	// +optional
 	LastReconciledAt *metav1.Time `json:"lastReconciledAt,omitempty"`
@@ -97,6 +308,14 @@ type ServiceBindingStatus struct {
 	// +optional
 	ServiceInstanceDigest string `json:"serviceInstanceDigest,omitempty"`
 
+	// Cloud Foundry application guid (app bindings only)
+	// +optional
+	AppGuid string `json:"appGuid,omitempty"`
+
+	// Type of the Cloud Foundry binding (key or app), as reported by Cloud Foundry
+	// +optional
+	Type ServiceBindingType `json:"type,omitempty"`
+
 	// Cloud Foundry service binding guid
 	// +optional
 	ServiceBindingGuid string `json:"serviceBindingGuid,omitempty"`
@@ -105,9 +324,56 @@ type ServiceBindingStatus struct {
 	// +optional
 	ServiceBindingDigest string `json:"serviceBindingDigest,omitempty"`
 
+	// Name of the binding secret holding the current credentials; differs from spec.secretName if spec.secretImmutable is true
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// Digest identifying the credentials last written to the binding secret
+	// +optional
+	CredentialsDigest string `json:"credentialsDigest,omitempty"`
+
+	// Names of the (top level) keys of the binding credentials last written (not their values)
+	// +optional
+	CredentialKeys []string `json:"credentialKeys,omitempty"`
+
+	// Effective tags of the bound service instance (the name of the service offering, followed by the tags of the instance)
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// Timestamp of the last explicit refresh of the binding credentials
+	// (see annotation service-operator.cf.cs.sap.com/refresh-credentials-interval)
+	// +optional
+	LastCredentialsRefreshAt *metav1.Time `json:"lastCredentialsRefreshAt,omitempty"`
+
+	// Counts the number of retries that have been attempted for the reconciliation of this service binding.
+	// This counter can be used to fail the binding if too many retries occur.
+	// +optional
+	RetryCounter int `json:"retryCounter,omitempty"`
+
+	// This is the maximum number of retries that are allowed for the reconciliation of this service binding.
+	// If the retry counter exceeds this value, the service binding will be marked as failed.
+	// +optional
+	MaxRetries int `json:"maxRetries,omitempty"`
+
+	// Error returned by the Cloud Foundry API which failed the most recent reconciliation
+	// (empty if the most recent reconciliation succeeded, or failed for other reasons)
+	// +optional
+	LastCFError *CFError `json:"lastCFError,omitempty"`
+
+	// URL of the Cloud Foundry API endpoint used by the most recent reconciliation
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Last operation performed by Cloud Foundry on the service binding (as of the most recent reconciliation);
+	// the timestamps allow to tell for how long an operation has been running
+	// +optional
+	LastOperation *LastOperation `json:"lastOperation,omitempty"`
+
 	// List of status conditions to indicate the status of a ServiceBinding.
-	// Known condition types are `Ready`.
+	// Known condition types are `Ready`, `Synced`, `CredentialsReady`, `DeletionBlocked`, `CFReachable`, `Paused`, `Timeout`.
 	// +optional
+	// +listType=map
+	// +listMapKey=type
 	Conditions []ServiceBindingCondition `json:"conditions,omitempty"`
 
 	// Readable form of the state.
@@ -117,7 +383,7 @@ type ServiceBindingStatus struct {
 
 // ServiceBindingCondition contains condition information for a ServiceBinding.
 type ServiceBindingCondition struct {
-	// Type of the condition, known values are ('Ready').
+	// Type of the condition, known values are ('Ready', 'Synced', 'CredentialsReady', 'DeletionBlocked', 'CFReachable').
 	Type ServiceBindingConditionType `json:"type"`
 
 	// Status of the condition, one of ('True', 'False', 'Unknown').
@@ -137,6 +403,10 @@ type ServiceBindingCondition struct {
 	// transition, complementing reason.
 	// +optional
 	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the metadata.generation of the object the condition was set for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // ServiceBindingConditionType represents a ServiceBinding condition value.
@@ -145,6 +415,34 @@ type ServiceBindingConditionType string
 const (
 	// ServiceBindingConditionReady represents the fact that a given service is ready.
 	ServiceBindingConditionReady ServiceBindingConditionType = "Ready"
+	// ServiceBindingConditionSynced represents the fact that the Cloud Foundry binding reflects the current spec;
+	// it is False if applying the spec failed, and Unknown while an operation is in progress.
+	ServiceBindingConditionSynced ServiceBindingConditionType = "Synced"
+	// ServiceBindingConditionCredentialsReady represents the fact that the binding credentials were written to the binding secret.
+	ServiceBindingConditionCredentialsReady ServiceBindingConditionType = "CredentialsReady"
+	// ServiceBindingConditionDeletionBlocked represents the fact that the deletion of the service binding
+	// is blocked (e.g. by foreign finalizers); it is only present while the service binding is being deleted.
+	ServiceBindingConditionDeletionBlocked ServiceBindingConditionType = "DeletionBlocked"
+	// ServiceBindingConditionCFReachable represents the fact that the Cloud Foundry API was reachable during the last reconciliation.
+	ServiceBindingConditionCFReachable ServiceBindingConditionType = "CFReachable"
+	// ServiceBindingConditionPaused represents the fact that reconciliation is paused by the annotation service-operator.cf.cs.sap.com/paused;
+	// it is only present while the annotation is set.
+	ServiceBindingConditionPaused ServiceBindingConditionType = "Paused"
+	// ServiceBindingConditionTimeout represents the fact that the last reconciliation did not complete within the maximum reconcile duration
+	// (see annotation service-operator.cf.cs.sap.com/reconcile-call-timeout); it is only present while this is the case.
+	ServiceBindingConditionTimeout ServiceBindingConditionType = "Timeout"
+)
+
+// ServiceBindingType represents the type of a Cloud Foundry service credential binding
+// +kubebuilder:validation:Enum=key;app
+type ServiceBindingType string
+
+const (
+	// ServiceBindingTypeKey represents a service key, that is, a binding not associated with an application
+	ServiceBindingTypeKey ServiceBindingType = "key"
+
+	// ServiceBindingTypeApp represents an app binding, that is, a binding associated with a Cloud Foundry application
+	ServiceBindingTypeApp ServiceBindingType = "app"
 )
 
 // ServiceBindingState represents a condition state in a readable form
@@ -174,6 +472,18 @@ func (serviceBinding *ServiceBinding) GetReadyCondition() *ServiceBindingConditi
 	return getServiceBindingReadyCondition(serviceBinding)
 }
 
+func (serviceBinding *ServiceBinding) SetCondition(conditionType ServiceBindingConditionType, conditionStatus ConditionStatus, reason, message string) {
+	setServiceBindingCondition(serviceBinding, conditionType, conditionStatus, reason, message)
+}
+
+func (serviceBinding *ServiceBinding) GetCondition(conditionType ServiceBindingConditionType) *ServiceBindingCondition {
+	return getServiceBindingCondition(serviceBinding, conditionType)
+}
+
+func (serviceBinding *ServiceBinding) RemoveCondition(conditionType ServiceBindingConditionType) {
+	removeServiceBindingCondition(serviceBinding, conditionType)
+}
+
 func (serviceBinding *ServiceBinding) IsReady() bool {
 	return isServiceBindingReady(serviceBinding)
 }
@@ -10,31 +10,15 @@ import (
 )
 
 func setServiceBindingReadyCondition(serviceBinding *ServiceBinding, conditionStatus ConditionStatus, reason, message string) {
-	status := &serviceBinding.Status
-	ready := getServiceBindingReadyCondition(serviceBinding)
-	if ready == nil {
-		ready = &ServiceBindingCondition{
-			Type: ServiceBindingConditionReady,
-		}
-		status.Conditions = append(status.Conditions, *ready)
-	}
-	if ready.Status != conditionStatus {
-		ready.Status = conditionStatus
-		now := metav1.Now()
-		ready.LastTransitionTime = &now
-	}
-	ready.Reason = reason
-	ready.Message = message
-
-	for i, c := range status.Conditions {
-		if c.Type == ServiceBindingConditionReady {
-			status.Conditions[i] = *ready
-			break
-		}
-	}
+	setServiceBindingCondition(serviceBinding, ServiceBindingConditionReady, conditionStatus, reason, message)
 
+	status := &serviceBinding.Status
+	// the observed generation is only bumped after a successful sync, such that health checks (e.g. of GitOps tools)
+	// do not consider the object ready while changes are still being applied
+	status.Ready = conditionStatus == ConditionTrue
 	switch conditionStatus {
 	case ConditionTrue:
+		status.ObservedGeneration = serviceBinding.Generation
 		status.State = ServiceBindingStateReady
 	case ConditionFalse:
 		status.State = ServiceBindingStateError
@@ -48,15 +32,56 @@ func setServiceBindingReadyCondition(serviceBinding *ServiceBinding, conditionSt
 }
 
 func getServiceBindingReadyCondition(serviceBinding *ServiceBinding) *ServiceBindingCondition {
+	return getServiceBindingCondition(serviceBinding, ServiceBindingConditionReady)
+}
+
+// setServiceBindingCondition adds or updates the condition of the given type; the transition time is only updated if the status changes.
+func setServiceBindingCondition(serviceBinding *ServiceBinding, conditionType ServiceBindingConditionType, conditionStatus ConditionStatus, reason, message string) {
+	status := &serviceBinding.Status
+	condition := getServiceBindingCondition(serviceBinding, conditionType)
+	if condition == nil {
+		condition = &ServiceBindingCondition{
+			Type: conditionType,
+		}
+		status.Conditions = append(status.Conditions, *condition)
+	}
+	if condition.Status != conditionStatus {
+		condition.Status = conditionStatus
+		now := metav1.Now()
+		condition.LastTransitionTime = &now
+	}
+	condition.Reason = reason
+	condition.Message = message
+	condition.ObservedGeneration = serviceBinding.GetGeneration()
+
+	for i, c := range status.Conditions {
+		if c.Type == conditionType {
+			status.Conditions[i] = *condition
+			break
+		}
+	}
+}
+
+func getServiceBindingCondition(serviceBinding *ServiceBinding, conditionType ServiceBindingConditionType) *ServiceBindingCondition {
 	status := &serviceBinding.Status
 	for _, c := range status.Conditions {
-		if c.Type == ServiceBindingConditionReady {
+		if c.Type == conditionType {
 			return &c
 		}
 	}
 	return nil
 }
 
+func removeServiceBindingCondition(serviceBinding *ServiceBinding, conditionType ServiceBindingConditionType) {
+	status := &serviceBinding.Status
+	for i, c := range status.Conditions {
+		if c.Type == conditionType {
+			status.Conditions = append(status.Conditions[:i], status.Conditions[i+1:]...)
+			return
+		}
+	}
+}
+
 func isServiceBindingReady(serviceBinding *ServiceBinding) bool {
 	if serviceBinding.Status.ObservedGeneration != serviceBinding.Generation {
 		return false
@@ -6,9 +6,14 @@ SPDX-License-Identifier: Apache-2.0
 package v1alpha1
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -18,10 +23,15 @@ import (
 // log is for logging in this package.
 var servicebindinglog = logf.Log.WithName("servicebinding-resource")
 
-func (r *ServiceBinding) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
-		For(r).
-		Complete()
+// SetupWebhookWithManager registers the webhooks for ServiceBinding; if secretNameTemplate is not nil,
+// spec.secretName is defaulted by rendering the template (instead of using metadata.name).
+func (r *ServiceBinding) SetupWebhookWithManager(mgr ctrl.Manager, secretNameTemplate *template.Template) error {
+	builder := ctrl.NewWebhookManagedBy(mgr).
+		For(r)
+	if secretNameTemplate != nil {
+		builder = builder.WithDefaulter(&serviceBindingDefaulter{secretNameTemplate: secretNameTemplate})
+	}
+	return builder.Complete()
 }
 
 // +kubebuilder:webhook:path=/mutate-cf-cs-sap-com-v1alpha1-servicebinding,mutating=true,failurePolicy=fail,sideEffects=None,groups=cf.cs.sap.com,resources=servicebindings,verbs=create;update,versions=v1alpha1,name=mservicebinding.kb.io,admissionReviewVersions=v1
@@ -35,7 +45,9 @@ func (r *ServiceBinding) Default() {
 	if r.Labels == nil {
 		r.Labels = make(map[string]string)
 	}
-	r.Labels[LabelKeyServiceInstance] = r.Spec.ServiceInstanceName
+	if r.Spec.ServiceInstanceName != "" {
+		r.Labels[LabelKeyServiceInstance] = r.Spec.ServiceInstanceName
+	}
 
 	if r.Spec.Name == "" {
 		r.Spec.Name = r.Name
@@ -43,6 +55,68 @@ func (r *ServiceBinding) Default() {
 	if r.Spec.SecretName == "" {
 		r.Spec.SecretName = r.Name
 	}
+	if r.Spec.Type == "" {
+		if r.Spec.AppGuid != "" || r.Spec.AppName != "" {
+			r.Spec.Type = ServiceBindingTypeApp
+		} else {
+			r.Spec.Type = ServiceBindingTypeKey
+		}
+	}
+}
+
+// DefaultSecretName defaults spec.secretName (if unspecified) by rendering the given template with the service binding;
+// nothing is done if the template is nil, or if metadata.name is not yet known (in case of generateName usage).
+// Bindings referencing the service instance by guid have no spec.serviceInstanceName; if the template uses it,
+// spec.secretName falls back to metadata.name (as without template), instead of a name rendered with an empty value.
+func (r *ServiceBinding) DefaultSecretName(secretNameTemplate *template.Template) error {
+	if secretNameTemplate == nil || r.Spec.SecretName != "" || r.Name == "" {
+		return nil
+	}
+	secretName, err := renderSecretName(secretNameTemplate, r)
+	if err != nil {
+		return err
+	}
+	if r.Spec.ServiceInstanceName == "" {
+		probe := r.DeepCopy()
+		probe.Spec.ServiceInstanceName = "instance"
+		probeSecretName, err := renderSecretName(secretNameTemplate, probe)
+		if err != nil {
+			return err
+		}
+		if probeSecretName != secretName {
+			r.Spec.SecretName = r.Name
+			return nil
+		}
+	}
+	if errs := validation.IsDNS1123Subdomain(secretName); len(errs) > 0 {
+		return fmt.Errorf("invalid binding secret name %q rendered from template: %s", secretName, strings.Join(errs, "; "))
+	}
+	r.Spec.SecretName = secretName
+	return nil
+}
+
+func renderSecretName(secretNameTemplate *template.Template, serviceBinding *ServiceBinding) (string, error) {
+	var secretName strings.Builder
+	if err := secretNameTemplate.Execute(&secretName, serviceBinding); err != nil {
+		return "", fmt.Errorf("failed to render binding secret name template: %w", err)
+	}
+	return secretName.String(), nil
+}
+
+// serviceBindingDefaulter extends the defaulting implemented by ServiceBinding by deriving the secret name from a template.
+type serviceBindingDefaulter struct {
+	secretNameTemplate *template.Template
+}
+
+var _ admission.CustomDefaulter = &serviceBindingDefaulter{}
+
+func (d *serviceBindingDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	r := obj.(*ServiceBinding)
+	if err := r.DefaultSecretName(d.secretNameTemplate); err != nil {
+		return err
+	}
+	r.Default()
+	return nil
 }
 
 // +kubebuilder:webhook:path=/validate-cf-cs-sap-com-v1alpha1-servicebinding,mutating=false,failurePolicy=fail,sideEffects=None,groups=cf.cs.sap.com,resources=servicebindings,verbs=create;update,versions=v1alpha1,name=vservicebinding.kb.io,admissionReviewVersions=v1
@@ -53,7 +127,31 @@ var _ webhook.Validator = &ServiceBinding{}
 func (r *ServiceBinding) ValidateCreate() (admission.Warnings, error) {
 	servicebindinglog.V(2).Info("Validate create", "name", r.Name)
 
-	return nil, nil
+	if err := r.validateServiceInstanceSpec(); err != nil {
+		return nil, err
+	}
+
+	if err := r.validateBindingType(); err != nil {
+		return nil, err
+	}
+
+	if err := r.validateSecretSpec(); err != nil {
+		return nil, err
+	}
+
+	if err := validateParametersFrom(r.Spec.ParametersFrom, "spec.parametersFrom"); err != nil {
+		return nil, err
+	}
+
+	if err := validateCFMetadata(r.Spec.Metadata, "spec.metadata"); err != nil {
+		return nil, err
+	}
+
+	if err := validateAdoptLabelSelector(r.Annotations); err != nil {
+		return nil, err
+	}
+
+	return r.validationWarnings(), nil
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -66,14 +164,155 @@ func (r *ServiceBinding) ValidateUpdate(old runtime.Object) (admission.Warnings,
 
 	// TODO: why not to allow name updates ?
 	if r.Spec.Name != s.Spec.Name {
-		return nil, fmt.Errorf("spec.name is immutable")
+		return nil, immutableFieldError("spec.name", s.Spec.Name, r.Spec.Name)
 	}
 
 	if r.Spec.ServiceInstanceName != s.Spec.ServiceInstanceName {
-		return nil, fmt.Errorf("spec.serviceInstanceName is immutable")
+		return nil, immutableFieldError("spec.serviceInstanceName", s.Spec.ServiceInstanceName, r.Spec.ServiceInstanceName)
 	}
 
-	return nil, nil
+	if r.Spec.ServiceInstanceGuid != s.Spec.ServiceInstanceGuid {
+		return nil, immutableFieldError("spec.serviceInstanceGuid", s.Spec.ServiceInstanceGuid, r.Spec.ServiceInstanceGuid)
+	}
+
+	if r.Spec.SpaceName != s.Spec.SpaceName {
+		return nil, immutableFieldError("spec.spaceName", s.Spec.SpaceName, r.Spec.SpaceName)
+	}
+
+	if r.Spec.ClusterSpaceName != s.Spec.ClusterSpaceName {
+		return nil, immutableFieldError("spec.clusterSpaceName", s.Spec.ClusterSpaceName, r.Spec.ClusterSpaceName)
+	}
+
+	if r.Spec.AppGuid != s.Spec.AppGuid {
+		return nil, immutableFieldError("spec.appGuid", s.Spec.AppGuid, r.Spec.AppGuid)
+	}
+
+	if r.Spec.AppName != s.Spec.AppName {
+		return nil, immutableFieldError("spec.appName", s.Spec.AppName, r.Spec.AppName)
+	}
+
+	if r.Spec.Type != s.Spec.Type {
+		return nil, immutableFieldError("spec.type", string(s.Spec.Type), string(r.Spec.Type))
+	}
+
+	if r.Spec.SecretKey != s.Spec.SecretKey {
+		return nil, immutableFieldError("spec.secretKey", s.Spec.SecretKey, r.Spec.SecretKey)
+	}
+
+	if !reflect.DeepEqual(r.Spec.SecretStoreRef, s.Spec.SecretStoreRef) {
+		return nil, fmt.Errorf("spec.secretStoreRef is immutable")
+	}
+
+	if err := r.validateSecretSpec(); err != nil {
+		return nil, err
+	}
+
+	if err := validateParametersFrom(r.Spec.ParametersFrom, "spec.parametersFrom"); err != nil {
+		return nil, err
+	}
+
+	if err := validateCFMetadata(r.Spec.Metadata, "spec.metadata"); err != nil {
+		return nil, err
+	}
+
+	if err := validateAdoptLabelSelector(r.Annotations); err != nil {
+		return nil, err
+	}
+
+	return updateWarnings(r.validationWarnings(), s.validationWarnings()), nil
+}
+
+// validateBindingType checks that the application of app bindings is specified (by exactly one of spec.appGuid and spec.appName),
+// and that no application is specified for service keys.
+func (r *ServiceBinding) validateBindingType() error {
+	if r.Spec.AppGuid != "" && r.Spec.AppName != "" {
+		return fmt.Errorf("at most one of spec.appGuid or spec.appName must be specified")
+	}
+	switch r.Spec.Type {
+	case ServiceBindingTypeApp:
+		if r.Spec.AppGuid == "" && r.Spec.AppName == "" {
+			return fmt.Errorf("one of spec.appGuid or spec.appName must be specified if spec.type is %s", ServiceBindingTypeApp)
+		}
+	case ServiceBindingTypeKey:
+		if r.Spec.AppGuid != "" || r.Spec.AppName != "" {
+			return fmt.Errorf("spec.appGuid and spec.appName must not be specified if spec.type is %s", ServiceBindingTypeKey)
+		}
+	default:
+		return fmt.Errorf("invalid value for spec.type: %s (must be one of %s, %s)", r.Spec.Type, ServiceBindingTypeKey, ServiceBindingTypeApp)
+	}
+	return nil
+}
+
+// validationWarnings returns warnings about risky (but valid) settings of the service binding.
+func (r *ServiceBinding) validationWarnings() admission.Warnings {
+	warnings := parametersWarnings(r.Spec.ParametersMergeStrategy, r.Spec.ParametersFrom, "spec.parametersFrom")
+	warnings = append(warnings, adoptionWarnings(r, r.Spec.Name)...)
+	return append(warnings, annotationWarnings(r.Annotations)...)
+}
+
+// validateServiceInstanceSpec checks that the service instance is referenced either by a ServiceInstance object,
+// or by the guid of an unmanaged Cloud Foundry instance (together with its space).
+func (r *ServiceBinding) validateServiceInstanceSpec() error {
+	if (r.Spec.ServiceInstanceName == "") == (r.Spec.ServiceInstanceGuid == "") {
+		return fmt.Errorf("exactly one of spec.serviceInstanceName or spec.serviceInstanceGuid must be specified")
+	}
+	if r.Spec.ServiceInstanceGuid == "" {
+		if r.Spec.SpaceName != "" || r.Spec.ClusterSpaceName != "" {
+			return fmt.Errorf("spec.spaceName and spec.clusterSpaceName must only be specified together with spec.serviceInstanceGuid")
+		}
+		return nil
+	}
+	if (r.Spec.SpaceName == "") == (r.Spec.ClusterSpaceName == "") {
+		return fmt.Errorf("exactly one of spec.spaceName or spec.clusterSpaceName must be specified together with spec.serviceInstanceGuid")
+	}
+	return nil
+}
+
+// ValidateSecretStorePath checks that the given path of a secret store is relative, and does not leave its base (that is,
+// the path does not start with a slash, and contains no empty, . or .. segments).
+func ValidateSecretStorePath(path string) error {
+	if strings.HasPrefix(path, "/") {
+		return fmt.Errorf("path %s must be relative", path)
+	}
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("path %s must not contain empty, . or .. segments", path)
+		}
+	}
+	return nil
+}
+
+// validateSecretSpec checks that the settings of the binding secret (resp. the secret store) fit together.
+func (r *ServiceBinding) validateSecretSpec() error {
+	if r.Spec.SecretStoreRef != nil && r.Spec.SecretNamespace != "" {
+		return fmt.Errorf("at most one of spec.secretStoreRef or spec.secretNamespace must be specified")
+	}
+	if r.Spec.SecretStoreRef != nil && r.Spec.SecretImmutable {
+		return fmt.Errorf("spec.secretImmutable must not be specified together with spec.secretStoreRef")
+	}
+	if r.Spec.SecretStoreRef != nil && r.Spec.SecretStoreRef.Type == SecretStoreTypeVault && r.Spec.SecretType != "" {
+		return fmt.Errorf("spec.secretType must not be specified together with spec.secretStoreRef of type %s", SecretStoreTypeVault)
+	}
+	if r.Spec.SecretStoreRef != nil && r.Spec.SecretStoreRef.Path != "" {
+		if err := ValidateSecretStorePath(r.Spec.SecretStoreRef.Path); err != nil {
+			return fmt.Errorf("invalid spec.secretStoreRef.path: %w", err)
+		}
+	}
+	if len(r.Spec.Secrets) > 0 {
+		if r.Spec.SecretStoreRef != nil || r.Spec.SecretImmutable {
+			return fmt.Errorf("spec.secrets must not be specified together with spec.secretStoreRef or spec.secretImmutable")
+		}
+		secretName := r.Spec.SecretName
+		if secretName == "" {
+			secretName = r.Name
+		}
+		for _, secret := range r.Spec.Secrets {
+			if secret.Name == secretName {
+				return fmt.Errorf("spec.secrets must not contain the binding secret %s", secretName)
+			}
+		}
+	}
+	return nil
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -0,0 +1,78 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package v1alpha1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Note: these tests check pure functions; they do not need the envtest based webhook suite, and therefore run standalone.
+
+func TestValidateSecretSpec(t *testing.T) {
+	g := NewWithT(t)
+
+	newServiceBinding := func(spec ServiceBindingSpec) *ServiceBinding {
+		return &ServiceBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "binding"}, Spec: spec}
+	}
+	vault := func(path string) *SecretStoreReference {
+		return &SecretStoreReference{Type: SecretStoreTypeVault, Name: "vault", Path: path}
+	}
+	additionalSecrets := func(names ...string) []BindingSecret {
+		var secrets []BindingSecret
+		for _, name := range names {
+			secrets = append(secrets, BindingSecret{Name: name, Keys: []BindingSecretKey{{Key: "url"}}})
+		}
+		return secrets
+	}
+
+	g.Expect(newServiceBinding(ServiceBindingSpec{}).validateSecretSpec()).To(Succeed())
+	g.Expect(newServiceBinding(ServiceBindingSpec{SecretStoreRef: vault("app/db")}).validateSecretSpec()).To(Succeed())
+	g.Expect(newServiceBinding(ServiceBindingSpec{Secrets: additionalSecrets("tls")}).validateSecretSpec()).To(Succeed())
+
+	g.Expect(newServiceBinding(ServiceBindingSpec{SecretStoreRef: vault(""), SecretNamespace: "other"}).validateSecretSpec()).
+		To(MatchError(ContainSubstring("at most one of spec.secretStoreRef or spec.secretNamespace")))
+	g.Expect(newServiceBinding(ServiceBindingSpec{SecretStoreRef: vault(""), SecretImmutable: true}).validateSecretSpec()).
+		To(MatchError(ContainSubstring("spec.secretImmutable must not be specified together with spec.secretStoreRef")))
+	g.Expect(newServiceBinding(ServiceBindingSpec{SecretStoreRef: vault(""), SecretType: "kubernetes.io/tls"}).validateSecretSpec()).
+		To(MatchError(ContainSubstring("spec.secretType must not be specified")))
+	g.Expect(newServiceBinding(ServiceBindingSpec{SecretStoreRef: vault(""), Secrets: additionalSecrets("tls")}).validateSecretSpec()).
+		To(MatchError(ContainSubstring("spec.secrets must not be specified together with spec.secretStoreRef")))
+
+	// the binding secret defaults to the name of the binding
+	g.Expect(newServiceBinding(ServiceBindingSpec{Secrets: additionalSecrets("tls", "binding")}).validateSecretSpec()).
+		To(MatchError("spec.secrets must not contain the binding secret binding"))
+	g.Expect(newServiceBinding(ServiceBindingSpec{SecretName: "creds", Secrets: additionalSecrets("binding")}).validateSecretSpec()).To(Succeed())
+	g.Expect(newServiceBinding(ServiceBindingSpec{SecretName: "creds", Secrets: additionalSecrets("creds")}).validateSecretSpec()).
+		To(MatchError("spec.secrets must not contain the binding secret creds"))
+
+	for _, path := range []string{"/app/db", "app//db", "app/", "./db", "app/../../other-ns"} {
+		g.Expect(newServiceBinding(ServiceBindingSpec{SecretStoreRef: vault(path)}).validateSecretSpec()).
+			To(MatchError(ContainSubstring("invalid spec.secretStoreRef.path")), path)
+	}
+}
+
+func TestValidateBindingType(t *testing.T) {
+	g := NewWithT(t)
+
+	newServiceBinding := func(spec ServiceBindingSpec) *ServiceBinding {
+		return &ServiceBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "binding"}, Spec: spec}
+	}
+
+	g.Expect(newServiceBinding(ServiceBindingSpec{Type: ServiceBindingTypeKey}).validateBindingType()).To(Succeed())
+	g.Expect(newServiceBinding(ServiceBindingSpec{Type: ServiceBindingTypeApp, AppGuid: "app-guid"}).validateBindingType()).To(Succeed())
+	g.Expect(newServiceBinding(ServiceBindingSpec{Type: ServiceBindingTypeApp, AppName: "my-app"}).validateBindingType()).To(Succeed())
+
+	g.Expect(newServiceBinding(ServiceBindingSpec{Type: ServiceBindingTypeApp, AppGuid: "app-guid", AppName: "my-app"}).validateBindingType()).
+		To(MatchError(ContainSubstring("at most one of spec.appGuid or spec.appName")))
+	g.Expect(newServiceBinding(ServiceBindingSpec{Type: ServiceBindingTypeApp}).validateBindingType()).
+		To(MatchError(ContainSubstring("one of spec.appGuid or spec.appName must be specified")))
+	g.Expect(newServiceBinding(ServiceBindingSpec{Type: ServiceBindingTypeKey, AppName: "my-app"}).validateBindingType()).
+		To(MatchError(ContainSubstring("must not be specified if spec.type is key")))
+	g.Expect(newServiceBinding(ServiceBindingSpec{Type: "other"}).validateBindingType()).
+		To(MatchError(ContainSubstring("invalid value for spec.type")))
+}
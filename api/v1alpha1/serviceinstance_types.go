@@ -75,27 +75,98 @@ type ServiceInstanceSpec struct {
 	// +kubebuilder:validation:MinLength=1
 	ServicePlanGuid string `json:"servicePlanGuid,omitempty"`
 
+	// Whether the service plan (ServicePlanName or ServicePlanGuid) may be changed after creation; plan changes are passed to
+	// the service broker, which may not support them, or perform destructive migrations, so they must be allowed explicitly.
+	// If false (the default), plan changes are rejected, resp. not applied (and reported by the PlanChangeBlocked condition).
+	// +optional
+	AllowPlanChange bool `json:"allowPlanChange,omitempty"`
+
 	// Instance parameters.
 	// Do not provide any sensitve data here; instead use ParametersFrom for such data.
 	// +optional
 	Parameters *apiextensionsv1.JSON `json:"parameters,omitempty"`
 
-	// References to secrets containing instance parameters.
-	// Top level keys must occur only once across Parameters and the secrest listed here.
+	// References to secrets, config maps or fields of this object containing instance parameters.
+	// Top level keys must occur only once across Parameters and the sources listed here, unless another ParametersMergeStrategy is specified.
 	// +optional
 	ParametersFrom []ParametersFromSource `json:"parametersFrom,omitempty"`
 
+	// How Parameters and the sources listed in ParametersFrom are merged (ordered by their priority); defaults to Error.
+	// +optional
+	ParametersMergeStrategy ParametersMergeStrategy `json:"parametersMergeStrategy,omitempty"`
+
 	// Tags to be attached to the instance.
 	// +optional
 	Tags []string `json:"tags,omitempty"`
+
+	// Labels and annotations to be set on the Cloud Foundry instance (in addition to the ones maintained by the operator).
+	// +optional
+	Metadata *CFMetadata `json:"metadata,omitempty"`
+
+	// Controls whether maintenance upgrades offered by the service broker (see status.availableUpgrade) are applied automatically;
+	// if Manual (or unspecified), an upgrade is only applied once requested through the upgrade-to-version annotation.
+	// +optional
+	// +kubebuilder:validation:Enum=Manual;Auto
+	UpgradePolicy UpgradePolicy `json:"upgradePolicy,omitempty"`
+
+	// Job which must complete (e.g. taking a data export) before the Cloud Foundry instance is deleted;
+	// the job is created (in the namespace of the service instance) once the service instance is being deleted,
+	// and is no longer blocked by depending bindings or foreign finalizers.
+	// +optional
+	PreDeleteHook *PreDeleteHook `json:"preDeleteHook,omitempty"`
+}
+
+// UpgradePolicy controls whether maintenance upgrades of a service instance are applied automatically.
+type UpgradePolicy string
+
+const (
+	// UpgradePolicyManual means that maintenance upgrades are only applied if requested through annotation.
+	UpgradePolicyManual UpgradePolicy = "Manual"
+	// UpgradePolicyAuto means that maintenance upgrades are applied as soon as they are offered.
+	UpgradePolicyAuto UpgradePolicy = "Auto"
+)
+
+// PreDeleteHook describes a job which must complete before the Cloud Foundry instance is deleted.
+type PreDeleteHook struct {
+	// Name of a job in the namespace of the service instance, which is resumed (that is, spec.suspend is set to false)
+	// when the Cloud Foundry instance is about to be deleted; the job should therefore be created with spec.suspend set to true.
+	// The job is created (and owned) by the user, such that it runs with the permissions of its creator (not the operator's)
+	// +kubebuilder:validation:MinLength=1
+	JobName string `json:"jobName"`
+
+	// Time after which an uncompleted job is considered failed; defaults to 1h
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Controls what happens if the job fails (or times out): if Abort (or unspecified), the deletion stays blocked
+	// (until spec.preDeleteHook is removed); if Ignore, the Cloud Foundry instance is deleted anyway
+	// +optional
+	// +kubebuilder:validation:Enum=Abort;Ignore
+	FailurePolicy PreDeleteHookFailurePolicy `json:"failurePolicy,omitempty"`
 }
 
+// PreDeleteHookFailurePolicy controls how a failed pre-delete hook is handled.
+type PreDeleteHookFailurePolicy string
+
+const (
+	// PreDeleteHookFailurePolicyAbort means that the deletion of the Cloud Foundry instance stays blocked if the pre-delete hook fails.
+	PreDeleteHookFailurePolicyAbort PreDeleteHookFailurePolicy = "Abort"
+	// PreDeleteHookFailurePolicyIgnore means that the Cloud Foundry instance is deleted even if the pre-delete hook fails.
+	PreDeleteHookFailurePolicyIgnore PreDeleteHookFailurePolicy = "Ignore"
+)
+
 // ServiceInstanceStatus defines the observed state of ServiceInstance
 type ServiceInstanceStatus struct {
-	// Observed generation
+	// Observed generation; this is the last generation which was successfully applied (that is, for which
+	// the Ready condition became True), so it lags behind metadata.generation while changes are in progress
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
+	// Whether the object is ready, that is, whether the Ready condition is True; note that this refers
+	// to status.observedGeneration, which must therefore be compared with metadata.generation
+	// +optional
+	Ready bool `json:"ready"`
+
 	// Last reconciliation timestamp
 	// +optional
 	LastReconciledAt *metav1.Time `json:"lastReconciledAt,omitempty"`
@@ -130,9 +201,43 @@ type ServiceInstanceStatus struct {
 	// +optional
 	MaxRetries int `json:"maxRetries,omitempty"`
 
+	// Error returned by the Cloud Foundry API which failed the most recent reconciliation
+	// (empty if the most recent reconciliation succeeded, or failed for other reasons)
+	// +optional
+	LastCFError *CFError `json:"lastCFError,omitempty"`
+
+	// URL of the Cloud Foundry API endpoint used by the most recent reconciliation
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Last operation performed by Cloud Foundry on the service instance (as of the most recent reconciliation);
+	// the timestamps allow to tell for how long an operation has been running
+	// +optional
+	LastOperation *LastOperation `json:"lastOperation,omitempty"`
+
+	// Maintenance upgrade offered by the service broker for the instance's service plan (if any)
+	// +optional
+	AvailableUpgrade *MaintenanceInfo `json:"availableUpgrade,omitempty"`
+
+	// Changes which would be applied to the Cloud Foundry instance; only maintained while the service instance
+	// is reconciled in dry-run mode (annotation service-operator.cf.cs.sap.com/dry-run)
+	// +optional
+	PendingChanges *PendingChanges `json:"pendingChanges,omitempty"`
+
+	// Most recent changes of the parameters applied to the Cloud Foundry instance (oldest first, bounded);
+	// parameters are only recorded by hash, so that changes can be audited without exposing their content
+	// +optional
+	ParameterHistory []ParameterChange `json:"parameterHistory,omitempty"`
+
+	// State of the pre-delete hook job (only present once the job was started)
+	// +optional
+	PreDeleteHook *PreDeleteHookStatus `json:"preDeleteHook,omitempty"`
+
 	// List of status conditions to indicate the status of a ServiceInstance.
-	// Known condition types are `Ready`.
+	// Known condition types are `Ready`, `Synced`, `DeletionBlocked`, `CFReachable`, `Stalled`, `Paused`, `Timeout`, `PlanChangeBlocked`, `ParameterDrift`.
 	// +optional
+	// +listType=map
+	// +listMapKey=type
 	Conditions []ServiceInstanceCondition `json:"conditions,omitempty"`
 
 	// Readable form of the state.
@@ -140,9 +245,99 @@ type ServiceInstanceStatus struct {
 	State ServiceInstanceState `json:"state,omitempty"`
 }
 
+// ParameterChange records parameters applied to a Cloud Foundry instance.
+type ParameterChange struct {
+	// Generation of the ServiceInstance with which the parameters were applied
+	Generation int64 `json:"generation"`
+
+	// Hash of the applied parameters (matching the parameter-hash annotation of the Cloud Foundry instance)
+	ParameterHash string `json:"parameterHash"`
+
+	// Who changed the ServiceInstance; taken from the annotation service-operator.cf.cs.sap.com/changed-by if present,
+	// otherwise the field manager which last changed the spec
+	// +optional
+	ChangedBy string `json:"changedBy,omitempty"`
+
+	// Time when the parameters were applied
+	AppliedAt metav1.Time `json:"appliedAt"`
+}
+
+// PreDeleteHookStatus describes the state of the pre-delete hook job of a service instance.
+type PreDeleteHookStatus struct {
+	// Name of the job
+	JobName string `json:"jobName"`
+
+	// Phase of the job
+	Phase PreDeleteHookPhase `json:"phase"`
+
+	// Time when the job was resumed (or first seen running)
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// Time when the job succeeded, failed or timed out
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// Details about a failed job
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PreDeleteHookPhase is the phase of a pre-delete hook job.
+// +kubebuilder:validation:Enum=Running;Succeeded;Failed;TimedOut
+type PreDeleteHookPhase string
+
+const (
+	PreDeleteHookPhaseRunning   PreDeleteHookPhase = "Running"
+	PreDeleteHookPhaseSucceeded PreDeleteHookPhase = "Succeeded"
+	PreDeleteHookPhaseFailed    PreDeleteHookPhase = "Failed"
+	PreDeleteHookPhaseTimedOut  PreDeleteHookPhase = "TimedOut"
+)
+
+// PendingChanges describes the changes the operator would apply to a Cloud Foundry instance (dry-run mode).
+type PendingChanges struct {
+	// Operation which would be performed on the Cloud Foundry instance
+	Operation PendingOperation `json:"operation"`
+
+	// Attributes of the Cloud Foundry instance which would be changed by an update, out of
+	// ('name', 'servicePlan', 'parameters', 'tags', 'generation'); 'generation' means that the spec changed otherwise
+	// (e.g. the metadata, which is always re-applied with an update)
+	// +optional
+	Changes []string `json:"changes,omitempty"`
+
+	// Hash of the parameters which would be applied
+	// +optional
+	ParameterHash string `json:"parameterHash,omitempty"`
+
+	// Hash of the parameters last applied to the Cloud Foundry instance
+	// +optional
+	CurrentParameterHash string `json:"currentParameterHash,omitempty"`
+
+	// Maintenance version the Cloud Foundry instance would be upgraded to
+	// +optional
+	UpgradeToVersion string `json:"upgradeToVersion,omitempty"`
+
+	// Time when the changes were determined
+	// +optional
+	ComputedAt *metav1.Time `json:"computedAt,omitempty"`
+}
+
+// PendingOperation is an operation the operator would perform on a Cloud Foundry instance.
+// +kubebuilder:validation:Enum=None;Create;Update;Recreate;Upgrade;Delete
+type PendingOperation string
+
+const (
+	PendingOperationNone     PendingOperation = "None"
+	PendingOperationCreate   PendingOperation = "Create"
+	PendingOperationUpdate   PendingOperation = "Update"
+	PendingOperationRecreate PendingOperation = "Recreate"
+	PendingOperationUpgrade  PendingOperation = "Upgrade"
+	PendingOperationDelete   PendingOperation = "Delete"
+)
+
 // ServiceInstanceCondition contains condition information for a ServiceInstance.
 type ServiceInstanceCondition struct {
-	// Type of the condition, known values are ('Ready').
+	// Type of the condition, known values are ('Ready', 'Synced', 'DeletionBlocked', 'CFReachable').
 	Type ServiceInstanceConditionType `json:"type"`
 
 	// Status of the condition, one of ('True', 'False', 'Unknown').
@@ -162,6 +357,10 @@ type ServiceInstanceCondition struct {
 	// transition, complementing reason.
 	// +optional
 	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the metadata.generation of the object the condition was set for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // ServiceInstanceConditionType represents a ServiceInstance condition value.
@@ -170,6 +369,32 @@ type ServiceInstanceConditionType string
 const (
 	// ServiceInstanceConditionReady represents the fact that a given service is ready.
 	ServiceInstanceConditionReady ServiceInstanceConditionType = "Ready"
+	// ServiceInstanceConditionSynced represents the fact that the Cloud Foundry instance reflects the current spec;
+	// it is False if applying the spec failed, and Unknown while an operation is in progress.
+	ServiceInstanceConditionSynced ServiceInstanceConditionType = "Synced"
+	// ServiceInstanceConditionDeletionBlocked represents the fact that the deletion of the service instance
+	// is blocked (e.g. by depending service bindings, or a failed pre-delete hook); it is only present while the service instance is being deleted.
+	ServiceInstanceConditionDeletionBlocked ServiceInstanceConditionType = "DeletionBlocked"
+	// ServiceInstanceConditionCFReachable represents the fact that the Cloud Foundry API was reachable during the last reconciliation.
+	ServiceInstanceConditionCFReachable ServiceInstanceConditionType = "CFReachable"
+	// ServiceInstanceConditionPaused represents the fact that reconciliation is paused by the annotation service-operator.cf.cs.sap.com/paused;
+	// it is only present while the annotation is set.
+	ServiceInstanceConditionPaused ServiceInstanceConditionType = "Paused"
+	// ServiceInstanceConditionTimeout represents the fact that the last reconciliation did not complete within the maximum reconcile duration
+	// (see annotation service-operator.cf.cs.sap.com/reconcile-call-timeout); it is only present while this is the case.
+	ServiceInstanceConditionTimeout ServiceInstanceConditionType = "Timeout"
+	// ServiceInstanceConditionStalled represents the fact that an operation on the Cloud Foundry instance has been in progress
+	// for longer than the stalled operation timeout; it is only present while this is the case.
+	ServiceInstanceConditionStalled ServiceInstanceConditionType = "Stalled"
+	// ServiceInstanceConditionPlanChangeBlocked represents the fact that the service plan of the Cloud Foundry instance differs
+	// from the specified one, but the plan change is not applied because spec.allowPlanChange is false; it is only present while this is the case.
+	ServiceInstanceConditionPlanChangeBlocked ServiceInstanceConditionType = "PlanChangeBlocked"
+	// ServiceInstanceConditionParameterDrift represents the fact that the parameters reported by the service broker for the Cloud Foundry
+	// instance differ from the specified ones; it is only present while the annotation service-operator.cf.cs.sap.com/check-parameter-drift is set.
+	ServiceInstanceConditionParameterDrift ServiceInstanceConditionType = "ParameterDrift"
+	// ServiceInstanceConditionNameConflict represents the fact that the Cloud Foundry instance cannot be created, because another instance
+	// with the specified name, not owned by the service instance, exists in the space; it is only present while this is the case.
+	ServiceInstanceConditionNameConflict ServiceInstanceConditionType = "NameConflict"
 )
 
 // ServiceInstanceState represents a condition state in a readable form
@@ -199,6 +424,18 @@ func (serviceInstance *ServiceInstance) GetReadyCondition() *ServiceInstanceCond
 	return getServiceInstanceReadyCondition(serviceInstance)
 }
 
+func (serviceInstance *ServiceInstance) SetCondition(conditionType ServiceInstanceConditionType, conditionStatus ConditionStatus, reason, message string) {
+	setServiceInstanceCondition(serviceInstance, conditionType, conditionStatus, reason, message)
+}
+
+func (serviceInstance *ServiceInstance) GetCondition(conditionType ServiceInstanceConditionType) *ServiceInstanceCondition {
+	return getServiceInstanceCondition(serviceInstance, conditionType)
+}
+
+func (serviceInstance *ServiceInstance) RemoveCondition(conditionType ServiceInstanceConditionType) {
+	removeServiceInstanceCondition(serviceInstance, conditionType)
+}
+
 func (serviceInstance *ServiceInstance) IsReady() bool {
 	return isServiceInstanceReady(serviceInstance)
 }
@@ -10,31 +10,15 @@ import (
 )
 
 func setServiceInstanceReadyCondition(serviceInstance *ServiceInstance, conditionStatus ConditionStatus, reason, message string) {
-	status := &serviceInstance.Status
-	ready := getServiceInstanceReadyCondition(serviceInstance)
-	if ready == nil {
-		ready = &ServiceInstanceCondition{
-			Type: ServiceInstanceConditionReady,
-		}
-		status.Conditions = append(status.Conditions, *ready)
-	}
-	if ready.Status != conditionStatus {
-		ready.Status = conditionStatus
-		now := metav1.Now()
-		ready.LastTransitionTime = &now
-	}
-	ready.Reason = reason
-	ready.Message = message
-
-	for i, c := range status.Conditions {
-		if c.Type == ServiceInstanceConditionReady {
-			status.Conditions[i] = *ready
-			break
-		}
-	}
+	setServiceInstanceCondition(serviceInstance, ServiceInstanceConditionReady, conditionStatus, reason, message)
 
+	status := &serviceInstance.Status
+	// the observed generation is only bumped after a successful sync, such that health checks (e.g. of GitOps tools)
+	// do not consider the object ready while changes are still being applied
+	status.Ready = conditionStatus == ConditionTrue
 	switch conditionStatus {
 	case ConditionTrue:
+		status.ObservedGeneration = serviceInstance.Generation
 		status.State = ServiceInstanceStateReady
 	case ConditionFalse:
 		status.State = ServiceInstanceStateError
@@ -48,15 +32,56 @@ func setServiceInstanceReadyCondition(serviceInstance *ServiceInstance, conditio
 }
 
 func getServiceInstanceReadyCondition(serviceInstance *ServiceInstance) *ServiceInstanceCondition {
+	return getServiceInstanceCondition(serviceInstance, ServiceInstanceConditionReady)
+}
+
+// setServiceInstanceCondition adds or updates the condition of the given type; the transition time is only updated if the status changes.
+func setServiceInstanceCondition(serviceInstance *ServiceInstance, conditionType ServiceInstanceConditionType, conditionStatus ConditionStatus, reason, message string) {
+	status := &serviceInstance.Status
+	condition := getServiceInstanceCondition(serviceInstance, conditionType)
+	if condition == nil {
+		condition = &ServiceInstanceCondition{
+			Type: conditionType,
+		}
+		status.Conditions = append(status.Conditions, *condition)
+	}
+	if condition.Status != conditionStatus {
+		condition.Status = conditionStatus
+		now := metav1.Now()
+		condition.LastTransitionTime = &now
+	}
+	condition.Reason = reason
+	condition.Message = message
+	condition.ObservedGeneration = serviceInstance.GetGeneration()
+
+	for i, c := range status.Conditions {
+		if c.Type == conditionType {
+			status.Conditions[i] = *condition
+			break
+		}
+	}
+}
+
+func getServiceInstanceCondition(serviceInstance *ServiceInstance, conditionType ServiceInstanceConditionType) *ServiceInstanceCondition {
 	status := &serviceInstance.Status
 	for _, c := range status.Conditions {
-		if c.Type == ServiceInstanceConditionReady {
+		if c.Type == conditionType {
 			return &c
 		}
 	}
 	return nil
 }
 
+func removeServiceInstanceCondition(serviceInstance *ServiceInstance, conditionType ServiceInstanceConditionType) {
+	status := &serviceInstance.Status
+	for i, c := range status.Conditions {
+		if c.Type == conditionType {
+			status.Conditions = append(status.Conditions[:i], status.Conditions[i+1:]...)
+			return
+		}
+	}
+}
+
 func isServiceInstanceReady(serviceInstance *ServiceInstance) bool {
 	if serviceInstance.Status.ObservedGeneration != serviceInstance.Generation {
 		return false
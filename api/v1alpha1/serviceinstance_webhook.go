@@ -6,6 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package v1alpha1
 
 import (
+	"context"
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -18,10 +19,34 @@ import (
 // log is for logging in this package.
 var serviceinstancelog = logf.Log.WithName("serviceinstance-resource")
 
-func (r *ServiceInstance) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
-		For(r).
-		Complete()
+// ServicePlanChecker checks whether the service plan referenced by a service instance exists in Cloud Foundry.
+// An error is returned if the plan does not exist; if the check cannot be performed, warnings are returned instead.
+// +kubebuilder:object:generate=false
+type ServicePlanChecker interface {
+	CheckServicePlan(ctx context.Context, serviceInstance *ServiceInstance) (admission.Warnings, error)
+}
+
+// DefaultSpaceResolver determines the space of service instances which specify neither spec.spaceName nor spec.clusterSpaceName.
+// +kubebuilder:object:generate=false
+type DefaultSpaceResolver interface {
+	// ResolveDefaultSpace returns the name of the default Space, or the name of the default ClusterSpace, of the given namespace;
+	// both are empty if the namespace does not define a default space.
+	ResolveDefaultSpace(ctx context.Context, namespace string) (spaceName string, clusterSpaceName string, err error)
+}
+
+// SetupWebhookWithManager registers the webhooks for ServiceInstance; if planChecker is not nil,
+// created service instances are additionally validated against the Cloud Foundry service catalog;
+// if spaceResolver is not nil, service instances without space reference are defaulted to the default space of their namespace.
+func (r *ServiceInstance) SetupWebhookWithManager(mgr ctrl.Manager, planChecker ServicePlanChecker, spaceResolver DefaultSpaceResolver) error {
+	builder := ctrl.NewWebhookManagedBy(mgr).
+		For(r)
+	if planChecker != nil {
+		builder = builder.WithValidator(&serviceInstanceValidator{planChecker: planChecker})
+	}
+	if spaceResolver != nil {
+		builder = builder.WithDefaulter(&serviceInstanceDefaulter{spaceResolver: spaceResolver})
+	}
+	return builder.Complete()
 }
 
 // +kubebuilder:webhook:path=/mutate-cf-cs-sap-com-v1alpha1-serviceinstance,mutating=true,failurePolicy=fail,sideEffects=None,groups=cf.cs.sap.com,resources=serviceinstances,verbs=create;update,versions=v1alpha1,name=mserviceinstance.kb.io,admissionReviewVersions=v1
@@ -57,7 +82,7 @@ func (r *ServiceInstance) ValidateCreate() (admission.Warnings, error) {
 
 	if !(r.Spec.SpaceName != "" && r.Spec.ClusterSpaceName == "" ||
 		r.Spec.SpaceName == "" && r.Spec.ClusterSpaceName != "") {
-		return nil, fmt.Errorf("exactly one of spec.spaceName or spec.clusterSpaceName must be specified")
+		return nil, fmt.Errorf("exactly one of spec.spaceName or spec.clusterSpaceName must be specified (unless the namespace defines a default space through annotation %s or %s)", AnnotationDefaultSpace, AnnotationDefaultClusterSpace)
 	}
 
 	if !(r.Spec.ServiceOfferingName != "" && r.Spec.ServicePlanName != "" && r.Spec.ServicePlanGuid == "" ||
@@ -65,7 +90,23 @@ func (r *ServiceInstance) ValidateCreate() (admission.Warnings, error) {
 		return nil, fmt.Errorf("exactly one of spec.serviceOfferingName plus spec.servicePlanName or spec.servicePlanGuid must be specified")
 	}
 
-	return nil, nil
+	if err := validateParametersFrom(r.Spec.ParametersFrom, "spec.parametersFrom"); err != nil {
+		return nil, err
+	}
+
+	if err := validateCFMetadata(r.Spec.Metadata, "spec.metadata"); err != nil {
+		return nil, err
+	}
+
+	if err := validateAdoptLabelSelector(r.Annotations); err != nil {
+		return nil, err
+	}
+
+	if err := validatePreDeleteHook(r.Spec.PreDeleteHook, "spec.preDeleteHook"); err != nil {
+		return nil, err
+	}
+
+	return r.validationWarnings(), nil
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -78,30 +119,79 @@ func (r *ServiceInstance) ValidateUpdate(old runtime.Object) (admission.Warnings
 
 	// TODO: why not to allow name updates ?
 	if r.Spec.Name != s.Spec.Name {
-		return nil, fmt.Errorf("spec.name is immutable")
+		return nil, immutableFieldError("spec.name", s.Spec.Name, r.Spec.Name)
 	}
 
 	if r.Spec.ClusterSpaceName != s.Spec.ClusterSpaceName {
-		return nil, fmt.Errorf("spec.clusterSpaceName is immutable")
+		return nil, immutableFieldError("spec.clusterSpaceName", s.Spec.ClusterSpaceName, r.Spec.ClusterSpaceName)
 	}
 
 	if r.Spec.SpaceName != s.Spec.SpaceName {
-		return nil, fmt.Errorf("spec.spaceName is immutable")
+		return nil, immutableFieldError("spec.spaceName", s.Spec.SpaceName, r.Spec.SpaceName)
 	}
 
 	if r.Spec.ServiceOfferingName != s.Spec.ServiceOfferingName {
-		return nil, fmt.Errorf("spec.serviceOfferingName is immutable")
+		return nil, immutableFieldError("spec.serviceOfferingName", s.Spec.ServiceOfferingName, r.Spec.ServiceOfferingName)
 	}
 
-	if r.Spec.ServicePlanName != s.Spec.ServicePlanName {
-		return nil, fmt.Errorf("spec.servicePlanName is immutable")
+	// the plan may only be changed if explicitly allowed (as part of the same update, or before)
+	if r.Spec.ServicePlanName != s.Spec.ServicePlanName && !r.Spec.AllowPlanChange {
+		return nil, fmt.Errorf("%w, unless spec.allowPlanChange is true", immutableFieldError("spec.servicePlanName", s.Spec.ServicePlanName, r.Spec.ServicePlanName))
 	}
 
-	if r.Spec.ServicePlanGuid != s.Spec.ServicePlanGuid {
-		return nil, fmt.Errorf("spec.servicePlanGuid is immutable")
+	if r.Spec.ServicePlanGuid != s.Spec.ServicePlanGuid && !r.Spec.AllowPlanChange {
+		return nil, fmt.Errorf("%w, unless spec.allowPlanChange is true", immutableFieldError("spec.servicePlanGuid", s.Spec.ServicePlanGuid, r.Spec.ServicePlanGuid))
 	}
 
-	return nil, nil
+	if err := validateParametersFrom(r.Spec.ParametersFrom, "spec.parametersFrom"); err != nil {
+		return nil, err
+	}
+
+	if err := validateCFMetadata(r.Spec.Metadata, "spec.metadata"); err != nil {
+		return nil, err
+	}
+
+	if err := validateAdoptLabelSelector(r.Annotations); err != nil {
+		return nil, err
+	}
+
+	if err := validatePreDeleteHook(r.Spec.PreDeleteHook, "spec.preDeleteHook"); err != nil {
+		return nil, err
+	}
+
+	return updateWarnings(r.validationWarnings(), s.validationWarnings()), nil
+}
+
+// validationWarnings returns warnings about risky (but valid) settings of the service instance.
+func (r *ServiceInstance) validationWarnings() admission.Warnings {
+	var warnings admission.Warnings
+	if r.Spec.ServicePlanGuid != "" {
+		warnings = append(warnings, "spec.servicePlanGuid is specific to a Cloud Foundry landscape; consider specifying spec.serviceOfferingName and spec.servicePlanName instead")
+	}
+	if len(r.Spec.Tags) == 0 {
+		warnings = append(warnings, "spec.tags is empty; applications looking up the service instance by tag will not find it")
+	}
+	if value, ok := r.Annotations[AnnotationDeletionProtection]; ok && value != DeletionProtectionEnabled {
+		warnings = append(warnings, fmt.Sprintf("annotation %s has no effect unless set to '%s'", AnnotationDeletionProtection, DeletionProtectionEnabled))
+	}
+	warnings = append(warnings, parametersWarnings(r.Spec.ParametersMergeStrategy, r.Spec.ParametersFrom, "spec.parametersFrom")...)
+	warnings = append(warnings, adoptionWarnings(r, r.Spec.Name)...)
+	warnings = append(warnings, annotationWarnings(r.Annotations)...)
+	return warnings
+}
+
+// validatePreDeleteHook checks that the given pre-delete hook (if any) references a job, and has a positive timeout.
+func validatePreDeleteHook(hook *PreDeleteHook, path string) error {
+	if hook == nil {
+		return nil
+	}
+	if hook.JobName == "" {
+		return fmt.Errorf("%s.jobName must not be empty", path)
+	}
+	if hook.Timeout != nil && hook.Timeout.Duration <= 0 {
+		return fmt.Errorf("%s.timeout must be positive", path)
+	}
+	return nil
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -110,3 +200,49 @@ func (r *ServiceInstance) ValidateDelete() (admission.Warnings, error) {
 
 	return nil, nil
 }
+
+// serviceInstanceValidator extends the validation implemented by ServiceInstance by a check against the Cloud Foundry service catalog.
+type serviceInstanceValidator struct {
+	planChecker ServicePlanChecker
+}
+
+var _ admission.CustomValidator = &serviceInstanceValidator{}
+
+func (v *serviceInstanceValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r := obj.(*ServiceInstance)
+	warnings, err := r.ValidateCreate()
+	if err != nil {
+		return warnings, err
+	}
+	checkWarnings, err := v.planChecker.CheckServicePlan(ctx, r)
+	return append(warnings, checkWarnings...), err
+}
+
+func (v *serviceInstanceValidator) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	return newObj.(*ServiceInstance).ValidateUpdate(oldObj)
+}
+
+func (v *serviceInstanceValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return obj.(*ServiceInstance).ValidateDelete()
+}
+
+// serviceInstanceDefaulter extends the defaulting implemented by ServiceInstance by applying the default space of the namespace.
+type serviceInstanceDefaulter struct {
+	spaceResolver DefaultSpaceResolver
+}
+
+var _ admission.CustomDefaulter = &serviceInstanceDefaulter{}
+
+func (d *serviceInstanceDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	r := obj.(*ServiceInstance)
+	if r.Spec.SpaceName == "" && r.Spec.ClusterSpaceName == "" {
+		spaceName, clusterSpaceName, err := d.spaceResolver.ResolveDefaultSpace(ctx, r.Namespace)
+		if err != nil {
+			return err
+		}
+		r.Spec.SpaceName = spaceName
+		r.Spec.ClusterSpaceName = clusterSpaceName
+	}
+	r.Default()
+	return nil
+}
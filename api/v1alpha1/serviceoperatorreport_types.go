@@ -0,0 +1,146 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ServiceOperatorReportName is the name of the ServiceOperatorReport object maintained by the operator.
+const ServiceOperatorReportName = "cf-service-operator"
+
+// ServiceOperatorReportStatus defines the observed state of ServiceOperatorReport
+type ServiceOperatorReportStatus struct {
+	// Timestamp of the last refresh of the report
+	// +optional
+	LastRefreshedAt *metav1.Time `json:"lastRefreshedAt,omitempty"`
+
+	// Cloud Foundry API endpoints used by the managed spaces
+	// +optional
+	Foundations []FoundationReport `json:"foundations,omitempty"`
+
+	// Counts of the managed spaces (Space and ClusterSpace objects)
+	// +optional
+	Spaces ResourceCounts `json:"spaces,omitempty"`
+
+	// Counts of the managed service instances
+	// +optional
+	ServiceInstances ResourceCounts `json:"serviceInstances,omitempty"`
+
+	// Counts of the managed service bindings
+	// +optional
+	ServiceBindings ResourceCounts `json:"serviceBindings,omitempty"`
+
+	// Most recent failures of managed objects (objects whose Ready condition is False), newest first
+	// +optional
+	RecentFailures []ResourceFailure `json:"recentFailures,omitempty"`
+
+	// Statistics of the Cloud Foundry resource cache
+	// +optional
+	Cache *CacheStatistics `json:"cache,omitempty"`
+}
+
+// FoundationReport summarizes the spaces managed on one Cloud Foundry API endpoint.
+type FoundationReport struct {
+	// Cloud Foundry API URL
+	URL string `json:"url"`
+
+	// Number of spaces (Space and ClusterSpace objects) using this endpoint
+	Spaces int `json:"spaces"`
+}
+
+// ResourceCounts holds the number of objects of one kind, in total and by state.
+type ResourceCounts struct {
+	// Total number of objects
+	Total int `json:"total"`
+
+	// Number of objects by state (an empty state is reported as Unknown)
+	// +optional
+	ByState map[string]int `json:"byState,omitempty"`
+}
+
+// ResourceFailure describes a managed object which is not ready.
+type ResourceFailure struct {
+	// Kind of the object
+	Kind string `json:"kind"`
+
+	// Namespace of the object (empty for cluster-scoped objects)
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the object
+	Name string `json:"name"`
+
+	// Reason of the Ready condition
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message of the Ready condition
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Last transition time of the Ready condition
+	// +optional
+	Time *metav1.Time `json:"time,omitempty"`
+}
+
+// CacheStatistics summarizes the Cloud Foundry resource cache.
+type CacheStatistics struct {
+	// Whether the resource cache is enabled
+	Enabled bool `json:"enabled"`
+
+	// Number of cached spaces
+	// +optional
+	Spaces int `json:"spaces,omitempty"`
+
+	// Number of cached service instances
+	// +optional
+	ServiceInstances int `json:"serviceInstances,omitempty"`
+
+	// Number of cached service bindings
+	// +optional
+	ServiceBindings int `json:"serviceBindings,omitempty"`
+
+	// Number of cache lookups which found a cached resource (since operator start)
+	// +optional
+	Hits int64 `json:"hits,omitempty"`
+
+	// Number of cache lookups which did not find a cached resource (since operator start)
+	// +optional
+	Misses int64 `json:"misses,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Spaces",type=integer,JSONPath=`.status.spaces.total`
+// +kubebuilder:printcolumn:name="Instances",type=integer,JSONPath=`.status.serviceInstances.total`
+// +kubebuilder:printcolumn:name="Bindings",type=integer,JSONPath=`.status.serviceBindings.total`
+// +kubebuilder:printcolumn:name="Refreshed",type="date",JSONPath=".status.lastRefreshedAt"
+// +genclient
+// +genclient:nonNamespaced
+
+// ServiceOperatorReport is the Schema for the serviceoperatorreports API.
+// It is maintained by the operator, and summarizes the state of all managed objects.
+type ServiceOperatorReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status ServiceOperatorReportStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ServiceOperatorReportList contains a list of ServiceOperatorReport
+type ServiceOperatorReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ServiceOperatorReport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ServiceOperatorReport{}, &ServiceOperatorReportList{})
+}
@@ -12,6 +12,9 @@ import (
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.status.managementMode`,priority=1
+// +kubebuilder:printcolumn:name="Instances",type=integer,JSONPath=`.status.usage.serviceInstances`,priority=1
+// +kubebuilder:printcolumn:name="Limit",type=integer,JSONPath=`.status.usage.serviceInstancesLimit`,priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +genclient
 
@@ -55,17 +58,163 @@ type SpaceSpec struct {
 	// +kubebuilder:validation:MinLength=1
 	OrganizationName string `json:"organizationName,omitempty"`
 
-	// A reference to a secret containing the space authentication data.
+	// A reference to a secret containing the space authentication data
+	// (in the namespace of the object; for ClusterSpace objects, in the cluster resource namespace of the operator).
+	// Exactly one of AuthSecretName and AuthSecretRef must be specified.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	AuthSecretName string `json:"authSecretName,omitempty"`
+
+	// A reference to a secret containing the space authentication data, possibly in another namespace, such that
+	// credentials can be managed centrally; other namespaces must be allowed by the operator configuration (credentialNamespaces),
+	// and are not supported for ClusterSpace objects.
+	// Exactly one of AuthSecretName and AuthSecretRef must be specified.
+	// +optional
+	AuthSecretRef *SecretReference `json:"authSecretRef,omitempty"`
+
+	// Users to be assigned the space developer role.
+	// The user specified in the referenced secret is always added as developer, and need not to be listed here.
+	// Must not be specified if Guid is present.
+	// +optional
+	Developers []SpaceUser `json:"developers,omitempty"`
+
+	// Users to be assigned the space auditor role.
+	// Must not be specified if Guid is present.
+	// +optional
+	Auditors []SpaceUser `json:"auditors,omitempty"`
+
+	// Users to be assigned the space manager role.
+	// Must not be specified if Guid is present.
+	// +optional
+	Managers []SpaceUser `json:"managers,omitempty"`
+
+	// Overrides of the operator configuration, applying to all service instances, service bindings, routes and route bindings in this space.
+	// +optional
+	ConfigOverrides *SpaceConfigOverrides `json:"configOverrides,omitempty"`
+
+	// Deep health checks of the space, in addition to the basic check (whether the space exists, and is accessible
+	// with the credentials of the referenced secret); every probe reports its result by a separate condition.
+	// +optional
+	HealthCheck *SpaceHealthCheck `json:"healthCheck,omitempty"`
+
+	// What happens with the Cloud Foundry service instances and bindings in the space (created by the operator) when the space is deleted;
+	// with Cascade, they are deleted (including such without ServiceInstance or ServiceBinding objects, as long as their owner resolves
+	// to this cluster) before the space itself is deleted;
+	// only allowed for spaces managed by the operator (that is, if spec.guid is not set). Defaults to Orphan.
+	// +optional
+	// +kubebuilder:validation:Enum=Orphan;Cascade
+	DeletionPolicy SpaceDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// What happens with the user of the space secret when the secret is changed to another user (credential rotation); the new user is always
+	// assigned the space developer role; with Remove, the developer role of the previous user is removed (unless that user is listed in spec.developers);
+	// only allowed for spaces managed by the operator (that is, if spec.guid is not set). Defaults to Retain.
+	// +optional
+	// +kubebuilder:validation:Enum=Retain;Remove
+	CredentialRotationPolicy SpaceCredentialRotationPolicy `json:"credentialRotationPolicy,omitempty"`
+}
+
+// SpaceDeletionPolicy defines what happens with the contents of a Cloud Foundry space when the space is deleted.
+type SpaceDeletionPolicy string
+
+const (
+	// SpaceDeletionPolicyOrphan leaves service instances and bindings without ServiceInstance or ServiceBinding objects untouched
+	// (deletion of the space waits until all ServiceInstance objects referencing the space are gone).
+	SpaceDeletionPolicyOrphan SpaceDeletionPolicy = "Orphan"
+	// SpaceDeletionPolicyCascade deletes all service instances and bindings created by the operator (in this cluster) in the space,
+	// before the space is deleted.
+	SpaceDeletionPolicyCascade SpaceDeletionPolicy = "Cascade"
+)
+
+// SpaceCredentialRotationPolicy defines what happens with the previous user of the space secret when the secret is changed to another user.
+type SpaceCredentialRotationPolicy string
+
+const (
+	// SpaceCredentialRotationPolicyRetain leaves the space roles of the previous user untouched.
+	SpaceCredentialRotationPolicyRetain SpaceCredentialRotationPolicy = "Retain"
+	// SpaceCredentialRotationPolicyRemove removes the space developer role of the previous user.
+	SpaceCredentialRotationPolicyRemove SpaceCredentialRotationPolicy = "Remove"
+)
+
+// SpaceHealthCheck configures the deep health checks of a space.
+type SpaceHealthCheck struct {
+	// Probes to be run.
+	// +optional
+	Probes []SpaceHealthProbe `json:"probes,omitempty"`
+
+	// Minimum interval between two runs of the probes; defaults to 10m.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Usage (in percent) of the service instances quota of the space above which the Quota probe fails; defaults to 90.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	QuotaThreshold *int `json:"quotaThreshold,omitempty"`
+}
+
+// SpaceHealthProbe is a deep health check of a space.
+// +kubebuilder:validation:Enum=ServicePlans;Quota;DeveloperRole
+type SpaceHealthProbe string
+
+const (
+	// SpaceHealthProbeServicePlans checks that service plans are available in the space (condition ServicePlansAvailable).
+	SpaceHealthProbeServicePlans SpaceHealthProbe = "ServicePlans"
+	// SpaceHealthProbeQuota checks that the service instances quota of the space is not (nearly) exhausted (condition QuotaAvailable).
+	SpaceHealthProbeQuota SpaceHealthProbe = "Quota"
+	// SpaceHealthProbeDeveloperRole checks that the user of the space secret has the space developer role (condition DeveloperRoleAssigned).
+	SpaceHealthProbeDeveloperRole SpaceHealthProbe = "DeveloperRole"
+)
+
+// SpaceConfigOverrides overrides operator-wide settings for the objects in a space.
+// Unset fields default to the operator configuration; annotations on the individual objects still take precedence.
+type SpaceConfigOverrides struct {
+	// Time after which cached Cloud Foundry resources of this space expire (overrides the configuration key resourceCacheTimeout);
+	// only relevant if the resource cache is enabled.
+	// +optional
+	ResourceCacheTimeout *metav1.Duration `json:"resourceCacheTimeout,omitempty"`
+
+	// Interval in which ready objects are re-synced with Cloud Foundry
+	// (default for the annotation service-operator.cf.cs.sap.com/polling-interval-ready).
+	// +optional
+	PollingIntervalReady *metav1.Duration `json:"pollingIntervalReady,omitempty"`
+
+	// Interval in which service instances are re-synced after the maximum number of retries was exceeded
+	// (default for the annotation service-operator.cf.cs.sap.com/polling-interval-fail).
+	// +optional
+	PollingIntervalFail *metav1.Duration `json:"pollingIntervalFail,omitempty"`
+
+	// Maximum number of retries for failed service instances
+	// (default for the annotation service-operator.cf.cs.sap.com/max-retries).
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries *int `json:"maxRetries,omitempty"`
+}
+
+// SpaceUser identifies a Cloud Foundry user.
+type SpaceUser struct {
+	// User name.
+	// +kubebuilder:validation:MinLength=1
+	Username string `json:"username"`
+
+	// Origin of the user (such as uaa, or the origin key of some identity provider).
+	// Must be specified if the user name is not unique across origins.
+	// +optional
 	// +kubebuilder:validation:MinLength=1
-	AuthSecretName string `json:"authSecretName"`
+	Origin string `json:"origin,omitempty"`
 }
 
 // SpaceStatus defines the observed state of Space.
 type SpaceStatus struct {
-	// Observed generation
+	// Observed generation; this is the last generation which was successfully applied (that is, for which
+	// the Ready condition became True), so it lags behind metadata.generation while changes are in progress
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
+	// Whether the object is ready, that is, whether the Ready condition is True; note that this refers
+	// to status.observedGeneration, which must therefore be compared with metadata.generation
+	// +optional
+	Ready bool `json:"ready"`
+
 	// Last reconciliation timestamp
 	// +optional
 	LastReconciledAt *metav1.Time `json:"lastReconciledAt,omitempty"`
@@ -78,9 +227,61 @@ type SpaceStatus struct {
 	// +optional
 	SpaceGuid string `json:"spaceGuid,omitempty"`
 
+	// URL of the Cloud Foundry API endpoint in use for the space; this is the url of the space secret, unless that endpoint
+	// is unreachable, and the secret specifies a (reachable) failoverUrl
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Whether the Cloud Foundry space is managed by the operator (Managed), or was created outside of the operator
+	// and is only referenced by its guid (External); derived from spec.guid.
+	// +optional
+	ManagementMode SpaceManagementMode `json:"managementMode,omitempty"`
+
+	// Users which have been assigned the space developer role by the operator (as listed in spec.developers)
+	// +optional
+	Developers []SpaceUser `json:"developers,omitempty"`
+
+	// Users which have been assigned the space auditor role by the operator (as listed in spec.auditors)
+	// +optional
+	Auditors []SpaceUser `json:"auditors,omitempty"`
+
+	// Users which have been assigned the space manager role by the operator (as listed in spec.managers)
+	// +optional
+	Managers []SpaceUser `json:"managers,omitempty"`
+
+	// User of the space secret which has been assigned the space developer role by the operator
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// Last change of the user of the space secret (credential rotation)
+	// +optional
+	LastCredentialRotation *SpaceCredentialRotation `json:"lastCredentialRotation,omitempty"`
+
+	// Timestamp of the last run of the deep health checks (see spec.healthCheck)
+	// +optional
+	LastHealthCheckAt *metav1.Time `json:"lastHealthCheckAt,omitempty"`
+
+	// Usage of the space by service instances and bindings, as determined by the last reconcile
+	// +optional
+	Usage *SpaceUsage `json:"usage,omitempty"`
+
+	// Counts the number of retries that have been attempted for the reconciliation of this space
+	// (reset once the reconciliation succeeds).
+	// +optional
+	RetryCounter int `json:"retryCounter,omitempty"`
+
+	// This is the maximum number of retries that are allowed for the reconciliation of this space
+	// (see annotation service-operator.cf.cs.sap.com/max-retries). If the retry counter exceeds this value,
+	// the space will be marked as failed.
+	// +optional
+	MaxRetries int `json:"maxRetries,omitempty"`
+
 	// List of status conditions to indicate the status of a Space.
-	// Known condition types are `Ready`.
+	// Known condition types are `Ready`, `Synced`, `CredentialsReady`, `DeletionBlocked`, `CFReachable`, `Paused`, `Timeout`,
+	// and (if the according probes are enabled) `ServicePlansAvailable`, `QuotaAvailable`, `DeveloperRoleAssigned`.
 	// +optional
+	// +listType=map
+	// +listMapKey=type
 	Conditions []SpaceCondition `json:"conditions,omitempty"`
 
 	// Readable form of the state.
@@ -88,9 +289,48 @@ type SpaceStatus struct {
 	State SpaceState `json:"state,omitempty"`
 }
 
+// SpaceCredentialRotation describes a change of the user of the space secret.
+type SpaceCredentialRotation struct {
+	// Previous user of the space secret
+	PreviousUsername string `json:"previousUsername"`
+
+	// New user of the space secret
+	Username string `json:"username"`
+
+	// Whether the space developer role of the previous user was removed (see spec.credentialRotationPolicy)
+	// +optional
+	PreviousUserRemoved bool `json:"previousUserRemoved,omitempty"`
+
+	// Timestamp of the rotation (when the new user was assigned the space developer role)
+	RotatedAt metav1.Time `json:"rotatedAt"`
+}
+
+// SpaceUsage describes the usage of a space by service instances and bindings.
+type SpaceUsage struct {
+	// Number of ServiceInstance objects referencing the space
+	ManagedServiceInstances int `json:"managedServiceInstances"`
+
+	// Number of ServiceBinding objects referencing these service instances, either by name, or by guid
+	// (spec.serviceInstanceGuid together with spec.spaceName resp. spec.clusterSpaceName)
+	ManagedServiceBindings int `json:"managedServiceBindings"`
+
+	// Number of service instances in the Cloud Foundry space (including the ones not managed by the operator);
+	// not set if it could not be determined
+	// +optional
+	ServiceInstances *int `json:"serviceInstances,omitempty"`
+
+	// Name of the quota assigned to the Cloud Foundry space (if any)
+	// +optional
+	QuotaName string `json:"quotaName,omitempty"`
+
+	// Maximum number of service instances allowed by the space quota; not set if the space has no quota, or if the quota is unlimited
+	// +optional
+	ServiceInstancesLimit *int `json:"serviceInstancesLimit,omitempty"`
+}
+
 // SpaceCondition contains condition information for a Space.
 type SpaceCondition struct {
-	// Type of the condition, known values are ('Ready').
+	// Type of the condition, known values are ('Ready', 'Synced', 'CredentialsReady', 'DeletionBlocked', 'CFReachable').
 	Type SpaceConditionType `json:"type"`
 
 	// Status of the condition, one of ('True', 'False', 'Unknown').
@@ -110,6 +350,10 @@ type SpaceCondition struct {
 	// transition, complementing reason.
 	// +optional
 	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the metadata.generation of the object the condition was set for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // SpaceConditionType represents a Space condition value.
@@ -121,6 +365,43 @@ const (
 	// If the `status` of this condition is `False`, ServiceInstance controllers
 	// should prevent attempts to provision service instances.
 	SpaceConditionReady SpaceConditionType = "Ready"
+	// SpaceConditionSynced represents the fact that the Cloud Foundry space (including the space roles) reflects the current spec;
+	// it is False if applying the spec failed.
+	SpaceConditionSynced SpaceConditionType = "Synced"
+	// SpaceConditionCredentialsReady represents the fact that the credentials given by the referenced secret
+	// grant access to the Cloud Foundry space.
+	SpaceConditionCredentialsReady SpaceConditionType = "CredentialsReady"
+	// SpaceConditionDeletionBlocked represents the fact that the deletion of the space is blocked
+	// (e.g. by depending service instances); it is only present while the space is being deleted.
+	SpaceConditionDeletionBlocked SpaceConditionType = "DeletionBlocked"
+	// SpaceConditionCFReachable represents the fact that the Cloud Foundry API was reachable during the last reconciliation.
+	SpaceConditionCFReachable SpaceConditionType = "CFReachable"
+	// SpaceConditionPaused represents the fact that reconciliation is paused by the annotation service-operator.cf.cs.sap.com/paused;
+	// it is only present while the annotation is set.
+	SpaceConditionPaused SpaceConditionType = "Paused"
+	// SpaceConditionTimeout represents the fact that the last reconciliation did not complete within the maximum reconcile duration
+	// (see annotation service-operator.cf.cs.sap.com/reconcile-call-timeout); it is only present while this is the case.
+	SpaceConditionTimeout SpaceConditionType = "Timeout"
+	// SpaceConditionServicePlansAvailable represents the result of the ServicePlans health probe.
+	SpaceConditionServicePlansAvailable SpaceConditionType = "ServicePlansAvailable"
+	// SpaceConditionQuotaAvailable represents the result of the Quota health probe.
+	SpaceConditionQuotaAvailable SpaceConditionType = "QuotaAvailable"
+	// SpaceConditionDeveloperRoleAssigned represents the result of the DeveloperRole health probe.
+	SpaceConditionDeveloperRoleAssigned SpaceConditionType = "DeveloperRoleAssigned"
+)
+
+// SpaceManagementMode describes which lifecycle operations the operator performs on a Cloud Foundry space
+// +kubebuilder:validation:Enum=Managed;External
+type SpaceManagementMode string
+
+const (
+	// SpaceManagementModeManaged means that the operator creates, updates and deletes the Cloud Foundry space,
+	// and maintains its role assignments.
+	SpaceManagementModeManaged SpaceManagementMode = "Managed"
+
+	// SpaceManagementModeExternal means that the Cloud Foundry space is managed outside of the operator;
+	// the operator only uses it for service instances and bindings, and never modifies or deletes it.
+	SpaceManagementModeExternal SpaceManagementMode = "External"
 )
 
 // SpaceState represents a condition state in a readable form
@@ -172,6 +453,21 @@ func (space *Space) GetReadyCondition() *SpaceCondition {
 	return getSpaceReadyCondition(space)
 }
 
+// Set condition of the given type
+func (space *Space) SetCondition(conditionType SpaceConditionType, conditionStatus ConditionStatus, reason, message string) {
+	setSpaceCondition(space, conditionType, conditionStatus, reason, message)
+}
+
+// Get condition of the given type
+func (space *Space) GetCondition(conditionType SpaceConditionType) *SpaceCondition {
+	return getSpaceCondition(space, conditionType)
+}
+
+// Remove condition of the given type
+func (space *Space) RemoveCondition(conditionType SpaceConditionType) {
+	removeSpaceCondition(space, conditionType)
+}
+
 // Check if space is in a ready state
 func (space *Space) IsReady() bool {
 	return isSpaceReady(space)
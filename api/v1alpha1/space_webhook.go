@@ -6,6 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package v1alpha1
 
 import (
+	"context"
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -18,9 +19,12 @@ import (
 // log is for logging in this package.
 var spacelog = logf.Log.WithName("space-resource")
 
-func (r *Space) SetupWebhookWithManager(mgr ctrl.Manager) error {
+// SetupWebhookWithManager registers the webhooks for Space; spec.authSecretRef may reference secrets in the given
+// credentialNamespaces (in addition to the namespace of the space).
+func (r *Space) SetupWebhookWithManager(mgr ctrl.Manager, credentialNamespaces []string) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithValidator(&spaceValidator{credentialNamespaces: credentialNamespaces}).
 		Complete()
 }
 
@@ -43,6 +47,10 @@ var _ webhook.Validator = &Space{}
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *Space) ValidateCreate() (admission.Warnings, error) {
+	return r.validateCreate(nil)
+}
+
+func (r *Space) validateCreate(credentialNamespaces []string) (admission.Warnings, error) {
 	spacelog.V(2).Info("Validate create", "name", r.Name)
 	// Call the defaulting logic again (because defaulting might be incomplete in case of generateName usage)
 	if r.Name == "" && r.GenerateName != "" {
@@ -55,11 +63,35 @@ func (r *Space) ValidateCreate() (admission.Warnings, error) {
 		return nil, fmt.Errorf("exactly one of spec.guid or spec.name plus spec.organizationName must be specified")
 	}
 
-	return nil, nil
+	if r.Spec.Guid != "" && (len(r.Spec.Developers) > 0 || len(r.Spec.Auditors) > 0 || len(r.Spec.Managers) > 0) {
+		return nil, fmt.Errorf("spec.developers, spec.auditors and spec.managers must not be specified if spec.guid is present")
+	}
+
+	if err := r.Spec.validateAuthSecret(true, r.Namespace, credentialNamespaces); err != nil {
+		return nil, err
+	}
+
+	if err := r.Spec.validateConfigOverrides(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Spec.validateDeletionPolicy(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Spec.validateCredentialRotationPolicy(); err != nil {
+		return nil, err
+	}
+
+	return spaceWarnings(r), nil
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *Space) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	return r.validateUpdate(old, nil)
+}
+
+func (r *Space) validateUpdate(old runtime.Object, credentialNamespaces []string) (admission.Warnings, error) {
 	spacelog.V(2).Info("Validate update", "name", r.Name)
 	s := old.(*Space)
 	// Call the defaulting webhook logic for the old object (because defaulting through the webhook might be incomplete in case of generateName usage)
@@ -67,19 +99,39 @@ func (r *Space) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
 	s.Default()
 
 	if r.Spec.Guid != s.Spec.Guid {
-		return nil, fmt.Errorf("spec.guid is immutable")
+		return nil, immutableFieldError("spec.guid", s.Spec.Guid, r.Spec.Guid)
 	}
 
 	// TODO: why not to allow name updates ?
 	if r.Spec.Name != s.Spec.Name {
-		return nil, fmt.Errorf("spec.name is immutable")
+		return nil, immutableFieldError("spec.name", s.Spec.Name, r.Spec.Name)
 	}
 
 	if r.Spec.OrganizationName != s.Spec.OrganizationName {
-		return nil, fmt.Errorf("spec.organizationName is immutable")
+		return nil, immutableFieldError("spec.organizationName", s.Spec.OrganizationName, r.Spec.OrganizationName)
 	}
 
-	return nil, nil
+	if r.Spec.Guid != "" && (len(r.Spec.Developers) > 0 || len(r.Spec.Auditors) > 0 || len(r.Spec.Managers) > 0) {
+		return nil, fmt.Errorf("spec.developers, spec.auditors and spec.managers must not be specified if spec.guid is present")
+	}
+
+	if err := r.Spec.validateAuthSecret(true, r.Namespace, credentialNamespaces); err != nil {
+		return nil, err
+	}
+
+	if err := r.Spec.validateConfigOverrides(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Spec.validateDeletionPolicy(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Spec.validateCredentialRotationPolicy(); err != nil {
+		return nil, err
+	}
+
+	return updateWarnings(spaceWarnings(r), spaceWarnings(s)), nil
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -88,3 +140,22 @@ func (r *Space) ValidateDelete() (admission.Warnings, error) {
 
 	return nil, nil
 }
+
+// spaceValidator extends the validation implemented by Space by the namespaces from which space credentials may be read.
+type spaceValidator struct {
+	credentialNamespaces []string
+}
+
+var _ admission.CustomValidator = &spaceValidator{}
+
+func (v *spaceValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return obj.(*Space).validateCreate(v.credentialNamespaces)
+}
+
+func (v *spaceValidator) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	return newObj.(*Space).validateUpdate(oldObj, v.credentialNamespaces)
+}
+
+func (v *spaceValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return obj.(*Space).ValidateDelete()
+}
@@ -0,0 +1,121 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Note: ValidateCreate and ValidateUpdate are called directly (on defaulted objects, as the API server would do);
+// so these tests do not need the envtest based webhook suite, and run standalone.
+
+func TestAnnotationWarnings(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(annotationWarnings(nil)).To(BeEmpty())
+	g.Expect(annotationWarnings(map[string]string{AnnotationPollingIntervalReady: "10m", AnnotationPollingIntervalFail: "10s"})).To(BeEmpty())
+	// zero intervals disable polling, and therefore do not put load on Cloud Foundry
+	g.Expect(annotationWarnings(map[string]string{AnnotationPollingIntervalReady: "0"})).To(BeEmpty())
+
+	g.Expect(annotationWarnings(map[string]string{AnnotationPollingIntervalFail: "5s"})).
+		To(ConsistOf(ContainSubstring("annotation " + AnnotationPollingIntervalFail + " specifies a very small polling interval (5s)")))
+	g.Expect(annotationWarnings(map[string]string{AnnotationPollingIntervalReady: "often"})).
+		To(ConsistOf(ContainSubstring("annotation " + AnnotationPollingIntervalReady + " has an invalid value (often)")))
+	g.Expect(annotationWarnings(map[string]string{AnnotationReconcileCallTimeout: "-1m"})).
+		To(ConsistOf(ContainSubstring("annotation " + AnnotationReconcileCallTimeout + " has an invalid value (-1m)")))
+}
+
+func TestAdoptionWarnings(t *testing.T) {
+	g := NewWithT(t)
+
+	object := &ServiceInstance{ObjectMeta: metav1.ObjectMeta{Name: "instance"}}
+	g.Expect(adoptionWarnings(object, "other-name")).To(BeEmpty())
+
+	object.Annotations = map[string]string{AnnotationAdoptCFResources: "adopt"}
+	g.Expect(adoptionWarnings(object, "instance")).To(BeEmpty())
+	g.Expect(adoptionWarnings(object, "other-name")).To(ConsistOf(ContainSubstring("adopts Cloud Foundry resources named instance (metadata.name), not other-name (spec.name)")))
+
+	object.Annotations[AnnotationAdoptCFResources] = "true"
+	g.Expect(adoptionWarnings(object, "instance")).To(ConsistOf(ContainSubstring("has no effect unless set to 'adopt'")))
+}
+
+func TestServiceInstanceWarnings(t *testing.T) {
+	g := NewWithT(t)
+
+	serviceInstance := &ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "instance"},
+		Spec:       ServiceInstanceSpec{SpaceName: "space", ServiceOfferingName: "offering", ServicePlanName: "plan", Tags: []string{"db"}},
+	}
+	serviceInstance.Default()
+	g.Expect(serviceInstance.ValidateCreate()).To(BeEmpty())
+
+	risky := &ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "instance", Annotations: map[string]string{AnnotationPollingIntervalReady: "1s"}},
+		Spec:       ServiceInstanceSpec{SpaceName: "space", ServicePlanGuid: "plan-guid"},
+	}
+	risky.Default()
+	g.Expect(risky.ValidateCreate()).To(ConsistOf(
+		ContainSubstring("spec.servicePlanGuid is specific to a Cloud Foundry landscape"),
+		ContainSubstring("spec.tags is empty"),
+		ContainSubstring("very small polling interval"),
+	))
+
+	// updates only report warnings about settings introduced by the update
+	changed := risky.DeepCopy()
+	changed.Labels = map[string]string{"team": "a"}
+	g.Expect(changed.ValidateUpdate(risky.DeepCopy())).To(BeEmpty())
+	changed.Annotations[AnnotationPollingIntervalFail] = "2s"
+	g.Expect(changed.ValidateUpdate(risky.DeepCopy())).To(ConsistOf(ContainSubstring("annotation " + AnnotationPollingIntervalFail)))
+
+	changed = serviceInstance.DeepCopy()
+	changed.Spec.Tags = nil
+	g.Expect(changed.ValidateUpdate(serviceInstance.DeepCopy())).To(ConsistOf(ContainSubstring("spec.tags is empty")))
+}
+
+func TestServiceBindingWarnings(t *testing.T) {
+	g := NewWithT(t)
+
+	serviceBinding := &ServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "binding"},
+		Spec:       ServiceBindingSpec{Name: "cf-binding", ServiceInstanceName: "instance"},
+	}
+	serviceBinding.Default()
+	g.Expect(serviceBinding.ValidateCreate()).To(BeEmpty())
+
+	changed := serviceBinding.DeepCopy()
+	changed.Annotations = map[string]string{AnnotationAdoptCFResources: "adopt"}
+	g.Expect(changed.ValidateUpdate(serviceBinding.DeepCopy())).To(ConsistOf(ContainSubstring("not cf-binding (spec.name)")))
+	g.Expect(changed.ValidateUpdate(changed.DeepCopy())).To(BeEmpty())
+}
+
+func TestSpaceWarnings(t *testing.T) {
+	g := NewWithT(t)
+
+	space := &Space{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "space"},
+		Spec:       SpaceSpec{Name: "space", OrganizationName: "org", AuthSecretName: "space-secret"},
+	}
+	space.Default()
+	g.Expect(space.ValidateCreate()).To(BeEmpty())
+
+	changed := space.DeepCopy()
+	changed.Spec.ConfigOverrides = &SpaceConfigOverrides{
+		PollingIntervalReady: &metav1.Duration{Duration: 5 * time.Second},
+		PollingIntervalFail:  &metav1.Duration{Duration: time.Minute},
+	}
+	g.Expect(changed.ValidateUpdate(space.DeepCopy())).To(ConsistOf(ContainSubstring("spec.configOverrides.pollingIntervalReady specifies a very small polling interval (5s)")))
+	g.Expect(changed.ValidateUpdate(changed.DeepCopy())).To(BeEmpty())
+
+	clusterSpace := &ClusterSpace{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-space", Annotations: map[string]string{AnnotationPollingIntervalReady: "1s"}},
+		Spec:       SpaceSpec{Name: "space", OrganizationName: "org", AuthSecretName: "space-secret"},
+	}
+	clusterSpace.Default()
+	g.Expect(clusterSpace.ValidateCreate()).To(ConsistOf(ContainSubstring("very small polling interval")))
+}
@@ -0,0 +1,76 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Validate binding types | ValidateCreate", func() {
+	ctx := context.Background()
+
+	It("should derive the binding type, and require an application for app bindings only", func() {
+		serviceBinding := &ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app-binding"},
+			Spec:       ServiceBindingSpec{ServiceInstanceName: "instance", AppName: "app"},
+		}
+		Expect(k8sClient.Create(ctx, serviceBinding)).To(Succeed())
+		Expect(serviceBinding.Spec.Type).To(Equal(ServiceBindingTypeApp))
+
+		changed := serviceBinding.DeepCopy()
+		changed.Spec.Type = ServiceBindingTypeKey
+		Expect(k8sClient.Update(ctx, changed)).To(MatchError(ContainSubstring("spec.type is immutable")))
+
+		serviceBinding = &ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app-binding-without-app"},
+			Spec:       ServiceBindingSpec{ServiceInstanceName: "instance", Type: ServiceBindingTypeApp},
+		}
+		Expect(k8sClient.Create(ctx, serviceBinding)).To(MatchError(ContainSubstring("one of spec.appGuid or spec.appName must be specified")))
+
+		serviceBinding = &ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "key-with-app"},
+			Spec:       ServiceBindingSpec{ServiceInstanceName: "instance", Type: ServiceBindingTypeKey, AppGuid: "app-guid"},
+		}
+		Expect(k8sClient.Create(ctx, serviceBinding)).To(MatchError(ContainSubstring("must not be specified if spec.type is key")))
+	})
+})
+
+var _ = Describe("Validate space credentials | ValidateCreate", func() {
+	ctx := context.Background()
+
+	It("should allow secrets in the credential namespaces only", func() {
+		space := &Space{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "central-space"},
+			Spec:       SpaceSpec{Guid: "space-guid", AuthSecretRef: &SecretReference{Namespace: "credentials", Name: "space-secret"}},
+		}
+		Expect(k8sClient.Create(ctx, space)).To(Succeed())
+
+		space = &Space{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other-space"},
+			Spec:       SpaceSpec{Guid: "space-guid", AuthSecretRef: &SecretReference{Namespace: "kube-system", Name: "space-secret"}},
+		}
+		Expect(k8sClient.Create(ctx, space)).To(MatchError(ContainSubstring("namespace not allowed by the operator configuration")))
+	})
+
+	It("should require exactly one secret reference", func() {
+		space := &Space{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ambiguous-space"},
+			Spec:       SpaceSpec{Guid: "space-guid", AuthSecretName: "space-secret", AuthSecretRef: &SecretReference{Name: "space-secret"}},
+		}
+		Expect(k8sClient.Create(ctx, space)).To(MatchError(ContainSubstring("exactly one of spec.authSecretName or spec.authSecretRef")))
+	})
+
+	It("should reject secrets in other namespaces for cluster spaces", func() {
+		clusterSpace := &ClusterSpace{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-space"},
+			Spec:       SpaceSpec{Guid: "space-guid", AuthSecretRef: &SecretReference{Namespace: "credentials", Name: "space-secret"}},
+		}
+		Expect(k8sClient.Create(ctx, clusterSpace)).To(MatchError(ContainSubstring("spec.authSecretRef.namespace must not be specified")))
+	})
+})
@@ -11,15 +11,259 @@ package v1alpha1
 
 import (
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindingSecret) DeepCopyInto(out *BindingSecret) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]BindingSecretKey, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindingSecret.
+func (in *BindingSecret) DeepCopy() *BindingSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(BindingSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindingSecretKey) DeepCopyInto(out *BindingSecretKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindingSecretKey.
+func (in *BindingSecretKey) DeepCopy() *BindingSecretKey {
+	if in == nil {
+		return nil
+	}
+	out := new(BindingSecretKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFError) DeepCopyInto(out *CFError) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFError.
+func (in *CFError) DeepCopy() *CFError {
+	if in == nil {
+		return nil
+	}
+	out := new(CFError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFMetadata) DeepCopyInto(out *CFMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFMetadata.
+func (in *CFMetadata) DeepCopy() *CFMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(CFMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CacheStatistics) DeepCopyInto(out *CacheStatistics) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheStatistics.
+func (in *CacheStatistics) DeepCopy() *CacheStatistics {
+	if in == nil {
+		return nil
+	}
+	out := new(CacheStatistics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterServiceBinding) DeepCopyInto(out *ClusterServiceBinding) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterServiceBinding.
+func (in *ClusterServiceBinding) DeepCopy() *ClusterServiceBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterServiceBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterServiceBinding) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterServiceBindingCondition) DeepCopyInto(out *ClusterServiceBindingCondition) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterServiceBindingCondition.
+func (in *ClusterServiceBindingCondition) DeepCopy() *ClusterServiceBindingCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterServiceBindingCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterServiceBindingList) DeepCopyInto(out *ClusterServiceBindingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterServiceBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterServiceBindingList.
+func (in *ClusterServiceBindingList) DeepCopy() *ClusterServiceBindingList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterServiceBindingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterServiceBindingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterServiceBindingSpec) DeepCopyInto(out *ClusterServiceBindingSpec) {
+	*out = *in
+	out.ServiceInstanceRef = in.ServiceInstanceRef
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = new(v1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(CFMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SAPBindingMetadata != nil {
+		in, out := &in.SAPBindingMetadata, &out.SAPBindingMetadata
+		*out = new(SAPBindingMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterServiceBindingSpec.
+func (in *ClusterServiceBindingSpec) DeepCopy() *ClusterServiceBindingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterServiceBindingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterServiceBindingStatus) DeepCopyInto(out *ClusterServiceBindingStatus) {
+	*out = *in
+	if in.LastReconciledAt != nil {
+		in, out := &in.LastReconciledAt, &out.LastReconciledAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastModifiedAt != nil {
+		in, out := &in.LastModifiedAt, &out.LastModifiedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CredentialKeys != nil {
+		in, out := &in.CredentialKeys, &out.CredentialKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastOperation != nil {
+		in, out := &in.LastOperation, &out.LastOperation
+		*out = new(LastOperation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ClusterServiceBindingCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterServiceBindingStatus.
+func (in *ClusterServiceBindingStatus) DeepCopy() *ClusterServiceBindingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterServiceBindingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterSpace) DeepCopyInto(out *ClusterSpace) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -55,55 +299,780 @@ func (in *ClusterSpaceList) DeepCopyInto(out *ClusterSpaceList) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpaceList.
-func (in *ClusterSpaceList) DeepCopy() *ClusterSpaceList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpaceList.
+func (in *ClusterSpaceList) DeepCopy() *ClusterSpaceList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSpaceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterSpaceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapKeyReference) DeepCopyInto(out *ConfigMapKeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapKeyReference.
+func (in *ConfigMapKeyReference) DeepCopy() *ConfigMapKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointRateLimit) DeepCopyInto(out *EndpointRateLimit) {
+	*out = *in
+	if in.MaxRequestsPerSecond != nil {
+		in, out := &in.MaxRequestsPerSecond, &out.MaxRequestsPerSecond
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointRateLimit.
+func (in *EndpointRateLimit) DeepCopy() *EndpointRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationReport) DeepCopyInto(out *FoundationReport) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationReport.
+func (in *FoundationReport) DeepCopy() *FoundationReport {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LastOperation) DeepCopyInto(out *LastOperation) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.UpdatedAt != nil {
+		in, out := &in.UpdatedAt, &out.UpdatedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LastOperation.
+func (in *LastOperation) DeepCopy() *LastOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(LastOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceInfo) DeepCopyInto(out *MaintenanceInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceInfo.
+func (in *MaintenanceInfo) DeepCopy() *MaintenanceInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfig) DeepCopyInto(out *OperatorConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfig.
+func (in *OperatorConfig) DeepCopy() *OperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigCondition) DeepCopyInto(out *OperatorConfigCondition) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigCondition.
+func (in *OperatorConfigCondition) DeepCopy() *OperatorConfigCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigList) DeepCopyInto(out *OperatorConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperatorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigList.
+func (in *OperatorConfigList) DeepCopy() *OperatorConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigSpec) DeepCopyInto(out *OperatorConfigSpec) {
+	*out = *in
+	if in.ResourceCacheEnabled != nil {
+		in, out := &in.ResourceCacheEnabled, &out.ResourceCacheEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ResourceCacheTimeout != nil {
+		in, out := &in.ResourceCacheTimeout, &out.ResourceCacheTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxConcurrentReconciles != nil {
+		in, out := &in.MaxConcurrentReconciles, &out.MaxConcurrentReconciles
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxConcurrentReconcilesPerEndpoint != nil {
+		in, out := &in.MaxConcurrentReconcilesPerEndpoint, &out.MaxConcurrentReconcilesPerEndpoint
+		*out = new(int)
+		**out = **in
+	}
+	if in.PollingIntervalsReady != nil {
+		in, out := &in.PollingIntervalsReady, &out.PollingIntervalsReady
+		*out = make(map[string]metav1.Duration, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PollingIntervalsFail != nil {
+		in, out := &in.PollingIntervalsFail, &out.PollingIntervalsFail
+		*out = make(map[string]metav1.Duration, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MaxRetriesOnTooManyRequests != nil {
+		in, out := &in.MaxRetriesOnTooManyRequests, &out.MaxRetriesOnTooManyRequests
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxRequestsPerSecond != nil {
+		in, out := &in.MaxRequestsPerSecond, &out.MaxRequestsPerSecond
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Burst != nil {
+		in, out := &in.Burst, &out.Burst
+		*out = new(int)
+		**out = **in
+	}
+	if in.EndpointRateLimits != nil {
+		in, out := &in.EndpointRateLimits, &out.EndpointRateLimits
+		*out = make(map[string]EndpointRateLimit, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigSpec.
+func (in *OperatorConfigSpec) DeepCopy() *OperatorConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigStatus) DeepCopyInto(out *OperatorConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]OperatorConfigCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastAppliedAt != nil {
+		in, out := &in.LastAppliedAt, &out.LastAppliedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigStatus.
+func (in *OperatorConfigStatus) DeepCopy() *OperatorConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParameterChange) DeepCopyInto(out *ParameterChange) {
+	*out = *in
+	in.AppliedAt.DeepCopyInto(&out.AppliedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParameterChange.
+func (in *ParameterChange) DeepCopy() *ParameterChange {
+	if in == nil {
+		return nil
+	}
+	out := new(ParameterChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParameterFieldReference) DeepCopyInto(out *ParameterFieldReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParameterFieldReference.
+func (in *ParameterFieldReference) DeepCopy() *ParameterFieldReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ParameterFieldReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParametersFromSource) DeepCopyInto(out *ParametersFromSource) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(SecretKeyReference)
+		**out = **in
+	}
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(ConfigMapKeyReference)
+		**out = **in
+	}
+	if in.FieldRef != nil {
+		in, out := &in.FieldRef, &out.FieldRef
+		*out = new(ParameterFieldReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParametersFromSource.
+func (in *ParametersFromSource) DeepCopy() *ParametersFromSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ParametersFromSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingChanges) DeepCopyInto(out *PendingChanges) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ComputedAt != nil {
+		in, out := &in.ComputedAt, &out.ComputedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingChanges.
+func (in *PendingChanges) DeepCopy() *PendingChanges {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingChanges)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreDeleteHook) DeepCopyInto(out *PreDeleteHook) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreDeleteHook.
+func (in *PreDeleteHook) DeepCopy() *PreDeleteHook {
+	if in == nil {
+		return nil
+	}
+	out := new(PreDeleteHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreDeleteHookStatus) DeepCopyInto(out *PreDeleteHookStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreDeleteHookStatus.
+func (in *PreDeleteHookStatus) DeepCopy() *PreDeleteHookStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PreDeleteHookStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceCounts) DeepCopyInto(out *ResourceCounts) {
+	*out = *in
+	if in.ByState != nil {
+		in, out := &in.ByState, &out.ByState
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceCounts.
+func (in *ResourceCounts) DeepCopy() *ResourceCounts {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceCounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceFailure) DeepCopyInto(out *ResourceFailure) {
+	*out = *in
+	if in.Time != nil {
+		in, out := &in.Time, &out.Time
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceFailure.
+func (in *ResourceFailure) DeepCopy() *ResourceFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Route) DeepCopyInto(out *Route) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Route.
+func (in *Route) DeepCopy() *Route {
+	if in == nil {
+		return nil
+	}
+	out := new(Route)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Route) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteBinding) DeepCopyInto(out *RouteBinding) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteBinding.
+func (in *RouteBinding) DeepCopy() *RouteBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RouteBinding) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteBindingCondition) DeepCopyInto(out *RouteBindingCondition) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteBindingCondition.
+func (in *RouteBindingCondition) DeepCopy() *RouteBindingCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteBindingCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteBindingList) DeepCopyInto(out *RouteBindingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RouteBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteBindingList.
+func (in *RouteBindingList) DeepCopy() *RouteBindingList {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteBindingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RouteBindingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteBindingSpec) DeepCopyInto(out *RouteBindingSpec) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = new(v1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ParametersFrom != nil {
+		in, out := &in.ParametersFrom, &out.ParametersFrom
+		*out = make([]ParametersFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteBindingSpec.
+func (in *RouteBindingSpec) DeepCopy() *RouteBindingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteBindingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteBindingStatus) DeepCopyInto(out *RouteBindingStatus) {
+	*out = *in
+	if in.LastReconciledAt != nil {
+		in, out := &in.LastReconciledAt, &out.LastReconciledAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastModifiedAt != nil {
+		in, out := &in.LastModifiedAt, &out.LastModifiedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]RouteBindingCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteBindingStatus.
+func (in *RouteBindingStatus) DeepCopy() *RouteBindingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteBindingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteCondition) DeepCopyInto(out *RouteCondition) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteCondition.
+func (in *RouteCondition) DeepCopy() *RouteCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteList) DeepCopyInto(out *RouteList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Route, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteList.
+func (in *RouteList) DeepCopy() *RouteList {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RouteList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteSpec) DeepCopyInto(out *RouteSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteSpec.
+func (in *RouteSpec) DeepCopy() *RouteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteStatus) DeepCopyInto(out *RouteStatus) {
+	*out = *in
+	if in.LastReconciledAt != nil {
+		in, out := &in.LastReconciledAt, &out.LastReconciledAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastModifiedAt != nil {
+		in, out := &in.LastModifiedAt, &out.LastModifiedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]RouteCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteStatus.
+func (in *RouteStatus) DeepCopy() *RouteStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SAPBindingMetadata) DeepCopyInto(out *SAPBindingMetadata) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]SAPBindingMetadataField, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SAPBindingMetadata.
+func (in *SAPBindingMetadata) DeepCopy() *SAPBindingMetadata {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterSpaceList)
+	out := new(SAPBindingMetadata)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterSpaceList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyReference) DeepCopyInto(out *SecretKeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyReference.
+func (in *SecretKeyReference) DeepCopy() *SecretKeyReference {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(SecretKeyReference)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ParametersFromSource) DeepCopyInto(out *ParametersFromSource) {
+func (in *SecretReference) DeepCopyInto(out *SecretReference) {
 	*out = *in
-	if in.SecretKeyRef != nil {
-		in, out := &in.SecretKeyRef, &out.SecretKeyRef
-		*out = new(SecretKeyReference)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParametersFromSource.
-func (in *ParametersFromSource) DeepCopy() *ParametersFromSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretReference.
+func (in *SecretReference) DeepCopy() *SecretReference {
 	if in == nil {
 		return nil
 	}
-	out := new(ParametersFromSource)
+	out := new(SecretReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretKeyReference) DeepCopyInto(out *SecretKeyReference) {
+func (in *SecretStoreReference) DeepCopyInto(out *SecretStoreReference) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyReference.
-func (in *SecretKeyReference) DeepCopy() *SecretKeyReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreReference.
+func (in *SecretStoreReference) DeepCopy() *SecretStoreReference {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretKeyReference)
+	out := new(SecretStoreReference)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -201,6 +1170,33 @@ func (in *ServiceBindingSpec) DeepCopyInto(out *ServiceBindingSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(CFMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeys != nil {
+		in, out := &in.SecretKeys, &out.SecretKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]BindingSecret, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SAPBindingMetadata != nil {
+		in, out := &in.SAPBindingMetadata, &out.SAPBindingMetadata
+		*out = new(SAPBindingMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretStoreRef != nil {
+		in, out := &in.SecretStoreRef, &out.SecretStoreRef
+		*out = new(SecretStoreReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceBindingSpec.
@@ -224,6 +1220,30 @@ func (in *ServiceBindingStatus) DeepCopyInto(out *ServiceBindingStatus) {
 		in, out := &in.LastModifiedAt, &out.LastModifiedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.CredentialKeys != nil {
+		in, out := &in.CredentialKeys, &out.CredentialKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastCredentialsRefreshAt != nil {
+		in, out := &in.LastCredentialsRefreshAt, &out.LastCredentialsRefreshAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastCFError != nil {
+		in, out := &in.LastCFError, &out.LastCFError
+		*out = new(CFError)
+		**out = **in
+	}
+	if in.LastOperation != nil {
+		in, out := &in.LastOperation, &out.LastOperation
+		*out = new(LastOperation)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]ServiceBindingCondition, len(*in))
@@ -321,6 +1341,21 @@ func (in *ServiceInstanceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceInstanceReference) DeepCopyInto(out *ServiceInstanceReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceInstanceReference.
+func (in *ServiceInstanceReference) DeepCopy() *ServiceInstanceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceInstanceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceInstanceSpec) DeepCopyInto(out *ServiceInstanceSpec) {
 	*out = *in
@@ -341,6 +1376,16 @@ func (in *ServiceInstanceSpec) DeepCopyInto(out *ServiceInstanceSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(CFMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreDeleteHook != nil {
+		in, out := &in.PreDeleteHook, &out.PreDeleteHook
+		*out = new(PreDeleteHook)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceInstanceSpec.
@@ -364,6 +1409,38 @@ func (in *ServiceInstanceStatus) DeepCopyInto(out *ServiceInstanceStatus) {
 		in, out := &in.LastModifiedAt, &out.LastModifiedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.LastCFError != nil {
+		in, out := &in.LastCFError, &out.LastCFError
+		*out = new(CFError)
+		**out = **in
+	}
+	if in.LastOperation != nil {
+		in, out := &in.LastOperation, &out.LastOperation
+		*out = new(LastOperation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AvailableUpgrade != nil {
+		in, out := &in.AvailableUpgrade, &out.AvailableUpgrade
+		*out = new(MaintenanceInfo)
+		**out = **in
+	}
+	if in.PendingChanges != nil {
+		in, out := &in.PendingChanges, &out.PendingChanges
+		*out = new(PendingChanges)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ParameterHistory != nil {
+		in, out := &in.ParameterHistory, &out.ParameterHistory
+		*out = make([]ParameterChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreDeleteHook != nil {
+		in, out := &in.PreDeleteHook, &out.PreDeleteHook
+		*out = new(PreDeleteHookStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]ServiceInstanceCondition, len(*in))
@@ -383,12 +1460,109 @@ func (in *ServiceInstanceStatus) DeepCopy() *ServiceInstanceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceOperatorReport) DeepCopyInto(out *ServiceOperatorReport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceOperatorReport.
+func (in *ServiceOperatorReport) DeepCopy() *ServiceOperatorReport {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceOperatorReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceOperatorReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceOperatorReportList) DeepCopyInto(out *ServiceOperatorReportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ServiceOperatorReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceOperatorReportList.
+func (in *ServiceOperatorReportList) DeepCopy() *ServiceOperatorReportList {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceOperatorReportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceOperatorReportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceOperatorReportStatus) DeepCopyInto(out *ServiceOperatorReportStatus) {
+	*out = *in
+	if in.LastRefreshedAt != nil {
+		in, out := &in.LastRefreshedAt, &out.LastRefreshedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Foundations != nil {
+		in, out := &in.Foundations, &out.Foundations
+		*out = make([]FoundationReport, len(*in))
+		copy(*out, *in)
+	}
+	in.Spaces.DeepCopyInto(&out.Spaces)
+	in.ServiceInstances.DeepCopyInto(&out.ServiceInstances)
+	in.ServiceBindings.DeepCopyInto(&out.ServiceBindings)
+	if in.RecentFailures != nil {
+		in, out := &in.RecentFailures, &out.RecentFailures
+		*out = make([]ResourceFailure, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(CacheStatistics)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceOperatorReportStatus.
+func (in *ServiceOperatorReportStatus) DeepCopy() *ServiceOperatorReportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceOperatorReportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Space) DeepCopyInto(out *Space) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -429,6 +1603,87 @@ func (in *SpaceCondition) DeepCopy() *SpaceCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpaceConfigOverrides) DeepCopyInto(out *SpaceConfigOverrides) {
+	*out = *in
+	if in.ResourceCacheTimeout != nil {
+		in, out := &in.ResourceCacheTimeout, &out.ResourceCacheTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PollingIntervalReady != nil {
+		in, out := &in.PollingIntervalReady, &out.PollingIntervalReady
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PollingIntervalFail != nil {
+		in, out := &in.PollingIntervalFail, &out.PollingIntervalFail
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpaceConfigOverrides.
+func (in *SpaceConfigOverrides) DeepCopy() *SpaceConfigOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(SpaceConfigOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpaceCredentialRotation) DeepCopyInto(out *SpaceCredentialRotation) {
+	*out = *in
+	in.RotatedAt.DeepCopyInto(&out.RotatedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpaceCredentialRotation.
+func (in *SpaceCredentialRotation) DeepCopy() *SpaceCredentialRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(SpaceCredentialRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpaceHealthCheck) DeepCopyInto(out *SpaceHealthCheck) {
+	*out = *in
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = make([]SpaceHealthProbe, len(*in))
+		copy(*out, *in)
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.QuotaThreshold != nil {
+		in, out := &in.QuotaThreshold, &out.QuotaThreshold
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpaceHealthCheck.
+func (in *SpaceHealthCheck) DeepCopy() *SpaceHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(SpaceHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpaceList) DeepCopyInto(out *SpaceList) {
 	*out = *in
@@ -464,6 +1719,36 @@ func (in *SpaceList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpaceSpec) DeepCopyInto(out *SpaceSpec) {
 	*out = *in
+	if in.AuthSecretRef != nil {
+		in, out := &in.AuthSecretRef, &out.AuthSecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+	if in.Developers != nil {
+		in, out := &in.Developers, &out.Developers
+		*out = make([]SpaceUser, len(*in))
+		copy(*out, *in)
+	}
+	if in.Auditors != nil {
+		in, out := &in.Auditors, &out.Auditors
+		*out = make([]SpaceUser, len(*in))
+		copy(*out, *in)
+	}
+	if in.Managers != nil {
+		in, out := &in.Managers, &out.Managers
+		*out = make([]SpaceUser, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigOverrides != nil {
+		in, out := &in.ConfigOverrides, &out.ConfigOverrides
+		*out = new(SpaceConfigOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(SpaceHealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpaceSpec.
@@ -487,6 +1772,35 @@ func (in *SpaceStatus) DeepCopyInto(out *SpaceStatus) {
 		in, out := &in.LastModifiedAt, &out.LastModifiedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.Developers != nil {
+		in, out := &in.Developers, &out.Developers
+		*out = make([]SpaceUser, len(*in))
+		copy(*out, *in)
+	}
+	if in.Auditors != nil {
+		in, out := &in.Auditors, &out.Auditors
+		*out = make([]SpaceUser, len(*in))
+		copy(*out, *in)
+	}
+	if in.Managers != nil {
+		in, out := &in.Managers, &out.Managers
+		*out = make([]SpaceUser, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastCredentialRotation != nil {
+		in, out := &in.LastCredentialRotation, &out.LastCredentialRotation
+		*out = new(SpaceCredentialRotation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastHealthCheckAt != nil {
+		in, out := &in.LastHealthCheckAt, &out.LastHealthCheckAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(SpaceUsage)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]SpaceCondition, len(*in))
@@ -505,3 +1819,43 @@ func (in *SpaceStatus) DeepCopy() *SpaceStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpaceUsage) DeepCopyInto(out *SpaceUsage) {
+	*out = *in
+	if in.ServiceInstances != nil {
+		in, out := &in.ServiceInstances, &out.ServiceInstances
+		*out = new(int)
+		**out = **in
+	}
+	if in.ServiceInstancesLimit != nil {
+		in, out := &in.ServiceInstancesLimit, &out.ServiceInstancesLimit
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpaceUsage.
+func (in *SpaceUsage) DeepCopy() *SpaceUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(SpaceUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpaceUser) DeepCopyInto(out *SpaceUser) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpaceUser.
+func (in *SpaceUser) DeepCopy() *SpaceUser {
+	if in == nil {
+		return nil
+	}
+	out := new(SpaceUser)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,59 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Command btp-migrate converts SAP BTP service operator ServiceInstance and ServiceBinding objects
+(as obtained for example by kubectl get serviceinstances.services.cloud.sap.com -o yaml) into
+equivalent cf-service-operator objects, adopting the existing Cloud Foundry instances and bindings.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sap/cf-service-operator/internal/migration"
+)
+
+func main() {
+	var inputPath string
+	var options migration.Options
+	flag.StringVar(&inputPath, "f", "-", "File containing the SAP BTP service operator resources (YAML or JSON); - reads from stdin.")
+	flag.StringVar(&options.SpaceName, "space-name", "", "Name of the Space (in the namespace of each resource) referenced by the generated service instances.")
+	flag.StringVar(&options.ClusterSpaceName, "cluster-space-name", "", "Name of the ClusterSpace referenced by the generated service instances.")
+	flag.Parse()
+
+	if err := run(inputPath, options, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(inputPath string, options migration.Options, stdout io.Writer, stderr io.Writer) error {
+	input := os.Stdin
+	if inputPath != "-" {
+		file, err := os.Open(inputPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	objects, err := migration.ReadObjects(input)
+	if err != nil {
+		return err
+	}
+	result, err := migration.ConvertBTPObjects(objects, options)
+	if err != nil {
+		return err
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(stderr, "warning: %s\n", warning)
+	}
+	return migration.WriteObjects(stdout, result.Objects)
+}
@@ -0,0 +1,189 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Command cfso inspects the Cloud Foundry resources managed through cf-service-operator. If installed as
+kubectl-cf_service_operator somewhere in $PATH, it can be invoked as kubectl plugin (kubectl cf-service-operator).
+
+Usage:
+
+	cfso [flags] list      list all managed service instances and bindings
+	cfso [flags] orphans   list service instances and bindings whose owning object does not exist (anymore)
+	cfso [flags] adopt     print ServiceInstance and ServiceBinding manifests adopting the orphaned resources
+	cfso [flags] import GUID...
+	                       print ServiceInstance and ServiceBinding manifests adopting the given service instances and bindings
+	                       of the space identified by -space-guid (such as instances created manually)
+*/
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/sap/cf-service-operator/internal/cf"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+	"github.com/sap/cf-service-operator/internal/inventory"
+	"github.com/sap/cf-service-operator/internal/migration"
+	"github.com/sap/cf-service-operator/pkg/client/clientset/versioned"
+)
+
+func main() {
+	var kubeconfig string
+	var clusterResourceNamespace string
+	var namespace string
+	var spaceSecret string
+	var spaceGuid string
+	var spaceName string
+	var clusterSpaceName string
+	// a separate flag set is used, since imported packages register flags (such as -kubeconfig) with the default one
+	flags := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	flags.StringVar(&kubeconfig, "kubeconfig", "", "Path to the kubeconfig file; defaults to $KUBECONFIG resp. ~/.kube/config.")
+	flags.StringVar(&clusterResourceNamespace, "cluster-resource-namespace", "", "The namespace containing the secrets of ClusterSpace objects (as configured for the operator).")
+	flags.StringVar(&namespace, "namespace", "", "Namespace of generated objects adopting resources found through a ClusterSpace, and of imported resources; defaults to the namespace of the current kubeconfig context.")
+	flags.StringVar(&spaceSecret, "space-secret", "", "Secret ([namespace/]name) containing the credentials of the space of imported resources (import only).")
+	flags.StringVar(&spaceGuid, "space-guid", "", "Guid of the Cloud Foundry space of imported resources (import only).")
+	flags.StringVar(&spaceName, "space-name", "", "Name of the Space referenced by imported resources (import only).")
+	flags.StringVar(&clusterSpaceName, "cluster-space-name", "", "Name of the ClusterSpace referenced by imported resources (import only).")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s [flags] list|orphans|adopt|import GUID...\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	// errors are handled by the flag set (ExitOnError)
+	_ = flags.Parse(os.Args[1:])
+	if flags.NArg() != 1 && (flags.Arg(0) != "import" || flags.NArg() < 2) {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+	if namespace == "" {
+		contextNamespace, _, err := clientConfig.Namespace()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s\n", err)
+			os.Exit(1)
+		}
+		namespace = contextNamespace
+	}
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+
+	if flags.Arg(0) == "import" {
+		importer := &inventory.Importer{
+			SpaceGuid:        spaceGuid,
+			Namespace:        namespace,
+			SpaceName:        spaceName,
+			ClusterSpaceName: clusterSpaceName,
+		}
+		if err := runImport(context.Background(), importer, kubernetes.NewForConfigOrDie(restConfig), cf.NewSpaceClient, spaceSecret, flags.Args()[1:], namespace, os.Stdout, os.Stderr); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	collector := &inventory.Collector{
+		Clientset:                versioned.NewForConfigOrDie(restConfig),
+		KubeClient:               kubernetes.NewForConfigOrDie(restConfig),
+		ClusterResourceNamespace: clusterResourceNamespace,
+		ClientBuilder:            cf.NewSpaceClient,
+		Config:                   config.Defaults(),
+	}
+
+	if err := run(context.Background(), flags.Arg(0), collector, namespace, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, command string, collector *inventory.Collector, namespace string, stdout io.Writer, stderr io.Writer) error {
+	switch command {
+	case "list", "orphans", "adopt":
+	default:
+		return fmt.Errorf("unknown command: %s", command)
+	}
+
+	inv, err := collector.Collect(ctx)
+	if err != nil {
+		return err
+	}
+	for _, warning := range inv.Warnings {
+		fmt.Fprintf(stderr, "warning: %s\n", warning)
+	}
+
+	switch command {
+	case "list":
+		return writeResources(stdout, inv.Resources)
+	case "orphans":
+		return writeResources(stdout, inv.Orphans())
+	default:
+		objects, warnings := inventory.AdoptionManifests(inv, namespace)
+		for _, warning := range warnings {
+			fmt.Fprintf(stderr, "warning: %s\n", warning)
+		}
+		return migration.WriteObjects(stdout, objects)
+	}
+}
+
+func runImport(ctx context.Context, importer *inventory.Importer, kubeClient kubernetes.Interface, clientBuilder facade.SpaceClientBuilder, spaceSecret string, guids []string, namespace string, stdout io.Writer, stderr io.Writer) error {
+	if spaceSecret == "" || importer.SpaceGuid == "" {
+		return fmt.Errorf("flags -space-secret and -space-guid are required")
+	}
+	secretNamespace, secretName, found := strings.Cut(spaceSecret, "/")
+	if !found {
+		secretNamespace, secretName = namespace, spaceSecret
+	}
+	secret, err := kubeClient.CoreV1().Secrets(secretNamespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	cfg := config.Defaults().WithConnectionOverrides(string(secret.Data["ca.crt"]), string(secret.Data["proxy"]))
+	importer.SpaceClient, err = clientBuilder(importer.SpaceGuid, string(secret.Data["url"]), string(secret.Data["username"]), string(secret.Data["password"]), cfg)
+	if err != nil {
+		return err
+	}
+
+	objects, warnings, err := importer.ImportManifests(ctx, guids)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(stderr, "warning: %s\n", warning)
+	}
+	return migration.WriteObjects(stdout, objects)
+}
+
+func writeResources(w io.Writer, resources []inventory.Resource) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tSPACE\tNAME\tGUID\tSTATE\tOWNER")
+	for _, resource := range resources {
+		space := resource.Space.Kind + "/" + resource.Space.Name
+		if resource.Space.Namespace != "" {
+			space = resource.Space.Kind + "/" + resource.Space.Namespace + "/" + resource.Space.Name
+		}
+		owner := "<orphaned>"
+		if resource.OwnerObject != nil {
+			owner = resource.OwnerObject.String()
+		} else if resource.RecordedOwnerObject != nil {
+			owner = "<orphaned, was " + resource.RecordedOwnerObject.String() + ">"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", resource.Kind, space, resource.Name, resource.Guid, resource.State, owner)
+	}
+	return tw.Flush()
+}
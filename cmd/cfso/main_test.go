@@ -0,0 +1,76 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+	"github.com/sap/cf-service-operator/internal/facade/facadefakes"
+	"github.com/sap/cf-service-operator/internal/inventory"
+)
+
+func TestRunImport(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	kubeClient := kubefake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space-secret"},
+			Data:       map[string][]byte{"url": []byte("https://api.cf.example.com"), "username": []byte("user"), "password": []byte("pass")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "other-ns", Name: "space-secret"},
+			Data:       map[string][]byte{"url": []byte("https://api.other.example.com"), "username": []byte("other-user"), "password": []byte("other-pass")},
+		},
+	)
+	spaceClient := &facadefakes.FakeSpaceClient{}
+	spaceClient.GetInstanceStub = func(_ context.Context, instanceOpts map[string]string) (*facade.Instance, error) {
+		if instanceOpts["guid"] == "instance-guid" {
+			return &facade.Instance{Guid: "instance-guid", Name: "instance", ServicePlanGuid: "plan-guid"}, nil
+		}
+		return nil, nil
+	}
+	spaceClient.ListServicePlansReturns([]facade.ServicePlan{{Guid: "plan-guid", Name: "plan", ServiceOfferingName: "offering"}}, nil)
+	var builtFor []string
+	clientBuilder := func(spaceGuid string, url string, username string, password string, _ *config.Config) (facade.SpaceClient, error) {
+		builtFor = []string{spaceGuid, url, username, password}
+		return spaceClient, nil
+	}
+	newImporter := func() *inventory.Importer {
+		return &inventory.Importer{SpaceGuid: "space-guid", Namespace: "ns", SpaceName: "space"}
+	}
+
+	// the secret is looked up in the given namespace, unless qualified by a namespace
+	var stdout, stderr bytes.Buffer
+	g.Expect(runImport(ctx, newImporter(), kubeClient, clientBuilder, "space-secret", []string{"instance-guid", "unknown-guid"}, "ns", &stdout, &stderr)).To(Succeed())
+	g.Expect(builtFor).To(Equal([]string{"space-guid", "https://api.cf.example.com", "user", "pass"}))
+	g.Expect(stdout.String()).To(ContainSubstring("kind: ServiceInstance"))
+	g.Expect(stdout.String()).To(ContainSubstring("service-operator.cf.cs.sap.com/adopt-cf-instance-guid: instance-guid"))
+	g.Expect(stdout.String()).To(ContainSubstring("servicePlanName: plan"))
+	g.Expect(stderr.String()).To(HavePrefix("warning: skipping unknown-guid"))
+
+	stdout.Reset()
+	stderr.Reset()
+	g.Expect(runImport(ctx, newImporter(), kubeClient, clientBuilder, "other-ns/space-secret", []string{"instance-guid"}, "ns", &stdout, &stderr)).To(Succeed())
+	g.Expect(builtFor).To(Equal([]string{"space-guid", "https://api.other.example.com", "other-user", "other-pass"}))
+	g.Expect(stderr.String()).To(BeEmpty())
+
+	builtFor = nil
+	g.Expect(runImport(ctx, newImporter(), kubeClient, clientBuilder, "missing-secret", []string{"instance-guid"}, "ns", &stdout, &stderr)).To(MatchError(ContainSubstring("not found")))
+	g.Expect(runImport(ctx, newImporter(), kubeClient, clientBuilder, "", []string{"instance-guid"}, "ns", &stdout, &stderr)).To(MatchError(ContainSubstring("-space-secret and -space-guid are required")))
+	importer := newImporter()
+	importer.SpaceGuid = ""
+	g.Expect(runImport(ctx, importer, kubeClient, clientBuilder, "space-secret", []string{"instance-guid"}, "ns", &stdout, &stderr)).To(MatchError(ContainSubstring("-space-secret and -space-guid are required")))
+	g.Expect(builtFor).To(BeNil())
+}
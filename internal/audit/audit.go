@@ -0,0 +1,110 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package audit provides a log recording the mutating calls made against the Cloud Foundry API,
+// written as a stream of JSON objects (one per line).
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Result of a recorded call.
+type Result string
+
+const (
+	ResultSucceeded Result = "Succeeded"
+	ResultFailed    Result = "Failed"
+)
+
+// ObjectReference identifies the Kubernetes object on whose behalf a call was made.
+type ObjectReference struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// Record of a single call against the Cloud Foundry API.
+type Record struct {
+	// Time at which the call completed
+	Time time.Time `json:"time"`
+	// Operation (name of the client method, such as CreateInstance)
+	Operation string `json:"operation"`
+	// Kubernetes object on whose behalf the call was made (if known)
+	Object *ObjectReference `json:"object,omitempty"`
+	// UID of the owning Kubernetes object (as stored in the Cloud Foundry resource)
+	Owner string `json:"owner,omitempty"`
+	// GUID of the affected Cloud Foundry resource (empty for creations, unless the resource was created synchronously)
+	Guid string `json:"guid,omitempty"`
+	// Name of the Cloud Foundry organization (for calls concerning spaces)
+	OrganizationName string `json:"organization,omitempty"`
+	// GUID of the Cloud Foundry space (for calls concerning instances, bindings and routes)
+	SpaceGuid string `json:"spaceGuid,omitempty"`
+	// Name and origin of the Cloud Foundry user (for calls concerning space roles)
+	Username string `json:"username,omitempty"`
+	Origin   string `json:"origin,omitempty"`
+	// Result of the call
+	Result Result `json:"result"`
+	// Error returned by the call (if it failed)
+	Error string `json:"error,omitempty"`
+}
+
+type objectContextKey struct{}
+
+// WithObject returns a copy of the given context, attributing calls made with it to the given Kubernetes object.
+func WithObject(ctx context.Context, object ObjectReference) context.Context {
+	return context.WithValue(ctx, objectContextKey{}, object)
+}
+
+// ObjectFromContext returns the Kubernetes object stored in the given context (nil if there is none).
+func ObjectFromContext(ctx context.Context) *ObjectReference {
+	if object, ok := ctx.Value(objectContextKey{}).(ObjectReference); ok {
+		return &object
+	}
+	return nil
+}
+
+// Log writes records to an underlying writer; it is safe for concurrent use.
+// The zero value of *Log (nil) is a valid log which discards all records.
+type Log struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+// NewLog returns a log writing to the given writer.
+func NewLog(writer io.Writer) *Log {
+	return &Log{writer: writer}
+}
+
+// Record completes the given record (by the current time, and the object stored in the given context, unless set already),
+// and writes it as single line of JSON. Failures to write are logged, but not returned, since they must not affect the recorded call.
+func (l *Log) Record(ctx context.Context, record Record) {
+	if l == nil {
+		return
+	}
+	if record.Time.IsZero() {
+		record.Time = time.Now().UTC()
+	}
+	if record.Object == nil {
+		record.Object = ObjectFromContext(ctx)
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to encode audit record", "operation", record.Operation)
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if _, err := l.writer.Write(append(data, '\n')); err != nil {
+		log.FromContext(ctx).Error(err, "failed to write audit record", "operation", record.Operation)
+	}
+}
@@ -0,0 +1,58 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestAudit(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Audit Test Suite")
+}
+
+// -----------------------------------------------------------------------------------------------
+// Tests
+// -----------------------------------------------------------------------------------------------
+
+var _ = Describe("Log tests", func() {
+	It("should write one JSON object per record, completed by the object from the context", func() {
+		buffer := &bytes.Buffer{}
+		log := NewLog(buffer)
+		ctx := WithObject(context.Background(), ObjectReference{Kind: "ServiceInstance", Namespace: "default", Name: "instance"})
+
+		log.Record(ctx, Record{Operation: "DeleteInstance", Guid: "instance-guid", SpaceGuid: "space-guid", Result: ResultSucceeded})
+		log.Record(context.Background(), Record{Operation: "CreateSpace", OrganizationName: "org", Result: ResultFailed, Error: "name taken"})
+
+		lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+		Expect(lines).To(HaveLen(2))
+
+		record := Record{}
+		Expect(json.Unmarshal([]byte(lines[0]), &record)).To(Succeed())
+		Expect(record.Time).NotTo(BeZero())
+		Expect(record.Operation).To(Equal("DeleteInstance"))
+		Expect(record.Object).To(Equal(&ObjectReference{Kind: "ServiceInstance", Namespace: "default", Name: "instance"}))
+		Expect(record.Guid).To(Equal("instance-guid"))
+		Expect(record.Result).To(Equal(ResultSucceeded))
+
+		record = Record{}
+		Expect(json.Unmarshal([]byte(lines[1]), &record)).To(Succeed())
+		Expect(record.Object).To(BeNil())
+		Expect(record.Result).To(Equal(ResultFailed))
+		Expect(record.Error).To(Equal("name taken"))
+	})
+
+	It("should discard records if the log is nil", func() {
+		var log *Log
+		Expect(func() { log.Record(context.Background(), Record{Operation: "DeleteInstance"}) }).NotTo(Panic())
+	})
+})
@@ -8,6 +8,8 @@ package binding
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -37,7 +39,7 @@ func NewBinding(serviceInstance *v1alpha1.ServiceInstance, serviceBinding *v1alp
 			"type":          serviceInstance.Spec.ServiceOfferingName,
 			"label":         serviceInstance.Spec.ServiceOfferingName,
 			"plan":          serviceInstance.Spec.ServicePlanName,
-			"tags":          append([]string{serviceInstance.Spec.ServiceOfferingName}, serviceInstance.Spec.Tags...),
+			"tags":          Tags(serviceInstance),
 			"instance_name": serviceInstance.Spec.Name,
 			"instance_guid": serviceInstance.Status.ServiceInstanceGuid,
 		},
@@ -45,6 +47,62 @@ func NewBinding(serviceInstance *v1alpha1.ServiceInstance, serviceBinding *v1alp
 	}
 }
 
+// WithMetadataFields restricts the SAP binding metadata of the binding to the given fields; all fields are kept if fields is empty.
+func (binding *Binding) WithMetadataFields(fields []v1alpha1.SAPBindingMetadataField) *Binding {
+	if len(fields) == 0 {
+		return binding
+	}
+	metadata := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if v, ok := binding.metadata[string(field)]; ok {
+			metadata[string(field)] = v
+		}
+	}
+	binding.metadata = metadata
+	return binding
+}
+
+// Tags returns the effective tags of the given service instance, that is the name of the service offering, followed by the tags of the instance.
+func Tags(serviceInstance *v1alpha1.ServiceInstance) []string {
+	var tags []string
+	if serviceInstance.Spec.ServiceOfferingName != "" {
+		tags = append(tags, serviceInstance.Spec.ServiceOfferingName)
+	}
+	return append(tags, serviceInstance.Spec.Tags...)
+}
+
+// CredentialKeys returns the (sorted) top level keys of the given binding credentials.
+func CredentialKeys(credentials map[string]interface{}) []string {
+	keys := make([]string, 0, len(credentials))
+	for k := range credentials {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SelectCredentials returns the given binding credentials, restricted to the given top level keys;
+// all credentials are returned if keys is empty, and it is an error if some of the keys is missing.
+func SelectCredentials(credentials map[string]interface{}, keys []string) (map[string]interface{}, error) {
+	if len(keys) == 0 {
+		return credentials, nil
+	}
+	selected := make(map[string]interface{}, len(keys))
+	var missing []string
+	for _, k := range keys {
+		v, ok := credentials[k]
+		if !ok {
+			missing = append(missing, k)
+			continue
+		}
+		selected[k] = v
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("selected keys missing in binding credentials: %s", strings.Join(missing, ", "))
+	}
+	return selected, nil
+}
+
 func (binding *Binding) SecretData(secretKey string, withMetadata bool) (map[string][]byte, error) {
 	metadata := BindingMetadata{}
 	secretData := make(map[string][]byte)
@@ -96,6 +154,49 @@ func (binding *Binding) SecretData(secretKey string, withMetadata bool) (map[str
 	return secretData, nil
 }
 
+// AdditionalSecretData returns the data of an additional binding secret, holding the given keys of the binding credentials
+// in the given format; it is an error if some of the keys is missing.
+func AdditionalSecretData(credentials map[string]interface{}, keys []v1alpha1.BindingSecretKey, format v1alpha1.BindingSecretFormat) (map[string][]byte, error) {
+	selected := make(map[string]interface{}, len(keys))
+	var missing []string
+	for _, k := range keys {
+		v, ok := credentials[k.Key]
+		if !ok {
+			missing = append(missing, k.Key)
+			continue
+		}
+		name := k.SecretKey
+		if name == "" {
+			name = k.Key
+		}
+		if _, ok := selected[name]; ok {
+			return nil, fmt.Errorf("conflicting secret key: %s", name)
+		}
+		selected[name] = v
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("selected keys missing in binding credentials: %s", strings.Join(missing, ", "))
+	}
+
+	secretData := make(map[string][]byte)
+	if format == v1alpha1.BindingSecretFormatJSON {
+		w, err := json.Marshal(selected)
+		if err != nil {
+			return nil, errors.Wrap(err, "error encoding binding credentials")
+		}
+		secretData["credentials"] = w
+		return secretData, nil
+	}
+	for k, v := range selected {
+		w, _, err := encode(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error encoding binding credentials key: %s", k)
+		}
+		secretData[k] = w
+	}
+	return secretData, nil
+}
+
 func encode(v interface{}) ([]byte, string, error) {
 	if s, ok := v.(string); ok {
 		return []byte(s), "text", nil
@@ -28,6 +28,66 @@ var _ = Describe("Binding Test", func() {
 			Expect(binding.metadata["plan"]).To(Equal("plan"))
 			Expect(binding.metadata["instance_name"]).To(Equal("name"))
 			Expect(binding.metadata["instance_guid"]).To(Equal(""))
+			Expect(binding.metadata["tags"]).To(Equal([]string{"offering"}))
+		})
+
+		It("should report effective tags and credential keys", func() {
+			serviceInstance := &v1alpha1.ServiceInstance{
+				Spec: v1alpha1.ServiceInstanceSpec{
+					ServiceOfferingName: "offering",
+					Tags:                []string{"db", "postgres"},
+				},
+			}
+			Expect(Tags(serviceInstance)).To(Equal([]string{"offering", "db", "postgres"}))
+			Expect(CredentialKeys(map[string]interface{}{"username": "user", "password": "secret", "uri": "postgres://"})).To(Equal([]string{"password", "uri", "username"}))
+
+			// instances referencing their plan by guid do not know the offering name
+			Expect(Tags(&v1alpha1.ServiceInstance{Spec: v1alpha1.ServiceInstanceSpec{Tags: []string{"db"}}})).To(Equal([]string{"db"}))
+		})
+
+		It("should only add the selected metadata fields", func() {
+			serviceInstance := &v1alpha1.ServiceInstance{
+				Spec: v1alpha1.ServiceInstanceSpec{
+					Name:                "name",
+					ServiceOfferingName: "offering",
+					ServicePlanName:     "plan",
+				},
+			}
+			data, err := NewBinding(serviceInstance, nil, map[string]interface{}{"user": "admin"}).
+				WithMetadataFields([]v1alpha1.SAPBindingMetadataField{v1alpha1.SAPBindingMetadataFieldType, v1alpha1.SAPBindingMetadataFieldTags}).
+				SecretData("", true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).To(HaveLen(4))
+			Expect(data).To(HaveKeyWithValue("type", []byte("offering")))
+			Expect(data).To(HaveKeyWithValue("tags", []byte(`["offering"]`)))
+			Expect(data).To(HaveKeyWithValue("user", []byte("admin")))
+			Expect(data).To(HaveKey(".metadata"))
+			Expect(string(data[".metadata"])).ToNot(ContainSubstring("plan"))
+
+			data, err = NewBinding(serviceInstance, nil, map[string]interface{}{"user": "admin"}).WithMetadataFields(nil).SecretData("", true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).To(HaveLen(8))
+		})
+
+		It("should select credential keys", func() {
+			credentials := map[string]interface{}{"username": "user", "password": "secret", "certificate": "-----BEGIN CERTIFICATE-----"}
+			Expect(SelectCredentials(credentials, nil)).To(Equal(credentials))
+			Expect(SelectCredentials(credentials, []string{"username", "password"})).To(Equal(map[string]interface{}{"username": "user", "password": "secret"}))
+			_, err := SelectCredentials(credentials, []string{"username", "uri", "key"})
+			Expect(err).To(MatchError("selected keys missing in binding credentials: uri, key"))
+		})
+
+		It("should build additional secrets from selected credential keys", func() {
+			credentials := map[string]interface{}{"certificate": "cert", "key": "key", "uri": "postgres://db", "port": 5432}
+			keys := []v1alpha1.BindingSecretKey{{Key: "certificate", SecretKey: "tls.crt"}, {Key: "key", SecretKey: "tls.key"}}
+			Expect(AdditionalSecretData(credentials, keys, "")).To(Equal(map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")}))
+			Expect(AdditionalSecretData(credentials, []v1alpha1.BindingSecretKey{{Key: "uri"}, {Key: "port"}}, v1alpha1.BindingSecretFormatJSON)).
+				To(Equal(map[string][]byte{"credentials": []byte(`{"port":5432,"uri":"postgres://db"}`)}))
+
+			_, err := AdditionalSecretData(credentials, []v1alpha1.BindingSecretKey{{Key: "uri"}, {Key: "password"}}, "")
+			Expect(err).To(MatchError("selected keys missing in binding credentials: password"))
+			_, err = AdditionalSecretData(credentials, []v1alpha1.BindingSecretKey{{Key: "uri"}, {Key: "port", SecretKey: "uri"}}, "")
+			Expect(err).To(MatchError("conflicting secret key: uri"))
 		})
 	})
 
@@ -0,0 +1,145 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package binding
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/sap/cf-service-operator/api/v1alpha1"
+)
+
+const pushSecretStoreKindDefault = "SecretStore"
+
+var pushSecretGroupVersionKind = schema.GroupVersionKind{Group: "external-secrets.io", Version: "v1alpha1", Kind: "PushSecret"}
+
+// pushSecretSink maintains an external-secrets PushSecret (named as the binding secret, in the namespace of the binding),
+// which makes the external-secrets operator push the binding secret to the referenced secret store;
+// the binding secret itself is maintained by the caller. Every key of the data becomes a property of the remote secret.
+type pushSecretSink struct {
+	client         client.Client
+	scheme         *runtime.Scheme
+	serviceBinding *v1alpha1.ServiceBinding
+	secretName     string
+	storeName      string
+	storeKind      string
+	remoteKey      string
+}
+
+func (s *pushSecretSink) Store(ctx context.Context, data map[string][]byte) error {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	entries := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, map[string]interface{}{
+			"match": map[string]interface{}{
+				"secretKey": key,
+				"remoteRef": map[string]interface{}{
+					"remoteKey": s.remoteKey,
+					"property":  key,
+				},
+			},
+		})
+	}
+	spec := map[string]interface{}{
+		"deletionPolicy": "Delete",
+		"secretStoreRefs": []interface{}{
+			map[string]interface{}{
+				"name": s.storeName,
+				"kind": s.storeKind,
+			},
+		},
+		"selector": map[string]interface{}{
+			"secret": map[string]interface{}{
+				"name": s.secretName,
+			},
+		},
+		"data": entries,
+	}
+
+	pushSecret := s.newPushSecret()
+	if err := s.client.Get(ctx, types.NamespacedName{Namespace: s.serviceBinding.Namespace, Name: s.secretName}, pushSecret); err != nil {
+		if err := client.IgnoreNotFound(err); err != nil {
+			return errors.Wrap(err, "failed to read PushSecret")
+		}
+		pushSecret.SetNamespace(s.serviceBinding.Namespace)
+		pushSecret.SetName(s.secretName)
+		pushSecret.SetLabels(map[string]string{v1alpha1.LabelKeyServiceBinding: s.serviceBinding.Name})
+		if err := controllerutil.SetControllerReference(s.serviceBinding, pushSecret, s.scheme); err != nil {
+			return errors.Wrap(err, "failed to create PushSecret")
+		}
+		pushSecret.Object["spec"] = spec
+		if err := s.client.Create(ctx, pushSecret); err != nil {
+			return errors.Wrap(err, "failed to create PushSecret")
+		}
+		return nil
+	}
+
+	if !metav1.IsControlledBy(pushSecret, s.serviceBinding) {
+		return fmt.Errorf("failed to update PushSecret: PushSecret %s exists and is not owned by this binding", s.secretName)
+	}
+	// fields not managed here (e.g. defaulted by the external-secrets webhook) are retained
+	currentSpec, _, err := unstructured.NestedMap(pushSecret.Object, "spec")
+	if err != nil {
+		return errors.Wrap(err, "failed to read PushSecret")
+	}
+	if currentSpec == nil {
+		currentSpec = make(map[string]interface{})
+	}
+	desiredSpec := runtime.DeepCopyJSON(currentSpec)
+	for key, value := range spec {
+		desiredSpec[key] = value
+	}
+	if reflect.DeepEqual(currentSpec, desiredSpec) {
+		return nil
+	}
+	pushSecret.Object["spec"] = desiredSpec
+	if err := s.client.Update(ctx, pushSecret); err != nil {
+		return errors.Wrap(err, "failed to update PushSecret")
+	}
+	return nil
+}
+
+func (s *pushSecretSink) Delete(ctx context.Context) error {
+	pushSecret := s.newPushSecret()
+	if err := s.client.Get(ctx, types.NamespacedName{Namespace: s.serviceBinding.Namespace, Name: s.secretName}, pushSecret); err != nil {
+		if err := client.IgnoreNotFound(err); err != nil {
+			return errors.Wrap(err, "failed to read PushSecret")
+		}
+		return nil
+	}
+	if !metav1.IsControlledBy(pushSecret, s.serviceBinding) {
+		return nil
+	}
+	if err := s.client.Delete(ctx, pushSecret); client.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, "failed to delete PushSecret")
+	}
+	return nil
+}
+
+func (s *pushSecretSink) Location() string {
+	return fmt.Sprintf("%s %s/%s (through PushSecret %s)", s.storeKind, s.storeName, s.remoteKey, s.secretName)
+}
+
+func (s *pushSecretSink) newPushSecret() *unstructured.Unstructured {
+	pushSecret := &unstructured.Unstructured{}
+	pushSecret.SetGroupVersionKind(pushSecretGroupVersionKind)
+	return pushSecret
+}
@@ -0,0 +1,79 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package binding
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sap/cf-service-operator/api/v1alpha1"
+)
+
+// Sink stores the secret data of a binding (as returned by SecretData) in an external secret store.
+type Sink interface {
+	// Store writes the given data; called with every reconcile of a ready binding, so it should avoid writes if nothing changed.
+	Store(ctx context.Context, data map[string][]byte) error
+	// Delete removes the stored data; it is no error if there is nothing to delete.
+	Delete(ctx context.Context) error
+	// Location describes where the data is stored (for status messages).
+	Location() string
+}
+
+// NewSink returns the sink for the secret store referenced by spec.secretStoreRef of the given binding,
+// whose binding secret is named secretName; settings of the secret store are read through c.
+func NewSink(ctx context.Context, c client.Client, scheme *runtime.Scheme, serviceBinding *v1alpha1.ServiceBinding, secretName string) (Sink, error) {
+	ref := serviceBinding.Spec.SecretStoreRef
+	if ref == nil {
+		return nil, fmt.Errorf("no secret store specified")
+	}
+	storePath := ref.Path
+	if storePath == "" {
+		storePath = path.Join(serviceBinding.Namespace, secretName)
+	}
+
+	switch ref.Type {
+	case v1alpha1.SecretStoreTypeVault:
+		// the operator may write to Vault with its own identity; so every binding is confined to the prefix of its namespace
+		if ref.Path != "" {
+			if err := v1alpha1.ValidateSecretStorePath(ref.Path); err != nil {
+				return nil, err
+			}
+			storePath = path.Join(serviceBinding.Namespace, ref.Path)
+		}
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: serviceBinding.Namespace, Name: ref.Name}, secret); err != nil {
+			return nil, errors.Wrapf(err, "failed to get Secret containing Vault settings, secret name: %s", ref.Name)
+		}
+		config, err := newVaultConfig(secret.Data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid Vault settings in secret %s", ref.Name)
+		}
+		return newVaultSink(config, storePath)
+	case v1alpha1.SecretStoreTypePushSecret:
+		kind := ref.Kind
+		if kind == "" {
+			kind = pushSecretStoreKindDefault
+		}
+		return &pushSecretSink{
+			client:         c,
+			scheme:         scheme,
+			serviceBinding: serviceBinding,
+			secretName:     secretName,
+			storeName:      ref.Name,
+			storeKind:      kind,
+			remoteKey:      storePath,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported secret store type: %s", ref.Type)
+	}
+}
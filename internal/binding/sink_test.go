@@ -0,0 +1,182 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package binding
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/sap/cf-service-operator/api/v1alpha1"
+)
+
+var _ = Describe("Secret sinks", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+	var serviceBinding *v1alpha1.ServiceBinding
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		serviceBinding = &v1alpha1.ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "binding", UID: "binding-uid"},
+		}
+	})
+
+	Context("Vault", func() {
+		var server *ghttp.Server
+
+		BeforeEach(func() {
+			server = ghttp.NewServer()
+			DeferCleanup(server.Close)
+		})
+
+		It("should write the data with the configured token, unless unchanged", func() {
+			serviceBinding.Spec.SecretStoreRef = &v1alpha1.SecretStoreReference{Type: v1alpha1.SecretStoreTypeVault, Name: "vault"}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "vault"},
+				Data:       map[string][]byte{"address": []byte(server.URL()), "token": []byte("s.token"), "namespace": []byte("team")},
+			}).Build()
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest(http.MethodGet, "/v1/secret/data/app/binding-secret"),
+					ghttp.VerifyHeaderKV("X-Vault-Token", "s.token"),
+					ghttp.VerifyHeaderKV("X-Vault-Namespace", "team"),
+					ghttp.RespondWith(http.StatusNotFound, `{"errors":[]}`),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest(http.MethodPost, "/v1/secret/data/app/binding-secret"),
+					ghttp.VerifyJSON(`{"data":{"password":"secret","user":"admin"}}`),
+					ghttp.RespondWith(http.StatusOK, `{"data":{"version":1}}`),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest(http.MethodGet, "/v1/secret/data/app/binding-secret"),
+					ghttp.RespondWith(http.StatusOK, `{"data":{"data":{"password":"secret","user":"admin"}}}`),
+				),
+			)
+
+			sink, err := NewSink(ctx, c, scheme, serviceBinding, "binding-secret")
+			Expect(err).ToNot(HaveOccurred())
+			data := map[string][]byte{"user": []byte("admin"), "password": []byte("secret")}
+			Expect(sink.Store(ctx, data)).To(Succeed())
+			Expect(sink.Store(ctx, data)).To(Succeed())
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+			Expect(sink.Location()).To(Equal("Vault secret secret/app/binding-secret"))
+		})
+
+		It("should log in through the Kubernetes auth method, and delete all versions", func() {
+			sink, err := newVaultSink(&vaultConfig{address: server.URL(), role: "operator", authMount: "k8s", mount: "kv"}, "/custom/path/")
+			Expect(err).ToNot(HaveOccurred())
+			sink.readServiceAccountToken = func() ([]byte, error) { return []byte("sa-token"), nil }
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest(http.MethodPost, "/v1/auth/k8s/login"),
+					ghttp.VerifyJSON(`{"role":"operator","jwt":"sa-token"}`),
+					ghttp.RespondWith(http.StatusOK, `{"auth":{"client_token":"s.login"}}`),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest(http.MethodDelete, "/v1/kv/metadata/custom/path"),
+					ghttp.VerifyHeaderKV("X-Vault-Token", "s.login"),
+					ghttp.RespondWith(http.StatusNoContent, nil),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest(http.MethodPost, "/v1/auth/token/revoke-self"),
+					ghttp.VerifyHeaderKV("X-Vault-Token", "s.login"),
+					ghttp.RespondWith(http.StatusNoContent, nil),
+				),
+			)
+
+			Expect(sink.Delete(ctx)).To(Succeed())
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+		})
+
+		It("should confine custom paths to the namespace of the binding", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "vault"},
+				Data:       map[string][]byte{"address": []byte(server.URL()), "token": []byte("s.token")},
+			}).Build()
+			serviceBinding.Spec.SecretStoreRef = &v1alpha1.SecretStoreReference{Type: v1alpha1.SecretStoreTypeVault, Name: "vault", Path: "team/uaa"}
+			sink, err := NewSink(ctx, c, scheme, serviceBinding, "binding-secret")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sink.Location()).To(Equal("Vault secret secret/app/team/uaa"))
+
+			for _, path := range []string{"../other/uaa", "team/../../other", "/other/uaa", "team//uaa"} {
+				serviceBinding.Spec.SecretStoreRef.Path = path
+				_, err := NewSink(ctx, c, scheme, serviceBinding, "binding-secret")
+				Expect(err).To(HaveOccurred(), path)
+			}
+		})
+
+		It("should report errors returned by Vault", func() {
+			sink, err := newVaultSink(&vaultConfig{address: server.URL(), token: "s.token", mount: "secret"}, "app/binding-secret")
+			Expect(err).ToNot(HaveOccurred())
+			server.AppendHandlers(ghttp.RespondWith(http.StatusForbidden, `{"errors":["permission denied"]}`))
+
+			Expect(sink.Store(ctx, map[string][]byte{"user": []byte("admin")})).To(MatchError(ContainSubstring("permission denied")))
+		})
+
+		It("should reject incomplete settings", func() {
+			_, err := newVaultConfig(map[string][]byte{"address": []byte("https://vault.example.com")})
+			Expect(err).To(MatchError(ContainSubstring("token, role")))
+			_, err = newVaultConfig(map[string][]byte{"token": []byte("s.token")})
+			Expect(err).To(MatchError(ContainSubstring("address")))
+		})
+	})
+
+	Context("PushSecret", func() {
+		It("should maintain a PushSecret owned by the binding", func() {
+			serviceBinding.Spec.SecretStoreRef = &v1alpha1.SecretStoreReference{Type: v1alpha1.SecretStoreTypePushSecret, Name: "aws", Kind: "ClusterSecretStore"}
+			c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+			sink, err := NewSink(ctx, c, scheme, serviceBinding, "binding-secret")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sink.Store(ctx, map[string][]byte{"user": []byte("admin"), "password": []byte("secret")})).To(Succeed())
+
+			pushSecret := &unstructured.Unstructured{}
+			pushSecret.SetGroupVersionKind(pushSecretGroupVersionKind)
+			Expect(c.Get(ctx, types.NamespacedName{Namespace: "app", Name: "binding-secret"}, pushSecret)).To(Succeed())
+			Expect(metav1.IsControlledBy(pushSecret, serviceBinding)).To(BeTrue())
+			Expect(pushSecret.GetLabels()).To(HaveKeyWithValue(v1alpha1.LabelKeyServiceBinding, "binding"))
+			storeRefs, _, _ := unstructured.NestedSlice(pushSecret.Object, "spec", "secretStoreRefs")
+			Expect(storeRefs).To(ConsistOf(map[string]interface{}{"name": "aws", "kind": "ClusterSecretStore"}))
+			secretName, _, _ := unstructured.NestedString(pushSecret.Object, "spec", "selector", "secret", "name")
+			Expect(secretName).To(Equal("binding-secret"))
+			entries, _, _ := unstructured.NestedSlice(pushSecret.Object, "spec", "data")
+			Expect(entries).To(HaveLen(2))
+			Expect(entries[0]).To(HaveKeyWithValue("match", HaveKeyWithValue("secretKey", "password")))
+			Expect(entries[1]).To(HaveKeyWithValue("match", HaveKeyWithValue("remoteRef", map[string]interface{}{"remoteKey": "app/binding-secret", "property": "user"})))
+
+			Expect(sink.Delete(ctx)).To(Succeed())
+			Expect(c.Get(ctx, types.NamespacedName{Namespace: "app", Name: "binding-secret"}, pushSecret)).ToNot(Succeed())
+		})
+
+		It("should not touch foreign PushSecrets", func() {
+			serviceBinding.Spec.SecretStoreRef = &v1alpha1.SecretStoreReference{Type: v1alpha1.SecretStoreTypePushSecret, Name: "aws"}
+			foreign := &unstructured.Unstructured{}
+			foreign.SetGroupVersionKind(pushSecretGroupVersionKind)
+			foreign.SetNamespace("app")
+			foreign.SetName("binding-secret")
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(foreign).Build()
+
+			sink, err := NewSink(ctx, c, scheme, serviceBinding, "binding-secret")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sink.Store(ctx, map[string][]byte{"user": []byte("admin")})).To(MatchError(ContainSubstring("not owned by this binding")))
+			Expect(sink.Delete(ctx)).To(Succeed())
+			Expect(c.Get(ctx, types.NamespacedName{Namespace: "app", Name: "binding-secret"}, foreign)).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,242 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package binding
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	vaultDefaultMount     = "secret"
+	vaultDefaultAuthMount = "kubernetes"
+	vaultRequestTimeout   = 30 * time.Second
+	serviceAccountToken   = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// vaultConfig holds the connection settings of a Vault server, as read from the secret referenced by the binding.
+type vaultConfig struct {
+	// URL of the Vault server
+	address string
+	// Vault token; if empty, the operator logs in through the Kubernetes auth method, using role
+	token string
+	// Role used for the Kubernetes auth method
+	role string
+	// Mount path of the Kubernetes auth method
+	authMount string
+	// Mount path of the KV (version 2) secrets engine
+	mount string
+	// Vault (enterprise) namespace
+	namespace string
+	// PEM encoded CA certificates trusted when connecting to Vault
+	caBundle []byte
+}
+
+func newVaultConfig(data map[string][]byte) (*vaultConfig, error) {
+	config := &vaultConfig{
+		address:   string(data["address"]),
+		token:     string(data["token"]),
+		role:      string(data["role"]),
+		authMount: string(data["authMount"]),
+		mount:     string(data["mount"]),
+		namespace: string(data["namespace"]),
+		caBundle:  data["ca.crt"],
+	}
+	if config.address == "" {
+		return nil, fmt.Errorf("missing or empty key: address")
+	}
+	if u, err := url.Parse(config.address); err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid address: %s", config.address)
+	}
+	if (config.token == "") == (config.role == "") {
+		return nil, fmt.Errorf("exactly one of the keys token, role must be specified")
+	}
+	if config.authMount == "" {
+		config.authMount = vaultDefaultAuthMount
+	}
+	if config.mount == "" {
+		config.mount = vaultDefaultMount
+	}
+	return config, nil
+}
+
+// vaultSink stores binding secret data as a secret of a Vault KV (version 2) secrets engine;
+// every key of the data becomes a key of the Vault secret.
+type vaultSink struct {
+	config     *vaultConfig
+	path       string
+	httpClient *http.Client
+	// for testing
+	readServiceAccountToken func() ([]byte, error)
+}
+
+func newVaultSink(config *vaultConfig, path string) (*vaultSink, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(config.caBundle) > 0 {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(config.caBundle) {
+			return nil, fmt.Errorf("invalid Vault CA bundle: no PEM encoded certificates found")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
+	}
+	return &vaultSink{
+		config:     config,
+		path:       strings.Trim(path, "/"),
+		httpClient: &http.Client{Transport: transport, Timeout: vaultRequestTimeout},
+		readServiceAccountToken: func() ([]byte, error) {
+			return os.ReadFile(serviceAccountToken)
+		},
+	}, nil
+}
+
+func (s *vaultSink) Store(ctx context.Context, data map[string][]byte) error {
+	token, err := s.login(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.logout(ctx, token)
+	values := make(map[string]string, len(data))
+	for key, value := range data {
+		values[key] = string(value)
+	}
+
+	// every write creates a new version of the Vault secret; so unchanged data is not written again
+	var current struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	found, err := s.do(ctx, token, http.MethodGet, "/v1/"+s.config.mount+"/data/"+s.path, nil, &current)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read Vault secret %s", s.Location())
+	}
+	if found && maps.Equal(current.Data.Data, values) {
+		return nil
+	}
+	if _, err := s.do(ctx, token, http.MethodPost, "/v1/"+s.config.mount+"/data/"+s.path, map[string]interface{}{"data": values}, nil); err != nil {
+		return errors.Wrapf(err, "failed to write Vault secret %s", s.Location())
+	}
+	return nil
+}
+
+func (s *vaultSink) Delete(ctx context.Context) error {
+	token, err := s.login(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.logout(ctx, token)
+	// deleting the metadata removes all versions of the secret
+	if _, err := s.do(ctx, token, http.MethodDelete, "/v1/"+s.config.mount+"/metadata/"+s.path, nil, nil); err != nil {
+		return errors.Wrapf(err, "failed to delete Vault secret %s", s.Location())
+	}
+	return nil
+}
+
+func (s *vaultSink) Location() string {
+	return fmt.Sprintf("Vault secret %s/%s", s.config.mount, s.path)
+}
+
+// login returns the configured token, or logs in through the Kubernetes auth method (with the service account token of the operator).
+func (s *vaultSink) login(ctx context.Context) (string, error) {
+	if s.config.token != "" {
+		return s.config.token, nil
+	}
+	jwt, err := s.readServiceAccountToken()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read service account token for Vault login")
+	}
+	var response struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	if _, err := s.do(ctx, "", http.MethodPost, "/v1/auth/"+s.config.authMount+"/login", map[string]interface{}{"role": s.config.role, "jwt": string(jwt)}, &response); err != nil {
+		return "", errors.Wrapf(err, "failed to log in to Vault with role %s", s.config.role)
+	}
+	if response.Auth.ClientToken == "" {
+		return "", fmt.Errorf("failed to log in to Vault with role %s: no token returned", s.config.role)
+	}
+	return response.Auth.ClientToken, nil
+}
+
+// logout revokes the given token, if it was obtained by login (the configured token is kept); failures are ignored,
+// since the token expires anyway.
+func (s *vaultSink) logout(ctx context.Context, token string) {
+	if s.config.token != "" {
+		return
+	}
+	_, _ = s.do(ctx, token, http.MethodPost, "/v1/auth/token/revoke-self", nil, nil)
+}
+
+// do sends a request to the Vault API, and decodes the response into result (if not nil);
+// returns false (and no error) if the server responded with 404 Not Found.
+func (s *vaultSink) do(ctx context.Context, token string, method string, path string, body interface{}, result interface{}) (bool, error) {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return false, err
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(s.config.address, "/")+path, reader)
+	if err != nil {
+		return false, err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if s.config.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", s.config.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode >= 300 {
+		var vaultError struct {
+			Errors []string `json:"errors"`
+		}
+		if json.Unmarshal(raw, &vaultError) == nil && len(vaultError.Errors) > 0 {
+			return false, fmt.Errorf("vault responded with status %d: %s", resp.StatusCode, strings.Join(vaultError.Errors, "; "))
+		}
+		return false, fmt.Errorf("vault responded with status %d", resp.StatusCode)
+	}
+	if result != nil && len(raw) > 0 {
+		if err := json.Unmarshal(raw, result); err != nil {
+			return false, errors.Wrap(err, "failed to decode Vault response")
+		}
+	}
+	return true, nil
+}
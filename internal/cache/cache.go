@@ -0,0 +1,205 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package cache provides a generic, thread-safe in-memory cache with time-based expiration.
+*/
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache is a thread-safe key-value store whose entries expire after a fixed time-to-live.
+// The zero value is not usable; instances must be created through New().
+type Cache[V any] struct {
+	mutex     sync.Mutex
+	ttl       time.Duration
+	now       func() time.Time
+	entries   map[string]entry[V]
+	nextPrune time.Time
+	onExpire  func(key string)
+}
+
+// Option configures optional behavior of a cache.
+type Option func(*options)
+
+type options struct {
+	onExpire func(key string)
+}
+
+// WithExpirationHandler registers a function which is called (with the cache locked) whenever an expired entry is removed.
+func WithExpirationHandler(onExpire func(key string)) Option {
+	return func(o *options) {
+		o.onExpire = onExpire
+	}
+}
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// New creates an empty cache whose entries expire after the given time-to-live.
+// A non-positive ttl means that entries never expire.
+func New[V any](ttl time.Duration, opts ...Option) *Cache[V] {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &Cache[V]{
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  make(map[string]entry[V]),
+		onExpire: o.onExpire,
+	}
+}
+
+// Get returns the value stored for key, and whether a non-expired entry was found.
+// Expired entries are removed on access.
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	if c.isExpired(e) {
+		c.expire(key)
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores value for key, replacing an existing entry and resetting its expiration.
+// Expired entries of other keys are removed from time to time (at most once per time-to-live).
+func (c *Cache[V]) Set(key string, value V) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e := entry[V]{value: value}
+	if c.ttl > 0 {
+		now := c.now()
+		e.expiresAt = now.Add(c.ttl)
+		if !now.Before(c.nextPrune) {
+			c.prune()
+			c.nextPrune = now.Add(c.ttl)
+		}
+	}
+	c.entries[key] = e
+}
+
+// SetTTL changes the time-to-live of entries stored afterwards. Existing entries keep their expiration, unless it is later
+// than the expiration according to the new time-to-live (such that a shortened time-to-live takes effect immediately).
+func (c *Cache[V]) SetTTL(ttl time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.ttl = ttl
+	if ttl <= 0 {
+		return
+	}
+	latest := c.now().Add(ttl)
+	for key, e := range c.entries {
+		if e.expiresAt.IsZero() || e.expiresAt.After(latest) {
+			e.expiresAt = latest
+			c.entries[key] = e
+		}
+	}
+	if c.nextPrune.After(latest) {
+		c.nextPrune = latest
+	}
+}
+
+// Delete removes the entry for key (if existing).
+func (c *Cache[V]) Delete(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.entries, key)
+}
+
+// DeleteFunc removes all entries for which the given function returns true.
+func (c *Cache[V]) DeleteFunc(del func(key string, value V) bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key, e := range c.entries {
+		if del(key, e.value) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Update replaces the values of all entries for which the given function returns true (as second return value),
+// by the value returned by the function; the expiration of updated entries is not changed.
+func (c *Cache[V]) Update(update func(key string, value V) (V, bool)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key, e := range c.entries {
+		if value, ok := update(key, e.value); ok {
+			e.value = value
+			c.entries[key] = e
+		}
+	}
+}
+
+// Clear removes all entries.
+func (c *Cache[V]) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries = make(map[string]entry[V])
+}
+
+// Len returns the number of non-expired entries.
+func (c *Cache[V]) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	n := 0
+	for _, e := range c.entries {
+		if !c.isExpired(e) {
+			n++
+		}
+	}
+	return n
+}
+
+// Range calls the given function for every non-expired entry (with the cache locked, so the function must not access the cache).
+func (c *Cache[V]) Range(f func(key string, value V)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key, e := range c.entries {
+		if !c.isExpired(e) {
+			f(key, e.value)
+		}
+	}
+}
+
+func (c *Cache[V]) prune() {
+	for key, e := range c.entries {
+		if c.isExpired(e) {
+			c.expire(key)
+		}
+	}
+}
+
+func (c *Cache[V]) expire(key string) {
+	delete(c.entries, key)
+	if c.onExpire != nil {
+		c.onExpire(key)
+	}
+}
+
+func (c *Cache[V]) isExpired(e entry[V]) bool {
+	return !e.expiresAt.IsZero() && !c.now().Before(e.expiresAt)
+}
@@ -0,0 +1,157 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCache(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cache Test Suite")
+}
+
+// -----------------------------------------------------------------------------------------------
+// Tests
+// -----------------------------------------------------------------------------------------------
+
+var _ = Describe("Cache tests", func() {
+	var c *Cache[string]
+	var now time.Time
+
+	BeforeEach(func() {
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		c = New[string](time.Minute)
+		c.now = func() time.Time { return now }
+	})
+
+	It("should return stored values", func() {
+		c.Set("a", "1")
+		value, ok := c.Get("a")
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal("1"))
+
+		_, ok = c.Get("b")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should expire entries after the time-to-live", func() {
+		c.Set("a", "1")
+		now = now.Add(59 * time.Second)
+		Expect(c.Len()).To(Equal(1))
+
+		now = now.Add(time.Second)
+		_, ok := c.Get("a")
+		Expect(ok).To(BeFalse())
+		Expect(c.Len()).To(Equal(0))
+	})
+
+	It("should remove expired entries when setting other entries", func() {
+		c.Set("a", "1")
+		now = now.Add(time.Minute)
+		c.Set("b", "2")
+		Expect(c.entries).To(HaveLen(1))
+		Expect(c.entries).To(HaveKey("b"))
+	})
+
+	It("should change the time-to-live, keeping existing entries", func() {
+		c.Set("a", "1")
+		c.SetTTL(time.Hour)
+		c.Set("b", "2")
+		now = now.Add(time.Minute)
+		// existing entries keep their expiration if the time-to-live is extended
+		_, ok := c.Get("a")
+		Expect(ok).To(BeFalse())
+		_, ok = c.Get("b")
+		Expect(ok).To(BeTrue())
+
+		// a shortened time-to-live takes effect immediately
+		c.SetTTL(time.Second)
+		now = now.Add(time.Second)
+		_, ok = c.Get("b")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should call the expiration handler for expired entries", func() {
+		var expired []string
+		c = New[string](time.Minute, WithExpirationHandler(func(key string) { expired = append(expired, key) }))
+		c.now = func() time.Time { return now }
+		c.Set("a", "1")
+		c.Set("b", "2")
+		now = now.Add(time.Minute)
+		_, ok := c.Get("a")
+		Expect(ok).To(BeFalse())
+		Expect(expired).To(ConsistOf("a"))
+		c.Set("c", "3")
+		Expect(expired).To(ConsistOf("a", "b"))
+	})
+
+	It("should never expire entries without time-to-live", func() {
+		c = New[string](0)
+		c.Set("a", "1")
+		_, ok := c.Get("a")
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should delete entries", func() {
+		c.Set("a", "1")
+		c.Set("b", "2")
+		c.Set("c", "2")
+
+		c.Delete("a")
+		_, ok := c.Get("a")
+		Expect(ok).To(BeFalse())
+
+		c.DeleteFunc(func(key string, value string) bool { return value == "2" })
+		Expect(c.Len()).To(Equal(0))
+
+		c.Set("d", "4")
+		c.Clear()
+		Expect(c.Len()).To(Equal(0))
+	})
+
+	It("should update entries", func() {
+		c.Set("a", "1")
+		c.Set("b", "2")
+		c.Update(func(key string, value string) (string, bool) { return value + "0", key == "a" })
+		value, _ := c.Get("a")
+		Expect(value).To(Equal("10"))
+		value, _ = c.Get("b")
+		Expect(value).To(Equal("2"))
+	})
+
+	It("should iterate over non-expired entries", func() {
+		c.Set("a", "1")
+		now = now.Add(time.Minute)
+		c.Set("b", "2")
+		entries := make(map[string]string)
+		c.Range(func(key string, value string) { entries[key] = value })
+		Expect(entries).To(Equal(map[string]string{"b": "2"}))
+	})
+
+	It("should be safe for concurrent use", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				defer GinkgoRecover()
+				key := fmt.Sprintf("key-%d", i)
+				c.Set(key, key)
+				value, ok := c.Get(key)
+				Expect(ok).To(BeTrue())
+				Expect(value).To(Equal(key))
+			}(i)
+		}
+		wg.Wait()
+		Expect(c.Len()).To(Equal(10))
+	})
+})
@@ -0,0 +1,31 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cf
+
+import (
+	"context"
+	"fmt"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient/v3/client"
+)
+
+// FindApp returns the guid of the application with the given name in the client's space;
+// an error is returned if there is no such application.
+func (c *spaceClient) FindApp(ctx context.Context, name string) (string, error) {
+	listOpts := cfclient.NewAppListOptions()
+	listOpts.Names.EqualTo(name)
+	listOpts.SpaceGUIDs.EqualTo(c.spaceGuid)
+	apps, err := c.client.Applications.ListAll(ctx, listOpts)
+	if err != nil {
+		return "", fmt.Errorf("failed to list applications: %w", err)
+	}
+	if len(apps) == 0 {
+		return "", fmt.Errorf("application not found in space %s, name: %s", c.spaceGuid, name)
+	} else if len(apps) > 1 {
+		return "", fmt.Errorf("found multiple applications with name: %s", name)
+	}
+	return apps[0].GUID, nil
+}
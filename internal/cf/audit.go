@@ -0,0 +1,184 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cf
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/sap/cf-service-operator/internal/audit"
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+// log into which the mutating calls of all clients are recorded
+var auditLog atomic.Pointer[audit.Log]
+
+// SetAuditLog sets the log into which all clients record their mutating calls against the Cloud Foundry API; nil disables recording.
+func SetAuditLog(log *audit.Log) {
+	auditLog.Store(log)
+}
+
+func recordAudit(ctx context.Context, record audit.Record, err error) {
+	record.Result = audit.ResultSucceeded
+	if err != nil {
+		record.Result = audit.ResultFailed
+		record.Error = err.Error()
+	}
+	auditLog.Load().Record(ctx, record)
+}
+
+// auditingOrganizationClient records the mutating space and space role calls of the wrapped organization client in the audit log;
+// all other calls are passed through.
+type auditingOrganizationClient struct {
+	facade.OrganizationClient
+	organizationName string
+}
+
+func (c *auditingOrganizationClient) record(ctx context.Context, operation string, guid string, owner facade.OwnerRef, err error) {
+	recordAudit(ctx, audit.Record{Operation: operation, Owner: owner.UID, Guid: guid, OrganizationName: c.organizationName}, err)
+}
+
+func (c *auditingOrganizationClient) CreateSpace(ctx context.Context, name string, owner facade.OwnerRef, generation int64) (space *facade.Space, err error) {
+	defer func() {
+		guid := ""
+		if space != nil {
+			guid = space.Guid
+		}
+		c.record(ctx, "CreateSpace", guid, owner, err)
+	}()
+	return c.OrganizationClient.CreateSpace(ctx, name, owner, generation)
+}
+
+func (c *auditingOrganizationClient) UpdateSpace(ctx context.Context, guid string, owner facade.OwnerRef, name string, generation int64) (err error) {
+	defer func() { c.record(ctx, "UpdateSpace", guid, owner, err) }()
+	return c.OrganizationClient.UpdateSpace(ctx, guid, owner, name, generation)
+}
+
+func (c *auditingOrganizationClient) DeleteSpace(ctx context.Context, guid string, owner facade.OwnerRef) (err error) {
+	defer func() { c.record(ctx, "DeleteSpace", guid, owner, err) }()
+	return c.OrganizationClient.DeleteSpace(ctx, guid, owner)
+}
+
+func (c *auditingOrganizationClient) recordRole(ctx context.Context, operation string, guid string, username string, origin string, err error) {
+	recordAudit(ctx, audit.Record{Operation: operation, Guid: guid, OrganizationName: c.organizationName, Username: username, Origin: origin}, err)
+}
+
+func (c *auditingOrganizationClient) AddAuditor(ctx context.Context, guid string, username string, origin string) (err error) {
+	defer func() { c.recordRole(ctx, "AddAuditor", guid, username, origin, err) }()
+	return c.OrganizationClient.AddAuditor(ctx, guid, username, origin)
+}
+
+func (c *auditingOrganizationClient) AddDeveloper(ctx context.Context, guid string, username string, origin string) (err error) {
+	defer func() { c.recordRole(ctx, "AddDeveloper", guid, username, origin, err) }()
+	return c.OrganizationClient.AddDeveloper(ctx, guid, username, origin)
+}
+
+func (c *auditingOrganizationClient) AddManager(ctx context.Context, guid string, username string, origin string) (err error) {
+	defer func() { c.recordRole(ctx, "AddManager", guid, username, origin, err) }()
+	return c.OrganizationClient.AddManager(ctx, guid, username, origin)
+}
+
+func (c *auditingOrganizationClient) RemoveAuditor(ctx context.Context, guid string, username string, origin string) (err error) {
+	defer func() { c.recordRole(ctx, "RemoveAuditor", guid, username, origin, err) }()
+	return c.OrganizationClient.RemoveAuditor(ctx, guid, username, origin)
+}
+
+func (c *auditingOrganizationClient) RemoveDeveloper(ctx context.Context, guid string, username string, origin string) (err error) {
+	defer func() { c.recordRole(ctx, "RemoveDeveloper", guid, username, origin, err) }()
+	return c.OrganizationClient.RemoveDeveloper(ctx, guid, username, origin)
+}
+
+func (c *auditingOrganizationClient) RemoveManager(ctx context.Context, guid string, username string, origin string) (err error) {
+	defer func() { c.recordRole(ctx, "RemoveManager", guid, username, origin, err) }()
+	return c.OrganizationClient.RemoveManager(ctx, guid, username, origin)
+}
+
+// auditingSpaceClient records the mutating instance, binding, route and route binding calls of the wrapped space client in the audit log;
+// all other calls are passed through.
+type auditingSpaceClient struct {
+	facade.SpaceClient
+	spaceGuid string
+}
+
+func (c *auditingSpaceClient) record(ctx context.Context, operation string, guid string, owner facade.OwnerRef, err error) {
+	recordAudit(ctx, audit.Record{Operation: operation, Owner: owner.UID, Guid: guid, SpaceGuid: c.spaceGuid}, err)
+}
+
+func (c *auditingSpaceClient) CreateInstance(ctx context.Context, name string, servicePlanGuid string, parameters map[string]interface{}, tags []string, metadata *facade.Metadata, owner facade.OwnerRef, generation int64) (instance *facade.Instance, err error) {
+	defer func() { c.record(ctx, "CreateInstance", "", owner, err) }()
+	return c.SpaceClient.CreateInstance(ctx, name, servicePlanGuid, parameters, tags, metadata, owner, generation)
+}
+
+func (c *auditingSpaceClient) UpdateInstance(ctx context.Context, guid string, owner facade.OwnerRef, name string, servicePlanGuid string, parameters map[string]interface{}, tags []string, metadata *facade.Metadata, generation int64) (err error) {
+	defer func() { c.record(ctx, "UpdateInstance", guid, owner, err) }()
+	return c.SpaceClient.UpdateInstance(ctx, guid, owner, name, servicePlanGuid, parameters, tags, metadata, generation)
+}
+
+func (c *auditingSpaceClient) UpgradeInstance(ctx context.Context, guid string, owner facade.OwnerRef, maintenanceInfo facade.MaintenanceInfo) (err error) {
+	defer func() { c.record(ctx, "UpgradeInstance", guid, owner, err) }()
+	return c.SpaceClient.UpgradeInstance(ctx, guid, owner, maintenanceInfo)
+}
+
+func (c *auditingSpaceClient) DeleteInstance(ctx context.Context, guid string, owner facade.OwnerRef) (err error) {
+	defer func() { c.record(ctx, "DeleteInstance", guid, owner, err) }()
+	return c.SpaceClient.DeleteInstance(ctx, guid, owner)
+}
+
+func (c *auditingSpaceClient) CreateBinding(ctx context.Context, name string, serviceInstanceGuid string, appGuid string, parameters map[string]interface{}, metadata *facade.Metadata, owner facade.OwnerRef, generation int64) (binding *facade.Binding, err error) {
+	defer func() {
+		guid := ""
+		if binding != nil {
+			guid = binding.Guid
+		}
+		c.record(ctx, "CreateBinding", guid, owner, err)
+	}()
+	return c.SpaceClient.CreateBinding(ctx, name, serviceInstanceGuid, appGuid, parameters, metadata, owner, generation)
+}
+
+func (c *auditingSpaceClient) UpdateBinding(ctx context.Context, guid string, owner facade.OwnerRef, generation int64, parameters map[string]interface{}, metadata *facade.Metadata) (err error) {
+	defer func() { c.record(ctx, "UpdateBinding", guid, owner, err) }()
+	return c.SpaceClient.UpdateBinding(ctx, guid, owner, generation, parameters, metadata)
+}
+
+func (c *auditingSpaceClient) DeleteBinding(ctx context.Context, guid string, owner facade.OwnerRef) (err error) {
+	defer func() { c.record(ctx, "DeleteBinding", guid, owner, err) }()
+	return c.SpaceClient.DeleteBinding(ctx, guid, owner)
+}
+
+func (c *auditingSpaceClient) PromoteBinding(ctx context.Context, guid string, owner facade.OwnerRef) (err error) {
+	defer func() { c.record(ctx, "PromoteBinding", guid, owner, err) }()
+	return c.SpaceClient.PromoteBinding(ctx, guid, owner)
+}
+
+func (c *auditingSpaceClient) CreateRoute(ctx context.Context, domainGuid string, host string, path string, owner string, generation int64) (err error) {
+	defer func() { c.record(ctx, "CreateRoute", "", facade.OwnerRef{UID: owner}, err) }()
+	return c.SpaceClient.CreateRoute(ctx, domainGuid, host, path, owner, generation)
+}
+
+func (c *auditingSpaceClient) UpdateRoute(ctx context.Context, guid string, generation int64) (err error) {
+	defer func() { c.record(ctx, "UpdateRoute", guid, facade.OwnerRef{}, err) }()
+	return c.SpaceClient.UpdateRoute(ctx, guid, generation)
+}
+
+func (c *auditingSpaceClient) DeleteRoute(ctx context.Context, guid string) (err error) {
+	defer func() { c.record(ctx, "DeleteRoute", guid, facade.OwnerRef{}, err) }()
+	return c.SpaceClient.DeleteRoute(ctx, guid)
+}
+
+func (c *auditingSpaceClient) CreateRouteBinding(ctx context.Context, routeGuid string, serviceInstanceGuid string, parameters map[string]interface{}, owner string, generation int64) (err error) {
+	defer func() { c.record(ctx, "CreateRouteBinding", "", facade.OwnerRef{UID: owner}, err) }()
+	return c.SpaceClient.CreateRouteBinding(ctx, routeGuid, serviceInstanceGuid, parameters, owner, generation)
+}
+
+func (c *auditingSpaceClient) UpdateRouteBinding(ctx context.Context, guid string, generation int64) (err error) {
+	defer func() { c.record(ctx, "UpdateRouteBinding", guid, facade.OwnerRef{}, err) }()
+	return c.SpaceClient.UpdateRouteBinding(ctx, guid, generation)
+}
+
+func (c *auditingSpaceClient) DeleteRouteBinding(ctx context.Context, guid string) (err error) {
+	defer func() { c.record(ctx, "DeleteRouteBinding", guid, facade.OwnerRef{}, err) }()
+	return c.SpaceClient.DeleteRouteBinding(ctx, guid)
+}
@@ -0,0 +1,141 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package cf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sap/cf-service-operator/internal/audit"
+	"github.com/sap/cf-service-operator/internal/facade"
+	"github.com/sap/cf-service-operator/internal/facade/facadefakes"
+)
+
+var _ = Describe("Audit tests", func() {
+	var buffer *bytes.Buffer
+
+	BeforeEach(func() {
+		buffer = &bytes.Buffer{}
+		SetAuditLog(audit.NewLog(buffer))
+		DeferCleanup(func() {
+			SetAuditLog(nil)
+		})
+	})
+
+	readRecords := func() []audit.Record {
+		var records []audit.Record
+		for _, line := range strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n") {
+			record := audit.Record{}
+			Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+			records = append(records, record)
+		}
+		return records
+	}
+
+	It("should record mutating space client calls, but not read calls", func() {
+		fakeClient := &facadefakes.FakeSpaceClient{}
+		fakeClient.DeleteInstanceReturns(errors.New("instance is in use"))
+		client := &auditingSpaceClient{SpaceClient: fakeClient, spaceGuid: "space-guid"}
+		ctx := audit.WithObject(context.Background(), audit.ObjectReference{Kind: "ServiceInstance", Namespace: "default", Name: "instance"})
+
+		_, err := client.GetInstance(ctx, map[string]string{"owner": "instance-uid"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.UpdateInstance(ctx, "instance-guid", facade.OwnerRef{UID: "instance-uid"}, "instance", "", nil, nil, nil, 2)).To(Succeed())
+		Expect(client.DeleteInstance(ctx, "instance-guid", facade.OwnerRef{UID: "instance-uid"})).To(MatchError("instance is in use"))
+
+		records := readRecords()
+		Expect(records).To(HaveLen(2))
+		Expect(records[0].Operation).To(Equal("UpdateInstance"))
+		Expect(records[0].Object).To(Equal(&audit.ObjectReference{Kind: "ServiceInstance", Namespace: "default", Name: "instance"}))
+		Expect(records[0].Owner).To(Equal("instance-uid"))
+		Expect(records[0].Guid).To(Equal("instance-guid"))
+		Expect(records[0].SpaceGuid).To(Equal("space-guid"))
+		Expect(records[0].Result).To(Equal(audit.ResultSucceeded))
+		Expect(records[1].Operation).To(Equal("DeleteInstance"))
+		Expect(records[1].Result).To(Equal(audit.ResultFailed))
+		Expect(records[1].Error).To(Equal("instance is in use"))
+	})
+
+	It("should record mutating route and route binding calls", func() {
+		fakeClient := &facadefakes.FakeSpaceClient{}
+		client := &auditingSpaceClient{SpaceClient: fakeClient, spaceGuid: "space-guid"}
+		ctx := context.Background()
+
+		_, err := client.GetRoute(ctx, "route-uid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.CreateRoute(ctx, "domain-guid", "host", "", "route-uid", 1)).To(Succeed())
+		Expect(client.UpdateRoute(ctx, "route-guid", 2)).To(Succeed())
+		Expect(client.DeleteRoute(ctx, "route-guid")).To(Succeed())
+		Expect(client.CreateRouteBinding(ctx, "route-guid", "instance-guid", nil, "route-binding-uid", 1)).To(Succeed())
+		Expect(client.UpdateRouteBinding(ctx, "route-binding-guid", 2)).To(Succeed())
+		Expect(client.DeleteRouteBinding(ctx, "route-binding-guid")).To(Succeed())
+
+		records := readRecords()
+		Expect(records).To(HaveLen(6))
+		var operations []string
+		for _, record := range records {
+			operations = append(operations, record.Operation)
+			Expect(record.SpaceGuid).To(Equal("space-guid"))
+			Expect(record.Result).To(Equal(audit.ResultSucceeded))
+		}
+		Expect(operations).To(Equal([]string{"CreateRoute", "UpdateRoute", "DeleteRoute", "CreateRouteBinding", "UpdateRouteBinding", "DeleteRouteBinding"}))
+		Expect(records[0].Owner).To(Equal("route-uid"))
+		Expect(records[1].Guid).To(Equal("route-guid"))
+		Expect(records[3].Owner).To(Equal("route-binding-uid"))
+		Expect(records[5].Guid).To(Equal("route-binding-guid"))
+		Expect(fakeClient.DeleteRouteBindingCallCount()).To(Equal(1))
+	})
+
+	It("should record mutating organization client calls", func() {
+		fakeClient := &facadefakes.FakeOrganizationClient{}
+		fakeClient.CreateSpaceReturns(&facade.Space{Guid: "space-guid"}, nil)
+		client := &auditingOrganizationClient{OrganizationClient: fakeClient, organizationName: "org"}
+
+		Expect(client.CreateSpace(context.Background(), "space", facade.OwnerRef{UID: "space-uid"}, 1)).Error().NotTo(HaveOccurred())
+
+		records := readRecords()
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Operation).To(Equal("CreateSpace"))
+		Expect(records[0].Object).To(BeNil())
+		Expect(records[0].Owner).To(Equal("space-uid"))
+		Expect(records[0].Guid).To(Equal("space-guid"))
+		Expect(records[0].OrganizationName).To(Equal("org"))
+		Expect(records[0].Result).To(Equal(audit.ResultSucceeded))
+	})
+
+	It("should record space role changes", func() {
+		fakeClient := &facadefakes.FakeOrganizationClient{}
+		fakeClient.RemoveManagerReturns(errors.New("forbidden"))
+		client := &auditingOrganizationClient{OrganizationClient: fakeClient, organizationName: "org"}
+		ctx := context.Background()
+
+		Expect(client.AddAuditor(ctx, "space-guid", "auditor", "uaa")).To(Succeed())
+		Expect(client.AddDeveloper(ctx, "space-guid", "developer", "uaa")).To(Succeed())
+		Expect(client.AddManager(ctx, "space-guid", "manager", "sap.ids")).To(Succeed())
+		Expect(client.RemoveAuditor(ctx, "space-guid", "auditor", "uaa")).To(Succeed())
+		Expect(client.RemoveDeveloper(ctx, "space-guid", "developer", "uaa")).To(Succeed())
+		Expect(client.RemoveManager(ctx, "space-guid", "manager", "sap.ids")).To(MatchError("forbidden"))
+
+		records := readRecords()
+		Expect(records).To(HaveLen(6))
+		var operations []string
+		for _, record := range records {
+			operations = append(operations, record.Operation)
+			Expect(record.Guid).To(Equal("space-guid"))
+			Expect(record.OrganizationName).To(Equal("org"))
+		}
+		Expect(operations).To(Equal([]string{"AddAuditor", "AddDeveloper", "AddManager", "RemoveAuditor", "RemoveDeveloper", "RemoveManager"}))
+		Expect(records[2].Username).To(Equal("manager"))
+		Expect(records[2].Origin).To(Equal("sap.ids"))
+		Expect(records[5].Result).To(Equal(audit.ResultFailed))
+		Expect(records[5].Error).To(Equal("forbidden"))
+	})
+})
@@ -10,11 +10,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"time"
 
 	cfclient "github.com/cloudfoundry-community/go-cfclient/v3/client"
 	cfresource "github.com/cloudfoundry-community/go-cfclient/v3/resource"
 	"github.com/pkg/errors"
 
+	"github.com/sap/cf-service-operator/internal/events"
 	"github.com/sap/cf-service-operator/internal/facade"
 )
 
@@ -28,6 +30,15 @@ type bindingFilterName struct {
 type bindingFilterOwner struct {
 	owner string
 }
+type bindingFilterReplacementOwner struct {
+	owner string
+}
+type bindingFilterGuid struct {
+	guid string
+}
+type bindingFilterLabelSelector struct {
+	labelSelector string
+}
 
 func (bn *bindingFilterName) getListOptions() *cfclient.ServiceCredentialBindingListOptions {
 	listOpts := cfclient.NewServiceCredentialBindingListOptions()
@@ -41,21 +52,61 @@ func (bo *bindingFilterOwner) getListOptions() *cfclient.ServiceCredentialBindin
 	return listOpts
 }
 
-// GetBinding returns the binding with the given bindingOpts["owner"] or bindingOpts["name"].
-// If bindingOpts["name"] is empty, the binding with the given bindingOpts["owner"] is returned.
+func (br *bindingFilterReplacementOwner) getListOptions() *cfclient.ServiceCredentialBindingListOptions {
+	listOpts := cfclient.NewServiceCredentialBindingListOptions()
+	listOpts.LabelSelector.EqualTo(fmt.Sprintf("%s/%s=%s", labelPrefix, labelKeyReplacementOwner, br.owner))
+	return listOpts
+}
+
+func (bg *bindingFilterGuid) getListOptions() *cfclient.ServiceCredentialBindingListOptions {
+	listOpts := cfclient.NewServiceCredentialBindingListOptions()
+	listOpts.GUIDs.EqualTo(bg.guid)
+	return listOpts
+}
+
+func (bl *bindingFilterLabelSelector) getListOptions() *cfclient.ServiceCredentialBindingListOptions {
+	listOpts := cfclient.NewServiceCredentialBindingListOptions()
+	// bindings owned by some object (or being their replacement) are never matched
+	listOpts.LabelSelector.EqualTo(bl.labelSelector + ",!" + labelOwner + ",!" + labelPrefix + "/" + labelKeyReplacementOwner)
+	return listOpts
+}
+
+// GetBinding returns the binding with the given bindingOpts["owner"], bindingOpts["name"] or bindingOpts["guid"].
+// If bindingOpts["name"] and bindingOpts["guid"] are empty, the binding with the given bindingOpts["owner"] is returned.
 // If bindingOpts["name"] is not empty, the binding with the given Name is returned for orphan bindings.
+// If bindingOpts["guid"] is not empty, the binding with the given GUID is returned for orphan bindings (taking precedence over the name).
+// If bindingOpts["labelSelector"] is not empty, the binding without owner label matching the given label selector is returned
+// for bindings created by other tools (taking precedence over the name).
+// If bindingOpts["replacementOwner"] is not empty, the (not yet promoted) replacement of the binding owned by the given owner is returned.
 // If no binding is found, nil is returned.
 // If multiple bindings are found, an error is returned.
 // The function add the parameter values to the orphan cf binding, so that can be adopted.
+// Credentials are not read; they are only needed when the binding secret is written (see GetBindingCredentials).
 func (c *spaceClient) GetBinding(ctx context.Context, bindingOpts map[string]string) (*facade.Binding, error) {
+	// orphan bindings (looked up by name, guid or label selector) and replacement bindings are never cached
+	orphan := bindingOpts["name"] != "" || bindingOpts["guid"] != "" || bindingOpts["labelSelector"] != ""
+	replacement := bindingOpts["replacementOwner"] != ""
+	if !orphan && !replacement {
+		if binding, ok := c.resourceCache.getBinding(bindingOpts["owner"]); ok {
+			return binding, nil
+		}
+		defer c.resourceCache.observeRefresh(resourceTypeBinding, time.Now())
+	}
+
 	var filterOpts bindingFilter
-	if bindingOpts["name"] != "" {
+	if bindingOpts["guid"] != "" {
+		filterOpts = &bindingFilterGuid{guid: bindingOpts["guid"]}
+	} else if bindingOpts["labelSelector"] != "" {
+		filterOpts = &bindingFilterLabelSelector{labelSelector: bindingOpts["labelSelector"]}
+	} else if bindingOpts["name"] != "" {
 		filterOpts = &bindingFilterName{name: bindingOpts["name"]}
+	} else if replacement {
+		filterOpts = &bindingFilterReplacementOwner{owner: bindingOpts["replacementOwner"]}
 	} else {
 		filterOpts = &bindingFilterOwner{owner: bindingOpts["owner"]}
 	}
 	listOpts := filterOpts.getListOptions()
-	serviceBindings, err := c.client.ServiceCredentialBindings.ListAll(ctx, listOpts)
+	serviceBindings, err := listAllPages(ctx, c.paging, resourceTypeBinding, listOpts, c.client.ServiceCredentialBindings.List)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list service credential bindings: %w", err)
 	}
@@ -69,82 +120,229 @@ func (c *spaceClient) GetBinding(ctx context.Context, bindingOpts map[string]str
 	serviceBinding := serviceBindings[0]
 
 	// add parameter values to the cf orphan binding
-	if bindingOpts["name"] != "" {
+	if orphan {
 		generationvalue := "0"
 		serviceBinding.Metadata.Annotations[annotationGeneration] = &generationvalue
 		parameterHashValue := "0"
 		serviceBinding.Metadata.Annotations[annotationParameterHash] = &parameterHashValue
 	}
 
+	owner := bindingOpts["owner"]
+	if replacement {
+		owner = bindingOpts["replacementOwner"]
+	}
+	result, err := newBinding(serviceBinding, owner)
+	if err != nil {
+		return nil, err
+	}
+	result.Replacement = replacement
+	if !orphan && !replacement {
+		c.resourceCache.addBinding(result)
+		publishEvent(events.EventTypeRefreshed, events.ResourceTypeBinding, result.Guid, result.Owner, c.spaceGuid)
+	}
+	return result, nil
+}
+
+// ListBindings returns all service bindings (service keys and app bindings) of service instances in the client's space which are owned
+// by some Kubernetes object (that is, carry the owner label), no matter if the owning object still exists; the resource cache is bypassed.
+func (c *spaceClient) ListBindings(ctx context.Context) ([]*facade.Binding, error) {
+	instanceListOpts := cfclient.NewServiceInstanceListOptions()
+	instanceListOpts.SpaceGUIDs.EqualTo(c.spaceGuid)
+	serviceInstances, err := listAllPages(ctx, c.paging, resourceTypeInstance, instanceListOpts, c.client.ServiceInstances.List)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service instances: %w", err)
+	}
+	if len(serviceInstances) == 0 {
+		return nil, nil
+	}
+	serviceInstanceGuids := make([]string, len(serviceInstances))
+	for i, serviceInstance := range serviceInstances {
+		serviceInstanceGuids[i] = serviceInstance.GUID
+	}
+
+	listOpts := cfclient.NewServiceCredentialBindingListOptions()
+	listOpts.LabelSelector.EqualTo(labelOwner)
+	listOpts.Type.EqualTo("key", "app")
+	listOpts.ServiceInstanceGUIDs.EqualTo(serviceInstanceGuids...)
+	serviceBindings, err := listAllPages(ctx, c.paging, resourceTypeBinding, listOpts, c.client.ServiceCredentialBindings.List)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service credential bindings: %w", err)
+	}
+
+	result := make([]*facade.Binding, 0, len(serviceBindings))
+	for _, serviceBinding := range serviceBindings {
+		binding, err := newBinding(serviceBinding, *serviceBinding.Metadata.Labels[labelOwner])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid service binding %s", serviceBinding.GUID)
+		}
+		result = append(result, binding)
+	}
+	return result, nil
+}
+
+// newBinding converts the given CF service credential binding, owned by the given owner (without credentials).
+func newBinding(serviceBinding *cfresource.ServiceCredentialBinding, owner string) (*facade.Binding, error) {
 	guid := serviceBinding.GUID
 	name := serviceBinding.Name
+	serviceInstanceGuid := ""
+	if serviceBinding.Relationships.ServiceInstance != nil && serviceBinding.Relationships.ServiceInstance.Data != nil {
+		serviceInstanceGuid = serviceBinding.Relationships.ServiceInstance.Data.GUID
+	}
+	appGuid := ""
+	if serviceBinding.Relationships.App != nil && serviceBinding.Relationships.App.Data != nil {
+		appGuid = serviceBinding.Relationships.App.Data.GUID
+	}
 	generation, err := strconv.ParseInt(*serviceBinding.Metadata.Annotations[annotationGeneration], 10, 64)
 	if err != nil {
 		return nil, errors.Wrap(err, "error parsing service binding generation")
 	}
 	parameterHash := *serviceBinding.Metadata.Annotations[annotationParameterHash]
-	var state facade.BindingState
-	switch serviceBinding.LastOperation.Type + ":" + serviceBinding.LastOperation.State {
+	state := bindingStateOf(serviceBinding.LastOperation)
+	stateDescription := serviceBinding.LastOperation.Description
+	ownerNamespace, ownerName := ownerObjectOf(serviceBinding.Metadata)
+
+	return &facade.Binding{
+		Guid:                guid,
+		Name:                name,
+		ServiceInstanceGuid: serviceInstanceGuid,
+		AppGuid:             appGuid,
+		Type:                facade.BindingType(serviceBinding.Type),
+		Owner:               owner,
+		OwnerNamespace:      ownerNamespace,
+		OwnerName:           ownerName,
+		OwnerCluster:        ownerClusterOf(serviceBinding.Metadata),
+		Generation:          generation,
+		ParameterHash:       parameterHash,
+		CustomMetadata:      hasCustomMetadata(serviceBinding.Metadata),
+		State:               state,
+		StateDescription:    stateDescription,
+		LastOperation:       lastOperationOf(serviceBinding.LastOperation),
+	}, nil
+}
+
+// lastOperationOf converts the last operation of a service instance or binding; nil if no operation is reported.
+func lastOperationOf(lastOperation cfresource.LastOperation) *facade.LastOperation {
+	if lastOperation.Type == "" && lastOperation.State == "" {
+		return nil
+	}
+	return &facade.LastOperation{
+		Type:        lastOperation.Type,
+		State:       lastOperation.State,
+		Description: lastOperation.Description,
+		CreatedAt:   lastOperation.CreatedAt,
+		UpdatedAt:   lastOperation.UpdatedAt,
+	}
+}
+
+// bindingStateOf maps the last operation of a service credential or route binding to the binding state.
+func bindingStateOf(lastOperation cfresource.LastOperation) facade.BindingState {
+	switch lastOperation.Type + ":" + lastOperation.State {
 	case "create:in progress":
-		state = facade.BindingStateCreating
+		return facade.BindingStateCreating
 	case "create:succeeded":
-		state = facade.BindingStateReady
+		return facade.BindingStateReady
 	case "create:failed":
-		state = facade.BindingStateCreatedFailed
+		return facade.BindingStateCreatedFailed
 	case "delete:in progress":
-		state = facade.BindingStateDeleting
+		return facade.BindingStateDeleting
 	case "delete:succeeded":
-		state = facade.BindingStateDeleted
+		return facade.BindingStateDeleted
 	case "delete:failed":
-		state = facade.BindingStateDeleteFailed
+		return facade.BindingStateDeleteFailed
 	default:
-		state = facade.BindingStateUnknown
+		return facade.BindingStateUnknown
 	}
-	stateDescription := serviceBinding.LastOperation.Description
+}
 
-	var credentials map[string]interface{}
-	if state == facade.BindingStateReady {
-		details, err := c.client.ServiceCredentialBindings.GetDetails(ctx, guid)
-		if err != nil {
-			return nil, errors.Wrap(err, "error getting service binding details")
-		}
-		credentials = details.Credentials
+// GetBindingCredentials reads the current credentials of the binding with the given guid from Cloud Foundry;
+// credentials are never cached.
+func (c *spaceClient) GetBindingCredentials(ctx context.Context, guid string) (map[string]interface{}, error) {
+	details, err := c.client.ServiceCredentialBindings.GetDetails(ctx, guid)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting service binding details")
 	}
-
-	return &facade.Binding{
-		Guid:             guid,
-		Name:             name,
-		Owner:            bindingOpts["owner"],
-		Generation:       generation,
-		ParameterHash:    parameterHash,
-		State:            state,
-		StateDescription: stateDescription,
-		Credentials:      credentials,
-	}, nil
+	return details.Credentials, nil
 }
 
 // Required parameters (may not be initial): name, serviceInstanceGuid, owner, generation
-// Optional parameters (may be initial): parameters
-func (c *spaceClient) CreateBinding(ctx context.Context, name string, serviceInstanceGuid string, parameters map[string]interface{}, owner string, generation int64) error {
-	req := cfresource.NewServiceCredentialBindingCreateKey(serviceInstanceGuid, name)
+// Optional parameters (may be initial): appGuid, parameters, metadata
+// If appGuid is specified, an app binding (type app) is created, otherwise a service key (type key).
+// If owner.Replacement is set, the binding is labeled as replacement of the binding owned by owner.UID (see PromoteBinding).
+func (c *spaceClient) CreateBinding(ctx context.Context, name string, serviceInstanceGuid string, appGuid string, parameters map[string]interface{}, metadata *facade.Metadata, owner facade.OwnerRef, generation int64) (*facade.Binding, error) {
+	var req *cfresource.ServiceCredentialBindingCreate
+	var bindingType facade.BindingType
+	if appGuid != "" {
+		req = cfresource.NewServiceCredentialBindingCreateApp(serviceInstanceGuid, appGuid).WithName(name)
+		bindingType = facade.BindingTypeApp
+	} else {
+		req = cfresource.NewServiceCredentialBindingCreateKey(serviceInstanceGuid, name)
+		bindingType = facade.BindingTypeKey
+	}
 	if parameters != nil {
 		jsonParameters, err := json.Marshal(parameters)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		req.WithJSONParameters(string(jsonParameters))
 	}
+	ownerLabelKey := labelKeyOwner
+	if owner.Replacement {
+		ownerLabelKey = labelKeyReplacementOwner
+	}
 	req.Metadata = cfresource.NewMetadata().
-		WithLabel(labelPrefix, labelKeyOwner, owner).
+		WithLabel(labelPrefix, ownerLabelKey, owner.UID).
 		WithAnnotation(annotationPrefix, annotationKeyGeneration, strconv.FormatInt(generation, 10)).
 		WithAnnotation(annotationPrefix, annotationKeyParameterHash, facade.ObjectHash(parameters))
+	applyOwnerLabels(req.Metadata, owner, c.clusterID)
+	if metadata != nil {
+		applyMetadata(req.Metadata, metadata, nil)
+	}
 
-	_, _, err := c.client.ServiceCredentialBindings.Create(ctx, req)
-	return err
+	_, serviceBinding, err := c.client.ServiceCredentialBindings.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var result *facade.Binding
+	if serviceBinding != nil {
+		// the binding is only returned if it was created synchronously (that is, for user-provided service instances);
+		// caching it (replacements are never cached) saves later lookups
+		result, err = newBinding(serviceBinding, owner.UID)
+		if err != nil {
+			return nil, err
+		}
+		result.Replacement = owner.Replacement
+		if !owner.Replacement {
+			c.resourceCache.addBinding(result)
+		}
+	} else {
+		if !owner.Replacement {
+			// bindings created asynchronously are not ready yet, and must be looked up again; drop outdated entries of the owner
+			c.resourceCache.deleteBinding("", owner)
+		}
+		// the returned binding is derived from the request; its guid is not known until the binding is read again
+		result = &facade.Binding{
+			Name:                name,
+			ServiceInstanceGuid: serviceInstanceGuid,
+			AppGuid:             appGuid,
+			Type:                bindingType,
+			Owner:               owner.UID,
+			OwnerNamespace:      owner.Namespace,
+			OwnerName:           owner.Name,
+			Replacement:         owner.Replacement,
+			Generation:          generation,
+			ParameterHash:       facade.ObjectHash(parameters),
+			State:               facade.BindingStateCreating,
+			LastOperation:       &facade.LastOperation{Type: "create", State: "in progress"},
+		}
+	}
+	publishEvent(events.EventTypeCreated, events.ResourceTypeBinding, result.Guid, owner.UID, c.spaceGuid)
+	return result, nil
 }
 
 // Required parameters (may not be initial): guid, generation
-func (c *spaceClient) UpdateBinding(ctx context.Context, guid string, generation int64, parameters map[string]interface{}) error {
+// Optional parameters (may be initial): owner, parameters, metadata
+// If metadata is not nil, the custom labels and annotations of the binding are set to exactly the given ones.
+func (c *spaceClient) UpdateBinding(ctx context.Context, guid string, owner facade.OwnerRef, generation int64, parameters map[string]interface{}, metadata *facade.Metadata) error {
 	// TODO: why is there no cfresource.NewServiceCredentialBindingUpdate() method ?
 	req := &cfresource.ServiceCredentialBindingUpdate{}
 	req.Metadata = cfresource.NewMetadata().
@@ -156,10 +354,50 @@ func (c *spaceClient) UpdateBinding(ctx context.Context, guid string, generation
 			req.Metadata.WithLabel(labelPrefix, labelKeyOwner, parameters["owner"].(string))
 		}
 	}
-	_, err := c.client.ServiceCredentialBindings.Update(ctx, guid, req)
-	return err
+	applyOwnerLabels(req.Metadata, owner, c.clusterID)
+	if metadata != nil {
+		// the current metadata tells which custom labels and annotations have to be removed
+		serviceBinding, err := c.client.ServiceCredentialBindings.Get(ctx, guid)
+		if err != nil {
+			return err
+		}
+		applyMetadata(req.Metadata, metadata, serviceBinding.Metadata)
+	}
+	c.resourceCache.deleteBinding(guid, owner)
+	if _, err := c.client.ServiceCredentialBindings.Update(ctx, guid, req); err != nil {
+		return err
+	}
+	publishEvent(events.EventTypeUpdated, events.ResourceTypeBinding, guid, owner.UID, c.spaceGuid)
+	return nil
+}
+
+// Required parameters (may not be initial): guid
+// Optional parameters (may be initial): owner
+func (c *spaceClient) DeleteBinding(ctx context.Context, guid string, owner facade.OwnerRef) error {
+	c.resourceCache.deleteBinding(guid, owner)
+	if err := c.client.ServiceCredentialBindings.Delete(ctx, guid); err != nil {
+		return err
+	}
+	publishEvent(events.EventTypeDeleted, events.ResourceTypeBinding, guid, owner.UID, c.spaceGuid)
+	return nil
 }
 
-func (c *spaceClient) DeleteBinding(ctx context.Context, guid string) error {
-	return c.client.ServiceCredentialBindings.Delete(ctx, guid)
+// PromoteBinding turns the replacement binding with the given guid into the binding owned by owner.UID,
+// by replacing its replacement-owner label with the owner label.
+// The replaced binding must be gone already; otherwise the owner would own two bindings.
+func (c *spaceClient) PromoteBinding(ctx context.Context, guid string, owner facade.OwnerRef) error {
+	req := &cfresource.ServiceCredentialBindingUpdate{}
+	req.Metadata = cfresource.NewMetadata().
+		WithLabel(labelPrefix, labelKeyOwner, owner.UID)
+	req.Metadata.RemoveLabel(labelPrefix, labelKeyReplacementOwner)
+	applyOwnerLabels(req.Metadata, owner, c.clusterID)
+	// annotations are left untouched (but must not be sent as null)
+	req.Metadata.Annotations = map[string]*string{}
+
+	c.resourceCache.deleteBinding(guid, owner)
+	if _, err := c.client.ServiceCredentialBindings.Update(ctx, guid, req); err != nil {
+		return err
+	}
+	publishEvent(events.EventTypeUpdated, events.ResourceTypeBinding, guid, owner.UID, c.spaceGuid)
+	return nil
 }
@@ -0,0 +1,178 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient/v3/client"
+	cfresource "github.com/cloudfoundry-community/go-cfclient/v3/resource"
+
+	"github.com/sap/cf-service-operator/internal/cache"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+// minimum age of a cached catalog before it is re-read because a requested service offering or plan was not found in it
+const catalogRefreshInterval = 30 * time.Second
+
+// catalogCache holds the service catalog (service offerings and plans) visible in each space, read through one CF client
+// (that is, one set of credentials), such that service plans are resolved from memory instead of querying Cloud Foundry per reconcile.
+type catalogCache struct {
+	catalogs *cache.Cache[*serviceCatalog]
+	mutex    sync.Mutex
+	// per space; held while loading the catalog of that space, so that concurrent lookups share one load
+	loading map[string]*sync.Mutex
+}
+
+// serviceCatalog holds the service offerings and plans visible in one space.
+type serviceCatalog struct {
+	// service offering guids by name
+	offerings map[string][]string
+	plans     []facade.ServicePlan
+	// parameter schemas by service plan guid
+	schemas  map[string]*facade.ServicePlanSchemas
+	loadedAt time.Time
+}
+
+// newCatalogCache returns a catalog cache according to the given configuration, or nil if catalog caching is disabled.
+func newCatalogCache(cfg *config.Config) *catalogCache {
+	if cfg == nil || cfg.CatalogCacheTimeout.Duration <= 0 {
+		return nil
+	}
+	return &catalogCache{
+		catalogs: cache.New[*serviceCatalog](cfg.CatalogCacheTimeout.Duration),
+		loading:  make(map[string]*sync.Mutex),
+	}
+}
+
+// get returns the cached catalog of the given space, or loads (and caches) it if there is none.
+func (cc *catalogCache) get(ctx context.Context, spaceGuid string, load func(context.Context) (*serviceCatalog, error)) (*serviceCatalog, error) {
+	if cc == nil {
+		return load(ctx)
+	}
+	if catalog, ok := cc.catalogs.Get(spaceGuid); ok {
+		return catalog, nil
+	}
+
+	lock := cc.loadingLock(spaceGuid)
+	lock.Lock()
+	defer lock.Unlock()
+
+	// the catalog may have been loaded while waiting for the lock
+	if catalog, ok := cc.catalogs.Get(spaceGuid); ok {
+		return catalog, nil
+	}
+	catalog, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cc.catalogs.Set(spaceGuid, catalog)
+	return catalog, nil
+}
+
+// invalidate drops the cached catalog of the given space.
+func (cc *catalogCache) invalidate(spaceGuid string) {
+	if cc == nil {
+		return
+	}
+	cc.catalogs.Delete(spaceGuid)
+}
+
+func (cc *catalogCache) loadingLock(spaceGuid string) *sync.Mutex {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	lock, ok := cc.loading[spaceGuid]
+	if !ok {
+		lock = &sync.Mutex{}
+		cc.loading[spaceGuid] = lock
+	}
+	return lock
+}
+
+// loadCatalog reads the service offerings and plans visible in the given space (two list requests, each possibly reading multiple pages);
+// if a service offering or plan name is given, only offerings or plans with that name are read.
+func (c *spaceClient) loadCatalog(ctx context.Context, spaceGuid string, serviceOfferingName string, servicePlanName string) (*serviceCatalog, error) {
+	serviceOfferingListOpts := cfclient.NewServiceOfferingListOptions()
+	serviceOfferingListOpts.SpaceGUIDs.EqualTo(spaceGuid)
+	if serviceOfferingName != "" {
+		serviceOfferingListOpts.Names.EqualTo(serviceOfferingName)
+	}
+	serviceOfferings, err := listAllPages(ctx, c.paging, resourceTypeServiceOffering, serviceOfferingListOpts, c.client.ServiceOfferings.List)
+	if err != nil {
+		return nil, err
+	}
+
+	servicePlanListOpts := cfclient.NewServicePlanListOptions()
+	servicePlanListOpts.SpaceGUIDs.EqualTo(spaceGuid)
+	if serviceOfferingName != "" {
+		servicePlanListOpts.ServiceOfferingNames.EqualTo(serviceOfferingName)
+	}
+	if servicePlanName != "" {
+		servicePlanListOpts.Names.EqualTo(servicePlanName)
+	}
+	servicePlans, err := listAllPages(ctx, c.paging, resourceTypeServicePlan, servicePlanListOpts, c.client.ServicePlans.List)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := &serviceCatalog{
+		offerings: make(map[string][]string),
+		schemas:   make(map[string]*facade.ServicePlanSchemas),
+		loadedAt:  time.Now(),
+	}
+	offeringNames := make(map[string]string)
+	for _, serviceOffering := range serviceOfferings {
+		catalog.offerings[serviceOffering.Name] = append(catalog.offerings[serviceOffering.Name], serviceOffering.GUID)
+		offeringNames[serviceOffering.GUID] = serviceOffering.Name
+	}
+	for _, servicePlan := range servicePlans {
+		serviceOfferingGuid := ""
+		if servicePlan.Relationships.ServiceOffering.Data != nil {
+			serviceOfferingGuid = servicePlan.Relationships.ServiceOffering.Data.GUID
+		}
+		catalog.plans = append(catalog.plans, facade.ServicePlan{
+			Guid:                servicePlan.GUID,
+			Name:                servicePlan.Name,
+			Description:         servicePlan.Description,
+			ServiceOfferingGuid: serviceOfferingGuid,
+			ServiceOfferingName: offeringNames[serviceOfferingGuid],
+		})
+		catalog.schemas[servicePlan.GUID] = servicePlanSchemas(servicePlan)
+	}
+	return catalog, nil
+}
+
+func servicePlanSchemas(servicePlan *cfresource.ServicePlan) *facade.ServicePlanSchemas {
+	return &facade.ServicePlanSchemas{
+		InstanceCreate: rawSchema(servicePlan.Schemas.ServiceInstance.Create.Parameters),
+		InstanceUpdate: rawSchema(servicePlan.Schemas.ServiceInstance.Update.Parameters),
+		BindingCreate:  rawSchema(servicePlan.Schemas.ServiceBinding.Create.Parameters),
+	}
+}
+
+// rawSchema returns the given schema, or nil if the broker did not publish a schema (which Cloud Foundry reports as empty object).
+func rawSchema(schema *json.RawMessage) []byte {
+	if schema == nil {
+		return nil
+	}
+	if trimmed := bytes.TrimSpace(*schema); len(trimmed) == 0 || bytes.Equal(trimmed, []byte("{}")) || bytes.Equal(trimmed, []byte("null")) {
+		return nil
+	}
+	return *schema
+}
+
+// getCatalog returns the (possibly cached) catalog of the given space.
+func (c *spaceClient) getCatalog(ctx context.Context, spaceGuid string) (*serviceCatalog, error) {
+	return c.catalogCache.get(ctx, spaceGuid, func(ctx context.Context) (*serviceCatalog, error) {
+		return c.loadCatalog(ctx, spaceGuid, "", "")
+	})
+}
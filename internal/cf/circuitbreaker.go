@@ -0,0 +1,105 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cf
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+// circuit breakers per CF API endpoint, shared by all clients of that endpoint;
+// guarded by cacheMutex
+var circuitBreakers = make(map[string]*circuitBreaker)
+
+// getCircuitBreaker returns the (shared) circuit breaker for the given CF API endpoint, or nil if the circuit breaker is disabled.
+// Must be called with cacheMutex locked.
+func getCircuitBreaker(url string, cfg *config.Config) *circuitBreaker {
+	if cfg == nil || cfg.CircuitBreakerThreshold <= 0 {
+		return nil
+	}
+	breaker, ok := circuitBreakers[url]
+	if !ok {
+		breaker = &circuitBreaker{url: url, now: time.Now}
+		circuitBreakers[url] = breaker
+	}
+	// configuration may have changed since the circuit breaker was created
+	breaker.configure(cfg.CircuitBreakerThreshold, cfg.CircuitBreakerTimeout.Duration)
+	return breaker
+}
+
+// circuitBreaker suspends all requests to a CF API endpoint for a while, after a number of consecutive
+// server (5xx) or connection errors; for example, during maintenance windows of the Cloud Foundry landscape.
+// After the suspension, a single further failure suspends requests again.
+type circuitBreaker struct {
+	mutex     sync.Mutex
+	url       string
+	threshold int
+	timeout   time.Duration
+	now       func() time.Time
+	failures  int
+	openUntil time.Time
+}
+
+func (cb *circuitBreaker) configure(threshold int, timeout time.Duration) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	cb.threshold = threshold
+	cb.timeout = timeout
+}
+
+// allow returns an error of type *facade.UnavailableError if requests are currently suspended.
+func (cb *circuitBreaker) allow() error {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	if cb.now().Before(cb.openUntil) {
+		return &facade.UnavailableError{URL: cb.url, Until: cb.openUntil}
+	}
+	return nil
+}
+
+// record records the outcome of a request.
+func (cb *circuitBreaker) record(success bool) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	if success {
+		cb.failures = 0
+		return
+	}
+	cb.failures++
+	if cb.failures >= cb.threshold {
+		cb.openUntil = cb.now().Add(cb.timeout)
+		cb.failures = cb.threshold - 1
+	}
+}
+
+// circuitBreakerTransport fails requests without sending them while the circuit breaker is open.
+type circuitBreakerTransport struct {
+	transport http.RoundTripper
+	breaker   *circuitBreaker
+}
+
+func (t *circuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.breaker.allow(); err != nil {
+		return nil, err
+	}
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		// cancelled requests (for example by reconcile timeouts) say nothing about the endpoint
+		if req.Context().Err() == nil {
+			t.breaker.record(false)
+		}
+		return resp, err
+	}
+	t.breaker.record(resp.StatusCode < http.StatusInternalServerError)
+	return resp, nil
+}
@@ -0,0 +1,100 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package cf
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+var _ = Describe("Circuit breaker tests", func() {
+	var server *ghttp.Server
+	var now time.Time
+	var breaker *circuitBreaker
+	var client *http.Client
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		now = time.Now()
+		breaker = &circuitBreaker{url: server.URL(), now: func() time.Time { return now }}
+		breaker.configure(2, time.Minute)
+		client = &http.Client{Transport: &circuitBreakerTransport{transport: http.DefaultTransport, breaker: breaker}}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	get := func() (int, error) {
+		resp, err := client.Get(server.URL() + "/v3/spaces")
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+
+	It("should suspend requests after consecutive server errors, and resume after the timeout", func() {
+		server.RouteToHandler("GET", "/v3/spaces", ghttp.RespondWith(http.StatusServiceUnavailable, nil))
+
+		for i := 0; i < 2; i++ {
+			status, err := get()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status).To(Equal(http.StatusServiceUnavailable))
+		}
+
+		_, err := get()
+		var unavailableErr *facade.UnavailableError
+		Expect(errors.As(err, &unavailableErr)).To(BeTrue())
+		Expect(unavailableErr.URL).To(Equal(server.URL()))
+		Expect(unavailableErr.Until).To(Equal(now.Add(time.Minute)))
+		Expect(server.ReceivedRequests()).To(HaveLen(2))
+
+		now = now.Add(time.Minute)
+		server.RouteToHandler("GET", "/v3/spaces", ghttp.RespondWith(http.StatusOK, "ok"))
+		status, err := get()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(server.ReceivedRequests()).To(HaveLen(3))
+	})
+
+	It("should not count client errors, and reset on success", func() {
+		server.AppendHandlers(
+			ghttp.RespondWith(http.StatusInternalServerError, nil),
+			ghttp.RespondWith(http.StatusNotFound, nil),
+			ghttp.RespondWith(http.StatusInternalServerError, nil),
+			ghttp.RespondWith(http.StatusOK, "ok"),
+		)
+
+		for _, expected := range []int{http.StatusInternalServerError, http.StatusNotFound, http.StatusInternalServerError, http.StatusOK} {
+			status, err := get()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status).To(Equal(expected))
+		}
+	})
+
+	It("should suspend requests again after a single failure following the suspension", func() {
+		server.RouteToHandler("GET", "/v3/spaces", ghttp.RespondWith(http.StatusBadGateway, nil))
+
+		for i := 0; i < 2; i++ {
+			_, err := get()
+			Expect(err).ToNot(HaveOccurred())
+		}
+		now = now.Add(time.Minute)
+		_, err := get()
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = get()
+		Expect(err).To(HaveOccurred())
+		Expect(server.ReceivedRequests()).To(HaveLen(3))
+	})
+})
@@ -8,11 +8,13 @@ package cf
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	cfclient "github.com/cloudfoundry-community/go-cfclient/v3/client"
 	cfconfig "github.com/cloudfoundry-community/go-cfclient/v3/config"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
+	"github.com/sap/cf-service-operator/internal/config"
 	"github.com/sap/cf-service-operator/internal/facade"
 	cfmetrics "github.com/sap/cf-service-operator/pkg/metrics"
 )
@@ -21,21 +23,36 @@ const (
 	labelPrefix                = "service-operator.cf.cs.sap.com"
 	labelKeyOwner              = "owner"
 	labelOwner                 = labelPrefix + "/" + labelKeyOwner
+	labelKeyReplacementOwner   = "replacement-owner"
+	labelKeyOwnerNamespace     = "owner-namespace"
+	labelOwnerNamespace        = labelPrefix + "/" + labelKeyOwnerNamespace
+	labelKeyOwnerName          = "owner-name"
+	labelOwnerName             = labelPrefix + "/" + labelKeyOwnerName
+	labelKeyOwnerCluster       = "owner-cluster"
+	labelOwnerCluster          = labelPrefix + "/" + labelKeyOwnerCluster
 	annotationPrefix           = "service-operator.cf.cs.sap.com"
 	annotationKeyGeneration    = "generation"
 	annotationGeneration       = annotationPrefix + "/" + annotationKeyGeneration
 	annotationKeyParameterHash = "parameter-hash"
 	annotationParameterHash    = annotationPrefix + "/" + annotationKeyParameterHash
+	annotationKeyTagsHash      = "tags-hash"
+	annotationTagsHash         = annotationPrefix + "/" + annotationKeyTagsHash
 )
 
 type organizationClient struct {
 	organizationName string
 	client           cfclient.Client
+	resourceCache    *resourcePartition
 }
 
 type spaceClient struct {
-	spaceGuid string
-	client    cfclient.Client
+	spaceGuid     string
+	client        cfclient.Client
+	resourceCache *resourcePartition
+	catalogCache  *catalogCache
+	paging        pagingOptions
+	// identifier of this cluster, recorded in the owner-cluster label of the created or updated instances and bindings (config clusterId)
+	clusterID string
 }
 
 type clientIdentifier struct {
@@ -44,21 +61,27 @@ type clientIdentifier struct {
 }
 
 type clientCacheEntry struct {
-	url      string
-	username string
-	password string
-	client   cfclient.Client
+	url           string
+	username      string
+	password      string
+	caBundle      string
+	proxy         string
+	client        cfclient.Client
+	resourceCache *resourceCache
+	catalogCache  *catalogCache
+	// time of the last lookup of the entry; idle entries are evicted by the ClientCacheJanitor
+	lastUsedAt time.Time
 }
 
 var (
 	cacheMutex  = &sync.Mutex{}
 	clientCache = make(map[clientIdentifier]*clientCacheEntry)
+	// configuration passed to Reconfigure; its reloadable settings replace the according settings of the configuration
+	// passed to the client constructors (nil if Reconfigure was not called)
+	reloadedConfig *config.Config
 )
 
-func newOrganizationClient(organizationName string, url string, username string, password string) (*organizationClient, error) {
-	if organizationName == "" {
-		return nil, fmt.Errorf("missing or empty organization name")
-	}
+func newClient(url string, username string, password string, cfg *config.Config) (*cfclient.Client, error) {
 	if url == "" {
 		return nil, fmt.Errorf("missing or empty URL")
 	}
@@ -73,142 +96,158 @@ func newOrganizationClient(organizationName string, url string, username string,
 		return nil, err
 	}
 	httpClient := config.HTTPClient()
-	transport, err := cfmetrics.AddMetricsToTransport(httpClient.Transport, metrics.Registry, "cf-api", url)
-	if err != nil {
+	if err := configureConnection(httpClient, cfg); err != nil {
 		return nil, err
 	}
-	httpClient.Transport = transport
-	config.WithHTTPClient(httpClient)
-	c, err := cfclient.New(config)
+	var metricsLabels []string
+	if cfg != nil {
+		metricsLabels = cfg.CFMetricsLabels
+	}
+	transport, err := cfmetrics.AddMetricsToTransport(httpClient.Transport, metrics.Registry, "cf-api", url, metricsLabels...)
 	if err != nil {
 		return nil, err
 	}
-	return &organizationClient{organizationName: organizationName, client: *c}, nil
-}
-
-func newSpaceClient(spaceGuid string, url string, username string, password string) (*spaceClient, error) {
-	if spaceGuid == "" {
-		return nil, fmt.Errorf("missing or empty space guid")
+	if limiter := getRateLimiter(url, cfg); limiter != nil {
+		transport = &rateLimitTransport{transport: transport, limiter: limiter}
 	}
-	if url == "" {
-		return nil, fmt.Errorf("missing or empty URL")
+	if cfg != nil {
+		maxRetriesOnTooManyRequests.Store(int64(cfg.MaxRetriesOnTooManyRequests))
+		transport = &retryTransport{transport: transport, maxRetries: getMaxRetriesOnTooManyRequests}
 	}
-	if username == "" {
-		return nil, fmt.Errorf("missing or empty username")
+	if breaker := getCircuitBreaker(url, cfg); breaker != nil {
+		transport = &circuitBreakerTransport{transport: transport, breaker: breaker}
 	}
-	if password == "" {
-		return nil, fmt.Errorf("missing or empty password")
+	if cfg != nil && cfg.EnableConditionalRequests {
+		transport = newConditionalTransport(transport, cfg.CacheTimeOut.Duration)
 	}
-	config, err := cfconfig.NewUserPassword(url, username, password)
-	if err != nil {
-		return nil, err
+	transport = &reachabilityTransport{transport: transport, url: url}
+	transport = &reauthenticationTransport{transport: transport, url: url, username: username, password: password}
+	transport = &authenticationTransport{transport: transport, identifier: clientIdentifier{url: url, username: username}, password: password}
+	httpClient.Transport = &tracingTransport{transport: transport}
+	config.WithHTTPClient(httpClient)
+	return cfclient.New(config)
+}
+
+// getClientCacheEntry returns the cached CF client (and resource cache) for the given credentials;
+// a new client is created (and cached) if there is none yet, or if the password or connection settings changed.
+// If the cache is full, the least recently used clients are evicted.
+// Must be called with cacheMutex locked.
+func getClientCacheEntry(url string, username string, password string, cfg *config.Config) (*clientCacheEntry, error) {
+	if reloadedConfig != nil {
+		cfg = cfg.WithReloadable(reloadedConfig)
 	}
-	httpClient := config.HTTPClient()
-	transport, err := cfmetrics.AddMetricsToTransport(httpClient.Transport, metrics.Registry, "cf-api", url)
-	if err != nil {
-		return nil, err
+
+	// look up CF client in cache
+	identifier := clientIdentifier{url: url, username: username}
+	caBundle, proxy := connectionSettings(cfg)
+	cacheEntry, isInCache := clientCache[identifier]
+	if isInCache && cacheEntry.password == password && cacheEntry.caBundle == caBundle && cacheEntry.proxy == proxy {
+		cacheEntry.lastUsedAt = time.Now()
+		return cacheEntry, nil
 	}
-	httpClient.Transport = transport
-	config.WithHTTPClient(httpClient)
-	c, err := cfclient.New(config)
+
+	// no CF client in cache, or password (or connection settings) changed => create a new one
+	// (note: in the latter case, the resource and catalog caches are dropped as well)
+	delete(clientCache, identifier)
+	if cfg != nil {
+		evictLeastRecentlyUsedClients(cfg.ClientCacheMaxEntries)
+	}
+	c, err := newClient(url, username, password, cfg)
 	if err != nil {
 		return nil, err
 	}
-	return &spaceClient{spaceGuid: spaceGuid, client: *c}, nil
+	cacheEntry = &clientCacheEntry{
+		url:           url,
+		username:      username,
+		password:      password,
+		caBundle:      caBundle,
+		proxy:         proxy,
+		client:        *c,
+		resourceCache: newResourceCache(cfg),
+		catalogCache:  newCatalogCache(cfg),
+		lastUsedAt:    time.Now(),
+	}
+	clientCache[identifier] = cacheEntry
+	return cacheEntry, nil
 }
 
-func NewOrganizationClient(organizationName string, url string, username string, password string) (facade.OrganizationClient, error) {
+// Reconfigure applies the reloadable settings (resource cache, rate limits, retries) of the given configuration to all cached
+// and future clients. Resource caches are dropped (and rebuilt) if the resource cache settings changed.
+func Reconfigure(cfg *config.Config) {
 	cacheMutex.Lock()
 	defer cacheMutex.Unlock()
 
-	// look up CF client in cache
-	identifier := clientIdentifier{url: url, username: username}
-	cacheEntry, isInCache := clientCache[identifier]
-
-	var err error = nil
-	var client *organizationClient = nil
-	if isInCache {
-		// re-use CF client and wrap it as organizationClient
-		client = &organizationClient{organizationName: organizationName, client: cacheEntry.client}
-		if cacheEntry.password != password {
-			// password was rotated => delete client from cache and create a new one below
-			delete(clientCache, identifier)
-			isInCache = false
+	reloadedConfig = cfg
+	maxRetriesOnTooManyRequests.Store(int64(cfg.MaxRetriesOnTooManyRequests))
+	for url, limiter := range rateLimiters {
+		setRateLimit(limiter, cfg.RateLimitFor(url))
+	}
+	for _, cacheEntry := range clientCache {
+		if !cacheEntry.resourceCache.matches(cfg) {
+			cacheEntry.resourceCache = newResourceCache(cfg)
 		}
 	}
+}
 
-	if !isInCache {
-		// create new CF client and wrap it as organizationClient
-		client, err = newOrganizationClient(organizationName, url, username, password)
-		if err == nil {
-			// add CF client to cache
-			clientCache[identifier] = &clientCacheEntry{url: url, username: username, password: password, client: client.client}
-		}
+// clusterID returns the configured identifier of this cluster (empty if not configured).
+func clusterID(cfg *config.Config) string {
+	if cfg == nil {
+		return ""
 	}
+	return cfg.ClusterID
+}
 
-	return client, err
+// spaceCacheTimeout returns the resource cache timeout overridden for a single space (zero if not overridden).
+func spaceCacheTimeout(cfg *config.Config) time.Duration {
+	if cfg == nil {
+		return 0
+	}
+	return cfg.SpaceCacheTimeout.Duration
 }
 
-func NewSpaceClient(spaceGuid string, url string, username string, password string) (facade.SpaceClient, error) {
+func NewOrganizationClient(organizationName string, url string, username string, password string, cfg *config.Config) (facade.OrganizationClient, error) {
+	if organizationName == "" {
+		return nil, fmt.Errorf("missing or empty organization name")
+	}
+
 	cacheMutex.Lock()
 	defer cacheMutex.Unlock()
 
-	// look up CF client in cache
-	identifier := clientIdentifier{url: url, username: username}
-	cacheEntry, isInCache := clientCache[identifier]
-
-	var err error = nil
-	var client *spaceClient = nil
-	if isInCache {
-		// re-use CF client from cache and wrap it as spaceClient
-		client = &spaceClient{spaceGuid: spaceGuid, client: cacheEntry.client}
-		if cacheEntry.password != password {
-			// password was rotated => delete client from cache and create a new one below
-			delete(clientCache, identifier)
-			isInCache = false
-		}
+	cacheEntry, err := getClientCacheEntry(url, username, password, cfg)
+	if err != nil {
+		return nil, err
 	}
+	client := &organizationClient{organizationName: organizationName, client: cacheEntry.client, resourceCache: cacheEntry.resourceCache.organizationPartition(organizationName)}
+	return &auditingOrganizationClient{OrganizationClient: &lockingOrganizationClient{OrganizationClient: &tracingOrganizationClient{client: client, organizationName: organizationName}, organizationName: organizationName}, organizationName: organizationName}, nil
+}
 
-	if !isInCache {
-		// create new CF client and wrap it as spaceClient
-		client, err = newSpaceClient(spaceGuid, url, username, password)
-		if err == nil {
-			// add CF client to cache
-			clientCache[identifier] = &clientCacheEntry{url: url, username: username, password: password, client: client.client}
-		}
+func NewSpaceClient(spaceGuid string, url string, username string, password string, cfg *config.Config) (facade.SpaceClient, error) {
+	if spaceGuid == "" {
+		return nil, fmt.Errorf("missing or empty space guid")
 	}
 
-	return client, err
-}
-
-func NewSpaceHealthChecker(spaceGuid string, url string, username string, password string) (facade.SpaceHealthChecker, error) {
 	cacheMutex.Lock()
 	defer cacheMutex.Unlock()
 
-	// look up CF client in cache
-	identifier := clientIdentifier{url: url, username: username}
-	cacheEntry, isInCache := clientCache[identifier]
-
-	var err error = nil
-	var client *spaceClient = nil
-	if isInCache {
-		// re-use CF client from cache and wrap it as spaceClient
-		client = &spaceClient{spaceGuid: spaceGuid, client: cacheEntry.client}
-		if cacheEntry.password != password {
-			// password was rotated => delete client from cache and create a new one below
-			delete(clientCache, identifier)
-			isInCache = false
-		}
+	cacheEntry, err := getClientCacheEntry(url, username, password, cfg)
+	if err != nil {
+		return nil, err
 	}
+	client := &spaceClient{spaceGuid: spaceGuid, client: cacheEntry.client, resourceCache: cacheEntry.resourceCache.spacePartition(spaceGuid, spaceCacheTimeout(cfg)), catalogCache: cacheEntry.catalogCache, paging: newPagingOptions(cfg), clusterID: clusterID(cfg)}
+	return &auditingSpaceClient{SpaceClient: &lockingSpaceClient{SpaceClient: &tracingSpaceClient{client: client, spaceGuid: spaceGuid}, spaceGuid: spaceGuid}, spaceGuid: spaceGuid}, nil
+}
 
-	if !isInCache {
-		// create new CF client and wrap it as spaceClient
-		client, err = newSpaceClient(spaceGuid, url, username, password)
-		if err == nil {
-			// add CF client to cache
-			clientCache[identifier] = &clientCacheEntry{url: url, username: username, password: password, client: client.client}
-		}
+func NewSpaceHealthChecker(spaceGuid string, url string, username string, password string, cfg *config.Config) (facade.SpaceHealthChecker, error) {
+	if spaceGuid == "" {
+		return nil, fmt.Errorf("missing or empty space guid")
 	}
 
-	return client, err
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	cacheEntry, err := getClientCacheEntry(url, username, password, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &spaceClient{spaceGuid: spaceGuid, client: cacheEntry.client, resourceCache: cacheEntry.resourceCache.spacePartition(spaceGuid, spaceCacheTimeout(cfg)), paging: newPagingOptions(cfg)}, nil
 }
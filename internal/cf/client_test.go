@@ -6,6 +6,8 @@ package cf
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
@@ -14,7 +16,12 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/ghttp"
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/events"
+	"github.com/sap/cf-service-operator/internal/facade"
 )
 
 // constants useful for this file
@@ -34,7 +41,16 @@ const (
 
 	spacesURI           = "/v3/spaces"
 	serviceInstancesURI = "/v3/service_instances"
+	servicePlansURI     = "/v3/service_plans"
+	serviceOfferingsURI = "/v3/service_offerings"
 	uaaURI              = "/uaa/oauth/token"
+
+	appsURI                      = "/v3/apps"
+	serviceCredentialBindingsURI = "/v3/service_credential_bindings"
+	routesURI                    = "/v3/routes"
+	serviceRouteBindingsURI      = "/v3/service_route_bindings"
+	usersURI                     = "/v3/users"
+	rolesURI                     = "/v3/roles"
 )
 
 type Token struct {
@@ -110,7 +126,7 @@ var _ = Describe("CF Client tests", Ordered, func() {
 		})
 
 		It("should create OrgClient", func() {
-			NewOrganizationClient(OrgName, url, Username, Password)
+			NewOrganizationClient(OrgName, url, Username, Password, nil)
 
 			// Discover UAA endpoint
 			Expect(server.ReceivedRequests()[0].Method).To(Equal("GET"))
@@ -120,7 +136,7 @@ var _ = Describe("CF Client tests", Ordered, func() {
 		})
 
 		It("should be able to query some org", func() {
-			orgClient, err := NewOrganizationClient(OrgName, url, Username, Password)
+			orgClient, err := NewOrganizationClient(OrgName, url, Username, Password, nil)
 			Expect(err).To(BeNil())
 
 			orgClient.GetSpace(ctx, Owner)
@@ -152,11 +168,11 @@ var _ = Describe("CF Client tests", Ordered, func() {
 		})
 
 		It("should be able to query some org twice", func() {
-			orgClient, err := NewOrganizationClient(OrgName, url, Username, Password)
+			orgClient, err := NewOrganizationClient(OrgName, url, Username, Password, nil)
 			Expect(err).To(BeNil())
 
 			orgClient.GetSpace(ctx, Owner)
-			orgClient, err = NewOrganizationClient(OrgName, url, Username, Password)
+			orgClient, err = NewOrganizationClient(OrgName, url, Username, Password, nil)
 			Expect(err).To(BeNil())
 			orgClient.GetSpace(ctx, Owner)
 
@@ -179,7 +195,7 @@ var _ = Describe("CF Client tests", Ordered, func() {
 
 		It("should be able to query two different orgs", func() {
 			// test org 1
-			orgClient1, err1 := NewOrganizationClient(OrgName, url, Username, Password)
+			orgClient1, err1 := NewOrganizationClient(OrgName, url, Username, Password, nil)
 			Expect(err1).To(BeNil())
 			orgClient1.GetSpace(ctx, Owner)
 			// Discover UAA endpoint
@@ -193,7 +209,7 @@ var _ = Describe("CF Client tests", Ordered, func() {
 			Expect(server.ReceivedRequests()[2].RequestURI).To(ContainSubstring(Owner))
 
 			// test org 2
-			orgClient2, err2 := NewOrganizationClient(OrgName2, url, Username, Password)
+			orgClient2, err2 := NewOrganizationClient(OrgName2, url, Username, Password, nil)
 			Expect(err2).To(BeNil())
 			orgClient2.GetSpace(ctx, Owner2)
 			// no discovery of UAA endpoint or oAuth token here due to caching
@@ -202,6 +218,61 @@ var _ = Describe("CF Client tests", Ordered, func() {
 			Expect(server.ReceivedRequests()[3].RequestURI).To(ContainSubstring(Owner2))
 		})
 
+		It("should add and remove space roles of users looked up by name and origin", func() {
+			server.RouteToHandler("GET", usersURI, ghttp.CombineHandlers(
+				ghttp.VerifyFormKV("usernames", "auditor"),
+				ghttp.VerifyFormKV("origins", "ldap"),
+				ghttp.RespondWith(http.StatusOK, `{
+					"pagination": {"total_results": 1, "total_pages": 1},
+					"resources": [{"guid": "user-guid", "username": "auditor", "origin": "ldap"}]
+				}`),
+			))
+			server.RouteToHandler("GET", rolesURI, ghttp.CombineHandlers(
+				ghttp.VerifyFormKV("space_guids", "space-guid"),
+				ghttp.VerifyFormKV("user_guids", "user-guid"),
+				ghttp.VerifyFormKV("types", "space_auditor"),
+				ghttp.RespondWith(http.StatusOK, `{"pagination": {"total_results": 0, "total_pages": 1}, "resources": []}`),
+			))
+			server.RouteToHandler("POST", rolesURI, ghttp.CombineHandlers(
+				ghttp.VerifyJSON(`{
+					"type": "space_auditor",
+					"relationships": {
+						"space": {"data": {"guid": "space-guid"}},
+						"user": {"data": {"guid": "user-guid"}}
+					}
+				}`),
+				ghttp.RespondWith(http.StatusCreated, `{"guid": "role-guid", "type": "space_auditor"}`),
+			))
+
+			orgClient, err := NewOrganizationClient(OrgName, url, Username, Password, nil)
+			Expect(err).To(BeNil())
+			Expect(orgClient.AddAuditor(ctx, "space-guid", "auditor", "ldap")).To(Succeed())
+			Expect(server.ReceivedRequests()[len(server.ReceivedRequests())-1].Method).To(Equal("POST"))
+
+			server.RouteToHandler("GET", rolesURI, ghttp.CombineHandlers(
+				ghttp.VerifyFormKV("types", "space_auditor"),
+				ghttp.RespondWith(http.StatusOK, `{
+					"pagination": {"total_results": 1, "total_pages": 1},
+					"resources": [{"guid": "role-guid", "type": "space_auditor"}]
+				}`),
+			))
+			server.RouteToHandler("DELETE", rolesURI+"/role-guid", ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusAccepted, nil, http.Header{"Location": []string{url + "/v3/jobs/job-guid"}}),
+			))
+			// assigning the role again is a no-op
+			Expect(orgClient.AddAuditor(ctx, "space-guid", "auditor", "ldap")).To(Succeed())
+			Expect(server.ReceivedRequests()[len(server.ReceivedRequests())-1].Method).To(Equal("GET"))
+			Expect(orgClient.RemoveAuditor(ctx, "space-guid", "auditor", "ldap")).To(Succeed())
+			Expect(server.ReceivedRequests()[len(server.ReceivedRequests())-1].Method).To(Equal("DELETE"))
+
+			// users which do not exist (with the given origin) have no roles to remove, but cannot be assigned roles
+			server.RouteToHandler("GET", usersURI, ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusOK, `{"pagination": {"total_results": 0, "total_pages": 1}, "resources": []}`),
+			))
+			Expect(orgClient.RemoveAuditor(ctx, "space-guid", "auditor", "ldap")).To(Succeed())
+			Expect(orgClient.AddAuditor(ctx, "space-guid", "auditor", "ldap")).To(MatchError(ContainSubstring("found no user with name: auditor")))
+		})
+
 	})
 
 	Describe("NewSpaceClient", func() {
@@ -224,7 +295,7 @@ var _ = Describe("CF Client tests", Ordered, func() {
 		})
 
 		It("should create SpaceClient", func() {
-			NewSpaceClient(OrgName, url, Username, Password)
+			NewSpaceClient(OrgName, url, Username, Password, nil)
 
 			// Discover UAA endpoint
 			Expect(server.ReceivedRequests()[0].Method).To(Equal("GET"))
@@ -234,7 +305,7 @@ var _ = Describe("CF Client tests", Ordered, func() {
 		})
 
 		It("should be able to query some space", func() {
-			spaceClient, err := NewSpaceClient(OrgName, url, Username, Password)
+			spaceClient, err := NewSpaceClient(OrgName, url, Username, Password, nil)
 			Expect(err).To(BeNil())
 
 			spaceClient.GetInstance(ctx, map[string]string{"owner": Owner})
@@ -266,11 +337,11 @@ var _ = Describe("CF Client tests", Ordered, func() {
 		})
 
 		It("should be able to query some space twice", func() {
-			spaceClient, err := NewSpaceClient(OrgName, url, Username, Password)
+			spaceClient, err := NewSpaceClient(OrgName, url, Username, Password, nil)
 			Expect(err).To(BeNil())
 
 			spaceClient.GetInstance(ctx, map[string]string{"owner": Owner})
-			spaceClient, err = NewSpaceClient(OrgName, url, Username, Password)
+			spaceClient, err = NewSpaceClient(OrgName, url, Username, Password, nil)
 			Expect(err).To(BeNil())
 			spaceClient.GetInstance(ctx, map[string]string{"owner": Owner})
 
@@ -293,7 +364,7 @@ var _ = Describe("CF Client tests", Ordered, func() {
 
 		It("should be able to query two different spaces", func() {
 			// test space 1
-			spaceClient1, err1 := NewSpaceClient(SpaceName, url, Username, Password)
+			spaceClient1, err1 := NewSpaceClient(SpaceName, url, Username, Password, nil)
 			Expect(err1).To(BeNil())
 			spaceClient1.GetInstance(ctx, map[string]string{"owner": Owner})
 			// Discover UAA endpoint
@@ -307,7 +378,7 @@ var _ = Describe("CF Client tests", Ordered, func() {
 			Expect(server.ReceivedRequests()[2].RequestURI).To(ContainSubstring(Owner))
 
 			// test space 2
-			spaceClient2, err2 := NewSpaceClient(SpaceName2, url, Username, Password)
+			spaceClient2, err2 := NewSpaceClient(SpaceName2, url, Username, Password, nil)
 			Expect(err2).To(BeNil())
 			spaceClient2.GetInstance(ctx, map[string]string{"owner": Owner2})
 			// no discovery of UAA endpoint or oAuth token here due to caching
@@ -316,8 +387,655 @@ var _ = Describe("CF Client tests", Ordered, func() {
 			Expect(server.ReceivedRequests()[3].RequestURI).To(ContainSubstring(Owner2))
 		})
 
+		It("should report maintenance upgrades offered by the broker", func() {
+			server.RouteToHandler("GET", serviceInstancesURI, ghttp.RespondWith(http.StatusOK, `{
+				"pagination": {"total_results": 1, "total_pages": 1},
+				"resources": [{
+					"guid": "instance-guid",
+					"name": "instance",
+					"type": "managed",
+					"upgrade_available": true,
+					"maintenance_info": {"version": "1.0.0"},
+					"last_operation": {"type": "create", "state": "succeeded"},
+					"relationships": {"service_plan": {"data": {"guid": "plan-guid"}}},
+					"metadata": {"labels": {}, "annotations": {
+						"service-operator.cf.cs.sap.com/generation": "1",
+						"service-operator.cf.cs.sap.com/parameter-hash": "hash"
+					}}
+				}]
+			}`))
+			server.RouteToHandler("GET", servicePlansURI+"/plan-guid", ghttp.RespondWith(http.StatusOK, `{
+				"guid": "plan-guid",
+				"name": "plan",
+				"maintenance_info": {"version": "1.1.0", "description": "security fixes"}
+			}`))
+
+			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password, nil)
+			Expect(err).To(BeNil())
+			instance, err := spaceClient.GetInstance(ctx, map[string]string{"owner": Owner})
+			Expect(err).To(BeNil())
+
+			Expect(instance.MaintenanceInfo).To(Equal(&facade.MaintenanceInfo{Version: "1.0.0"}))
+			Expect(instance.UpgradeAvailable).To(BeTrue())
+			Expect(instance.AvailableMaintenanceInfo).To(Equal(&facade.MaintenanceInfo{Version: "1.1.0", Description: "security fixes"}))
+		})
+
+		It("should list managed service instances", func() {
+			server.RouteToHandler("GET", serviceInstancesURI, ghttp.CombineHandlers(
+				ghttp.VerifyFormKV("label_selector", "service-operator.cf.cs.sap.com/owner"),
+				ghttp.VerifyFormKV("space_guids", SpaceName),
+				ghttp.RespondWith(http.StatusOK, `{
+					"pagination": {"total_results": 1, "total_pages": 1},
+					"resources": [{
+						"guid": "instance-guid",
+						"name": "instance",
+						"type": "managed",
+						"last_operation": {"type": "create", "state": "succeeded"},
+						"relationships": {"service_plan": {"data": {"guid": "plan-guid"}}},
+						"metadata": {
+							"labels": {"service-operator.cf.cs.sap.com/owner": "owner-uid"},
+							"annotations": {"service-operator.cf.cs.sap.com/generation": "2", "service-operator.cf.cs.sap.com/parameter-hash": "hash"}
+						}
+					}]
+				}`),
+			))
+
+			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password, nil)
+			Expect(err).To(BeNil())
+			instances, err := spaceClient.ListInstances(ctx)
+			Expect(err).To(BeNil())
+			Expect(instances).To(Equal([]*facade.Instance{{
+				Guid:            "instance-guid",
+				Name:            "instance",
+				ServicePlanGuid: "plan-guid",
+				Owner:           "owner-uid",
+				Generation:      2,
+				ParameterHash:   "hash",
+				TagsHash:        facade.TagsHash(nil),
+				State:           facade.InstanceStateReady,
+				LastOperation:   &facade.LastOperation{Type: "create", State: "succeeded"},
+			}}))
+		})
+
+		It("should read all pages of service instances, in parallel and in page order", func() {
+			server.RouteToHandler("GET", serviceInstancesURI, func(w http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+				Expect(req.URL.Query().Get("per_page")).To(Equal("2"))
+				page := req.URL.Query().Get("page")
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{
+					"pagination": {"total_results": 5, "total_pages": 3},
+					"resources": [{
+						"guid": "instance-guid-%s",
+						"name": "instance-%s",
+						"last_operation": {"type": "create", "state": "succeeded"},
+						"relationships": {"service_plan": {"data": {"guid": "plan-guid"}}},
+						"metadata": {"labels": {"service-operator.cf.cs.sap.com/owner": "owner-uid"}, "annotations": {"service-operator.cf.cs.sap.com/generation": "1", "service-operator.cf.cs.sap.com/parameter-hash": "hash"}}
+					}]
+				}`, page, page)
+			})
+
+			cfg := config.Defaults()
+			cfg.ListPageSize = 2
+			cfg.ListParallelism = 2
+			listDurationSamples := func() uint64 {
+				metric := &dto.Metric{}
+				Expect(listDuration.WithLabelValues(resourceTypeInstance).(prometheus.Histogram).Write(metric)).To(Succeed())
+				return metric.GetHistogram().GetSampleCount()
+			}
+			samples := listDurationSamples()
+			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password, cfg)
+			Expect(err).To(BeNil())
+			instances, err := spaceClient.ListInstances(ctx)
+			Expect(err).To(BeNil())
+			Expect(instances).To(HaveLen(3))
+			for i, instance := range instances {
+				Expect(instance.Guid).To(Equal(fmt.Sprintf("instance-guid-%d", i+1)))
+			}
+			Expect(listDurationSamples()).To(Equal(samples + 1))
+		})
+
+		It("should look up orphaned service instances by guid within the space", func() {
+			server.RouteToHandler("GET", serviceInstancesURI, ghttp.CombineHandlers(
+				ghttp.VerifyFormKV("guids", "instance-guid"),
+				ghttp.VerifyFormKV("space_guids", SpaceName),
+				ghttp.RespondWith(http.StatusOK, `{
+					"pagination": {"total_results": 1, "total_pages": 1},
+					"resources": [{
+						"guid": "instance-guid",
+						"name": "renamed-instance",
+						"type": "managed",
+						"last_operation": {"type": "create", "state": "succeeded"},
+						"relationships": {"service_plan": {"data": {"guid": "plan-guid"}}},
+						"metadata": {
+							"labels": {"service-operator.cf.cs.sap.com/owner": "deleted-uid"},
+							"annotations": {"service-operator.cf.cs.sap.com/generation": "2", "service-operator.cf.cs.sap.com/parameter-hash": "hash"}
+						}
+					}]
+				}`),
+			))
+
+			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password, nil)
+			Expect(err).To(BeNil())
+			instance, err := spaceClient.GetInstance(ctx, map[string]string{"owner": Owner, "guid": "instance-guid"})
+			Expect(err).To(BeNil())
+			Expect(instance.Guid).To(Equal("instance-guid"))
+			Expect(instance.Name).To(Equal("renamed-instance"))
+			// the generation and parameter hash are reset, such that the adopting object's spec is applied
+			Expect(instance.Generation).To(BeZero())
+			Expect(instance.ParameterHash).To(Equal("0"))
+		})
+
+		It("should find user-provided service instances without service plan by name", func() {
+			server.RouteToHandler("GET", serviceInstancesURI, ghttp.CombineHandlers(
+				ghttp.VerifyFormKV("names", "instance"),
+				ghttp.VerifyFormKV("space_guids", SpaceName),
+				ghttp.RespondWith(http.StatusOK, `{
+					"pagination": {"total_results": 1, "total_pages": 1},
+					"resources": [{
+						"guid": "instance-guid",
+						"name": "instance",
+						"type": "user-provided",
+						"last_operation": {"type": "create", "state": "succeeded"},
+						"relationships": {"space": {"data": {"guid": "space-guid"}}},
+						"metadata": {"labels": {}, "annotations": {}}
+					}]
+				}`),
+			))
+
+			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password, nil)
+			Expect(err).To(BeNil())
+			instance, err := spaceClient.FindInstanceName(ctx, "instance")
+			Expect(err).To(BeNil())
+			Expect(instance).To(Equal(&facade.InstanceName{Guid: "instance-guid", Name: "instance"}))
+		})
+
+		It("should look up service instances created by other tools by label selector", func() {
+			server.RouteToHandler("GET", serviceInstancesURI, ghttp.CombineHandlers(
+				ghttp.VerifyFormKV("label_selector", "managed-by=terraform,!service-operator.cf.cs.sap.com/owner"),
+				ghttp.VerifyFormKV("space_guids", SpaceName),
+				ghttp.RespondWith(http.StatusOK, `{
+					"pagination": {"total_results": 1, "total_pages": 1},
+					"resources": [{
+						"guid": "instance-guid",
+						"name": "terraform-instance",
+						"type": "managed",
+						"last_operation": {"type": "create", "state": "succeeded"},
+						"relationships": {"service_plan": {"data": {"guid": "plan-guid"}}},
+						"metadata": {"labels": {"managed-by": "terraform"}, "annotations": {}}
+					}]
+				}`),
+			))
+			server.RouteToHandler("GET", serviceInstancesURI+"/instance-guid", ghttp.RespondWith(http.StatusOK, `{
+				"guid": "instance-guid",
+				"metadata": {"labels": {"managed-by": "terraform"}, "annotations": {}}
+			}`))
+			server.RouteToHandler("PATCH", serviceInstancesURI+"/instance-guid", ghttp.CombineHandlers(
+				ghttp.VerifyJSON(`{
+					"metadata": {
+						"labels": {"managed-by": null},
+						"annotations": {"service-operator.cf.cs.sap.com/generation": "1"}
+					}
+				}`),
+				ghttp.RespondWith(http.StatusAccepted, nil, http.Header{"Location": []string{url + "/v3/jobs/job-guid"}}),
+			))
+
+			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password, nil)
+			Expect(err).To(BeNil())
+			instance, err := spaceClient.GetInstance(ctx, map[string]string{"owner": Owner, "labelSelector": "managed-by=terraform"})
+			Expect(err).To(BeNil())
+			Expect(instance.Guid).To(Equal("instance-guid"))
+			Expect(instance.Owner).To(Equal(Owner))
+			Expect(instance.Generation).To(BeZero())
+			Expect(spaceClient.UpdateInstance(ctx, "instance-guid", instance.OwnerRef(), "", "", nil, nil, &facade.Metadata{RemovedLabels: []string{"managed-by"}}, 1)).To(Succeed())
+		})
+
+		It("should check the service instances quota of the space", func() {
+			server.RouteToHandler("GET", spacesURI+"/"+SpaceName, ghttp.RespondWith(http.StatusOK, `{
+				"guid": "`+SpaceName+`",
+				"relationships": {"quota": {"data": {"guid": "quota-guid"}}}
+			}`))
+			server.RouteToHandler("GET", "/v3/space_quotas/quota-guid", ghttp.RespondWith(http.StatusOK, `{
+				"guid": "quota-guid",
+				"name": "small",
+				"services": {"total_service_instances": 10}
+			}`))
+			server.RouteToHandler("GET", serviceInstancesURI, ghttp.CombineHandlers(
+				ghttp.VerifyFormKV("space_guids", SpaceName),
+				ghttp.RespondWith(http.StatusOK, `{"pagination": {"total_results": 9, "total_pages": 9}, "resources": [{"guid": "instance-guid"}]}`),
+			))
+
+			checker, err := NewSpaceHealthChecker(SpaceName, url, Username, Password, nil)
+			Expect(err).To(BeNil())
+			Expect(checker.CheckQuota(ctx, 90)).To(Succeed())
+			Expect(checker.CheckQuota(ctx, 80)).To(MatchError(ContainSubstring("9 of 10 service instances used")))
+		})
+
+		It("should get spaces by guid", func() {
+			server.RouteToHandler("GET", spacesURI+"/"+SpaceName, ghttp.RespondWith(http.StatusOK, `{
+				"guid": "`+SpaceName+`",
+				"name": "my-space",
+				"metadata": {"labels": {}, "annotations": {}}
+			}`))
+			server.RouteToHandler("GET", spacesURI+"/unknown-guid", ghttp.RespondWith(http.StatusNotFound, `{
+				"errors": [{"code": 10010, "title": "CF-ResourceNotFound", "detail": "Space not found"}]
+			}`))
+
+			checker, err := NewSpaceHealthChecker(SpaceName, url, Username, Password, nil)
+			Expect(err).To(BeNil())
+			space, err := checker.GetSpaceByGuid(ctx, SpaceName)
+			Expect(err).To(BeNil())
+			Expect(space).To(Equal(&facade.Space{Guid: SpaceName, Name: "my-space"}))
+			space, err = checker.GetSpaceByGuid(ctx, "unknown-guid")
+			Expect(err).To(BeNil())
+			Expect(space).To(BeNil())
+		})
+
+		It("should create app bindings for applications looked up by name", func() {
+			server.RouteToHandler("GET", appsURI, ghttp.CombineHandlers(
+				ghttp.VerifyFormKV("names", "my-app"),
+				ghttp.VerifyFormKV("space_guids", SpaceName),
+				ghttp.RespondWith(http.StatusOK, `{
+					"pagination": {"total_results": 1, "total_pages": 1},
+					"resources": [{"guid": "app-guid", "name": "my-app"}]
+				}`),
+			))
+			server.RouteToHandler("POST", serviceCredentialBindingsURI, ghttp.CombineHandlers(
+				ghttp.VerifyJSON(`{
+					"type": "app",
+					"name": "binding",
+					"relationships": {
+						"service_instance": {"data": {"guid": "instance-guid"}},
+						"app": {"data": {"guid": "app-guid"}}
+					},
+					"metadata": {
+						"labels": {"service-operator.cf.cs.sap.com/owner": "`+Owner+`"},
+						"annotations": {"service-operator.cf.cs.sap.com/generation": "1", "service-operator.cf.cs.sap.com/parameter-hash": "`+facade.ObjectHash(nil)+`"}
+					}
+				}`),
+				ghttp.RespondWith(http.StatusAccepted, nil, http.Header{"Location": []string{url + "/v3/jobs/job-guid"}}),
+			))
+
+			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password, nil)
+			Expect(err).To(BeNil())
+			appGuid, err := spaceClient.FindApp(ctx, "my-app")
+			Expect(err).To(BeNil())
+			Expect(appGuid).To(Equal("app-guid"))
+			binding, err := spaceClient.CreateBinding(ctx, "binding", "instance-guid", appGuid, nil, nil, facade.OwnerRef{UID: Owner}, 1)
+			Expect(err).To(BeNil())
+			Expect(binding.Type).To(Equal(facade.BindingTypeApp))
+
+			Expect(server.ReceivedRequests()[len(server.ReceivedRequests())-1].Method).To(Equal("POST"))
+
+			server.RouteToHandler("GET", serviceCredentialBindingsURI, ghttp.RespondWith(http.StatusOK, `{
+				"pagination": {"total_results": 1, "total_pages": 1},
+				"resources": [{
+					"guid": "binding-guid",
+					"name": "binding",
+					"type": "app",
+					"last_operation": {"type": "create", "state": "succeeded"},
+					"relationships": {"service_instance": {"data": {"guid": "instance-guid"}}, "app": {"data": {"guid": "app-guid"}}},
+					"metadata": {
+						"labels": {"service-operator.cf.cs.sap.com/owner": "`+Owner+`"},
+						"annotations": {"service-operator.cf.cs.sap.com/generation": "1", "service-operator.cf.cs.sap.com/parameter-hash": "hash"}
+					}
+				}]
+			}`))
+			binding, err = spaceClient.GetBinding(ctx, map[string]string{"owner": Owner})
+			Expect(err).To(BeNil())
+			Expect(binding.Type).To(Equal(facade.BindingTypeApp))
+			Expect(binding.AppGuid).To(Equal("app-guid"))
+			Expect(binding.ServiceInstanceGuid).To(Equal("instance-guid"))
+		})
+
+		It("should fail to look up applications which do not exist, or whose name is ambiguous", func() {
+			server.RouteToHandler("GET", appsURI, ghttp.RespondWith(http.StatusOK, `{
+				"pagination": {"total_results": 0, "total_pages": 1},
+				"resources": []
+			}`))
+			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password, nil)
+			Expect(err).To(BeNil())
+			_, err = spaceClient.FindApp(ctx, "my-app")
+			Expect(err).To(MatchError(ContainSubstring("application not found in space " + SpaceName + ", name: my-app")))
+
+			server.RouteToHandler("GET", appsURI, ghttp.RespondWith(http.StatusOK, `{
+				"pagination": {"total_results": 2, "total_pages": 1},
+				"resources": [{"guid": "app-guid-1", "name": "my-app"}, {"guid": "app-guid-2", "name": "my-app"}]
+			}`))
+			_, err = spaceClient.FindApp(ctx, "my-app")
+			Expect(err).To(MatchError(ContainSubstring("found multiple applications with name: my-app")))
+		})
+
+		It("should create replacement bindings, and promote them", func() {
+			server.RouteToHandler("POST", serviceCredentialBindingsURI, ghttp.CombineHandlers(
+				ghttp.VerifyJSON(`{
+					"type": "key",
+					"name": "binding-replacement",
+					"relationships": {"service_instance": {"data": {"guid": "instance-guid"}}},
+					"metadata": {
+						"labels": {"service-operator.cf.cs.sap.com/replacement-owner": "`+Owner+`"},
+						"annotations": {"service-operator.cf.cs.sap.com/generation": "1", "service-operator.cf.cs.sap.com/parameter-hash": "`+facade.ObjectHash(nil)+`"}
+					}
+				}`),
+				ghttp.RespondWith(http.StatusAccepted, nil, http.Header{"Location": []string{url + "/v3/jobs/job-guid"}}),
+			))
+			server.RouteToHandler("PATCH", serviceCredentialBindingsURI+"/replacement-guid", ghttp.CombineHandlers(
+				ghttp.VerifyJSON(`{
+					"metadata": {
+						"labels": {"service-operator.cf.cs.sap.com/owner": "`+Owner+`", "service-operator.cf.cs.sap.com/replacement-owner": null},
+						"annotations": {}
+					}
+				}`),
+				ghttp.RespondWith(http.StatusOK, `{"guid": "replacement-guid"}`),
+			))
+
+			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password, nil)
+			Expect(err).To(BeNil())
+			Expect(spaceClient.CreateBinding(ctx, "binding-replacement", "instance-guid", "", nil, nil, facade.OwnerRef{UID: Owner, Replacement: true}, 1)).Error().NotTo(HaveOccurred())
+			Expect(spaceClient.PromoteBinding(ctx, "replacement-guid", facade.OwnerRef{UID: Owner})).To(Succeed())
+
+			Expect(server.ReceivedRequests()[len(server.ReceivedRequests())-1].Method).To(Equal("PATCH"))
+		})
+
+		It("should record the owning object in labels", func() {
+			server.RouteToHandler("POST", serviceCredentialBindingsURI, ghttp.CombineHandlers(
+				ghttp.VerifyJSON(`{
+					"type": "key",
+					"name": "binding",
+					"relationships": {"service_instance": {"data": {"guid": "instance-guid"}}},
+					"metadata": {
+						"labels": {
+							"service-operator.cf.cs.sap.com/owner": "`+Owner+`",
+							"service-operator.cf.cs.sap.com/owner-namespace": "ns",
+							"service-operator.cf.cs.sap.com/owner-name": "binding"
+						},
+						"annotations": {"service-operator.cf.cs.sap.com/generation": "1", "service-operator.cf.cs.sap.com/parameter-hash": "`+facade.ObjectHash(nil)+`"}
+					}
+				}`),
+				ghttp.RespondWith(http.StatusAccepted, nil, http.Header{"Location": []string{url + "/v3/jobs/job-guid"}}),
+			))
+
+			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password, nil)
+			Expect(err).To(BeNil())
+			Expect(spaceClient.CreateBinding(ctx, "binding", "instance-guid", "", nil, nil, facade.OwnerRef{UID: Owner, Namespace: "ns", Name: "binding"}, 1)).Error().NotTo(HaveOccurred())
+
+			Expect(server.ReceivedRequests()[len(server.ReceivedRequests())-1].Method).To(Equal("POST"))
+		})
+
+		It("should record the configured cluster id in the owner-cluster label", func() {
+			server.RouteToHandler("POST", serviceCredentialBindingsURI, ghttp.CombineHandlers(
+				ghttp.VerifyJSON(`{
+					"type": "key",
+					"name": "binding",
+					"relationships": {"service_instance": {"data": {"guid": "instance-guid"}}},
+					"metadata": {
+						"labels": {
+							"service-operator.cf.cs.sap.com/owner": "`+Owner+`",
+							"service-operator.cf.cs.sap.com/owner-cluster": "eu10"
+						},
+						"annotations": {"service-operator.cf.cs.sap.com/generation": "1", "service-operator.cf.cs.sap.com/parameter-hash": "`+facade.ObjectHash(nil)+`"}
+					}
+				}`),
+				ghttp.RespondWith(http.StatusAccepted, nil, http.Header{"Location": []string{url + "/v3/jobs/job-guid"}}),
+			))
+			server.RouteToHandler("GET", serviceCredentialBindingsURI, ghttp.RespondWith(http.StatusOK, `{
+				"pagination": {"total_results": 1, "total_pages": 1},
+				"resources": [{
+					"guid": "binding-guid",
+					"name": "binding",
+					"type": "key",
+					"last_operation": {"type": "create", "state": "succeeded"},
+					"relationships": {"service_instance": {"data": {"guid": "instance-guid"}}},
+					"metadata": {
+						"labels": {"service-operator.cf.cs.sap.com/owner": "`+Owner+`", "service-operator.cf.cs.sap.com/owner-cluster": "eu10"},
+						"annotations": {"service-operator.cf.cs.sap.com/generation": "1", "service-operator.cf.cs.sap.com/parameter-hash": "hash"}
+					}
+				}]
+			}`))
+
+			cfg := config.Defaults()
+			cfg.ClusterID = "eu10"
+			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password, cfg)
+			Expect(err).To(BeNil())
+			Expect(spaceClient.CreateBinding(ctx, "binding", "instance-guid", "", nil, nil, facade.OwnerRef{UID: Owner}, 1)).Error().NotTo(HaveOccurred())
+
+			binding, err := spaceClient.GetBinding(ctx, map[string]string{"owner": Owner})
+			Expect(err).To(BeNil())
+			Expect(binding.OwnerCluster).To(Equal("eu10"))
+			Expect(binding.CustomMetadata).To(BeFalse())
+		})
+
+		It("should set custom metadata, and remove custom metadata no longer specified", func() {
+			server.RouteToHandler("GET", serviceInstancesURI+"/instance-guid", ghttp.RespondWith(http.StatusOK, `{
+				"guid": "instance-guid",
+				"metadata": {
+					"labels": {"cost-center": "0815", "stale": "true", "foreign": "true"},
+					"annotations": {"service-operator.cf.cs.sap.com/metadata-keys": "{\"labels\":[\"cost-center\",\"stale\"]}"}
+				}
+			}`))
+			server.RouteToHandler("PATCH", serviceInstancesURI+"/instance-guid", ghttp.CombineHandlers(
+				ghttp.VerifyJSON(`{
+					"metadata": {
+						"labels": {"cost-center": "4711", "stale": null},
+						"annotations": {
+							"service-operator.cf.cs.sap.com/generation": "2",
+							"contact": "team@example.com",
+							"service-operator.cf.cs.sap.com/metadata-keys": "{\"labels\":[\"cost-center\"],\"annotations\":[\"contact\"]}"
+						}
+					}
+				}`),
+				ghttp.RespondWith(http.StatusAccepted, nil, http.Header{"Location": []string{url + "/v3/jobs/job-guid"}}),
+			))
+
+			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password, nil)
+			Expect(err).To(BeNil())
+			metadata := &facade.Metadata{Labels: map[string]string{"cost-center": "4711"}, Annotations: map[string]string{"contact": "team@example.com"}}
+			Expect(spaceClient.UpdateInstance(ctx, "instance-guid", facade.OwnerRef{UID: Owner}, "", "", nil, nil, metadata, 2)).To(Succeed())
+
+			Expect(server.ReceivedRequests()[len(server.ReceivedRequests())-1].Method).To(Equal("PATCH"))
+		})
+
+		It("should get routes by owner and bind them to route services", func() {
+			server.RouteToHandler("GET", routesURI, ghttp.CombineHandlers(
+				ghttp.VerifyFormKV("space_guids", SpaceName),
+				ghttp.VerifyFormKV("label_selector", "service-operator.cf.cs.sap.com/owner="+Owner),
+				ghttp.RespondWith(http.StatusOK, `{
+					"pagination": {"total_results": 1, "total_pages": 1},
+					"resources": [{
+						"guid": "route-guid",
+						"host": "my-app",
+						"path": "/api",
+						"url": "my-app.example.com/api",
+						"relationships": {"domain": {"data": {"guid": "domain-guid"}}},
+						"metadata": {
+							"labels": {"service-operator.cf.cs.sap.com/owner": "`+Owner+`"},
+							"annotations": {"service-operator.cf.cs.sap.com/generation": "2"}
+						}
+					}]
+				}`),
+			))
+			server.RouteToHandler("POST", serviceRouteBindingsURI, ghttp.CombineHandlers(
+				ghttp.VerifyJSON(`{
+					"relationships": {
+						"route": {"data": {"guid": "route-guid"}},
+						"service_instance": {"data": {"guid": "instance-guid"}}
+					},
+					"parameters": {"limit": 100},
+					"metadata": {
+						"labels": {"service-operator.cf.cs.sap.com/owner": "`+Owner2+`"},
+						"annotations": {"service-operator.cf.cs.sap.com/generation": "1", "service-operator.cf.cs.sap.com/parameter-hash": "`+facade.ObjectHash(map[string]interface{}{"limit": 100})+`"}
+					}
+				}`),
+				ghttp.RespondWith(http.StatusAccepted, nil, http.Header{"Location": []string{url + "/v3/jobs/job-guid"}}),
+			))
+
+			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password, nil)
+			Expect(err).To(BeNil())
+			route, err := spaceClient.GetRoute(ctx, Owner)
+			Expect(err).To(BeNil())
+			Expect(route).To(Equal(&facade.Route{
+				Guid:       "route-guid",
+				Host:       "my-app",
+				Path:       "/api",
+				DomainGuid: "domain-guid",
+				URL:        "my-app.example.com/api",
+				Owner:      Owner,
+				Generation: 2,
+			}))
+			Expect(spaceClient.CreateRouteBinding(ctx, route.Guid, "instance-guid", map[string]interface{}{"limit": 100}, Owner2, 1)).To(Succeed())
+
+			Expect(server.ReceivedRequests()[len(server.ReceivedRequests())-1].Method).To(Equal("POST"))
+		})
+
+		It("should tolerate route bindings without annotations", func() {
+			server.RouteToHandler("GET", serviceRouteBindingsURI, ghttp.CombineHandlers(
+				ghttp.VerifyFormKV("label_selector", "service-operator.cf.cs.sap.com/owner="+Owner),
+				ghttp.RespondWith(http.StatusOK, `{
+					"pagination": {"total_results": 1, "total_pages": 1},
+					"resources": [{
+						"guid": "route-binding-guid",
+						"last_operation": {"type": "create", "state": "succeeded"},
+						"relationships": {"route": {"data": {"guid": "route-guid"}}, "service_instance": {"data": {"guid": "instance-guid"}}},
+						"metadata": {"labels": {"service-operator.cf.cs.sap.com/owner": "`+Owner+`"}}
+					}]
+				}`),
+			))
+
+			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password, nil)
+			Expect(err).To(BeNil())
+			routeBinding, err := spaceClient.GetRouteBinding(ctx, Owner)
+			Expect(err).To(BeNil())
+			Expect(routeBinding.Guid).To(Equal("route-binding-guid"))
+			Expect(routeBinding.Generation).To(BeZero())
+			Expect(routeBinding.ParameterHash).To(BeEmpty())
+			Expect(routeBinding.State).To(Equal(facade.BindingStateReady))
+		})
+
+		It("should apply maintenance upgrades", func() {
+			server.RouteToHandler("PATCH", serviceInstancesURI+"/instance-guid", ghttp.CombineHandlers(
+				ghttp.VerifyJSON(`{"maintenance_info": {"version": "1.1.0", "description": "security fixes"}}`),
+				ghttp.RespondWith(http.StatusAccepted, nil, http.Header{"Location": []string{url + "/v3/jobs/job-guid"}}),
+			))
+
+			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password, nil)
+			Expect(err).To(BeNil())
+			err = spaceClient.UpgradeInstance(ctx, "instance-guid", facade.OwnerRef{UID: Owner}, facade.MaintenanceInfo{Version: "1.1.0", Description: "security fixes"})
+			Expect(err).To(BeNil())
+
+			Expect(server.ReceivedRequests()[len(server.ReceivedRequests())-1].Method).To(Equal("PATCH"))
+		})
+
+		It("should publish changes made through the client", func() {
+			server.RouteToHandler("DELETE", serviceInstancesURI+"/instance-guid", ghttp.RespondWith(http.StatusAccepted, nil, http.Header{"Location": []string{url + "/v3/jobs/job-guid"}}))
+			bus := events.NewBus()
+			SetEventBus(bus)
+			defer SetEventBus(nil)
+			ch, unsubscribe := bus.Subscribe(10, nil)
+			defer unsubscribe()
+
+			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password, nil)
+			Expect(err).To(BeNil())
+			Expect(spaceClient.DeleteInstance(ctx, "instance-guid", facade.OwnerRef{UID: Owner})).To(Succeed())
+
+			Expect(ch).To(Receive(Equal(events.Event{
+				Type:         events.EventTypeDeleted,
+				ResourceType: events.ResourceTypeInstance,
+				Guid:         "instance-guid",
+				Owner:        Owner,
+				SpaceGuid:    SpaceName,
+			})))
+		})
+
+		It("should resolve service plans from the cached catalog", func() {
+			server.RouteToHandler("GET", serviceOfferingsURI, ghttp.RespondWith(http.StatusOK, `{
+				"pagination": {"total_results": 2, "total_pages": 1},
+				"resources": [
+					{"guid": "offering-guid", "name": "offering"},
+					{"guid": "other-offering-guid", "name": "other-offering"}
+				]
+			}`))
+			server.RouteToHandler("GET", servicePlansURI, ghttp.RespondWith(http.StatusOK, `{
+				"pagination": {"total_results": 3, "total_pages": 1},
+				"resources": [
+					{"guid": "plan-guid", "name": "plan", "relationships": {"service_offering": {"data": {"guid": "offering-guid"}}},
+					 "schemas": {"service_instance": {"create": {"parameters": {"type": "object", "required": ["size"]}}, "update": {"parameters": {}}}}},
+					{"guid": "other-plan-guid", "name": "other-plan", "relationships": {"service_offering": {"data": {"guid": "offering-guid"}}}},
+					{"guid": "foreign-plan-guid", "name": "plan", "relationships": {"service_offering": {"data": {"guid": "other-offering-guid"}}}}
+				]
+			}`))
+
+			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password, config.Defaults())
+			Expect(err).To(BeNil())
+			guid, err := spaceClient.FindServicePlan(ctx, "offering", "plan", SpaceName)
+			Expect(err).To(BeNil())
+			Expect(guid).To(Equal("plan-guid"))
+			numRequests := len(server.ReceivedRequests())
+
+			guid, err = spaceClient.FindServicePlan(ctx, "other-offering", "plan", SpaceName)
+			Expect(err).To(BeNil())
+			Expect(guid).To(Equal("foreign-plan-guid"))
+			_, err = spaceClient.FindServicePlan(ctx, "offering", "missing-plan", SpaceName)
+			Expect(err).To(MatchError(&facade.ServicePlanNotFoundError{ServiceOfferingName: "offering", ServicePlanName: "missing-plan"}))
+			servicePlans, err := spaceClient.ListServicePlans(ctx, SpaceName)
+			Expect(err).To(BeNil())
+			Expect(servicePlans).To(ContainElement(facade.ServicePlan{
+				Guid:                "other-plan-guid",
+				Name:                "other-plan",
+				ServiceOfferingGuid: "offering-guid",
+				ServiceOfferingName: "offering",
+			}))
+			schemas, err := spaceClient.GetServicePlanSchemas(ctx, "plan-guid", SpaceName)
+			Expect(err).To(BeNil())
+			Expect(schemas.InstanceCreate).To(MatchJSON(`{"type": "object", "required": ["size"]}`))
+			Expect(schemas.InstanceUpdate).To(BeNil())
+			Expect(schemas.BindingCreate).To(BeNil())
+			schemas, err = spaceClient.GetServicePlanSchemas(ctx, "unknown-plan-guid", SpaceName)
+			Expect(err).To(BeNil())
+			Expect(schemas).To(BeNil())
+
+			// all lookups are served from the catalog cache
+			Expect(server.ReceivedRequests()).To(HaveLen(numRequests))
+		})
+
+		It("should look up single service plans and their schemas if the catalog cache is disabled", func() {
+			server.RouteToHandler("GET", serviceOfferingsURI, ghttp.CombineHandlers(
+				ghttp.VerifyFormKV("names", "offering"),
+				ghttp.RespondWith(http.StatusOK, `{
+					"pagination": {"total_results": 1, "total_pages": 1},
+					"resources": [{"guid": "offering-guid", "name": "offering"}]
+				}`),
+			))
+			server.RouteToHandler("GET", servicePlansURI, ghttp.CombineHandlers(
+				ghttp.VerifyFormKV("names", "plan"),
+				ghttp.VerifyFormKV("service_offering_names", "offering"),
+				ghttp.RespondWith(http.StatusOK, `{
+					"pagination": {"total_results": 1, "total_pages": 1},
+					"resources": [{"guid": "plan-guid", "name": "plan", "relationships": {"service_offering": {"data": {"guid": "offering-guid"}}}}]
+				}`),
+			))
+			server.RouteToHandler("GET", servicePlansURI+"/plan-guid", ghttp.RespondWith(http.StatusOK, `{
+				"guid": "plan-guid", "name": "plan",
+				"schemas": {"service_instance": {"create": {"parameters": {"type": "object", "required": ["size"]}}}}
+			}`))
+			server.RouteToHandler("GET", servicePlansURI+"/unknown-plan-guid", ghttp.RespondWith(http.StatusNotFound, `{
+				"errors": [{"code": 10010, "title": "CF-ResourceNotFound", "detail": "Service plan not found"}]
+			}`))
+
+			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password, nil)
+			Expect(err).To(BeNil())
+			guid, err := spaceClient.FindServicePlan(ctx, "offering", "plan", SpaceName)
+			Expect(err).To(BeNil())
+			Expect(guid).To(Equal("plan-guid"))
+			schemas, err := spaceClient.GetServicePlanSchemas(ctx, "plan-guid", SpaceName)
+			Expect(err).To(BeNil())
+			Expect(schemas.InstanceCreate).To(MatchJSON(`{"type": "object", "required": ["size"]}`))
+			schemas, err = spaceClient.GetServicePlanSchemas(ctx, "unknown-plan-guid", SpaceName)
+			Expect(err).To(BeNil())
+			Expect(schemas).To(BeNil())
+		})
+
 		It("should register prometheus metrics for OrgClient", func() {
-			orgClient, err := NewOrganizationClient(OrgName, url, Username, Password)
+			orgClient, err := NewOrganizationClient(OrgName, url, Username, Password, nil)
 			Expect(err).To(BeNil())
 			Expect(orgClient).ToNot(BeNil())
 
@@ -336,7 +1054,7 @@ var _ = Describe("CF Client tests", Ordered, func() {
 		})
 
 		It("should register prometheus metrics for SpaceClient", func() {
-			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password)
+			spaceClient, err := NewSpaceClient(SpaceName, url, Username, Password, nil)
 			Expect(err).To(BeNil())
 			Expect(spaceClient).ToNot(BeNil())
 
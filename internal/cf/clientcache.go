@@ -0,0 +1,154 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cf
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// reasons for evicting cached clients (label of the eviction metric)
+const (
+	clientEvictionReasonIdle           = "idle"
+	clientEvictionReasonCapacity       = "capacity"
+	clientEvictionReasonAuthentication = "authentication"
+)
+
+// evictClientCacheEntry removes the cached client with the given identifier (including its resource and catalog caches).
+// Must be called with cacheMutex locked.
+func evictClientCacheEntry(identifier clientIdentifier, reason string) {
+	cacheEntry, ok := clientCache[identifier]
+	if !ok {
+		return
+	}
+	delete(clientCache, identifier)
+	clientCacheEvictions.WithLabelValues(reason).Inc()
+
+	// forget the reachability of endpoints which are no longer used by any client
+	for _, other := range clientCache {
+		if other.url == cacheEntry.url {
+			return
+		}
+	}
+	reachabilityMutex.Lock()
+	defer reachabilityMutex.Unlock()
+	delete(reachability, cacheEntry.url)
+}
+
+// evictIdleClients removes all cached clients which were not used since the given time; returns the number of evicted clients.
+// Must be called with cacheMutex locked.
+func evictIdleClients(usedBefore time.Time) int {
+	count := 0
+	for identifier, cacheEntry := range clientCache {
+		if cacheEntry.lastUsedAt.Before(usedBefore) {
+			evictClientCacheEntry(identifier, clientEvictionReasonIdle)
+			count++
+		}
+	}
+	return count
+}
+
+// evictLeastRecentlyUsedClients removes the least recently used cached clients, until fewer than maxEntries clients are cached
+// (making room for a new one); zero means no limit.
+// Must be called with cacheMutex locked.
+func evictLeastRecentlyUsedClients(maxEntries int) {
+	if maxEntries <= 0 {
+		return
+	}
+	for len(clientCache) >= maxEntries {
+		var lruIdentifier clientIdentifier
+		var lruEntry *clientCacheEntry
+		for identifier, cacheEntry := range clientCache {
+			if lruEntry == nil || cacheEntry.lastUsedAt.Before(lruEntry.lastUsedAt) {
+				lruIdentifier, lruEntry = identifier, cacheEntry
+			}
+		}
+		evictClientCacheEntry(lruIdentifier, clientEvictionReasonCapacity)
+	}
+}
+
+// invalidateClient removes the cached client for the given credentials, unless it was replaced already
+// (for example because the password was rotated in the meantime).
+func invalidateClient(identifier clientIdentifier, password string) {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	if cacheEntry, ok := clientCache[identifier]; ok && cacheEntry.password == password {
+		evictClientCacheEntry(identifier, clientEvictionReasonAuthentication)
+	}
+}
+
+// authenticationTransport invalidates the cached client if fetching or refreshing an access token fails permanently,
+// that is, if the token endpoint rejects the credentials (401 Unauthorized) or the refresh token (400 Bad Request with
+// error invalid_grant); otherwise the client would keep failing until the operator is restarted, since the token source
+// of the client does not recover. The next reconcile then creates a new client, logging in again.
+type authenticationTransport struct {
+	transport  http.RoundTripper
+	identifier clientIdentifier
+	password   string
+}
+
+func (t *authenticationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil || !strings.HasSuffix(req.URL.Path, "/oauth/token") {
+		return resp, err
+	}
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		invalidateClient(t.identifier, t.password)
+	case http.StatusBadRequest:
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if bytes.Contains(body, []byte("invalid_grant")) {
+			invalidateClient(t.identifier, t.password)
+		}
+	}
+	return resp, nil
+}
+
+// ClientCacheJanitor periodically evicts cached CF clients (together with their resource and catalog caches)
+// which were not used for longer than the timeout, such as clients of deleted spaces, or of rotated credentials.
+type ClientCacheJanitor struct {
+	Timeout  time.Duration
+	Interval time.Duration
+}
+
+// Start evicts idle clients every interval, until the context is cancelled.
+// Implements manager.Runnable.
+func (j *ClientCacheJanitor) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithName("client-cache")
+
+	ticker := time.NewTicker(j.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		cacheMutex.Lock()
+		count := evictIdleClients(time.Now().Add(-j.Timeout))
+		cacheMutex.Unlock()
+		if count > 0 {
+			log.V(1).Info("evicted idle clients", "count", count)
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; all replicas cache clients.
+func (j *ClientCacheJanitor) NeedLeaderElection() bool {
+	return false
+}
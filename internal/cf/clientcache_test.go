@@ -0,0 +1,97 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package cf
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/sap/cf-service-operator/internal/config"
+)
+
+var _ = Describe("Client cache eviction tests", func() {
+	var server *ghttp.Server
+
+	BeforeEach(func() {
+		previousClientCache, previousReachability := clientCache, reachability
+		DeferCleanup(func() {
+			clientCache, reachability = previousClientCache, previousReachability
+		})
+		clientCache = make(map[clientIdentifier]*clientCacheEntry)
+		reachability = make(map[string]endpointReachability)
+		server = ghttp.NewServer()
+		server.RouteToHandler("GET", "/", ghttp.RespondWith(http.StatusOK, `{"links": {"self": {"href": "`+server.URL()+`"}}}`))
+		DeferCleanup(server.Close)
+	})
+
+	newSpaceClient := func(username string, cfg *config.Config) {
+		_, err := NewSpaceClient(SpaceName, server.URL(), username, Password, cfg)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	It("should evict the least recently used clients if the cache is full", func() {
+		cfg := config.Defaults()
+		cfg.ClientCacheMaxEntries = 2
+		evictions := testutil.ToFloat64(clientCacheEvictions.WithLabelValues(clientEvictionReasonCapacity))
+
+		newSpaceClient("user-a", cfg)
+		newSpaceClient("user-b", cfg)
+		clientCache[clientIdentifier{url: server.URL(), username: "user-b"}].lastUsedAt = time.Now().Add(-time.Minute)
+		newSpaceClient("user-a", cfg)
+		newSpaceClient("user-c", cfg)
+
+		Expect(clientCache).To(HaveLen(2))
+		Expect(clientCache).To(HaveKey(clientIdentifier{url: server.URL(), username: "user-a"}))
+		Expect(clientCache).To(HaveKey(clientIdentifier{url: server.URL(), username: "user-c"}))
+		Expect(testutil.ToFloat64(clientCacheEvictions.WithLabelValues(clientEvictionReasonCapacity))).To(Equal(evictions + 1))
+	})
+
+	It("should evict idle clients, and forget the reachability of endpoints no longer in use", func() {
+		newSpaceClient("user-a", nil)
+		newSpaceClient("user-b", nil)
+		clientCache[clientIdentifier{url: server.URL(), username: "user-a"}].lastUsedAt = time.Now().Add(-2 * time.Hour)
+
+		Expect(evictIdleClients(time.Now().Add(-time.Hour))).To(Equal(1))
+		Expect(clientCache).To(HaveLen(1))
+		Expect(reachability).To(HaveKey(server.URL()))
+
+		Expect(evictIdleClients(time.Now().Add(time.Second))).To(Equal(1))
+		Expect(clientCache).To(BeEmpty())
+		Expect(reachability).ToNot(HaveKey(server.URL()))
+	})
+
+	It("should invalidate clients whose token cannot be refreshed", func() {
+		newSpaceClient(Username, nil)
+		identifier := clientIdentifier{url: server.URL(), username: Username}
+		transport := &authenticationTransport{transport: http.DefaultTransport, identifier: identifier, password: Password}
+		server.RouteToHandler("POST", "/oauth/token", ghttp.RespondWith(http.StatusBadRequest, `{"error": "invalid_request"}`))
+
+		resp, err := transport.RoundTrip(newTokenRequest(server.URL()))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		Expect(clientCache).To(HaveKey(identifier))
+
+		server.RouteToHandler("POST", "/oauth/token", ghttp.RespondWith(http.StatusBadRequest, `{"error": "invalid_grant"}`))
+		resp, err = transport.RoundTrip(newTokenRequest(server.URL()))
+		Expect(err).ToNot(HaveOccurred())
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(body)).To(ContainSubstring("invalid_grant"))
+		Expect(clientCache).ToNot(HaveKey(identifier))
+	})
+})
+
+func newTokenRequest(url string) *http.Request {
+	req, err := http.NewRequest(http.MethodPost, url+"/oauth/token", strings.NewReader("grant_type=refresh_token"))
+	Expect(err).ToNot(HaveOccurred())
+	return req
+}
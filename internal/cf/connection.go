@@ -0,0 +1,57 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cf
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/sap/cf-service-operator/internal/config"
+)
+
+// configureConnection applies the CA bundle and proxy of the given configuration to the (base) transport of httpClient.
+// The CA bundle is trusted in addition to the system roots; if no proxy is configured, the standard proxy environment variables apply.
+func configureConnection(httpClient *http.Client, cfg *config.Config) error {
+	if cfg == nil || (cfg.CABundle == "" && cfg.Proxy == "") {
+		return nil
+	}
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("unable to configure connection: unexpected transport type %T", httpClient.Transport)
+	}
+	if cfg.CABundle != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(cfg.CABundle)) {
+			return fmt.Errorf("invalid CA bundle: no PEM encoded certificates found")
+		}
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL %s: %w", cfg.Proxy, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return nil
+}
+
+// connectionSettings returns the settings of cfg affecting the connection (as opposed to the credentials) of a CF client.
+func connectionSettings(cfg *config.Config) (string, string) {
+	if cfg == nil {
+		return "", ""
+	}
+	return cfg.CABundle, cfg.Proxy
+}
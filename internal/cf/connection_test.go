@@ -0,0 +1,64 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package cf
+
+import (
+	"encoding/pem"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/sap/cf-service-operator/internal/config"
+)
+
+var _ = Describe("Connection tests", func() {
+	var server *ghttp.Server
+	var httpClient *http.Client
+
+	BeforeEach(func() {
+		server = ghttp.NewTLSServer()
+		server.AppendHandlers(ghttp.RespondWith(http.StatusOK, "ok"))
+		httpClient = &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should reject untrusted server certificates by default", func() {
+		Expect(configureConnection(httpClient, config.Defaults())).To(Succeed())
+		_, err := httpClient.Get(server.URL())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should trust the configured CA bundle", func() {
+		caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.HTTPTestServer.Certificate().Raw})
+		cfg := config.Defaults().WithConnectionOverrides(string(caBundle), "")
+
+		Expect(configureConnection(httpClient, cfg)).To(Succeed())
+		resp, err := httpClient.Get(server.URL())
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	})
+
+	It("should use the configured proxy", func() {
+		cfg := config.Defaults().WithConnectionOverrides("", "http://proxy.example.com:3128")
+
+		Expect(configureConnection(httpClient, cfg)).To(Succeed())
+		req, err := http.NewRequest(http.MethodGet, "https://api.cf.example.com", nil)
+		Expect(err).ToNot(HaveOccurred())
+		proxyURL, err := httpClient.Transport.(*http.Transport).Proxy(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(proxyURL.String()).To(Equal("http://proxy.example.com:3128"))
+	})
+
+	It("should reject an invalid CA bundle", func() {
+		cfg := config.Defaults().WithConnectionOverrides("not a certificate", "")
+		Expect(configureConnection(httpClient, cfg)).To(MatchError(ContainSubstring("invalid CA bundle")))
+	})
+})
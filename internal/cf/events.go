@@ -0,0 +1,30 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cf
+
+import (
+	"sync/atomic"
+
+	"github.com/sap/cf-service-operator/internal/events"
+)
+
+// bus on which changes of Cloud Foundry resources (made or observed through any client) are published
+var eventBus atomic.Pointer[events.Bus]
+
+// SetEventBus sets the bus on which all clients publish changes of Cloud Foundry resources; nil disables publishing.
+func SetEventBus(bus *events.Bus) {
+	eventBus.Store(bus)
+}
+
+func publishEvent(eventType events.EventType, resourceType events.ResourceType, guid string, owner string, spaceGuid string) {
+	eventBus.Load().Publish(events.Event{
+		Type:         eventType,
+		ResourceType: resourceType,
+		Guid:         guid,
+		Owner:        owner,
+		SpaceGuid:    spaceGuid,
+	})
+}
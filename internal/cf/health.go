@@ -5,7 +5,17 @@ SPDX-License-Identifier: Apache-2.0
 
 package cf
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient/v3/client"
+	cfresource "github.com/cloudfoundry-community/go-cfclient/v3/resource"
+	"github.com/pkg/errors"
+
+	"github.com/sap/cf-service-operator/internal/facade"
+)
 
 func (c *spaceClient) Check(ctx context.Context) error {
 	_, err := c.client.Spaces.Get(ctx, c.spaceGuid)
@@ -14,3 +24,116 @@ func (c *spaceClient) Check(ctx context.Context) error {
 	}
 	return nil
 }
+
+func (c *spaceClient) GetSpaceByGuid(ctx context.Context, guid string) (*facade.Space, error) {
+	space, err := c.client.Spaces.Get(ctx, guid)
+	if err != nil {
+		if cfresource.IsResourceNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	result := &facade.Space{
+		Guid: space.GUID,
+		Name: space.Name,
+	}
+	// spaces not created by the operator carry neither owner nor generation
+	if space.Metadata != nil {
+		if owner := space.Metadata.Labels[labelOwner]; owner != nil {
+			result.Owner = *owner
+		}
+		if generation := space.Metadata.Annotations[annotationGeneration]; generation != nil {
+			result.Generation, err = strconv.ParseInt(*generation, 10, 64)
+			if err != nil {
+				return nil, errors.Wrap(err, "error parsing space generation")
+			}
+		}
+	}
+	return result, nil
+}
+
+func (c *spaceClient) CheckServicePlans(ctx context.Context) error {
+	listOpts := cfclient.NewServicePlanListOptions()
+	listOpts.SpaceGUIDs.EqualTo(c.spaceGuid)
+	listOpts.PerPage = 1
+	plans, _, err := c.client.ServicePlans.List(ctx, listOpts)
+	if err != nil {
+		return err
+	}
+	if len(plans) == 0 {
+		return fmt.Errorf("no service plans are available in space %s", c.spaceGuid)
+	}
+	return nil
+}
+
+func (c *spaceClient) CheckQuota(ctx context.Context, threshold int) error {
+	usage, err := c.GetQuotaUsage(ctx)
+	if err != nil {
+		return err
+	}
+	if usage.ServiceInstancesLimit < 0 {
+		// unlimited, or no space quota assigned
+		return nil
+	}
+	if usage.ServiceInstances*100 > usage.ServiceInstancesLimit*threshold {
+		return fmt.Errorf("space quota %s: %d of %d service instances used (more than %d%%)", usage.QuotaName, usage.ServiceInstances, usage.ServiceInstancesLimit, threshold)
+	}
+	return nil
+}
+
+func (c *spaceClient) GetQuotaUsage(ctx context.Context) (*facade.QuotaUsage, error) {
+	usage := &facade.QuotaUsage{ServiceInstancesLimit: -1}
+
+	space, err := c.client.Spaces.Get(ctx, c.spaceGuid)
+	if err != nil {
+		return nil, err
+	}
+	if space.Relationships.Quota != nil && space.Relationships.Quota.Data != nil {
+		quota, err := c.client.SpaceQuotas.Get(ctx, space.Relationships.Quota.Data.GUID)
+		if err != nil {
+			return nil, err
+		}
+		usage.QuotaName = quota.Name
+		if quota.Services.TotalServiceInstances != nil && *quota.Services.TotalServiceInstances >= 0 {
+			usage.ServiceInstancesLimit = *quota.Services.TotalServiceInstances
+		}
+	}
+
+	listOpts := cfclient.NewServiceInstanceListOptions()
+	listOpts.SpaceGUIDs.EqualTo(c.spaceGuid)
+	listOpts.PerPage = 1
+	_, pager, err := c.client.ServiceInstances.List(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	usage.ServiceInstances = pager.TotalResults
+	return usage, nil
+}
+
+func (c *spaceClient) CheckDeveloperRole(ctx context.Context, username string) error {
+	userListOpts := cfclient.NewUserListOptions()
+	userListOpts.UserNames.EqualTo(username)
+	users, err := c.client.Users.ListAll(ctx, userListOpts)
+	if err != nil {
+		return err
+	}
+	if len(users) > 0 {
+		roleListOpts := cfclient.NewRoleListOptions()
+		roleListOpts.SpaceGUIDs.EqualTo(c.spaceGuid)
+		userGuids := make([]string, 0, len(users))
+		for _, user := range users {
+			userGuids = append(userGuids, user.GUID)
+		}
+		roleListOpts.UserGUIDs.EqualTo(userGuids...)
+		roleListOpts.Types.EqualTo(cfresource.SpaceRoleDeveloper.String())
+		roles, err := c.client.Roles.ListAll(ctx, roleListOpts)
+		if err != nil {
+			return err
+		}
+		if len(roles) > 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("user %s does not have the space developer role in space %s", username, c.spaceGuid)
+}
@@ -10,11 +10,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"time"
 
 	cfclient "github.com/cloudfoundry-community/go-cfclient/v3/client"
 	cfresource "github.com/cloudfoundry-community/go-cfclient/v3/resource"
 	"github.com/pkg/errors"
 
+	"github.com/sap/cf-service-operator/internal/events"
 	"github.com/sap/cf-service-operator/internal/facade"
 )
 
@@ -28,6 +30,12 @@ type instanceFilterName struct {
 type instanceFilterOwner struct {
 	owner string
 }
+type instanceFilterGuid struct {
+	guid string
+}
+type instanceFilterLabelSelector struct {
+	labelSelector string
+}
 
 func (in *instanceFilterName) getListOptions() *cfclient.ServiceInstanceListOptions {
 	listOpts := cfclient.NewServiceInstanceListOptions()
@@ -41,22 +49,52 @@ func (io *instanceFilterOwner) getListOptions() *cfclient.ServiceInstanceListOpt
 	return listOpts
 }
 
-// GetInstance returns the instance with the given instanceOpts["owner"] or instanceOpts["name"].
-// If instanceOpts["name"] is empty, the instance with the given instanceOpts["owner"] is returned.
+func (ig *instanceFilterGuid) getListOptions() *cfclient.ServiceInstanceListOptions {
+	listOpts := cfclient.NewServiceInstanceListOptions()
+	listOpts.GUIDs.EqualTo(ig.guid)
+	return listOpts
+}
+
+func (il *instanceFilterLabelSelector) getListOptions() *cfclient.ServiceInstanceListOptions {
+	listOpts := cfclient.NewServiceInstanceListOptions()
+	// instances owned by some object are never matched
+	listOpts.LabelSelector.EqualTo(il.labelSelector + ",!" + labelOwner)
+	return listOpts
+}
+
+// GetInstance returns the instance with the given instanceOpts["owner"], instanceOpts["name"] or instanceOpts["guid"].
+// If instanceOpts["name"] and instanceOpts["guid"] are empty, the instance with the given instanceOpts["owner"] is returned.
 // If instanceOpts["name"] is not empty, the instance with the given Name is returned for orphan instances.
+// If instanceOpts["guid"] is not empty, the instance with the given GUID is returned for orphan instances (taking precedence over the name).
+// If instanceOpts["labelSelector"] is not empty, the instance without owner label matching the given label selector is returned
+// for instances created by other tools (taking precedence over the name).
 // If no instance is found, nil is returned.
 // If multiple instances are found, an error is returned.
 // The function add the parameter values to the orphan cf instance, so that can be adopted.
 func (c *spaceClient) GetInstance(ctx context.Context, instanceOpts map[string]string) (*facade.Instance, error) {
+	// orphan instances (looked up by name, guid or label selector) are never cached
+	orphan := instanceOpts["name"] != "" || instanceOpts["guid"] != "" || instanceOpts["labelSelector"] != ""
+	if !orphan {
+		if instance, ok := c.resourceCache.getInstance(instanceOpts["owner"]); ok {
+			return instance, nil
+		}
+		defer c.resourceCache.observeRefresh(resourceTypeInstance, time.Now())
+	}
 
 	var filterOpts instanceFilter
-	if instanceOpts["name"] != "" {
+	if instanceOpts["guid"] != "" {
+		filterOpts = &instanceFilterGuid{guid: instanceOpts["guid"]}
+	} else if instanceOpts["labelSelector"] != "" {
+		filterOpts = &instanceFilterLabelSelector{labelSelector: instanceOpts["labelSelector"]}
+	} else if instanceOpts["name"] != "" {
 		filterOpts = &instanceFilterName{name: instanceOpts["name"]}
 	} else {
 		filterOpts = &instanceFilterOwner{owner: instanceOpts["owner"]}
 	}
 	listOpts := filterOpts.getListOptions()
-	serviceInstances, err := c.client.ServiceInstances.ListAll(ctx, listOpts)
+	// restrict the lookup to the client's space, instead of all service instances visible to the credentials
+	listOpts.SpaceGUIDs.EqualTo(c.spaceGuid)
+	serviceInstances, err := listAllPages(ctx, c.paging, resourceTypeInstance, listOpts, c.client.ServiceInstances.List)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list service instances: %w", err)
 	}
@@ -69,14 +107,104 @@ func (c *spaceClient) GetInstance(ctx context.Context, instanceOpts map[string]s
 
 	serviceInstance := serviceInstances[0]
 
-	// add parameter values to the orphan cf instance
-	if instanceOpts["name"] != "" {
+	owner := instanceOpts["owner"]
+	if orphan {
+		// add parameter values to the orphan cf instance
 		generationvalue := "0"
 		serviceInstance.Metadata.Annotations[annotationGeneration] = &generationvalue
 		parameterHashValue := "0"
 		serviceInstance.Metadata.Annotations[annotationParameterHash] = &parameterHashValue
 	}
 
+	result, err := newInstance(serviceInstance, owner)
+	if err != nil {
+		return nil, err
+	}
+	if serviceInstance.UpgradeAvailable != nil && *serviceInstance.UpgradeAvailable {
+		// the offered version is published by the service plan
+		servicePlan, err := c.client.ServicePlans.Get(ctx, result.ServicePlanGuid)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get service plan: %w", err)
+		}
+		result.UpgradeAvailable = true
+		result.AvailableMaintenanceInfo = &facade.MaintenanceInfo{
+			Version:     servicePlan.MaintenanceInfo.Version,
+			Description: servicePlan.MaintenanceInfo.Description,
+		}
+	}
+	if !orphan {
+		c.resourceCache.addInstance(result)
+		publishEvent(events.EventTypeRefreshed, events.ResourceTypeInstance, result.Guid, result.Owner, c.spaceGuid)
+	}
+	return result, nil
+}
+
+// ListInstances returns all service instances in the client's space which are owned by some Kubernetes object
+// (that is, carry the owner label), no matter if the owning object still exists; the resource cache is bypassed.
+// Note that AvailableMaintenanceInfo is not populated for the returned instances.
+func (c *spaceClient) ListInstances(ctx context.Context) ([]*facade.Instance, error) {
+	listOpts := cfclient.NewServiceInstanceListOptions()
+	listOpts.LabelSelector.EqualTo(labelOwner)
+	listOpts.SpaceGUIDs.EqualTo(c.spaceGuid)
+	serviceInstances, err := listAllPages(ctx, c.paging, resourceTypeInstance, listOpts, c.client.ServiceInstances.List)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service instances: %w", err)
+	}
+
+	result := make([]*facade.Instance, 0, len(serviceInstances))
+	for _, serviceInstance := range serviceInstances {
+		instance, err := newInstance(serviceInstance, *serviceInstance.Metadata.Labels[labelOwner])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid service instance %s", serviceInstance.GUID)
+		}
+		result = append(result, instance)
+	}
+	return result, nil
+}
+
+// FindInstanceName looks up the service instance with the given name in the client's space (of any type, including user-provided
+// instances, which have no service plan); only guid, name and owner label are evaluated. The resource cache is bypassed.
+func (c *spaceClient) FindInstanceName(ctx context.Context, name string) (*facade.InstanceName, error) {
+	listOpts := cfclient.NewServiceInstanceListOptions()
+	listOpts.Names.EqualTo(name)
+	listOpts.SpaceGUIDs.EqualTo(c.spaceGuid)
+	serviceInstances, err := listAllPages(ctx, c.paging, resourceTypeInstance, listOpts, c.client.ServiceInstances.List)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service instances: %w", err)
+	}
+	if len(serviceInstances) == 0 {
+		return nil, nil
+	}
+
+	// instance names are unique within a space
+	serviceInstance := serviceInstances[0]
+	result := &facade.InstanceName{Guid: serviceInstance.GUID, Name: serviceInstance.Name}
+	if serviceInstance.Metadata != nil {
+		if owner := serviceInstance.Metadata.Labels[labelOwner]; owner != nil {
+			result.Owner = *owner
+		}
+	}
+	return result, nil
+}
+
+// GetInstanceParameters reads the parameters of the instance with the given guid from the service broker (through Cloud Foundry);
+// the resource cache is bypassed.
+func (c *spaceClient) GetInstanceParameters(ctx context.Context, guid string) (map[string]interface{}, error) {
+	rawParameters, err := c.client.ServiceInstances.GetManagedParameters(ctx, guid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service instance parameters: %w", err)
+	}
+	var parameters map[string]interface{}
+	if rawParameters != nil {
+		if err := json.Unmarshal(*rawParameters, &parameters); err != nil {
+			return nil, errors.Wrap(err, "error parsing service instance parameters")
+		}
+	}
+	return parameters, nil
+}
+
+// newInstance converts the given CF service instance, owned by the given owner.
+func newInstance(serviceInstance *cfresource.ServiceInstance, owner string) (*facade.Instance, error) {
 	guid := serviceInstance.GUID
 	name := serviceInstance.Name
 	servicePlanGuid := serviceInstance.Relationships.ServicePlan.Data.GUID
@@ -85,6 +213,11 @@ func (c *spaceClient) GetInstance(ctx context.Context, instanceOpts map[string]s
 		return nil, errors.Wrap(err, "error parsing service instance generation")
 	}
 	parameterHash := *serviceInstance.Metadata.Annotations[annotationParameterHash]
+	// instances created by older versions of the operator do not carry the tags hash; then the actual tags are hashed
+	tagsHash := facade.TagsHash(serviceInstance.Tags)
+	if value := serviceInstance.Metadata.Annotations[annotationTagsHash]; value != nil {
+		tagsHash = *value
+	}
 	var state facade.InstanceState
 	switch serviceInstance.LastOperation.Type + ":" + serviceInstance.LastOperation.State {
 	case "create:in progress":
@@ -109,27 +242,41 @@ func (c *spaceClient) GetInstance(ctx context.Context, instanceOpts map[string]s
 		state = facade.InstanceStateUnknown
 	}
 	stateDescription := serviceInstance.LastOperation.Description
+	ownerNamespace, ownerName := ownerObjectOf(serviceInstance.Metadata)
 
-	return &facade.Instance{
+	result := &facade.Instance{
 		Guid:             guid,
 		Name:             name,
 		ServicePlanGuid:  servicePlanGuid,
-		Owner:            instanceOpts["owner"],
+		Owner:            owner,
+		OwnerNamespace:   ownerNamespace,
+		OwnerName:        ownerName,
+		OwnerCluster:     ownerClusterOf(serviceInstance.Metadata),
 		Generation:       generation,
 		ParameterHash:    parameterHash,
+		TagsHash:         tagsHash,
+		CustomMetadata:   hasCustomMetadata(serviceInstance.Metadata),
 		State:            state,
 		StateDescription: stateDescription,
-	}, nil
+		LastOperation:    lastOperationOf(serviceInstance.LastOperation),
+	}
+	if serviceInstance.MaintenanceInfo != nil {
+		result.MaintenanceInfo = &facade.MaintenanceInfo{
+			Version:     serviceInstance.MaintenanceInfo.Version,
+			Description: serviceInstance.MaintenanceInfo.Description,
+		}
+	}
+	return result, nil
 }
 
 // Required parameters (may not be initial): name, servicePlanGuid, owner, generation
-// Optional parameters (may be initial): parameters, tags
-func (c *spaceClient) CreateInstance(ctx context.Context, name string, servicePlanGuid string, parameters map[string]interface{}, tags []string, owner string, generation int64) error {
+// Optional parameters (may be initial): parameters, tags, metadata
+func (c *spaceClient) CreateInstance(ctx context.Context, name string, servicePlanGuid string, parameters map[string]interface{}, tags []string, metadata *facade.Metadata, owner facade.OwnerRef, generation int64) (*facade.Instance, error) {
 	req := cfresource.NewServiceInstanceCreateManaged(name, c.spaceGuid, servicePlanGuid)
 	if parameters != nil {
 		jsonParameters, err := json.Marshal(parameters)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		// TODO: why is there no ServiceInstanceCreate.WithJSONParamters() method (ServiceCredentialBindingCreate has such a method) ?
 		// or ServiceInstance.WithParameters() method (ServiceInstanceManagedUpdate has such a method)
@@ -139,17 +286,41 @@ func (c *spaceClient) CreateInstance(ctx context.Context, name string, servicePl
 		req.Tags = tags
 	}
 	req.Metadata = cfresource.NewMetadata().
-		WithLabel(labelPrefix, labelKeyOwner, owner).
+		WithLabel(labelPrefix, labelKeyOwner, owner.UID).
 		WithAnnotation(annotationPrefix, annotationKeyGeneration, strconv.FormatInt(generation, 10)).
-		WithAnnotation(annotationPrefix, annotationKeyParameterHash, facade.ObjectHash(parameters))
+		WithAnnotation(annotationPrefix, annotationKeyParameterHash, facade.ObjectHash(parameters)).
+		WithAnnotation(annotationPrefix, annotationKeyTagsHash, facade.TagsHash(tags))
+	applyOwnerLabels(req.Metadata, owner, c.clusterID)
+	if metadata != nil {
+		applyMetadata(req.Metadata, metadata, nil)
+	}
 
-	_, err := c.client.ServiceInstances.CreateManaged(ctx, req)
-	return err
+	if _, err := c.client.ServiceInstances.CreateManaged(ctx, req); err != nil {
+		return nil, err
+	}
+	// managed instances are created asynchronously (only a job is returned), so there is nothing to be cached yet;
+	// drop outdated entries of the owner, such that the next lookup reads the new instance
+	c.resourceCache.deleteInstance("", owner)
+	publishEvent(events.EventTypeCreated, events.ResourceTypeInstance, "", owner.UID, c.spaceGuid)
+	// the returned instance is derived from the request; its guid is not known until the instance is read again
+	return &facade.Instance{
+		Name:            name,
+		ServicePlanGuid: servicePlanGuid,
+		Owner:           owner.UID,
+		OwnerNamespace:  owner.Namespace,
+		OwnerName:       owner.Name,
+		Generation:      generation,
+		ParameterHash:   facade.ObjectHash(parameters),
+		TagsHash:        facade.TagsHash(tags),
+		State:           facade.InstanceStateCreating,
+		LastOperation:   &facade.LastOperation{Type: "create", State: "in progress"},
+	}, nil
 }
 
 // Required parameters (may not be initial): guid, generation
-// Optional parameters (may be initial): name, servicePlanGuid, parameters, tags
-func (c *spaceClient) UpdateInstance(ctx context.Context, guid string, name string, servicePlanGuid string, parameters map[string]interface{}, tags []string, generation int64) error {
+// Optional parameters (may be initial): owner, name, servicePlanGuid, parameters, tags, metadata
+// If metadata is not nil, the custom labels and annotations of the instance are set to exactly the given ones.
+func (c *spaceClient) UpdateInstance(ctx context.Context, guid string, owner facade.OwnerRef, name string, servicePlanGuid string, parameters map[string]interface{}, tags []string, metadata *facade.Metadata, generation int64) error {
 	req := cfresource.NewServiceInstanceManagedUpdate()
 	if name != "" {
 		req.WithName(name)
@@ -176,13 +347,48 @@ func (c *spaceClient) UpdateInstance(ctx context.Context, guid string, name stri
 			req.Metadata.WithLabel(labelPrefix, labelKeyOwner, parameters["owner"].(string))
 		}
 	}
+	if tags != nil {
+		req.Metadata.WithAnnotation(annotationPrefix, annotationKeyTagsHash, facade.TagsHash(tags))
+	}
+	applyOwnerLabels(req.Metadata, owner, c.clusterID)
+	if metadata != nil {
+		// the current metadata tells which custom labels and annotations have to be removed
+		serviceInstance, err := c.client.ServiceInstances.Get(ctx, guid)
+		if err != nil {
+			return err
+		}
+		applyMetadata(req.Metadata, metadata, serviceInstance.Metadata)
+	}
 
-	_, _, err := c.client.ServiceInstances.UpdateManaged(ctx, guid, req)
-	return err
+	c.resourceCache.deleteInstance(guid, owner)
+	if _, _, err := c.client.ServiceInstances.UpdateManaged(ctx, guid, req); err != nil {
+		return err
+	}
+	publishEvent(events.EventTypeUpdated, events.ResourceTypeInstance, guid, owner.UID, c.spaceGuid)
+	return nil
 }
 
-func (c *spaceClient) DeleteInstance(ctx context.Context, guid string) error {
+// UpgradeInstance applies the given maintenance upgrade (as offered by the service broker) to the instance.
+func (c *spaceClient) UpgradeInstance(ctx context.Context, guid string, owner facade.OwnerRef, maintenanceInfo facade.MaintenanceInfo) error {
+	req := cfresource.NewServiceInstanceManagedUpdate().
+		WithMaintenanceInfo(maintenanceInfo.Version, maintenanceInfo.Description)
+
+	c.resourceCache.deleteInstance(guid, owner)
+	if _, _, err := c.client.ServiceInstances.UpdateManaged(ctx, guid, req); err != nil {
+		return err
+	}
+	publishEvent(events.EventTypeUpdated, events.ResourceTypeInstance, guid, owner.UID, c.spaceGuid)
+	return nil
+}
+
+// Required parameters (may not be initial): guid
+// Optional parameters (may be initial): owner
+func (c *spaceClient) DeleteInstance(ctx context.Context, guid string, owner facade.OwnerRef) error {
+	c.resourceCache.deleteInstance(guid, owner)
 	// TODO: return jobGUID to enable querying the job deletion status
-	_, err := c.client.ServiceInstances.Delete(ctx, guid)
-	return err
+	if _, err := c.client.ServiceInstances.Delete(ctx, guid); err != nil {
+		return err
+	}
+	publishEvent(events.EventTypeDeleted, events.ResourceTypeInstance, guid, owner.UID, c.spaceGuid)
+	return nil
 }
@@ -0,0 +1,257 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cf
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+// locks serializing the mutating calls of all clients against the same Cloud Foundry resource
+var resourceLocks = newKeyedMutex()
+
+// keyedMutex is a set of mutexes identified by keys; mutexes are created on first use, and dropped once they are no longer used.
+type keyedMutex struct {
+	mutex sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	// channel with capacity 1; the lock is held by whoever sent the (single) value
+	held chan struct{}
+	// number of callers holding or waiting for the lock
+	refs int
+}
+
+func newKeyedMutex() *keyedMutex {
+	return &keyedMutex{locks: make(map[string]*keyedLock)}
+}
+
+// lock acquires the mutex identified by the given key, and returns a function releasing it;
+// fails if the given context is done before the mutex could be acquired.
+func (m *keyedMutex) lock(ctx context.Context, key string) (func(), error) {
+	m.mutex.Lock()
+	l, ok := m.locks[key]
+	if !ok {
+		l = &keyedLock{held: make(chan struct{}, 1)}
+		m.locks[key] = l
+	}
+	l.refs++
+	m.mutex.Unlock()
+
+	select {
+	case l.held <- struct{}{}:
+		return func() {
+			<-l.held
+			m.release(key, l)
+		}, nil
+	case <-ctx.Done():
+		m.release(key, l)
+		return nil, errors.Wrapf(ctx.Err(), "failed to acquire lock on %s", key)
+	}
+}
+
+func (m *keyedMutex) release(key string, l *keyedLock) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	l.refs--
+	if l.refs == 0 {
+		delete(m.locks, key)
+	}
+}
+
+// resourceKey returns the key identifying the Cloud Foundry resource of the given kind, with the given guid.
+func resourceKey(kind string, guid string) string {
+	return kind + "/" + guid
+}
+
+// creationKey returns the key identifying the Cloud Foundry resource of the given kind which is about to be created, by its name,
+// which is unique within the given scope (for example, the space of an instance); that way, concurrent creations of the same
+// resource are serialized, even if requested by different objects.
+func creationKey(kind string, scope string, name string) string {
+	return kind + "/" + scope + "/name/" + name
+}
+
+// lockingOrganizationClient serializes the mutating space calls of the wrapped organization client per space (across all clients);
+// all other calls are passed through. As with lockingSpaceClient, only single calls are serialized.
+type lockingOrganizationClient struct {
+	facade.OrganizationClient
+	organizationName string
+}
+
+func (c *lockingOrganizationClient) CreateSpace(ctx context.Context, name string, owner facade.OwnerRef, generation int64) (*facade.Space, error) {
+	unlock, err := resourceLocks.lock(ctx, creationKey("space", c.organizationName, name))
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return c.OrganizationClient.CreateSpace(ctx, name, owner, generation)
+}
+
+func (c *lockingOrganizationClient) UpdateSpace(ctx context.Context, guid string, owner facade.OwnerRef, name string, generation int64) error {
+	unlock, err := resourceLocks.lock(ctx, resourceKey("space", guid))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return c.OrganizationClient.UpdateSpace(ctx, guid, owner, name, generation)
+}
+
+func (c *lockingOrganizationClient) DeleteSpace(ctx context.Context, guid string, owner facade.OwnerRef) error {
+	unlock, err := resourceLocks.lock(ctx, resourceKey("space", guid))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return c.OrganizationClient.DeleteSpace(ctx, guid, owner)
+}
+
+// lockingSpaceClient serializes the mutating calls of the wrapped space client per instance, binding, route and route binding
+// (across all clients); all other calls are passed through. Resources are identified by guid, and by name while being created.
+// Note that only single calls are serialized: the lock is released as soon as Cloud Foundry accepted the request (asynchronous
+// operations continue afterwards), and the reads preceding a call (by which reconcilers decide what to do) are not covered.
+// That is, concurrent reconciles acting on the same Cloud Foundry resource (for example, objects adopting the same instance)
+// do not send overlapping requests, but may still act on outdated reads; Cloud Foundry rejects conflicting operations
+// (such as an operation on an instance with another operation in progress), which are then retried with the next reconcile.
+type lockingSpaceClient struct {
+	facade.SpaceClient
+	spaceGuid string
+}
+
+func (c *lockingSpaceClient) CreateInstance(ctx context.Context, name string, servicePlanGuid string, parameters map[string]interface{}, tags []string, metadata *facade.Metadata, owner facade.OwnerRef, generation int64) (*facade.Instance, error) {
+	unlock, err := resourceLocks.lock(ctx, creationKey("instance", c.spaceGuid, name))
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return c.SpaceClient.CreateInstance(ctx, name, servicePlanGuid, parameters, tags, metadata, owner, generation)
+}
+
+func (c *lockingSpaceClient) UpdateInstance(ctx context.Context, guid string, owner facade.OwnerRef, name string, servicePlanGuid string, parameters map[string]interface{}, tags []string, metadata *facade.Metadata, generation int64) error {
+	unlock, err := resourceLocks.lock(ctx, resourceKey("instance", guid))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return c.SpaceClient.UpdateInstance(ctx, guid, owner, name, servicePlanGuid, parameters, tags, metadata, generation)
+}
+
+func (c *lockingSpaceClient) UpgradeInstance(ctx context.Context, guid string, owner facade.OwnerRef, maintenanceInfo facade.MaintenanceInfo) error {
+	unlock, err := resourceLocks.lock(ctx, resourceKey("instance", guid))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return c.SpaceClient.UpgradeInstance(ctx, guid, owner, maintenanceInfo)
+}
+
+func (c *lockingSpaceClient) DeleteInstance(ctx context.Context, guid string, owner facade.OwnerRef) error {
+	unlock, err := resourceLocks.lock(ctx, resourceKey("instance", guid))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return c.SpaceClient.DeleteInstance(ctx, guid, owner)
+}
+
+func (c *lockingSpaceClient) CreateBinding(ctx context.Context, name string, serviceInstanceGuid string, appGuid string, parameters map[string]interface{}, metadata *facade.Metadata, owner facade.OwnerRef, generation int64) (*facade.Binding, error) {
+	// binding names are unique per instance; app bindings (which may be unnamed) are unique per instance and app
+	bindingName := name
+	if appGuid != "" {
+		bindingName = "app/" + appGuid
+	}
+	unlock, err := resourceLocks.lock(ctx, creationKey("binding", serviceInstanceGuid, bindingName))
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return c.SpaceClient.CreateBinding(ctx, name, serviceInstanceGuid, appGuid, parameters, metadata, owner, generation)
+}
+
+func (c *lockingSpaceClient) UpdateBinding(ctx context.Context, guid string, owner facade.OwnerRef, generation int64, parameters map[string]interface{}, metadata *facade.Metadata) error {
+	unlock, err := resourceLocks.lock(ctx, resourceKey("binding", guid))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return c.SpaceClient.UpdateBinding(ctx, guid, owner, generation, parameters, metadata)
+}
+
+func (c *lockingSpaceClient) DeleteBinding(ctx context.Context, guid string, owner facade.OwnerRef) error {
+	unlock, err := resourceLocks.lock(ctx, resourceKey("binding", guid))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return c.SpaceClient.DeleteBinding(ctx, guid, owner)
+}
+
+func (c *lockingSpaceClient) PromoteBinding(ctx context.Context, guid string, owner facade.OwnerRef) error {
+	unlock, err := resourceLocks.lock(ctx, resourceKey("binding", guid))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return c.SpaceClient.PromoteBinding(ctx, guid, owner)
+}
+
+func (c *lockingSpaceClient) CreateRoute(ctx context.Context, domainGuid string, host string, path string, owner string, generation int64) error {
+	unlock, err := resourceLocks.lock(ctx, creationKey("route", domainGuid, host+path))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return c.SpaceClient.CreateRoute(ctx, domainGuid, host, path, owner, generation)
+}
+
+func (c *lockingSpaceClient) UpdateRoute(ctx context.Context, guid string, generation int64) error {
+	unlock, err := resourceLocks.lock(ctx, resourceKey("route", guid))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return c.SpaceClient.UpdateRoute(ctx, guid, generation)
+}
+
+func (c *lockingSpaceClient) DeleteRoute(ctx context.Context, guid string) error {
+	unlock, err := resourceLocks.lock(ctx, resourceKey("route", guid))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return c.SpaceClient.DeleteRoute(ctx, guid)
+}
+
+func (c *lockingSpaceClient) CreateRouteBinding(ctx context.Context, routeGuid string, serviceInstanceGuid string, parameters map[string]interface{}, owner string, generation int64) error {
+	unlock, err := resourceLocks.lock(ctx, creationKey("routeBinding", routeGuid, serviceInstanceGuid))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return c.SpaceClient.CreateRouteBinding(ctx, routeGuid, serviceInstanceGuid, parameters, owner, generation)
+}
+
+func (c *lockingSpaceClient) UpdateRouteBinding(ctx context.Context, guid string, generation int64) error {
+	unlock, err := resourceLocks.lock(ctx, resourceKey("routeBinding", guid))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return c.SpaceClient.UpdateRouteBinding(ctx, guid, generation)
+}
+
+func (c *lockingSpaceClient) DeleteRouteBinding(ctx context.Context, guid string) error {
+	unlock, err := resourceLocks.lock(ctx, resourceKey("routeBinding", guid))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return c.SpaceClient.DeleteRouteBinding(ctx, guid)
+}
@@ -0,0 +1,120 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package cf
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sap/cf-service-operator/internal/facade"
+	"github.com/sap/cf-service-operator/internal/facade/facadefakes"
+)
+
+var _ = Describe("Lock tests", func() {
+	It("should serialize holders of the same key, and drop unused locks", func() {
+		m := newKeyedMutex()
+		unlock, err := m.lock(context.Background(), "instance/guid")
+		Expect(err).NotTo(HaveOccurred())
+
+		// other keys are not blocked
+		unlockOther, err := m.lock(context.Background(), "instance/other-guid")
+		Expect(err).NotTo(HaveOccurred())
+		unlockOther()
+
+		acquired := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			unlock, err := m.lock(context.Background(), "instance/guid")
+			Expect(err).NotTo(HaveOccurred())
+			close(acquired)
+			unlock()
+		}()
+		Consistently(acquired, 100*time.Millisecond).ShouldNot(BeClosed())
+		unlock()
+		Eventually(acquired).Should(BeClosed())
+		Eventually(func() int {
+			m.mutex.Lock()
+			defer m.mutex.Unlock()
+			return len(m.locks)
+		}).Should(BeZero())
+	})
+
+	It("should give up waiting once the context is done", func() {
+		m := newKeyedMutex()
+		unlock, err := m.lock(context.Background(), "binding/guid")
+		Expect(err).NotTo(HaveOccurred())
+		defer unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = m.lock(ctx, "binding/guid")
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(err).To(MatchError(ContainSubstring("failed to acquire lock on binding/guid")))
+	})
+
+	It("should serialize mutating calls of different clients against the same instance", func() {
+		var running, maxRunning atomic.Int32
+		fakeClient := &facadefakes.FakeSpaceClient{}
+		fakeClient.UpdateInstanceStub = func(context.Context, string, facade.OwnerRef, string, string, map[string]interface{}, []string, *facade.Metadata, int64) error {
+			runConcurrently(&running, &maxRunning)
+			return nil
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func(uid string) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				client := &lockingSpaceClient{SpaceClient: fakeClient}
+				Expect(client.UpdateInstance(context.Background(), "instance-guid", facade.OwnerRef{UID: uid}, "instance", "", nil, nil, nil, 1)).To(Succeed())
+			}(string(rune('a' + i)))
+		}
+		wg.Wait()
+		Expect(fakeClient.UpdateInstanceCallCount()).To(Equal(5))
+		Expect(maxRunning.Load()).To(Equal(int32(1)))
+	})
+
+	It("should serialize creations of the same instance by different objects", func() {
+		var running, maxRunning atomic.Int32
+		fakeClient := &facadefakes.FakeSpaceClient{}
+		fakeClient.CreateInstanceStub = func(context.Context, string, string, map[string]interface{}, []string, *facade.Metadata, facade.OwnerRef, int64) (*facade.Instance, error) {
+			runConcurrently(&running, &maxRunning)
+			return &facade.Instance{}, nil
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func(uid string) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				client := &lockingSpaceClient{SpaceClient: fakeClient, spaceGuid: "space-guid"}
+				Expect(client.CreateInstance(context.Background(), "instance", "plan-guid", nil, nil, nil, facade.OwnerRef{UID: uid}, 1)).Error().NotTo(HaveOccurred())
+			}(string(rune('a' + i)))
+		}
+		wg.Wait()
+		Expect(fakeClient.CreateInstanceCallCount()).To(Equal(5))
+		Expect(maxRunning.Load()).To(Equal(int32(1)))
+	})
+})
+
+// runConcurrently simulates a call taking some time, recording the maximum number of such calls running at the same time
+func runConcurrently(running *atomic.Int32, maxRunning *atomic.Int32) {
+	n := running.Add(1)
+	for {
+		m := maxRunning.Load()
+		if n <= m || maxRunning.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	running.Add(-1)
+}
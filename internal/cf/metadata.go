@@ -0,0 +1,129 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cf
+
+import (
+	"encoding/json"
+	"sort"
+
+	cfresource "github.com/cloudfoundry-community/go-cfclient/v3/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+// annotation recording the keys of the custom labels and annotations applied by the operator,
+// such that they can be removed once they are no longer specified
+const (
+	annotationKeyMetadataKeys = "metadata-keys"
+	annotationMetadataKeys    = annotationPrefix + "/" + annotationKeyMetadataKeys
+)
+
+type metadataKeys struct {
+	Labels      []string `json:"labels,omitempty"`
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// applyMetadata sets the given custom labels and annotations in the given request metadata; custom labels and annotations
+// previously applied by the operator (as recorded in the given current metadata of the resource, which may be nil)
+// are removed if they are no longer specified.
+func applyMetadata(req *cfresource.Metadata, metadata *facade.Metadata, current *cfresource.Metadata) {
+	previousKeys := metadataKeys{}
+	if current != nil {
+		if value := current.Annotations[annotationMetadataKeys]; value != nil {
+			// unparsable values are ignored; at worst, stale labels or annotations remain
+			_ = json.Unmarshal([]byte(*value), &previousKeys)
+		}
+	}
+
+	keys := metadataKeys{}
+	for key, value := range metadata.Labels {
+		req.SetLabel("", key, value)
+		keys.Labels = append(keys.Labels, key)
+	}
+	for key, value := range metadata.Annotations {
+		req.SetAnnotation("", key, value)
+		keys.Annotations = append(keys.Annotations, key)
+	}
+	for _, key := range previousKeys.Labels {
+		if _, ok := metadata.Labels[key]; !ok {
+			req.RemoveLabel("", key)
+		}
+	}
+	for _, key := range previousKeys.Annotations {
+		if _, ok := metadata.Annotations[key]; !ok {
+			req.RemoveAnnotation("", key)
+		}
+	}
+	for _, key := range metadata.RemovedLabels {
+		if _, ok := metadata.Labels[key]; !ok {
+			req.RemoveLabel("", key)
+		}
+	}
+
+	if len(keys.Labels) == 0 && len(keys.Annotations) == 0 {
+		if current != nil && current.Annotations[annotationMetadataKeys] != nil {
+			req.RemoveAnnotation(annotationPrefix, annotationKeyMetadataKeys)
+		}
+		return
+	}
+	sort.Strings(keys.Labels)
+	sort.Strings(keys.Annotations)
+	value, _ := json.Marshal(keys)
+	req.SetAnnotation(annotationPrefix, annotationKeyMetadataKeys, string(value))
+}
+
+// hasCustomMetadata checks whether custom labels or annotations were applied by the operator, as recorded in the given metadata.
+func hasCustomMetadata(metadata *cfresource.Metadata) bool {
+	return metadata != nil && metadata.Annotations[annotationMetadataKeys] != nil
+}
+
+// applyOwnerLabels records the given cluster identifier (if not empty) in the owner-cluster label, and the namespace and name of
+// the owning object (if given) in the owner-namespace and owner-name labels; names which are no valid label values
+// (such as names longer than 63 characters) are not recorded.
+func applyOwnerLabels(req *cfresource.Metadata, owner facade.OwnerRef, clusterID string) {
+	if clusterID != "" {
+		req.SetLabel(labelPrefix, labelKeyOwnerCluster, clusterID)
+	}
+	if owner.Namespace == "" || owner.Name == "" {
+		return
+	}
+	req.SetLabel(labelPrefix, labelKeyOwnerNamespace, owner.Namespace)
+	if errs := validation.IsValidLabelValue(owner.Name); len(errs) == 0 {
+		req.SetLabel(labelPrefix, labelKeyOwnerName, owner.Name)
+	}
+}
+
+// ownerObjectOf returns the namespace and name of the owning object, as recorded in the given metadata (empty if not recorded).
+func ownerObjectOf(metadata *cfresource.Metadata) (string, string) {
+	if metadata == nil {
+		return "", ""
+	}
+	namespace, name := "", ""
+	if value := metadata.Labels[labelOwnerNamespace]; value != nil {
+		namespace = *value
+	}
+	if value := metadata.Labels[labelOwnerName]; value != nil {
+		name = *value
+	}
+	return namespace, name
+}
+
+// ownerClusterOf returns the identifier of the owning cluster, as recorded in the given metadata (empty if not recorded).
+func ownerClusterOf(metadata *cfresource.Metadata) string {
+	if metadata == nil || metadata.Labels[labelOwnerCluster] == nil {
+		return ""
+	}
+	return *metadata.Labels[labelOwnerCluster]
+}
+
+// annotationOf returns the value of the given annotation, as recorded in the given metadata (empty if not recorded).
+func annotationOf(metadata *cfresource.Metadata, key string) string {
+	if metadata == nil || metadata.Annotations[key] == nil {
+		return ""
+	}
+	return *metadata.Annotations[key]
+}
@@ -0,0 +1,109 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cf
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	resourceCacheMetricsSubsystem = "cf_resource_cache"
+	clientCacheMetricsSubsystem   = "cf_client_cache"
+	clientMetricsSubsystem        = "cf_client"
+
+	resourceTypeSpace    = "space"
+	resourceTypeInstance = "instance"
+	resourceTypeBinding  = "binding"
+
+	resourceTypeServiceOffering = "service_offering"
+	resourceTypeServicePlan     = "service_plan"
+)
+
+var (
+	resourceCacheLookups = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: resourceCacheMetricsSubsystem,
+			Name:      "lookups_total",
+			Help:      "The number of resource cache lookups, by resource type and result (hit or miss)",
+		},
+		[]string{"resource", "result"},
+	)
+	resourceCacheRefreshDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: resourceCacheMetricsSubsystem,
+			Name:      "refresh_duration_seconds",
+			Help:      "A histogram of the durations of reading resources from Cloud Foundry after a cache miss",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"resource"},
+	)
+	resourceCacheExpirations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: resourceCacheMetricsSubsystem,
+			Name:      "expirations_total",
+			Help:      "The number of resource cache entries removed because they exceeded the cache timeout",
+		},
+		[]string{"resource"},
+	)
+	clientCacheEvictions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: clientCacheMetricsSubsystem,
+			Name:      "evictions_total",
+			Help:      "The number of cached CF clients evicted, by reason (idle, capacity or authentication)",
+		},
+		[]string{"reason"},
+	)
+	clientReauthentications = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: clientMetricsSubsystem,
+			Name:      "reauthentications_total",
+			Help:      "The number of logins performed because the access token of a CF client could not be refreshed, by API URL and result (success or failure)",
+		},
+		[]string{"url", "result"},
+	)
+	listDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: clientMetricsSubsystem,
+			Name:      "list_duration_seconds",
+			Help:      "A histogram of the durations of list operations (reading all pages of a list request), by resource type",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"resource"},
+	)
+	resourceCacheEntriesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("", resourceCacheMetricsSubsystem, "entries"),
+		"The number of resources currently cached, by resource type",
+		[]string{"resource"},
+		nil,
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		resourceCacheLookups,
+		resourceCacheRefreshDuration,
+		resourceCacheExpirations,
+		clientCacheEvictions,
+		clientReauthentications,
+		listDuration,
+		&resourceCacheCollector{},
+	)
+}
+
+// resourceCacheCollector reports the current size of all resource caches (summed up over all cached CF clients).
+type resourceCacheCollector struct{}
+
+func (rcc *resourceCacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- resourceCacheEntriesDesc
+}
+
+func (rcc *resourceCacheCollector) Collect(ch chan<- prometheus.Metric) {
+	statistics := GetResourceCacheStatistics()
+	ch <- prometheus.MustNewConstMetric(resourceCacheEntriesDesc, prometheus.GaugeValue, float64(statistics.Spaces), resourceTypeSpace)
+	ch <- prometheus.MustNewConstMetric(resourceCacheEntriesDesc, prometheus.GaugeValue, float64(statistics.Instances), resourceTypeInstance)
+	ch <- prometheus.MustNewConstMetric(resourceCacheEntriesDesc, prometheus.GaugeValue, float64(statistics.Bindings), resourceTypeBinding)
+}
@@ -7,40 +7,102 @@ package cf
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
-	cfclient "github.com/cloudfoundry-community/go-cfclient/v3/client"
+	cfresource "github.com/cloudfoundry-community/go-cfclient/v3/resource"
+
+	"github.com/sap/cf-service-operator/internal/facade"
 )
 
+// FindServicePlan resolves the given service offering and plan names in the catalog of the given space;
+// the catalog is served from the catalog cache (if enabled), and re-read if the offering or plan is not found in an older cached catalog.
+// If the catalog cache is disabled, only the given offering and plan are read.
 func (c *spaceClient) FindServicePlan(ctx context.Context, serviceOfferingName string, servicePlanName string, spaceGuid string) (string, error) {
-	serviceOfferingListOpts := cfclient.NewServiceOfferingListOptions()
-	serviceOfferingListOpts.Names.EqualTo(serviceOfferingName)
-	serviceOfferingListOpts.SpaceGUIDs.EqualTo(spaceGuid)
-	serviceOfferings, err := c.client.ServiceOfferings.ListAll(ctx, serviceOfferingListOpts)
+	if c.catalogCache == nil {
+		catalog, err := c.loadCatalog(ctx, spaceGuid, serviceOfferingName, servicePlanName)
+		if err != nil {
+			return "", err
+		}
+		return catalog.findServicePlan(serviceOfferingName, servicePlanName)
+	}
+
+	catalog, err := c.getCatalog(ctx, spaceGuid)
 	if err != nil {
 		return "", err
 	}
-	if len(serviceOfferings) == 0 {
-		return "", fmt.Errorf("found no service offering with name: %s", serviceOfferingName)
-	} else if len(serviceOfferings) > 1 {
-		return "", fmt.Errorf("found multiple service offerings with name: %s", serviceOfferingName)
+	guid, err := catalog.findServicePlan(serviceOfferingName, servicePlanName)
+	var notFoundErr *facade.ServicePlanNotFoundError
+	if errors.As(err, &notFoundErr) && c.catalogCache != nil && time.Since(catalog.loadedAt) >= catalogRefreshInterval {
+		// the offering or plan may have been added since the catalog was cached
+		c.catalogCache.invalidate(spaceGuid)
+		if catalog, err = c.getCatalog(ctx, spaceGuid); err != nil {
+			return "", err
+		}
+		guid, err = catalog.findServicePlan(serviceOfferingName, servicePlanName)
 	}
-	serviceOffering := serviceOfferings[0]
+	return guid, err
+}
 
-	servicePlanListOpts := cfclient.NewServicePlanListOptions()
-	servicePlanListOpts.Names.EqualTo(servicePlanName)
-	servicePlanListOpts.SpaceGUIDs.EqualTo(spaceGuid)
-	servicePlanListOpts.ServiceOfferingGUIDs.EqualTo(serviceOffering.GUID)
-	servicePlans, err := c.client.ServicePlans.ListAll(ctx, servicePlanListOpts)
+// ListServicePlans returns all service plans visible in the given space (served from the catalog cache, if enabled).
+func (c *spaceClient) ListServicePlans(ctx context.Context, spaceGuid string) ([]facade.ServicePlan, error) {
+	catalog, err := c.getCatalog(ctx, spaceGuid)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	if len(servicePlans) == 0 {
-		return "", fmt.Errorf("found no service plan with name: %s (service offering: %s)", servicePlanName, serviceOfferingName)
-	} else if len(servicePlans) > 1 {
-		return "", fmt.Errorf("found multiple service plans with name: %s (service offering: %s)", servicePlanName, serviceOfferingName)
+	return append([]facade.ServicePlan(nil), catalog.plans...), nil
+}
+
+// GetServicePlanSchemas returns the parameter schemas of the given service plan (served from the catalog cache, if enabled),
+// or nil if the plan is not visible in the given space. If the catalog cache is disabled, only the given plan is read (and nil is returned
+// if it does not exist).
+func (c *spaceClient) GetServicePlanSchemas(ctx context.Context, servicePlanGuid string, spaceGuid string) (*facade.ServicePlanSchemas, error) {
+	if c.catalogCache == nil {
+		servicePlan, err := c.client.ServicePlans.Get(ctx, servicePlanGuid)
+		if err != nil {
+			if cfresource.IsResourceNotFoundError(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return servicePlanSchemas(servicePlan), nil
+	}
+
+	catalog, err := c.getCatalog(ctx, spaceGuid)
+	if err != nil {
+		return nil, err
+	}
+	schemas, ok := catalog.schemas[servicePlanGuid]
+	if !ok && c.catalogCache != nil && time.Since(catalog.loadedAt) >= catalogRefreshInterval {
+		// the plan may have been added since the catalog was cached
+		c.catalogCache.invalidate(spaceGuid)
+		if catalog, err = c.getCatalog(ctx, spaceGuid); err != nil {
+			return nil, err
+		}
+		schemas = catalog.schemas[servicePlanGuid]
+	}
+	return schemas, nil
+}
+
+func (catalog *serviceCatalog) findServicePlan(serviceOfferingName string, servicePlanName string) (string, error) {
+	serviceOfferingGuids := catalog.offerings[serviceOfferingName]
+	if len(serviceOfferingGuids) == 0 {
+		return "", &facade.ServicePlanNotFoundError{ServiceOfferingName: serviceOfferingName}
+	} else if len(serviceOfferingGuids) > 1 {
+		return "", fmt.Errorf("found multiple service offerings with name: %s", serviceOfferingName)
 	}
-	servicePlan := servicePlans[0]
 
-	return servicePlan.GUID, nil
+	var guids []string
+	for _, servicePlan := range catalog.plans {
+		if servicePlan.ServiceOfferingGuid == serviceOfferingGuids[0] && servicePlan.Name == servicePlanName {
+			guids = append(guids, servicePlan.Guid)
+		}
+	}
+	if len(guids) == 0 {
+		return "", &facade.ServicePlanNotFoundError{ServiceOfferingName: serviceOfferingName, ServicePlanName: servicePlanName}
+	} else if len(guids) > 1 {
+		return "", fmt.Errorf("found multiple service plans with name: %s (service offering: %s)", servicePlanName, serviceOfferingName)
+	}
+	return guids[0], nil
 }
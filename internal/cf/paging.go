@@ -0,0 +1,100 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cf
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient/v3/client"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/sap/cf-service-operator/internal/config"
+)
+
+// pagingOptions controls how list operations read multi-page results from Cloud Foundry.
+type pagingOptions struct {
+	// Number of results per page; zero means the default page size of the Cloud Foundry client
+	pageSize int
+	// Maximum number of pages read in parallel; values below two mean that pages are read one after another
+	parallelism int
+}
+
+func newPagingOptions(cfg *config.Config) pagingOptions {
+	if cfg == nil {
+		return pagingOptions{}
+	}
+	return pagingOptions{pageSize: cfg.ListPageSize, parallelism: cfg.ListParallelism}
+}
+
+// listPageFunc reads the given page (starting with 1) of a list request, with the given number of results per page.
+type listPageFunc[R any] func(ctx context.Context, page int, perPage int) ([]R, *cfclient.Pager, error)
+
+// listAll reads all results of a list request, and records the duration of the operation (by the given resource type).
+// The first page tells the total number of pages; the remaining pages are then read in parallel (as far as permitted by the
+// paging options), and the results are returned in page order.
+func listAll[R any](ctx context.Context, paging pagingOptions, resource string, listPage listPageFunc[R]) ([]R, error) {
+	defer observeListDuration(resource, time.Now())
+
+	perPage := paging.pageSize
+	if perPage <= 0 {
+		perPage = cfclient.DefaultPageSize
+	}
+	first, pager, err := listPage(ctx, 1, perPage)
+	if err != nil {
+		return nil, err
+	}
+	if pager == nil || pager.TotalPages <= 1 {
+		return first, nil
+	}
+
+	pages := make([][]R, pager.TotalPages)
+	pages[0] = first
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(max(paging.parallelism, 1))
+	for page := 2; page <= len(pages); page++ {
+		group.Go(func() error {
+			results, _, err := listPage(groupCtx, page, perPage)
+			pages[page-1] = results
+			return err
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	var result []R
+	for _, results := range pages {
+		result = append(result, results...)
+	}
+	return result, nil
+}
+
+// listAllPages reads all results of a list request (see listAll), by calling the given list function of the Cloud Foundry client
+// once per page. The list options must be one of the typed list options of the Cloud Foundry client (such as
+// cfclient.ServiceInstanceListOptions), that is, a struct embedding *cfclient.ListOptions; they are copied for every page, so that
+// pages may be read concurrently.
+func listAllPages[O any, R any](ctx context.Context, paging pagingOptions, resource string, listOpts *O, list func(context.Context, *O) ([]R, *cfclient.Pager, error)) ([]R, error) {
+	return listAll(ctx, paging, resource, func(ctx context.Context, page int, perPage int) ([]R, *cfclient.Pager, error) {
+		pageListOpts := *listOpts
+		field := reflect.ValueOf(&pageListOpts).Elem().FieldByName("ListOptions")
+		field.Set(reflect.ValueOf(pageOptions(field.Interface().(*cfclient.ListOptions), page, perPage)))
+		return list(ctx, &pageListOpts)
+	})
+}
+
+// pageOptions returns a copy of the given list options, requesting the given page; the copy may be used concurrently
+// with other pages of the same list request.
+func pageOptions(listOpts *cfclient.ListOptions, page int, perPage int) *cfclient.ListOptions {
+	pageListOpts := *listOpts
+	pageListOpts.CurrentPage(page, perPage)
+	return &pageListOpts
+}
+
+func observeListDuration(resource string, start time.Time) {
+	listDuration.WithLabelValues(resource).Observe(time.Since(start).Seconds())
+}
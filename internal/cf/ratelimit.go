@@ -0,0 +1,141 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cf
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/sap/cf-service-operator/internal/config"
+)
+
+const (
+	// upper bound for waiting on a Retry-After header
+	maxRetryAfter = 1 * time.Minute
+	// wait time if a 429 response does not contain a (valid) Retry-After header
+	defaultRetryAfter = 1 * time.Second
+)
+
+// rate limiters per CF API endpoint, shared by all clients of that endpoint;
+// guarded by cacheMutex
+var rateLimiters = make(map[string]*rate.Limiter)
+
+// maximum number of retries of requests answered with 429 Too Many Requests, shared by all clients;
+// set whenever a client is created, and by Reconfigure
+var maxRetriesOnTooManyRequests atomic.Int64
+
+func getMaxRetriesOnTooManyRequests() int {
+	return int(maxRetriesOnTooManyRequests.Load())
+}
+
+// getRateLimiter returns the (shared) rate limiter for the given CF API endpoint, or nil if there is no configuration.
+// If requests are not limited, the returned limiter allows all requests (but may be restricted later, see Reconfigure).
+// Must be called with cacheMutex locked.
+func getRateLimiter(url string, cfg *config.Config) *rate.Limiter {
+	if cfg == nil {
+		return nil
+	}
+	limiter, ok := rateLimiters[url]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Inf, 1)
+		rateLimiters[url] = limiter
+	}
+	// configuration may have changed since the limiter was created
+	setRateLimit(limiter, cfg.RateLimitFor(url))
+	return limiter
+}
+
+func setRateLimit(limiter *rate.Limiter, limit config.RateLimit) {
+	if limit.MaxRequestsPerSecond <= 0 {
+		limiter.SetLimit(rate.Inf)
+		return
+	}
+	burst := limit.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+	limiter.SetLimit(rate.Limit(limit.MaxRequestsPerSecond))
+	limiter.SetBurst(burst)
+}
+
+// rateLimitTransport throttles requests according to a token bucket rate limiter.
+type rateLimitTransport struct {
+	transport http.RoundTripper
+	limiter   *rate.Limiter
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.transport.RoundTrip(req)
+}
+
+// retryTransport retries requests answered with 429 Too Many Requests, honoring the Retry-After header.
+// Requests whose body cannot be re-read are not retried.
+type retryTransport struct {
+	transport http.RoundTripper
+	// returns the current maximum number of retries (which may change at runtime)
+	maxRetries func() int
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.transport.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt >= t.maxRetries() {
+			return resp, err
+		}
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		wait := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		// drain body to allow re-use of the connection
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// parseRetryAfter interprets the value of a Retry-After header (either delay seconds, or an HTTP date),
+// capped at maxRetryAfter; defaultRetryAfter is returned if the value is missing or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		wait = date.Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+	} else {
+		wait = defaultRetryAfter
+	}
+	if wait > maxRetryAfter {
+		wait = maxRetryAfter
+	}
+	return wait
+}
@@ -0,0 +1,78 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package cf
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"golang.org/x/time/rate"
+)
+
+var _ = Describe("Rate limit tests", func() {
+	var server *ghttp.Server
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should retry requests answered with 429", func() {
+		server.AppendHandlers(
+			ghttp.RespondWith(http.StatusTooManyRequests, nil, http.Header{"Retry-After": []string{"0"}}),
+			ghttp.CombineHandlers(
+				ghttp.VerifyBody([]byte("payload")),
+				ghttp.RespondWith(http.StatusOK, "ok"),
+			),
+		)
+		client := &http.Client{Transport: &retryTransport{transport: http.DefaultTransport, maxRetries: func() int { return 3 }}}
+
+		resp, err := client.Post(server.URL()+"/v3/spaces", "text/plain", strings.NewReader("payload"))
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(server.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("should give up after the maximum number of retries", func() {
+		server.RouteToHandler("GET", "/v3/spaces", ghttp.RespondWith(http.StatusTooManyRequests, nil, http.Header{"Retry-After": []string{"0"}}))
+		client := &http.Client{Transport: &retryTransport{transport: http.DefaultTransport, maxRetries: func() int { return 2 }}}
+
+		resp, err := client.Get(server.URL() + "/v3/spaces")
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusTooManyRequests))
+		Expect(server.ReceivedRequests()).To(HaveLen(3))
+	})
+
+	It("should throttle requests", func() {
+		server.RouteToHandler("GET", "/v3/spaces", ghttp.RespondWith(http.StatusOK, "ok"))
+		client := &http.Client{Transport: &rateLimitTransport{transport: http.DefaultTransport, limiter: rate.NewLimiter(rate.Limit(20), 1)}}
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			resp, err := client.Get(server.URL() + "/v3/spaces")
+			Expect(err).ToNot(HaveOccurred())
+			resp.Body.Close()
+		}
+		Expect(time.Since(start)).To(BeNumerically(">=", 90*time.Millisecond))
+	})
+
+	It("should parse Retry-After headers", func() {
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		Expect(parseRetryAfter("5", now)).To(Equal(5 * time.Second))
+		Expect(parseRetryAfter(now.Add(10*time.Second).Format(http.TimeFormat), now)).To(Equal(10 * time.Second))
+		Expect(parseRetryAfter("3600", now)).To(Equal(maxRetryAfter))
+		Expect(parseRetryAfter("", now)).To(Equal(defaultRetryAfter))
+		Expect(parseRetryAfter("invalid", now)).To(Equal(defaultRetryAfter))
+	})
+})
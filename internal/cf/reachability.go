@@ -0,0 +1,174 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cf
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient/v3/client"
+
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+const (
+	// observed reachability older than this is refreshed by probing the endpoint
+	reachabilityMaxAge = time.Minute
+	// timeout of a single probe
+	reachabilityProbeTimeout = 5 * time.Second
+)
+
+// reachability of the CF API endpoints (by url), as observed by the requests sent to them; guarded by reachabilityMutex
+var (
+	reachabilityMutex = &sync.Mutex{}
+	reachability      = make(map[string]endpointReachability)
+)
+
+type endpointReachability struct {
+	observedAt time.Time
+	err        error
+}
+
+func recordReachability(url string, err error) {
+	reachabilityMutex.Lock()
+	defer reachabilityMutex.Unlock()
+
+	reachability[url] = endpointReachability{observedAt: time.Now(), err: err}
+}
+
+// reachabilityTransport records the reachability of a CF API endpoint; connection errors and server (5xx) errors
+// mark the endpoint as unreachable, all other responses as reachable.
+type reachabilityTransport struct {
+	transport http.RoundTripper
+	url       string
+}
+
+func (t *reachabilityTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	switch {
+	case err != nil:
+		// cancelled requests (for example by reconcile timeouts) say nothing about the endpoint
+		if req.Context().Err() == nil {
+			recordReachability(t.url, err)
+		}
+	case resp.StatusCode >= http.StatusInternalServerError:
+		recordReachability(t.url, fmt.Errorf("server error: %s", resp.Status))
+	default:
+		recordReachability(t.url, nil)
+		facade.RecordResponse(req.Context())
+	}
+	return resp, err
+}
+
+// ProbeEndpoint checks whether the CF API endpoint with the given url is reachable, returning nil if so.
+// The reachability observed by recent requests to the endpoint is reused; otherwise the (unauthenticated) root
+// of the endpoint is requested, using the connection settings (CA bundle, proxy) of the given configuration.
+// Implements facade.EndpointProber.
+func ProbeEndpoint(ctx context.Context, url string, cfg *config.Config) error {
+	reachabilityMutex.Lock()
+	observed, ok := reachability[url]
+	reachabilityMutex.Unlock()
+	if ok && time.Since(observed.observedAt) < reachabilityMaxAge {
+		return observed.err
+	}
+
+	httpClient := &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}
+	if err := configureConnection(httpClient, cfg); err != nil {
+		return err
+	}
+	probeCtx, cancel := context.WithTimeout(ctx, reachabilityProbeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(probeCtx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := (&reachabilityTransport{transport: httpClient.Transport, url: url}).RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("server error: %s", resp.Status)
+	}
+	return nil
+}
+
+// EndpointReachability returns the reachability of all CF API endpoints for which clients exist (that is, the endpoints
+// used by the Space and ClusterSpace objects reconciled so far), as a map from url to error (nil if reachable).
+// If no request was sent to an endpoint recently, the endpoint is probed.
+func EndpointReachability(ctx context.Context) map[string]error {
+	clients := make(map[string]*cfclient.Client)
+	cacheMutex.Lock()
+	for _, cacheEntry := range clientCache {
+		client := cacheEntry.client
+		clients[cacheEntry.url] = &client
+	}
+	cacheMutex.Unlock()
+
+	result := make(map[string]error, len(clients))
+	var wg sync.WaitGroup
+	var resultMutex sync.Mutex
+	for url, client := range clients {
+		reachabilityMutex.Lock()
+		observed, ok := reachability[url]
+		reachabilityMutex.Unlock()
+		if ok && time.Since(observed.observedAt) < reachabilityMaxAge {
+			result[url] = observed.err
+			continue
+		}
+		wg.Add(1)
+		go func(url string, client *cfclient.Client) {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, reachabilityProbeTimeout)
+			defer cancel()
+			_, err := client.Root.Get(probeCtx)
+			recordReachability(url, err)
+			resultMutex.Lock()
+			result[url] = err
+			resultMutex.Unlock()
+		}(url, client)
+	}
+	wg.Wait()
+	return result
+}
+
+// CheckAnyEndpoint is a healthz.Checker which fails if all CF API endpoints in use are unreachable;
+// it succeeds if no endpoint is in use (yet).
+func CheckAnyEndpoint(req *http.Request) error {
+	result := EndpointReachability(req.Context())
+	for _, err := range result {
+		if err == nil {
+			return nil
+		}
+	}
+	if len(result) > 0 {
+		return fmt.Errorf("all Cloud Foundry API endpoints unreachable: %s", formatReachability(result))
+	}
+	return nil
+}
+
+func formatReachability(result map[string]error) string {
+	urls := make([]string, 0, len(result))
+	for url := range result {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+	entries := make([]string, 0, len(urls))
+	for _, url := range urls {
+		if err := result[url]; err != nil {
+			entries = append(entries, fmt.Sprintf("%s (unreachable: %s)", url, err))
+		} else {
+			entries = append(entries, fmt.Sprintf("%s (reachable)", url))
+		}
+	}
+	return strings.Join(entries, ", ")
+}
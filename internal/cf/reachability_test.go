@@ -0,0 +1,112 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package cf
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+var _ = Describe("Endpoint reachability tests", func() {
+	var servers [2]*ghttp.Server
+
+	BeforeEach(func() {
+		previousClientCache, previousReachability := clientCache, reachability
+		DeferCleanup(func() {
+			clientCache, reachability = previousClientCache, previousReachability
+		})
+		clientCache = make(map[clientIdentifier]*clientCacheEntry)
+		reachability = make(map[string]endpointReachability)
+		for i := range servers {
+			server := ghttp.NewServer()
+			server.RouteToHandler("GET", "/", ghttp.RespondWith(http.StatusOK, `{"links": {"self": {"href": "`+server.URL()+`"}}}`))
+			servers[i] = server
+			DeferCleanup(server.Close)
+		}
+	})
+
+	check := func(checker func(*http.Request) error) error {
+		return checker(httptest.NewRequest(http.MethodGet, "/healthz/cf", nil))
+	}
+
+	It("should succeed if no endpoint is in use", func() {
+		Expect(check(CheckAnyEndpoint)).To(Succeed())
+	})
+
+	It("should probe endpoints, and report unreachable ones", func() {
+		for _, server := range servers {
+			_, err := NewSpaceClient(SpaceName, server.URL(), Username, Password, nil)
+			Expect(err).ToNot(HaveOccurred())
+		}
+		unreachableURL := servers[1].URL()
+		servers[1].Close()
+		// forget the reachability observed while creating the clients
+		reachability = make(map[string]endpointReachability)
+
+		result := EndpointReachability(context.Background())
+		Expect(result).To(HaveLen(2))
+		Expect(result[servers[0].URL()]).ToNot(HaveOccurred())
+		Expect(result[unreachableURL]).To(HaveOccurred())
+		Expect(formatReachability(result)).To(And(
+			ContainSubstring(servers[0].URL()+" (reachable)"),
+			ContainSubstring(unreachableURL+" (unreachable: "),
+		))
+		Expect(check(CheckAnyEndpoint)).To(Succeed())
+
+		// recently observed reachability is not probed again
+		requests := len(servers[0].ReceivedRequests())
+		recordReachability(servers[0].URL(), http.ErrHandlerTimeout)
+		Expect(check(CheckAnyEndpoint)).To(MatchError(ContainSubstring("all Cloud Foundry API endpoints unreachable")))
+		Expect(servers[0].ReceivedRequests()).To(HaveLen(requests))
+	})
+
+	It("should record server errors as unreachable", func() {
+		servers[0].RouteToHandler("GET", "/v3/spaces", ghttp.RespondWith(http.StatusBadGateway, nil))
+		client := &http.Client{Transport: &reachabilityTransport{transport: http.DefaultTransport, url: servers[0].URL()}}
+
+		resp, err := client.Get(servers[0].URL() + "/v3/spaces")
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(reachability[servers[0].URL()].err).To(MatchError(ContainSubstring("502")))
+	})
+
+	It("should record responses (but not server errors) in the request context", func() {
+		servers[0].RouteToHandler("GET", "/v3/spaces", ghttp.RespondWith(http.StatusBadGateway, nil))
+		servers[0].RouteToHandler("GET", "/v3/apps", ghttp.RespondWith(http.StatusNotFound, nil))
+		client := &http.Client{Transport: &reachabilityTransport{transport: http.DefaultTransport, url: servers[0].URL()}}
+		ctx, responded := facade.WithResponseRecorder(context.Background())
+		get := func(path string) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, servers[0].URL()+path, nil)
+			Expect(err).ToNot(HaveOccurred())
+			resp, err := client.Do(req)
+			Expect(err).ToNot(HaveOccurred())
+			resp.Body.Close()
+		}
+
+		Expect(responded()).To(BeFalse())
+		get("/v3/spaces")
+		Expect(responded()).To(BeFalse())
+		get("/v3/apps")
+		Expect(responded()).To(BeTrue())
+	})
+	It("should probe single endpoints, reusing recently observed reachability", func() {
+		Expect(ProbeEndpoint(context.Background(), servers[0].URL(), nil)).To(Succeed())
+		Expect(servers[0].ReceivedRequests()).To(HaveLen(1))
+		Expect(ProbeEndpoint(context.Background(), servers[0].URL(), nil)).To(Succeed())
+		Expect(servers[0].ReceivedRequests()).To(HaveLen(1))
+
+		unreachableURL := servers[1].URL()
+		servers[1].Close()
+		Expect(ProbeEndpoint(context.Background(), unreachableURL, nil)).To(HaveOccurred())
+		Expect(reachability[unreachableURL].err).To(HaveOccurred())
+	})
+})
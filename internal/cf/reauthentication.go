@@ -0,0 +1,79 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cf
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// reauthenticationTransport logs in again (using the password grant) if refreshing the access token fails,
+// for example because the refresh token expired during a long idle period, or was revoked.
+// The token source of the CF client never recovers from a failed refresh; by answering the refresh request
+// with the tokens obtained through the new login, the client continues to work transparently.
+type reauthenticationTransport struct {
+	transport http.RoundTripper
+	url       string
+	username  string
+	password  string
+}
+
+func (t *reauthenticationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPost || !strings.HasSuffix(req.URL.Path, "/oauth/token") || req.Body == nil {
+		return t.transport.RoundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil || form.Get("grant_type") != "refresh_token" {
+		return t.transport.RoundTrip(withBody(req, body))
+	}
+
+	resp, err := t.transport.RoundTrip(withBody(req, body))
+	if err != nil || (resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusBadRequest) {
+		return resp, err
+	}
+	// drain body to allow re-use of the connection
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	// client authentication is passed on as is (either as header, or as form values)
+	login := url.Values{
+		"grant_type": {"password"},
+		"username":   {t.username},
+		"password":   {t.password},
+		"scope":      {form.Get("scope")},
+	}
+	for _, key := range []string{"client_id", "client_secret"} {
+		if form.Has(key) {
+			login.Set(key, form.Get(key))
+		}
+	}
+	resp, err = t.transport.RoundTrip(withBody(req, []byte(login.Encode())))
+	switch {
+	case err != nil, resp.StatusCode != http.StatusOK:
+		clientReauthentications.WithLabelValues(t.url, "failure").Inc()
+	default:
+		clientReauthentications.WithLabelValues(t.url, "success").Inc()
+	}
+	return resp, err
+}
+
+// withBody returns a copy of the given request with the given body.
+func withBody(req *http.Request, body []byte) *http.Request {
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	req.ContentLength = int64(len(body))
+	return req
+}
@@ -0,0 +1,91 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package cf
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("Re-authentication tests", func() {
+	var server *ghttp.Server
+	var transport *reauthenticationTransport
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		DeferCleanup(server.Close)
+		transport = &reauthenticationTransport{transport: http.DefaultTransport, url: server.URL(), username: Username, password: Password}
+	})
+
+	tokenRequest := func(form url.Values) *http.Request {
+		req, err := http.NewRequest(http.MethodPost, server.URL()+"/oauth/token", strings.NewReader(form.Encode()))
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth("cf", "")
+		return req
+	}
+
+	It("should pass through successful refreshes and other grants", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyForm(url.Values{"grant_type": {"refresh_token"}}),
+				ghttp.RespondWith(http.StatusOK, `{"access_token": "token"}`),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyForm(url.Values{"grant_type": {"password"}}),
+				ghttp.RespondWith(http.StatusUnauthorized, `{"error": "unauthorized"}`),
+			),
+		)
+
+		resp, err := transport.RoundTrip(tokenRequest(url.Values{"grant_type": {"refresh_token"}, "refresh_token": {"refresh"}}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		resp, err = transport.RoundTrip(tokenRequest(url.Values{"grant_type": {"password"}, "username": {Username}, "password": {"wrong"}}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		Expect(server.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("should log in again if the token cannot be refreshed", func() {
+		successes := testutil.ToFloat64(clientReauthentications.WithLabelValues(server.URL(), "success"))
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyForm(url.Values{"grant_type": {"refresh_token"}, "refresh_token": {"expired"}}),
+				ghttp.RespondWith(http.StatusUnauthorized, `{"error": "invalid_token"}`),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyBasicAuth("cf", ""),
+				ghttp.VerifyForm(url.Values{"grant_type": {"password"}, "username": {Username}, "password": {Password}, "scope": {"openid"}}),
+				ghttp.RespondWith(http.StatusOK, `{"access_token": "token", "refresh_token": "refresh"}`),
+			),
+		)
+
+		resp, err := transport.RoundTrip(tokenRequest(url.Values{"grant_type": {"refresh_token"}, "refresh_token": {"expired"}, "scope": {"openid"}}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(server.ReceivedRequests()).To(HaveLen(2))
+		Expect(testutil.ToFloat64(clientReauthentications.WithLabelValues(server.URL(), "success"))).To(Equal(successes + 1))
+	})
+
+	It("should count failed logins", func() {
+		failures := testutil.ToFloat64(clientReauthentications.WithLabelValues(server.URL(), "failure"))
+		server.AppendHandlers(
+			ghttp.RespondWith(http.StatusBadRequest, `{"error": "invalid_grant"}`),
+			ghttp.RespondWith(http.StatusUnauthorized, `{"error": "unauthorized"}`),
+		)
+
+		resp, err := transport.RoundTrip(tokenRequest(url.Values{"grant_type": {"refresh_token"}, "refresh_token": {"revoked"}}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		Expect(testutil.ToFloat64(clientReauthentications.WithLabelValues(server.URL(), "failure"))).To(Equal(failures + 1))
+	})
+})
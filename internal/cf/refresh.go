@@ -0,0 +1,51 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cf
+
+import (
+	"context"
+	"fmt"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient/v3/client"
+	"github.com/pkg/errors"
+)
+
+// RefreshCache reads the service instances and bindings of the client's space which are owned by some object into the resource cache
+// (replacing existing entries, and resetting their expiration); no-op if the resource cache is disabled.
+// As with lookups through GetInstance and GetBinding, only ready instances and bindings are cached; instances with an available upgrade
+// are skipped, since the offered version would have to be read from their service plan (they are cached on their next lookup).
+func (c *spaceClient) RefreshCache(ctx context.Context) error {
+	if c.resourceCache == nil {
+		return nil
+	}
+
+	listOpts := cfclient.NewServiceInstanceListOptions()
+	listOpts.LabelSelector.EqualTo(labelOwner)
+	listOpts.SpaceGUIDs.EqualTo(c.spaceGuid)
+	serviceInstances, err := listAllPages(ctx, c.paging, resourceTypeInstance, listOpts, c.client.ServiceInstances.List)
+	if err != nil {
+		return fmt.Errorf("failed to list service instances: %w", err)
+	}
+	for _, serviceInstance := range serviceInstances {
+		if serviceInstance.UpgradeAvailable != nil && *serviceInstance.UpgradeAvailable {
+			continue
+		}
+		instance, err := newInstance(serviceInstance, *serviceInstance.Metadata.Labels[labelOwner])
+		if err != nil {
+			return errors.Wrapf(err, "invalid service instance %s", serviceInstance.GUID)
+		}
+		c.resourceCache.addInstance(instance)
+	}
+
+	bindings, err := c.ListBindings(ctx)
+	if err != nil {
+		return err
+	}
+	for _, binding := range bindings {
+		c.resourceCache.addBinding(binding)
+	}
+	return nil
+}
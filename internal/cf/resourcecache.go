@@ -0,0 +1,347 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cf
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sap/cf-service-operator/internal/cache"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+// resourceCache holds Cloud Foundry resources read through one CF client (that is, one set of credentials).
+// The cache is split into partitions per scope (organization for spaces, space for service instances and bindings),
+// such that each client only reads (and caches) the resources of its own scope, instead of all resources visible to the credentials.
+type resourceCache struct {
+	mutex      sync.Mutex
+	ttl        time.Duration
+	partitions map[string]*resourcePartition
+	nextPrune  time.Time
+}
+
+// resourcePartition holds the cached resources of one scope, indexed by owner.
+// Only resources in a stable state are cached; entries are invalidated when the operator modifies the according resource,
+// and expire after the configured cache timeout (bounding the staleness with respect to changes made outside of the operator).
+type resourcePartition struct {
+	ttl time.Duration
+	// last time the partition was requested (that is, a client for its scope was built)
+	lastUsed  time.Time
+	spaces    *cache.Cache[facade.Space]
+	instances *cache.Cache[facade.Instance]
+	bindings  *cache.Cache[facade.Binding]
+}
+
+// newResourceCache returns a resource cache according to the given configuration,
+// or nil if resource caching is disabled.
+func newResourceCache(cfg *config.Config) *resourceCache {
+	if cfg == nil || !cfg.IsResourceCacheEnabled {
+		return nil
+	}
+	return &resourceCache{
+		ttl:        cfg.CacheTimeOut.Duration,
+		partitions: make(map[string]*resourcePartition),
+	}
+}
+
+// matches reports whether the resource cache (nil if caching is disabled) is set up according to the given configuration.
+func (rc *resourceCache) matches(cfg *config.Config) bool {
+	if rc == nil {
+		return cfg == nil || !cfg.IsResourceCacheEnabled
+	}
+	return cfg != nil && cfg.IsResourceCacheEnabled && rc.ttl == cfg.CacheTimeOut.Duration
+}
+
+// organizationPartition returns the partition for spaces of the given organization (nil if caching is disabled).
+func (rc *resourceCache) organizationPartition(organizationName string) *resourcePartition {
+	return rc.partition("organization:"+organizationName, 0)
+}
+
+// spacePartition returns the partition for service instances and bindings of the given space (nil if caching is disabled);
+// a positive ttl overrides the cache timeout for this partition.
+func (rc *resourceCache) spacePartition(spaceGuid string, ttl time.Duration) *resourcePartition {
+	return rc.partition("space:"+spaceGuid, ttl)
+}
+
+// partition returns the partition for the given scope, with entries expiring after ttl (or the cache timeout, if ttl is not positive);
+// the timeout of an existing partition is updated in place (see cache.SetTTL), keeping the cached entries.
+// Partitions of other scopes are pruned from time to time (see prune).
+func (rc *resourceCache) partition(scope string, ttl time.Duration) *resourcePartition {
+	if rc == nil {
+		return nil
+	}
+	if ttl <= 0 {
+		ttl = rc.ttl
+	}
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	now := time.Now()
+	if !now.Before(rc.nextPrune) {
+		rc.prune(now)
+		rc.nextPrune = now.Add(rc.ttl)
+	}
+	rp, ok := rc.partitions[scope]
+	if !ok {
+		rp = &resourcePartition{
+			ttl:       ttl,
+			spaces:    cache.New[facade.Space](ttl, expirationHandler(resourceTypeSpace)),
+			instances: cache.New[facade.Instance](ttl, expirationHandler(resourceTypeInstance)),
+			bindings:  cache.New[facade.Binding](ttl, expirationHandler(resourceTypeBinding)),
+		}
+		rc.partitions[scope] = rp
+	} else if rp.ttl != ttl {
+		rp.ttl = ttl
+		rp.spaces.SetTTL(ttl)
+		rp.instances.SetTTL(ttl)
+		rp.bindings.SetTTL(ttl)
+	}
+	rp.lastUsed = now
+	return rp
+}
+
+// prune drops the partitions which were not used for longer than their timeout, and hold no unexpired entries
+// (such as the partitions of deleted spaces, or of spaces now accessed with other credentials); must be called with the cache locked.
+func (rc *resourceCache) prune(now time.Time) {
+	for scope, rp := range rc.partitions {
+		if now.Sub(rp.lastUsed) > rp.ttl && rp.spaces.Len() == 0 && rp.instances.Len() == 0 && rp.bindings.Len() == 0 {
+			delete(rc.partitions, scope)
+		}
+	}
+}
+
+// len returns the number of cached spaces, instances and bindings (summed up over all partitions).
+func (rc *resourceCache) len() (int, int, int) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	spaces, instances, bindings := 0, 0, 0
+	for _, rp := range rc.partitions {
+		spaces += rp.spaces.Len()
+		instances += rp.instances.Len()
+		bindings += rp.bindings.Len()
+	}
+	return spaces, instances, bindings
+}
+
+// GetResourceCacheStatistics returns the current size of all resource caches (summed up over all cached CF clients),
+// and the number of cache hits and misses since operator start.
+func GetResourceCacheStatistics() facade.ResourceCacheStatistics {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	statistics := facade.ResourceCacheStatistics{
+		Hits:   resourceCacheHits.Load(),
+		Misses: resourceCacheMisses.Load(),
+	}
+	for _, cacheEntry := range clientCache {
+		if rc := cacheEntry.resourceCache; rc != nil {
+			spaces, instances, bindings := rc.len()
+			statistics.Spaces += spaces
+			statistics.Instances += instances
+			statistics.Bindings += bindings
+		}
+	}
+	return statistics
+}
+
+// ClearResourceCaches drops the cached resources of all cached CF clients (the clients themselves are kept).
+func ClearResourceCaches() {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	for _, cacheEntry := range clientCache {
+		cacheEntry.resourceCache.clear()
+	}
+}
+
+// clear drops the cached resources of all partitions.
+func (rc *resourceCache) clear() {
+	if rc == nil {
+		return
+	}
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	for _, rp := range rc.partitions {
+		rp.spaces.Clear()
+		rp.instances.Clear()
+		rp.bindings.Clear()
+	}
+}
+
+// DumpCaches returns the cached CF clients (sorted by url and username), and the contents of their resource caches
+// (for debugging purposes); credentials of cached bindings are omitted.
+// Implements facade.CacheDumpProvider.
+func DumpCaches() facade.CacheDump {
+	dump := facade.CacheDump{Statistics: GetResourceCacheStatistics()}
+
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	for _, cacheEntry := range clientCache {
+		dump.Clients = append(dump.Clients, facade.ClientCacheDump{
+			URL:        cacheEntry.url,
+			Username:   cacheEntry.username,
+			LastUsedAt: cacheEntry.lastUsedAt,
+			Partitions: cacheEntry.resourceCache.dump(),
+		})
+	}
+	sort.Slice(dump.Clients, func(i, j int) bool {
+		if dump.Clients[i].URL != dump.Clients[j].URL {
+			return dump.Clients[i].URL < dump.Clients[j].URL
+		}
+		return dump.Clients[i].Username < dump.Clients[j].Username
+	})
+	return dump
+}
+
+// dump returns the contents of all partitions (sorted by scope); nil if caching is disabled.
+func (rc *resourceCache) dump() []facade.ResourcePartitionDump {
+	if rc == nil {
+		return nil
+	}
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	var partitions []facade.ResourcePartitionDump
+	for scope, rp := range rc.partitions {
+		partition := facade.ResourcePartitionDump{Scope: scope, TTL: rp.ttl.String()}
+		rp.spaces.Range(func(owner string, space facade.Space) {
+			partition.Spaces = append(partition.Spaces, facade.CachedResource{Owner: owner, Guid: space.Guid, Name: space.Name})
+		})
+		rp.instances.Range(func(owner string, instance facade.Instance) {
+			partition.Instances = append(partition.Instances, facade.CachedResource{Owner: owner, Guid: instance.Guid, Name: instance.Name, State: string(instance.State)})
+		})
+		rp.bindings.Range(func(owner string, binding facade.Binding) {
+			partition.Bindings = append(partition.Bindings, facade.CachedResource{Owner: owner, Guid: binding.Guid, Name: binding.Name, State: string(binding.State)})
+		})
+		for _, resources := range [][]facade.CachedResource{partition.Spaces, partition.Instances, partition.Bindings} {
+			sort.Slice(resources, func(i, j int) bool { return resources[i].Owner < resources[j].Owner })
+		}
+		partitions = append(partitions, partition)
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].Scope < partitions[j].Scope })
+	return partitions
+}
+
+func expirationHandler(resourceType string) cache.Option {
+	expirations := resourceCacheExpirations.WithLabelValues(resourceType)
+	return cache.WithExpirationHandler(func(string) { expirations.Inc() })
+}
+
+// overall number of cache hits and misses (as reported by GetResourceCacheStatistics)
+var resourceCacheHits, resourceCacheMisses atomic.Int64
+
+func recordLookup(resourceType string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+		resourceCacheHits.Add(1)
+	} else {
+		resourceCacheMisses.Add(1)
+	}
+	resourceCacheLookups.WithLabelValues(resourceType, result).Inc()
+}
+
+// observeRefresh records the time passed since start as duration of reading a resource from Cloud Foundry after a cache miss.
+// Supposed to be deferred right after the cache miss.
+func (rp *resourcePartition) observeRefresh(resourceType string, start time.Time) {
+	if rp == nil {
+		return
+	}
+	resourceCacheRefreshDuration.WithLabelValues(resourceType).Observe(time.Since(start).Seconds())
+}
+
+func (rp *resourcePartition) getSpace(owner string) (*facade.Space, bool) {
+	if rp == nil || owner == "" {
+		return nil, false
+	}
+	space, ok := rp.spaces.Get(owner)
+	recordLookup(resourceTypeSpace, ok)
+	return &space, ok
+}
+
+func (rp *resourcePartition) addSpace(space *facade.Space) {
+	if rp == nil || space == nil || space.Owner == "" {
+		return
+	}
+	rp.spaces.Set(space.Owner, *space)
+}
+
+// deleteSpace invalidates the cached space with the given guid; the entry is looked up by owner, unless the owner is unknown.
+func (rp *resourcePartition) deleteSpace(guid string, owner facade.OwnerRef) {
+	if rp == nil {
+		return
+	}
+	if owner.UID != "" {
+		rp.spaces.Delete(owner.UID)
+		return
+	}
+	rp.spaces.DeleteFunc(func(_ string, space facade.Space) bool { return space.Guid == guid })
+}
+
+func (rp *resourcePartition) getInstance(owner string) (*facade.Instance, bool) {
+	if rp == nil || owner == "" {
+		return nil, false
+	}
+	instance, ok := rp.instances.Get(owner)
+	recordLookup(resourceTypeInstance, ok)
+	return &instance, ok
+}
+
+func (rp *resourcePartition) addInstance(instance *facade.Instance) {
+	if rp == nil || instance == nil || instance.Owner == "" || instance.State != facade.InstanceStateReady {
+		return
+	}
+	rp.instances.Set(instance.Owner, *instance)
+}
+
+// deleteInstance invalidates the cached instance with the given guid; the entry is looked up by owner, unless the owner is unknown.
+func (rp *resourcePartition) deleteInstance(guid string, owner facade.OwnerRef) {
+	if rp == nil {
+		return
+	}
+	if owner.UID != "" {
+		rp.instances.Delete(owner.UID)
+		return
+	}
+	rp.instances.DeleteFunc(func(_ string, instance facade.Instance) bool { return instance.Guid == guid })
+}
+
+func (rp *resourcePartition) getBinding(owner string) (*facade.Binding, bool) {
+	if rp == nil || owner == "" {
+		return nil, false
+	}
+	binding, ok := rp.bindings.Get(owner)
+	recordLookup(resourceTypeBinding, ok)
+	return &binding, ok
+}
+
+func (rp *resourcePartition) addBinding(binding *facade.Binding) {
+	if rp == nil || binding == nil || binding.Owner == "" || binding.State != facade.BindingStateReady {
+		return
+	}
+	rp.bindings.Set(binding.Owner, *binding)
+}
+
+// deleteBinding invalidates the cached binding with the given guid; the entry is looked up by owner, unless the owner is unknown.
+func (rp *resourcePartition) deleteBinding(guid string, owner facade.OwnerRef) {
+	if rp == nil {
+		return
+	}
+	if owner.UID != "" {
+		rp.bindings.Delete(owner.UID)
+		return
+	}
+	rp.bindings.DeleteFunc(func(_ string, binding facade.Binding) bool { return binding.Guid == guid })
+}
@@ -0,0 +1,254 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package cf
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"golang.org/x/time/rate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+var _ = Describe("Resource cache tests", func() {
+	var rc *resourceCache
+	var rp *resourcePartition
+
+	BeforeEach(func() {
+		cfg := config.Defaults()
+		cfg.IsResourceCacheEnabled = true
+		cfg.CacheTimeOut = metav1.Duration{Duration: time.Minute}
+		rc = newResourceCache(cfg)
+		rp = rc.spacePartition("space-guid", 0)
+	})
+
+	It("should be disabled by default", func() {
+		Expect(newResourceCache(config.Defaults())).To(BeNil())
+		Expect(newResourceCache(nil)).To(BeNil())
+
+		// a disabled cache never hits
+		var disabledCache *resourceCache
+		disabled := disabledCache.organizationPartition(OrgName)
+		Expect(disabled).To(BeNil())
+		disabled.addSpace(&facade.Space{Guid: "guid", Owner: Owner})
+		_, ok := disabled.getSpace(Owner)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should cache and invalidate spaces", func() {
+		rp.addSpace(&facade.Space{Guid: "guid", Owner: Owner})
+		space, ok := rp.getSpace(Owner)
+		Expect(ok).To(BeTrue())
+		Expect(space.Guid).To(Equal("guid"))
+
+		rp.deleteSpace("guid", facade.OwnerRef{})
+		_, ok = rp.getSpace(Owner)
+		Expect(ok).To(BeFalse())
+
+		rp.addSpace(&facade.Space{Guid: "guid", Owner: Owner})
+		rp.deleteSpace("guid", facade.OwnerRef{UID: Owner})
+		_, ok = rp.getSpace(Owner)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should keep separate partitions per scope", func() {
+		Expect(rc.spacePartition("space-guid", 0)).To(BeIdenticalTo(rp))
+		other := rc.spacePartition("other-space-guid", 0)
+		Expect(other).ToNot(BeIdenticalTo(rp))
+		Expect(rc.organizationPartition("space-guid")).ToNot(BeIdenticalTo(rp))
+
+		rp.addInstance(&facade.Instance{Guid: "guid", Owner: Owner, State: facade.InstanceStateReady})
+		_, ok := other.getInstance(Owner)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should prune partitions which are no longer used", func() {
+		rp.addInstance(&facade.Instance{Guid: "guid", Owner: Owner, State: facade.InstanceStateReady})
+		other := rc.spacePartition("other-space-guid", 0)
+		Expect(rc.partitions).To(HaveLen(2))
+
+		// unused partitions are only dropped once their entries expired
+		rp.lastUsed = time.Now().Add(-2 * time.Minute)
+		other.lastUsed = rp.lastUsed
+		rc.nextPrune = time.Time{}
+		rc.spacePartition("third-space-guid", 0)
+		Expect(rc.partitions).To(HaveKey("space:space-guid"))
+		Expect(rc.partitions).NotTo(HaveKey("space:other-space-guid"))
+
+		rp.instances.Clear()
+		rc.nextPrune = time.Time{}
+		rc.spacePartition("third-space-guid", 0)
+		Expect(rc.partitions).To(HaveLen(1))
+		Expect(rc.partitions).To(HaveKey("space:third-space-guid"))
+	})
+
+	It("should apply per-space cache timeouts", func() {
+		Expect(rp.ttl).To(Equal(time.Minute))
+		Expect(rc.spacePartition("space-guid", time.Minute)).To(BeIdenticalTo(rp))
+
+		rp.addInstance(&facade.Instance{Guid: "guid", Owner: Owner, State: facade.InstanceStateReady})
+		overridden := rc.spacePartition("space-guid", time.Hour)
+		Expect(overridden).To(BeIdenticalTo(rp))
+		Expect(overridden.ttl).To(Equal(time.Hour))
+		// the cached entries are kept when the timeout changes
+		_, ok := overridden.getInstance(Owner)
+		Expect(ok).To(BeTrue())
+		Expect(rc.spacePartition("space-guid", time.Hour)).To(BeIdenticalTo(overridden))
+	})
+
+	It("should only cache ready instances and bindings", func() {
+		rp.addInstance(&facade.Instance{Guid: "guid", Owner: Owner, State: facade.InstanceStateCreating})
+		_, ok := rp.getInstance(Owner)
+		Expect(ok).To(BeFalse())
+		rp.addInstance(&facade.Instance{Guid: "guid", Owner: Owner, State: facade.InstanceStateReady})
+		_, ok = rp.getInstance(Owner)
+		Expect(ok).To(BeTrue())
+
+		rp.addBinding(&facade.Binding{Guid: "guid", Owner: Owner, State: facade.BindingStateCreating})
+		_, ok = rp.getBinding(Owner)
+		Expect(ok).To(BeFalse())
+		rp.addBinding(&facade.Binding{Guid: "guid", Owner: Owner, State: facade.BindingStateReady})
+		_, ok = rp.getBinding(Owner)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should count hits and misses", func() {
+		hits := testutil.ToFloat64(resourceCacheLookups.WithLabelValues(resourceTypeSpace, "hit"))
+		misses := testutil.ToFloat64(resourceCacheLookups.WithLabelValues(resourceTypeSpace, "miss"))
+
+		rp.getSpace(Owner)
+		rp.addSpace(&facade.Space{Guid: "guid", Owner: Owner})
+		rp.getSpace(Owner)
+		rp.getSpace(Owner)
+
+		Expect(testutil.ToFloat64(resourceCacheLookups.WithLabelValues(resourceTypeSpace, "hit"))).To(Equal(hits + 2))
+		Expect(testutil.ToFloat64(resourceCacheLookups.WithLabelValues(resourceTypeSpace, "miss"))).To(Equal(misses + 1))
+	})
+
+	It("should report the number of cached entries", func() {
+		setClientCache(map[clientIdentifier]*clientCacheEntry{
+			{url: "url", username: Username}: {resourceCache: rc},
+		})
+
+		rc.organizationPartition(OrgName).addSpace(&facade.Space{Guid: "guid", Owner: Owner})
+		rc.organizationPartition(OrgName2).addSpace(&facade.Space{Guid: "guid2", Owner: Owner2})
+		rp.addInstance(&facade.Instance{Guid: "guid", Owner: Owner, State: facade.InstanceStateReady})
+
+		expected := `
+# HELP cf_resource_cache_entries The number of resources currently cached, by resource type
+# TYPE cf_resource_cache_entries gauge
+cf_resource_cache_entries{resource="binding"} 0
+cf_resource_cache_entries{resource="instance"} 1
+cf_resource_cache_entries{resource="space"} 2
+`
+		Expect(testutil.CollectAndCompare(&resourceCacheCollector{}, strings.NewReader(expected))).To(Succeed())
+	})
+
+	It("should report cache statistics", func() {
+		setClientCache(map[clientIdentifier]*clientCacheEntry{
+			{url: "url", username: Username}:  {resourceCache: rc},
+			{url: "url2", username: Username}: {},
+		})
+
+		before := GetResourceCacheStatistics()
+		rp.addBinding(&facade.Binding{Guid: "guid", Owner: Owner, State: facade.BindingStateReady})
+		rp.getBinding(Owner)
+		rp.getBinding(Owner2)
+
+		statistics := GetResourceCacheStatistics()
+		Expect(statistics.Spaces).To(Equal(0))
+		Expect(statistics.Instances).To(Equal(0))
+		Expect(statistics.Bindings).To(Equal(1))
+		Expect(statistics.Hits).To(Equal(before.Hits + 1))
+		Expect(statistics.Misses).To(Equal(before.Misses + 1))
+	})
+
+	It("should clear cached resources, but keep cached clients", func() {
+		setClientCache(map[clientIdentifier]*clientCacheEntry{
+			{url: "url", username: Username}:  {resourceCache: rc},
+			{url: "url2", username: Username}: {},
+		})
+
+		rp.addInstance(&facade.Instance{Guid: "guid", Owner: Owner, State: facade.InstanceStateReady})
+		rp.addBinding(&facade.Binding{Guid: "guid", Owner: Owner, State: facade.BindingStateReady})
+
+		ClearResourceCaches()
+		Expect(clientCache).To(HaveLen(2))
+		Expect(GetResourceCacheStatistics().Instances).To(Equal(0))
+		Expect(GetResourceCacheStatistics().Bindings).To(Equal(0))
+	})
+
+	It("should dump cached clients and resources without credentials", func() {
+		setClientCache(map[clientIdentifier]*clientCacheEntry{
+			{url: "url2", username: Username}: {url: "url2", username: Username},
+			{url: "url", username: Username}:  {url: "url", username: Username, resourceCache: rc},
+		})
+
+		rp.addBinding(&facade.Binding{Guid: "guid", Name: "binding", Owner: Owner, State: facade.BindingStateReady})
+
+		dump := DumpCaches()
+		Expect(dump.Clients).To(HaveLen(2))
+		Expect(dump.Clients[0].URL).To(Equal("url"))
+		Expect(dump.Clients[0].Partitions).To(Equal([]facade.ResourcePartitionDump{{
+			Scope:    "space:space-guid",
+			TTL:      "1m0s",
+			Bindings: []facade.CachedResource{{Owner: Owner, Guid: "guid", Name: "binding", State: string(facade.BindingStateReady)}},
+		}}))
+		Expect(dump.Clients[1].Partitions).To(BeNil())
+		Expect(dump.Statistics.Bindings).To(Equal(1))
+	})
+
+	It("should apply reloaded settings to cached clients", func() {
+		limiter := rate.NewLimiter(rate.Inf, 1)
+		setClientCache(map[clientIdentifier]*clientCacheEntry{
+			{url: "url", username: Username}:  {resourceCache: rc},
+			{url: "url2", username: Username}: {},
+		})
+		cacheMutex.Lock()
+		previousRateLimiters, previousReloadedConfig, previousMaxRetries := rateLimiters, reloadedConfig, maxRetriesOnTooManyRequests.Load()
+		rateLimiters = map[string]*rate.Limiter{"url": limiter}
+		cacheMutex.Unlock()
+		DeferCleanup(func() {
+			cacheMutex.Lock()
+			defer cacheMutex.Unlock()
+			rateLimiters, reloadedConfig = previousRateLimiters, previousReloadedConfig
+			maxRetriesOnTooManyRequests.Store(previousMaxRetries)
+		})
+
+		cfg := config.Defaults()
+		cfg.IsResourceCacheEnabled = true
+		cfg.CacheTimeOut = metav1.Duration{Duration: time.Minute}
+		cfg.MaxRequestsPerSecond = 5
+		Reconfigure(cfg)
+		Expect(limiter.Limit()).To(Equal(rate.Limit(5)))
+		Expect(clientCache[clientIdentifier{url: "url", username: Username}].resourceCache).To(BeIdenticalTo(rc))
+		Expect(clientCache[clientIdentifier{url: "url2", username: Username}].resourceCache).ToNot(BeNil())
+
+		cfg = config.Defaults()
+		Reconfigure(cfg)
+		Expect(limiter.Limit()).To(Equal(rate.Inf))
+		Expect(clientCache[clientIdentifier{url: "url", username: Username}].resourceCache).To(BeNil())
+	})
+})
+
+// setClientCache replaces the cached clients for the current test; the previous entries are restored when the test ends.
+func setClientCache(entries map[clientIdentifier]*clientCacheEntry) {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	previous := clientCache
+	DeferCleanup(func() {
+		cacheMutex.Lock()
+		defer cacheMutex.Unlock()
+		clientCache = previous
+	})
+	clientCache = entries
+}
@@ -0,0 +1,195 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient/v3/client"
+	cfresource "github.com/cloudfoundry-community/go-cfclient/v3/resource"
+	"github.com/pkg/errors"
+
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+// FindDomain returns the guid of the domain with the given name (as visible to the client);
+// an error is returned if there is no such domain.
+func (c *spaceClient) FindDomain(ctx context.Context, name string) (string, error) {
+	listOpts := cfclient.NewDomainListOptions()
+	listOpts.Names.EqualTo(name)
+	domains, err := c.client.Domains.ListAll(ctx, listOpts)
+	if err != nil {
+		return "", fmt.Errorf("failed to list domains: %w", err)
+	}
+	if len(domains) == 0 {
+		return "", fmt.Errorf("domain not found, name: %s", name)
+	} else if len(domains) > 1 {
+		return "", fmt.Errorf("found multiple domains with name: %s", name)
+	}
+	return domains[0].GUID, nil
+}
+
+// GetRoute returns the route in the client's space with the given owner, or nil if there is none.
+func (c *spaceClient) GetRoute(ctx context.Context, owner string) (*facade.Route, error) {
+	listOpts := cfclient.NewRouteListOptions()
+	listOpts.SpaceGUIDs.EqualTo(c.spaceGuid)
+	listOpts.LabelSelector.EqualTo(fmt.Sprintf("%s/%s=%s", labelPrefix, labelKeyOwner, owner))
+	routes, err := c.client.Routes.ListAll(ctx, listOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list routes: %w", err)
+	}
+
+	if len(routes) == 0 {
+		return nil, nil
+	} else if len(routes) > 1 {
+		return nil, fmt.Errorf("found multiple routes with owner: %s", owner)
+	}
+	route := routes[0]
+
+	generation, err := parseGeneration(annotationOf(route.Metadata, annotationGeneration))
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing route generation")
+	}
+
+	domainGuid := ""
+	if route.Relationships.Domain.Data != nil {
+		domainGuid = route.Relationships.Domain.Data.GUID
+	}
+
+	return &facade.Route{
+		Guid:       route.GUID,
+		Host:       route.Host,
+		Path:       route.Path,
+		DomainGuid: domainGuid,
+		URL:        route.URL,
+		Owner:      owner,
+		Generation: generation,
+	}, nil
+}
+
+// parseGeneration parses the given generation annotation value; a missing annotation (for example, removed by some other tool)
+// is taken as generation zero, so that the metadata of the resource are updated with the next reconcile.
+func parseGeneration(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// Required parameters (may not be initial): domainGuid, owner, generation
+// Optional parameters (may be initial): host, path
+func (c *spaceClient) CreateRoute(ctx context.Context, domainGuid string, host string, path string, owner string, generation int64) error {
+	req := cfresource.NewRouteCreate(domainGuid, c.spaceGuid)
+	if host != "" {
+		req.Host = &host
+	}
+	if path != "" {
+		req.Path = &path
+	}
+	req.Metadata = cfresource.NewMetadata().
+		WithLabel(labelPrefix, labelKeyOwner, owner).
+		WithAnnotation(annotationPrefix, annotationKeyGeneration, strconv.FormatInt(generation, 10))
+
+	_, err := c.client.Routes.Create(ctx, req)
+	return err
+}
+
+// Required parameters (may not be initial): guid, generation
+func (c *spaceClient) UpdateRoute(ctx context.Context, guid string, generation int64) error {
+	req := &cfresource.RouteUpdate{}
+	req.Metadata = cfresource.NewMetadata().
+		WithAnnotation(annotationPrefix, annotationKeyGeneration, strconv.FormatInt(generation, 10))
+
+	_, err := c.client.Routes.Update(ctx, guid, req)
+	return err
+}
+
+func (c *spaceClient) DeleteRoute(ctx context.Context, guid string) error {
+	_, err := c.client.Routes.Delete(ctx, guid)
+	return err
+}
+
+// GetRouteBinding returns the route binding with the given owner, or nil if there is none.
+func (c *spaceClient) GetRouteBinding(ctx context.Context, owner string) (*facade.RouteBinding, error) {
+	listOpts := cfclient.NewServiceRouteBindingListOptions()
+	listOpts.LabelSelector.EqualTo(fmt.Sprintf("%s/%s=%s", labelPrefix, labelKeyOwner, owner))
+	routeBindings, err := c.client.ServiceRouteBindings.ListAll(ctx, listOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service route bindings: %w", err)
+	}
+
+	if len(routeBindings) == 0 {
+		return nil, nil
+	} else if len(routeBindings) > 1 {
+		return nil, fmt.Errorf("found multiple service route bindings with owner: %s", owner)
+	}
+	routeBinding := routeBindings[0]
+
+	generation, err := parseGeneration(annotationOf(routeBinding.Metadata, annotationGeneration))
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing service route binding generation")
+	}
+	// a missing parameter hash lets the route binding be re-created, since its parameters are unknown
+	parameterHash := annotationOf(routeBinding.Metadata, annotationParameterHash)
+	var routeGuid, serviceInstanceGuid string
+	if routeBinding.Relationships.Route.Data != nil {
+		routeGuid = routeBinding.Relationships.Route.Data.GUID
+	}
+	if routeBinding.Relationships.ServiceInstance.Data != nil {
+		serviceInstanceGuid = routeBinding.Relationships.ServiceInstance.Data.GUID
+	}
+
+	return &facade.RouteBinding{
+		Guid:                routeBinding.GUID,
+		RouteGuid:           routeGuid,
+		ServiceInstanceGuid: serviceInstanceGuid,
+		RouteServiceURL:     routeBinding.RouteServiceURL,
+		Owner:               owner,
+		Generation:          generation,
+		ParameterHash:       parameterHash,
+		State:               bindingStateOf(routeBinding.LastOperation),
+		StateDescription:    routeBinding.LastOperation.Description,
+	}, nil
+}
+
+// Required parameters (may not be initial): routeGuid, serviceInstanceGuid, owner, generation
+// Optional parameters (may be initial): parameters
+func (c *spaceClient) CreateRouteBinding(ctx context.Context, routeGuid string, serviceInstanceGuid string, parameters map[string]interface{}, owner string, generation int64) error {
+	req := cfresource.NewServiceRouteBindingCreate(routeGuid, serviceInstanceGuid)
+	if parameters != nil {
+		jsonParameters, err := json.Marshal(parameters)
+		if err != nil {
+			return err
+		}
+		rawParameters := json.RawMessage(jsonParameters)
+		req.Parameters = &rawParameters
+	}
+	req.Metadata = cfresource.NewMetadata().
+		WithLabel(labelPrefix, labelKeyOwner, owner).
+		WithAnnotation(annotationPrefix, annotationKeyGeneration, strconv.FormatInt(generation, 10)).
+		WithAnnotation(annotationPrefix, annotationKeyParameterHash, facade.ObjectHash(parameters))
+
+	_, _, err := c.client.ServiceRouteBindings.Create(ctx, req)
+	return err
+}
+
+// Required parameters (may not be initial): guid, generation
+func (c *spaceClient) UpdateRouteBinding(ctx context.Context, guid string, generation int64) error {
+	req := &cfresource.ServiceRouteBindingUpdate{}
+	req.Metadata = cfresource.NewMetadata().
+		WithAnnotation(annotationPrefix, annotationKeyGeneration, strconv.FormatInt(generation, 10))
+
+	_, err := c.client.ServiceRouteBindings.Update(ctx, guid, req)
+	return err
+}
+
+func (c *spaceClient) DeleteRouteBinding(ctx context.Context, guid string) error {
+	_, err := c.client.ServiceRouteBindings.Delete(ctx, guid)
+	return err
+}
@@ -9,15 +9,22 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	cfclient "github.com/cloudfoundry-community/go-cfclient/v3/client"
 	cfresource "github.com/cloudfoundry-community/go-cfclient/v3/resource"
 	"github.com/pkg/errors"
 
+	"github.com/sap/cf-service-operator/internal/events"
 	"github.com/sap/cf-service-operator/internal/facade"
 )
 
 func (c *organizationClient) GetSpace(ctx context.Context, owner string) (*facade.Space, error) {
+	if space, ok := c.resourceCache.getSpace(owner); ok {
+		return space, nil
+	}
+	defer c.resourceCache.observeRefresh(resourceTypeSpace, time.Now())
+
 	listOpts := cfclient.NewSpaceListOptions()
 	listOpts.LabelSelector.EqualTo(labelPrefix + "/" + labelKeyOwner + "=" + owner)
 	spaces, err := c.client.Spaces.ListAll(ctx, listOpts)
@@ -30,50 +37,66 @@ func (c *organizationClient) GetSpace(ctx context.Context, owner string) (*facad
 	} else if len(spaces) > 1 {
 		return nil, fmt.Errorf("found multiple spaces with owner: %s", owner)
 	}
-	space := spaces[0]
+	result, err := newSpace(spaces[0], owner)
+	if err != nil {
+		return nil, err
+	}
+	c.resourceCache.addSpace(result)
+	publishEvent(events.EventTypeRefreshed, events.ResourceTypeSpace, result.Guid, owner, "")
+	return result, nil
+}
 
-	guid := space.GUID
-	name := space.Name
+func newSpace(space *cfresource.Space, owner string) (*facade.Space, error) {
 	generation, err := strconv.ParseInt(*space.Metadata.Annotations[annotationGeneration], 10, 64)
 	if err != nil {
 		return nil, errors.Wrap(err, "error parsing space generation")
 	}
 
 	return &facade.Space{
-		Guid:       guid,
-		Name:       name,
+		Guid:       space.GUID,
+		Name:       space.Name,
 		Owner:      owner,
 		Generation: generation,
 	}, nil
 }
 
 // Required parameters (may not be initial): name, owner, generation
-func (c *organizationClient) CreateSpace(ctx context.Context, name string, owner string, generation int64) error {
+func (c *organizationClient) CreateSpace(ctx context.Context, name string, owner facade.OwnerRef, generation int64) (*facade.Space, error) {
 	listOpts := cfclient.NewOrganizationListOptions()
 	listOpts.Names.EqualTo(c.organizationName)
 	organizations, err := c.client.Organizations.ListAll(ctx, listOpts)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if len(organizations) == 0 {
-		return fmt.Errorf("found no organization with name: %s", c.organizationName)
+		return nil, fmt.Errorf("found no organization with name: %s", c.organizationName)
 	} else if len(organizations) > 1 {
-		return fmt.Errorf("found multiple organizations with name: %s (this should not be possible, actually)", c.organizationName)
+		return nil, fmt.Errorf("found multiple organizations with name: %s (this should not be possible, actually)", c.organizationName)
 	}
 	organization := organizations[0]
 
 	req := cfresource.NewSpaceCreate(name, organization.GUID)
 	req.Metadata = cfresource.NewMetadata().
-		WithLabel(labelPrefix, labelKeyOwner, owner).
+		WithLabel(labelPrefix, labelKeyOwner, owner.UID).
 		WithAnnotation(annotationPrefix, annotationKeyGeneration, strconv.FormatInt(generation, 10))
 
-	_, err = c.client.Spaces.Create(ctx, req)
-	return err
+	space, err := c.client.Spaces.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	// spaces are created synchronously; caching the returned space saves later lookups
+	result, err := newSpace(space, owner.UID)
+	if err != nil {
+		return nil, err
+	}
+	c.resourceCache.addSpace(result)
+	publishEvent(events.EventTypeCreated, events.ResourceTypeSpace, result.Guid, owner.UID, "")
+	return result, nil
 }
 
 // Required parameters (may not be initial): guid, generation
-// Optional parameters (may be initial): name
-func (c *organizationClient) UpdateSpace(ctx context.Context, guid string, name string, generation int64) error {
+// Optional parameters (may be initial): owner, name
+func (c *organizationClient) UpdateSpace(ctx context.Context, guid string, owner facade.OwnerRef, name string, generation int64) error {
 	// TODO: why is there no cfresource.NewSpaceUpdate() method ?
 	req := &cfresource.SpaceUpdate{}
 	if name != "" {
@@ -82,48 +105,126 @@ func (c *organizationClient) UpdateSpace(ctx context.Context, guid string, name
 	req.Metadata = cfresource.NewMetadata().
 		WithAnnotation(annotationPrefix, annotationKeyGeneration, strconv.FormatInt(generation, 10))
 
-	_, err := c.client.Spaces.Update(ctx, guid, req)
-	return err
+	c.resourceCache.deleteSpace(guid, owner)
+	if _, err := c.client.Spaces.Update(ctx, guid, req); err != nil {
+		return err
+	}
+	publishEvent(events.EventTypeUpdated, events.ResourceTypeSpace, guid, owner.UID, "")
+	return nil
 }
 
-func (c *organizationClient) DeleteSpace(ctx context.Context, guid string) error {
-	_, err := c.client.Spaces.Delete(ctx, guid)
-	return err
+// Required parameters (may not be initial): guid
+// Optional parameters (may be initial): owner
+func (c *organizationClient) DeleteSpace(ctx context.Context, guid string, owner facade.OwnerRef) error {
+	c.resourceCache.deleteSpace(guid, owner)
+	if _, err := c.client.Spaces.Delete(ctx, guid); err != nil {
+		return err
+	}
+	publishEvent(events.EventTypeDeleted, events.ResourceTypeSpace, guid, owner.UID, "")
+	return nil
 }
 
-func (c *organizationClient) AddAuditor(ctx context.Context, guid string, username string) error {
-	return nil
+// Required parameters (may not be initial): guid, username
+// Optional parameters (may be initial): origin
+func (c *organizationClient) AddAuditor(ctx context.Context, guid string, username string, origin string) error {
+	return c.addSpaceRole(ctx, guid, username, origin, cfresource.SpaceRoleAuditor)
+}
+
+// Required parameters (may not be initial): guid, username
+// Optional parameters (may be initial): origin
+func (c *organizationClient) AddDeveloper(ctx context.Context, guid string, username string, origin string) error {
+	return c.addSpaceRole(ctx, guid, username, origin, cfresource.SpaceRoleDeveloper)
+}
+
+// Required parameters (may not be initial): guid, username
+// Optional parameters (may be initial): origin
+func (c *organizationClient) AddManager(ctx context.Context, guid string, username string, origin string) error {
+	return c.addSpaceRole(ctx, guid, username, origin, cfresource.SpaceRoleManager)
+}
+
+// Required parameters (may not be initial): guid, username
+// Optional parameters (may be initial): origin
+func (c *organizationClient) RemoveAuditor(ctx context.Context, guid string, username string, origin string) error {
+	return c.removeSpaceRole(ctx, guid, username, origin, cfresource.SpaceRoleAuditor)
+}
+
+// Required parameters (may not be initial): guid, username
+// Optional parameters (may be initial): origin
+func (c *organizationClient) RemoveDeveloper(ctx context.Context, guid string, username string, origin string) error {
+	return c.removeSpaceRole(ctx, guid, username, origin, cfresource.SpaceRoleDeveloper)
+}
+
+// Required parameters (may not be initial): guid, username
+// Optional parameters (may be initial): origin
+func (c *organizationClient) RemoveManager(ctx context.Context, guid string, username string, origin string) error {
+	return c.removeSpaceRole(ctx, guid, username, origin, cfresource.SpaceRoleManager)
 }
 
-func (c *organizationClient) AddDeveloper(ctx context.Context, guid string, username string) error {
-	userListOpts := cfclient.NewUserListOptions()
-	userListOpts.UserNames.EqualTo(username)
-	users, err := c.client.Users.ListAll(ctx, userListOpts)
+func (c *organizationClient) addSpaceRole(ctx context.Context, guid string, username string, origin string, roleType cfresource.SpaceRoleType) error {
+	user, err := c.findUser(ctx, username, origin)
 	if err != nil {
 		return err
 	}
-	if len(users) == 0 {
+	if user == nil {
 		return fmt.Errorf("found no user with name: %s", username)
-	} else if len(users) > 1 {
-		return fmt.Errorf("found multiple users with name: %s (this should not be possible, actually)", username)
 	}
-	user := users[0]
 
-	roleListOpts := cfclient.NewRoleListOptions()
-	roleListOpts.SpaceGUIDs.EqualTo(guid)
-	roleListOpts.UserGUIDs.EqualTo(user.GUID)
-	roleListOpts.Types.EqualTo(cfresource.SpaceRoleDeveloper.String())
-	roles, err := c.client.Roles.ListAll(ctx, roleListOpts)
+	roles, err := c.listSpaceRoles(ctx, guid, user.GUID, roleType)
 	if err != nil {
 		return err
 	}
 	if len(roles) > 0 {
 		return nil
 	}
-	_, err = c.client.Roles.CreateSpaceRole(ctx, guid, user.GUID, cfresource.SpaceRoleDeveloper)
+	_, err = c.client.Roles.CreateSpaceRole(ctx, guid, user.GUID, roleType)
 	return err
 }
 
-func (c *organizationClient) AddManager(ctx context.Context, guid string, username string) error {
+func (c *organizationClient) removeSpaceRole(ctx context.Context, guid string, username string, origin string, roleType cfresource.SpaceRoleType) error {
+	user, err := c.findUser(ctx, username, origin)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		// user is gone, so there is no role left to remove
+		return nil
+	}
+
+	roles, err := c.listSpaceRoles(ctx, guid, user.GUID, roleType)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		// TODO: return jobGUID to enable querying the job deletion status
+		if _, err := c.client.Roles.Delete(ctx, role.GUID); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+func (c *organizationClient) findUser(ctx context.Context, username string, origin string) (*cfresource.User, error) {
+	listOpts := cfclient.NewUserListOptions()
+	listOpts.UserNames.EqualTo(username)
+	if origin != "" {
+		listOpts.Origins.EqualTo(origin)
+	}
+	users, err := c.client.Users.ListAll(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, nil
+	} else if len(users) > 1 {
+		return nil, fmt.Errorf("found multiple users with name: %s (consider specifying the origin)", username)
+	}
+	return users[0], nil
+}
+
+func (c *organizationClient) listSpaceRoles(ctx context.Context, guid string, userGuid string, roleType cfresource.SpaceRoleType) ([]*cfresource.Role, error) {
+	listOpts := cfclient.NewRoleListOptions()
+	listOpts.SpaceGUIDs.EqualTo(guid)
+	listOpts.UserGUIDs.EqualTo(userGuid)
+	listOpts.Types.EqualTo(roleType.String())
+	return c.client.Roles.ListAll(ctx, listOpts)
+}
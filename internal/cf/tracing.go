@@ -0,0 +1,302 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cf
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+
+	"github.com/sap/cf-service-operator/internal/facade"
+	"github.com/sap/cf-service-operator/internal/tracing"
+	cfmetrics "github.com/sap/cf-service-operator/pkg/metrics"
+)
+
+// tracingTransport creates a client span for every request sent to the Cloud Foundry API,
+// and propagates the trace context through the request headers.
+type tracingTransport struct {
+	transport http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := tracing.StartClient(req.Context(), "HTTP "+req.Method,
+		attribute.String("http.request.method", req.Method),
+		attribute.String("server.address", req.URL.Host),
+		attribute.String("url.path", req.URL.Path),
+	)
+	// round trippers must not modify the passed request
+	req = req.Clone(ctx)
+	tracing.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.transport.RoundTrip(req)
+	if err == nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	}
+	tracing.End(span, err)
+	return resp, err
+}
+
+// tracingOrganizationClient creates a span for every call of the wrapped organization client, and attributes the requests
+// sent during the call to the organization (see cfmetrics.WithLabelValues).
+type tracingOrganizationClient struct {
+	client           facade.OrganizationClient
+	organizationName string
+}
+
+func (c *tracingOrganizationClient) start(ctx context.Context, operation string) (context.Context, func(error)) {
+	ctx = cfmetrics.WithLabelValues(ctx, map[string]string{cfmetrics.LabelOrganization: c.organizationName})
+	ctx, span := tracing.Start(ctx, "OrganizationClient."+operation, attribute.String("cf.organization.name", c.organizationName))
+	return ctx, func(err error) { tracing.End(span, err) }
+}
+
+func (c *tracingOrganizationClient) GetSpace(ctx context.Context, owner string) (space *facade.Space, err error) {
+	ctx, end := c.start(ctx, "GetSpace")
+	defer func() { end(err) }()
+	return c.client.GetSpace(ctx, owner)
+}
+
+func (c *tracingOrganizationClient) CreateSpace(ctx context.Context, name string, owner facade.OwnerRef, generation int64) (space *facade.Space, err error) {
+	ctx, end := c.start(ctx, "CreateSpace")
+	defer func() { end(err) }()
+	return c.client.CreateSpace(ctx, name, owner, generation)
+}
+
+func (c *tracingOrganizationClient) UpdateSpace(ctx context.Context, guid string, owner facade.OwnerRef, name string, generation int64) (err error) {
+	ctx, end := c.start(ctx, "UpdateSpace")
+	defer func() { end(err) }()
+	return c.client.UpdateSpace(ctx, guid, owner, name, generation)
+}
+
+func (c *tracingOrganizationClient) DeleteSpace(ctx context.Context, guid string, owner facade.OwnerRef) (err error) {
+	ctx, end := c.start(ctx, "DeleteSpace")
+	defer func() { end(err) }()
+	return c.client.DeleteSpace(ctx, guid, owner)
+}
+
+func (c *tracingOrganizationClient) AddAuditor(ctx context.Context, guid string, username string, origin string) (err error) {
+	ctx, end := c.start(ctx, "AddAuditor")
+	defer func() { end(err) }()
+	return c.client.AddAuditor(ctx, guid, username, origin)
+}
+
+func (c *tracingOrganizationClient) AddDeveloper(ctx context.Context, guid string, username string, origin string) (err error) {
+	ctx, end := c.start(ctx, "AddDeveloper")
+	defer func() { end(err) }()
+	return c.client.AddDeveloper(ctx, guid, username, origin)
+}
+
+func (c *tracingOrganizationClient) AddManager(ctx context.Context, guid string, username string, origin string) (err error) {
+	ctx, end := c.start(ctx, "AddManager")
+	defer func() { end(err) }()
+	return c.client.AddManager(ctx, guid, username, origin)
+}
+
+func (c *tracingOrganizationClient) RemoveAuditor(ctx context.Context, guid string, username string, origin string) (err error) {
+	ctx, end := c.start(ctx, "RemoveAuditor")
+	defer func() { end(err) }()
+	return c.client.RemoveAuditor(ctx, guid, username, origin)
+}
+
+func (c *tracingOrganizationClient) RemoveDeveloper(ctx context.Context, guid string, username string, origin string) (err error) {
+	ctx, end := c.start(ctx, "RemoveDeveloper")
+	defer func() { end(err) }()
+	return c.client.RemoveDeveloper(ctx, guid, username, origin)
+}
+
+func (c *tracingOrganizationClient) RemoveManager(ctx context.Context, guid string, username string, origin string) (err error) {
+	ctx, end := c.start(ctx, "RemoveManager")
+	defer func() { end(err) }()
+	return c.client.RemoveManager(ctx, guid, username, origin)
+}
+
+// tracingSpaceClient creates a span for every call of the wrapped space client, and attributes the requests
+// sent during the call to the space (see cfmetrics.WithLabelValues).
+type tracingSpaceClient struct {
+	client    facade.SpaceClient
+	spaceGuid string
+}
+
+func (c *tracingSpaceClient) start(ctx context.Context, operation string) (context.Context, func(error)) {
+	ctx = cfmetrics.WithLabelValues(ctx, map[string]string{cfmetrics.LabelSpace: c.spaceGuid})
+	ctx, span := tracing.Start(ctx, "SpaceClient."+operation, attribute.String("cf.space.guid", c.spaceGuid))
+	return ctx, func(err error) { tracing.End(span, err) }
+}
+
+func (c *tracingSpaceClient) GetInstance(ctx context.Context, instanceOpts map[string]string) (instance *facade.Instance, err error) {
+	ctx, end := c.start(ctx, "GetInstance")
+	defer func() { end(err) }()
+	return c.client.GetInstance(ctx, instanceOpts)
+}
+
+func (c *tracingSpaceClient) CreateInstance(ctx context.Context, name string, servicePlanGuid string, parameters map[string]interface{}, tags []string, metadata *facade.Metadata, owner facade.OwnerRef, generation int64) (instance *facade.Instance, err error) {
+	ctx, end := c.start(ctx, "CreateInstance")
+	defer func() { end(err) }()
+	return c.client.CreateInstance(ctx, name, servicePlanGuid, parameters, tags, metadata, owner, generation)
+}
+
+func (c *tracingSpaceClient) UpdateInstance(ctx context.Context, guid string, owner facade.OwnerRef, name string, servicePlanGuid string, parameters map[string]interface{}, tags []string, metadata *facade.Metadata, generation int64) (err error) {
+	ctx, end := c.start(ctx, "UpdateInstance")
+	defer func() { end(err) }()
+	return c.client.UpdateInstance(ctx, guid, owner, name, servicePlanGuid, parameters, tags, metadata, generation)
+}
+
+func (c *tracingSpaceClient) UpgradeInstance(ctx context.Context, guid string, owner facade.OwnerRef, maintenanceInfo facade.MaintenanceInfo) (err error) {
+	ctx, end := c.start(ctx, "UpgradeInstance")
+	defer func() { end(err) }()
+	return c.client.UpgradeInstance(ctx, guid, owner, maintenanceInfo)
+}
+
+func (c *tracingSpaceClient) DeleteInstance(ctx context.Context, guid string, owner facade.OwnerRef) (err error) {
+	ctx, end := c.start(ctx, "DeleteInstance")
+	defer func() { end(err) }()
+	return c.client.DeleteInstance(ctx, guid, owner)
+}
+
+func (c *tracingSpaceClient) ListInstances(ctx context.Context) (instances []*facade.Instance, err error) {
+	ctx, end := c.start(ctx, "ListInstances")
+	defer func() { end(err) }()
+	return c.client.ListInstances(ctx)
+}
+
+func (c *tracingSpaceClient) FindInstanceName(ctx context.Context, name string) (instance *facade.InstanceName, err error) {
+	ctx, end := c.start(ctx, "FindInstanceName")
+	defer func() { end(err) }()
+	return c.client.FindInstanceName(ctx, name)
+}
+
+func (c *tracingSpaceClient) GetInstanceParameters(ctx context.Context, guid string) (parameters map[string]interface{}, err error) {
+	ctx, end := c.start(ctx, "GetInstanceParameters")
+	defer func() { end(err) }()
+	return c.client.GetInstanceParameters(ctx, guid)
+}
+
+func (c *tracingSpaceClient) GetBinding(ctx context.Context, bindingOpts map[string]string) (binding *facade.Binding, err error) {
+	ctx, end := c.start(ctx, "GetBinding")
+	defer func() { end(err) }()
+	return c.client.GetBinding(ctx, bindingOpts)
+}
+
+func (c *tracingSpaceClient) GetBindingCredentials(ctx context.Context, guid string) (credentials map[string]interface{}, err error) {
+	ctx, end := c.start(ctx, "GetBindingCredentials")
+	defer func() { end(err) }()
+	return c.client.GetBindingCredentials(ctx, guid)
+}
+
+func (c *tracingSpaceClient) CreateBinding(ctx context.Context, name string, serviceInstanceGuid string, appGuid string, parameters map[string]interface{}, metadata *facade.Metadata, owner facade.OwnerRef, generation int64) (binding *facade.Binding, err error) {
+	ctx, end := c.start(ctx, "CreateBinding")
+	defer func() { end(err) }()
+	return c.client.CreateBinding(ctx, name, serviceInstanceGuid, appGuid, parameters, metadata, owner, generation)
+}
+
+func (c *tracingSpaceClient) UpdateBinding(ctx context.Context, guid string, owner facade.OwnerRef, generation int64, parameters map[string]interface{}, metadata *facade.Metadata) (err error) {
+	ctx, end := c.start(ctx, "UpdateBinding")
+	defer func() { end(err) }()
+	return c.client.UpdateBinding(ctx, guid, owner, generation, parameters, metadata)
+}
+
+func (c *tracingSpaceClient) DeleteBinding(ctx context.Context, guid string, owner facade.OwnerRef) (err error) {
+	ctx, end := c.start(ctx, "DeleteBinding")
+	defer func() { end(err) }()
+	return c.client.DeleteBinding(ctx, guid, owner)
+}
+
+func (c *tracingSpaceClient) RefreshCache(ctx context.Context) (err error) {
+	ctx, end := c.start(ctx, "RefreshCache")
+	defer func() { end(err) }()
+	return c.client.RefreshCache(ctx)
+}
+
+func (c *tracingSpaceClient) PromoteBinding(ctx context.Context, guid string, owner facade.OwnerRef) (err error) {
+	ctx, end := c.start(ctx, "PromoteBinding")
+	defer func() { end(err) }()
+	return c.client.PromoteBinding(ctx, guid, owner)
+}
+
+func (c *tracingSpaceClient) ListBindings(ctx context.Context) (bindings []*facade.Binding, err error) {
+	ctx, end := c.start(ctx, "ListBindings")
+	defer func() { end(err) }()
+	return c.client.ListBindings(ctx)
+}
+
+func (c *tracingSpaceClient) FindServicePlan(ctx context.Context, serviceOfferingName string, servicePlanName string, spaceGuid string) (guid string, err error) {
+	ctx, end := c.start(ctx, "FindServicePlan")
+	defer func() { end(err) }()
+	return c.client.FindServicePlan(ctx, serviceOfferingName, servicePlanName, spaceGuid)
+}
+
+func (c *tracingSpaceClient) ListServicePlans(ctx context.Context, spaceGuid string) (servicePlans []facade.ServicePlan, err error) {
+	ctx, end := c.start(ctx, "ListServicePlans")
+	defer func() { end(err) }()
+	return c.client.ListServicePlans(ctx, spaceGuid)
+}
+
+func (c *tracingSpaceClient) GetServicePlanSchemas(ctx context.Context, servicePlanGuid string, spaceGuid string) (schemas *facade.ServicePlanSchemas, err error) {
+	ctx, end := c.start(ctx, "GetServicePlanSchemas")
+	defer func() { end(err) }()
+	return c.client.GetServicePlanSchemas(ctx, servicePlanGuid, spaceGuid)
+}
+
+func (c *tracingSpaceClient) FindApp(ctx context.Context, name string) (guid string, err error) {
+	ctx, end := c.start(ctx, "FindApp")
+	defer func() { end(err) }()
+	return c.client.FindApp(ctx, name)
+}
+
+func (c *tracingSpaceClient) FindDomain(ctx context.Context, name string) (guid string, err error) {
+	ctx, end := c.start(ctx, "FindDomain")
+	defer func() { end(err) }()
+	return c.client.FindDomain(ctx, name)
+}
+
+func (c *tracingSpaceClient) GetRoute(ctx context.Context, owner string) (route *facade.Route, err error) {
+	ctx, end := c.start(ctx, "GetRoute")
+	defer func() { end(err) }()
+	return c.client.GetRoute(ctx, owner)
+}
+
+func (c *tracingSpaceClient) CreateRoute(ctx context.Context, domainGuid string, host string, path string, owner string, generation int64) (err error) {
+	ctx, end := c.start(ctx, "CreateRoute")
+	defer func() { end(err) }()
+	return c.client.CreateRoute(ctx, domainGuid, host, path, owner, generation)
+}
+
+func (c *tracingSpaceClient) UpdateRoute(ctx context.Context, guid string, generation int64) (err error) {
+	ctx, end := c.start(ctx, "UpdateRoute")
+	defer func() { end(err) }()
+	return c.client.UpdateRoute(ctx, guid, generation)
+}
+
+func (c *tracingSpaceClient) DeleteRoute(ctx context.Context, guid string) (err error) {
+	ctx, end := c.start(ctx, "DeleteRoute")
+	defer func() { end(err) }()
+	return c.client.DeleteRoute(ctx, guid)
+}
+
+func (c *tracingSpaceClient) GetRouteBinding(ctx context.Context, owner string) (routeBinding *facade.RouteBinding, err error) {
+	ctx, end := c.start(ctx, "GetRouteBinding")
+	defer func() { end(err) }()
+	return c.client.GetRouteBinding(ctx, owner)
+}
+
+func (c *tracingSpaceClient) CreateRouteBinding(ctx context.Context, routeGuid string, serviceInstanceGuid string, parameters map[string]interface{}, owner string, generation int64) (err error) {
+	ctx, end := c.start(ctx, "CreateRouteBinding")
+	defer func() { end(err) }()
+	return c.client.CreateRouteBinding(ctx, routeGuid, serviceInstanceGuid, parameters, owner, generation)
+}
+
+func (c *tracingSpaceClient) UpdateRouteBinding(ctx context.Context, guid string, generation int64) (err error) {
+	ctx, end := c.start(ctx, "UpdateRouteBinding")
+	defer func() { end(err) }()
+	return c.client.UpdateRouteBinding(ctx, guid, generation)
+}
+
+func (c *tracingSpaceClient) DeleteRouteBinding(ctx context.Context, guid string) (err error) {
+	ctx, end := c.start(ctx, "DeleteRouteBinding")
+	defer func() { end(err) }()
+	return c.client.DeleteRouteBinding(ctx, guid)
+}
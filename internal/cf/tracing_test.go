@@ -0,0 +1,80 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package cf
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/sap/cf-service-operator/internal/facade"
+	"github.com/sap/cf-service-operator/internal/facade/facadefakes"
+	"github.com/sap/cf-service-operator/internal/tracing"
+)
+
+var _ = Describe("Tracing tests", func() {
+	var recorder *tracetest.SpanRecorder
+
+	BeforeEach(func() {
+		recorder = tracetest.NewSpanRecorder()
+		tracerProvider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		DeferCleanup(func() {
+			otel.SetTracerProvider(tracerProvider)
+			otel.SetTextMapPropagator(propagator)
+		})
+	})
+
+	It("should create a span for every space client call", func() {
+		fakeClient := &facadefakes.FakeSpaceClient{}
+		fakeClient.DeleteInstanceReturns(errors.New("instance is in use"))
+		client := &tracingSpaceClient{client: fakeClient, spaceGuid: "space-guid"}
+
+		ctx, parent := tracing.Start(context.Background(), "Reconcile")
+		Expect(client.DeleteInstance(ctx, "instance-guid", facade.OwnerRef{})).To(MatchError("instance is in use"))
+		parent.End()
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Name()).To(Equal("SpaceClient.DeleteInstance"))
+		Expect(spans[0].Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+		Expect(spans[0].Status().Code).To(Equal(codes.Error))
+		passedCtx, _, _ := fakeClient.DeleteInstanceArgsForCall(0)
+		Expect(passedCtx).NotTo(Equal(ctx))
+	})
+
+	It("should propagate the trace context to the Cloud Foundry API", func() {
+		server := ghttp.NewServer()
+		defer server.Close()
+		server.AppendHandlers(ghttp.RespondWith(http.StatusOK, "{}"))
+		client := &http.Client{Transport: &tracingTransport{transport: http.DefaultTransport}}
+
+		ctx, parent := tracing.Start(context.Background(), "Reconcile")
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL()+"/v3/spaces", nil)
+		Expect(err).ToNot(HaveOccurred())
+		resp, err := client.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		parent.End()
+
+		Expect(req.Header.Get("traceparent")).To(BeEmpty())
+		traceparent := server.ReceivedRequests()[0].Header.Get("traceparent")
+		Expect(traceparent).To(ContainSubstring(parent.SpanContext().TraceID().String()))
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Name()).To(Equal("HTTP GET"))
+		Expect(traceparent).To(ContainSubstring(spans[0].SpanContext().SpanID().String()))
+	})
+})
@@ -0,0 +1,91 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cf
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sap/cf-service-operator/internal/cache"
+)
+
+// conditionalTransport adds conditional request support (ETag/If-None-Match) to GET requests.
+// Responses carrying an ETag are remembered; subsequent requests for the same URL are sent with If-None-Match,
+// and if the server answers with 304 Not Modified, the remembered response is returned instead (as 200 OK).
+// Endpoints not returning an ETag are passed through unchanged.
+// Since every CF client (i.e. every set of credentials) uses its own transport, responses are never shared across users.
+type conditionalTransport struct {
+	transport http.RoundTripper
+	responses *cache.Cache[cachedResponse]
+}
+
+type cachedResponse struct {
+	etag   string
+	header http.Header
+	body   []byte
+}
+
+func newConditionalTransport(transport http.RoundTripper, ttl time.Duration) *conditionalTransport {
+	return &conditionalTransport{
+		transport: transport,
+		responses: cache.New[cachedResponse](ttl),
+	}
+}
+
+func (t *conditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" || req.Header.Get("Range") != "" {
+		return t.transport.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	cached, isCached := t.responses.Get(key)
+	if isCached {
+		// round trippers must not modify the passed request
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if isCached && resp.StatusCode == http.StatusNotModified {
+		// drain body to allow re-use of the connection
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         resp.Proto,
+			ProtoMajor:    resp.ProtoMajor,
+			ProtoMinor:    resp.ProtoMinor,
+			Header:        cached.header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(cached.body)),
+			ContentLength: int64(len(cached.body)),
+			Request:       req,
+		}, nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || etag == "" {
+		if isCached {
+			t.responses.Delete(key)
+		}
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	t.responses.Set(key, cachedResponse{etag: etag, header: resp.Header.Clone(), body: body})
+	return resp, nil
+}
@@ -0,0 +1,99 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package cf
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Conditional transport tests", func() {
+	var server *ghttp.Server
+	var client *http.Client
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		client = &http.Client{Transport: newConditionalTransport(http.DefaultTransport, time.Minute)}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	get := func(path string) (int, string) {
+		resp, err := client.Get(server.URL() + path)
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		return resp.StatusCode, string(body)
+	}
+
+	It("should re-use the remembered response if not modified", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v3/service_offerings"),
+				ghttp.RespondWith(http.StatusOK, "catalog", http.Header{"ETag": []string{`"v1"`}}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v3/service_offerings"),
+				ghttp.VerifyHeaderKV("If-None-Match", `"v1"`),
+				ghttp.RespondWith(http.StatusNotModified, nil),
+			),
+		)
+
+		code, body := get("/v3/service_offerings")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(Equal("catalog"))
+
+		code, body = get("/v3/service_offerings")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(Equal("catalog"))
+		Expect(server.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("should replace the remembered response if modified", func() {
+		server.AppendHandlers(
+			ghttp.RespondWith(http.StatusOK, "old", http.Header{"ETag": []string{`"v1"`}}),
+			ghttp.CombineHandlers(
+				ghttp.VerifyHeaderKV("If-None-Match", `"v1"`),
+				ghttp.RespondWith(http.StatusOK, "new", http.Header{"ETag": []string{`"v2"`}}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyHeaderKV("If-None-Match", `"v2"`),
+				ghttp.RespondWith(http.StatusNotModified, nil),
+			),
+		)
+
+		_, body := get("/v3/spaces")
+		Expect(body).To(Equal("old"))
+		_, body = get("/v3/spaces")
+		Expect(body).To(Equal("new"))
+		_, body = get("/v3/spaces")
+		Expect(body).To(Equal("new"))
+	})
+
+	It("should pass through responses without ETag", func() {
+		server.AppendHandlers(
+			ghttp.RespondWith(http.StatusOK, "first"),
+			ghttp.CombineHandlers(
+				func(w http.ResponseWriter, req *http.Request) {
+					Expect(req.Header.Get("If-None-Match")).To(BeEmpty())
+				},
+				ghttp.RespondWith(http.StatusOK, "second"),
+			),
+		)
+
+		_, body := get("/v3/spaces")
+		Expect(body).To(Equal("first"))
+		_, body = get("/v3/spaces")
+		Expect(body).To(Equal("second"))
+	})
+})
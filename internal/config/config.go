@@ -0,0 +1,594 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package config contains the operator-wide configuration, which is loaded from an optional configuration file and
+from environment variables (taking precedence over the configuration file).
+*/
+package config
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+
+	cfmetrics "github.com/sap/cf-service-operator/pkg/metrics"
+)
+
+// Config holds the operator-wide configuration.
+// Fields are read from the configuration file by their json name, and from the environment variable named by their env tag.
+// Fields tagged with reload:"true" may be changed at runtime by editing the configuration file (see Watcher).
+type Config struct {
+	// Namespace for secrets in which cluster-scoped resources are found.
+	ClusterResourceNamespace string `json:"clusterResourceNamespace,omitempty" env:"CLUSTER_RESOURCE_NAMESPACE"`
+
+	// Whether to enhance binding secrets by SAP binding metadata by default.
+	EnableBindingMetadata bool `json:"sapBindingMetadata,omitempty" env:"SAP_BINDING_METADATA"`
+
+	// Maximum duration of a single reconcile call; zero means no timeout.
+	ReconcileTimeout metav1.Duration `json:"reconcileTimeout,omitempty" env:"RECONCILE_TIMEOUT"`
+
+	// Default intervals in which ready objects are polled, by kind (such as ServiceInstance); overridden per space by spec.configOverrides,
+	// and per object by the annotation service-operator.cf.cs.sap.com/polling-interval-ready.
+	PollingIntervalsReady map[string]metav1.Duration `json:"pollingIntervalsReady,omitempty" env:"POLLING_INTERVALS_READY" reload:"true"`
+
+	// Default intervals in which failed objects (which exceeded their maximum number of retries) are polled, by kind; overridden per space
+	// by spec.configOverrides, and per object by the annotation service-operator.cf.cs.sap.com/polling-interval-fail.
+	PollingIntervalsFail map[string]metav1.Duration `json:"pollingIntervalsFail,omitempty" env:"POLLING_INTERVALS_FAIL" reload:"true"`
+
+	// Time after which an operation on a Cloud Foundry instance which is still in progress is considered stalled;
+	// overridden per object by the annotation service-operator.cf.cs.sap.com/stalled-operation-timeout; zero disables the detection.
+	StalledOperationTimeout metav1.Duration `json:"stalledOperationTimeout,omitempty" env:"STALLED_OPERATION_TIMEOUT"`
+
+	// Whether Cloud Foundry resources (spaces, service instances, service bindings) are cached in memory.
+	IsResourceCacheEnabled bool `json:"resourceCacheEnabled,omitempty" env:"RESOURCE_CACHE_ENABLED" reload:"true"`
+
+	// Time after which cached Cloud Foundry resources expire.
+	CacheTimeOut metav1.Duration `json:"resourceCacheTimeout,omitempty" env:"RESOURCE_CACHE_TIMEOUT" reload:"true"`
+
+	// Time after which cached Cloud Foundry resources of a single space expire, overriding CacheTimeOut for that space;
+	// not read from the configuration file, but set per space (see WithSpaceCacheTimeout).
+	SpaceCacheTimeout metav1.Duration `json:"-"`
+
+	// Time for which the service catalog (service offerings and plans) of a space is cached in memory; zero disables the catalog cache.
+	CatalogCacheTimeout metav1.Duration `json:"catalogCacheTimeout,omitempty" env:"CATALOG_CACHE_TIMEOUT"`
+
+	// Time after which cached Cloud Foundry clients (together with their resource and catalog caches) which were not used are evicted;
+	// zero disables the eviction of idle clients.
+	ClientCacheTimeout metav1.Duration `json:"clientCacheTimeout,omitempty" env:"CLIENT_CACHE_TIMEOUT"`
+
+	// Maximum number of cached Cloud Foundry clients (one per API endpoint and user); if exceeded, the least recently used
+	// clients are evicted. Zero means no limit.
+	ClientCacheMaxEntries int `json:"clientCacheMaxEntries,omitempty" env:"CLIENT_CACHE_MAX_ENTRIES"`
+
+	// Whether GET requests against the Cloud Foundry API are sent as conditional requests (If-None-Match),
+	// re-using remembered responses if the server reports them as unchanged.
+	EnableConditionalRequests bool `json:"conditionalRequests,omitempty" env:"CONDITIONAL_REQUESTS"`
+
+	// Maximum number of requests per second sent to a Cloud Foundry API endpoint; zero means no limit.
+	MaxRequestsPerSecond float64 `json:"maxRequestsPerSecond,omitempty" env:"CF_MAX_REQUESTS_PER_SECOND" reload:"true"`
+
+	// Number of requests which may exceed MaxRequestsPerSecond in a short burst.
+	Burst int `json:"burst,omitempty" env:"CF_BURST" reload:"true"`
+
+	// Maximum number of retries of a request answered with 429 Too Many Requests.
+	MaxRetriesOnTooManyRequests int `json:"maxRetriesOnTooManyRequests,omitempty" env:"CF_MAX_RETRIES_ON_TOO_MANY_REQUESTS" reload:"true"`
+
+	// Rate limits overriding MaxRequestsPerSecond and Burst for specific Cloud Foundry API endpoints, by API URL.
+	EndpointRateLimits map[string]RateLimit `json:"endpointRateLimits,omitempty" reload:"true"`
+
+	// Number of results requested per page when listing resources (such as service instances and bindings) in Cloud Foundry;
+	// zero means the default page size of the Cloud Foundry client (50); at most 5000.
+	ListPageSize int `json:"listPageSize,omitempty" env:"CF_LIST_PAGE_SIZE"`
+
+	// Maximum number of pages of a list request read in parallel, once the first page told the total number of pages;
+	// zero or one means that pages are read one after another.
+	ListParallelism int `json:"listParallelism,omitempty" env:"CF_LIST_PARALLELISM"`
+
+	// Number of consecutive server (5xx) or connection errors after which requests to a Cloud Foundry API endpoint are suspended;
+	// zero disables the circuit breaker.
+	CircuitBreakerThreshold int `json:"circuitBreakerThreshold,omitempty" env:"CF_CIRCUIT_BREAKER_THRESHOLD"`
+
+	// Time for which requests to a Cloud Foundry API endpoint are suspended once the circuit breaker opened.
+	CircuitBreakerTimeout metav1.Duration `json:"circuitBreakerTimeout,omitempty" env:"CF_CIRCUIT_BREAKER_TIMEOUT"`
+
+	// PEM encoded CA certificates trusted (in addition to the system roots) when connecting to Cloud Foundry API endpoints;
+	// may be overridden per space by the key ca.crt of the space secret.
+	CABundle string `json:"caBundle,omitempty" env:"CF_CA_BUNDLE"`
+
+	// URL of a proxy used to connect to Cloud Foundry API endpoints; if empty, the standard proxy environment variables apply;
+	// may be overridden per space by the key proxy of the space secret.
+	Proxy string `json:"proxy,omitempty" env:"CF_PROXY"`
+
+	// Interval in which the ServiceOperatorReport object is refreshed; zero disables the report.
+	ReportInterval metav1.Duration `json:"reportInterval,omitempty" env:"REPORT_INTERVAL"`
+
+	// Whether the validating webhook checks that service offerings and plans referenced (by name) by new service instances
+	// exist in Cloud Foundry; should be disabled if the Cloud Foundry API is not reachable when objects are created.
+	CatalogValidation bool `json:"catalogValidation,omitempty" env:"CATALOG_VALIDATION"`
+
+	// Labels of ServiceBinding and ServiceInstance objects which are copied to the generated binding secrets (binding labels taking precedence);
+	// entries are label keys, or key prefixes ending with '*'.
+	SecretLabels []string `json:"secretLabels,omitempty" env:"SECRET_LABELS"`
+
+	// Propagation policy used when deleting binding secrets (Foreground, Background or Orphan);
+	// Foreground deletion is skipped for secrets without dependents.
+	SecretDeletionPropagation metav1.DeletionPropagation `json:"secretDeletionPropagation,omitempty" env:"SECRET_DELETION_PROPAGATION"`
+
+	// Time for which an immutable binding secret is retained after it was superseded by a binding secret with new content (spec.secretImmutable),
+	// such that consumers can follow status.secretName; zero deletes superseded secrets immediately.
+	SupersededSecretGracePeriod metav1.Duration `json:"supersededSecretGracePeriod,omitempty" env:"SUPERSEDED_SECRET_GRACE_PERIOD"`
+
+	// Namespaces into which ServiceBinding objects of other namespaces may write their binding secret (spec.secretNamespace).
+	SecretNamespaces []string `json:"secretNamespaces,omitempty" env:"SECRET_NAMESPACES"`
+
+	// Namespaces from which Space objects of other namespaces may read their credentials (spec.authSecretRef).
+	CredentialNamespaces []string `json:"credentialNamespaces,omitempty" env:"CREDENTIAL_NAMESPACES"`
+
+	// Go template from which the binding secret name (spec.secretName) of ServiceBinding objects is defaulted, instead of metadata.name;
+	// it is rendered with the ServiceBinding object, such as {{ .Spec.ServiceInstanceName }}-{{ .Name }}-creds.
+	BindingSecretNameTemplate string `json:"bindingSecretNameTemplate,omitempty" env:"BINDING_SECRET_NAME_TEMPLATE"`
+
+	// Interval in which the managed Cloud Foundry spaces are scanned for orphaned service instances and bindings
+	// (carrying the owner label, but lacking the owning object); zero disables the scan.
+	OrphanScanInterval metav1.Duration `json:"orphanScanInterval,omitempty" env:"ORPHAN_SCAN_INTERVAL"`
+
+	// How orphaned service instances and bindings are handled (Report or Delete).
+	OrphanPolicy OrphanPolicy `json:"orphanPolicy,omitempty" env:"ORPHAN_POLICY"`
+
+	// Identifier of this cluster (or operator installation), recorded in the owner-cluster label of the Cloud Foundry service instances
+	// and bindings created or updated by the operator; orphaned resources (and leftovers of cascading space deletions) are only deleted
+	// if their owner-cluster label matches (resources without that label only match if no identifier is configured).
+	ClusterID string `json:"clusterId,omitempty" env:"CLUSTER_ID"`
+
+	// Whether all objects are reconciled in observe-only mode, that is, Cloud Foundry resources are only read (and reflected in the status),
+	// but never created, updated or deleted, and no finalizers are added.
+	ObserveOnly bool `json:"observeOnly,omitempty" env:"OBSERVE_ONLY"`
+
+	// Maximum number of concurrent reconciles per controller (at most MaxConcurrentReconcilesLimit).
+	MaxConcurrentReconciles int `json:"maxConcurrentReconciles,omitempty" env:"MAX_CONCURRENT_RECONCILES" reload:"true"`
+
+	// Maximum number of concurrent reconciles per controller for objects targeting the same Cloud Foundry API endpoint
+	// (as reported in their status), such that a slow or rate-limited endpoint cannot occupy all reconciles of a controller;
+	// reconciles exceeding the limit are deferred. Zero means no limit per endpoint.
+	MaxConcurrentReconcilesPerEndpoint int `json:"maxConcurrentReconcilesPerEndpoint,omitempty" env:"MAX_CONCURRENT_RECONCILES_PER_ENDPOINT" reload:"true"`
+
+	// Namespaces whose objects are reconciled; if empty, objects of all namespaces are reconciled.
+	WatchNamespaces []string `json:"watchNamespaces,omitempty" env:"WATCH_NAMESPACES"`
+
+	// Namespaces whose objects are not reconciled (taking precedence over WatchNamespaces).
+	IgnoreNamespaces []string `json:"ignoreNamespaces,omitempty" env:"IGNORE_NAMESPACES"`
+
+	// Label selector restricting the namespaces whose objects are reconciled.
+	NamespaceLabelSelector string `json:"namespaceLabelSelector,omitempty" env:"NAMESPACE_LABEL_SELECTOR"`
+
+	// Number of shards the namespaces are distributed to (by consistent hashing on the namespace name),
+	// such that multiple operator deployments can split the load; zero or one disables sharding.
+	ShardCount int `json:"shardCount,omitempty" env:"SHARD_COUNT"`
+
+	// Shard (0 to ShardCount-1) whose namespaces are reconciled by this operator deployment.
+	ShardIndex int `json:"shardIndex,omitempty" env:"SHARD_INDEX"`
+
+	// Whether cluster-scoped objects (ClusterSpace, ClusterServiceBinding) are reconciled; if sharding is enabled, they are only reconciled by shard 0.
+	ReconcileClusterSpaces bool `json:"reconcileClusterSpaces,omitempty" env:"RECONCILE_CLUSTER_SPACES"`
+
+	// URL of an OTLP/HTTP endpoint (such as http://otel-collector:4318) to which traces of reconciles and Cloud Foundry API calls are exported;
+	// if empty, tracing is disabled.
+	TracingEndpoint string `json:"tracingEndpoint,omitempty" env:"TRACING_ENDPOINT"`
+
+	// Fraction (between 0 and 1) of reconciles which are traced.
+	TracingSampleRate float64 `json:"tracingSampleRate,omitempty" env:"TRACING_SAMPLE_RATE"`
+
+	// Service name reported with exported traces.
+	TracingServiceName string `json:"tracingServiceName,omitempty" env:"TRACING_SERVICE_NAME"`
+
+	// Additional labels (organization, space, controller) of the metrics of requests against the Cloud Foundry API,
+	// attributing the requests to tenants; disabled by default, since every label multiplies the number of time series.
+	CFMetricsLabels []string `json:"cfMetricsLabels,omitempty" env:"CF_METRICS_LABELS"`
+}
+
+// OrphanPolicy defines how orphaned Cloud Foundry resources are handled.
+type OrphanPolicy string
+
+const (
+	// Orphaned resources are reported through metrics and events.
+	OrphanPolicyReport OrphanPolicy = "Report"
+	// Orphaned resources are reported, and deleted if still orphaned in the next scan.
+	OrphanPolicyDelete OrphanPolicy = "Delete"
+)
+
+// RateLimit limits the requests sent to a Cloud Foundry API endpoint.
+type RateLimit struct {
+	// Maximum number of requests per second; zero means no limit.
+	MaxRequestsPerSecond float64 `json:"maxRequestsPerSecond,omitempty"`
+	// Number of requests which may exceed MaxRequestsPerSecond in a short burst.
+	Burst int `json:"burst,omitempty"`
+}
+
+const (
+	defaultReconcileTimeout        = 5 * time.Minute
+	defaultCacheTimeOut            = 5 * time.Minute
+	defaultCatalogCacheTimeout     = 10 * time.Minute
+	defaultClientCacheTimeout      = 1 * time.Hour
+	defaultBurst                   = 10
+	defaultMaxRetries              = 3
+	defaultReportInterval          = 5 * time.Minute
+	defaultSecretGracePeriod       = 5 * time.Minute
+	defaultCircuitBreakerThreshold = 5
+	defaultCircuitBreakerTimeout   = 1 * time.Minute
+	defaultMaxConcurrentReconciles = 1
+	defaultTracingSampleRate       = 1
+	defaultTracingServiceName      = "cf-service-operator"
+)
+
+// PollingKinds are the kinds for which default polling intervals may be configured.
+var PollingKinds = []string{"Space", "ClusterSpace", "ServiceInstance", "ServiceBinding", "ClusterServiceBinding", "Route", "RouteBinding"}
+
+// MaxConcurrentReconcilesLimit is the upper bound for MaxConcurrentReconciles; controllers start this many workers,
+// such that the effective concurrency can be raised at runtime.
+const MaxConcurrentReconcilesLimit = 50
+
+// Defaults returns a configuration with all default values set.
+func Defaults() *Config {
+	return &Config{
+		ReconcileTimeout:            metav1.Duration{Duration: defaultReconcileTimeout},
+		CacheTimeOut:                metav1.Duration{Duration: defaultCacheTimeOut},
+		CatalogCacheTimeout:         metav1.Duration{Duration: defaultCatalogCacheTimeout},
+		ClientCacheTimeout:          metav1.Duration{Duration: defaultClientCacheTimeout},
+		Burst:                       defaultBurst,
+		MaxRetriesOnTooManyRequests: defaultMaxRetries,
+		ReportInterval:              metav1.Duration{Duration: defaultReportInterval},
+		CircuitBreakerThreshold:     defaultCircuitBreakerThreshold,
+		CircuitBreakerTimeout:       metav1.Duration{Duration: defaultCircuitBreakerTimeout},
+		SecretDeletionPropagation:   metav1.DeletePropagationForeground,
+		SupersededSecretGracePeriod: metav1.Duration{Duration: defaultSecretGracePeriod},
+		OrphanPolicy:                OrphanPolicyReport,
+		CatalogValidation:           true,
+		MaxConcurrentReconciles:     defaultMaxConcurrentReconciles,
+		ReconcileClusterSpaces:      true,
+		TracingSampleRate:           defaultTracingSampleRate,
+		TracingServiceName:          defaultTracingServiceName,
+	}
+}
+
+// Load returns the configuration read from the file at path (if path is not empty), overridden by environment variables.
+// The result is validated; unknown fields in the configuration file are rejected.
+func Load(path string) (*Config, error) {
+	return load(path, os.LookupEnv)
+}
+
+func load(path string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	cfg := Defaults()
+
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading configuration file %s", path)
+		}
+		if err := yaml.UnmarshalStrict(raw, cfg); err != nil {
+			return nil, errors.Wrapf(err, "error parsing configuration file %s", path)
+		}
+	}
+
+	if err := loadEnv(cfg, lookupEnv); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Validate checks the configuration for consistency.
+func (c *Config) Validate() error {
+	if c.ReconcileTimeout.Duration < 0 {
+		return fmt.Errorf("invalid reconcile timeout %s: must not be negative", c.ReconcileTimeout.Duration)
+	}
+	if err := validatePollingIntervals(c.PollingIntervalsReady); err != nil {
+		return errors.Wrap(err, "invalid ready polling intervals")
+	}
+	if err := validatePollingIntervals(c.PollingIntervalsFail); err != nil {
+		return errors.Wrap(err, "invalid fail polling intervals")
+	}
+	if c.StalledOperationTimeout.Duration < 0 {
+		return fmt.Errorf("invalid stalled operation timeout %s: must not be negative", c.StalledOperationTimeout.Duration)
+	}
+	if (c.IsResourceCacheEnabled || c.EnableConditionalRequests) && c.CacheTimeOut.Duration <= 0 {
+		return fmt.Errorf("invalid resource cache timeout %s: must be positive if the resource cache or conditional requests are enabled", c.CacheTimeOut.Duration)
+	}
+	if c.CatalogCacheTimeout.Duration < 0 {
+		return fmt.Errorf("invalid catalog cache timeout %s: must not be negative", c.CatalogCacheTimeout.Duration)
+	}
+	if c.ClientCacheTimeout.Duration < 0 {
+		return fmt.Errorf("invalid client cache timeout %s: must not be negative", c.ClientCacheTimeout.Duration)
+	}
+	if c.ClientCacheMaxEntries < 0 {
+		return fmt.Errorf("invalid maximum number of cached clients %d: must not be negative", c.ClientCacheMaxEntries)
+	}
+	if c.MaxRequestsPerSecond < 0 || c.Burst < 0 {
+		return fmt.Errorf("invalid rate limit: maxRequestsPerSecond and burst must not be negative")
+	}
+	for url, limit := range c.EndpointRateLimits {
+		if limit.MaxRequestsPerSecond < 0 || limit.Burst < 0 {
+			return fmt.Errorf("invalid rate limit for endpoint %s: maxRequestsPerSecond and burst must not be negative", url)
+		}
+	}
+	if c.MaxRetriesOnTooManyRequests < 0 {
+		return fmt.Errorf("invalid number of retries %d: must not be negative", c.MaxRetriesOnTooManyRequests)
+	}
+	if c.ListPageSize < 0 || c.ListPageSize > 5000 {
+		return fmt.Errorf("invalid list page size %d: must be between 0 and 5000", c.ListPageSize)
+	}
+	if c.ListParallelism < 0 {
+		return fmt.Errorf("invalid list parallelism %d: must not be negative", c.ListParallelism)
+	}
+	if c.CircuitBreakerThreshold < 0 {
+		return fmt.Errorf("invalid circuit breaker threshold %d: must not be negative", c.CircuitBreakerThreshold)
+	}
+	if c.CircuitBreakerThreshold > 0 && c.CircuitBreakerTimeout.Duration <= 0 {
+		return fmt.Errorf("invalid circuit breaker timeout %s: must be positive if the circuit breaker is enabled", c.CircuitBreakerTimeout.Duration)
+	}
+	if err := validateConnection(c.CABundle, c.Proxy); err != nil {
+		return err
+	}
+	if c.ReportInterval.Duration < 0 {
+		return fmt.Errorf("invalid report interval %s: must not be negative", c.ReportInterval.Duration)
+	}
+	switch c.SecretDeletionPropagation {
+	case metav1.DeletePropagationForeground, metav1.DeletePropagationBackground, metav1.DeletePropagationOrphan:
+	default:
+		return fmt.Errorf("invalid secret deletion propagation %q: must be one of Foreground, Background, Orphan", c.SecretDeletionPropagation)
+	}
+	if c.SupersededSecretGracePeriod.Duration < 0 {
+		return fmt.Errorf("invalid superseded secret grace period %s: must not be negative", c.SupersededSecretGracePeriod.Duration)
+	}
+	if _, err := c.GetBindingSecretNameTemplate(); err != nil {
+		return err
+	}
+	if c.OrphanScanInterval.Duration < 0 {
+		return fmt.Errorf("invalid orphan scan interval %s: must not be negative", c.OrphanScanInterval.Duration)
+	}
+	switch c.OrphanPolicy {
+	case OrphanPolicyReport, OrphanPolicyDelete:
+	default:
+		return fmt.Errorf("invalid orphan policy %q: must be one of Report, Delete", c.OrphanPolicy)
+	}
+	if errs := validation.IsValidLabelValue(c.ClusterID); len(errs) > 0 {
+		return fmt.Errorf("invalid cluster id %q: %s", c.ClusterID, strings.Join(errs, "; "))
+	}
+	if c.MaxConcurrentReconciles < 1 || c.MaxConcurrentReconciles > MaxConcurrentReconcilesLimit {
+		return fmt.Errorf("invalid number of concurrent reconciles %d: must be between 1 and %d", c.MaxConcurrentReconciles, MaxConcurrentReconcilesLimit)
+	}
+	if c.MaxConcurrentReconcilesPerEndpoint < 0 || c.MaxConcurrentReconcilesPerEndpoint > MaxConcurrentReconcilesLimit {
+		return fmt.Errorf("invalid number of concurrent reconciles per endpoint %d: must be between 0 and %d", c.MaxConcurrentReconcilesPerEndpoint, MaxConcurrentReconcilesLimit)
+	}
+	if _, err := labels.Parse(c.NamespaceLabelSelector); err != nil {
+		return errors.Wrapf(err, "invalid namespace label selector %q", c.NamespaceLabelSelector)
+	}
+	if c.ShardCount < 0 {
+		return fmt.Errorf("invalid shard count %d: must not be negative", c.ShardCount)
+	}
+	if c.ShardIndex < 0 || c.ShardIndex >= max(c.ShardCount, 1) {
+		return fmt.Errorf("invalid shard index %d: must be between 0 and %d", c.ShardIndex, max(c.ShardCount, 1)-1)
+	}
+	// owners of Cloud Foundry resources may exist outside of the objects seen by a partial deployment (which would then delete
+	// their resources as orphans), so orphans may only be deleted by a deployment reconciling all namespaces
+	if c.OrphanPolicy == OrphanPolicyDelete && (len(c.WatchNamespaces) > 0 || len(c.IgnoreNamespaces) > 0 || c.NamespaceLabelSelector != "" || c.ShardCount > 1) {
+		return fmt.Errorf("invalid orphan policy %q: not supported together with watchNamespaces, ignoreNamespaces, namespaceLabelSelector or shardCount > 1", c.OrphanPolicy)
+	}
+	if c.TracingEndpoint != "" {
+		u, err := url.Parse(c.TracingEndpoint)
+		if err != nil {
+			return errors.Wrapf(err, "invalid tracing endpoint %s", c.TracingEndpoint)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("invalid tracing endpoint %s: must be an http or https URL", c.TracingEndpoint)
+		}
+	}
+	if c.TracingSampleRate < 0 || c.TracingSampleRate > 1 {
+		return fmt.Errorf("invalid tracing sample rate %g: must be between 0 and 1", c.TracingSampleRate)
+	}
+	for _, label := range c.CFMetricsLabels {
+		if !slices.Contains(cfmetrics.ContextLabels, label) {
+			return fmt.Errorf("invalid metrics label %q: must be one of %s", label, strings.Join(cfmetrics.ContextLabels, ", "))
+		}
+	}
+	return nil
+}
+
+// GetBindingSecretNameTemplate returns the parsed BindingSecretNameTemplate, or nil if it is not set.
+func (c *Config) GetBindingSecretNameTemplate() (*template.Template, error) {
+	if c.BindingSecretNameTemplate == "" {
+		return nil, nil
+	}
+	t, err := template.New("secretName").Option("missingkey=error").Parse(c.BindingSecretNameTemplate)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid binding secret name template %q", c.BindingSecretNameTemplate)
+	}
+	return t, nil
+}
+
+// RateLimitFor returns the rate limit applying to the Cloud Foundry API endpoint with the given URL.
+func (c *Config) RateLimitFor(url string) RateLimit {
+	for endpoint, limit := range c.EndpointRateLimits {
+		if strings.TrimSuffix(endpoint, "/") == strings.TrimSuffix(url, "/") {
+			return limit
+		}
+	}
+	return RateLimit{MaxRequestsPerSecond: c.MaxRequestsPerSecond, Burst: c.Burst}
+}
+
+// WithConnectionOverrides returns a copy of the configuration, with CABundle and Proxy replaced by the given values (if not empty).
+// The receiver is returned unchanged if there is nothing to override.
+func (c *Config) WithConnectionOverrides(caBundle string, proxy string) *Config {
+	if caBundle == "" && proxy == "" {
+		return c
+	}
+	cfg := &Config{}
+	if c != nil {
+		*cfg = *c
+	}
+	if caBundle != "" {
+		cfg.CABundle = caBundle
+	}
+	if proxy != "" {
+		cfg.Proxy = proxy
+	}
+	return cfg
+}
+
+// WithSpaceCacheTimeout returns a copy of the configuration, with SpaceCacheTimeout set to the given value.
+func (c *Config) WithSpaceCacheTimeout(timeout time.Duration) *Config {
+	cfg := &Config{}
+	if c != nil {
+		*cfg = *c
+	}
+	cfg.SpaceCacheTimeout = metav1.Duration{Duration: timeout}
+	return cfg
+}
+
+func validatePollingIntervals(intervals map[string]metav1.Duration) error {
+	for kind, interval := range intervals {
+		if !slices.Contains(PollingKinds, kind) {
+			return fmt.Errorf("unknown kind %s: must be one of %s", kind, strings.Join(PollingKinds, ", "))
+		}
+		if interval.Duration <= 0 {
+			return fmt.Errorf("interval %s for kind %s: must be positive", interval.Duration, kind)
+		}
+	}
+	return nil
+}
+
+// ParseDurationMap parses a comma-separated list of key=duration pairs (such as ServiceInstance=5m,Space=2m).
+func ParseDurationMap(value string) (map[string]metav1.Duration, error) {
+	durations := make(map[string]metav1.Duration)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		key, durationStr, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid entry %q: must be of the form key=duration", item)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(durationStr))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid entry %q", item)
+		}
+		durations[strings.TrimSpace(key)] = metav1.Duration{Duration: d}
+	}
+	return durations, nil
+}
+
+func validateConnection(caBundle string, proxy string) error {
+	if caBundle != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(caBundle)) {
+		return fmt.Errorf("invalid CA bundle: no PEM encoded certificates found")
+	}
+	if proxy != "" {
+		u, err := url.Parse(proxy)
+		if err != nil {
+			return errors.Wrapf(err, "invalid proxy URL %s", proxy)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid proxy URL %s: scheme and host are required", proxy)
+		}
+	}
+	return nil
+}
+
+var (
+	durationType    = reflect.TypeOf(metav1.Duration{})
+	durationMapType = reflect.TypeOf(map[string]metav1.Duration{})
+)
+
+// Set all fields having an env tag from the according environment variable (if present).
+func loadEnv(cfg *Config, lookupEnv func(string) (string, bool)) error {
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("env")
+		if name == "" {
+			continue
+		}
+		value, ok := lookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setField(v.Field(i), value); err != nil {
+			return errors.Wrapf(err, "invalid value for environment variable %s", name)
+		}
+	}
+	return nil
+}
+
+func setField(field reflect.Value, value string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(metav1.Duration{Duration: d}))
+		return nil
+	}
+	if field.Type() == durationMapType {
+		durations, err := ParseDurationMap(value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(durations))
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported field type: %s", field.Type())
+		}
+		// lists are given as comma-separated values
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported field type: %s", field.Type())
+	}
+	return nil
+}
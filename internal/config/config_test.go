@@ -0,0 +1,354 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConfig(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Config Test Suite")
+}
+
+// -----------------------------------------------------------------------------------------------
+// Tests
+// -----------------------------------------------------------------------------------------------
+
+var _ = Describe("Config tests", func() {
+	var env map[string]string
+	var lookupEnv func(string) (string, bool)
+
+	BeforeEach(func() {
+		env = make(map[string]string)
+		lookupEnv = func(name string) (string, bool) {
+			value, ok := env[name]
+			return value, ok
+		}
+	})
+
+	writeFile := func(content string) string {
+		path := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(path, []byte(content), 0o644)).To(Succeed())
+		return path
+	}
+
+	It("should return defaults without file and environment", func() {
+		cfg, err := load("", lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg).To(Equal(Defaults()))
+	})
+
+	It("should read the configuration file", func() {
+		path := writeFile("clusterResourceNamespace: cf-system\nsapBindingMetadata: true\n")
+		cfg, err := load(path, lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.ClusterResourceNamespace).To(Equal("cf-system"))
+		Expect(cfg.EnableBindingMetadata).To(BeTrue())
+	})
+
+	It("should let environment variables take precedence over the configuration file", func() {
+		path := writeFile("clusterResourceNamespace: cf-system\n")
+		env["CLUSTER_RESOURCE_NAMESPACE"] = "other"
+		env["SAP_BINDING_METADATA"] = "true"
+		cfg, err := load(path, lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.ClusterResourceNamespace).To(Equal("other"))
+		Expect(cfg.EnableBindingMetadata).To(BeTrue())
+	})
+
+	It("should reject unknown fields in the configuration file", func() {
+		path := writeFile("unknownField: foo\n")
+		_, err := load(path, lookupEnv)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject invalid environment values", func() {
+		env["SAP_BINDING_METADATA"] = "maybe"
+		_, err := load("", lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("SAP_BINDING_METADATA")))
+	})
+
+	It("should read durations from the configuration file and environment", func() {
+		path := writeFile("reconcileTimeout: 2m\n")
+		cfg, err := load(path, lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.ReconcileTimeout.Duration).To(Equal(2 * time.Minute))
+
+		env["RECONCILE_TIMEOUT"] = "30s"
+		cfg, err = load(path, lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.ReconcileTimeout.Duration).To(Equal(30 * time.Second))
+	})
+
+	It("should read and validate the polling intervals", func() {
+		path := writeFile("pollingIntervalsReady:\n  ServiceInstance: 30m\n  Space: 5m\n")
+		cfg, err := load(path, lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.PollingIntervalsReady).To(Equal(map[string]metav1.Duration{
+			"ServiceInstance": {Duration: 30 * time.Minute},
+			"Space":           {Duration: 5 * time.Minute},
+		}))
+
+		env["POLLING_INTERVALS_FAIL"] = "ServiceInstance=1h, ServiceBinding=2h"
+		cfg, err = load(path, lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.PollingIntervalsFail).To(Equal(map[string]metav1.Duration{
+			"ServiceInstance": {Duration: time.Hour},
+			"ServiceBinding":  {Duration: 2 * time.Hour},
+		}))
+
+		env["POLLING_INTERVALS_FAIL"] = "ServiceInstance"
+		_, err = load(path, lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("POLLING_INTERVALS_FAIL")))
+
+		env["POLLING_INTERVALS_FAIL"] = "Secret=1h"
+		_, err = load(path, lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("unknown kind Secret")))
+
+		env["POLLING_INTERVALS_FAIL"] = "ServiceInstance=0s"
+		_, err = load(path, lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("must be positive")))
+	})
+
+	It("should reject a negative reconcile timeout", func() {
+		env["RECONCILE_TIMEOUT"] = "-1s"
+		_, err := load("", lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("reconcile timeout")))
+	})
+
+	It("should reject a negative stalled operation timeout", func() {
+		env["STALLED_OPERATION_TIMEOUT"] = "-1s"
+		_, err := load("", lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("stalled operation timeout")))
+	})
+
+	It("should reject invalid connection settings", func() {
+		env["CF_CA_BUNDLE"] = "not a certificate"
+		_, err := load("", lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("invalid CA bundle")))
+
+		delete(env, "CF_CA_BUNDLE")
+		env["CF_PROXY"] = "proxy.example.com"
+		_, err = load("", lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("invalid proxy URL")))
+	})
+
+	It("should override connection settings", func() {
+		cfg := Defaults()
+		cfg.Proxy = "http://proxy.example.com"
+		Expect(cfg.WithConnectionOverrides("", "")).To(BeIdenticalTo(cfg))
+
+		overridden := cfg.WithConnectionOverrides("bundle", "")
+		Expect(overridden.CABundle).To(Equal("bundle"))
+		Expect(overridden.Proxy).To(Equal("http://proxy.example.com"))
+		Expect(cfg.CABundle).To(BeEmpty())
+	})
+
+	It("should reject a negative report interval", func() {
+		env["REPORT_INTERVAL"] = "-5m"
+		_, err := load("", lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("report interval")))
+	})
+
+	It("should read lists from the environment", func() {
+		env["SECRET_LABELS"] = "app.kubernetes.io/*, team"
+		cfg, err := load("", lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.SecretLabels).To(Equal([]string{"app.kubernetes.io/*", "team"}))
+	})
+
+	It("should reject an invalid secret deletion propagation", func() {
+		env["SECRET_DELETION_PROPAGATION"] = "Immediate"
+		_, err := load("", lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("secret deletion propagation")))
+
+		env["SECRET_DELETION_PROPAGATION"] = "Background"
+		cfg, err := load("", lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.SecretDeletionPropagation).To(Equal(metav1.DeletePropagationBackground))
+	})
+
+	It("should allow deleting superseded secrets immediately, but reject a negative grace period", func() {
+		cfg, err := load("", lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.SupersededSecretGracePeriod.Duration).To(Equal(5 * time.Minute))
+
+		env["SUPERSEDED_SECRET_GRACE_PERIOD"] = "0s"
+		cfg, err = load("", lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.SupersededSecretGracePeriod.Duration).To(BeZero())
+
+		env["SUPERSEDED_SECRET_GRACE_PERIOD"] = "-1m"
+		_, err = load("", lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("superseded secret grace period")))
+	})
+
+	It("should reject an invalid binding secret name template", func() {
+		env["BINDING_SECRET_NAME_TEMPLATE"] = "{{ .Name"
+		_, err := load("", lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("binding secret name template")))
+
+		env["BINDING_SECRET_NAME_TEMPLATE"] = "{{ .Name }}-creds"
+		cfg, err := load("", lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		t, err := cfg.GetBindingSecretNameTemplate()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(t).ToNot(BeNil())
+	})
+
+	It("should reject invalid circuit breaker settings", func() {
+		env["CF_CIRCUIT_BREAKER_THRESHOLD"] = "-1"
+		_, err := load("", lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("circuit breaker threshold")))
+
+		env["CF_CIRCUIT_BREAKER_THRESHOLD"] = "3"
+		env["CF_CIRCUIT_BREAKER_TIMEOUT"] = "0s"
+		_, err = load("", lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("circuit breaker timeout")))
+	})
+
+	It("should reject invalid list paging settings", func() {
+		env["CF_LIST_PAGE_SIZE"] = "5001"
+		_, err := load("", lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("list page size")))
+
+		env["CF_LIST_PAGE_SIZE"] = "500"
+		env["CF_LIST_PARALLELISM"] = "-1"
+		_, err = load("", lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("list parallelism")))
+
+		env["CF_LIST_PARALLELISM"] = "4"
+		cfg, err := load("", lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.ListPageSize).To(Equal(500))
+		Expect(cfg.ListParallelism).To(Equal(4))
+	})
+
+	It("should reject a non-positive resource cache timeout if the resource cache is enabled", func() {
+		path := writeFile("resourceCacheEnabled: true\nresourceCacheTimeout: 0s\n")
+		_, err := load(path, lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("resource cache timeout")))
+	})
+
+	It("should allow disabling the catalog cache, but reject a negative catalog cache timeout", func() {
+		env["CATALOG_CACHE_TIMEOUT"] = "0s"
+		cfg, err := load("", lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.CatalogCacheTimeout.Duration).To(BeZero())
+
+		env["CATALOG_CACHE_TIMEOUT"] = "-1m"
+		_, err = load("", lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("catalog cache timeout")))
+	})
+
+	It("should read and validate the orphan policy", func() {
+		env["ORPHAN_SCAN_INTERVAL"] = "1h"
+		env["ORPHAN_POLICY"] = "Delete"
+		cfg, err := load("", lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.OrphanScanInterval.Duration).To(Equal(time.Hour))
+		Expect(cfg.OrphanPolicy).To(Equal(OrphanPolicyDelete))
+
+		env["ORPHAN_POLICY"] = "Ignore"
+		_, err = load("", lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("orphan policy")))
+	})
+
+	It("should refuse to delete orphans unless all namespaces are reconciled", func() {
+		env["ORPHAN_POLICY"] = "Delete"
+		for _, path := range []string{
+			writeFile("watchNamespaces:\n- a\n"),
+			writeFile("ignoreNamespaces:\n- a\n"),
+			writeFile("namespaceLabelSelector: landscape=eu10\n"),
+			writeFile("shardCount: 2\n"),
+		} {
+			_, err := load(path, lookupEnv)
+			Expect(err).To(MatchError(ContainSubstring("not supported together with watchNamespaces")))
+		}
+
+		env["ORPHAN_POLICY"] = "Report"
+		_, err := load(writeFile("shardCount: 2\n"), lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should read and validate the cluster id", func() {
+		env["CLUSTER_ID"] = "eu10-canary"
+		cfg, err := load("", lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.ClusterID).To(Equal("eu10-canary"))
+
+		env["CLUSTER_ID"] = "eu10/canary"
+		_, err = load("", lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("invalid cluster id")))
+	})
+
+	It("should read and validate the namespace filter and shard", func() {
+		path := writeFile("watchNamespaces:\n- a\n- b\nnamespaceLabelSelector: landscape in (eu10, us10)\nshardCount: 3\nshardIndex: 2\n")
+		cfg, err := load(path, lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.WatchNamespaces).To(Equal([]string{"a", "b"}))
+		Expect(cfg.ShardIndex).To(Equal(2))
+		Expect(cfg.ReconcileClusterSpaces).To(BeTrue())
+
+		env["SHARD_INDEX"] = "3"
+		_, err = load(path, lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("shard index")))
+
+		delete(env, "SHARD_INDEX")
+		env["NAMESPACE_LABEL_SELECTOR"] = "landscape in eu10"
+		_, err = load(path, lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("namespace label selector")))
+	})
+
+	It("should read and validate the tracing settings", func() {
+		env["TRACING_ENDPOINT"] = "http://otel-collector:4318"
+		env["TRACING_SAMPLE_RATE"] = "0.25"
+		cfg, err := load("", lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.TracingSampleRate).To(Equal(0.25))
+		Expect(cfg.TracingServiceName).To(Equal("cf-service-operator"))
+
+		env["TRACING_SAMPLE_RATE"] = "2"
+		_, err = load("", lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("tracing sample rate")))
+
+		env["TRACING_SAMPLE_RATE"] = "1"
+		env["TRACING_ENDPOINT"] = "otel-collector:4318"
+		_, err = load("", lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("tracing endpoint")))
+	})
+
+	It("should read and validate the metrics labels", func() {
+		env["CF_METRICS_LABELS"] = "space,controller"
+		cfg, err := load("", lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.CFMetricsLabels).To(Equal([]string{"space", "controller"}))
+
+		env["CF_METRICS_LABELS"] = "namespace"
+		_, err = load("", lookupEnv)
+		Expect(err).To(MatchError(ContainSubstring("metrics label")))
+	})
+
+	It("should determine rate limits per endpoint", func() {
+		path := writeFile("maxRequestsPerSecond: 5\nendpointRateLimits:\n  https://api.cf.example.com/:\n    maxRequestsPerSecond: 1\n    burst: 2\n")
+		cfg, err := load(path, lookupEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.RateLimitFor("https://api.cf.example.com")).To(Equal(RateLimit{MaxRequestsPerSecond: 1, Burst: 2}))
+		Expect(cfg.RateLimitFor("https://api.cf.other.com")).To(Equal(RateLimit{MaxRequestsPerSecond: 5, Burst: 10}))
+	})
+
+	It("should fail on a missing configuration file", func() {
+		_, err := load(filepath.Join(GinkgoT().TempDir(), "missing.yaml"), lookupEnv)
+		Expect(err).To(HaveOccurred())
+	})
+})
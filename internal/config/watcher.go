@@ -0,0 +1,129 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Watcher reloads the configuration file in regular intervals, and passes the configuration to a handler whenever it changed.
+// The configuration file is usually a mounted ConfigMap; since the kubelet updates such files by swapping symlinks,
+// the file is re-read instead of relying on file system events.
+// Only fields tagged with reload:"true" are applied at runtime; changes of other fields are logged, and require a restart.
+type Watcher struct {
+	path      string
+	interval  time.Duration
+	onChange  func(*Config)
+	lookupEnv func(string) (string, bool)
+	// last successfully loaded configuration
+	current *Config
+	// last load error (to avoid logging the same error in every interval)
+	lastError string
+}
+
+// NewWatcher returns a watcher for the configuration file at path, where current is the configuration loaded at startup
+// (before applying command line flags). The handler is called with the complete reloaded configuration.
+func NewWatcher(path string, interval time.Duration, current *Config, onChange func(*Config)) *Watcher {
+	return &Watcher{
+		path:      path,
+		interval:  interval,
+		onChange:  onChange,
+		lookupEnv: os.LookupEnv,
+		current:   current,
+	}
+}
+
+// Start reloads the configuration file every interval, until the context is cancelled.
+// Implements manager.Runnable; errors are logged, and the previous configuration stays in effect.
+func (w *Watcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			w.reload(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; the configuration is reloaded in all replicas.
+func (w *Watcher) NeedLeaderElection() bool {
+	return false
+}
+
+func (w *Watcher) reload(ctx context.Context) {
+	log := log.FromContext(ctx).WithName("config")
+
+	cfg, err := load(w.path, w.lookupEnv)
+	if err != nil {
+		if err.Error() != w.lastError {
+			log.Error(err, "failed to reload configuration; keeping previous configuration")
+			w.lastError = err.Error()
+		}
+		return
+	}
+	w.lastError = ""
+	if reflect.DeepEqual(cfg, w.current) {
+		return
+	}
+
+	reloaded, ignored := diffFields(w.current, cfg)
+	if len(ignored) > 0 {
+		log.Info("ignoring changed configuration keys; a restart is required to apply them", "keys", ignored)
+	}
+	if len(reloaded) > 0 {
+		log.Info("applying changed configuration keys", "keys", reloaded)
+		w.onChange(cfg)
+	}
+	w.current = cfg
+}
+
+// WithReloadable returns a copy of the configuration, with all fields tagged with reload:"true" taken from other.
+func (c *Config) WithReloadable(other *Config) *Config {
+	cfg := &Config{}
+	if c != nil {
+		*cfg = *c
+	}
+	if other == nil {
+		return cfg
+	}
+	v := reflect.ValueOf(cfg).Elem()
+	o := reflect.ValueOf(other).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("reload") == "true" {
+			v.Field(i).Set(o.Field(i))
+		}
+	}
+	return cfg
+}
+
+// diffFields returns the json names of the fields differing between old and new, split into reloadable and other fields.
+func diffFields(old *Config, new *Config) (reloadable []string, other []string) {
+	v := reflect.ValueOf(old).Elem()
+	w := reflect.ValueOf(new).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if reflect.DeepEqual(v.Field(i).Interface(), w.Field(i).Interface()) {
+			continue
+		}
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if t.Field(i).Tag.Get("reload") == "true" {
+			reloadable = append(reloadable, name)
+		} else {
+			other = append(other, name)
+		}
+	}
+	return reloadable, other
+}
@@ -0,0 +1,79 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Watcher tests", func() {
+	ctx := context.Background()
+	var path string
+	var reloaded []*Config
+	var watcher *Watcher
+
+	writeFile := func(content string) {
+		Expect(os.WriteFile(path, []byte(content), 0o644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		writeFile("resourceCacheEnabled: true\n")
+		current, err := Load(path)
+		Expect(err).ToNot(HaveOccurred())
+		reloaded = nil
+		watcher = NewWatcher(path, time.Second, current, func(cfg *Config) { reloaded = append(reloaded, cfg) })
+		watcher.lookupEnv = func(string) (string, bool) { return "", false }
+	})
+
+	It("should pass changed reloadable settings to the handler", func() {
+		watcher.reload(ctx)
+		Expect(reloaded).To(BeEmpty())
+
+		writeFile("resourceCacheEnabled: true\nmaxRequestsPerSecond: 20\nmaxConcurrentReconciles: 5\n")
+		watcher.reload(ctx)
+		Expect(reloaded).To(HaveLen(1))
+		Expect(reloaded[0].MaxRequestsPerSecond).To(Equal(float64(20)))
+		Expect(reloaded[0].MaxConcurrentReconciles).To(Equal(5))
+
+		watcher.reload(ctx)
+		Expect(reloaded).To(HaveLen(1))
+	})
+
+	It("should not call the handler for settings which require a restart", func() {
+		writeFile("resourceCacheEnabled: true\nclusterResourceNamespace: other\n")
+		watcher.reload(ctx)
+		Expect(reloaded).To(BeEmpty())
+	})
+
+	It("should keep the previous configuration if the file is invalid", func() {
+		writeFile("resourceCacheEnabled: true\nmaxConcurrentReconciles: 0\n")
+		watcher.reload(ctx)
+		Expect(reloaded).To(BeEmpty())
+		Expect(watcher.current.IsResourceCacheEnabled).To(BeTrue())
+		Expect(watcher.current.MaxConcurrentReconciles).To(Equal(1))
+	})
+
+	It("should take only reloadable fields from the reloaded configuration", func() {
+		cfg := Defaults()
+		cfg.ClusterResourceNamespace = "cf-system"
+		other := Defaults()
+		other.ClusterResourceNamespace = "other"
+		other.Burst = 50
+		other.EndpointRateLimits = map[string]RateLimit{"https://api.cf.example.com": {MaxRequestsPerSecond: 1}}
+
+		result := cfg.WithReloadable(other)
+		Expect(result.ClusterResourceNamespace).To(Equal("cf-system"))
+		Expect(result.Burst).To(Equal(50))
+		Expect(result.EndpointRateLimits).To(HaveKey("https://api.cf.example.com"))
+		Expect(cfg.Burst).To(Equal(Defaults().Burst))
+	})
+})
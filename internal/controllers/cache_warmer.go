@@ -0,0 +1,116 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+// CacheWarmer populates the resource cache with the service instances and bindings of all Cloud Foundry spaces managed
+// through Space and ClusterSpace objects, as soon as the operator acquires leadership (instead of lazily, on the first lookups),
+// and refreshes the cache of each space after half of its cache timeout (such that entries are renewed before they expire).
+// The cache timeout is taken from the reloaded configuration, overridden by spec.configOverrides of the space (if set there).
+// When leadership is lost, the resource cache is invalidated.
+type CacheWarmer struct {
+	client.Client
+	ClusterResourceNamespace string
+	ClientBuilder            facade.SpaceClientBuilder
+	Config                   *config.Config
+	// Invalidate drops the contents of the resource cache; called when the context passed to Start is cancelled
+	Invalidate func()
+	// time of the last successful refresh, by space guid
+	refreshedAt map[string]time.Time
+}
+
+// Start refreshes the resource cache of each space when it is due, until the context is cancelled.
+// Implements manager.Runnable; errors are logged, and do not stop the warmer.
+func (r *CacheWarmer) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithName("cache-warmer")
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if r.Invalidate != nil {
+				r.Invalidate()
+			}
+			return nil
+		case <-timer.C:
+		}
+		timer.Reset(r.refresh(ctx))
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; the cache is only populated by the leader.
+func (r *CacheWarmer) NeedLeaderElection() bool {
+	return true
+}
+
+// refresh refreshes the resource cache of all spaces which are due, and returns the time until the next space is due.
+func (r *CacheWarmer) refresh(ctx context.Context) time.Duration {
+	log := ctrl.LoggerFrom(ctx)
+	cfg := withReloadedConfig(r.Config)
+	next := cacheRefreshInterval(cfg)
+
+	spaces, err := listManagedSpaces(ctx, r.Client)
+	if err != nil {
+		log.Error(err, "failed to refresh resource cache")
+		return next
+	}
+	now := time.Now()
+	refreshedAt := make(map[string]time.Time)
+	refreshed := 0
+	for _, space := range spaces {
+		if ctx.Err() != nil {
+			return next
+		}
+		guid := space.GetSpec().Guid
+		if guid == "" {
+			guid = space.GetStatus().SpaceGuid
+		}
+		interval := cacheRefreshInterval(getSpaceConfig(cfg, space))
+		if last, ok := r.refreshedAt[guid]; ok && now.Sub(last) < interval {
+			refreshedAt[guid] = last
+			next = min(next, interval-now.Sub(last))
+			continue
+		}
+		// spaces failing to refresh are retried after their interval
+		next = min(next, interval)
+		spaceClient, err := newManagedSpaceClient(ctx, r.Client, r.ClusterResourceNamespace, r.ClientBuilder, cfg, space)
+		if err != nil {
+			log.Error(err, "skipping space", "kind", space.GetKind(), "namespace", space.GetNamespace(), "name", space.GetName())
+			continue
+		}
+		if err := spaceClient.RefreshCache(ctx); err != nil {
+			log.Error(err, "skipping space", "kind", space.GetKind(), "namespace", space.GetNamespace(), "name", space.GetName())
+			continue
+		}
+		refreshedAt[guid] = now
+		refreshed++
+	}
+	r.refreshedAt = refreshedAt
+	log.V(1).Info("refreshed resource cache", "spaces", refreshed)
+	return next
+}
+
+// cacheRefreshInterval returns the interval in which the resource cache is refreshed according to the given (space) configuration;
+// that is, half of the cache timeout, such that entries are renewed before they expire.
+func cacheRefreshInterval(cfg *config.Config) time.Duration {
+	timeout := cfg.CacheTimeOut.Duration
+	if cfg.SpaceCacheTimeout.Duration > 0 {
+		timeout = cfg.SpaceCacheTimeout.Duration
+	}
+	return max(timeout/2, time.Second)
+}
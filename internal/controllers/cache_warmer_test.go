@@ -0,0 +1,119 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+	"github.com/sap/cf-service-operator/internal/facade/facadefakes"
+)
+
+var _ = Describe("Warm up and refresh the resource cache | CacheWarmer", func() {
+	var spaceClient *facadefakes.FakeSpaceClient
+	var spaceGuids []string
+	var invalidated bool
+	var warmer *CacheWarmer
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		spaceClient = &facadefakes.FakeSpaceClient{}
+		spaceGuids = nil
+		invalidated = false
+		warmer = &CacheWarmer{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&cfv1alpha1.Space{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space"},
+					Spec:       cfv1alpha1.SpaceSpec{Guid: "space-guid", AuthSecretName: "space-secret"},
+				},
+				&cfv1alpha1.Space{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "same-space"},
+					Spec:       cfv1alpha1.SpaceSpec{Guid: "space-guid", AuthSecretName: "space-secret"},
+				},
+				&cfv1alpha1.ClusterSpace{
+					ObjectMeta: metav1.ObjectMeta{Name: "cluster-space"},
+					Spec:       cfv1alpha1.SpaceSpec{Guid: "cluster-space-guid", AuthSecretName: "missing-secret"},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space-secret"},
+					Data:       map[string][]byte{"url": []byte("https://api.cf.example.com")},
+				},
+			).Build(),
+			ClusterResourceNamespace: "cluster-ns",
+			ClientBuilder: func(spaceGuid string, _ string, _ string, _ string, _ *config.Config) (facade.SpaceClient, error) {
+				spaceGuids = append(spaceGuids, spaceGuid)
+				return spaceClient, nil
+			},
+			Config:     config.Defaults(),
+			Invalidate: func() { invalidated = true },
+		}
+	})
+
+	It("should refresh the cache of each space once, skipping spaces without credentials", func() {
+		warmer.refresh(context.Background())
+		Expect(spaceGuids).To(Equal([]string{"space-guid"}))
+		Expect(spaceClient.RefreshCacheCallCount()).To(Equal(1))
+	})
+
+	It("should not stop on refresh errors", func() {
+		spaceClient.RefreshCacheReturns(errors.New("some error"))
+		warmer.refresh(context.Background())
+		Expect(spaceClient.RefreshCacheCallCount()).To(Equal(1))
+	})
+
+	It("should refresh each space when due, according to its (reloaded) cache timeout", func() {
+		ctx := context.Background()
+		Expect(warmer.Create(ctx, &cfv1alpha1.Space{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "other-space"},
+			Spec: cfv1alpha1.SpaceSpec{
+				Guid:            "other-space-guid",
+				AuthSecretName:  "space-secret",
+				ConfigOverrides: &cfv1alpha1.SpaceConfigOverrides{ResourceCacheTimeout: &metav1.Duration{Duration: time.Minute}},
+			},
+		})).To(Succeed())
+		reloaded := config.Defaults()
+		reloaded.CacheTimeOut = metav1.Duration{Duration: time.Hour}
+		DeferCleanup(func(cfg *config.Config) { reloadedConfig = cfg }, reloadedConfig)
+		reloadedConfig = reloaded
+
+		Expect(warmer.refresh(ctx)).To(Equal(30 * time.Second))
+		Expect(spaceGuids).To(ConsistOf("space-guid", "other-space-guid"))
+
+		// spaces are not refreshed again before they are due
+		Expect(warmer.refresh(ctx)).To(BeNumerically("~", 30*time.Second, time.Second))
+		Expect(spaceClient.RefreshCacheCallCount()).To(Equal(2))
+
+		warmer.refreshedAt["other-space-guid"] = time.Now().Add(-time.Minute)
+		Expect(warmer.refresh(ctx)).To(Equal(30 * time.Second))
+		Expect(spaceGuids).To(HaveLen(3))
+		Expect(spaceGuids[2]).To(Equal("other-space-guid"))
+	})
+
+	It("should populate the cache on start, and invalidate it when stopped", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- warmer.Start(ctx) }()
+		Eventually(spaceClient.RefreshCacheCallCount).Should(Equal(1))
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(invalidated).To(BeTrue())
+		Expect(warmer.NeedLeaderElection()).To(BeTrue())
+	})
+})
@@ -0,0 +1,590 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/binding"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+const (
+	clusterServiceBindingFinalizer = "cf.cs.sap.com/service-operator"
+)
+
+const (
+	clusterServiceBindingReadyConditionReasonNew                     = "FirstSeen"
+	clusterServiceBindingReadyConditionReasonSpaceNotReady           = "SpaceNotReady"
+	clusterServiceBindingReadyConditionReasonServiceInstanceNotReady = "ServiceInstanceNotReady"
+	clusterServiceBindingReadyConditionReasonError                   = "Error"
+	clusterServiceBindingReadyConditionReasonDeletionBlocked         = "DeletionBlocked"
+	// Additionally, all of facade.BindingState* may occur as Ready condition reason
+)
+
+// ClusterServiceBindingReconciler reconciles a ClusterServiceBinding object
+type ClusterServiceBindingReconciler struct {
+	client.Client
+	Scheme                   *runtime.Scheme
+	ClusterResourceNamespace string
+	EnableBindingMetadata    bool
+	ClientBuilder            facade.SpaceClientBuilder
+	ReconcileTimeout         time.Duration
+	Config                   *config.Config
+}
+
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=clusterservicebindings,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=clusterservicebindings/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=clusterservicebindings/finalizers,verbs=update
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=serviceinstances,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=clusterspaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+func (r *ClusterServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	log := ctrl.LoggerFrom(ctx)
+	log.V(2).Info("Running reconcile")
+
+	// Retrieve target cluster service binding
+	clusterServiceBinding := &cfv1alpha1.ClusterServiceBinding{}
+	if err := r.Get(ctx, req.NamespacedName, clusterServiceBinding); err != nil {
+		if err := client.IgnoreNotFound(err); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "unexpected get error")
+		}
+		log.V(1).Info("Not found; ignoring")
+		return ctrl.Result{}, nil
+	}
+
+	// Bound the duration of this reconcile (unless overridden by annotation, as configured); the final status update (see below)
+	// is attempted even after expiry
+	reconcileCallTimeout := getReconcileCallTimeout(clusterServiceBinding.GetAnnotations(), r.ReconcileTimeout)
+	ctx, cancel := withReconcileTimeout(ctx, reconcileCallTimeout)
+	defer cancel()
+	ctx, cfResponded := facade.WithResponseRecorder(ctx)
+
+	// Call the defaulting webhook logic also here (because defaulting through the webhook might be incomplete in case of generateName usage)
+	clusterServiceBinding.Default()
+
+	spec := &clusterServiceBinding.Spec
+	status := &clusterServiceBinding.Status
+	status.LastReconciledAt = &[]metav1.Time{metav1.Now()}[0]
+
+	// Always attempt to update the status
+	skipStatusUpdate := false
+	defer func() {
+		if skipStatusUpdate {
+			return
+		}
+		if cfResponded() {
+			clusterServiceBinding.SetCondition(cfv1alpha1.ClusterServiceBindingConditionCFReachable, cfv1alpha1.ConditionTrue, conditionReasonReachable, "Cloud Foundry API is reachable")
+		}
+		err = reportReconcileTimeout(ctx, clusterServiceBinding, cfv1alpha1.ClusterServiceBindingConditionTimeout, err, reconcileCallTimeout)
+		if err != nil {
+			if unavailableResult, ok := cfUnavailableResult(err); ok {
+				log.V(1).Info("Cloud Foundry API unavailable; requeuing", "requeueAfter", unavailableResult.RequeueAfter)
+				clusterServiceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, readyConditionReasonCFUnavailable, err.Error())
+				clusterServiceBinding.SetCondition(cfv1alpha1.ClusterServiceBindingConditionCFReachable, cfv1alpha1.ConditionFalse, readyConditionReasonCFUnavailable, err.Error())
+				result, err = unavailableResult, nil
+			} else {
+				clusterServiceBinding.SetReadyCondition(cfv1alpha1.ConditionFalse, clusterServiceBindingReadyConditionReasonError, err.Error())
+				clusterServiceBinding.SetCondition(cfv1alpha1.ClusterServiceBindingConditionSynced, cfv1alpha1.ConditionFalse, conditionReasonError, err.Error())
+			}
+		}
+		if updateErr := r.Status().Update(context.WithoutCancel(ctx), clusterServiceBinding); updateErr != nil {
+			err = utilerrors.NewAggregate([]error{err, updateErr})
+			result = ctrl.Result{}
+		}
+	}()
+
+	// While paused, nothing is read from or changed in Cloud Foundry; the annotation being removed triggers the next reconciliation
+	if isPaused(clusterServiceBinding.Annotations) {
+		clusterServiceBinding.SetCondition(cfv1alpha1.ClusterServiceBindingConditionPaused, cfv1alpha1.ConditionTrue, conditionReasonPaused, pausedMessage)
+		return ctrl.Result{}, nil
+	}
+	if clusterServiceBinding.GetCondition(cfv1alpha1.ClusterServiceBindingConditionPaused) != nil {
+		// persist the removal of the condition first (and requeue), since updates of the object (e.g. adding finalizers) reset the status
+		clusterServiceBinding.RemoveCondition(cfv1alpha1.ClusterServiceBindingConditionPaused)
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Set a first status (and requeue, because the status update itself will not trigger another reconciliation because of the event filter set)
+	if ready := clusterServiceBinding.GetReadyCondition(); ready == nil {
+		clusterServiceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, clusterServiceBindingReadyConditionReasonNew, "First seen")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Retrieve referenced service instance; it must belong to a cluster space
+	serviceInstanceName := types.NamespacedName{Namespace: spec.ServiceInstanceRef.Namespace, Name: spec.ServiceInstanceRef.Name}
+	serviceInstance := &cfv1alpha1.ServiceInstance{}
+	if err := r.Get(ctx, serviceInstanceName, serviceInstance); err != nil {
+		if !apierrors.IsNotFound(err) || clusterServiceBinding.DeletionTimestamp.IsZero() {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to get ServiceInstance, name: %s", serviceInstanceName)
+		}
+		// Deletion case, with the service instance gone; since service instances are only deleted once all depending bindings are gone,
+		// there is no Cloud Foundry binding left to delete (unless the instance was removed forcefully), so only the binding secret is deleted
+		log.V(1).Info("Referenced service instance not found; skipping deletion of Cloud Foundry binding", "serviceInstance", serviceInstanceName)
+		secretGone, err := r.deleteBindingSecret(ctx, clusterServiceBinding, types.NamespacedName{Namespace: spec.SecretNamespace, Name: spec.SecretName})
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !secretGone {
+			clusterServiceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, clusterServiceBindingReadyConditionReasonDeletionBlocked, "Waiting for deletion of binding secret")
+			clusterServiceBinding.SetCondition(cfv1alpha1.ClusterServiceBindingConditionDeletionBlocked, cfv1alpha1.ConditionTrue, conditionReasonDependentsExist, "Waiting for deletion of binding secret")
+			// TODO: apply some increasing period, depending on the age of the last update
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		if containsString(clusterServiceBinding.Finalizers, clusterServiceBindingFinalizer) {
+			controllerutil.RemoveFinalizer(clusterServiceBinding, clusterServiceBindingFinalizer)
+			if err := r.Update(ctx, clusterServiceBinding); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		// skip status update, since the cluster service binding will anyway deleted timely by the API server
+		skipStatusUpdate = true
+		return ctrl.Result{}, nil
+	}
+	if serviceInstance.Spec.ClusterSpaceName == "" {
+		return ctrl.Result{}, fmt.Errorf("referenced ServiceInstance %s does not belong to a ClusterSpace", serviceInstanceName)
+	}
+
+	// Retrieve referenced space
+	space := &cfv1alpha1.ClusterSpace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: serviceInstance.Spec.ClusterSpaceName}, space); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to get ClusterSpace, name: %s", serviceInstance.Spec.ClusterSpaceName)
+	}
+
+	spaceSecretName, err := getSpaceSecretName(space, r.ClusterResourceNamespace, r.Config)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	spaceGuid := space.Spec.Guid
+	if spaceGuid == "" {
+		spaceGuid = space.Status.SpaceGuid
+	}
+
+	// Apply the configuration overrides of the space
+	annotations := getEffectiveAnnotations(clusterServiceBinding.GetAnnotations(), space)
+
+	spaceSecret := &corev1.Secret{}
+	if err := r.Get(ctx, spaceSecretName, spaceSecret); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to get Secret containing space credentials, secret name: %s", spaceSecretName)
+	}
+
+	// Require readiness of space unless in deletion case
+	if clusterServiceBinding.DeletionTimestamp.IsZero() {
+		if !space.IsReady() {
+			clusterServiceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, clusterServiceBindingReadyConditionReasonSpaceNotReady, getSpaceNotReadyMessage(space))
+			// TODO: apply some increasing period, depending on the age of the last update
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		if spaceGuid == "" {
+			return ctrl.Result{}, fmt.Errorf("unexpected error; unable to find guid on ready %s: name: %s", space.GetKind(), space.GetName())
+		}
+	}
+
+	// Build cloud foundry client
+	endpoint := getEndpoint(space, spaceSecret, annotations)
+	status.Endpoint = endpoint
+	var client facade.SpaceClient
+	if spaceGuid != "" {
+		client, err = r.ClientBuilder(spaceGuid, endpoint, string(spaceSecret.Data["username"]), string(spaceSecret.Data["password"]), getClientConfig(getSpaceConfig(r.Config, space), spaceSecret))
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to build the client from secret %s", spaceSecretName)
+		}
+	}
+
+	// Retrieve cloud foundry binding
+	var cfbinding *facade.Binding
+	bindingOpts := map[string]string{"name": "", "owner": string(clusterServiceBinding.UID)}
+	if client != nil {
+		log.V(1).Info("Retrieving binding by owner")
+		cfbinding, err = client.GetBinding(ctx, bindingOpts)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// In observe-only mode, the cloud foundry binding is only read; nothing is created, updated or deleted (neither the binding secret)
+	if isObserveOnly(r.Config, clusterServiceBinding.Annotations) {
+		if !clusterServiceBinding.DeletionTimestamp.IsZero() {
+			// the cloud foundry binding and the binding secret are left untouched; just release the object (if it was managed before)
+			if containsString(clusterServiceBinding.Finalizers, clusterServiceBindingFinalizer) {
+				controllerutil.RemoveFinalizer(clusterServiceBinding, clusterServiceBindingFinalizer)
+				if err := r.Update(ctx, clusterServiceBinding); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			skipStatusUpdate = true
+			return ctrl.Result{}, nil
+		}
+		clusterServiceBinding.SetCondition(cfv1alpha1.ClusterServiceBindingConditionSynced, cfv1alpha1.ConditionUnknown, conditionReasonObserveOnly, "Changes are not applied in observe-only mode")
+		if cfbinding == nil {
+			clusterServiceBinding.SetReadyCondition(cfv1alpha1.ConditionFalse, readyConditionReasonNotFound, "Cloud Foundry binding not found (observe-only mode; it will not be created)")
+			return getPollingInterval(annotations, getDefaultPollingIntervalReady(r.Config, "ClusterServiceBinding"), cfv1alpha1.AnnotationPollingIntervalReady), nil
+		}
+		r.updateStatus(clusterServiceBinding, cfbinding, spaceGuid)
+		switch cfbinding.State {
+		case facade.BindingStateReady:
+			clusterServiceBinding.SetReadyCondition(cfv1alpha1.ConditionTrue, string(cfbinding.State), cfbinding.StateDescription)
+		case facade.BindingStateCreatedFailed, facade.BindingStateDeleteFailed:
+			clusterServiceBinding.SetReadyCondition(cfv1alpha1.ConditionFalse, string(cfbinding.State), cfbinding.StateDescription)
+		default:
+			clusterServiceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, string(cfbinding.State), cfbinding.StateDescription)
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		return getPollingInterval(annotations, getDefaultPollingIntervalReady(r.Config, "ClusterServiceBinding"), cfv1alpha1.AnnotationPollingIntervalReady), nil
+	}
+
+	secretName := types.NamespacedName{Namespace: spec.SecretNamespace, Name: spec.SecretName}
+
+	if clusterServiceBinding.DeletionTimestamp.IsZero() {
+		// Create/update case
+		if !containsString(clusterServiceBinding.Finalizers, clusterServiceBindingFinalizer) {
+			controllerutil.AddFinalizer(clusterServiceBinding, clusterServiceBindingFinalizer)
+			if err := r.Update(ctx, clusterServiceBinding); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		if !serviceInstance.IsReady() {
+			clusterServiceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, clusterServiceBindingReadyConditionReasonServiceInstanceNotReady,
+				fmt.Sprintf("Referenced ServiceInstance is not ready, name: %s", serviceInstanceName))
+			// TODO: apply some increasing period, depending on the age of the last update
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+
+		parameters, err := getParameters(ctx, r.Client, clusterServiceBinding, spec.Parameters, nil, "")
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		inRecreation := false
+
+		if cfbinding == nil {
+			schemas, err := getServicePlanSchemas(ctx, client, serviceInstance.Status.ServicePlanGuid, serviceInstance.Status.SpaceGuid)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if err := validateParameters(schemas.BindingCreate, parameters); err != nil {
+				// the binding is not created (instead of letting the broker fail asynchronously)
+				clusterServiceBinding.SetReadyCondition(cfv1alpha1.ConditionFalse, readyConditionReasonInvalidParameters, err.Error())
+				clusterServiceBinding.SetCondition(cfv1alpha1.ClusterServiceBindingConditionSynced, cfv1alpha1.ConditionFalse, readyConditionReasonInvalidParameters, err.Error())
+				return ctrl.Result{RequeueAfter: invalidParametersRequeueInterval}, nil
+			}
+			log.V(1).Info("Creating binding")
+			cfbinding, err = client.CreateBinding(
+				ctx,
+				spec.Name,
+				serviceInstance.Status.ServiceInstanceGuid,
+				"",
+				parameters,
+				getCFMetadata(spec.Metadata, false),
+				ownerRefOf(clusterServiceBinding),
+				clusterServiceBinding.Generation,
+			)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
+		} else {
+			if cfbinding.State == facade.BindingStateDeleting {
+				// This is the re-creation case; nothing to, we just wait until it is gone
+			} else if cfbinding.ParameterHash != facade.ObjectHash(parameters) ||
+				cfbinding.State == facade.BindingStateCreatedFailed || cfbinding.State == facade.BindingStateDeleteFailed {
+				// Re-create binding (cloud foundry does not support binding updates, other than metadata)
+				log.V(1).Info("Deleting binding for later re-creation")
+				if err := client.DeleteBinding(ctx, cfbinding.Guid, cfbinding.OwnerRef()); err != nil {
+					return ctrl.Result{}, err
+				}
+				status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
+				inRecreation = true
+				// Clear binding, so it will be re-read below
+				cfbinding = nil
+			} else if cfbinding.Generation < clusterServiceBinding.Generation {
+				log.V(1).Info("Updating binding")
+				if err := client.UpdateBinding(
+					ctx,
+					cfbinding.Guid,
+					withOwnerObject(cfbinding.OwnerRef(), clusterServiceBinding),
+					clusterServiceBinding.Generation,
+					nil,
+					getCFMetadata(spec.Metadata, cfbinding.CustomMetadata),
+				); err != nil {
+					return ctrl.Result{}, err
+				}
+				status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
+				// Clear binding, so it will be re-read below
+				cfbinding = nil
+			}
+		}
+
+		if cfbinding == nil {
+			// Re-retrieve cloud foundry binding by UID; this happens exactly if the binding was updated or deleted above
+			log.V(1).Info("Retrieving binding")
+			cfbinding, err = client.GetBinding(ctx, bindingOpts)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if cfbinding == nil {
+				if inRecreation {
+					// This is the re-create case, if the binding is already gone (maybe deleted synchronously)
+					return ctrl.Result{Requeue: true}, nil
+				}
+				return ctrl.Result{}, fmt.Errorf("unexpected error; binding not found in cloud foundry although it should exist")
+			}
+		}
+
+		// Update status
+		previousServiceBindingGuid := status.ServiceBindingGuid
+		previousServiceInstanceDigest := status.ServiceInstanceDigest
+		r.updateStatus(clusterServiceBinding, cfbinding, spaceGuid)
+		status.ServiceInstanceDigest = serviceInstance.Status.ServiceInstanceDigest
+		switch cfbinding.State {
+		case facade.BindingStateReady:
+			clusterServiceBinding.SetReadyCondition(cfv1alpha1.ConditionTrue, string(cfbinding.State), cfbinding.StateDescription)
+			clusterServiceBinding.SetCondition(cfv1alpha1.ClusterServiceBindingConditionSynced, cfv1alpha1.ConditionTrue, conditionReasonSynced, "Cloud Foundry binding reflects the current spec")
+			// credentials are only read from Cloud Foundry if the binding secret has to be (re)written
+			rewrite := previousServiceBindingGuid != cfbinding.Guid || previousServiceInstanceDigest != status.ServiceInstanceDigest
+			if !rewrite {
+				rewrite, err = r.isBindingSecretOutdated(ctx, clusterServiceBinding, secretName)
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			if rewrite {
+				log.V(1).Info("Reading binding credentials")
+				credentials, err := client.GetBindingCredentials(ctx, cfbinding.Guid)
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+				if err := r.storeBindingSecret(ctx, serviceInstance, clusterServiceBinding, credentials, secretName); err != nil {
+					clusterServiceBinding.SetCondition(cfv1alpha1.ClusterServiceBindingConditionCredentialsReady, cfv1alpha1.ConditionFalse, conditionReasonError, err.Error())
+					return ctrl.Result{RequeueAfter: 10 * time.Minute}, nil
+				}
+				clusterServiceBinding.SetCondition(cfv1alpha1.ClusterServiceBindingConditionCredentialsReady, cfv1alpha1.ConditionTrue, conditionReasonSecretStored, "Credentials stored in secret "+secretName.String())
+				status.CredentialsDigest = facade.ObjectHash(map[string]interface{}{"uid": string(clusterServiceBinding.UID), "credentials": credentials})
+				status.CredentialKeys = binding.CredentialKeys(credentials)
+			}
+			// TODO: apply some increasing period, depending on the age of the last update
+			return getPollingInterval(annotations, getDefaultPollingIntervalReady(r.Config, "ClusterServiceBinding"), cfv1alpha1.AnnotationPollingIntervalReady), nil
+		case facade.BindingStateCreatedFailed, facade.BindingStateDeleteFailed:
+			clusterServiceBinding.SetReadyCondition(cfv1alpha1.ConditionFalse, string(cfbinding.State), cfbinding.StateDescription)
+			clusterServiceBinding.SetCondition(cfv1alpha1.ClusterServiceBindingConditionSynced, cfv1alpha1.ConditionFalse, string(cfbinding.State), cfbinding.StateDescription)
+			// the failed binding is re-created with the next attempt
+			// TODO: apply some increasing period, depending on the age of the last update
+			return ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
+		default:
+			clusterServiceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, string(cfbinding.State), cfbinding.StateDescription)
+			clusterServiceBinding.SetCondition(cfv1alpha1.ClusterServiceBindingConditionSynced, cfv1alpha1.ConditionUnknown, string(cfbinding.State), cfbinding.StateDescription)
+			// TODO: apply some increasing period, depending on the age of the last update
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+	} else if len(removeString(clusterServiceBinding.Finalizers, clusterServiceBindingFinalizer)) > 0 {
+		clusterServiceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, clusterServiceBindingReadyConditionReasonDeletionBlocked, "Deletion blocked due to foreign finalizers")
+		clusterServiceBinding.SetCondition(cfv1alpha1.ClusterServiceBindingConditionDeletionBlocked, cfv1alpha1.ConditionTrue, conditionReasonForeignFinalizers, "Deletion blocked due to foreign finalizers")
+		// TODO: apply some increasing period, depending on the age of the last update
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	} else {
+		// Deletion case; the binding secret is deleted first, such that consumers do not keep using credentials which are about to be revoked
+		secretGone, err := r.deleteBindingSecret(ctx, clusterServiceBinding, secretName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !secretGone {
+			clusterServiceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, clusterServiceBindingReadyConditionReasonDeletionBlocked, "Waiting for deletion of binding secret")
+			clusterServiceBinding.SetCondition(cfv1alpha1.ClusterServiceBindingConditionDeletionBlocked, cfv1alpha1.ConditionTrue, conditionReasonDependentsExist, "Waiting for deletion of binding secret")
+			// TODO: apply some increasing period, depending on the age of the last update
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		clusterServiceBinding.RemoveCondition(cfv1alpha1.ClusterServiceBindingConditionDeletionBlocked)
+		if cfbinding == nil {
+			if containsString(clusterServiceBinding.Finalizers, clusterServiceBindingFinalizer) {
+				controllerutil.RemoveFinalizer(clusterServiceBinding, clusterServiceBindingFinalizer)
+				if err := r.Update(ctx, clusterServiceBinding); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			// skip status update, since the cluster service binding will anyway deleted timely by the API server
+			skipStatusUpdate = true
+			return ctrl.Result{}, nil
+		}
+		if cfbinding.State != facade.BindingStateDeleting {
+			log.V(1).Info("Deleting binding")
+			if err := client.DeleteBinding(ctx, cfbinding.Guid, cfbinding.OwnerRef()); err != nil {
+				return ctrl.Result{}, err
+			}
+			status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
+			cfbinding.State = facade.BindingStateUnknown
+			cfbinding.StateDescription = "Deletion triggered."
+		}
+		clusterServiceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, string(cfbinding.State), cfbinding.StateDescription)
+		// TODO: apply some increasing period, depending on the age of the last update
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+}
+
+func (r *ClusterServiceBindingReconciler) updateStatus(clusterServiceBinding *cfv1alpha1.ClusterServiceBinding, cfbinding *facade.Binding, spaceGuid string) {
+	status := &clusterServiceBinding.Status
+	status.SpaceGuid = spaceGuid
+	status.ServiceInstanceGuid = cfbinding.ServiceInstanceGuid
+	status.ServiceBindingGuid = cfbinding.Guid
+	status.LastOperation = getLastOperation(status.LastOperation, cfbinding.LastOperation)
+}
+
+// isBindingSecretOutdated checks whether the binding secret has to be rewritten, because the credentials were not yet stored
+// for the current generation, or because the secret is missing (or being deleted).
+func (r *ClusterServiceBindingReconciler) isBindingSecretOutdated(ctx context.Context, clusterServiceBinding *cfv1alpha1.ClusterServiceBinding, secretName types.NamespacedName) (bool, error) {
+	condition := clusterServiceBinding.GetCondition(cfv1alpha1.ClusterServiceBindingConditionCredentialsReady)
+	if clusterServiceBinding.Status.CredentialsDigest == "" || condition == nil || condition.Status != cfv1alpha1.ConditionTrue || condition.ObservedGeneration != clusterServiceBinding.Generation {
+		return true, nil
+	}
+	secret, err := r.getBindingSecret(ctx, clusterServiceBinding, secretName)
+	if err != nil {
+		return false, err
+	}
+	return secret == nil || !secret.DeletionTimestamp.IsZero(), nil
+}
+
+// getBindingSecret returns the given binding secret, or nil if it does not exist, or is not controlled by the cluster service binding.
+func (r *ClusterServiceBindingReconciler) getBindingSecret(ctx context.Context, clusterServiceBinding *cfv1alpha1.ClusterServiceBinding, secretName types.NamespacedName) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, secretName, secret); err != nil {
+		if err := client.IgnoreNotFound(err); err != nil {
+			return nil, errors.Wrap(err, "failed to read binding secret")
+		}
+		return nil, nil
+	}
+	if !metav1.IsControlledBy(secret, clusterServiceBinding) {
+		return nil, nil
+	}
+	return secret, nil
+}
+
+// deleteBindingSecret deletes the binding secret (if it exists and is controlled by the cluster service binding);
+// it returns true if the secret is gone, false if its deletion is still blocked by finalizers.
+func (r *ClusterServiceBindingReconciler) deleteBindingSecret(ctx context.Context, clusterServiceBinding *cfv1alpha1.ClusterServiceBinding, secretName types.NamespacedName) (bool, error) {
+	secret, err := r.getBindingSecret(ctx, clusterServiceBinding, secretName)
+	if err != nil {
+		return false, err
+	}
+	if secret == nil {
+		return true, nil
+	}
+	if secret.DeletionTimestamp.IsZero() {
+		if err := r.Delete(ctx, secret, client.Preconditions{UID: &secret.UID}); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return false, errors.Wrap(err, "failed to delete binding secret")
+			}
+			return true, nil
+		}
+	}
+	return len(secret.Finalizers) == 0, nil
+}
+
+// storeBindingSecret creates or updates the binding secret; the secret is owned (controlled) by the cluster service binding
+// (which is possible across namespaces, since the owner is cluster-scoped), and existing secrets not controlled by it are never overwritten.
+func (r *ClusterServiceBindingReconciler) storeBindingSecret(ctx context.Context, serviceInstance *cfv1alpha1.ServiceInstance, clusterServiceBinding *cfv1alpha1.ClusterServiceBinding, credentials map[string]interface{}, secretName types.NamespacedName) error {
+	spec := &clusterServiceBinding.Spec
+	// the secret namespace is validated by the webhook on creation; the configuration might have changed since
+	if spec.SecretNamespace != spec.ServiceInstanceRef.Namespace && (r.Config == nil || !slices.Contains(r.Config.SecretNamespaces, spec.SecretNamespace)) {
+		return fmt.Errorf("binding secret must not be stored in namespace %s: namespace not allowed by the operator configuration (secretNamespaces)", spec.SecretNamespace)
+	}
+	withMetadata := r.EnableBindingMetadata
+	result := binding.NewBinding(serviceInstance, nil, credentials)
+	if metadata := spec.SAPBindingMetadata; metadata != nil {
+		if metadata.Enabled != nil {
+			withMetadata = *metadata.Enabled
+		}
+		result = result.WithMetadataFields(metadata.Fields)
+	}
+	data, err := result.SecretData(spec.SecretKey, withMetadata)
+	if err != nil {
+		return errors.Wrap(err, "failed to build binding secret")
+	}
+	secretType := corev1.SecretTypeOpaque
+	if spec.SecretType != "" {
+		secretType = corev1.SecretType(spec.SecretType)
+	}
+	labels := map[string]string{cfv1alpha1.LabelKeyClusterServiceBinding: clusterServiceBinding.Name}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, secretName, secret); err != nil {
+		if err := client.IgnoreNotFound(err); err != nil {
+			return errors.Wrap(err, "failed to read binding secret")
+		}
+		secret = nil
+	} else {
+		if !metav1.IsControlledBy(secret, clusterServiceBinding) {
+			return fmt.Errorf("failed to update binding secret: secret %s exists and was not written by this binding", secretName)
+		}
+		// the type of a secret cannot be changed; such secrets are recreated
+		existingType := secret.Type
+		if existingType == "" {
+			existingType = corev1.SecretTypeOpaque
+		}
+		if existingType != secretType {
+			if err := r.Delete(ctx, secret, client.Preconditions{UID: &secret.UID}); client.IgnoreNotFound(err) != nil {
+				return errors.Wrap(err, "failed to delete binding secret for recreation")
+			}
+			secret = nil
+		}
+	}
+
+	if secret == nil {
+		secret = &corev1.Secret{}
+		secret.Namespace = secretName.Namespace
+		secret.Name = secretName.Name
+		if err := controllerutil.SetControllerReference(clusterServiceBinding, secret, r.Scheme); err != nil {
+			return errors.Wrap(err, "failed to create binding secret")
+		}
+		secret.Labels = labels
+		secret.Type = secretType
+		secret.Data = data
+		if err := r.Create(ctx, secret); err != nil {
+			return errors.Wrap(err, "failed to create binding secret")
+		}
+	} else if !maps.Equal(secret.Labels, labels) || !maps.EqualFunc(secret.Data, data, bytes.Equal) {
+		// idempotent updates are skipped, since every update would wake up all watchers of the secret
+		secret.Labels = labels
+		secret.Data = data
+		if err := r.Update(ctx, secret); err != nil {
+			return errors.Wrap(err, "failed to update binding secret")
+		}
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterServiceBindingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	reconciler, options := limitConcurrency(traceReconciles(measureReconciles(labelReconciles(r, "ClusterServiceBinding"), "ClusterServiceBinding"), "ClusterServiceBinding"), r.Config, nil)
+	reconciler, namespacePredicate := filterNamespaces(reconciler, mgr.GetClient(), r.Config)
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&cfv1alpha1.ClusterServiceBinding{}).
+		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})).
+		WithEventFilter(namespacePredicate).
+		WithOptions(options).
+		Complete(reconciler)
+}
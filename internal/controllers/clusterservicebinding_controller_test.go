@@ -0,0 +1,217 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+	"github.com/sap/cf-service-operator/internal/facade/facadefakes"
+)
+
+var _ = Describe("Reconcile cluster service bindings | ClusterServiceBindingReconciler", func() {
+	ctx := context.Background()
+	bindingKey := types.NamespacedName{Name: "cluster-binding"}
+	secretKey := types.NamespacedName{Namespace: "kube-system", Name: "cluster-binding"}
+	var spaceClient *facadefakes.FakeSpaceClient
+
+	newServiceInstance := func(clusterSpaceName string) *cfv1alpha1.ServiceInstance {
+		serviceInstance := &cfv1alpha1.ServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "instance", Generation: 1},
+			Spec:       cfv1alpha1.ServiceInstanceSpec{ClusterSpaceName: clusterSpaceName},
+			Status:     cfv1alpha1.ServiceInstanceStatus{ServiceInstanceGuid: "instance-guid", ServicePlanGuid: "plan-guid"},
+		}
+		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionTrue, "Ready", "")
+		return serviceInstance
+	}
+
+	newReconciler := func(objects ...client.Object) *ClusterServiceBindingReconciler {
+		cfg := config.Defaults()
+		cfg.SecretNamespaces = []string{secretKey.Namespace}
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		space := &cfv1alpha1.ClusterSpace{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-space"},
+			Spec:       cfv1alpha1.SpaceSpec{Guid: "space-guid", AuthSecretName: "space-secret"},
+		}
+		space.SetReadyCondition(cfv1alpha1.ConditionTrue, "Ready", "")
+		objects = append(objects,
+			space,
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-ns", Name: "space-secret"},
+				Data:       map[string][]byte{"url": []byte("https://api.cf.example.com")},
+			},
+		)
+		return &ClusterServiceBindingReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(objects...).
+				WithStatusSubresource(&cfv1alpha1.ClusterServiceBinding{}).
+				Build(),
+			Scheme:                   scheme,
+			ClusterResourceNamespace: "cluster-ns",
+			ClientBuilder: func(string, string, string, string, *config.Config) (facade.SpaceClient, error) {
+				return spaceClient, nil
+			},
+			Config: cfg,
+		}
+	}
+
+	newClusterServiceBinding := func() *cfv1alpha1.ClusterServiceBinding {
+		clusterServiceBinding := &cfv1alpha1.ClusterServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: bindingKey.Name, UID: "binding-uid", Generation: 1},
+			Spec: cfv1alpha1.ClusterServiceBindingSpec{
+				ServiceInstanceRef: cfv1alpha1.ServiceInstanceReference{Namespace: "ns", Name: "instance"},
+				SecretNamespace:    secretKey.Namespace,
+			},
+		}
+		clusterServiceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, clusterServiceBindingReadyConditionReasonNew, "First seen")
+		return clusterServiceBinding
+	}
+
+	BeforeEach(func() {
+		spaceClient = &facadefakes.FakeSpaceClient{}
+	})
+
+	It("should create the binding, and store the credentials in a secret owned by the cluster service binding", func() {
+		reconciler := newReconciler(newClusterServiceBinding(), newServiceInstance("cluster-space"))
+		cfbinding := &facade.Binding{Guid: "binding-guid", ServiceInstanceGuid: "instance-guid", Owner: "binding-uid", Generation: 1, State: facade.BindingStateReady, ParameterHash: facade.ObjectHash(nil)}
+		spaceClient.GetBindingReturnsOnCall(0, nil, nil)
+		spaceClient.GetBindingReturnsOnCall(1, cfbinding, nil)
+		spaceClient.CreateBindingReturns(cfbinding, nil)
+		spaceClient.GetBindingCredentialsReturns(map[string]interface{}{"user": "admin"}, nil)
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: bindingKey})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(spaceClient.CreateBindingCallCount()).To(Equal(1))
+		_, name, serviceInstanceGuid, appGuid, _, _, owner, _ := spaceClient.CreateBindingArgsForCall(0)
+		Expect([]interface{}{name, serviceInstanceGuid, appGuid, owner.UID}).To(Equal([]interface{}{"cluster-binding", "instance-guid", "", "binding-uid"}))
+		clusterServiceBinding := &cfv1alpha1.ClusterServiceBinding{}
+		Expect(reconciler.Get(ctx, bindingKey, clusterServiceBinding)).To(Succeed())
+		Expect(clusterServiceBinding.IsReady()).To(BeTrue())
+		Expect(clusterServiceBinding.Status.ServiceBindingGuid).To(Equal("binding-guid"))
+		Expect(clusterServiceBinding.GetCondition(cfv1alpha1.ClusterServiceBindingConditionCredentialsReady).Status).To(Equal(cfv1alpha1.ConditionTrue))
+		secret := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, secretKey, secret)).To(Succeed())
+		Expect(metav1.IsControlledBy(secret, clusterServiceBinding)).To(BeTrue())
+		Expect(secret.Labels).To(HaveKeyWithValue(cfv1alpha1.LabelKeyClusterServiceBinding, bindingKey.Name))
+		Expect(secret.Data).To(HaveKeyWithValue("user", []byte("admin")))
+	})
+
+	It("should neither read nor change the binding while paused", func() {
+		clusterServiceBinding := newClusterServiceBinding()
+		clusterServiceBinding.Annotations = map[string]string{cfv1alpha1.AnnotationPaused: "true"}
+		reconciler := newReconciler(clusterServiceBinding, newServiceInstance("cluster-space"))
+
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: bindingKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(ctrl.Result{}))
+
+		Expect(spaceClient.Invocations()).To(BeEmpty())
+		Expect(reconciler.Get(ctx, bindingKey, clusterServiceBinding)).To(Succeed())
+		Expect(clusterServiceBinding.Finalizers).To(BeEmpty())
+		Expect(clusterServiceBinding.GetCondition(cfv1alpha1.ClusterServiceBindingConditionPaused)).ToNot(BeNil())
+		Expect(clusterServiceBinding.GetCondition(cfv1alpha1.ClusterServiceBindingConditionPaused).Status).To(Equal(cfv1alpha1.ConditionTrue))
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, secretKey, &corev1.Secret{}))).To(BeTrue())
+
+		// resume
+		delete(clusterServiceBinding.Annotations, cfv1alpha1.AnnotationPaused)
+		Expect(reconciler.Update(ctx, clusterServiceBinding)).To(Succeed())
+		result, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: bindingKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+		Expect(reconciler.Get(ctx, bindingKey, clusterServiceBinding)).To(Succeed())
+		Expect(clusterServiceBinding.GetCondition(cfv1alpha1.ClusterServiceBindingConditionPaused)).To(BeNil())
+	})
+
+	It("should not store the binding secret in namespaces not allowed by the configuration", func() {
+		reconciler := newReconciler(newClusterServiceBinding(), newServiceInstance("cluster-space"))
+		reconciler.Config.SecretNamespaces = nil
+		cfbinding := &facade.Binding{Guid: "binding-guid", ServiceInstanceGuid: "instance-guid", Owner: "binding-uid", Generation: 1, State: facade.BindingStateReady, ParameterHash: facade.ObjectHash(nil)}
+		spaceClient.GetBindingReturns(cfbinding, nil)
+		spaceClient.GetBindingCredentialsReturns(map[string]interface{}{"user": "admin"}, nil)
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: bindingKey})
+		Expect(err).ToNot(HaveOccurred())
+
+		clusterServiceBinding := &cfv1alpha1.ClusterServiceBinding{}
+		Expect(reconciler.Get(ctx, bindingKey, clusterServiceBinding)).To(Succeed())
+		condition := clusterServiceBinding.GetCondition(cfv1alpha1.ClusterServiceBindingConditionCredentialsReady)
+		Expect(condition.Status).To(Equal(cfv1alpha1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("namespace not allowed"))
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, secretKey, &corev1.Secret{}))).To(BeTrue())
+	})
+
+	It("should delete the binding secret and release the binding if the service instance is gone", func() {
+		clusterServiceBinding := newClusterServiceBinding()
+		clusterServiceBinding.Finalizers = []string{clusterServiceBindingFinalizer, "other"}
+		clusterServiceBinding.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+		reconciler := newReconciler(clusterServiceBinding)
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: secretKey.Namespace, Name: secretKey.Name}}
+		Expect(reconciler.Get(ctx, bindingKey, clusterServiceBinding)).To(Succeed())
+		Expect(ctrl.SetControllerReference(clusterServiceBinding, secret, reconciler.Scheme)).To(Succeed())
+		Expect(reconciler.Create(ctx, secret)).To(Succeed())
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: bindingKey})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(spaceClient.Invocations()).To(BeEmpty())
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, secretKey, &corev1.Secret{}))).To(BeTrue())
+		Expect(reconciler.Get(ctx, bindingKey, clusterServiceBinding)).To(Succeed())
+		Expect(clusterServiceBinding.Finalizers).To(Equal([]string{"other"}))
+	})
+
+	It("should not bind service instances which do not belong to a cluster space", func() {
+		reconciler := newReconciler(newClusterServiceBinding(), newServiceInstance(""))
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: bindingKey})
+		Expect(err).To(MatchError(ContainSubstring("does not belong to a ClusterSpace")))
+
+		Expect(spaceClient.Invocations()).To(BeEmpty())
+		clusterServiceBinding := &cfv1alpha1.ClusterServiceBinding{}
+		Expect(reconciler.Get(ctx, bindingKey, clusterServiceBinding)).To(Succeed())
+		Expect(clusterServiceBinding.Status.State).To(Equal(cfv1alpha1.ClusterServiceBindingStateError))
+	})
+
+	It("should delete the binding secret before the binding, and not touch foreign secrets", func() {
+		clusterServiceBinding := newClusterServiceBinding()
+		clusterServiceBinding.Finalizers = []string{clusterServiceBindingFinalizer}
+		clusterServiceBinding.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+		foreignSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: secretKey.Namespace, Name: secretKey.Name}}
+		reconciler := newReconciler(clusterServiceBinding, newServiceInstance("cluster-space"), foreignSecret)
+		spaceClient.GetBindingReturns(&facade.Binding{Guid: "binding-guid", Owner: "binding-uid", Generation: 1, State: facade.BindingStateReady}, nil)
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: bindingKey})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(spaceClient.DeleteBindingCallCount()).To(Equal(1))
+		Expect(reconciler.Get(ctx, secretKey, &corev1.Secret{})).To(Succeed())
+
+		// a secret written by the cluster service binding is deleted
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "owned-secret"}}
+		Expect(reconciler.Get(ctx, bindingKey, clusterServiceBinding)).To(Succeed())
+		Expect(ctrl.SetControllerReference(clusterServiceBinding, secret, reconciler.Scheme)).To(Succeed())
+		Expect(reconciler.Create(ctx, secret)).To(Succeed())
+		Expect(reconciler.deleteBindingSecret(ctx, clusterServiceBinding, client.ObjectKeyFromObject(secret))).To(BeTrue())
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, client.ObjectKeyFromObject(secret), secret))).To(BeTrue())
+	})
+})
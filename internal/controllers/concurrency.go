@@ -0,0 +1,215 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/sap/cf-service-operator/internal/config"
+)
+
+// limiters of all controllers set up so far, adjusted by SetMaxConcurrentReconciles; guarded by concurrencyLimitersMutex
+var (
+	concurrencyLimitersMutex sync.Mutex
+	concurrencyLimiters      []*concurrencyLimiter
+)
+
+// SetMaxConcurrentReconciles changes the maximum number of concurrent reconciles of all controllers
+// (bounded by config.MaxConcurrentReconcilesLimit); takes effect for reconciles started afterwards.
+func SetMaxConcurrentReconciles(limit int) {
+	concurrencyLimitersMutex.Lock()
+	defer concurrencyLimitersMutex.Unlock()
+
+	for _, l := range concurrencyLimiters {
+		l.setLimit(limit)
+	}
+}
+
+// SetMaxConcurrentReconcilesPerEndpoint changes the maximum number of concurrent reconciles of all controllers for objects
+// targeting the same Cloud Foundry API endpoint (zero means no limit); takes effect for reconciles started afterwards.
+func SetMaxConcurrentReconcilesPerEndpoint(limit int) {
+	concurrencyLimitersMutex.Lock()
+	defer concurrencyLimitersMutex.Unlock()
+
+	for _, l := range concurrencyLimiters {
+		l.setEndpointLimit(limit)
+	}
+}
+
+// Interval after which reconciles deferred because their endpoint reached its concurrency limit are retried
+const endpointBusyRequeueInterval = 5 * time.Second
+
+// endpointFunc returns the Cloud Foundry API endpoint targeted by the object of the given request, or the empty string if unknown.
+type endpointFunc func(ctx context.Context, req ctrl.Request) string
+
+// concurrencyLimiter bounds the number of concurrently running reconciles of one controller.
+// Unlike the MaxConcurrentReconciles option of controller-runtime (which is fixed once the controller is started),
+// the limit can be changed at runtime.
+// In addition, the reconciles of objects targeting the same Cloud Foundry API endpoint may be bounded (if an endpoint function is given);
+// reconciles exceeding that limit do not wait (which would block a worker), but are requeued, so that reconciles of objects targeting
+// other endpoints can proceed.
+type concurrencyLimiter struct {
+	reconciler reconcile.Reconciler
+	endpointOf endpointFunc
+	mutex      sync.Mutex
+	limit      int
+	active     int
+	// closed (and replaced) whenever a slot may have become available
+	released chan struct{}
+	// limit of concurrent reconciles per endpoint (zero means no limit), and number of active reconciles by endpoint
+	endpointLimit  int
+	endpointActive map[string]int
+}
+
+// limitConcurrency wraps the given reconciler into a concurrency limiter, with the initial limits taken from the configuration;
+// the returned options must be passed to the controller, such that enough workers are started. The endpoint function may be nil,
+// if the objects of the controller do not report their endpoint.
+func limitConcurrency(r reconcile.Reconciler, cfg *config.Config, endpointOf endpointFunc) (reconcile.Reconciler, controller.Options) {
+	limit := 1
+	endpointLimit := 0
+	if cfg != nil && cfg.MaxConcurrentReconciles > 0 {
+		limit = cfg.MaxConcurrentReconciles
+	}
+	if cfg != nil {
+		endpointLimit = cfg.MaxConcurrentReconcilesPerEndpoint
+	}
+	l := &concurrencyLimiter{reconciler: r, endpointOf: endpointOf, released: make(chan struct{}), endpointActive: make(map[string]int)}
+	l.setLimit(limit)
+	l.setEndpointLimit(endpointLimit)
+
+	concurrencyLimitersMutex.Lock()
+	defer concurrencyLimitersMutex.Unlock()
+	concurrencyLimiters = append(concurrencyLimiters, l)
+
+	return l, controller.Options{MaxConcurrentReconciles: config.MaxConcurrentReconcilesLimit}
+}
+
+func (l *concurrencyLimiter) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// the endpoint is only determined if reconciles per endpoint are limited at all
+	if l.endpointOf != nil && l.hasEndpointLimit() {
+		endpoint := l.endpointOf(ctx, req)
+		if !l.acquireEndpoint(endpoint) {
+			deferredReconciles.WithLabelValues(endpoint).Inc()
+			return ctrl.Result{RequeueAfter: endpointBusyRequeueInterval}, nil
+		}
+		defer l.releaseEndpoint(endpoint)
+	}
+	if err := l.acquire(ctx); err != nil {
+		return ctrl.Result{}, err
+	}
+	defer l.release()
+	return l.reconciler.Reconcile(ctx, req)
+}
+
+func (l *concurrencyLimiter) acquire(ctx context.Context) error {
+	for {
+		l.mutex.Lock()
+		if l.active < l.limit {
+			l.active++
+			l.mutex.Unlock()
+			return nil
+		}
+		released := l.released
+		l.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-released:
+		}
+	}
+}
+
+func (l *concurrencyLimiter) release() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.active--
+	l.notify()
+}
+
+// acquireEndpoint takes a slot of the given endpoint, unless the endpoint reached its limit; reconciles of objects
+// with unknown endpoint are not limited.
+func (l *concurrencyLimiter) acquireEndpoint(endpoint string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if endpoint == "" {
+		return true
+	}
+	if l.endpointLimit > 0 && l.endpointActive[endpoint] >= l.endpointLimit {
+		return false
+	}
+	l.endpointActive[endpoint]++
+	return true
+}
+
+func (l *concurrencyLimiter) releaseEndpoint(endpoint string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if endpoint == "" {
+		return
+	}
+	if l.endpointActive[endpoint] <= 1 {
+		delete(l.endpointActive, endpoint)
+	} else {
+		l.endpointActive[endpoint]--
+	}
+}
+
+func (l *concurrencyLimiter) hasEndpointLimit() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.endpointLimit > 0
+}
+
+func (l *concurrencyLimiter) setEndpointLimit(limit int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.endpointLimit = max(limit, 0)
+}
+
+func (l *concurrencyLimiter) setLimit(limit int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if limit < 1 {
+		limit = 1
+	} else if limit > config.MaxConcurrentReconcilesLimit {
+		limit = config.MaxConcurrentReconcilesLimit
+	}
+	l.limit = limit
+	l.notify()
+}
+
+// Must be called with mutex locked.
+func (l *concurrencyLimiter) notify() {
+	close(l.released)
+	l.released = make(chan struct{})
+}
+
+// endpointFromStatus returns an endpoint function reading the endpoint from the status of the object (of the type given by newObject)
+// of the request, as reported by its most recent reconcile; the given reader should be the manager's cache (a slightly outdated
+// endpoint does no harm, whereas an uncached read would cost an API server round trip per reconcile).
+func endpointFromStatus(c client.Reader, newObject func() client.Object, endpoint func(client.Object) string) endpointFunc {
+	return func(ctx context.Context, req ctrl.Request) string {
+		obj := newObject()
+		if err := c.Get(ctx, req.NamespacedName, obj); err != nil {
+			return ""
+		}
+		return endpoint(obj)
+	}
+}
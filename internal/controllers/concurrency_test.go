@@ -0,0 +1,141 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/sap/cf-service-operator/internal/config"
+)
+
+var _ = Describe("Limit concurrent reconciles | concurrencyLimiter", func() {
+	It("should bound concurrent reconciles, and apply limit changes at runtime", func() {
+		var running, maxRunning atomic.Int32
+		unblock := make(chan struct{})
+		r := reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			<-unblock
+			return ctrl.Result{}, nil
+		})
+		cfg := config.Defaults()
+		cfg.MaxConcurrentReconciles = 2
+		limited, options := limitConcurrency(r, cfg, nil)
+		Expect(options.MaxConcurrentReconciles).To(Equal(config.MaxConcurrentReconcilesLimit))
+		l := limited.(*concurrencyLimiter)
+
+		for i := 0; i < 4; i++ {
+			go func() {
+				defer GinkgoRecover()
+				_, err := limited.Reconcile(context.Background(), ctrl.Request{})
+				Expect(err).ToNot(HaveOccurred())
+			}()
+		}
+		Eventually(running.Load).Should(Equal(int32(2)))
+		Consistently(running.Load, 100*time.Millisecond).Should(Equal(int32(2)))
+
+		l.setLimit(4)
+		Eventually(running.Load).Should(Equal(int32(4)))
+		close(unblock)
+		Eventually(running.Load).Should(BeZero())
+		Expect(maxRunning.Load()).To(Equal(int32(4)))
+	})
+
+	It("should defer reconciles of objects targeting an endpoint which reached its limit, but not block other endpoints", func() {
+		unblock := make(chan struct{})
+		var running atomic.Int32
+		r := reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+			running.Add(1)
+			defer running.Add(-1)
+			if req.Name != "healthy" {
+				<-unblock
+			}
+			return ctrl.Result{}, nil
+		})
+		cfg := config.Defaults()
+		cfg.MaxConcurrentReconciles = 3
+		cfg.MaxConcurrentReconcilesPerEndpoint = 2
+		endpoints := map[string]string{"slow-1": "https://api.slow", "slow-2": "https://api.slow", "slow-3": "https://api.slow", "healthy": "https://api.healthy"}
+		limited, _ := limitConcurrency(r, cfg, func(ctx context.Context, req ctrl.Request) string { return endpoints[req.Name] })
+
+		for _, name := range []string{"slow-1", "slow-2"} {
+			go func() {
+				defer GinkgoRecover()
+				_, err := limited.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: name}})
+				Expect(err).ToNot(HaveOccurred())
+			}()
+		}
+		Eventually(running.Load).Should(Equal(int32(2)))
+
+		result, err := limited.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "slow-3"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(endpointBusyRequeueInterval))
+		result, err = limited.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "healthy"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+
+		limited.(*concurrencyLimiter).setEndpointLimit(0)
+		go func() {
+			defer GinkgoRecover()
+			_, err := limited.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "slow-3"}})
+			Expect(err).ToNot(HaveOccurred())
+		}()
+		Eventually(running.Load).Should(Equal(int32(3)))
+		close(unblock)
+		Eventually(running.Load).Should(BeZero())
+	})
+
+	It("should not determine the endpoint of objects unless reconciles per endpoint are limited", func() {
+		cfg := config.Defaults()
+		cfg.MaxConcurrentReconcilesPerEndpoint = 0
+		var calls atomic.Int32
+		limited, _ := limitConcurrency(reconcile.Func(func(context.Context, ctrl.Request) (ctrl.Result, error) {
+			return ctrl.Result{}, nil
+		}), cfg, func(ctx context.Context, req ctrl.Request) string {
+			calls.Add(1)
+			return "https://api.cf.example.com"
+		})
+
+		_, err := limited.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "object"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(calls.Load()).To(BeZero())
+
+		limited.(*concurrencyLimiter).setEndpointLimit(1)
+		_, err = limited.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "object"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(calls.Load()).To(Equal(int32(1)))
+		Expect(limited.(*concurrencyLimiter).endpointActive).To(BeEmpty())
+	})
+
+	It("should give up waiting if the context is cancelled", func() {
+		cfg := config.Defaults()
+		limited, _ := limitConcurrency(reconcile.Func(func(context.Context, ctrl.Request) (ctrl.Result, error) {
+			return ctrl.Result{}, nil
+		}), cfg, nil)
+		l := limited.(*concurrencyLimiter)
+		Expect(l.acquire(context.Background())).To(Succeed())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := limited.Reconcile(ctx, ctrl.Request{})
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		l.release()
+	})
+})
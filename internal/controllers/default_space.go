@@ -0,0 +1,42 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+)
+
+// DefaultSpaceResolver reads the default space of a namespace from the namespace annotations
+// service-operator.cf.cs.sap.com/default-space resp. service-operator.cf.cs.sap.com/default-cluster-space.
+// Implements cfv1alpha1.DefaultSpaceResolver; used by the mutating webhook.
+type DefaultSpaceResolver struct {
+	client.Client
+}
+
+var _ cfv1alpha1.DefaultSpaceResolver = &DefaultSpaceResolver{}
+
+// ResolveDefaultSpace returns the default Space or ClusterSpace of the given namespace (both empty if none is defined);
+// an error is returned if both are defined.
+func (r *DefaultSpaceResolver) ResolveDefaultSpace(ctx context.Context, namespace string) (string, string, error) {
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return "", "", errors.Wrapf(err, "failed to get namespace %s", namespace)
+	}
+	spaceName := ns.Annotations[cfv1alpha1.AnnotationDefaultSpace]
+	clusterSpaceName := ns.Annotations[cfv1alpha1.AnnotationDefaultClusterSpace]
+	if spaceName != "" && clusterSpaceName != "" {
+		return "", "", fmt.Errorf("namespace %s must not define both annotations %s and %s", namespace, cfv1alpha1.AnnotationDefaultSpace, cfv1alpha1.AnnotationDefaultClusterSpace)
+	}
+	return spaceName, clusterSpaceName, nil
+}
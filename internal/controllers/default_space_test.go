@@ -0,0 +1,60 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+)
+
+var _ = Describe("Default space of a namespace | ResolveDefaultSpace", func() {
+	ctx := context.Background()
+	var resolver *DefaultSpaceResolver
+
+	BeforeEach(func() {
+		resolver = &DefaultSpaceResolver{Client: fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "plain"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Annotations: map[string]string{cfv1alpha1.AnnotationDefaultSpace: "dev"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Annotations: map[string]string{cfv1alpha1.AnnotationDefaultClusterSpace: "shared"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-c", Annotations: map[string]string{
+				cfv1alpha1.AnnotationDefaultSpace:        "dev",
+				cfv1alpha1.AnnotationDefaultClusterSpace: "shared",
+			}}},
+		).Build()}
+	})
+
+	It("should return the default space or cluster space", func() {
+		spaceName, clusterSpaceName, err := resolver.ResolveDefaultSpace(ctx, "team-a")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(spaceName).To(Equal("dev"))
+		Expect(clusterSpaceName).To(BeEmpty())
+
+		spaceName, clusterSpaceName, err = resolver.ResolveDefaultSpace(ctx, "team-b")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(spaceName).To(BeEmpty())
+		Expect(clusterSpaceName).To(Equal("shared"))
+	})
+
+	It("should return nothing if the namespace defines no default space", func() {
+		spaceName, clusterSpaceName, err := resolver.ResolveDefaultSpace(ctx, "plain")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(spaceName).To(BeEmpty())
+		Expect(clusterSpaceName).To(BeEmpty())
+	})
+
+	It("should reject ambiguous defaults", func() {
+		_, _, err := resolver.ResolveDefaultSpace(ctx, "team-c")
+		Expect(err).To(MatchError(ContainSubstring("must not define both annotations")))
+	})
+})
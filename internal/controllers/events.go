@@ -0,0 +1,145 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/events"
+)
+
+const (
+	// index of service instances by status.spaceGuid
+	indexServiceInstanceSpaceGuid = "status.spaceGuid"
+	// index of service bindings by status.serviceInstanceGuid
+	indexServiceBindingInstanceGuid = "status.serviceInstanceGuid"
+
+	// number of Cloud Foundry resource events buffered per controller
+	resourceEventBufferSize = 1024
+)
+
+// resourceEventSource adapts the Cloud Foundry resource events published on the event bus to a controller source;
+// for every event accepted by filter, mapFunc determines the objects to be enqueued.
+type resourceEventSource struct {
+	bus     *events.Bus
+	filter  events.Filter
+	mapFunc func(ctx context.Context, event events.Event) ([]client.Object, error)
+}
+
+var _ source.Source = &resourceEventSource{}
+
+func (s *resourceEventSource) String() string {
+	return fmt.Sprintf("cf resource event source: %p", s)
+}
+
+// Start implements source.Source; events are consumed until ctx is done.
+func (s *resourceEventSource) Start(ctx context.Context, eventHandler handler.EventHandler, queue workqueue.RateLimitingInterface, predicates ...predicate.Predicate) error {
+	ch, unsubscribe := s.bus.Subscribe(resourceEventBufferSize, s.filter)
+	go func() {
+		defer unsubscribe()
+		log := log.FromContext(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case resourceEvent := <-ch:
+				objects, err := s.mapFunc(ctx, resourceEvent)
+				if err != nil {
+					log.Error(err, "failed to determine objects affected by Cloud Foundry resource event", "type", resourceEvent.Type, "resource", resourceEvent.ResourceType, "guid", resourceEvent.Guid)
+					continue
+				}
+			objectLoop:
+				for _, object := range objects {
+					genericEvent := event.GenericEvent{Object: object}
+					for _, p := range predicates {
+						if !p.Generic(genericEvent) {
+							continue objectLoop
+						}
+					}
+					eventHandler.Generic(ctx, genericEvent, queue)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// isResourceEvent returns a filter accepting events about the given resource type with one of the given event types.
+func isResourceEvent(resourceType events.ResourceType, eventTypes ...events.EventType) events.Filter {
+	return func(event events.Event) bool {
+		if event.ResourceType != resourceType {
+			return false
+		}
+		for _, eventType := range eventTypes {
+			if event.Type == eventType {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// serviceInstancesInSpace returns the service instances (of all namespaces) living in the space the event refers to;
+// the given reader must support the index by status.spaceGuid (i.e. it must be the manager's cache).
+func serviceInstancesInSpace(c client.Reader) func(context.Context, events.Event) ([]client.Object, error) {
+	return func(ctx context.Context, event events.Event) ([]client.Object, error) {
+		if event.Guid == "" {
+			return nil, nil
+		}
+		serviceInstanceList := &cfv1alpha1.ServiceInstanceList{}
+		if err := c.List(ctx, serviceInstanceList, client.MatchingFields{indexServiceInstanceSpaceGuid: event.Guid}); err != nil {
+			return nil, err
+		}
+		objects := make([]client.Object, len(serviceInstanceList.Items))
+		for i := range serviceInstanceList.Items {
+			objects[i] = &serviceInstanceList.Items[i]
+		}
+		return objects, nil
+	}
+}
+
+// serviceBindingsOfInstance returns the service bindings (of all namespaces) of the service instance the event refers to;
+// the given reader must support the index by status.serviceInstanceGuid (i.e. it must be the manager's cache).
+func serviceBindingsOfInstance(c client.Reader) func(context.Context, events.Event) ([]client.Object, error) {
+	return func(ctx context.Context, event events.Event) ([]client.Object, error) {
+		if event.Guid == "" {
+			return nil, nil
+		}
+		serviceBindingList := &cfv1alpha1.ServiceBindingList{}
+		if err := c.List(ctx, serviceBindingList, client.MatchingFields{indexServiceBindingInstanceGuid: event.Guid}); err != nil {
+			return nil, err
+		}
+		objects := make([]client.Object, len(serviceBindingList.Items))
+		for i := range serviceBindingList.Items {
+			objects[i] = &serviceBindingList.Items[i]
+		}
+		return objects, nil
+	}
+}
+
+func indexServiceInstanceBySpaceGuid(object client.Object) []string {
+	if guid := object.(*cfv1alpha1.ServiceInstance).Status.SpaceGuid; guid != "" {
+		return []string{guid}
+	}
+	return nil
+}
+
+func indexServiceBindingByInstanceGuid(object client.Object) []string {
+	if guid := object.(*cfv1alpha1.ServiceBinding).Status.ServiceInstanceGuid; guid != "" {
+		return []string{guid}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/events"
+)
+
+var _ = Describe("Enqueue objects affected by Cloud Foundry resource events | resourceEventSource", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var bus *events.Bus
+	var queue workqueue.RateLimitingInterface
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		bus = events.NewBus()
+		queue = workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+
+		scheme := runtime.NewScheme()
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithIndex(&cfv1alpha1.ServiceBinding{}, indexServiceBindingInstanceGuid, indexServiceBindingByInstanceGuid).
+			WithObjects(
+				&cfv1alpha1.ServiceBinding{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "binding"},
+					Status:     cfv1alpha1.ServiceBindingStatus{ServiceInstanceGuid: "instance-guid"},
+				},
+				&cfv1alpha1.ServiceBinding{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "other-binding"},
+					Status:     cfv1alpha1.ServiceBindingStatus{ServiceInstanceGuid: "other-instance-guid"},
+				},
+			).
+			Build()
+
+		src := &resourceEventSource{
+			bus:     bus,
+			filter:  isResourceEvent(events.ResourceTypeInstance, events.EventTypeUpdated, events.EventTypeDeleted),
+			mapFunc: serviceBindingsOfInstance(c),
+		}
+		Expect(src.Start(ctx, &handler.EnqueueRequestForObject{}, queue)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		cancel()
+		queue.ShutDown()
+	})
+
+	It("should enqueue the bindings of a deleted instance", func() {
+		bus.Publish(events.Event{Type: events.EventTypeDeleted, ResourceType: events.ResourceTypeInstance, Guid: "instance-guid"})
+
+		Eventually(queue.Len).Should(Equal(1))
+		item, _ := queue.Get()
+		Expect(item).To(Equal(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "binding"}}))
+	})
+
+	It("should ignore events not accepted by the filter", func() {
+		bus.Publish(events.Event{Type: events.EventTypeRefreshed, ResourceType: events.ResourceTypeInstance, Guid: "instance-guid"})
+		bus.Publish(events.Event{Type: events.EventTypeDeleted, ResourceType: events.ResourceTypeSpace, Guid: "instance-guid"})
+
+		Consistently(queue.Len, "100ms").Should(BeZero())
+	})
+})
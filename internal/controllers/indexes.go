@@ -0,0 +1,117 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+)
+
+// Field indexes used to look up the objects depending on an object (through the cache);
+// unlike the according labels, the indexed fields are set regardless of whether the defaulting webhooks are enabled.
+const (
+	// index of service instances by spec.spaceName
+	indexServiceInstanceSpaceName = "spec.spaceName"
+	// index of service instances by spec.clusterSpaceName
+	indexServiceInstanceClusterSpaceName = "spec.clusterSpaceName"
+	// index of service bindings referencing their service instance by guid, by spec.spaceName
+	indexServiceBindingSpaceName = "spec.spaceName"
+	// index of service bindings referencing their service instance by guid, by spec.clusterSpaceName
+	indexServiceBindingClusterSpaceName = "spec.clusterSpaceName"
+	// index of service bindings and route bindings by spec.serviceInstanceName
+	indexServiceInstanceName = "spec.serviceInstanceName"
+	// index of route bindings by spec.routeName
+	indexRouteName = "spec.routeName"
+	// index of cluster service bindings by spec.serviceInstanceRef (as namespace/name)
+	indexClusterServiceBindingServiceInstance = "spec.serviceInstanceRef"
+)
+
+// indexByField returns an indexer function indexing objects by the value returned by field (objects with empty value are not indexed).
+func indexByField[T client.Object](field func(T) string) client.IndexerFunc {
+	return func(object client.Object) []string {
+		if value := field(object.(T)); value != "" {
+			return []string{value}
+		}
+		return nil
+	}
+}
+
+// addServiceInstanceSpaceIndex registers the index of service instances by the name of their Space (or ClusterSpace, depending on kind).
+func addServiceInstanceSpaceIndex(mgr ctrl.Manager, kind string) error {
+	if kind == "ClusterSpace" {
+		return mgr.GetFieldIndexer().IndexField(context.Background(), &cfv1alpha1.ServiceInstance{}, indexServiceInstanceClusterSpaceName,
+			indexByField(func(serviceInstance *cfv1alpha1.ServiceInstance) string { return serviceInstance.Spec.ClusterSpaceName }))
+	}
+	return mgr.GetFieldIndexer().IndexField(context.Background(), &cfv1alpha1.ServiceInstance{}, indexServiceInstanceSpaceName,
+		indexByField(func(serviceInstance *cfv1alpha1.ServiceInstance) string { return serviceInstance.Spec.SpaceName }))
+}
+
+// addServiceBindingSpaceIndex registers the index of service bindings referencing their service instance by guid,
+// by the name of the Space (or ClusterSpace, depending on kind) of that instance.
+func addServiceBindingSpaceIndex(mgr ctrl.Manager, kind string) error {
+	if kind == "ClusterSpace" {
+		return mgr.GetFieldIndexer().IndexField(context.Background(), &cfv1alpha1.ServiceBinding{}, indexServiceBindingClusterSpaceName, indexServiceBindingByClusterSpaceName)
+	}
+	return mgr.GetFieldIndexer().IndexField(context.Background(), &cfv1alpha1.ServiceBinding{}, indexServiceBindingSpaceName, indexServiceBindingBySpaceName)
+}
+
+var indexServiceBindingBySpaceName = indexByField(func(serviceBinding *cfv1alpha1.ServiceBinding) string {
+	if serviceBinding.Spec.ServiceInstanceGuid == "" {
+		return ""
+	}
+	return serviceBinding.Spec.SpaceName
+})
+
+var indexServiceBindingByClusterSpaceName = indexByField(func(serviceBinding *cfv1alpha1.ServiceBinding) string {
+	if serviceBinding.Spec.ServiceInstanceGuid == "" {
+		return ""
+	}
+	return serviceBinding.Spec.ClusterSpaceName
+})
+
+// addServiceInstanceDependentIndexes registers the indexes of service bindings, route bindings and cluster service bindings by their service instance.
+func addServiceInstanceDependentIndexes(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &cfv1alpha1.ServiceBinding{}, indexServiceInstanceName,
+		indexByField(func(serviceBinding *cfv1alpha1.ServiceBinding) string { return serviceBinding.Spec.ServiceInstanceName })); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &cfv1alpha1.RouteBinding{}, indexServiceInstanceName,
+		indexByField(func(routeBinding *cfv1alpha1.RouteBinding) string { return routeBinding.Spec.ServiceInstanceName })); err != nil {
+		return err
+	}
+	return mgr.GetFieldIndexer().IndexField(context.Background(), &cfv1alpha1.ClusterServiceBinding{}, indexClusterServiceBindingServiceInstance,
+		indexByField(func(clusterServiceBinding *cfv1alpha1.ClusterServiceBinding) string {
+			return serviceInstanceRefKey(clusterServiceBinding.Spec.ServiceInstanceRef.Namespace, clusterServiceBinding.Spec.ServiceInstanceRef.Name)
+		}))
+}
+
+// serviceInstanceRefKey returns the value under which cluster service bindings referencing the given service instance are indexed.
+func serviceInstanceRefKey(namespace string, name string) string {
+	if namespace == "" || name == "" {
+		return ""
+	}
+	return namespace + "/" + name
+}
+
+// addRouteDependentIndexes registers the index of route bindings by the name of their route.
+func addRouteDependentIndexes(mgr ctrl.Manager) error {
+	return mgr.GetFieldIndexer().IndexField(context.Background(), &cfv1alpha1.RouteBinding{}, indexRouteName,
+		indexByField(func(routeBinding *cfv1alpha1.RouteBinding) string { return routeBinding.Spec.RouteName }))
+}
+
+// indexReader returns the reader serving lists by field index; since the manager's client reads service instances and service bindings
+// uncached (and the API server does not support field selectors on the indexed fields), these lists must go through the manager's cache.
+// The given client is used if no cache reader is set (i.e. if the reconciler was not set up with a manager).
+func indexReader(cacheReader client.Reader, c client.Client) client.Reader {
+	if cacheReader != nil {
+		return cacheReader
+	}
+	return c
+}
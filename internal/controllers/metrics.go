@@ -0,0 +1,238 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/audit"
+	"github.com/sap/cf-service-operator/internal/config"
+	cfmetrics "github.com/sap/cf-service-operator/pkg/metrics"
+)
+
+var (
+	serviceBindingCredentialsRotations = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "cf_service_binding_credentials_rotations_total",
+			Help: "The number of service binding credentials changes detected without re-creation of the binding (i.e. rotated on the broker side)",
+		},
+	)
+	serviceBindingSecretWrites = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cf_service_binding_secret_writes_total",
+			Help: "The number of actual writes of binding secrets (unchanged secrets are not written), by operation",
+		},
+		[]string{"operation"},
+	)
+	serviceInstanceStalledOperations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cf_service_instance_stalled_operations_total",
+			Help: "The number of operations on Cloud Foundry instances detected as stalled (in progress for longer than the stalled operation timeout), by operation type",
+		},
+		[]string{"operation"},
+	)
+	orphanedResources = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cf_orphaned_resources",
+			Help: "The number of orphaned Cloud Foundry resources (carrying the owner label, but lacking the owning object) found by the last scan, by kind",
+		},
+		[]string{"kind"},
+	)
+	deferredReconciles = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cf_deferred_reconciles_total",
+			Help: "The number of reconciles deferred because the targeted Cloud Foundry API endpoint reached its concurrency limit, by endpoint",
+		},
+		[]string{"endpoint"},
+	)
+	orphanedResourcesDeleted = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cf_orphaned_resources_deleted_total",
+			Help: "The number of orphaned Cloud Foundry resources deleted by the orphan collector, by kind",
+		},
+		[]string{"kind"},
+	)
+	reconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "cf_reconcile_duration_seconds",
+			Help:    "The duration of reconciles, by kind and result (success or error); observations of traced reconciles carry the trace id as exemplar",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+		},
+		[]string{"kind", "result"},
+	)
+)
+
+// descriptor of the metric reported by ResourceStateCollector
+var resourceStatesDesc = prometheus.NewDesc(
+	"cf_resources",
+	"The number of objects reconciled by this operator deployment, by kind and state (Unknown if not reconciled yet)",
+	[]string{"kind", "state"},
+	nil,
+)
+
+// states reported by ResourceStateCollector even if no object is in them, such that dashboards and alerts see zero instead of no data
+var resourceStates = []string{"Processing", "Deleting", "Ready", "Error"}
+
+// timeout for listing the objects of one kind when collecting the resource states
+const resourceStateListTimeout = 10 * time.Second
+
+func init() {
+	metrics.Registry.MustRegister(
+		serviceBindingCredentialsRotations,
+		serviceBindingSecretWrites,
+		serviceInstanceStalledOperations,
+		orphanedResources,
+		orphanedResourcesDeleted,
+		deferredReconciles,
+		reconcileDuration,
+	)
+}
+
+// labelReconciles wraps the given reconciler, such that requests against the Cloud Foundry API sent during a reconcile
+// are attributed to the controller (named after the given kind) in the HTTP client metrics (if the controller label is enabled),
+// and mutating calls are attributed to the reconciled object in the audit log.
+func labelReconciles(r reconcile.Reconciler, kind string) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		ctx = cfmetrics.WithLabelValues(ctx, map[string]string{cfmetrics.LabelController: kind})
+		ctx = audit.WithObject(ctx, audit.ObjectReference{Kind: kind, Namespace: req.Namespace, Name: req.Name})
+		return r.Reconcile(ctx, req)
+	})
+}
+
+// measureReconciles wraps the given reconciler, such that the duration of every reconcile is observed in cf_reconcile_duration_seconds
+// (labeled by the given kind); if the reconcile is traced (i.e. wrapped by traceReconciles), the trace id is attached as exemplar.
+func measureReconciles(r reconcile.Reconciler, kind string) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		start := time.Now()
+		result, err := r.Reconcile(ctx, req)
+		outcome := "success"
+		if err != nil {
+			outcome = "error"
+		}
+		observeWithTraceExemplar(ctx, reconcileDuration.WithLabelValues(kind, outcome), time.Since(start).Seconds())
+		return result, err
+	})
+}
+
+// observeWithTraceExemplar observes the given value, attaching the id of the sampled trace of the given context (if any) as exemplar;
+// exemplars are only exposed if the metrics are scraped in the OpenMetrics format.
+func observeWithTraceExemplar(ctx context.Context, observer prometheus.Observer, value float64) {
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsSampled() {
+		if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok {
+			exemplarObserver.ObserveWithExemplar(value, prometheus.Labels{"trace_id": spanContext.TraceID().String()})
+			return
+		}
+	}
+	observer.Observe(value)
+}
+
+// ResourceStateCollector reports the number of objects reconciled by this operator deployment (i.e. accepted by its namespace filter),
+// by kind and state (cf_resources). The objects are counted when the metrics are scraped, reading through the given reader
+// (which should be the manager's cache, since the manager's client reads some of the kinds uncached), such that deleted objects
+// and objects moved to other shards are never reported stale.
+type ResourceStateCollector struct {
+	Client client.Reader
+	Config *config.Config
+}
+
+// Describe implements prometheus.Collector.
+func (c *ResourceStateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- resourceStatesDesc
+}
+
+// Collect implements prometheus.Collector; kinds which cannot be listed are logged and skipped.
+func (c *ResourceStateCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), resourceStateListTimeout)
+	defer cancel()
+	log := ctrl.Log.WithName("metrics")
+	f := newNamespaceFilter(c.Client, c.Config)
+	matches := make(map[string]bool)
+	count := func(counts map[string]int, obj client.Object, state string) {
+		namespace := obj.GetNamespace()
+		if _, ok := matches[namespace]; !ok {
+			matches[namespace] = f.matches(ctx, namespace)
+		}
+		if !matches[namespace] {
+			return
+		}
+		if state == "" {
+			state = "Unknown"
+		}
+		counts[state]++
+	}
+
+	lists := []struct {
+		kind  string
+		list  client.ObjectList
+		count func(client.ObjectList, map[string]int)
+	}{
+		{"Space", &cfv1alpha1.SpaceList{}, func(list client.ObjectList, counts map[string]int) {
+			for i := range list.(*cfv1alpha1.SpaceList).Items {
+				space := &list.(*cfv1alpha1.SpaceList).Items[i]
+				count(counts, space, string(space.Status.State))
+			}
+		}},
+		{"ClusterSpace", &cfv1alpha1.ClusterSpaceList{}, func(list client.ObjectList, counts map[string]int) {
+			for i := range list.(*cfv1alpha1.ClusterSpaceList).Items {
+				clusterSpace := &list.(*cfv1alpha1.ClusterSpaceList).Items[i]
+				count(counts, clusterSpace, string(clusterSpace.Status.State))
+			}
+		}},
+		{"ServiceInstance", &cfv1alpha1.ServiceInstanceList{}, func(list client.ObjectList, counts map[string]int) {
+			for i := range list.(*cfv1alpha1.ServiceInstanceList).Items {
+				serviceInstance := &list.(*cfv1alpha1.ServiceInstanceList).Items[i]
+				count(counts, serviceInstance, string(serviceInstance.Status.State))
+			}
+		}},
+		{"ServiceBinding", &cfv1alpha1.ServiceBindingList{}, func(list client.ObjectList, counts map[string]int) {
+			for i := range list.(*cfv1alpha1.ServiceBindingList).Items {
+				serviceBinding := &list.(*cfv1alpha1.ServiceBindingList).Items[i]
+				count(counts, serviceBinding, string(serviceBinding.Status.State))
+			}
+		}},
+		{"Route", &cfv1alpha1.RouteList{}, func(list client.ObjectList, counts map[string]int) {
+			for i := range list.(*cfv1alpha1.RouteList).Items {
+				route := &list.(*cfv1alpha1.RouteList).Items[i]
+				count(counts, route, string(route.Status.State))
+			}
+		}},
+		{"RouteBinding", &cfv1alpha1.RouteBindingList{}, func(list client.ObjectList, counts map[string]int) {
+			for i := range list.(*cfv1alpha1.RouteBindingList).Items {
+				routeBinding := &list.(*cfv1alpha1.RouteBindingList).Items[i]
+				count(counts, routeBinding, string(routeBinding.Status.State))
+			}
+		}},
+		{"ClusterServiceBinding", &cfv1alpha1.ClusterServiceBindingList{}, func(list client.ObjectList, counts map[string]int) {
+			for i := range list.(*cfv1alpha1.ClusterServiceBindingList).Items {
+				clusterServiceBinding := &list.(*cfv1alpha1.ClusterServiceBindingList).Items[i]
+				count(counts, clusterServiceBinding, string(clusterServiceBinding.Status.State))
+			}
+		}},
+	}
+	for _, l := range lists {
+		if err := c.Client.List(ctx, l.list); err != nil {
+			log.Error(err, "failed to list objects for resource state metrics", "kind", l.kind)
+			continue
+		}
+		counts := make(map[string]int)
+		for _, state := range resourceStates {
+			counts[state] = 0
+		}
+		l.count(l.list, counts)
+		for state, n := range counts {
+			ch <- prometheus.MustNewConstMetric(resourceStatesDesc, prometheus.GaugeValue, float64(n), l.kind, state)
+		}
+	}
+}
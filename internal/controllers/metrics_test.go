@@ -0,0 +1,97 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+)
+
+var _ = Describe("Measure reconciles | measureReconciles", func() {
+	It("should observe the duration of reconciles, with the trace id as exemplar", func() {
+		tracerProvider := otel.GetTracerProvider()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider())
+		defer otel.SetTracerProvider(tracerProvider)
+
+		r := traceReconciles(measureReconciles(reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+			return ctrl.Result{}, errors.New("space not ready")
+		}), "Route"), "Route")
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "a", Name: "route"}})
+		Expect(err).To(MatchError("space not ready"))
+
+		metric := &dto.Metric{}
+		Expect(reconcileDuration.WithLabelValues("Route", "error").(prometheus.Metric).Write(metric)).To(Succeed())
+		Expect(metric.GetHistogram().GetSampleCount()).To(BeNumerically(">=", 1))
+		var exemplars []*dto.Exemplar
+		for _, bucket := range metric.GetHistogram().GetBucket() {
+			if bucket.GetExemplar() != nil {
+				exemplars = append(exemplars, bucket.GetExemplar())
+			}
+		}
+		Expect(exemplars).To(HaveLen(1))
+		Expect(exemplars[0].GetLabel()[0].GetName()).To(Equal("trace_id"))
+	})
+})
+
+var _ = Describe("Resource state metrics | ResourceStateCollector", func() {
+	It("should count the objects of accepted namespaces by kind and state", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		instance := func(namespace string, name string, state cfv1alpha1.ServiceInstanceState) *cfv1alpha1.ServiceInstance {
+			return &cfv1alpha1.ServiceInstance{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+				Status:     cfv1alpha1.ServiceInstanceStatus{State: state},
+			}
+		}
+		collector := &ResourceStateCollector{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				instance("a", "ready", cfv1alpha1.ServiceInstanceStateReady),
+				instance("a", "failed", cfv1alpha1.ServiceInstanceStateError),
+				instance("a", "new", ""),
+				instance("ignored", "failed", cfv1alpha1.ServiceInstanceStateError),
+			).Build(),
+			Config: &config.Config{IgnoreNamespaces: []string{"ignored"}},
+		}
+
+		registry := prometheus.NewPedanticRegistry()
+		Expect(registry.Register(collector)).To(Succeed())
+		families, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		counts := make(map[string]float64)
+		for _, family := range families {
+			for _, metric := range family.GetMetric() {
+				labels := make(map[string]string)
+				for _, label := range metric.GetLabel() {
+					labels[label.GetName()] = label.GetValue()
+				}
+				if labels["kind"] == "ServiceInstance" {
+					counts[labels["state"]] = metric.GetGauge().GetValue()
+				}
+			}
+		}
+		Expect(counts).To(Equal(map[string]float64{"Ready": 1, "Error": 1, "Unknown": 1, "Processing": 0, "Deleting": 0}))
+		Expect(testutil.CollectAndCount(collector)).To(Equal(7*4 + 1))
+	})
+})
@@ -0,0 +1,121 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"hash/fnv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/sap/cf-service-operator/internal/config"
+)
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// namespaceFilter decides whether objects of a namespace are reconciled by this operator deployment,
+// according to the namespace allow and deny lists, the namespace label selector, and the shard (see config.Config).
+// Cluster-scoped objects are reconciled by shard 0 only (and only if reconcileClusterSpaces is enabled).
+type namespaceFilter struct {
+	reader        client.Reader
+	allowed       map[string]bool
+	ignored       map[string]bool
+	selector      labels.Selector
+	shardCount    int
+	shardIndex    int
+	clusterScoped bool
+}
+
+// newNamespaceFilter returns the namespace filter according to the given configuration;
+// namespace labels (if a label selector is configured) are read through reader.
+func newNamespaceFilter(reader client.Reader, cfg *config.Config) *namespaceFilter {
+	f := &namespaceFilter{reader: reader, clusterScoped: true}
+	if cfg == nil {
+		return f
+	}
+	if len(cfg.WatchNamespaces) > 0 {
+		f.allowed = make(map[string]bool)
+		for _, namespace := range cfg.WatchNamespaces {
+			f.allowed[namespace] = true
+		}
+	}
+	f.ignored = make(map[string]bool)
+	for _, namespace := range cfg.IgnoreNamespaces {
+		f.ignored[namespace] = true
+	}
+	if cfg.NamespaceLabelSelector != "" {
+		// the selector was validated when the configuration was loaded
+		f.selector, _ = labels.Parse(cfg.NamespaceLabelSelector)
+	}
+	f.shardCount = cfg.ShardCount
+	f.shardIndex = cfg.ShardIndex
+	f.clusterScoped = cfg.ReconcileClusterSpaces
+	return f
+}
+
+// filterNamespaces wraps the given reconciler, such that only objects accepted by the namespace filter of the given configuration
+// are reconciled; the returned predicate must be passed to the controller (as event filter), such that other objects are not even enqueued.
+// The reconciler itself checks the filter again, since requeued objects bypass the predicate (e.g. after namespace labels changed).
+func filterNamespaces(r reconcile.Reconciler, reader client.Reader, cfg *config.Config) (reconcile.Reconciler, predicate.Predicate) {
+	f := newNamespaceFilter(reader, cfg)
+	filtered := reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		if !f.matches(ctx, req.Namespace) {
+			log.FromContext(ctx).V(1).Info("Namespace is not reconciled by this operator deployment; ignoring")
+			return ctrl.Result{}, nil
+		}
+		return r.Reconcile(ctx, req)
+	})
+	return filtered, predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return f.matches(context.Background(), obj.GetNamespace())
+	})
+}
+
+// matches reports whether objects of the given namespace (empty for cluster-scoped objects) are reconciled.
+func (f *namespaceFilter) matches(ctx context.Context, namespace string) bool {
+	if namespace == "" {
+		return f.clusterScoped && f.shardIndex == 0
+	}
+	if f.allowed != nil && !f.allowed[namespace] || f.ignored[namespace] {
+		return false
+	}
+	if f.shardCount > 1 && shardOf(namespace, f.shardCount) != f.shardIndex {
+		return false
+	}
+	if f.selector != nil {
+		ns := &corev1.Namespace{}
+		if err := f.reader.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				log.FromContext(ctx).Error(err, "failed to get namespace; skipping its objects", "namespace", namespace)
+			}
+			return false
+		}
+		return f.selector.Matches(labels.Set(ns.Labels))
+	}
+	return true
+}
+
+// shardOf assigns the given namespace to one of shardCount shards, using jump consistent hashing (Lamping, Veach);
+// when the number of shards changes, only a minimal fraction of the namespaces moves to another shard.
+func shardOf(namespace string, shardCount int) int {
+	h := fnv.New64a()
+	h.Write([]byte(namespace))
+	key := h.Sum64()
+
+	var b, j int64 = -1, 0
+	for j < int64(shardCount) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
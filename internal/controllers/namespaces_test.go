@@ -0,0 +1,117 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/sap/cf-service-operator/internal/config"
+)
+
+var _ = Describe("Filter reconciled namespaces | namespaceFilter", func() {
+	ctx := context.Background()
+
+	It("should honor the namespace allow and deny lists", func() {
+		cfg := config.Defaults()
+		cfg.WatchNamespaces = []string{"a", "b"}
+		cfg.IgnoreNamespaces = []string{"b"}
+		f := newNamespaceFilter(nil, cfg)
+		Expect(f.matches(ctx, "a")).To(BeTrue())
+		Expect(f.matches(ctx, "b")).To(BeFalse())
+		Expect(f.matches(ctx, "c")).To(BeFalse())
+		// cluster-scoped objects
+		Expect(f.matches(ctx, "")).To(BeTrue())
+		cfg.ReconcileClusterSpaces = false
+		Expect(newNamespaceFilter(nil, cfg).matches(ctx, "")).To(BeFalse())
+	})
+
+	It("should select namespaces by label", func() {
+		c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "a", Labels: map[string]string{"landscape": "eu10"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "b", Labels: map[string]string{"landscape": "us10"}}},
+		).Build()
+		cfg := config.Defaults()
+		cfg.NamespaceLabelSelector = "landscape=eu10"
+		f := newNamespaceFilter(c, cfg)
+		Expect(f.matches(ctx, "a")).To(BeTrue())
+		Expect(f.matches(ctx, "b")).To(BeFalse())
+		Expect(f.matches(ctx, "missing")).To(BeFalse())
+	})
+
+	It("should assign every namespace to exactly one shard", func() {
+		const shardCount = 4
+		counts := make([]int, shardCount)
+		filters := make([]*namespaceFilter, shardCount)
+		for i := range filters {
+			cfg := config.Defaults()
+			cfg.ShardCount = shardCount
+			cfg.ShardIndex = i
+			filters[i] = newNamespaceFilter(nil, cfg)
+		}
+		for n := 0; n < 1000; n++ {
+			namespace := fmt.Sprintf("namespace-%d", n)
+			matching := 0
+			for i, f := range filters {
+				if f.matches(ctx, namespace) {
+					matching++
+					counts[i]++
+				}
+			}
+			Expect(matching).To(Equal(1))
+		}
+		for _, count := range counts {
+			Expect(count).To(BeNumerically("~", 250, 50))
+		}
+		// cluster-scoped objects are reconciled by shard 0
+		Expect(filters[0].matches(ctx, "")).To(BeTrue())
+		Expect(filters[1].matches(ctx, "")).To(BeFalse())
+	})
+
+	It("should move few namespaces if the number of shards grows", func() {
+		moved := 0
+		for n := 0; n < 1000; n++ {
+			namespace := fmt.Sprintf("namespace-%d", n)
+			if shard := shardOf(namespace, 5); shard != shardOf(namespace, 4) {
+				// namespaces only move to the new shard
+				Expect(shard).To(Equal(4))
+				moved++
+			}
+		}
+		Expect(moved).To(BeNumerically("~", 200, 50))
+	})
+
+	It("should neither enqueue nor reconcile objects of other namespaces", func() {
+		cfg := config.Defaults()
+		cfg.WatchNamespaces = []string{"a"}
+		reconciled := 0
+		r, p := filterNamespaces(reconcile.Func(func(context.Context, ctrl.Request) (ctrl.Result, error) {
+			reconciled++
+			return ctrl.Result{}, nil
+		}), nil, cfg)
+
+		Expect(p.Create(event.CreateEvent{Object: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "s"}}})).To(BeTrue())
+		Expect(p.Create(event.CreateEvent{Object: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "b", Name: "s"}}})).To(BeFalse())
+
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "b", Name: "s"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reconciled).To(BeZero())
+		_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "a", Name: "s"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reconciled).To(Equal(1))
+	})
+})
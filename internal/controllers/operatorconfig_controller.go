@@ -0,0 +1,242 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+)
+
+const (
+	operatorConfigConditionReasonApplied = "Applied"
+	operatorConfigConditionReasonInvalid = "Invalid"
+)
+
+// OperatorConfigReconciler applies the settings of the (cluster-scoped) OperatorConfig object at runtime.
+// The settings override the operator configuration (file, environment or flags); if the object is deleted,
+// the operator configuration applies again.
+type OperatorConfigReconciler struct {
+	client.Client
+	// Called with the effective configuration whenever it changed
+	Apply func(*config.Config)
+
+	mutex sync.Mutex
+	// operator configuration, overridden by the OperatorConfig object
+	config *config.Config
+	// spec of the OperatorConfig object which was applied last (nil if there is none)
+	spec *cfv1alpha1.OperatorConfigSpec
+	// effective configuration passed to Apply last
+	applied *config.Config
+}
+
+// NewOperatorConfigReconciler returns a reconciler for the OperatorConfig object, overriding the given operator configuration.
+func NewOperatorConfigReconciler(c client.Client, cfg *config.Config, apply func(*config.Config)) *OperatorConfigReconciler {
+	return &OperatorConfigReconciler{Client: c, Apply: apply, config: cfg, applied: cfg}
+}
+
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=operatorconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=operatorconfigs/status,verbs=get;update;patch
+
+// Reconcile applies the OperatorConfig object; its status is reported by the elected leader only (see operatorConfigStatusReconciler).
+func (r *OperatorConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	if req.Name != cfv1alpha1.OperatorConfigName {
+		log.V(1).Info("ignoring OperatorConfig", "expectedName", cfv1alpha1.OperatorConfigName)
+		return ctrl.Result{}, nil
+	}
+
+	operatorConfig := &cfv1alpha1.OperatorConfig{}
+	if err := r.Get(ctx, req.NamespacedName, operatorConfig); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, errors.Wrap(err, "failed to get OperatorConfig")
+		}
+		log.Info("OperatorConfig not found; applying operator configuration")
+		return ctrl.Result{}, r.apply(nil)
+	}
+
+	if err := r.apply(&operatorConfig.Spec); err != nil {
+		log.Error(err, "rejecting OperatorConfig; keeping previous configuration")
+	}
+	return ctrl.Result{}, nil
+}
+
+// operatorConfigStatusReconciler reports in the status of the OperatorConfig object whether it was applied. Other than the
+// OperatorConfigReconciler, it runs in the elected leader only, such that replicas do not compete for updating the status.
+type operatorConfigStatusReconciler struct {
+	reconciler *OperatorConfigReconciler
+}
+
+func (r *operatorConfigStatusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if req.Name != cfv1alpha1.OperatorConfigName {
+		return ctrl.Result{}, nil
+	}
+
+	operatorConfig := &cfv1alpha1.OperatorConfig{}
+	if err := r.reconciler.Get(ctx, req.NamespacedName, operatorConfig); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, errors.Wrap(err, "failed to get OperatorConfig")
+	}
+
+	status := &operatorConfig.Status
+	previousStatus := status.DeepCopy()
+	if err := r.reconciler.validate(&operatorConfig.Spec); err != nil {
+		operatorConfig.SetCondition(cfv1alpha1.OperatorConfigConditionApplied, cfv1alpha1.ConditionFalse, operatorConfigConditionReasonInvalid, err.Error())
+	} else {
+		if condition := operatorConfig.GetCondition(cfv1alpha1.OperatorConfigConditionApplied); condition == nil || condition.Status != cfv1alpha1.ConditionTrue || status.ObservedGeneration != operatorConfig.Generation {
+			now := metav1.Now()
+			status.LastAppliedAt = &now
+		}
+		operatorConfig.SetCondition(cfv1alpha1.OperatorConfigConditionApplied, cfv1alpha1.ConditionTrue, operatorConfigConditionReasonApplied, "Configuration applied")
+	}
+	status.ObservedGeneration = operatorConfig.Generation
+	if !reflect.DeepEqual(status, previousStatus) {
+		if err := r.reconciler.Status().Update(ctx, operatorConfig); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to update OperatorConfig status")
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetConfig replaces the operator configuration (such as after the configuration file was reloaded),
+// and applies it, overridden by the last applied OperatorConfig object.
+func (r *OperatorConfigReconciler) SetConfig(cfg *config.Config) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.config = cfg
+	effective := withOperatorConfig(cfg, r.spec)
+	if err := effective.Validate(); err != nil {
+		// the OperatorConfig object is re-validated when it is reconciled the next time; until then, it is ignored
+		r.spec = nil
+		effective = cfg
+	}
+	r.applyLocked(effective)
+}
+
+// validate tells whether the given OperatorConfig spec can be applied over the operator configuration.
+func (r *OperatorConfigReconciler) validate(spec *cfv1alpha1.OperatorConfigSpec) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return withOperatorConfig(r.config, spec).Validate()
+}
+
+func (r *OperatorConfigReconciler) apply(spec *cfv1alpha1.OperatorConfigSpec) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	effective := withOperatorConfig(r.config, spec)
+	if err := effective.Validate(); err != nil {
+		return err
+	}
+	r.spec = spec
+	r.applyLocked(effective)
+	return nil
+}
+
+// Must be called with mutex locked.
+func (r *OperatorConfigReconciler) applyLocked(cfg *config.Config) {
+	if reflect.DeepEqual(cfg, r.applied) {
+		return
+	}
+	r.Apply(cfg)
+	r.applied = cfg
+}
+
+// withOperatorConfig returns a copy of the given configuration, overridden by the fields set in the given OperatorConfig spec.
+// Default polling intervals are merged by kind; endpoint rate limits are replaced as a whole.
+func withOperatorConfig(cfg *config.Config, spec *cfv1alpha1.OperatorConfigSpec) *config.Config {
+	result := &config.Config{}
+	if cfg != nil {
+		*result = *cfg
+	}
+	if spec == nil {
+		return result
+	}
+	if spec.ResourceCacheEnabled != nil {
+		result.IsResourceCacheEnabled = *spec.ResourceCacheEnabled
+	}
+	if spec.ResourceCacheTimeout != nil {
+		result.CacheTimeOut = *spec.ResourceCacheTimeout
+	}
+	if spec.MaxConcurrentReconciles != nil {
+		result.MaxConcurrentReconciles = *spec.MaxConcurrentReconciles
+	}
+	if spec.MaxConcurrentReconcilesPerEndpoint != nil {
+		result.MaxConcurrentReconcilesPerEndpoint = *spec.MaxConcurrentReconcilesPerEndpoint
+	}
+	result.PollingIntervalsReady = mergePollingIntervals(result.PollingIntervalsReady, spec.PollingIntervalsReady)
+	result.PollingIntervalsFail = mergePollingIntervals(result.PollingIntervalsFail, spec.PollingIntervalsFail)
+	if spec.MaxRetriesOnTooManyRequests != nil {
+		result.MaxRetriesOnTooManyRequests = *spec.MaxRetriesOnTooManyRequests
+	}
+	if spec.MaxRequestsPerSecond != nil {
+		result.MaxRequestsPerSecond = spec.MaxRequestsPerSecond.AsApproximateFloat64()
+	}
+	if spec.Burst != nil {
+		result.Burst = *spec.Burst
+	}
+	if spec.EndpointRateLimits != nil {
+		result.EndpointRateLimits = make(map[string]config.RateLimit, len(spec.EndpointRateLimits))
+		for url, limit := range spec.EndpointRateLimits {
+			rateLimit := config.RateLimit{Burst: limit.Burst}
+			if limit.MaxRequestsPerSecond != nil {
+				rateLimit.MaxRequestsPerSecond = limit.MaxRequestsPerSecond.AsApproximateFloat64()
+			}
+			result.EndpointRateLimits[url] = rateLimit
+		}
+	}
+	return result
+}
+
+func mergePollingIntervals(intervals map[string]metav1.Duration, overrides map[string]metav1.Duration) map[string]metav1.Duration {
+	if len(overrides) == 0 {
+		return intervals
+	}
+	result := make(map[string]metav1.Duration, len(intervals)+len(overrides))
+	for kind, interval := range intervals {
+		result[kind] = interval
+	}
+	for kind, interval := range overrides {
+		result[kind] = interval
+	}
+	return result
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// The controller does not need leader election, since the configuration is applied in all replicas; the status of the
+// OperatorConfig object is updated by a second controller, running in the elected leader only.
+func (r *OperatorConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	needLeaderElection := false
+	if err := ctrl.NewControllerManagedBy(mgr).
+		For(&cfv1alpha1.OperatorConfig{}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		WithOptions(controller.Options{NeedLeaderElection: &needLeaderElection}).
+		Complete(r); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("operatorconfig-status").
+		For(&cfv1alpha1.OperatorConfig{}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(&operatorConfigStatusReconciler{reconciler: r})
+}
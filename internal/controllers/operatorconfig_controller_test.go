@@ -0,0 +1,162 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+)
+
+var _ = Describe("Apply the operator configuration at runtime | OperatorConfigReconciler", func() {
+	var ctx context.Context
+	var c client.Client
+	var applied []*config.Config
+	var reconciler *OperatorConfigReconciler
+	var statusReconciler *operatorConfigStatusReconciler
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: cfv1alpha1.OperatorConfigName}}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		c = fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&cfv1alpha1.OperatorConfig{}).Build()
+		applied = nil
+		cfg := config.Defaults()
+		cfg.PollingIntervalsReady = map[string]metav1.Duration{"Space": {Duration: time.Minute}}
+		reconciler = NewOperatorConfigReconciler(c, cfg, func(cfg *config.Config) { applied = append(applied, cfg) })
+		statusReconciler = &operatorConfigStatusReconciler{reconciler: reconciler}
+	})
+
+	createOperatorConfig := func(name string, spec cfv1alpha1.OperatorConfigSpec) *cfv1alpha1.OperatorConfig {
+		operatorConfig := &cfv1alpha1.OperatorConfig{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: spec}
+		Expect(c.Create(ctx, operatorConfig)).To(Succeed())
+		return operatorConfig
+	}
+
+	It("should apply the settings of the OperatorConfig object over the operator configuration", func() {
+		maxConcurrentReconciles := 5
+		createOperatorConfig(cfv1alpha1.OperatorConfigName, cfv1alpha1.OperatorConfigSpec{
+			MaxConcurrentReconciles: &maxConcurrentReconciles,
+			PollingIntervalsReady:   map[string]metav1.Duration{"ServiceInstance": {Duration: time.Hour}},
+			MaxRequestsPerSecond:    resource.NewMilliQuantity(500, resource.DecimalSI),
+			EndpointRateLimits: map[string]cfv1alpha1.EndpointRateLimit{
+				"https://api.cf.example.com": {MaxRequestsPerSecond: resource.NewQuantity(2, resource.DecimalSI), Burst: 4},
+			},
+		})
+
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(applied).To(HaveLen(1))
+		Expect(applied[0].MaxConcurrentReconciles).To(Equal(5))
+		Expect(applied[0].PollingIntervalsReady).To(Equal(map[string]metav1.Duration{"Space": {Duration: time.Minute}, "ServiceInstance": {Duration: time.Hour}}))
+		Expect(applied[0].MaxRequestsPerSecond).To(Equal(0.5))
+		Expect(applied[0].Burst).To(Equal(config.Defaults().Burst))
+		Expect(applied[0].EndpointRateLimits).To(Equal(map[string]config.RateLimit{"https://api.cf.example.com": {MaxRequestsPerSecond: 2, Burst: 4}}))
+
+		// unchanged configuration is not applied again
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(applied).To(HaveLen(1))
+	})
+
+	It("should report the applied OperatorConfig object in its status only by the status reconciler (running in the leader)", func() {
+		maxConcurrentReconciles := 5
+		createOperatorConfig(cfv1alpha1.OperatorConfigName, cfv1alpha1.OperatorConfigSpec{MaxConcurrentReconciles: &maxConcurrentReconciles})
+
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(applied).To(HaveLen(1))
+		operatorConfig := &cfv1alpha1.OperatorConfig{}
+		Expect(c.Get(ctx, request.NamespacedName, operatorConfig)).To(Succeed())
+		Expect(operatorConfig.Status).To(Equal(cfv1alpha1.OperatorConfigStatus{}))
+
+		_, err = statusReconciler.Reconcile(ctx, request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.Get(ctx, request.NamespacedName, operatorConfig)).To(Succeed())
+		Expect(operatorConfig.GetCondition(cfv1alpha1.OperatorConfigConditionApplied).Status).To(Equal(cfv1alpha1.ConditionTrue))
+		Expect(operatorConfig.Status.LastAppliedAt).ToNot(BeNil())
+		lastAppliedAt := operatorConfig.Status.LastAppliedAt
+		resourceVersion := operatorConfig.ResourceVersion
+
+		// the status is not updated again as long as the OperatorConfig object does not change
+		_, err = statusReconciler.Reconcile(ctx, request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.Get(ctx, request.NamespacedName, operatorConfig)).To(Succeed())
+		Expect(operatorConfig.ResourceVersion).To(Equal(resourceVersion))
+		Expect(operatorConfig.Status.LastAppliedAt).To(Equal(lastAppliedAt))
+		Expect(applied).To(HaveLen(1))
+	})
+
+	It("should reject an invalid OperatorConfig object, and keep the previous configuration", func() {
+		createOperatorConfig(cfv1alpha1.OperatorConfigName, cfv1alpha1.OperatorConfigSpec{
+			PollingIntervalsReady: map[string]metav1.Duration{"Unknown": {Duration: time.Hour}},
+		})
+
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(applied).To(BeEmpty())
+
+		_, err = statusReconciler.Reconcile(ctx, request)
+		Expect(err).ToNot(HaveOccurred())
+		operatorConfig := &cfv1alpha1.OperatorConfig{}
+		Expect(c.Get(ctx, request.NamespacedName, operatorConfig)).To(Succeed())
+		condition := operatorConfig.GetCondition(cfv1alpha1.OperatorConfigConditionApplied)
+		Expect(condition.Status).To(Equal(cfv1alpha1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(operatorConfigConditionReasonInvalid))
+		Expect(condition.Message).To(ContainSubstring("invalid ready polling intervals"))
+	})
+
+	It("should restore the operator configuration once the OperatorConfig object is deleted", func() {
+		enabled := true
+		operatorConfig := createOperatorConfig(cfv1alpha1.OperatorConfigName, cfv1alpha1.OperatorConfigSpec{ResourceCacheEnabled: &enabled})
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(applied).To(HaveLen(1))
+		Expect(applied[0].IsResourceCacheEnabled).To(BeTrue())
+
+		Expect(c.Delete(ctx, operatorConfig)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(applied).To(HaveLen(2))
+		Expect(applied[1].IsResourceCacheEnabled).To(BeFalse())
+	})
+
+	It("should keep the OperatorConfig settings when the operator configuration is reloaded", func() {
+		maxConcurrentReconciles := 5
+		createOperatorConfig(cfv1alpha1.OperatorConfigName, cfv1alpha1.OperatorConfigSpec{MaxConcurrentReconciles: &maxConcurrentReconciles})
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).ToNot(HaveOccurred())
+
+		reloaded := config.Defaults()
+		reloaded.MaxConcurrentReconciles = 2
+		reloaded.Burst = 42
+		reconciler.SetConfig(reloaded)
+		Expect(applied).To(HaveLen(2))
+		Expect(applied[1].MaxConcurrentReconciles).To(Equal(5))
+		Expect(applied[1].Burst).To(Equal(42))
+	})
+
+	It("should ignore OperatorConfig objects with other names", func() {
+		maxConcurrentReconciles := 5
+		createOperatorConfig("other", cfv1alpha1.OperatorConfigSpec{MaxConcurrentReconciles: &maxConcurrentReconciles})
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "other"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(applied).To(BeEmpty())
+	})
+})
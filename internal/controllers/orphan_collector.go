@@ -0,0 +1,355 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+// OrphanCollector periodically scans the Cloud Foundry spaces managed through Space and ClusterSpace objects for service instances
+// and bindings which carry the owner label, but whose owning ServiceInstance or ServiceBinding object does not exist (anymore).
+// Orphans are reported through metrics and events (on the space object); according to the configured policy, they are deleted
+// if they are still orphaned in the next scan.
+type OrphanCollector struct {
+	client.Client
+	ClusterResourceNamespace string
+	ClientBuilder            facade.SpaceClientBuilder
+	Config                   *config.Config
+	Recorder                 record.EventRecorder
+	Interval                 time.Duration
+	Policy                   config.OrphanPolicy
+
+	// guids of the orphans found by the previous scan
+	suspects map[string]bool
+}
+
+// orphan is a service instance or binding found by the orphan collector.
+type orphan struct {
+	kind  string
+	guid  string
+	name  string
+	owner string
+	// namespace, name and cluster of the owning object, as recorded in the owner labels (empty if not recorded)
+	ownerNamespace string
+	ownerName      string
+	ownerCluster   string
+	space          cfv1alpha1.GenericSpace
+	spaceClient    facade.SpaceClient
+}
+
+// Start scans the spaces every interval, until the context is cancelled.
+// Implements manager.Runnable; errors are logged, and do not stop the collector.
+func (r *OrphanCollector) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithName("orphans")
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	for {
+		if err := r.scan(ctx); err != nil {
+			log.Error(err, "failed to scan for orphaned service instances and bindings")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *OrphanCollector) scan(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	spaces, err := r.listSpaces(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Cloud Foundry resources are listed before the owning objects, such that resources created
+	// while scanning are not mistaken for orphans (their owners exist when the owners are listed)
+	var candidates []orphan
+	for _, space := range spaces {
+		spaceClient, err := r.newSpaceClient(ctx, space)
+		if err != nil {
+			log.Error(err, "skipping space", "kind", space.GetKind(), "namespace", space.GetNamespace(), "name", space.GetName())
+			continue
+		}
+		instances, err := spaceClient.ListInstances(ctx)
+		if err != nil {
+			log.Error(err, "skipping space", "kind", space.GetKind(), "namespace", space.GetNamespace(), "name", space.GetName())
+			continue
+		}
+		bindings, err := spaceClient.ListBindings(ctx)
+		if err != nil {
+			log.Error(err, "skipping space", "kind", space.GetKind(), "namespace", space.GetNamespace(), "name", space.GetName())
+			continue
+		}
+		for _, instance := range instances {
+			if instance.State == facade.InstanceStateDeleting || instance.State == facade.InstanceStateDeleted {
+				continue
+			}
+			candidates = append(candidates, orphan{kind: "ServiceInstance", guid: instance.Guid, name: instance.Name, owner: instance.Owner,
+				ownerNamespace: instance.OwnerNamespace, ownerName: instance.OwnerName, ownerCluster: instance.OwnerCluster, space: space, spaceClient: spaceClient})
+		}
+		for _, binding := range bindings {
+			if binding.State == facade.BindingStateDeleting || binding.State == facade.BindingStateDeleted {
+				continue
+			}
+			candidates = append(candidates, orphan{kind: "ServiceBinding", guid: binding.Guid, name: binding.Name, owner: binding.Owner,
+				ownerNamespace: binding.OwnerNamespace, ownerName: binding.OwnerName, ownerCluster: binding.OwnerCluster, space: space, spaceClient: spaceClient})
+		}
+	}
+
+	owners, err := r.listOwners(ctx)
+	if err != nil {
+		return err
+	}
+
+	var orphans []orphan
+	counts := map[string]int{"ServiceInstance": 0, "ServiceBinding": 0}
+	for _, candidate := range candidates {
+		if owners[candidate.owner] {
+			continue
+		}
+		orphans = append(orphans, candidate)
+		counts[candidate.kind]++
+		log.Info("found orphaned resource", "kind", candidate.kind, "name", candidate.name, "guid", candidate.guid, "owner", candidate.owner)
+		r.Recorder.Eventf(candidate.space, corev1.EventTypeWarning, "Orphaned", "Orphaned Cloud Foundry %s %s (%s), owner %s does not exist", candidate.kind, candidate.name, candidate.guid, candidate.owner)
+	}
+	for kind, count := range counts {
+		orphanedResources.WithLabelValues(kind).Set(float64(count))
+	}
+
+	suspects := make(map[string]bool)
+	for _, o := range orphans {
+		suspects[o.guid] = true
+	}
+	// nothing is deleted if the operator runs in observe-only mode
+	if r.Policy == config.OrphanPolicyDelete && (r.Config == nil || !r.Config.ObserveOnly) {
+		// bindings go first, since instances with bindings cannot be deleted
+		for _, kind := range []string{"ServiceBinding", "ServiceInstance"} {
+			for _, o := range orphans {
+				if o.kind != kind || !r.suspects[o.guid] {
+					continue
+				}
+				if deletable, reason, err := r.isDeletable(ctx, o); err != nil {
+					log.Error(err, "failed to verify orphaned resource", "kind", o.kind, "name", o.name, "guid", o.guid)
+					continue
+				} else if !deletable {
+					log.Info("not deleting orphaned resource", "kind", o.kind, "name", o.name, "guid", o.guid, "reason", reason)
+					continue
+				}
+				if err := r.deleteOrphan(ctx, o); err != nil {
+					log.Error(err, "failed to delete orphaned resource", "kind", o.kind, "name", o.name, "guid", o.guid)
+					continue
+				}
+				orphanedResourcesDeleted.WithLabelValues(o.kind).Inc()
+				r.Recorder.Eventf(o.space, corev1.EventTypeNormal, "OrphanDeleted", "Deleted orphaned Cloud Foundry %s %s (%s)", o.kind, o.name, o.guid)
+			}
+		}
+	}
+	r.suspects = suspects
+	return nil
+}
+
+// isDeletable checks whether the given orphan may be deleted; that is, whether it was created (or last updated) by this cluster
+// (according to its owner-cluster label), and whether its owner labels record the namespace and name of the owning object,
+// and no object of that kind exists under that name (such an object would adopt the resource). Returns the reason otherwise.
+func (r *OrphanCollector) isDeletable(ctx context.Context, o orphan) (bool, string, error) {
+	clusterID := ""
+	if r.Config != nil {
+		clusterID = r.Config.ClusterID
+	}
+	if o.ownerCluster != clusterID {
+		return false, fmt.Sprintf("owned by cluster %q", o.ownerCluster), nil
+	}
+	if o.ownerNamespace == "" || o.ownerName == "" {
+		return false, "owning object not recorded", nil
+	}
+	var owner client.Object = &cfv1alpha1.ServiceInstance{}
+	if o.kind == "ServiceBinding" {
+		owner = &cfv1alpha1.ServiceBinding{}
+	}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: o.ownerNamespace, Name: o.ownerName}, owner); err == nil {
+		return false, fmt.Sprintf("%s %s/%s exists", o.kind, o.ownerNamespace, o.ownerName), nil
+	} else if !apierrors.IsNotFound(err) {
+		return false, "", errors.Wrapf(err, "failed to read %s %s/%s", o.kind, o.ownerNamespace, o.ownerName)
+	}
+	return true, "", nil
+}
+
+func (r *OrphanCollector) deleteOrphan(ctx context.Context, o orphan) error {
+	if o.kind == "ServiceBinding" {
+		return o.spaceClient.DeleteBinding(ctx, o.guid, facade.OwnerRef{UID: o.owner})
+	}
+	return o.spaceClient.DeleteInstance(ctx, o.guid, facade.OwnerRef{UID: o.owner})
+}
+
+func (r *OrphanCollector) listSpaces(ctx context.Context) ([]cfv1alpha1.GenericSpace, error) {
+	return listManagedSpaces(ctx, r.Client)
+}
+
+// listManagedSpaces returns all Space and ClusterSpace objects, skipping objects referring to an already listed Cloud Foundry space.
+func listManagedSpaces(ctx context.Context, c client.Client) ([]cfv1alpha1.GenericSpace, error) {
+	spaceList := &cfv1alpha1.SpaceList{}
+	if err := c.List(ctx, spaceList); err != nil {
+		return nil, errors.Wrap(err, "failed to list spaces")
+	}
+	clusterSpaceList := &cfv1alpha1.ClusterSpaceList{}
+	if err := c.List(ctx, clusterSpaceList); err != nil {
+		return nil, errors.Wrap(err, "failed to list cluster spaces")
+	}
+
+	var spaces []cfv1alpha1.GenericSpace
+	for i := range spaceList.Items {
+		spaces = append(spaces, &spaceList.Items[i])
+	}
+	for i := range clusterSpaceList.Items {
+		spaces = append(spaces, &clusterSpaceList.Items[i])
+	}
+
+	var result []cfv1alpha1.GenericSpace
+	seen := make(map[string]bool)
+	for _, space := range spaces {
+		guid := space.GetSpec().Guid
+		if guid == "" {
+			guid = space.GetStatus().SpaceGuid
+		}
+		if guid == "" || seen[guid] {
+			continue
+		}
+		seen[guid] = true
+		result = append(result, space)
+	}
+	return result, nil
+}
+
+// listOwners returns the uids of all ServiceInstance and ServiceBinding objects.
+func (r *OrphanCollector) listOwners(ctx context.Context) (map[string]bool, error) {
+	return listOwnerUIDs(ctx, r.Client)
+}
+
+// listOwnerUIDs returns the uids of all ServiceInstance and ServiceBinding objects.
+func listOwnerUIDs(ctx context.Context, c client.Reader) (map[string]bool, error) {
+	owners := make(map[string]bool)
+	serviceInstanceList := &cfv1alpha1.ServiceInstanceList{}
+	if err := c.List(ctx, serviceInstanceList); err != nil {
+		return nil, errors.Wrap(err, "failed to list service instances")
+	}
+	for _, serviceInstance := range serviceInstanceList.Items {
+		owners[string(serviceInstance.UID)] = true
+	}
+	serviceBindingList := &cfv1alpha1.ServiceBindingList{}
+	if err := c.List(ctx, serviceBindingList); err != nil {
+		return nil, errors.Wrap(err, "failed to list service bindings")
+	}
+	for _, serviceBinding := range serviceBindingList.Items {
+		owners[string(serviceBinding.UID)] = true
+	}
+	return owners, nil
+}
+
+// checkAdoptable checks that the Cloud Foundry resource (of the given kind and guid) to be adopted by the given object is not owned by another
+// existing ServiceInstance or ServiceBinding object (as recorded in the owner label of the resource); resources without owner, and resources
+// whose owner is gone (orphans), may be adopted.
+func checkAdoptable(ctx context.Context, c client.Reader, kind string, guid string, owner string, adopter client.Object) error {
+	if owner == "" || owner == string(adopter.GetUID()) {
+		return nil
+	}
+	owners, err := listOwnerUIDs(ctx, c)
+	if err != nil {
+		return err
+	}
+	if owners[owner] {
+		return fmt.Errorf("%s to be adopted is owned by another existing object (uid: %s), guid: %s", kind, owner, guid)
+	}
+	return nil
+}
+
+// ownerResolver resolves the owners of Cloud Foundry resources created by the operator (as recorded in their owner labels) to this cluster.
+type ownerResolver struct {
+	client client.Reader
+	// uids of all ServiceInstance and ServiceBinding objects
+	owners map[string]bool
+	// existence of the namespaces looked up so far
+	namespaces map[string]bool
+	// identifier of this cluster (config clusterId)
+	clusterID string
+}
+
+func newOwnerResolver(ctx context.Context, c client.Reader, cfg *config.Config) (*ownerResolver, error) {
+	owners, err := listOwnerUIDs(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	resolver := &ownerResolver{client: c, owners: owners, namespaces: make(map[string]bool)}
+	if cfg != nil {
+		resolver.clusterID = cfg.ClusterID
+	}
+	return resolver, nil
+}
+
+// resolves checks whether the given owner (recorded by the given cluster) resolves to this cluster; that is, whether the owning object exists,
+// or (since the owner is typically gone when its leftovers are cleaned up) whether the owner was recorded by this cluster (see config clusterId),
+// and the namespace recorded in the owner-namespace label exists in this cluster. Owners without recorded namespace (resources created
+// by older versions of the operator) only resolve if the owning object exists.
+func (o *ownerResolver) resolves(ctx context.Context, owner facade.OwnerRef, ownerCluster string) (bool, error) {
+	if o.owners[owner.UID] {
+		return true, nil
+	}
+	if owner.Namespace == "" || ownerCluster != o.clusterID {
+		return false, nil
+	}
+	exists, ok := o.namespaces[owner.Namespace]
+	if !ok {
+		if err := o.client.Get(ctx, types.NamespacedName{Name: owner.Namespace}, &corev1.Namespace{}); err != nil {
+			if err := client.IgnoreNotFound(err); err != nil {
+				return false, errors.Wrapf(err, "failed to read namespace %s", owner.Namespace)
+			}
+		} else {
+			exists = true
+		}
+		o.namespaces[owner.Namespace] = exists
+	}
+	return exists, nil
+}
+
+func (r *OrphanCollector) newSpaceClient(ctx context.Context, space cfv1alpha1.GenericSpace) (facade.SpaceClient, error) {
+	return newManagedSpaceClient(ctx, r.Client, r.ClusterResourceNamespace, r.ClientBuilder, r.Config, space)
+}
+
+// newManagedSpaceClient returns a client for the Cloud Foundry space managed through the given Space or ClusterSpace object,
+// using the credentials from the object's secret.
+func newManagedSpaceClient(ctx context.Context, c client.Client, clusterResourceNamespace string, clientBuilder facade.SpaceClientBuilder, cfg *config.Config, space cfv1alpha1.GenericSpace) (facade.SpaceClient, error) {
+	secretName, err := getSpaceSecretName(space, clusterResourceNamespace, cfg)
+	if err != nil {
+		return nil, err
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, secretName, secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to get Secret containing space credentials, secret name: %s", secretName)
+	}
+	guid := space.GetSpec().Guid
+	if guid == "" {
+		guid = space.GetStatus().SpaceGuid
+	}
+	return clientBuilder(guid, getEndpoint(space, secret, nil), string(secret.Data["username"]), string(secret.Data["password"]), getClientConfig(getSpaceConfig(cfg, space), secret))
+}
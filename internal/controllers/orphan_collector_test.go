@@ -0,0 +1,162 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+	"github.com/sap/cf-service-operator/internal/facade/facadefakes"
+)
+
+var _ = Describe("Detect and delete orphaned Cloud Foundry resources | OrphanCollector", func() {
+	ctx := context.Background()
+	var spaceClient *facadefakes.FakeSpaceClient
+	var recorder *record.FakeRecorder
+	var collector *OrphanCollector
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		spaceClient = &facadefakes.FakeSpaceClient{}
+		recorder = record.NewFakeRecorder(10)
+		collector = &OrphanCollector{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&cfv1alpha1.Space{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space"},
+					Spec:       cfv1alpha1.SpaceSpec{Guid: "space-guid", AuthSecretName: "space-secret"},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space-secret"},
+					Data:       map[string][]byte{"url": []byte("https://api.cf.example.com")},
+				},
+				&cfv1alpha1.ServiceInstance{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "instance", UID: "instance-uid"},
+				},
+			).Build(),
+			ClientBuilder: func(string, string, string, string, *config.Config) (facade.SpaceClient, error) {
+				return spaceClient, nil
+			},
+			Config:   config.Defaults(),
+			Recorder: recorder,
+			Policy:   config.OrphanPolicyReport,
+		}
+		spaceClient.ListInstancesReturns([]*facade.Instance{
+			{Guid: "instance-guid", Name: "instance", Owner: "instance-uid", State: facade.InstanceStateReady},
+			{Guid: "orphaned-instance-guid", Name: "orphaned-instance", Owner: "deleted-uid", OwnerNamespace: "ns", OwnerName: "orphaned-instance", State: facade.InstanceStateReady},
+			{Guid: "deleting-instance-guid", Name: "deleting-instance", Owner: "deleted-uid-2", State: facade.InstanceStateDeleting},
+		}, nil)
+		spaceClient.ListBindingsReturns([]*facade.Binding{
+			{Guid: "orphaned-binding-guid", Name: "orphaned-binding", Owner: "deleted-uid-3", OwnerNamespace: "ns", OwnerName: "orphaned-binding", State: facade.BindingStateReady},
+		}, nil)
+	})
+
+	It("should report orphans without deleting them", func() {
+		Expect(collector.scan(ctx)).To(Succeed())
+		Expect(collector.scan(ctx)).To(Succeed())
+
+		Expect(recorder.Events).To(HaveLen(4))
+		Expect(<-recorder.Events).To(ContainSubstring("Orphaned Cloud Foundry ServiceInstance orphaned-instance (orphaned-instance-guid)"))
+		Expect(spaceClient.DeleteInstanceCallCount()).To(BeZero())
+		Expect(spaceClient.DeleteBindingCallCount()).To(BeZero())
+	})
+
+	It("should delete resources found orphaned by two consecutive scans", func() {
+		collector.Policy = config.OrphanPolicyDelete
+
+		Expect(collector.scan(ctx)).To(Succeed())
+		Expect(spaceClient.DeleteInstanceCallCount()).To(BeZero())
+		Expect(spaceClient.DeleteBindingCallCount()).To(BeZero())
+
+		Expect(collector.scan(ctx)).To(Succeed())
+		Expect(spaceClient.DeleteBindingCallCount()).To(Equal(1))
+		_, guid, _ := spaceClient.DeleteBindingArgsForCall(0)
+		Expect(guid).To(Equal("orphaned-binding-guid"))
+		Expect(spaceClient.DeleteInstanceCallCount()).To(Equal(1))
+		_, guid, _ = spaceClient.DeleteInstanceArgsForCall(0)
+		Expect(guid).To(Equal("orphaned-instance-guid"))
+	})
+
+	It("should only delete orphans recorded by this cluster, with recorded owning object, of which no namesake exists", func() {
+		collector.Policy = config.OrphanPolicyDelete
+		collector.Config.ClusterID = "eu10"
+		collector.Recorder = record.NewFakeRecorder(20)
+		spaceClient.ListInstancesReturns([]*facade.Instance{
+			{Guid: "other-cluster-guid", Owner: "deleted-uid", OwnerNamespace: "ns", OwnerName: "a", OwnerCluster: "us10", State: facade.InstanceStateReady},
+			{Guid: "no-cluster-guid", Owner: "deleted-uid-2", OwnerNamespace: "ns", OwnerName: "b", State: facade.InstanceStateReady},
+			{Guid: "unrecorded-owner-guid", Owner: "deleted-uid-3", OwnerCluster: "eu10", State: facade.InstanceStateReady},
+			// recreated object (with another uid) will adopt the instance
+			{Guid: "namesake-guid", Owner: "deleted-uid-4", OwnerNamespace: "ns", OwnerName: "instance", OwnerCluster: "eu10", State: facade.InstanceStateReady},
+			{Guid: "orphaned-instance-guid", Owner: "deleted-uid-5", OwnerNamespace: "ns", OwnerName: "c", OwnerCluster: "eu10", State: facade.InstanceStateReady},
+		}, nil)
+		spaceClient.ListBindingsReturns(nil, nil)
+
+		Expect(collector.scan(ctx)).To(Succeed())
+		Expect(collector.scan(ctx)).To(Succeed())
+
+		Expect(spaceClient.DeleteInstanceCallCount()).To(Equal(1))
+		_, guid, _ := spaceClient.DeleteInstanceArgsForCall(0)
+		Expect(guid).To(Equal("orphaned-instance-guid"))
+	})
+
+	It("should not delete anything in observe-only mode", func() {
+		collector.Policy = config.OrphanPolicyDelete
+		collector.Config.ObserveOnly = true
+
+		Expect(collector.scan(ctx)).To(Succeed())
+		Expect(collector.scan(ctx)).To(Succeed())
+
+		Expect(spaceClient.DeleteInstanceCallCount()).To(BeZero())
+		Expect(spaceClient.DeleteBindingCallCount()).To(BeZero())
+	})
+
+	It("should not delete resources which are no longer orphaned", func() {
+		collector.Policy = config.OrphanPolicyDelete
+
+		Expect(collector.scan(ctx)).To(Succeed())
+		spaceClient.ListInstancesReturns(nil, nil)
+		spaceClient.ListBindingsReturns(nil, nil)
+		Expect(collector.scan(ctx)).To(Succeed())
+		Expect(collector.scan(ctx)).To(Succeed())
+
+		Expect(spaceClient.DeleteInstanceCallCount()).To(BeZero())
+		Expect(spaceClient.DeleteBindingCallCount()).To(BeZero())
+	})
+})
+
+var _ = Describe("Check ownership before adoption | checkAdoptable", func() {
+	ctx := context.Background()
+
+	It("should only adopt resources without owner, owned by the adopting object, or whose owner is gone", func() {
+		scheme := runtime.NewScheme()
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&cfv1alpha1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "instance", UID: "instance-uid"}},
+			&cfv1alpha1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "binding", UID: "binding-uid"}},
+		).Build()
+		adopter := &cfv1alpha1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "adopter", UID: "adopter-uid"}}
+
+		Expect(checkAdoptable(ctx, c, "service instance", "guid", "", adopter)).To(Succeed())
+		Expect(checkAdoptable(ctx, c, "service instance", "guid", "adopter-uid", adopter)).To(Succeed())
+		Expect(checkAdoptable(ctx, c, "service instance", "guid", "deleted-uid", adopter)).To(Succeed())
+		Expect(checkAdoptable(ctx, c, "service instance", "guid", "instance-uid", adopter)).
+			To(MatchError("service instance to be adopted is owned by another existing object (uid: instance-uid), guid: guid"))
+		Expect(checkAdoptable(ctx, c, "service binding", "guid", "binding-uid", adopter)).
+			To(MatchError(ContainSubstring("owned by another existing object")))
+	})
+})
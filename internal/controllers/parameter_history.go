@@ -0,0 +1,56 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"bytes"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+)
+
+// Maximum number of entries kept in status.parameterHistory of service instances
+const maxParameterHistory = 10
+
+// recordParameterChange adds the given parameter hash to the parameter history of the service instance, unless the parameters
+// did not change since the last entry; the oldest entries are dropped once the history exceeds its bound.
+func recordParameterChange(serviceInstance *cfv1alpha1.ServiceInstance, parameterHash string) {
+	history := serviceInstance.Status.ParameterHistory
+	if len(history) > 0 && history[len(history)-1].ParameterHash == parameterHash {
+		return
+	}
+	history = append(history, cfv1alpha1.ParameterChange{
+		Generation:    serviceInstance.Generation,
+		ParameterHash: parameterHash,
+		ChangedBy:     getChangedBy(serviceInstance),
+		AppliedAt:     metav1.Now(),
+	})
+	if len(history) > maxParameterHistory {
+		history = history[len(history)-maxParameterHistory:]
+	}
+	serviceInstance.Status.ParameterHistory = history
+}
+
+// getChangedBy tells who changed the given object last; that is the value of the changed-by annotation if present,
+// otherwise the field manager which most recently changed the spec (empty if unknown).
+func getChangedBy(obj metav1.Object) string {
+	if changedBy := obj.GetAnnotations()[cfv1alpha1.AnnotationChangedBy]; changedBy != "" {
+		return changedBy
+	}
+	changedBy := ""
+	var changedAt *metav1.Time
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Subresource != "" || entry.FieldsV1 == nil || !bytes.Contains(entry.FieldsV1.Raw, []byte(`"f:spec"`)) {
+			continue
+		}
+		if changedAt == nil || entry.Time != nil && changedAt.Before(entry.Time) {
+			changedBy = entry.Manager
+			changedAt = entry.Time
+		}
+	}
+	return changedBy
+}
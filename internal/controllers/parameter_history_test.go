@@ -0,0 +1,51 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+)
+
+var _ = Describe("Audit parameter changes | recordParameterChange", func() {
+	It("should record changed parameter hashes only, and keep the history bounded", func() {
+		serviceInstance := &cfv1alpha1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Generation: 1}}
+		recordParameterChange(serviceInstance, "hash-1")
+		serviceInstance.Generation = 2
+		recordParameterChange(serviceInstance, "hash-1")
+		Expect(serviceInstance.Status.ParameterHistory).To(HaveLen(1))
+		Expect(serviceInstance.Status.ParameterHistory[0].Generation).To(Equal(int64(1)))
+
+		for i := 2; i <= maxParameterHistory+2; i++ {
+			serviceInstance.Generation = int64(i)
+			recordParameterChange(serviceInstance, fmt.Sprintf("hash-%d", i))
+		}
+		Expect(serviceInstance.Status.ParameterHistory).To(HaveLen(maxParameterHistory))
+		Expect(serviceInstance.Status.ParameterHistory[0].ParameterHash).To(Equal("hash-3"))
+		Expect(serviceInstance.Status.ParameterHistory[maxParameterHistory-1].ParameterHash).To(Equal(fmt.Sprintf("hash-%d", maxParameterHistory+2)))
+	})
+
+	It("should tell who changed the spec, the annotation taking precedence over the field managers", func() {
+		earlier := metav1.NewTime(time.Now().Add(-time.Hour))
+		later := metav1.Now()
+		serviceInstance := &cfv1alpha1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{ManagedFields: []metav1.ManagedFieldsEntry{
+			{Manager: "kubectl", Time: &earlier, FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:parameters":{}}}`)}},
+			{Manager: "argocd", Time: &later, FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:tags":{}}}`)}},
+			{Manager: "cf-service-operator", Time: &later, Subresource: "status", FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:status":{}}`)}},
+			{Manager: "labeler", Time: &later, FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{}}}`)}},
+		}}}
+		Expect(getChangedBy(serviceInstance)).To(Equal("argocd"))
+
+		serviceInstance.Annotations = map[string]string{cfv1alpha1.AnnotationChangedBy: "pipeline/release-42"}
+		Expect(getChangedBy(serviceInstance)).To(Equal("pipeline/release-42"))
+	})
+})
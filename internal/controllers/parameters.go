@@ -0,0 +1,265 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+// Ready condition reason used (for all kinds) if the parameters do not match the schema published by the service broker
+const readyConditionReasonInvalidParameters = "InvalidParameters"
+
+// interval in which objects with invalid parameters are reconciled again (changes of parameter secrets and config maps are not watched)
+const invalidParametersRequeueInterval = 1 * time.Minute
+
+// getParameters returns the parameters of the given object, merged from the given inline parameters and parameter sources
+// according to the given strategy (see cfv1alpha1.ParametersMergeStrategy); secrets and config maps are read from the namespace
+// of the object, field references are resolved against the object itself.
+func getParameters(ctx context.Context, c client.Reader, obj client.Object, parameters *apiextensionsv1.JSON, parametersFrom []cfv1alpha1.ParametersFromSource, strategy cfv1alpha1.ParametersMergeStrategy) (map[string]interface{}, error) {
+	allowPatches := strategy == cfv1alpha1.ParametersMergeStrategyJSONPatch
+	var documents []parameterDocument
+	if parameters != nil {
+		parameterObject, err := unmarshalObject(parameters.Raw)
+		if err != nil {
+			return nil, errors.Wrap(err, "error decoding inline parameters")
+		}
+		documents = append(documents, parameterDocument{source: "inline parameters", object: parameterObject})
+	}
+	for _, pf := range parametersFrom {
+		var document parameterDocument
+		switch {
+		case pf.SecretKeyRef != nil:
+			secretName := types.NamespacedName{
+				Namespace: obj.GetNamespace(),
+				Name:      pf.SecretKeyRef.Name,
+			}
+			secret := &corev1.Secret{}
+			if err := c.Get(ctx, secretName, secret); err != nil {
+				return nil, errors.Wrapf(err, "failed to get Secret containing parameters, secret name: %s", secretName)
+			}
+			raw, ok := secret.Data[pf.SecretKeyRef.Key]
+			if !ok {
+				return nil, fmt.Errorf("secret key not found, secret name: %s, key: %s", secretName, pf.SecretKeyRef.Key)
+			}
+			var err error
+			document, err = decodeParameterDocument(raw, allowPatches)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error decoding parameters from secret, secret name: %s, key: %s", secretName, pf.SecretKeyRef.Key)
+			}
+			document.source = fmt.Sprintf("secret %s, key %s", secretName, pf.SecretKeyRef.Key)
+		case pf.ConfigMapKeyRef != nil:
+			configMapName := types.NamespacedName{
+				Namespace: obj.GetNamespace(),
+				Name:      pf.ConfigMapKeyRef.Name,
+			}
+			configMap := &corev1.ConfigMap{}
+			if err := c.Get(ctx, configMapName, configMap); err != nil {
+				return nil, errors.Wrapf(err, "failed to get ConfigMap containing parameters, config map name: %s", configMapName)
+			}
+			raw, ok := configMap.Data[pf.ConfigMapKeyRef.Key]
+			if !ok {
+				return nil, fmt.Errorf("config map key not found, config map name: %s, key: %s", configMapName, pf.ConfigMapKeyRef.Key)
+			}
+			var err error
+			document, err = decodeParameterDocument([]byte(raw), allowPatches)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error decoding parameters from config map, config map name: %s, key: %s", configMapName, pf.ConfigMapKeyRef.Key)
+			}
+			document.source = fmt.Sprintf("config map %s, key %s", configMapName, pf.ConfigMapKeyRef.Key)
+		case pf.FieldRef != nil:
+			value, err := pf.FieldRef.Resolve(obj)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error resolving parameter %s", pf.FieldRef.Parameter)
+			}
+			document = parameterDocument{source: "field " + pf.FieldRef.FieldPath, object: map[string]interface{}{pf.FieldRef.Parameter: value}}
+		default:
+			return nil, fmt.Errorf("invalid parameter source; one of secretKeyRef, configMapKeyRef or fieldRef must be specified")
+		}
+		document.priority = pf.Priority
+		documents = append(documents, document)
+	}
+
+	result, err := mergeParameterDocuments(documents, strategy)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal/merge parameters")
+	}
+	return result, nil
+}
+
+// parameterDocument holds the inline parameters, or the content of a parameter source, of an object;
+// exactly one of object and patch is set (patches only with merge strategy JSONPatch).
+type parameterDocument struct {
+	// origin of the document (for error messages)
+	source   string
+	priority int32
+	object   map[string]interface{}
+	patch    jsonpatch.Patch
+}
+
+// decodeParameterDocument decodes the given parameters, which must be an object, or - if allowPatches is true - a JSON patch.
+func decodeParameterDocument(raw []byte, allowPatches bool) (parameterDocument, error) {
+	if allowPatches && bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		patch, err := jsonpatch.DecodePatch(raw)
+		if err != nil {
+			return parameterDocument{}, err
+		}
+		return parameterDocument{patch: patch}, nil
+	}
+	object, err := unmarshalObject(raw)
+	if err != nil {
+		return parameterDocument{}, err
+	}
+	return parameterDocument{object: object}, nil
+}
+
+// mergeParameterDocuments merges the given documents according to the given strategy, in ascending order of their priority
+// (documents of the same priority in the given order); the result is nil if there are no documents (or all of them are null).
+func mergeParameterDocuments(documents []parameterDocument, strategy cfv1alpha1.ParametersMergeStrategy) (map[string]interface{}, error) {
+	sort.SliceStable(documents, func(i, j int) bool { return documents[i].priority < documents[j].priority })
+
+	switch strategy {
+	case "", cfv1alpha1.ParametersMergeStrategyError:
+		objects := make([]map[string]interface{}, 0, len(documents))
+		for _, document := range documents {
+			objects = append(objects, document.object)
+		}
+		return mergeObjects(objects...)
+	case cfv1alpha1.ParametersMergeStrategyShallow, cfv1alpha1.ParametersMergeStrategyDeep, cfv1alpha1.ParametersMergeStrategyJSONPatch:
+		var result map[string]interface{}
+		for _, document := range documents {
+			if document.patch != nil {
+				if result == nil {
+					return nil, fmt.Errorf("JSON patch from %s has nothing to be applied to; it must be preceded by an object", document.source)
+				}
+				raw, err := json.Marshal(result)
+				if err != nil {
+					return nil, err
+				}
+				if raw, err = document.patch.Apply(raw); err != nil {
+					return nil, errors.Wrapf(err, "error applying JSON patch from %s", document.source)
+				}
+				if result, err = unmarshalObject(raw); err != nil {
+					return nil, errors.Wrapf(err, "error decoding result of JSON patch from %s", document.source)
+				}
+				continue
+			}
+			if document.object == nil {
+				continue
+			}
+			if result == nil {
+				result = make(map[string]interface{})
+			}
+			if strategy == cfv1alpha1.ParametersMergeStrategyDeep {
+				deepMergeObject(result, document.object)
+			} else {
+				for key, value := range document.object {
+					result[key] = value
+				}
+			}
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unknown parameters merge strategy: %s", strategy)
+	}
+}
+
+// deepMergeObject merges source into target recursively: objects present in both are merged, all other values of source
+// (including arrays) replace the ones of target.
+func deepMergeObject(target map[string]interface{}, source map[string]interface{}) {
+	for key, value := range source {
+		sourceObject, ok := value.(map[string]interface{})
+		targetObject, isObject := target[key].(map[string]interface{})
+		if ok && isObject {
+			deepMergeObject(targetObject, sourceObject)
+		} else {
+			target[key] = value
+		}
+	}
+}
+
+// getServicePlanSchemas returns the parameter schemas of the given service plan; if the plan is unknown,
+// empty schemas are returned (such that parameters are not validated).
+func getServicePlanSchemas(ctx context.Context, client facade.SpaceClient, servicePlanGuid string, spaceGuid string) (*facade.ServicePlanSchemas, error) {
+	schemas, err := client.GetServicePlanSchemas(ctx, servicePlanGuid, spaceGuid)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get parameter schemas of service plan %s", servicePlanGuid)
+	}
+	if schemas == nil {
+		schemas = &facade.ServicePlanSchemas{}
+	}
+	return schemas, nil
+}
+
+// validateParameters validates the given parameters against the given JSON schema (as published by the service broker).
+// Parameters are not validated if there is no schema, if no parameters are passed at all, or if the schema cannot be
+// interpreted (for example because it uses references); the broker remains the final judge in that case.
+func validateParameters(schema []byte, parameters map[string]interface{}) (err error) {
+	if schema == nil || parameters == nil {
+		return nil
+	}
+	s := &spec.Schema{}
+	if err := json.Unmarshal(schema, s); err != nil {
+		return nil
+	}
+	defer func() {
+		// the validator panics on unsupported schemas
+		if r := recover(); r != nil {
+			err = nil
+		}
+	}()
+	result := validate.NewSchemaValidator(s, nil, "", strfmt.Default).Validate(parameters)
+	if !result.HasErrors() {
+		return nil
+	}
+	messages := make([]string, 0, len(result.Errors))
+	for _, err := range result.Errors {
+		messages = append(messages, err.Error())
+	}
+	sort.Strings(messages)
+	return fmt.Errorf("parameters do not match the schema published by the service broker: %s", strings.Join(messages, "; "))
+}
+
+// getDriftedParameters compares the desired parameters with the live parameters reported by the service broker, and returns
+// the (sorted) top-level keys whose values differ; keys which are only present in the live parameters (such as broker defaults)
+// are ignored. Values are compared after a JSON round trip, so that numbers compare equal regardless of their Go type.
+func getDriftedParameters(desired map[string]interface{}, live map[string]interface{}) ([]string, error) {
+	raw, err := json.Marshal(desired)
+	if err != nil {
+		return nil, errors.Wrap(err, "error encoding parameters")
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(raw, &normalized); err != nil {
+		return nil, errors.Wrap(err, "error decoding parameters")
+	}
+	var drifted []string
+	for key, value := range normalized {
+		if liveValue, ok := live[key]; !ok || !reflect.DeepEqual(value, liveValue) {
+			drifted = append(drifted, key)
+		}
+	}
+	sort.Strings(drifted)
+	return drifted, nil
+}
@@ -0,0 +1,186 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+)
+
+var _ = Describe("Parameter sources | getParameters", func() {
+	ctx := context.Background()
+	var c client.Client
+	var serviceInstance *cfv1alpha1.ServiceInstance
+
+	BeforeEach(func() {
+		c = fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "params"},
+				Data:       map[string][]byte{"parameters.json": []byte(`{"password": "secret"}`)},
+			},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "params"},
+				Data:       map[string]string{"parameters.json": `{"plan": {"size": "small"}}`},
+			},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "other-params"},
+				Data:       map[string]string{"parameters.json": `{}`},
+			},
+		).Build()
+		serviceInstance = &cfv1alpha1.ServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "instance", Labels: map[string]string{"team": "blue"}},
+		}
+	})
+
+	get := func(parametersFrom ...cfv1alpha1.ParametersFromSource) (map[string]interface{}, error) {
+		return getParameters(ctx, c, serviceInstance, &apiextensionsv1.JSON{Raw: []byte(`{"xsappname": "app"}`)}, parametersFrom, "")
+	}
+
+	It("should merge inline parameters, secrets, config maps and fields", func() {
+		parameters, err := get(
+			cfv1alpha1.ParametersFromSource{SecretKeyRef: &cfv1alpha1.SecretKeyReference{Name: "params", Key: "parameters.json"}},
+			cfv1alpha1.ParametersFromSource{ConfigMapKeyRef: &cfv1alpha1.ConfigMapKeyReference{Name: "params", Key: "parameters.json"}},
+			cfv1alpha1.ParametersFromSource{FieldRef: &cfv1alpha1.ParameterFieldReference{FieldPath: "metadata.namespace", Parameter: "namespace"}},
+			cfv1alpha1.ParametersFromSource{FieldRef: &cfv1alpha1.ParameterFieldReference{FieldPath: "metadata.name", Parameter: "name"}},
+			cfv1alpha1.ParametersFromSource{FieldRef: &cfv1alpha1.ParameterFieldReference{FieldPath: "metadata.labels['team']", Parameter: "team"}},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parameters).To(Equal(map[string]interface{}{
+			"xsappname": "app",
+			"password":  "secret",
+			"plan":      map[string]interface{}{"size": "small"},
+			"namespace": "ns",
+			"name":      "instance",
+			"team":      "blue",
+		}))
+	})
+
+	It("should only read config maps of the object's namespace", func() {
+		_, err := get(cfv1alpha1.ParametersFromSource{ConfigMapKeyRef: &cfv1alpha1.ConfigMapKeyReference{Name: "other-params", Key: "parameters.json"}})
+		Expect(err).To(MatchError(ContainSubstring("failed to get ConfigMap containing parameters")))
+	})
+
+	It("should fail on missing config map keys and labels", func() {
+		_, err := get(cfv1alpha1.ParametersFromSource{ConfigMapKeyRef: &cfv1alpha1.ConfigMapKeyReference{Name: "params", Key: "missing.json"}})
+		Expect(err).To(MatchError(ContainSubstring("config map key not found")))
+
+		_, err = get(cfv1alpha1.ParametersFromSource{FieldRef: &cfv1alpha1.ParameterFieldReference{FieldPath: "metadata.labels['missing']", Parameter: "missing"}})
+		Expect(err).To(MatchError(ContainSubstring("label missing not found")))
+	})
+
+	It("should reject duplicate top level keys across sources", func() {
+		_, err := get(cfv1alpha1.ParametersFromSource{FieldRef: &cfv1alpha1.ParameterFieldReference{FieldPath: "metadata.name", Parameter: "xsappname"}})
+		Expect(err).To(MatchError(ContainSubstring("key: xsappname")))
+	})
+})
+
+var _ = Describe("Parameter merge strategies | getParameters", func() {
+	ctx := context.Background()
+	var c client.Client
+	var serviceInstance *cfv1alpha1.ServiceInstance
+	base := &apiextensionsv1.JSON{Raw: []byte(`{"plan": {"size": "small", "zones": ["a", "b"]}, "env": "dev"}`)}
+	fromConfigMap := func(key string, priority int32) cfv1alpha1.ParametersFromSource {
+		return cfv1alpha1.ParametersFromSource{ConfigMapKeyRef: &cfv1alpha1.ConfigMapKeyReference{Name: "overrides", Key: key}, Priority: priority}
+	}
+
+	BeforeEach(func() {
+		c = fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "overrides"},
+				Data: map[string]string{
+					"prod.json":  `{"plan": {"size": "large", "zones": ["c"]}, "env": "prod"}`,
+					"eu.json":    `{"env": "prod-eu"}`,
+					"patch.json": `[{"op": "replace", "path": "/plan/size", "value": "medium"}, {"op": "remove", "path": "/env"}]`,
+				},
+			},
+		).Build()
+		serviceInstance = &cfv1alpha1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "instance"}}
+	})
+
+	It("should replace top level keys with strategy Shallow, in the order of priority", func() {
+		parameters, err := getParameters(ctx, c, serviceInstance, base, []cfv1alpha1.ParametersFromSource{fromConfigMap("eu.json", 2), fromConfigMap("prod.json", 1)}, cfv1alpha1.ParametersMergeStrategyShallow)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parameters).To(Equal(map[string]interface{}{
+			"plan": map[string]interface{}{"size": "large", "zones": []interface{}{"c"}},
+			"env":  "prod-eu",
+		}))
+	})
+
+	It("should merge objects recursively with strategy Deep, and let the inline parameters precede sources of the same priority", func() {
+		parameters, err := getParameters(ctx, c, serviceInstance, base, []cfv1alpha1.ParametersFromSource{fromConfigMap("prod.json", 0)}, cfv1alpha1.ParametersMergeStrategyDeep)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parameters).To(Equal(map[string]interface{}{
+			"plan": map[string]interface{}{"size": "large", "zones": []interface{}{"c"}},
+			"env":  "prod",
+		}))
+
+		parameters, err = getParameters(ctx, c, serviceInstance, base, []cfv1alpha1.ParametersFromSource{fromConfigMap("prod.json", -1)}, cfv1alpha1.ParametersMergeStrategyDeep)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parameters["env"]).To(Equal("dev"))
+	})
+
+	It("should apply JSON patches with strategy JSONPatch", func() {
+		parameters, err := getParameters(ctx, c, serviceInstance, base, []cfv1alpha1.ParametersFromSource{fromConfigMap("patch.json", 0)}, cfv1alpha1.ParametersMergeStrategyJSONPatch)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parameters).To(Equal(map[string]interface{}{
+			"plan": map[string]interface{}{"size": "medium", "zones": []interface{}{"a", "b"}},
+		}))
+
+		_, err = getParameters(ctx, c, serviceInstance, nil, []cfv1alpha1.ParametersFromSource{fromConfigMap("patch.json", 0)}, cfv1alpha1.ParametersMergeStrategyJSONPatch)
+		Expect(err).To(MatchError(ContainSubstring("must be preceded by an object")))
+	})
+
+	It("should reject JSON patches and duplicate keys with strategy Error", func() {
+		_, err := getParameters(ctx, c, serviceInstance, base, []cfv1alpha1.ParametersFromSource{fromConfigMap("patch.json", 0)}, cfv1alpha1.ParametersMergeStrategyError)
+		Expect(err).To(MatchError(ContainSubstring("error decoding parameters from config map")))
+
+		_, err = getParameters(ctx, c, serviceInstance, base, []cfv1alpha1.ParametersFromSource{fromConfigMap("eu.json", 1)}, cfv1alpha1.ParametersMergeStrategyError)
+		Expect(err).To(MatchError(ContainSubstring("key: env")))
+	})
+})
+
+var _ = Describe("Parameter schemas | validateParameters", func() {
+	schema := []byte(`{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"type": "object",
+		"properties": {
+			"size": {"type": "integer", "minimum": 1},
+			"region": {"type": "string", "enum": ["eu", "us"]}
+		},
+		"required": ["size"],
+		"additionalProperties": false
+	}`)
+
+	It("should accept matching parameters", func() {
+		Expect(validateParameters(schema, map[string]interface{}{"size": float64(2), "region": "eu"})).To(Succeed())
+	})
+
+	It("should reject parameters not matching the schema", func() {
+		err := validateParameters(schema, map[string]interface{}{"region": "asia", "color": "red"})
+		Expect(err).To(MatchError(And(
+			ContainSubstring("parameters do not match the schema published by the service broker"),
+			ContainSubstring("size"),
+			ContainSubstring("region"),
+			ContainSubstring("color"),
+		)))
+	})
+
+	It("should skip validation without schema, without parameters, or for unsupported schemas", func() {
+		Expect(validateParameters(nil, map[string]interface{}{"color": "red"})).To(Succeed())
+		Expect(validateParameters(schema, nil)).To(Succeed())
+		Expect(validateParameters([]byte(`{"$ref": "#/definitions/parameters"}`), map[string]interface{}{"color": "red"})).To(Succeed())
+	})
+})
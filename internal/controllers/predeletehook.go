@@ -0,0 +1,140 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+)
+
+const (
+	// Default time after which an uncompleted pre-delete hook job is considered failed
+	preDeleteHookDefaultTimeout = 1 * time.Hour
+	// Interval in which the pre-delete hook job is checked while running
+	preDeleteHookPollingInterval = 10 * time.Second
+)
+
+// runPreDeleteHook runs the pre-delete hook job of the given service instance (if spec.preDeleteHook is set), and tells whether
+// the Cloud Foundry instance may be deleted; this is the case once the job succeeded, or if it failed (or timed out) and the failure
+// policy is Ignore. Otherwise, the DeletionBlocked condition is set, and the returned result tells when to check again.
+// The job (created by the user, typically suspended) is resumed on the first call; its state is tracked in status.preDeleteHook.
+// The operator never creates jobs itself, since the pods of such jobs would run with the operator's permissions rather than the user's.
+func (r *ServiceInstanceReconciler) runPreDeleteHook(ctx context.Context, serviceInstance *cfv1alpha1.ServiceInstance) (bool, ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	hook := serviceInstance.Spec.PreDeleteHook
+	if hook == nil {
+		return true, ctrl.Result{}, nil
+	}
+	status := serviceInstance.Status.PreDeleteHook
+	if status == nil || status.Phase == cfv1alpha1.PreDeleteHookPhaseRunning {
+		var err error
+		status, err = r.syncPreDeleteHookJob(ctx, serviceInstance, status)
+		if err != nil {
+			return false, ctrl.Result{}, err
+		}
+		serviceInstance.Status.PreDeleteHook = status
+	}
+
+	switch status.Phase {
+	case cfv1alpha1.PreDeleteHookPhaseSucceeded:
+		return true, ctrl.Result{}, nil
+	case cfv1alpha1.PreDeleteHookPhaseFailed, cfv1alpha1.PreDeleteHookPhaseTimedOut:
+		message := fmt.Sprintf("Pre-delete hook job %s failed: %s", status.JobName, status.Message)
+		if hook.FailurePolicy == cfv1alpha1.PreDeleteHookFailurePolicyIgnore {
+			log.Info("Pre-delete hook failed; deleting instance anyway (failure policy Ignore)", "job", status.JobName, "message", status.Message)
+			return true, ctrl.Result{}, nil
+		}
+		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceInstanceReadyConditionReasonDeletionBlocked, message)
+		serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionDeletionBlocked, cfv1alpha1.ConditionTrue, serviceInstanceDeletionBlockedConditionReasonPreDeleteHookFailed, message+" (remove spec.preDeleteHook to delete the instance anyway)")
+		return false, ctrl.Result{RequeueAfter: preDeleteHookPollingInterval}, nil
+	default:
+		message := fmt.Sprintf("Waiting for completion of pre-delete hook job %s", status.JobName)
+		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceInstanceReadyConditionReasonDeletionBlocked, message)
+		serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionDeletionBlocked, cfv1alpha1.ConditionTrue, serviceInstanceDeletionBlockedConditionReasonPreDeleteHookRunning, message)
+		return false, ctrl.Result{RequeueAfter: preDeleteHookPollingInterval}, nil
+	}
+}
+
+// syncPreDeleteHookJob resumes the pre-delete hook job of the given service instance (if suspended), and returns the resulting
+// status of the hook, given the previous one (nil if the job was not resumed yet). A missing job counts as failure.
+func (r *ServiceInstanceReconciler) syncPreDeleteHookJob(ctx context.Context, serviceInstance *cfv1alpha1.ServiceInstance, previous *cfv1alpha1.PreDeleteHookStatus) (*cfv1alpha1.PreDeleteHookStatus, error) {
+	log := ctrl.LoggerFrom(ctx)
+	hook := serviceInstance.Spec.PreDeleteHook
+	status := &cfv1alpha1.PreDeleteHookStatus{JobName: hook.JobName, Phase: cfv1alpha1.PreDeleteHookPhaseRunning}
+	if previous != nil {
+		status.StartedAt = previous.StartedAt
+	}
+
+	now := metav1.Now()
+	job := &batchv1.Job{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: serviceInstance.Namespace, Name: hook.JobName}, job); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, errors.Wrap(err, "failed to get pre-delete hook job")
+		}
+		status.Phase = cfv1alpha1.PreDeleteHookPhaseFailed
+		status.CompletedAt = &now
+		status.Message = "job not found"
+		return status, nil
+	}
+	if job.Spec.Suspend != nil && *job.Spec.Suspend && previous == nil {
+		log.V(1).Info("Resuming pre-delete hook job", "job", hook.JobName)
+		if err := r.Patch(ctx, job, client.RawPatch(types.MergePatchType, []byte(`{"spec":{"suspend":false}}`))); err != nil {
+			return nil, errors.Wrap(err, "failed to resume pre-delete hook job")
+		}
+	}
+	if status.StartedAt == nil {
+		status.StartedAt = &now
+	}
+
+	if condition := getJobCondition(job, batchv1.JobComplete); condition != nil && condition.Status == corev1.ConditionTrue {
+		status.Phase = cfv1alpha1.PreDeleteHookPhaseSucceeded
+		status.CompletedAt = &now
+	} else if condition := getJobCondition(job, batchv1.JobFailed); condition != nil && condition.Status == corev1.ConditionTrue {
+		status.Phase = cfv1alpha1.PreDeleteHookPhaseFailed
+		status.CompletedAt = &now
+		status.Message = condition.Message
+	} else if timeout := getPreDeleteHookTimeout(hook); now.Sub(status.StartedAt.Time) > timeout {
+		status.Phase = cfv1alpha1.PreDeleteHookPhaseTimedOut
+		status.CompletedAt = &now
+		status.Message = fmt.Sprintf("job did not complete within %s", timeout)
+		// stop the job (by suspending it again), so that it does not interfere with the deletion of the Cloud Foundry instance
+		log.V(1).Info("Suspending timed out pre-delete hook job", "job", hook.JobName)
+		if err := r.Patch(ctx, job, client.RawPatch(types.MergePatchType, []byte(`{"spec":{"suspend":true}}`))); client.IgnoreNotFound(err) != nil {
+			return nil, errors.Wrap(err, "failed to suspend pre-delete hook job")
+		}
+	}
+	return status, nil
+}
+
+// getPreDeleteHookTimeout returns the timeout of the given pre-delete hook, or the default timeout if none is specified.
+func getPreDeleteHookTimeout(hook *cfv1alpha1.PreDeleteHook) time.Duration {
+	if hook.Timeout != nil && hook.Timeout.Duration > 0 {
+		return hook.Timeout.Duration
+	}
+	return preDeleteHookDefaultTimeout
+}
+
+// getJobCondition returns the condition of the given type of the given job (nil if there is none).
+func getJobCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		if job.Status.Conditions[i].Type == conditionType {
+			return &job.Status.Conditions[i]
+		}
+	}
+	return nil
+}
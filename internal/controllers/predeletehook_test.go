@@ -0,0 +1,129 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+)
+
+var _ = Describe("Pre-delete hook | runPreDeleteHook", func() {
+	ctx := context.Background()
+	var reconciler *ServiceInstanceReconciler
+	var serviceInstance *cfv1alpha1.ServiceInstance
+	jobKey := types.NamespacedName{Namespace: "default", Name: "export"}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		suspend := true
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Namespace: jobKey.Namespace, Name: jobKey.Name},
+			Spec: batchv1.JobSpec{Suspend: &suspend, Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers:    []corev1.Container{{Name: "export", Image: "export:latest"}},
+				RestartPolicy: corev1.RestartPolicyNever,
+			}}},
+		}
+		reconciler = &ServiceInstanceReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(job).WithStatusSubresource(job).Build(), Scheme: scheme}
+		serviceInstance = &cfv1alpha1.ServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "instance", UID: "instance-uid"},
+			Spec:       cfv1alpha1.ServiceInstanceSpec{PreDeleteHook: &cfv1alpha1.PreDeleteHook{JobName: jobKey.Name}},
+		}
+	})
+
+	getJob := func() *batchv1.Job {
+		job := &batchv1.Job{}
+		Expect(reconciler.Get(ctx, jobKey, job)).To(Succeed())
+		return job
+	}
+
+	setJobCondition := func(conditionType batchv1.JobConditionType, message string) {
+		job := getJob()
+		job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{Type: conditionType, Status: corev1.ConditionTrue, Message: message})
+		Expect(reconciler.Status().Update(ctx, job)).To(Succeed())
+	}
+
+	It("should proceed immediately if no hook is specified", func() {
+		serviceInstance.Spec.PreDeleteHook = nil
+		proceed, _, err := reconciler.runPreDeleteHook(ctx, serviceInstance)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(proceed).To(BeTrue())
+	})
+
+	It("should resume the job, and block the deletion until the job completes", func() {
+		proceed, result, err := reconciler.runPreDeleteHook(ctx, serviceInstance)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(proceed).To(BeFalse())
+		Expect(result.RequeueAfter).To(Equal(preDeleteHookPollingInterval))
+		Expect(serviceInstance.Status.PreDeleteHook.Phase).To(Equal(cfv1alpha1.PreDeleteHookPhaseRunning))
+		Expect(serviceInstance.Status.PreDeleteHook.JobName).To(Equal(jobKey.Name))
+		Expect(serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionDeletionBlocked).Reason).To(Equal(serviceInstanceDeletionBlockedConditionReasonPreDeleteHookRunning))
+
+		job := getJob()
+		Expect(*job.Spec.Suspend).To(BeFalse())
+		Expect(job.OwnerReferences).To(BeEmpty())
+
+		setJobCondition(batchv1.JobComplete, "")
+		proceed, _, err = reconciler.runPreDeleteHook(ctx, serviceInstance)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(proceed).To(BeTrue())
+		Expect(serviceInstance.Status.PreDeleteHook.Phase).To(Equal(cfv1alpha1.PreDeleteHookPhaseSucceeded))
+	})
+
+	It("should keep the deletion blocked if the job fails, unless failures are ignored", func() {
+		_, _, err := reconciler.runPreDeleteHook(ctx, serviceInstance)
+		Expect(err).ToNot(HaveOccurred())
+		setJobCondition(batchv1.JobFailed, "BackoffLimitExceeded")
+
+		proceed, _, err := reconciler.runPreDeleteHook(ctx, serviceInstance)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(proceed).To(BeFalse())
+		Expect(serviceInstance.Status.PreDeleteHook.Phase).To(Equal(cfv1alpha1.PreDeleteHookPhaseFailed))
+		Expect(serviceInstance.Status.PreDeleteHook.Message).To(Equal("BackoffLimitExceeded"))
+		Expect(serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionDeletionBlocked).Reason).To(Equal(serviceInstanceDeletionBlockedConditionReasonPreDeleteHookFailed))
+
+		serviceInstance.Spec.PreDeleteHook.FailurePolicy = cfv1alpha1.PreDeleteHookFailurePolicyIgnore
+		proceed, _, err = reconciler.runPreDeleteHook(ctx, serviceInstance)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(proceed).To(BeTrue())
+	})
+
+	It("should consider a missing job as failed", func() {
+		serviceInstance.Spec.PreDeleteHook.JobName = "missing"
+		proceed, _, err := reconciler.runPreDeleteHook(ctx, serviceInstance)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(proceed).To(BeFalse())
+		Expect(serviceInstance.Status.PreDeleteHook.Phase).To(Equal(cfv1alpha1.PreDeleteHookPhaseFailed))
+		Expect(serviceInstance.Status.PreDeleteHook.Message).To(Equal("job not found"))
+	})
+
+	It("should suspend the job again once it times out", func() {
+		serviceInstance.Spec.PreDeleteHook.Timeout = &metav1.Duration{Duration: time.Minute}
+		_, _, err := reconciler.runPreDeleteHook(ctx, serviceInstance)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*getJob().Spec.Suspend).To(BeFalse())
+
+		serviceInstance.Status.PreDeleteHook.StartedAt = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+		proceed, _, err := reconciler.runPreDeleteHook(ctx, serviceInstance)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(proceed).To(BeFalse())
+		Expect(serviceInstance.Status.PreDeleteHook.Phase).To(Equal(cfv1alpha1.PreDeleteHookPhaseTimedOut))
+		Expect(*getJob().Spec.Suspend).To(BeTrue())
+	})
+})
@@ -10,32 +10,96 @@ Package controllers contains the implementation of various helper functions used
 package controllers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
+	pkgerrors "github.com/pkg/errors"
 	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/wait"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
-// setMaxRetries sets the maximum number of retries for a service instance based on the value provided in the annotations
-// or uses the default value if the annotation is not set or is invalid.
-// TODO: Make it Generic so applies to Space and ServiceBinding.
-// TODO: Add a test for this function.
-func setMaxRetries(serviceInstance *cfv1alpha1.ServiceInstance, log logr.Logger) {
+// Keys of the space secret overriding the operator-wide connection settings
+const (
+	spaceSecretKeyCABundle = "ca.crt"
+	spaceSecretKeyProxy    = "proxy"
+)
+
+// Ready condition reason used (for all kinds) while the Cloud Foundry API endpoint is considered unavailable
+const readyConditionReasonCFUnavailable = "CFUnavailable"
+
+// Reasons of the Synced, CredentialsReady, DeletionBlocked, CFReachable and Timeout conditions (used for all kinds);
+// additionally, readyConditionReasonCFUnavailable is used as reason of a False CFReachable condition
+const (
+	conditionReasonSynced            = "Synced"
+	conditionReasonError             = "Error"
+	conditionReasonCredentialsValid  = "CredentialsValid"
+	conditionReasonSecretStored      = "SecretStored"
+	conditionReasonDependentsExist   = "DependentsExist"
+	conditionReasonForeignFinalizers = "ForeignFinalizers"
+	conditionReasonReachable         = "Reachable"
+	conditionReasonObserveOnly       = "ObserveOnly"
+	conditionReasonDryRun            = "DryRun"
+	conditionReasonPaused            = "Paused"
+	conditionReasonReconcileTimeout  = "ReconcileTimeout"
+)
+
+// Ready condition reason used (for all kinds) in observe-only mode if the Cloud Foundry resource does not exist
+const readyConditionReasonNotFound = "NotFound"
+
+// describeError returns the condition reason and message describing the given error; errors returned by the Cloud Foundry API
+// are described by a reason classifying the error (such as QuotaExceeded, see facade.CFError) and the error detail reported
+// by Cloud Foundry, all other errors by the given default reason and the full error string.
+func describeError(err error, defaultReason string) (string, string) {
+	cfErr := facade.ClassifyError(err)
+	if cfErr == nil {
+		return defaultReason, err.Error()
+	}
+	return cfErr.Reason(), cfErr.Message()
+}
+
+// getCFError returns the error returned by the Cloud Foundry API contained in the given error, as reported in the status
+// of service instances and bindings (nil if there is none).
+func getCFError(err error) *cfv1alpha1.CFError {
+	cfErr := facade.ClassifyError(err)
+	if cfErr == nil {
+		return nil
+	}
+	return &cfv1alpha1.CFError{Code: cfErr.Code, Title: cfErr.Title, Detail: cfErr.Detail}
+}
+
+// setMaxRetries sets the maximum number of retries for a service instance based on the value provided in the given annotations
+// (usually the effective annotations, see getEffectiveAnnotations) or uses the default value if the annotation is not set or is invalid.
+func setMaxRetries(serviceInstance *cfv1alpha1.ServiceInstance, annotations map[string]string, log logr.Logger) {
 	// Default to an infinite number of retries
-	serviceInstance.Status.MaxRetries = serviceInstanceDefaultMaxRetries
+	serviceInstance.Status.MaxRetries = getMaxRetries(annotations, serviceInstanceDefaultMaxRetries, log)
+}
 
+// getMaxRetries returns the maximum number of retries given in the annotations (usually the effective annotations, see getEffectiveAnnotations),
+// or the given default if the annotation is not set or is invalid.
+func getMaxRetries(annotations map[string]string, defaultMaxRetries int, log logr.Logger) int {
 	// Use max retries from annotation
-	maxRetriesStr, found := serviceInstance.GetAnnotations()[cfv1alpha1.AnnotationMaxRetries]
+	maxRetriesStr, found := annotations[cfv1alpha1.AnnotationMaxRetries]
 	if found {
 		maxRetries, err := strconv.Atoi(maxRetriesStr)
 		if err != nil {
 			log.V(1).Info("Invalid max retries annotation value, using default", "AnnotationMaxRetries", maxRetriesStr)
 		} else {
-			serviceInstance.Status.MaxRetries = maxRetries
+			return maxRetries
 		}
 	}
+	return defaultMaxRetries
 }
 
 // getReconcileTimeout reads the reconcile timeout from the annotation on the service instance
@@ -77,3 +141,294 @@ func getPollingInterval(annotations map[string]string, defaultDurationStr, annot
 
 	return ctrl.Result{RequeueAfter: defaultDuration}
 }
+
+// built-in intervals in which ready objects are polled, by kind, unless configured otherwise
+var defaultPollingIntervalsReady = map[string]string{
+	"Space":        "60s",
+	"ClusterSpace": "60s",
+}
+
+// getDefaultPollingIntervalReady returns the default interval (to be passed to getPollingInterval) in which ready objects
+// of the given kind are polled; as configured by pollingIntervalsReady, or else built-in (10 minutes, unless specified otherwise).
+func getDefaultPollingIntervalReady(cfg *config.Config, kind string) string {
+	cfg = withReloadedConfig(cfg)
+	if cfg != nil {
+		if interval, ok := cfg.PollingIntervalsReady[kind]; ok {
+			return interval.Duration.String()
+		}
+	}
+	if interval, ok := defaultPollingIntervalsReady[kind]; ok {
+		return interval
+	}
+	return "10m"
+}
+
+// getDefaultPollingIntervalFail returns the default interval (to be passed to getPollingInterval) in which failed objects
+// of the given kind are polled; as configured by pollingIntervalsFail, or else empty (failed objects are not polled).
+func getDefaultPollingIntervalFail(cfg *config.Config, kind string) string {
+	cfg = withReloadedConfig(cfg)
+	if cfg != nil {
+		if interval, ok := cfg.PollingIntervalsFail[kind]; ok {
+			return interval.Duration.String()
+		}
+	}
+	return ""
+}
+
+// getRefreshCredentialsInterval returns the interval at which the credentials of a service binding shall be re-read,
+// as specified by the annotation service-operator.cf.cs.sap.com/refresh-credentials-interval;
+// zero is returned if the annotation is not set or invalid.
+func getRefreshCredentialsInterval(annotations map[string]string) time.Duration {
+	refreshIntervalStr, ok := annotations[cfv1alpha1.AnnotationRefreshCredentialsInterval]
+	if !ok {
+		return 0
+	}
+	refreshInterval, err := time.ParseDuration(refreshIntervalStr)
+	if err != nil || refreshInterval < 0 {
+		return 0
+	}
+	return refreshInterval
+}
+
+// getStalledOperationTimeout returns the time after which an operation on a Cloud Foundry instance which is still in progress
+// is considered stalled, as specified by the annotation service-operator.cf.cs.sap.com/stalled-operation-timeout,
+// or by the configuration key stalledOperationTimeout (if the annotation is not set or invalid); zero disables the detection.
+func getStalledOperationTimeout(cfg *config.Config, annotations map[string]string) time.Duration {
+	if timeoutStr, ok := annotations[cfv1alpha1.AnnotationStalledOperationTimeout]; ok {
+		if timeout, err := time.ParseDuration(timeoutStr); err == nil && timeout >= 0 {
+			return timeout
+		}
+	}
+	if cfg == nil {
+		return 0
+	}
+	return cfg.StalledOperationTimeout.Duration
+}
+
+// isObserveOnly returns whether an object with the given annotations is reconciled in observe-only mode, either operator-wide
+// (configuration key observeOnly), or through the annotation service-operator.cf.cs.sap.com/observe-only;
+// the annotation cannot disable the operator-wide setting.
+func isObserveOnly(cfg *config.Config, annotations map[string]string) bool {
+	if cfg != nil && cfg.ObserveOnly {
+		return true
+	}
+	return annotations[cfv1alpha1.AnnotationObserveOnly] == "true"
+}
+
+// isPaused returns whether the reconciliation of an object with the given annotations is paused
+// through the annotation service-operator.cf.cs.sap.com/paused.
+func isPaused(annotations map[string]string) bool {
+	return annotations[cfv1alpha1.AnnotationPaused] == "true"
+}
+
+// isDeletionProtected returns whether an object with the given annotations is protected against the deletion of its
+// Cloud Foundry resource through the annotation service-operator.cf.cs.sap.com/deletion-protection.
+func isDeletionProtected(annotations map[string]string) bool {
+	return annotations[cfv1alpha1.AnnotationDeletionProtection] == cfv1alpha1.DeletionProtectionEnabled
+}
+
+// pausedMessage is the message of the Paused condition (used for all kinds)
+const pausedMessage = "Reconciliation is paused by annotation " + cfv1alpha1.AnnotationPaused
+
+// withReconcileTimeout returns a context bounding a single reconcile call by the given timeout,
+// such that slow responses from Cloud Foundry or the Kubernetes API server cannot block a worker indefinitely.
+// A non-positive timeout means that no timeout is applied.
+func withReconcileTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// getReconcileCallTimeout returns the maximum duration of a single reconcile call for an object with the given annotations,
+// as specified by the annotation service-operator.cf.cs.sap.com/reconcile-call-timeout, or the given default (configuration key
+// reconcileTimeout) if the annotation is not set or invalid; zero means that no timeout is applied.
+func getReconcileCallTimeout(annotations map[string]string, defaultDuration time.Duration) time.Duration {
+	value, ok := annotations[cfv1alpha1.AnnotationReconcileCallTimeout]
+	if !ok {
+		return defaultDuration
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return defaultDuration
+	}
+	return duration
+}
+
+// isReconcileTimedOut returns whether the reconcile context returned by withReconcileTimeout expired.
+func isReconcileTimedOut(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.DeadlineExceeded)
+}
+
+// timeoutConditionSetter is implemented by all custom resources, with their respective condition type T.
+type timeoutConditionSetter[T ~string] interface {
+	SetCondition(conditionType T, conditionStatus cfv1alpha1.ConditionStatus, reason, message string)
+	RemoveCondition(conditionType T)
+}
+
+// reportReconcileTimeout maintains the Timeout condition (given as conditionType) of the given object, at the end of a reconcile
+// bounded by withReconcileTimeout: if the reconcile failed because the context expired, the condition is set, and the returned error
+// carries a hint at the timeout (the error is still returned to the caller, so that the reconcile request is retried with backoff);
+// otherwise, the condition is removed, and err is returned unchanged.
+func reportReconcileTimeout[T ~string](ctx context.Context, object timeoutConditionSetter[T], conditionType T, err error, timeout time.Duration) error {
+	if err == nil || !isReconcileTimedOut(ctx) {
+		object.RemoveCondition(conditionType)
+		return err
+	}
+	object.SetCondition(conditionType, cfv1alpha1.ConditionTrue, conditionReasonReconcileTimeout, fmt.Sprintf("Reconcile did not complete within %s", timeout))
+	return pkgerrors.Wrapf(err, "reconcile timed out after %s", timeout)
+}
+
+// getCFMetadata returns the custom labels and annotations of the Cloud Foundry resource, as specified by the given spec.metadata;
+// if none are specified, the result is nil (leaving the metadata untouched), unless some were applied before (applied), which are then removed.
+func getCFMetadata(metadata *cfv1alpha1.CFMetadata, applied bool) *facade.Metadata {
+	if metadata == nil || len(metadata.Labels) == 0 && len(metadata.Annotations) == 0 {
+		if !applied {
+			return nil
+		}
+		return &facade.Metadata{}
+	}
+	return &facade.Metadata{Labels: metadata.Labels, Annotations: metadata.Annotations}
+}
+
+// getAdoptionMetadata returns the metadata to be applied when adopting a Cloud Foundry resource created by another tool, looked up
+// by the given label selector (annotation adopt-cf-label-selector): the labels required by the selector are removed, such that the
+// other tool no longer considers the resource its own; the result is nil if no label selector is given.
+func getAdoptionMetadata(labelSelector string) (*facade.Metadata, error) {
+	if labelSelector == "" {
+		return nil, nil
+	}
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "invalid label selector %s", labelSelector)
+	}
+	requirements, _ := selector.Requirements()
+	metadata := &facade.Metadata{}
+	for _, requirement := range requirements {
+		switch requirement.Operator() {
+		case selection.Equals, selection.DoubleEquals, selection.In, selection.Exists:
+			metadata.RemovedLabels = append(metadata.RemovedLabels, requirement.Key())
+		}
+	}
+	return metadata, nil
+}
+
+// getLastOperation returns the status representation of the given last operation of a Cloud Foundry instance or binding
+// (nil if there is none); the previous value is returned if nothing changed, such that the status is only updated
+// when the operation progresses.
+func getLastOperation(previous *cfv1alpha1.LastOperation, lastOperation *facade.LastOperation) *cfv1alpha1.LastOperation {
+	if lastOperation == nil {
+		return nil
+	}
+	result := &cfv1alpha1.LastOperation{
+		Type:        lastOperation.Type,
+		State:       lastOperation.State,
+		Description: lastOperation.Description,
+		StartedAt:   getStatusTime(lastOperation.CreatedAt),
+		UpdatedAt:   getStatusTime(lastOperation.UpdatedAt),
+	}
+	if previous != nil && previous.Type == result.Type && previous.State == result.State && previous.Description == result.Description &&
+		previous.StartedAt.Equal(result.StartedAt) && previous.UpdatedAt.Equal(result.UpdatedAt) {
+		return previous
+	}
+	return result
+}
+
+// getStatusTime returns the given time, truncated to the precision of serialized status timestamps (nil if the time is zero).
+func getStatusTime(t time.Time) *metav1.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &[]metav1.Time{metav1.NewTime(t).Rfc3339Copy()}[0]
+}
+
+// getSpaceNotReadyMessage describes why service instances and bindings are waiting for the given space,
+// taking into account whether the Cloud Foundry space is managed by the operator or externally.
+func getSpaceNotReadyMessage(space cfv1alpha1.GenericSpace) string {
+	message := fmt.Sprintf("Referenced %s is not ready, name: %s", space.GetKind(), space.GetName())
+	if space.GetSpec().GetManagementMode() == cfv1alpha1.SpaceManagementModeExternal {
+		message += fmt.Sprintf("; the Cloud Foundry space (guid: %s) is managed externally, so the operator will not create it;"+
+			" make sure that it exists, and is accessible with the space credentials", space.GetSpec().Guid)
+	}
+	return message
+}
+
+// matchesLabelAllowlist checks whether the given label key is listed in allowlist, either literally,
+// or by a prefix (entry ending with '*').
+func matchesLabelAllowlist(key string, allowlist []string) bool {
+	for _, entry := range allowlist {
+		if prefix, ok := strings.CutSuffix(entry, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == entry {
+			return true
+		}
+	}
+	return false
+}
+
+// getClientConfig returns the configuration for building CF clients from the given space secret;
+// that is, the operator configuration, with the connection settings (CA bundle, proxy) overridden by the secret (if present there).
+func getClientConfig(cfg *config.Config, spaceSecret *corev1.Secret) *config.Config {
+	return cfg.WithConnectionOverrides(string(spaceSecret.Data[spaceSecretKeyCABundle]), string(spaceSecret.Data[spaceSecretKeyProxy]))
+}
+
+// getEndpoint returns the Cloud Foundry API endpoint to be used for objects in the given space (which may be nil),
+// pinned by the given annotations (if set); see SpaceStatus.GetEndpoint.
+func getEndpoint(space cfv1alpha1.GenericSpace, spaceSecret *corev1.Secret, annotations map[string]string) string {
+	var status *cfv1alpha1.SpaceStatus
+	if space != nil {
+		status = space.GetStatus()
+	}
+	return status.GetEndpoint(spaceSecret.Data, annotations)
+}
+
+// getSpaceConfig returns the operator configuration in effect for objects in the given space;
+// that is, the operator configuration, with the resource cache timeout overridden by spec.configOverrides of the space (if set there).
+func getSpaceConfig(cfg *config.Config, space cfv1alpha1.GenericSpace) *config.Config {
+	overrides := space.GetSpec().ConfigOverrides
+	if overrides == nil || overrides.ResourceCacheTimeout == nil {
+		return cfg
+	}
+	return cfg.WithSpaceCacheTimeout(overrides.ResourceCacheTimeout.Duration)
+}
+
+// getEffectiveAnnotations returns the given annotations of an object in the given space, complemented by the polling intervals
+// and the maximum number of retries given in spec.configOverrides of the space (for annotations not set on the object itself).
+// The returned map must not be modified.
+func getEffectiveAnnotations(annotations map[string]string, space cfv1alpha1.GenericSpace) map[string]string {
+	overrides := space.GetSpec().ConfigOverrides
+	if overrides == nil {
+		return annotations
+	}
+	effective := make(map[string]string, len(annotations)+3)
+	if overrides.PollingIntervalReady != nil {
+		effective[cfv1alpha1.AnnotationPollingIntervalReady] = overrides.PollingIntervalReady.Duration.String()
+	}
+	if overrides.PollingIntervalFail != nil {
+		effective[cfv1alpha1.AnnotationPollingIntervalFail] = overrides.PollingIntervalFail.Duration.String()
+	}
+	if overrides.MaxRetries != nil {
+		effective[cfv1alpha1.AnnotationMaxRetries] = strconv.Itoa(*overrides.MaxRetries)
+	}
+	for key, value := range annotations {
+		effective[key] = value
+	}
+	return effective
+}
+
+// cfUnavailableResult checks if the given error was caused by an open circuit breaker, that is, the Cloud Foundry API
+// endpoint is considered unavailable; in that case, it returns a result requeuing the object (with some jitter) once
+// the endpoint will be probed again, so that the error does not trigger the usual (log flooding) retry with backoff.
+func cfUnavailableResult(err error) (ctrl.Result, bool) {
+	var unavailableErr *facade.UnavailableError
+	if !errors.As(err, &unavailableErr) {
+		return ctrl.Result{}, false
+	}
+	requeueAfter := time.Until(unavailableErr.Until)
+	if requeueAfter < time.Second {
+		requeueAfter = time.Second
+	}
+	return ctrl.Result{RequeueAfter: wait.Jitter(requeueAfter, 0.1)}, true
+}
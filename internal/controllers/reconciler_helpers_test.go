@@ -6,14 +6,21 @@ SPDX-License-Identifier: Apache-2.0
 package controllers
 
 import (
+	"context"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/go-logr/logr"
+	pkgerrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -95,3 +102,270 @@ var _ = Describe("Create a Space instance without annotations and empty defaul t
 		Expect(result).To(Equal(ctrl.Result{}))
 	})
 })
+
+var _ = Describe("Determine the default polling intervals | getDefaultPollingIntervalReady, getDefaultPollingIntervalFail", func() {
+	It("should return the built-in defaults if nothing is configured", func() {
+		Expect(getDefaultPollingIntervalReady(nil, "ServiceInstance")).To(Equal("10m"))
+		Expect(getDefaultPollingIntervalReady(config.Defaults(), "Space")).To(Equal("60s"))
+		Expect(getDefaultPollingIntervalFail(config.Defaults(), "ServiceInstance")).To(BeEmpty())
+	})
+
+	It("should return the configured defaults", func() {
+		cfg := config.Defaults()
+		cfg.PollingIntervalsReady = map[string]metav1.Duration{"ServiceInstance": {Duration: 30 * time.Minute}}
+		cfg.PollingIntervalsFail = map[string]metav1.Duration{"ServiceInstance": {Duration: time.Hour}}
+		Expect(getDefaultPollingIntervalReady(cfg, "ServiceInstance")).To(Equal("30m0s"))
+		Expect(getDefaultPollingIntervalReady(cfg, "ServiceBinding")).To(Equal("10m"))
+		Expect(getDefaultPollingIntervalFail(cfg, "ServiceInstance")).To(Equal("1h0m0s"))
+
+		// annotations still take precedence
+		annotations := map[string]string{cfv1alpha1.AnnotationPollingIntervalReady: "2m"}
+		Expect(getPollingInterval(annotations, getDefaultPollingIntervalReady(cfg, "ServiceInstance"), cfv1alpha1.AnnotationPollingIntervalReady).RequeueAfter).To(Equal(2 * time.Minute))
+		Expect(getPollingInterval(nil, getDefaultPollingIntervalReady(cfg, "ServiceInstance"), cfv1alpha1.AnnotationPollingIntervalReady).RequeueAfter).To(Equal(30 * time.Minute))
+	})
+})
+
+var _ = Describe("Create a ServiceBinding with the refresh credentials interval annotation | GetRefreshCredentialsInterval", func() {
+	It("Should return the interval from the annotation", func() {
+		annotations := map[string]string{cfv1alpha1.AnnotationRefreshCredentialsInterval: "1h"}
+		Expect(getRefreshCredentialsInterval(annotations)).To(Equal(time.Hour))
+	})
+
+	It("Should return zero if the annotation is missing or invalid", func() {
+		Expect(getRefreshCredentialsInterval(map[string]string{})).To(BeZero())
+		Expect(getRefreshCredentialsInterval(map[string]string{cfv1alpha1.AnnotationRefreshCredentialsInterval: "invalid"})).To(BeZero())
+		Expect(getRefreshCredentialsInterval(map[string]string{cfv1alpha1.AnnotationRefreshCredentialsInterval: "-1h"})).To(BeZero())
+	})
+})
+
+var _ = Describe("Build the client configuration from the space secret | getClientConfig", func() {
+	It("should override the connection settings by the space secret", func() {
+		cfg := config.Defaults()
+		cfg.Proxy = "http://proxy.example.com"
+
+		secret := &corev1.Secret{Data: map[string][]byte{"url": []byte("https://api.cf.example.com")}}
+		Expect(getClientConfig(cfg, secret)).To(BeIdenticalTo(cfg))
+
+		secret.Data[spaceSecretKeyProxy] = []byte("http://other-proxy.example.com")
+		Expect(getClientConfig(cfg, secret).Proxy).To(Equal("http://other-proxy.example.com"))
+		Expect(cfg.Proxy).To(Equal("http://proxy.example.com"))
+	})
+})
+
+var _ = Describe("Select the Cloud Foundry API endpoint | getEndpoint", func() {
+	const url = "https://api.cf.example.com"
+	const failoverURL = "https://api.cf2.example.com"
+
+	It("should use the url of the space secret, unless the space failed over", func() {
+		space := &cfv1alpha1.Space{}
+		secret := &corev1.Secret{Data: map[string][]byte{cfv1alpha1.SpaceSecretKeyURL: []byte(url)}}
+		Expect(getEndpoint(space, secret, nil)).To(Equal(url))
+
+		secret.Data[cfv1alpha1.SpaceSecretKeyFailoverURL] = []byte(failoverURL)
+		Expect(getEndpoint(space, secret, nil)).To(Equal(url))
+		space.Status.Endpoint = failoverURL
+		Expect(getEndpoint(space, secret, nil)).To(Equal(failoverURL))
+
+		// stale status of the space (failoverUrl removed from the secret)
+		delete(secret.Data, cfv1alpha1.SpaceSecretKeyFailoverURL)
+		Expect(getEndpoint(space, secret, nil)).To(Equal(url))
+	})
+
+	It("should use the endpoint pinned by annotation", func() {
+		space := &cfv1alpha1.Space{Status: cfv1alpha1.SpaceStatus{Endpoint: failoverURL}}
+		secret := &corev1.Secret{Data: map[string][]byte{cfv1alpha1.SpaceSecretKeyURL: []byte(url), cfv1alpha1.SpaceSecretKeyFailoverURL: []byte(failoverURL)}}
+		Expect(getEndpoint(space, secret, map[string]string{cfv1alpha1.AnnotationCFEndpoint: cfv1alpha1.CFEndpointPrimary})).To(Equal(url))
+		space.Status.Endpoint = url
+		Expect(getEndpoint(space, secret, map[string]string{cfv1alpha1.AnnotationCFEndpoint: cfv1alpha1.CFEndpointFailover})).To(Equal(failoverURL))
+	})
+})
+
+var _ = Describe("Detect an unavailable Cloud Foundry API endpoint | cfUnavailableResult", func() {
+	It("should requeue once requests are accepted again", func() {
+		err := pkgerrors.Wrap(&facade.UnavailableError{URL: "https://api.cf.example.com", Until: time.Now().Add(time.Minute)}, "failed to get instance")
+		result, ok := cfUnavailableResult(err)
+		Expect(ok).To(BeTrue())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 50*time.Second))
+		Expect(result.RequeueAfter).To(BeNumerically("<=", 66*time.Second))
+		Expect(retryError(err)).To(BeIdenticalTo(err))
+	})
+
+	It("should ignore other errors", func() {
+		err := fmt.Errorf("some error")
+		_, ok := cfUnavailableResult(err)
+		Expect(ok).To(BeFalse())
+		Expect(retryError(err)).To(BeIdenticalTo(RetryError))
+	})
+})
+
+var _ = Describe("Describe errors returned by the Cloud Foundry API | describeError, getCFError", func() {
+	It("should classify Cloud Foundry API errors, and report their detail", func() {
+		cfErr := &facade.CFError{Code: 60005, Title: "CF-ServiceInstanceQuotaExceeded", Detail: "You have exceeded your organization's services limit.", Err: fmt.Errorf("cfclient error")}
+		err := pkgerrors.Wrap(cfErr, "failed to create instance")
+
+		reason, message := describeError(err, conditionReasonError)
+		Expect(reason).To(Equal(facade.CFErrorReasonQuotaExceeded))
+		Expect(message).To(Equal("You have exceeded your organization's services limit. (CF-ServiceInstanceQuotaExceeded, code 60005)"))
+		Expect(getCFError(err)).To(Equal(&cfv1alpha1.CFError{Code: 60005, Title: "CF-ServiceInstanceQuotaExceeded", Detail: "You have exceeded your organization's services limit."}))
+	})
+
+	It("should describe other errors by the default reason and the error string", func() {
+		err := pkgerrors.Wrap(fmt.Errorf("connection refused"), "failed to get instance")
+
+		reason, message := describeError(err, conditionReasonError)
+		Expect(reason).To(Equal(conditionReasonError))
+		Expect(message).To(Equal("failed to get instance: connection refused"))
+		Expect(getCFError(err)).To(BeNil())
+	})
+})
+
+var _ = Describe("Apply custom labels and annotations to Cloud Foundry resources | getCFMetadata", func() {
+	It("should only return metadata if specified, or applied before", func() {
+		Expect(getCFMetadata(nil, false)).To(BeNil())
+		Expect(getCFMetadata(&cfv1alpha1.CFMetadata{}, false)).To(BeNil())
+		Expect(getCFMetadata(nil, true)).To(Equal(&facade.Metadata{}))
+
+		metadata := &cfv1alpha1.CFMetadata{Labels: map[string]string{"cost-center": "4711"}}
+		Expect(getCFMetadata(metadata, false)).To(Equal(&facade.Metadata{Labels: map[string]string{"cost-center": "4711"}}))
+	})
+})
+
+var _ = Describe("Take over Cloud Foundry resources created by other tools | getAdoptionMetadata", func() {
+	It("should remove the labels required by the label selector", func() {
+		metadata, err := getAdoptionMetadata("managed-by=terraform,terraform-id in (a,b),legacy,stage!=dev,!other")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metadata.RemovedLabels).To(ConsistOf("managed-by", "terraform-id", "legacy"))
+		Expect(metadata.Labels).To(BeEmpty())
+	})
+
+	It("should return nil without label selector, and fail for invalid ones", func() {
+		Expect(getAdoptionMetadata("")).To(BeNil())
+		_, err := getAdoptionMetadata("managed-by in (a")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Describe spaces which are not ready | getSpaceNotReadyMessage", func() {
+	It("should hint at externally managed spaces", func() {
+		space := &cfv1alpha1.Space{ObjectMeta: metav1.ObjectMeta{Name: "space"}}
+		Expect(getSpaceNotReadyMessage(space)).To(Equal("Referenced Space is not ready, name: space"))
+
+		space.Spec.Guid = "space-guid"
+		Expect(space.Spec.GetManagementMode()).To(Equal(cfv1alpha1.SpaceManagementModeExternal))
+		Expect(getSpaceNotReadyMessage(space)).To(ContainSubstring("(guid: space-guid) is managed externally"))
+	})
+})
+
+var _ = Describe("Reconcile objects in observe-only mode | isObserveOnly", func() {
+	It("should honor the annotation and the operator-wide setting", func() {
+		cfg := config.Defaults()
+		Expect(isObserveOnly(cfg, nil)).To(BeFalse())
+		Expect(isObserveOnly(cfg, map[string]string{cfv1alpha1.AnnotationObserveOnly: "true"})).To(BeTrue())
+
+		cfg.ObserveOnly = true
+		Expect(isObserveOnly(cfg, nil)).To(BeTrue())
+		// the annotation cannot disable the operator-wide setting
+		Expect(isObserveOnly(cfg, map[string]string{cfv1alpha1.AnnotationObserveOnly: "false"})).To(BeTrue())
+	})
+})
+
+var _ = Describe("Pause the reconciliation of objects | isPaused", func() {
+	It("should only pause objects annotated with true", func() {
+		Expect(isPaused(nil)).To(BeFalse())
+		Expect(isPaused(map[string]string{cfv1alpha1.AnnotationPaused: "false"})).To(BeFalse())
+		Expect(isPaused(map[string]string{cfv1alpha1.AnnotationPaused: "true"})).To(BeTrue())
+	})
+})
+
+var _ = Describe("Protect Cloud Foundry resources against deletion | isDeletionProtected", func() {
+	It("should only protect objects annotated with enabled", func() {
+		Expect(isDeletionProtected(nil)).To(BeFalse())
+		Expect(isDeletionProtected(map[string]string{cfv1alpha1.AnnotationDeletionProtection: "true"})).To(BeFalse())
+		Expect(isDeletionProtected(map[string]string{cfv1alpha1.AnnotationDeletionProtection: "enabled"})).To(BeTrue())
+	})
+})
+
+var _ = Describe("Bound the duration of reconciles | getReconcileCallTimeout, withReconcileTimeout, reportReconcileTimeout", func() {
+	It("should use the configured timeout unless overridden by a valid annotation", func() {
+		Expect(getReconcileCallTimeout(nil, 5*time.Minute)).To(Equal(5 * time.Minute))
+		Expect(getReconcileCallTimeout(map[string]string{cfv1alpha1.AnnotationReconcileCallTimeout: "10m"}, 5*time.Minute)).To(Equal(10 * time.Minute))
+		Expect(getReconcileCallTimeout(map[string]string{cfv1alpha1.AnnotationReconcileCallTimeout: "0"}, 5*time.Minute)).To(BeZero())
+		Expect(getReconcileCallTimeout(map[string]string{cfv1alpha1.AnnotationReconcileCallTimeout: "soon"}, 5*time.Minute)).To(Equal(5 * time.Minute))
+		Expect(getReconcileCallTimeout(map[string]string{cfv1alpha1.AnnotationReconcileCallTimeout: "-1m"}, 5*time.Minute)).To(Equal(5 * time.Minute))
+	})
+
+	It("should tell expired reconciles, and report the timeout", func() {
+		serviceInstance := &cfv1alpha1.ServiceInstance{}
+		ctx, cancel := withReconcileTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		Expect(isReconcileTimedOut(ctx)).To(BeFalse())
+		<-ctx.Done()
+		Expect(isReconcileTimedOut(ctx)).To(BeTrue())
+		Expect(reportReconcileTimeout(ctx, serviceInstance, cfv1alpha1.ServiceInstanceConditionTimeout, ctx.Err(), time.Millisecond)).
+			To(MatchError(ContainSubstring("reconcile timed out after 1ms")))
+		condition := serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionTimeout)
+		Expect(condition.Status).To(Equal(cfv1alpha1.ConditionTrue))
+		Expect(condition.Message).To(Equal("Reconcile did not complete within 1ms"))
+
+		// the condition is removed once a reconcile completes in time (successfully or not)
+		ctx, cancel = withReconcileTimeout(context.Background(), 0)
+		cancel()
+		Expect(isReconcileTimedOut(ctx)).To(BeFalse())
+		err := pkgerrors.New("failed")
+		Expect(reportReconcileTimeout(ctx, serviceInstance, cfv1alpha1.ServiceInstanceConditionTimeout, err, time.Millisecond)).To(BeIdenticalTo(err))
+		Expect(serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionTimeout)).To(BeNil())
+	})
+})
+
+var _ = Describe("Apply the configuration overrides of a space | getEffectiveAnnotations, getSpaceConfig", func() {
+	It("should fall back to the space overrides for annotations not set on the object", func() {
+		space := &cfv1alpha1.ClusterSpace{ObjectMeta: metav1.ObjectMeta{Name: "space"}}
+		annotations := map[string]string{cfv1alpha1.AnnotationPollingIntervalReady: "2m"}
+		Expect(getEffectiveAnnotations(annotations, space)).To(Equal(annotations))
+
+		maxRetries := 5
+		space.Spec.ConfigOverrides = &cfv1alpha1.SpaceConfigOverrides{
+			PollingIntervalReady: &metav1.Duration{Duration: 30 * time.Minute},
+			PollingIntervalFail:  &metav1.Duration{Duration: time.Hour},
+			MaxRetries:           &maxRetries,
+		}
+		effective := getEffectiveAnnotations(annotations, space)
+		Expect(getPollingInterval(effective, "10m", cfv1alpha1.AnnotationPollingIntervalReady).RequeueAfter).To(Equal(2 * time.Minute))
+		Expect(getPollingInterval(effective, "", cfv1alpha1.AnnotationPollingIntervalFail).RequeueAfter).To(Equal(time.Hour))
+		Expect(annotations).To(HaveLen(1))
+
+		serviceInstance := &cfv1alpha1.ServiceInstance{}
+		setMaxRetries(serviceInstance, effective, logr.Discard())
+		Expect(serviceInstance.Status.MaxRetries).To(Equal(5))
+	})
+
+	It("should override the resource cache timeout", func() {
+		cfg := config.Defaults()
+		space := &cfv1alpha1.Space{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space"}}
+		Expect(getSpaceConfig(cfg, space)).To(BeIdenticalTo(cfg))
+
+		space.Spec.ConfigOverrides = &cfv1alpha1.SpaceConfigOverrides{ResourceCacheTimeout: &metav1.Duration{Duration: 30 * time.Second}}
+		Expect(getSpaceConfig(cfg, space).SpaceCacheTimeout.Duration).To(Equal(30 * time.Second))
+		Expect(cfg.SpaceCacheTimeout.Duration).To(BeZero())
+	})
+})
+
+var _ = Describe("Mirror the last Cloud Foundry operation in the status | getLastOperation", func() {
+	It("should only replace the last operation if it changed", func() {
+		startedAt := time.Date(2024, 5, 1, 10, 0, 0, 500, time.UTC)
+		lastOperation := &facade.LastOperation{Type: "create", State: "in progress", CreatedAt: startedAt, UpdatedAt: startedAt}
+		Expect(getLastOperation(nil, nil)).To(BeNil())
+
+		previous := getLastOperation(nil, lastOperation)
+		Expect(previous.Type).To(Equal("create"))
+		Expect(previous.StartedAt.Time).To(Equal(startedAt.Truncate(time.Second)))
+		Expect(getLastOperation(previous, lastOperation)).To(BeIdenticalTo(previous))
+
+		lastOperation.State = "succeeded"
+		lastOperation.UpdatedAt = startedAt.Add(time.Minute)
+		current := getLastOperation(previous, lastOperation)
+		Expect(current).ToNot(BeIdenticalTo(previous))
+		Expect(current.State).To(Equal("succeeded"))
+		Expect(current.UpdatedAt.Sub(current.StartedAt.Time)).To(Equal(time.Minute))
+	})
+})
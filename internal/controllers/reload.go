@@ -0,0 +1,41 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"sync"
+
+	"github.com/sap/cf-service-operator/internal/config"
+)
+
+// configuration passed to Reconfigure; its reloadable settings replace the according settings of the reconcilers' configuration
+// (nil if Reconfigure was not called); guarded by reloadedConfigMutex
+var (
+	reloadedConfigMutex sync.RWMutex
+	reloadedConfig      *config.Config
+)
+
+// Reconfigure applies the reloadable settings (concurrency, default polling intervals) of the given configuration to all controllers;
+// takes effect for reconciles started afterwards.
+func Reconfigure(cfg *config.Config) {
+	reloadedConfigMutex.Lock()
+	reloadedConfig = cfg
+	reloadedConfigMutex.Unlock()
+
+	SetMaxConcurrentReconciles(cfg.MaxConcurrentReconciles)
+	SetMaxConcurrentReconcilesPerEndpoint(cfg.MaxConcurrentReconcilesPerEndpoint)
+}
+
+// withReloadedConfig returns the given configuration, with the reloadable settings replaced by the configuration passed to Reconfigure.
+func withReloadedConfig(cfg *config.Config) *config.Config {
+	reloadedConfigMutex.RLock()
+	defer reloadedConfigMutex.RUnlock()
+
+	if reloadedConfig == nil {
+		return cfg
+	}
+	return cfg.WithReloadable(reloadedConfig)
+}
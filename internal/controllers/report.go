@@ -0,0 +1,223 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+// maximum number of failures listed in the report
+const maxReportedFailures = 10
+
+// ServiceOperatorReporter periodically refreshes the (cluster-scoped) ServiceOperatorReport object,
+// summarizing all managed spaces, service instances and service bindings.
+type ServiceOperatorReporter struct {
+	client.Client
+	ClusterResourceNamespace string
+	Config                   *config.Config
+	Interval                 time.Duration
+	CacheStatistics          facade.ResourceCacheStatisticsProvider
+}
+
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=serviceoperatorreports,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=serviceoperatorreports/status,verbs=get;update;patch
+
+// Start refreshes the report every interval, until the context is cancelled.
+// Implements manager.Runnable; errors are logged, and do not stop the reporter.
+func (r *ServiceOperatorReporter) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithName("report")
+
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	for {
+		if err := r.refresh(ctx); err != nil {
+			log.Error(err, "failed to refresh ServiceOperatorReport")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *ServiceOperatorReporter) refresh(ctx context.Context) error {
+	spaceList := &cfv1alpha1.SpaceList{}
+	if err := r.List(ctx, spaceList); err != nil {
+		return errors.Wrap(err, "failed to list spaces")
+	}
+	clusterSpaceList := &cfv1alpha1.ClusterSpaceList{}
+	if err := r.List(ctx, clusterSpaceList); err != nil {
+		return errors.Wrap(err, "failed to list cluster spaces")
+	}
+	serviceInstanceList := &cfv1alpha1.ServiceInstanceList{}
+	if err := r.List(ctx, serviceInstanceList); err != nil {
+		return errors.Wrap(err, "failed to list service instances")
+	}
+	serviceBindingList := &cfv1alpha1.ServiceBindingList{}
+	if err := r.List(ctx, serviceBindingList); err != nil {
+		return errors.Wrap(err, "failed to list service bindings")
+	}
+
+	var spaces []cfv1alpha1.GenericSpace
+	for i := range spaceList.Items {
+		spaces = append(spaces, &spaceList.Items[i])
+	}
+	for i := range clusterSpaceList.Items {
+		spaces = append(spaces, &clusterSpaceList.Items[i])
+	}
+
+	urls := make(map[string]int)
+	for _, space := range spaces {
+		url, err := r.getSpaceURL(ctx, space)
+		if err != nil {
+			return err
+		}
+		if url != "" {
+			urls[url]++
+		}
+	}
+
+	status := buildServiceOperatorReportStatus(spaces, serviceInstanceList.Items, serviceBindingList.Items, urls)
+	status.Cache = &cfv1alpha1.CacheStatistics{}
+	if r.Config != nil && r.Config.IsResourceCacheEnabled && r.CacheStatistics != nil {
+		statistics := r.CacheStatistics()
+		status.Cache = &cfv1alpha1.CacheStatistics{
+			Enabled:          true,
+			Spaces:           statistics.Spaces,
+			ServiceInstances: statistics.Instances,
+			ServiceBindings:  statistics.Bindings,
+			Hits:             statistics.Hits,
+			Misses:           statistics.Misses,
+		}
+	}
+	now := metav1.Now()
+	status.LastRefreshedAt = &now
+
+	report := &cfv1alpha1.ServiceOperatorReport{}
+	if err := r.Get(ctx, types.NamespacedName{Name: cfv1alpha1.ServiceOperatorReportName}, report); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to get ServiceOperatorReport")
+		}
+		report = &cfv1alpha1.ServiceOperatorReport{
+			ObjectMeta: metav1.ObjectMeta{Name: cfv1alpha1.ServiceOperatorReportName},
+		}
+		if err := r.Create(ctx, report); err != nil {
+			return errors.Wrap(err, "failed to create ServiceOperatorReport")
+		}
+	}
+	report.Status = *status
+	if err := r.Status().Update(ctx, report); err != nil {
+		return errors.Wrap(err, "failed to update ServiceOperatorReport status")
+	}
+	return nil
+}
+
+// getSpaceURL returns the Cloud Foundry API URL in use for the given space (see getEndpoint),
+// or an empty string if the space secret does not exist.
+func (r *ServiceOperatorReporter) getSpaceURL(ctx context.Context, space cfv1alpha1.GenericSpace) (string, error) {
+	secretName, err := getSpaceSecretName(space, r.ClusterResourceNamespace, r.Config)
+	if err != nil {
+		return "", err
+	}
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, secretName, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to get Secret containing space credentials, secret name: %s", secretName)
+	}
+	return getEndpoint(space, secret, nil), nil
+}
+
+// buildServiceOperatorReportStatus summarizes the given objects; urls contains the number of spaces by Cloud Foundry API URL.
+func buildServiceOperatorReportStatus(
+	spaces []cfv1alpha1.GenericSpace,
+	serviceInstances []cfv1alpha1.ServiceInstance,
+	serviceBindings []cfv1alpha1.ServiceBinding,
+	urls map[string]int,
+) *cfv1alpha1.ServiceOperatorReportStatus {
+	status := &cfv1alpha1.ServiceOperatorReportStatus{}
+	var failures []cfv1alpha1.ResourceFailure
+
+	for url, count := range urls {
+		status.Foundations = append(status.Foundations, cfv1alpha1.FoundationReport{URL: url, Spaces: count})
+	}
+	sort.Slice(status.Foundations, func(i, j int) bool { return status.Foundations[i].URL < status.Foundations[j].URL })
+
+	for _, space := range spaces {
+		countState(&status.Spaces, string(space.GetStatus().State))
+		if condition := space.GetReadyCondition(); condition != nil && condition.Status == cfv1alpha1.ConditionFalse {
+			failures = append(failures, newResourceFailure(space.GetKind(), space.GetNamespace(), space.GetName(), condition.Reason, condition.Message, condition.LastTransitionTime))
+		}
+	}
+	for i := range serviceInstances {
+		serviceInstance := &serviceInstances[i]
+		countState(&status.ServiceInstances, string(serviceInstance.Status.State))
+		if condition := serviceInstance.GetReadyCondition(); condition != nil && condition.Status == cfv1alpha1.ConditionFalse {
+			failures = append(failures, newResourceFailure("ServiceInstance", serviceInstance.Namespace, serviceInstance.Name, condition.Reason, condition.Message, condition.LastTransitionTime))
+		}
+	}
+	for i := range serviceBindings {
+		serviceBinding := &serviceBindings[i]
+		countState(&status.ServiceBindings, string(serviceBinding.Status.State))
+		if condition := serviceBinding.GetReadyCondition(); condition != nil && condition.Status == cfv1alpha1.ConditionFalse {
+			failures = append(failures, newResourceFailure("ServiceBinding", serviceBinding.Namespace, serviceBinding.Name, condition.Reason, condition.Message, condition.LastTransitionTime))
+		}
+	}
+
+	// newest first; failures without timestamp go last
+	sort.SliceStable(failures, func(i, j int) bool {
+		ti, tj := failures[i].Time, failures[j].Time
+		if ti == nil || tj == nil {
+			return tj == nil && ti != nil
+		}
+		return tj.Before(ti)
+	})
+	if len(failures) > maxReportedFailures {
+		failures = failures[:maxReportedFailures]
+	}
+	status.RecentFailures = failures
+
+	return status
+}
+
+func countState(counts *cfv1alpha1.ResourceCounts, state string) {
+	if state == "" {
+		state = "Unknown"
+	}
+	if counts.ByState == nil {
+		counts.ByState = make(map[string]int)
+	}
+	counts.Total++
+	counts.ByState[state]++
+}
+
+func newResourceFailure(kind string, namespace string, name string, reason string, message string, transitionTime *metav1.Time) cfv1alpha1.ResourceFailure {
+	return cfv1alpha1.ResourceFailure{
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+		Reason:    reason,
+		Message:   message,
+		Time:      transitionTime,
+	}
+}
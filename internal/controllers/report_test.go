@@ -0,0 +1,71 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+)
+
+var _ = Describe("Summarize managed objects | buildServiceOperatorReportStatus", func() {
+	failedInstance := func(name string, transitionTime time.Time) cfv1alpha1.ServiceInstance {
+		instance := cfv1alpha1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}}
+		instance.Status.State = cfv1alpha1.ServiceInstanceStateError
+		instance.Status.Conditions = []cfv1alpha1.ServiceInstanceCondition{{
+			Type:               cfv1alpha1.ServiceInstanceConditionReady,
+			Status:             cfv1alpha1.ConditionFalse,
+			Reason:             "Error",
+			Message:            "failed",
+			LastTransitionTime: &metav1.Time{Time: transitionTime},
+		}}
+		return instance
+	}
+
+	It("should count objects by state", func() {
+		space := &cfv1alpha1.Space{}
+		space.Status.State = cfv1alpha1.SpaceStateReady
+		clusterSpace := &cfv1alpha1.ClusterSpace{}
+		instances := []cfv1alpha1.ServiceInstance{{}, {}}
+		instances[0].Status.State = cfv1alpha1.ServiceInstanceStateReady
+		instances[1].Status.State = cfv1alpha1.ServiceInstanceStateReady
+
+		status := buildServiceOperatorReportStatus(
+			[]cfv1alpha1.GenericSpace{space, clusterSpace},
+			instances,
+			nil,
+			map[string]int{"https://b": 1, "https://a": 1},
+		)
+
+		Expect(status.Spaces).To(Equal(cfv1alpha1.ResourceCounts{Total: 2, ByState: map[string]int{"Ready": 1, "Unknown": 1}}))
+		Expect(status.ServiceInstances).To(Equal(cfv1alpha1.ResourceCounts{Total: 2, ByState: map[string]int{"Ready": 2}}))
+		Expect(status.ServiceBindings.Total).To(Equal(0))
+		Expect(status.Foundations).To(Equal([]cfv1alpha1.FoundationReport{{URL: "https://a", Spaces: 1}, {URL: "https://b", Spaces: 1}}))
+		Expect(status.RecentFailures).To(BeEmpty())
+	})
+
+	It("should list the most recent failures first", func() {
+		now := time.Now()
+		var instances []cfv1alpha1.ServiceInstance
+		for i := 0; i < maxReportedFailures+2; i++ {
+			instances = append(instances, failedInstance(fmt.Sprintf("instance-%d", i), now.Add(time.Duration(i)*time.Minute)))
+		}
+
+		status := buildServiceOperatorReportStatus(nil, instances, nil, nil)
+
+		Expect(status.RecentFailures).To(HaveLen(maxReportedFailures))
+		Expect(status.RecentFailures[0].Name).To(Equal(fmt.Sprintf("instance-%d", maxReportedFailures+1)))
+		Expect(status.RecentFailures[0].Kind).To(Equal("ServiceInstance"))
+		Expect(status.RecentFailures[0].Namespace).To(Equal("ns"))
+		Expect(status.RecentFailures[0].Message).To(Equal("failed"))
+		Expect(status.RecentFailures[maxReportedFailures-1].Name).To(Equal("instance-2"))
+	})
+})
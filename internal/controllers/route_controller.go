@@ -0,0 +1,352 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+const (
+	routeFinalizer = "cf.cs.sap.com/service-operator"
+	// Cloud Foundry deletes routes asynchronously; the deletion is triggered again only if the route still exists after this interval
+	routeDeletionRetryInterval = 5 * time.Minute
+)
+
+const (
+	routeReadyConditionReasonNew             = "FirstSeen"
+	routeReadyConditionReasonSpaceNotReady   = "SpaceNotReady"
+	routeReadyConditionReasonError           = "Error"
+	routeReadyConditionReasonDeletionBlocked = "DeletionBlocked"
+	routeReadyConditionReasonCreated         = "Created"
+	routeReadyConditionReasonDeleting        = "Deleting"
+)
+
+// RouteReconciler reconciles a Route object
+type RouteReconciler struct {
+	client.Client
+	Scheme                   *runtime.Scheme
+	ClusterResourceNamespace string
+	ClientBuilder            facade.SpaceClientBuilder
+	ReconcileTimeout         time.Duration
+	Config                   *config.Config
+}
+
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=routes,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=routes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=routes/finalizers,verbs=update
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=routebindings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=clusterspaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=spaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+
+func (r *RouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	log := ctrl.LoggerFrom(ctx)
+	log.V(2).Info("Running reconcile")
+
+	// Retrieve target route
+	route := &cfv1alpha1.Route{}
+	if err := r.Get(ctx, req.NamespacedName, route); err != nil {
+		if err := client.IgnoreNotFound(err); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "unexpected get error")
+		}
+		log.V(1).Info("Not found; ignoring")
+		return ctrl.Result{}, nil
+	}
+
+	// Bound the duration of this reconcile (unless overridden by annotation, as configured); the final status update (see below)
+	// is attempted even after expiry
+	reconcileCallTimeout := getReconcileCallTimeout(route.GetAnnotations(), r.ReconcileTimeout)
+	ctx, cancel := withReconcileTimeout(ctx, reconcileCallTimeout)
+	defer cancel()
+	ctx, cfResponded := facade.WithResponseRecorder(ctx)
+
+	// Call the defaulting webhook logic also here (because defaulting through the webhook might be incomplete in case of generateName usage)
+	route.Default()
+
+	spec := &route.Spec
+	status := &route.Status
+	status.LastReconciledAt = &[]metav1.Time{metav1.Now()}[0]
+
+	// Always attempt to update the status
+	skipStatusUpdate := false
+	defer func() {
+		if skipStatusUpdate {
+			return
+		}
+		if cfResponded() {
+			route.SetCondition(cfv1alpha1.RouteConditionCFReachable, cfv1alpha1.ConditionTrue, conditionReasonReachable, "Cloud Foundry API is reachable")
+		}
+		err = reportReconcileTimeout(ctx, route, cfv1alpha1.RouteConditionTimeout, err, reconcileCallTimeout)
+		if err != nil {
+			if unavailableResult, ok := cfUnavailableResult(err); ok {
+				log.V(1).Info("Cloud Foundry API unavailable; requeuing", "requeueAfter", unavailableResult.RequeueAfter)
+				route.SetReadyCondition(cfv1alpha1.ConditionUnknown, readyConditionReasonCFUnavailable, err.Error())
+				route.SetCondition(cfv1alpha1.RouteConditionCFReachable, cfv1alpha1.ConditionFalse, readyConditionReasonCFUnavailable, err.Error())
+				result, err = unavailableResult, nil
+			} else {
+				route.SetReadyCondition(cfv1alpha1.ConditionFalse, routeReadyConditionReasonError, err.Error())
+				route.SetCondition(cfv1alpha1.RouteConditionSynced, cfv1alpha1.ConditionFalse, conditionReasonError, err.Error())
+			}
+		}
+		if updateErr := r.Status().Update(context.WithoutCancel(ctx), route); updateErr != nil {
+			err = utilerrors.NewAggregate([]error{err, updateErr})
+			result = ctrl.Result{}
+		}
+	}()
+
+	// While paused, nothing is read from or changed in Cloud Foundry; the annotation being removed triggers the next reconciliation
+	if isPaused(route.Annotations) {
+		route.SetCondition(cfv1alpha1.RouteConditionPaused, cfv1alpha1.ConditionTrue, conditionReasonPaused, pausedMessage)
+		return ctrl.Result{}, nil
+	}
+	if route.GetCondition(cfv1alpha1.RouteConditionPaused) != nil {
+		// persist the removal of the condition first (and requeue), since updates of the object (e.g. adding finalizers) reset the status
+		route.RemoveCondition(cfv1alpha1.RouteConditionPaused)
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Set a first status (and requeue, because the status update itself will not trigger another reconciliation because of the event filter set)
+	if ready := route.GetReadyCondition(); ready == nil {
+		route.SetReadyCondition(cfv1alpha1.ConditionUnknown, routeReadyConditionReasonNew, "First seen")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Retrieve referenced space
+	space, spaceSecretName, err := getReferencedSpace(ctx, r.Client, r.ClusterResourceNamespace, r.Config, route.Namespace, spec.SpaceName, spec.ClusterSpaceName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	spaceGuid := space.GetSpec().Guid
+	if spaceGuid == "" {
+		spaceGuid = space.GetStatus().SpaceGuid
+	}
+
+	// Apply the configuration overrides of the space
+	annotations := getEffectiveAnnotations(route.GetAnnotations(), space)
+
+	spaceSecret := &corev1.Secret{}
+	if err := r.Get(ctx, spaceSecretName, spaceSecret); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to get Secret containing space credentials, secret name: %s", spaceSecretName)
+	}
+
+	// Find depending route bindings
+	routeBindingList := &cfv1alpha1.RouteBindingList{}
+	if err := client.NewNamespacedClient(r.Client, route.Namespace).List(
+		ctx,
+		routeBindingList,
+		client.MatchingFields{indexRouteName: route.Name},
+	); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to list depending route bindings")
+	}
+
+	// Require readiness of space unless in deletion case
+	if route.DeletionTimestamp.IsZero() {
+		if !space.IsReady() {
+			route.SetReadyCondition(cfv1alpha1.ConditionUnknown, routeReadyConditionReasonSpaceNotReady, getSpaceNotReadyMessage(space))
+			// TODO: apply some increasing period, depending on the age of the last update
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		if spaceGuid == "" {
+			return ctrl.Result{}, fmt.Errorf("unexpected error; unable to find guid on ready %s: name: %s", space.GetKind(), space.GetName())
+		}
+	}
+
+	// Build cloud foundry client
+	endpoint := getEndpoint(space, spaceSecret, annotations)
+	var client facade.SpaceClient
+	if spaceGuid != "" {
+		client, err = r.ClientBuilder(spaceGuid, endpoint, string(spaceSecret.Data["username"]), string(spaceSecret.Data["password"]), getClientConfig(getSpaceConfig(r.Config, space), spaceSecret))
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to build the client from secret %s", spaceSecretName)
+		}
+	}
+
+	// Retrieve cloud foundry route
+	var cfroute *facade.Route
+	if client != nil {
+		log.V(1).Info("Retrieving route")
+		cfroute, err = client.GetRoute(ctx, string(route.UID))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// In observe-only mode, the cloud foundry route is only read; nothing is created, updated or deleted
+	if isObserveOnly(r.Config, route.Annotations) {
+		if !route.DeletionTimestamp.IsZero() {
+			// the cloud foundry route is left untouched; just release the object (if it was managed before)
+			if containsString(route.Finalizers, routeFinalizer) {
+				controllerutil.RemoveFinalizer(route, routeFinalizer)
+				if err := r.Update(ctx, route); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			skipStatusUpdate = true
+			return ctrl.Result{}, nil
+		}
+		route.SetCondition(cfv1alpha1.RouteConditionSynced, cfv1alpha1.ConditionUnknown, conditionReasonObserveOnly, "Changes are not applied in observe-only mode")
+		if cfroute == nil {
+			route.SetReadyCondition(cfv1alpha1.ConditionFalse, readyConditionReasonNotFound, "Cloud Foundry route not found (observe-only mode; it will not be created)")
+		} else {
+			r.updateStatus(route, cfroute, spaceGuid)
+			route.SetReadyCondition(cfv1alpha1.ConditionTrue, routeReadyConditionReasonCreated, "Route exists")
+		}
+		return getPollingInterval(annotations, getDefaultPollingIntervalReady(r.Config, "Route"), cfv1alpha1.AnnotationPollingIntervalReady), nil
+	}
+
+	if route.DeletionTimestamp.IsZero() {
+		// Create/update case
+		if !containsString(route.Finalizers, routeFinalizer) {
+			controllerutil.AddFinalizer(route, routeFinalizer)
+			if err := r.Update(ctx, route); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		if cfroute == nil {
+			log.V(1).Info("Searching domain")
+			domainGuid, err := client.FindDomain(ctx, spec.Domain)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			log.V(1).Info("Creating route")
+			if err := client.CreateRoute(ctx, domainGuid, spec.Host, spec.Path, string(route.UID), route.Generation); err != nil {
+				return ctrl.Result{}, err
+			}
+			status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
+		} else if cfroute.Generation < route.Generation {
+			// host, domain and path are immutable, so only the metadata has to be updated
+			log.V(1).Info("Updating route")
+			if err := client.UpdateRoute(ctx, cfroute.Guid, route.Generation); err != nil {
+				return ctrl.Result{}, err
+			}
+			status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
+			// Clear route, so it will be re-read below
+			cfroute = nil
+		}
+
+		if cfroute == nil {
+			// Re-retrieve cloud foundry route by UID; this happens exactly if the route was created or updated above
+			log.V(1).Info("Retrieving route")
+			cfroute, err = client.GetRoute(ctx, string(route.UID))
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if cfroute == nil {
+				return ctrl.Result{}, fmt.Errorf("unexpected error; route not found in cloud foundry although it should exist")
+			}
+		}
+
+		// Update status; routes are created synchronously, so they are ready once they exist
+		r.updateStatus(route, cfroute, spaceGuid)
+		route.SetReadyCondition(cfv1alpha1.ConditionTrue, routeReadyConditionReasonCreated, "Route exists")
+		route.SetCondition(cfv1alpha1.RouteConditionSynced, cfv1alpha1.ConditionTrue, conditionReasonSynced, "Cloud Foundry route reflects the current spec")
+		return getPollingInterval(annotations, getDefaultPollingIntervalReady(r.Config, "Route"), cfv1alpha1.AnnotationPollingIntervalReady), nil
+	} else if len(routeBindingList.Items) > 0 {
+		route.SetReadyCondition(cfv1alpha1.ConditionUnknown, routeReadyConditionReasonDeletionBlocked, "Waiting for deletion of depending route bindings")
+		route.SetCondition(cfv1alpha1.RouteConditionDeletionBlocked, cfv1alpha1.ConditionTrue, conditionReasonDependentsExist, "Waiting for deletion of depending route bindings")
+		// TODO: apply some increasing period, depending on the age of the last update
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	} else if len(removeString(route.Finalizers, routeFinalizer)) > 0 {
+		route.SetReadyCondition(cfv1alpha1.ConditionUnknown, routeReadyConditionReasonDeletionBlocked, "Deletion blocked due to foreign finalizers")
+		route.SetCondition(cfv1alpha1.RouteConditionDeletionBlocked, cfv1alpha1.ConditionTrue, conditionReasonForeignFinalizers, "Deletion blocked due to foreign finalizers")
+		// TODO: apply some increasing period, depending on the age of the last update
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	} else {
+		// Deletion case
+		route.RemoveCondition(cfv1alpha1.RouteConditionDeletionBlocked)
+		if cfroute == nil {
+			if containsString(route.Finalizers, routeFinalizer) {
+				controllerutil.RemoveFinalizer(route, routeFinalizer)
+				if err := r.Update(ctx, route); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			// skip status update, since the route will anyway deleted timely by the API server
+			skipStatusUpdate = true
+			return ctrl.Result{}, nil
+		}
+		if ready := route.GetReadyCondition(); ready != nil && ready.Reason == routeReadyConditionReasonDeleting &&
+			status.LastModifiedAt != nil && time.Since(status.LastModifiedAt.Time) < routeDeletionRetryInterval {
+			// the deletion was triggered already; wait for it to complete
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		log.V(1).Info("Deleting route")
+		if err := client.DeleteRoute(ctx, cfroute.Guid); err != nil {
+			return ctrl.Result{}, err
+		}
+		status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
+		route.SetReadyCondition(cfv1alpha1.ConditionUnknown, routeReadyConditionReasonDeleting, "Deletion triggered.")
+		// TODO: apply some increasing period, depending on the age of the last update
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+}
+
+func (r *RouteReconciler) updateStatus(route *cfv1alpha1.Route, cfroute *facade.Route, spaceGuid string) {
+	status := &route.Status
+	status.SpaceGuid = spaceGuid
+	status.DomainGuid = cfroute.DomainGuid
+	status.RouteGuid = cfroute.Guid
+	status.URL = cfroute.URL
+}
+
+// getReferencedSpace returns the Space (if spaceName is set) or ClusterSpace (if clusterSpaceName is set) referenced by an object
+// in the given namespace, together with the name of the secret containing the space credentials.
+func getReferencedSpace(ctx context.Context, c client.Client, clusterResourceNamespace string, cfg *config.Config, namespace string, spaceName string, clusterSpaceName string) (cfv1alpha1.GenericSpace, types.NamespacedName, error) {
+	var space cfv1alpha1.GenericSpace
+	if spaceName != "" {
+		space = &cfv1alpha1.Space{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: spaceName}, space); err != nil {
+			return nil, types.NamespacedName{}, errors.Wrapf(err, "failed to get Space, name: %s", spaceName)
+		}
+	} else if clusterSpaceName != "" {
+		space = &cfv1alpha1.ClusterSpace{}
+		if err := c.Get(ctx, types.NamespacedName{Name: clusterSpaceName}, space); err != nil {
+			return nil, types.NamespacedName{}, errors.Wrapf(err, "failed to get ClusterSpace, name: %s", clusterSpaceName)
+		}
+	} else {
+		return nil, types.NamespacedName{}, fmt.Errorf("neither space nor cluster space specified")
+	}
+	secretName, err := getSpaceSecretName(space, clusterResourceNamespace, cfg)
+	if err != nil {
+		return nil, types.NamespacedName{}, err
+	}
+	return space, secretName, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	reconciler, options := limitConcurrency(traceReconciles(measureReconciles(labelReconciles(r, "Route"), "Route"), "Route"), r.Config, nil)
+	reconciler, namespacePredicate := filterNamespaces(reconciler, mgr.GetClient(), r.Config)
+	if err := addRouteDependentIndexes(mgr); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&cfv1alpha1.Route{}).
+		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})).
+		WithEventFilter(namespacePredicate).
+		WithOptions(options).
+		Complete(reconciler)
+}
@@ -0,0 +1,164 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+	"github.com/sap/cf-service-operator/internal/facade/facadefakes"
+)
+
+var _ = Describe("Reconcile routes | RouteReconciler", func() {
+	ctx := context.Background()
+	routeKey := types.NamespacedName{Namespace: "ns", Name: "route"}
+	var spaceClient *facadefakes.FakeSpaceClient
+
+	newReconciler := func(objects ...client.Object) *RouteReconciler {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		space := &cfv1alpha1.Space{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space"},
+			Spec:       cfv1alpha1.SpaceSpec{Guid: "space-guid", AuthSecretName: "space-secret"},
+		}
+		space.SetReadyCondition(cfv1alpha1.ConditionTrue, "Ready", "")
+		objects = append(objects,
+			space,
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space-secret"},
+				Data:       map[string][]byte{"url": []byte("https://api.cf.example.com")},
+			},
+		)
+		return &RouteReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithIndex(&cfv1alpha1.RouteBinding{}, indexRouteName, indexByField(func(routeBinding *cfv1alpha1.RouteBinding) string { return routeBinding.Spec.RouteName })).
+				WithObjects(objects...).
+				WithStatusSubresource(&cfv1alpha1.Route{}).
+				Build(),
+			ClientBuilder: func(string, string, string, string, *config.Config) (facade.SpaceClient, error) {
+				return spaceClient, nil
+			},
+			Config: config.Defaults(),
+		}
+	}
+
+	newRoute := func() *cfv1alpha1.Route {
+		route := &cfv1alpha1.Route{
+			ObjectMeta: metav1.ObjectMeta{Namespace: routeKey.Namespace, Name: routeKey.Name, UID: "route-uid", Generation: 1},
+			Spec:       cfv1alpha1.RouteSpec{SpaceName: "space", Domain: "example.com", Host: "my-app"},
+		}
+		route.SetReadyCondition(cfv1alpha1.ConditionUnknown, routeReadyConditionReasonNew, "First seen")
+		return route
+	}
+
+	BeforeEach(func() {
+		spaceClient = &facadefakes.FakeSpaceClient{}
+	})
+
+	It("should create the route and report its url", func() {
+		reconciler := newReconciler(newRoute())
+		cfroute := &facade.Route{Guid: "route-guid", DomainGuid: "domain-guid", URL: "my-app.example.com", Owner: "route-uid", Generation: 1}
+		spaceClient.GetRouteReturnsOnCall(0, nil, nil)
+		spaceClient.GetRouteReturnsOnCall(1, cfroute, nil)
+		spaceClient.FindDomainReturns("domain-guid", nil)
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: routeKey})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(spaceClient.CreateRouteCallCount()).To(Equal(1))
+		_, domainGuid, host, path, owner, generation := spaceClient.CreateRouteArgsForCall(0)
+		Expect([]interface{}{domainGuid, host, path, owner, generation}).To(Equal([]interface{}{"domain-guid", "my-app", "", "route-uid", int64(1)}))
+		route := &cfv1alpha1.Route{}
+		Expect(reconciler.Get(ctx, routeKey, route)).To(Succeed())
+		Expect(route.Finalizers).To(ContainElement(routeFinalizer))
+		Expect(route.Status.RouteGuid).To(Equal("route-guid"))
+		Expect(route.Status.URL).To(Equal("my-app.example.com"))
+		Expect(route.IsReady()).To(BeTrue())
+	})
+
+	It("should neither read nor change the route while paused", func() {
+		route := newRoute()
+		route.Annotations = map[string]string{cfv1alpha1.AnnotationPaused: "true"}
+		reconciler := newReconciler(route)
+
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: routeKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(ctrl.Result{}))
+
+		Expect(spaceClient.Invocations()).To(BeEmpty())
+		Expect(reconciler.Get(ctx, routeKey, route)).To(Succeed())
+		Expect(route.Finalizers).To(BeEmpty())
+		Expect(route.GetCondition(cfv1alpha1.RouteConditionPaused)).ToNot(BeNil())
+		Expect(route.GetCondition(cfv1alpha1.RouteConditionPaused).Status).To(Equal(cfv1alpha1.ConditionTrue))
+
+		// resume
+		delete(route.Annotations, cfv1alpha1.AnnotationPaused)
+		Expect(reconciler.Update(ctx, route)).To(Succeed())
+		spaceClient.GetRouteReturns(&facade.Route{Guid: "route-guid", URL: "my-app.example.com", Owner: "route-uid", Generation: 1}, nil)
+		_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: routeKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reconciler.Get(ctx, routeKey, route)).To(Succeed())
+		Expect(route.GetCondition(cfv1alpha1.RouteConditionPaused)).To(BeNil())
+	})
+
+	It("should not delete the route while route bindings exist", func() {
+		route := newRoute()
+		route.Finalizers = []string{routeFinalizer}
+		route.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+		routeBinding := &cfv1alpha1.RouteBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      "route-binding",
+				Labels:    map[string]string{cfv1alpha1.LabelKeyRoute: routeKey.Name},
+			},
+			Spec: cfv1alpha1.RouteBindingSpec{RouteName: routeKey.Name},
+		}
+		reconciler := newReconciler(route, routeBinding)
+		spaceClient.GetRouteReturns(&facade.Route{Guid: "route-guid", Owner: "route-uid", Generation: 1}, nil)
+
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: routeKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).ToNot(BeZero())
+
+		Expect(spaceClient.DeleteRouteCallCount()).To(BeZero())
+		Expect(reconciler.Get(ctx, routeKey, route)).To(Succeed())
+		Expect(route.GetCondition(cfv1alpha1.RouteConditionDeletionBlocked)).ToNot(BeNil())
+		Expect(route.GetCondition(cfv1alpha1.RouteConditionDeletionBlocked).Reason).To(Equal(conditionReasonDependentsExist))
+	})
+
+	It("should trigger the deletion of the route only once", func() {
+		route := newRoute()
+		route.Finalizers = []string{routeFinalizer}
+		route.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+		reconciler := newReconciler(route)
+		spaceClient.GetRouteReturns(&facade.Route{Guid: "route-guid", Owner: "route-uid", Generation: 1}, nil)
+
+		for i := 0; i < 2; i++ {
+			result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: routeKey})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).ToNot(BeZero())
+		}
+
+		Expect(spaceClient.DeleteRouteCallCount()).To(Equal(1))
+		Expect(reconciler.Get(ctx, routeKey, route)).To(Succeed())
+		Expect(route.GetReadyCondition().Reason).To(Equal(routeReadyConditionReasonDeleting))
+	})
+})
@@ -0,0 +1,407 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+const (
+	routeBindingFinalizer = "cf.cs.sap.com/service-operator"
+)
+
+const (
+	routeBindingReadyConditionReasonNew                     = "FirstSeen"
+	routeBindingReadyConditionReasonSpaceNotReady           = "SpaceNotReady"
+	routeBindingReadyConditionReasonRouteNotReady           = "RouteNotReady"
+	routeBindingReadyConditionReasonServiceInstanceNotReady = "ServiceInstanceNotReady"
+	routeBindingReadyConditionReasonError                   = "Error"
+	routeBindingReadyConditionReasonDeletionBlocked         = "DeletionBlocked"
+	// Additionally, all of facade.BindingState* may occur as Ready condition reason
+)
+
+// RouteBindingReconciler reconciles a RouteBinding object
+type RouteBindingReconciler struct {
+	client.Client
+	Scheme                   *runtime.Scheme
+	ClusterResourceNamespace string
+	ClientBuilder            facade.SpaceClientBuilder
+	ReconcileTimeout         time.Duration
+	Config                   *config.Config
+}
+
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=routebindings,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=routebindings/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=routebindings/finalizers,verbs=update
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=routes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=serviceinstances,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=clusterspaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=spaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+
+func (r *RouteBindingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	log := ctrl.LoggerFrom(ctx)
+	log.V(2).Info("Running reconcile")
+
+	// Retrieve target route binding
+	routeBinding := &cfv1alpha1.RouteBinding{}
+	if err := r.Get(ctx, req.NamespacedName, routeBinding); err != nil {
+		if err := client.IgnoreNotFound(err); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "unexpected get error")
+		}
+		log.V(1).Info("Not found; ignoring")
+		return ctrl.Result{}, nil
+	}
+
+	// Bound the duration of this reconcile (unless overridden by annotation, as configured); the final status update (see below)
+	// is attempted even after expiry
+	reconcileCallTimeout := getReconcileCallTimeout(routeBinding.GetAnnotations(), r.ReconcileTimeout)
+	ctx, cancel := withReconcileTimeout(ctx, reconcileCallTimeout)
+	defer cancel()
+	ctx, cfResponded := facade.WithResponseRecorder(ctx)
+
+	// Call the defaulting webhook logic also here (because defaulting through the webhook might be incomplete in case of generateName usage)
+	routeBinding.Default()
+
+	spec := &routeBinding.Spec
+	status := &routeBinding.Status
+	status.LastReconciledAt = &[]metav1.Time{metav1.Now()}[0]
+
+	// Always attempt to update the status
+	skipStatusUpdate := false
+	defer func() {
+		if skipStatusUpdate {
+			return
+		}
+		if cfResponded() {
+			routeBinding.SetCondition(cfv1alpha1.RouteBindingConditionCFReachable, cfv1alpha1.ConditionTrue, conditionReasonReachable, "Cloud Foundry API is reachable")
+		}
+		err = reportReconcileTimeout(ctx, routeBinding, cfv1alpha1.RouteBindingConditionTimeout, err, reconcileCallTimeout)
+		if err != nil {
+			if unavailableResult, ok := cfUnavailableResult(err); ok {
+				log.V(1).Info("Cloud Foundry API unavailable; requeuing", "requeueAfter", unavailableResult.RequeueAfter)
+				routeBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, readyConditionReasonCFUnavailable, err.Error())
+				routeBinding.SetCondition(cfv1alpha1.RouteBindingConditionCFReachable, cfv1alpha1.ConditionFalse, readyConditionReasonCFUnavailable, err.Error())
+				result, err = unavailableResult, nil
+			} else {
+				routeBinding.SetReadyCondition(cfv1alpha1.ConditionFalse, routeBindingReadyConditionReasonError, err.Error())
+				routeBinding.SetCondition(cfv1alpha1.RouteBindingConditionSynced, cfv1alpha1.ConditionFalse, conditionReasonError, err.Error())
+			}
+		}
+		if updateErr := r.Status().Update(context.WithoutCancel(ctx), routeBinding); updateErr != nil {
+			err = utilerrors.NewAggregate([]error{err, updateErr})
+			result = ctrl.Result{}
+		}
+	}()
+
+	// While paused, nothing is read from or changed in Cloud Foundry; the annotation being removed triggers the next reconciliation
+	if isPaused(routeBinding.Annotations) {
+		routeBinding.SetCondition(cfv1alpha1.RouteBindingConditionPaused, cfv1alpha1.ConditionTrue, conditionReasonPaused, pausedMessage)
+		return ctrl.Result{}, nil
+	}
+	if routeBinding.GetCondition(cfv1alpha1.RouteBindingConditionPaused) != nil {
+		// persist the removal of the condition first (and requeue), since updates of the object (e.g. adding finalizers) reset the status
+		routeBinding.RemoveCondition(cfv1alpha1.RouteBindingConditionPaused)
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Set a first status (and requeue, because the status update itself will not trigger another reconciliation because of the event filter set)
+	if ready := routeBinding.GetReadyCondition(); ready == nil {
+		routeBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, routeBindingReadyConditionReasonNew, "First seen")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Retrieve referenced route
+	route := &cfv1alpha1.Route{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: routeBinding.Namespace, Name: spec.RouteName}, route); err != nil {
+		if apierrors.IsNotFound(err) && !routeBinding.DeletionTimestamp.IsZero() {
+			// without the route, the space (and therefore the cloud foundry route binding) cannot be determined; since routes are only
+			// deleted once their route bindings are gone, this happens only if the route was removed forcibly, and the route binding is
+			// released as is
+			log.V(1).Info("Route not found; releasing route binding", "route", spec.RouteName)
+			if containsString(routeBinding.Finalizers, routeBindingFinalizer) {
+				controllerutil.RemoveFinalizer(routeBinding, routeBindingFinalizer)
+				if err := r.Update(ctx, routeBinding); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			skipStatusUpdate = true
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, errors.Wrapf(err, "failed to get Route, name: %s", spec.RouteName)
+	}
+	// Call the defaulting webhook logic also here (because defaulting through the webhook might be incomplete in case of generateName usage)
+	route.Default()
+
+	// Retrieve referenced service instance; it is not needed for the deletion of the route binding
+	serviceInstance := &cfv1alpha1.ServiceInstance{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: routeBinding.Namespace, Name: spec.ServiceInstanceName}, serviceInstance); err != nil {
+		if !apierrors.IsNotFound(err) || routeBinding.DeletionTimestamp.IsZero() {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to get ServiceInstance, name: %s", spec.ServiceInstanceName)
+		}
+	}
+
+	// Retrieve referenced space (through the route); the service instance must live in the same space
+	space, spaceSecretName, err := getReferencedSpace(ctx, r.Client, r.ClusterResourceNamespace, r.Config, route.Namespace, route.Spec.SpaceName, route.Spec.ClusterSpaceName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	spaceGuid := space.GetSpec().Guid
+	if spaceGuid == "" {
+		spaceGuid = space.GetStatus().SpaceGuid
+	}
+
+	// Apply the configuration overrides of the space
+	annotations := getEffectiveAnnotations(routeBinding.GetAnnotations(), space)
+
+	spaceSecret := &corev1.Secret{}
+	if err := r.Get(ctx, spaceSecretName, spaceSecret); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to get Secret containing space credentials, secret name: %s", spaceSecretName)
+	}
+
+	// Require readiness of space unless in deletion case
+	if routeBinding.DeletionTimestamp.IsZero() {
+		if !space.IsReady() {
+			routeBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, routeBindingReadyConditionReasonSpaceNotReady, getSpaceNotReadyMessage(space))
+			// TODO: apply some increasing period, depending on the age of the last update
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		if spaceGuid == "" {
+			return ctrl.Result{}, fmt.Errorf("unexpected error; unable to find guid on ready %s: name: %s", space.GetKind(), space.GetName())
+		}
+	}
+
+	// Build cloud foundry client
+	endpoint := getEndpoint(space, spaceSecret, annotations)
+	var client facade.SpaceClient
+	if spaceGuid != "" {
+		client, err = r.ClientBuilder(spaceGuid, endpoint, string(spaceSecret.Data["username"]), string(spaceSecret.Data["password"]), getClientConfig(getSpaceConfig(r.Config, space), spaceSecret))
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to build the client from secret %s", spaceSecretName)
+		}
+	}
+
+	// Retrieve cloud foundry route binding
+	var cfbinding *facade.RouteBinding
+	if client != nil {
+		log.V(1).Info("Retrieving route binding")
+		cfbinding, err = client.GetRouteBinding(ctx, string(routeBinding.UID))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// In observe-only mode, the cloud foundry route binding is only read; nothing is created, updated or deleted
+	if isObserveOnly(r.Config, routeBinding.Annotations) {
+		if !routeBinding.DeletionTimestamp.IsZero() {
+			// the cloud foundry route binding is left untouched; just release the object (if it was managed before)
+			if containsString(routeBinding.Finalizers, routeBindingFinalizer) {
+				controllerutil.RemoveFinalizer(routeBinding, routeBindingFinalizer)
+				if err := r.Update(ctx, routeBinding); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			skipStatusUpdate = true
+			return ctrl.Result{}, nil
+		}
+		routeBinding.SetCondition(cfv1alpha1.RouteBindingConditionSynced, cfv1alpha1.ConditionUnknown, conditionReasonObserveOnly, "Changes are not applied in observe-only mode")
+		if cfbinding == nil {
+			routeBinding.SetReadyCondition(cfv1alpha1.ConditionFalse, readyConditionReasonNotFound, "Cloud Foundry route binding not found (observe-only mode; it will not be created)")
+			return getPollingInterval(annotations, getDefaultPollingIntervalReady(r.Config, "RouteBinding"), cfv1alpha1.AnnotationPollingIntervalReady), nil
+		}
+		r.updateStatus(routeBinding, cfbinding, spaceGuid)
+		switch cfbinding.State {
+		case facade.BindingStateReady:
+			routeBinding.SetReadyCondition(cfv1alpha1.ConditionTrue, string(cfbinding.State), cfbinding.StateDescription)
+		case facade.BindingStateCreatedFailed, facade.BindingStateDeleteFailed:
+			routeBinding.SetReadyCondition(cfv1alpha1.ConditionFalse, string(cfbinding.State), cfbinding.StateDescription)
+		default:
+			routeBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, string(cfbinding.State), cfbinding.StateDescription)
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		return getPollingInterval(annotations, getDefaultPollingIntervalReady(r.Config, "RouteBinding"), cfv1alpha1.AnnotationPollingIntervalReady), nil
+	}
+
+	if routeBinding.DeletionTimestamp.IsZero() {
+		// Create/update case
+		if !containsString(routeBinding.Finalizers, routeBindingFinalizer) {
+			controllerutil.AddFinalizer(routeBinding, routeBindingFinalizer)
+			if err := r.Update(ctx, routeBinding); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		if !route.IsReady() {
+			routeBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, routeBindingReadyConditionReasonRouteNotReady,
+				fmt.Sprintf("Referenced Route is not ready, name: %s", route.Name))
+			// TODO: apply some increasing period, depending on the age of the last update
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		if !serviceInstance.IsReady() {
+			routeBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, routeBindingReadyConditionReasonServiceInstanceNotReady,
+				fmt.Sprintf("Referenced ServiceInstance is not ready, name: %s", serviceInstance.Name))
+			// TODO: apply some increasing period, depending on the age of the last update
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		if serviceInstance.Status.SpaceGuid != spaceGuid {
+			return ctrl.Result{}, fmt.Errorf("referenced ServiceInstance %s does not belong to the space of Route %s", serviceInstance.Name, route.Name)
+		}
+
+		parameters, err := getParameters(ctx, r.Client, routeBinding, spec.Parameters, spec.ParametersFrom, spec.ParametersMergeStrategy)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		inRecreation := false
+
+		if cfbinding == nil {
+			log.V(1).Info("Creating route binding")
+			if err := client.CreateRouteBinding(
+				ctx,
+				route.Status.RouteGuid,
+				serviceInstance.Status.ServiceInstanceGuid,
+				parameters,
+				string(routeBinding.UID),
+				routeBinding.Generation,
+			); err != nil {
+				return ctrl.Result{}, err
+			}
+			status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
+		} else {
+			if cfbinding.State == facade.BindingStateDeleting {
+				// This is the re-creation case; nothing to, we just wait until it is gone
+			} else if cfbinding.ParameterHash != facade.ObjectHash(parameters) ||
+				cfbinding.State == facade.BindingStateCreatedFailed || cfbinding.State == facade.BindingStateDeleteFailed {
+				// Re-create route binding (cloud foundry does not support route binding updates, other than metadata)
+				log.V(1).Info("Deleting route binding for later re-creation")
+				if err := client.DeleteRouteBinding(ctx, cfbinding.Guid); err != nil {
+					return ctrl.Result{}, err
+				}
+				status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
+				inRecreation = true
+				// Clear route binding, so it will be re-read below
+				cfbinding = nil
+			} else if cfbinding.Generation < routeBinding.Generation {
+				log.V(1).Info("Updating route binding")
+				if err := client.UpdateRouteBinding(ctx, cfbinding.Guid, routeBinding.Generation); err != nil {
+					return ctrl.Result{}, err
+				}
+				status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
+				// Clear route binding, so it will be re-read below
+				cfbinding = nil
+			}
+		}
+
+		if cfbinding == nil {
+			// Re-retrieve cloud foundry route binding by UID; this happens exactly if the route binding was created or updated above
+			log.V(1).Info("Retrieving route binding")
+			cfbinding, err = client.GetRouteBinding(ctx, string(routeBinding.UID))
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if cfbinding == nil {
+				if inRecreation {
+					// This is the re-create case, if the route binding is already gone (maybe deleted synchronously)
+					return ctrl.Result{Requeue: true}, nil
+				}
+				return ctrl.Result{}, fmt.Errorf("unexpected error; route binding not found in cloud foundry although it should exist")
+			}
+		}
+
+		// Update status
+		r.updateStatus(routeBinding, cfbinding, spaceGuid)
+		switch cfbinding.State {
+		case facade.BindingStateReady:
+			routeBinding.SetReadyCondition(cfv1alpha1.ConditionTrue, string(cfbinding.State), cfbinding.StateDescription)
+			routeBinding.SetCondition(cfv1alpha1.RouteBindingConditionSynced, cfv1alpha1.ConditionTrue, conditionReasonSynced, "Cloud Foundry route binding reflects the current spec")
+			// TODO: apply some increasing period, depending on the age of the last update
+			return getPollingInterval(annotations, getDefaultPollingIntervalReady(r.Config, "RouteBinding"), cfv1alpha1.AnnotationPollingIntervalReady), nil
+		case facade.BindingStateCreatedFailed, facade.BindingStateDeleteFailed:
+			routeBinding.SetReadyCondition(cfv1alpha1.ConditionFalse, string(cfbinding.State), cfbinding.StateDescription)
+			routeBinding.SetCondition(cfv1alpha1.RouteBindingConditionSynced, cfv1alpha1.ConditionFalse, string(cfbinding.State), cfbinding.StateDescription)
+			// TODO: apply some increasing period, depending on the age of the last update
+			return ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
+		default:
+			routeBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, string(cfbinding.State), cfbinding.StateDescription)
+			routeBinding.SetCondition(cfv1alpha1.RouteBindingConditionSynced, cfv1alpha1.ConditionUnknown, string(cfbinding.State), cfbinding.StateDescription)
+			// TODO: apply some increasing period, depending on the age of the last update
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+	} else if len(removeString(routeBinding.Finalizers, routeBindingFinalizer)) > 0 {
+		routeBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, routeBindingReadyConditionReasonDeletionBlocked, "Deletion blocked due to foreign finalizers")
+		routeBinding.SetCondition(cfv1alpha1.RouteBindingConditionDeletionBlocked, cfv1alpha1.ConditionTrue, conditionReasonForeignFinalizers, "Deletion blocked due to foreign finalizers")
+		// TODO: apply some increasing period, depending on the age of the last update
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	} else {
+		// Deletion case
+		routeBinding.RemoveCondition(cfv1alpha1.RouteBindingConditionDeletionBlocked)
+		if cfbinding == nil {
+			if containsString(routeBinding.Finalizers, routeBindingFinalizer) {
+				controllerutil.RemoveFinalizer(routeBinding, routeBindingFinalizer)
+				if err := r.Update(ctx, routeBinding); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			// skip status update, since the route binding will anyway deleted timely by the API server
+			skipStatusUpdate = true
+			return ctrl.Result{}, nil
+		}
+		if cfbinding.State != facade.BindingStateDeleting {
+			log.V(1).Info("Deleting route binding")
+			if err := client.DeleteRouteBinding(ctx, cfbinding.Guid); err != nil {
+				return ctrl.Result{}, err
+			}
+			status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
+			cfbinding.State = facade.BindingStateUnknown
+			cfbinding.StateDescription = "Deletion triggered."
+		}
+		routeBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, string(cfbinding.State), cfbinding.StateDescription)
+		// TODO: apply some increasing period, depending on the age of the last update
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+}
+
+func (r *RouteBindingReconciler) updateStatus(routeBinding *cfv1alpha1.RouteBinding, cfbinding *facade.RouteBinding, spaceGuid string) {
+	status := &routeBinding.Status
+	status.SpaceGuid = spaceGuid
+	status.RouteGuid = cfbinding.RouteGuid
+	status.ServiceInstanceGuid = cfbinding.ServiceInstanceGuid
+	status.RouteBindingGuid = cfbinding.Guid
+	status.RouteServiceURL = cfbinding.RouteServiceURL
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RouteBindingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	reconciler, options := limitConcurrency(traceReconciles(measureReconciles(labelReconciles(r, "RouteBinding"), "RouteBinding"), "RouteBinding"), r.Config, nil)
+	reconciler, namespacePredicate := filterNamespaces(reconciler, mgr.GetClient(), r.Config)
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&cfv1alpha1.RouteBinding{}).
+		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})).
+		WithEventFilter(namespacePredicate).
+		WithOptions(options).
+		Complete(reconciler)
+}
@@ -0,0 +1,221 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+	"github.com/sap/cf-service-operator/internal/facade/facadefakes"
+)
+
+var _ = Describe("Reconcile route bindings | RouteBindingReconciler", func() {
+	ctx := context.Background()
+	routeBindingKey := types.NamespacedName{Namespace: "ns", Name: "route-binding"}
+	var spaceClient *facadefakes.FakeSpaceClient
+
+	newReconciler := func(objects ...client.Object) *RouteBindingReconciler {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		space := &cfv1alpha1.Space{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space"},
+			Spec:       cfv1alpha1.SpaceSpec{Guid: "space-guid", AuthSecretName: "space-secret"},
+		}
+		space.SetReadyCondition(cfv1alpha1.ConditionTrue, "Ready", "")
+		objects = append(objects,
+			space,
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space-secret"},
+				Data:       map[string][]byte{"url": []byte("https://api.cf.example.com")},
+			},
+		)
+		return &RouteBindingReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(objects...).
+				WithStatusSubresource(&cfv1alpha1.RouteBinding{}).
+				Build(),
+			ClientBuilder: func(string, string, string, string, *config.Config) (facade.SpaceClient, error) {
+				return spaceClient, nil
+			},
+			Config: config.Defaults(),
+		}
+	}
+
+	newRoute := func() *cfv1alpha1.Route {
+		route := &cfv1alpha1.Route{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "route", Generation: 1},
+			Spec:       cfv1alpha1.RouteSpec{SpaceName: "space", Domain: "example.com", Host: "my-app"},
+			Status:     cfv1alpha1.RouteStatus{RouteGuid: "route-guid"},
+		}
+		route.SetReadyCondition(cfv1alpha1.ConditionTrue, routeReadyConditionReasonCreated, "Route exists")
+		return route
+	}
+
+	newServiceInstance := func() *cfv1alpha1.ServiceInstance {
+		serviceInstance := &cfv1alpha1.ServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "instance", Generation: 1},
+			Spec:       cfv1alpha1.ServiceInstanceSpec{SpaceName: "space"},
+			Status:     cfv1alpha1.ServiceInstanceStatus{SpaceGuid: "space-guid", ServiceInstanceGuid: "instance-guid"},
+		}
+		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionTrue, string(facade.InstanceStateReady), "")
+		return serviceInstance
+	}
+
+	newRouteBinding := func() *cfv1alpha1.RouteBinding {
+		routeBinding := &cfv1alpha1.RouteBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: routeBindingKey.Namespace, Name: routeBindingKey.Name, UID: "route-binding-uid", Generation: 1},
+			Spec: cfv1alpha1.RouteBindingSpec{
+				RouteName:           "route",
+				ServiceInstanceName: "instance",
+				Parameters:          &apiextensionsv1.JSON{Raw: []byte(`{"limit":100}`)},
+			},
+		}
+		routeBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, routeBindingReadyConditionReasonNew, "First seen")
+		return routeBinding
+	}
+
+	newDeletedRouteBinding := func() *cfv1alpha1.RouteBinding {
+		routeBinding := newRouteBinding()
+		routeBinding.Finalizers = []string{routeBindingFinalizer}
+		routeBinding.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+		return routeBinding
+	}
+
+	parameterHash := facade.ObjectHash(map[string]interface{}{"limit": float64(100)})
+
+	BeforeEach(func() {
+		spaceClient = &facadefakes.FakeSpaceClient{}
+	})
+
+	It("should neither read nor change the route binding while paused", func() {
+		routeBinding := newRouteBinding()
+		routeBinding.Annotations = map[string]string{cfv1alpha1.AnnotationPaused: "true"}
+		reconciler := newReconciler(routeBinding, newRoute(), newServiceInstance())
+
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: routeBindingKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(ctrl.Result{}))
+
+		Expect(spaceClient.Invocations()).To(BeEmpty())
+		Expect(reconciler.Get(ctx, routeBindingKey, routeBinding)).To(Succeed())
+		Expect(routeBinding.Finalizers).To(BeEmpty())
+		Expect(routeBinding.GetCondition(cfv1alpha1.RouteBindingConditionPaused)).ToNot(BeNil())
+		Expect(routeBinding.GetCondition(cfv1alpha1.RouteBindingConditionPaused).Status).To(Equal(cfv1alpha1.ConditionTrue))
+
+		// resume
+		delete(routeBinding.Annotations, cfv1alpha1.AnnotationPaused)
+		Expect(reconciler.Update(ctx, routeBinding)).To(Succeed())
+		result, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: routeBindingKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+		Expect(reconciler.Get(ctx, routeBindingKey, routeBinding)).To(Succeed())
+		Expect(routeBinding.GetCondition(cfv1alpha1.RouteBindingConditionPaused)).To(BeNil())
+	})
+
+	It("should create the route binding and report the route service url", func() {
+		reconciler := newReconciler(newRoute(), newServiceInstance(), newRouteBinding())
+		spaceClient.GetRouteBindingReturnsOnCall(0, nil, nil)
+		spaceClient.GetRouteBindingReturnsOnCall(1, &facade.RouteBinding{
+			Guid:                "route-binding-guid",
+			RouteGuid:           "route-guid",
+			ServiceInstanceGuid: "instance-guid",
+			RouteServiceURL:     "https://route-service.example.com",
+			Owner:               "route-binding-uid",
+			Generation:          1,
+			ParameterHash:       parameterHash,
+			State:               facade.BindingStateReady,
+		}, nil)
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: routeBindingKey})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(spaceClient.CreateRouteBindingCallCount()).To(Equal(1))
+		_, routeGuid, serviceInstanceGuid, parameters, owner, generation := spaceClient.CreateRouteBindingArgsForCall(0)
+		Expect([]interface{}{routeGuid, serviceInstanceGuid, owner, generation}).To(Equal([]interface{}{"route-guid", "instance-guid", "route-binding-uid", int64(1)}))
+		Expect(facade.ObjectHash(parameters)).To(Equal(parameterHash))
+		routeBinding := &cfv1alpha1.RouteBinding{}
+		Expect(reconciler.Get(ctx, routeBindingKey, routeBinding)).To(Succeed())
+		Expect(routeBinding.Finalizers).To(ContainElement(routeBindingFinalizer))
+		Expect(routeBinding.Status.RouteBindingGuid).To(Equal("route-binding-guid"))
+		Expect(routeBinding.Status.RouteServiceURL).To(Equal("https://route-service.example.com"))
+		Expect(routeBinding.IsReady()).To(BeTrue())
+	})
+
+	It("should re-create the route binding if its parameters changed", func() {
+		reconciler := newReconciler(newRoute(), newServiceInstance(), newRouteBinding())
+		spaceClient.GetRouteBindingReturnsOnCall(0, &facade.RouteBinding{Guid: "route-binding-guid", Owner: "route-binding-uid", Generation: 1, ParameterHash: "outdated", State: facade.BindingStateReady}, nil)
+		spaceClient.GetRouteBindingReturnsOnCall(1, nil, nil)
+
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: routeBindingKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+
+		Expect(spaceClient.DeleteRouteBindingCallCount()).To(Equal(1))
+		_, guid := spaceClient.DeleteRouteBindingArgsForCall(0)
+		Expect(guid).To(Equal("route-binding-guid"))
+		Expect(spaceClient.CreateRouteBindingCallCount()).To(BeZero())
+	})
+
+	It("should delete the route binding, even if the service instance is gone", func() {
+		reconciler := newReconciler(newRoute(), newDeletedRouteBinding())
+		spaceClient.GetRouteBindingReturns(&facade.RouteBinding{Guid: "route-binding-guid", Owner: "route-binding-uid", Generation: 1, State: facade.BindingStateReady}, nil)
+
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: routeBindingKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).ToNot(BeZero())
+
+		Expect(spaceClient.DeleteRouteBindingCallCount()).To(Equal(1))
+		routeBinding := &cfv1alpha1.RouteBinding{}
+		Expect(reconciler.Get(ctx, routeBindingKey, routeBinding)).To(Succeed())
+		Expect(routeBinding.Finalizers).To(ContainElement(routeBindingFinalizer))
+
+		// once the cloud foundry route binding is gone, the object is released
+		spaceClient.GetRouteBindingReturns(nil, nil)
+		_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: routeBindingKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(spaceClient.DeleteRouteBindingCallCount()).To(Equal(1))
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, routeBindingKey, routeBinding))).To(BeTrue())
+	})
+
+	It("should release the route binding if the route is gone", func() {
+		reconciler := newReconciler(newServiceInstance(), newDeletedRouteBinding())
+
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: routeBindingKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(ctrl.Result{}))
+
+		Expect(spaceClient.Invocations()).To(BeEmpty())
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, routeBindingKey, &cfv1alpha1.RouteBinding{}))).To(BeTrue())
+	})
+
+	It("should fail if the route is missing, unless the route binding is being deleted", func() {
+		reconciler := newReconciler(newServiceInstance(), newRouteBinding())
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: routeBindingKey})
+		Expect(err).To(MatchError(ContainSubstring("failed to get Route, name: route")))
+
+		routeBinding := &cfv1alpha1.RouteBinding{}
+		Expect(reconciler.Get(ctx, routeBindingKey, routeBinding)).To(Succeed())
+		Expect(routeBinding.GetReadyCondition().Reason).To(Equal(routeBindingReadyConditionReasonError))
+	})
+})
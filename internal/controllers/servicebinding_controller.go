@@ -6,13 +6,22 @@ SPDX-License-Identifier: Apache-2.0
 package controllers
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"text/template"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -20,10 +29,13 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
 	"github.com/sap/cf-service-operator/internal/binding"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/events"
 	"github.com/sap/cf-service-operator/internal/facade"
 )
 
@@ -37,9 +49,20 @@ const (
 	serviceBindingReadyConditionReasonServiceInstanceNotReady = "ServiceInstanceNotReady"
 	serviceBindingReadyConditionReasonError                   = "Error"
 	serviceBindingReadyConditionReasonDeletionBlocked         = "DeletionBlocked"
+	serviceBindingReadyConditionReasonMaxRetriesExceeded      = "MaximumRetriesExceeded"
 	// Additionally, all of facade.BindingState* may occur as Ready condition reason
 )
 
+// Reason of the Synced condition while a service key is re-created through a replacement binding (create-before-delete)
+const serviceBindingConditionReasonReplacing = "Replacing"
+
+const (
+	// Default values for failed Cloud Foundry bindings
+	serviceBindingDefaultMaxRetries       = math.MaxInt32 // infinite number of retries
+	serviceBindingDefaultRetryInterval    = 10 * time.Second
+	serviceBindingDefaultMaxRetryInterval = 10 * time.Minute
+)
+
 // ServiceBindingReconciler reconciles a ServiceBinding object
 type ServiceBindingReconciler struct {
 	client.Client
@@ -47,6 +70,12 @@ type ServiceBindingReconciler struct {
 	ClusterResourceNamespace string
 	EnableBindingMetadata    bool
 	ClientBuilder            facade.SpaceClientBuilder
+	ReconcileTimeout         time.Duration
+	Config                   *config.Config
+	// Optional; if set, service bindings are reconciled when their service instance is updated or deleted through the operator
+	EventBus *events.Bus
+	// Optional; if set, the binding secret name is defaulted by rendering this template (instead of using metadata.name)
+	SecretNameTemplate *template.Template
 }
 
 // +kubebuilder:rbac:groups=cf.cs.sap.com,resources=servicebindings,verbs=get;list;watch;update
@@ -55,7 +84,9 @@ type ServiceBindingReconciler struct {
 // +kubebuilder:rbac:groups=cf.cs.sap.com,resources=serviceinstances,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cf.cs.sap.com,resources=clusterspaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cf.cs.sap.com,resources=spaces,verbs=get;list;watch
-// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=external-secrets.io,resources=pushsecrets,verbs=get;list;watch;create;update;delete
 
 func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -70,13 +101,26 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		log.V(1).Info("Not found; ignoring")
 		return ctrl.Result{}, nil
 	}
+
+	// Bound the duration of this reconcile (unless overridden by annotation, as configured); the final status update (see below)
+	// is attempted even after expiry
+	reconcileCallTimeout := getReconcileCallTimeout(serviceBinding.GetAnnotations(), r.ReconcileTimeout)
+	ctx, cancel := withReconcileTimeout(ctx, reconcileCallTimeout)
+	defer cancel()
+	ctx, cfResponded := facade.WithResponseRecorder(ctx)
+
 	// Call the defaulting webhook logic also here (because defaulting through the webhook might be incomplete in case of generateName usage)
+	if err := serviceBinding.DefaultSecretName(r.SecretNameTemplate); err != nil {
+		return ctrl.Result{}, err
+	}
 	serviceBinding.Default()
 
 	spec := &serviceBinding.Spec
 	status := &serviceBinding.Status
-	status.ObservedGeneration = serviceBinding.Generation
 	status.LastReconciledAt = &[]metav1.Time{metav1.Now()}[0]
+	status.LastCFError = nil
+	// Annotations complemented by the overrides of the referenced space (once retrieved)
+	annotations := serviceBinding.GetAnnotations()
 
 	// Always attempt to update the status
 	skipStatusUpdate := false
@@ -84,37 +128,52 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		if skipStatusUpdate {
 			return
 		}
+		if cfResponded() {
+			serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionCFReachable, cfv1alpha1.ConditionTrue, conditionReasonReachable, "Cloud Foundry API is reachable")
+		}
+		err = reportReconcileTimeout(ctx, serviceBinding, cfv1alpha1.ServiceBindingConditionTimeout, err, reconcileCallTimeout)
 		if err != nil {
-			serviceBinding.SetReadyCondition(cfv1alpha1.ConditionFalse, serviceBindingReadyConditionReasonError, err.Error())
+			if unavailableResult, ok := cfUnavailableResult(err); ok {
+				log.V(1).Info("Cloud Foundry API unavailable; requeuing", "requeueAfter", unavailableResult.RequeueAfter)
+				serviceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, readyConditionReasonCFUnavailable, err.Error())
+				serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionCFReachable, cfv1alpha1.ConditionFalse, readyConditionReasonCFUnavailable, err.Error())
+				result, err = unavailableResult, nil
+			} else {
+				result, err = r.HandleError(serviceBinding, annotations, err, log)
+			}
 		}
-		if updateErr := r.Status().Update(ctx, serviceBinding); updateErr != nil {
+		if updateErr := r.Status().Update(context.WithoutCancel(ctx), serviceBinding); updateErr != nil {
 			err = utilerrors.NewAggregate([]error{err, updateErr})
 			result = ctrl.Result{}
 		}
 	}()
 
+	// While paused, nothing is read from or changed in Cloud Foundry; the annotation being removed triggers the next reconciliation
+	if isPaused(serviceBinding.Annotations) {
+		serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionPaused, cfv1alpha1.ConditionTrue, conditionReasonPaused, pausedMessage)
+		return ctrl.Result{}, nil
+	}
+	if serviceBinding.GetCondition(cfv1alpha1.ServiceBindingConditionPaused) != nil {
+		// persist the removal of the condition first (and requeue), since updates of the object (e.g. adding finalizers) reset the status
+		serviceBinding.RemoveCondition(cfv1alpha1.ServiceBindingConditionPaused)
+		return ctrl.Result{Requeue: true}, nil
+	}
+
 	// Set a first status (and requeue, because the status update itself will not trigger another reconciliation because of the event filter set)
 	if ready := serviceBinding.GetReadyCondition(); ready == nil {
 		serviceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceBindingReadyConditionReasonNew, "First seen")
+		status.MaxRetries = getMaxRetries(annotations, serviceBindingDefaultMaxRetries, log)
 		return ctrl.Result{Requeue: true}, nil
 	}
 
 	// Retrieve referenced service instance
-	serviceInstanceName := types.NamespacedName{
-		Namespace: serviceBinding.Namespace,
-		Name:      spec.ServiceInstanceName,
-	}
-	serviceInstance := &cfv1alpha1.ServiceInstance{}
-	if err := r.Get(ctx, serviceInstanceName, serviceInstance); err != nil {
-		return ctrl.Result{}, errors.Wrapf(err, "failed to get ServiceInstance, name: %s", spec.ServiceInstanceName)
+	serviceInstance, err := r.getServiceInstance(ctx, serviceBinding)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
-	// Call the defaulting webhook logic also here (because defaulting through the webhook might be incomplete in case of generateName usage)
-	serviceInstance.Default()
 
 	// Retrieve referenced space
 	var space cfv1alpha1.GenericSpace
-	var spaceSecretName types.NamespacedName
-
 	if serviceInstance.Spec.SpaceName != "" {
 		spaceName := types.NamespacedName{
 			Namespace: serviceInstance.Namespace,
@@ -124,10 +183,6 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		if err := r.Get(ctx, spaceName, space); err != nil {
 			return ctrl.Result{}, errors.Wrapf(err, "failed to get Space, name: %s", serviceInstance.Spec.SpaceName)
 		}
-		spaceSecretName = types.NamespacedName{
-			Namespace: serviceInstance.Namespace,
-			Name:      space.GetSpec().AuthSecretName,
-		}
 	} else if serviceInstance.Spec.ClusterSpaceName != "" {
 		clusterSpaceName := types.NamespacedName{
 			Name: serviceInstance.Spec.ClusterSpaceName,
@@ -136,16 +191,24 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		if err := r.Get(ctx, clusterSpaceName, space); err != nil {
 			return ctrl.Result{}, errors.Wrapf(err, "failed to get ClusterSpace, name: %s", serviceInstance.Spec.ClusterSpaceName)
 		}
-		spaceSecretName = types.NamespacedName{
-			Namespace: r.ClusterResourceNamespace,
-			Name:      space.GetSpec().AuthSecretName,
-		}
+	}
+
+	spaceSecretName, err := getSpaceSecretName(space, r.ClusterResourceNamespace, r.Config)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 
 	spaceGuid := space.GetSpec().Guid
 	if spaceGuid == "" {
 		spaceGuid = space.GetStatus().SpaceGuid
 	}
+	if spec.ServiceInstanceGuid != "" {
+		serviceInstance.Status.SpaceGuid = spaceGuid
+	}
+
+	// Apply the configuration overrides of the space
+	annotations = getEffectiveAnnotations(annotations, space)
+	status.MaxRetries = getMaxRetries(annotations, serviceBindingDefaultMaxRetries, log)
 
 	spaceSecret := &corev1.Secret{}
 	if err := r.Get(ctx, spaceSecretName, spaceSecret); err != nil {
@@ -155,8 +218,7 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	// Require readiness of space unless in deletion case
 	if serviceBinding.DeletionTimestamp.IsZero() {
 		if !space.IsReady() {
-			serviceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceBindingReadyConditionReasonSpaceNotReady,
-				fmt.Sprintf("Referenced %s is not ready, name: %s", space.GetKind(), space.GetName()))
+			serviceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceBindingReadyConditionReasonSpaceNotReady, getSpaceNotReadyMessage(space))
 			// TODO: apply some increasing period, depending on the age of the last update
 			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 		}
@@ -166,14 +228,32 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	}
 
 	// Build cloud foundry client
+	endpoint := getEndpoint(space, spaceSecret, annotations)
+	status.Endpoint = endpoint
 	var client facade.SpaceClient
 	if spaceGuid != "" {
-		client, err = r.ClientBuilder(spaceGuid, string(spaceSecret.Data["url"]), string(spaceSecret.Data["username"]), string(spaceSecret.Data["password"]))
+		client, err = r.ClientBuilder(spaceGuid, endpoint, string(spaceSecret.Data["username"]), string(spaceSecret.Data["password"]), getClientConfig(getSpaceConfig(r.Config, space), spaceSecret))
 		if err != nil {
 			return ctrl.Result{}, errors.Wrapf(err, "failed to build the client from secret %s", spaceSecretName)
 		}
 	}
 
+	// In observe-only mode, the cloud foundry binding is only read; nothing is created, updated or deleted (neither the binding secret)
+	if isObserveOnly(r.Config, serviceBinding.Annotations) {
+		if !serviceBinding.DeletionTimestamp.IsZero() {
+			// the cloud foundry binding and the binding secret are left untouched; just release the object (if it was managed before)
+			if containsString(serviceBinding.Finalizers, serviceBindingFinalizer) {
+				controllerutil.RemoveFinalizer(serviceBinding, serviceBindingFinalizer)
+				if err := r.Update(ctx, serviceBinding); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			skipStatusUpdate = true
+			return ctrl.Result{}, nil
+		}
+		return r.observeBinding(ctx, serviceBinding, annotations, client, spaceGuid)
+	}
+
 	// Retrieve cloud foundry binding
 	var cfbinding *facade.Binding
 	bindingOpts := map[string]string{"name": "", "owner": string(serviceBinding.UID)}
@@ -184,7 +264,42 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			return ctrl.Result{}, err
 		}
 		orphan, exists := serviceBinding.Annotations[cfv1alpha1.AnnotationAdoptCFResources]
-		if exists && cfbinding == nil && orphan == "adopt" {
+		adoptGuid := serviceBinding.Annotations[cfv1alpha1.AnnotationAdoptCFBindingGuid]
+		adoptLabelSelector := serviceBinding.Annotations[cfv1alpha1.AnnotationAdoptCFLabelSelector]
+		if cfbinding == nil && (adoptGuid != "" || adoptLabelSelector != "") {
+			// find orphaned binding by guid, or binding created by another tool by label selector; it must belong to the referenced service instance
+			if adoptGuid != "" {
+				bindingOpts["guid"] = adoptGuid
+				log.V(1).Info("Retrieving binding by guid", "guid", adoptGuid)
+			} else {
+				bindingOpts["labelSelector"] = adoptLabelSelector
+				log.V(1).Info("Retrieving binding by label selector", "labelSelector", adoptLabelSelector)
+			}
+			cfbinding, err = client.GetBinding(ctx, bindingOpts)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if cfbinding == nil && adoptGuid != "" {
+				return ctrl.Result{}, fmt.Errorf("service binding to be adopted not found, guid: %s", adoptGuid)
+			}
+			if cfbinding == nil {
+				return ctrl.Result{}, fmt.Errorf("service binding to be adopted not found, label selector: %s", adoptLabelSelector)
+			}
+			if adoptGuid != "" {
+				if err := checkAdoptable(ctx, r.Client, "service binding", cfbinding.Guid, cfbinding.Owner, serviceBinding); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			if serviceInstance.Status.ServiceInstanceGuid == "" {
+				serviceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceBindingReadyConditionReasonServiceInstanceNotReady,
+					fmt.Sprintf("Referenced ServiceInstance is not ready, name: %s", serviceInstance.Name))
+				return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+			}
+			if cfbinding.ServiceInstanceGuid != serviceInstance.Status.ServiceInstanceGuid {
+				return ctrl.Result{}, fmt.Errorf("service binding to be adopted belongs to service instance %s instead of %s, guid: %s",
+					cfbinding.ServiceInstanceGuid, serviceInstance.Status.ServiceInstanceGuid, cfbinding.Guid)
+			}
+		} else if exists && cfbinding == nil && orphan == "adopt" {
 			// find orphaned binding by name
 			bindingOpts["name"] = serviceBinding.Name
 			log.V(1).Info("Retrieving binding by name")
@@ -192,7 +307,8 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			if err != nil {
 				return ctrl.Result{}, err
 			}
-
+		}
+		if cfbinding != nil && (bindingOpts["name"] != "" || bindingOpts["guid"] != "" || bindingOpts["labelSelector"] != "") {
 			//Add parameters to adopt the orphaned binding
 			var parameterObjects []map[string]interface{}
 			paramMap := make(map[string]interface{})
@@ -203,13 +319,19 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			if err != nil {
 				return ctrl.Result{}, errors.Wrap(err, "failed to unmarshal/merge parameters")
 			}
+			metadata, err := getAdoptionMetadata(bindingOpts["labelSelector"])
+			if err != nil {
+				return ctrl.Result{}, err
+			}
 			// update the orphaned cloud foundry service binding
 			log.V(1).Info("Updating binding")
 			if err := client.UpdateBinding(
 				ctx,
 				cfbinding.Guid,
+				withOwnerObject(cfbinding.OwnerRef(), serviceBinding),
 				serviceBinding.Generation,
 				parameters,
+				metadata,
 			); err != nil {
 				return ctrl.Result{}, err
 			}
@@ -230,43 +352,17 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			}
 		}
 
-		if !serviceInstance.IsReady() {
+		// unmanaged instances (referenced by guid) are not gated; if they are not usable, Cloud Foundry rejects the binding
+		if spec.ServiceInstanceGuid == "" && !serviceInstance.IsReady() {
 			serviceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceBindingReadyConditionReasonServiceInstanceNotReady,
 				fmt.Sprintf("Referenced ServiceInstance is not ready, name: %s", serviceInstance.Name))
 			// TODO: apply some increasing period, depending on the age of the last update
 			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 		}
 
-		var parameterObjects []map[string]interface{}
-		if spec.Parameters != nil {
-			obj, err := unmarshalObject(spec.Parameters.Raw)
-			if err != nil {
-				return ctrl.Result{}, errors.Wrap(err, "error decoding inline parameters")
-			}
-			parameterObjects = append(parameterObjects, obj)
-		}
-		for _, pf := range spec.ParametersFrom {
-			secretName := types.NamespacedName{
-				Namespace: serviceBinding.Namespace,
-				Name:      pf.SecretKeyRef.Name,
-			}
-			secret := &corev1.Secret{}
-			if err := r.Get(ctx, secretName, secret); err != nil {
-				return ctrl.Result{}, errors.Wrapf(err, "failed to get Secret containing service binding parameters, secret name: %s", secretName)
-			}
-			if raw, ok := secret.Data[pf.SecretKeyRef.Key]; ok {
-				obj, err := unmarshalObject(raw)
-				if err != nil {
-					return ctrl.Result{}, errors.Wrapf(err, "error decoding parameters from secret, secret name: %s, key: %s", secretName, pf.SecretKeyRef.Key)
-				}
-				parameterObjects = append(parameterObjects, obj)
-			} else {
-				return ctrl.Result{}, fmt.Errorf("secret key not found, secret name: %s, key: %s", secretName, pf.SecretKeyRef.Key)
-			}
-		}
-		parameters, err := mergeObjects(parameterObjects...)
+		parameters, err := getParameters(ctx, r.Client, serviceBinding, spec.Parameters, spec.ParametersFrom, spec.ParametersMergeStrategy)
 		if err != nil {
-			return ctrl.Result{}, errors.Wrap(err, "failed to unmarshal/merge parameters")
+			return ctrl.Result{}, err
 		}
 
 		status.ServiceBindingDigest = facade.ObjectHash(map[string]interface{}{"generation": serviceBinding.Generation, "parameters": parameters})
@@ -274,29 +370,88 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		recreateOnParameterChange := serviceBinding.Annotations["service-operator.cf.cs.sap.com/rotate-on-parameter-change"] == "true"
 		recreateOnInstanceChange := serviceBinding.Annotations["service-operator.cf.cs.sap.com/rotate-on-instance-change"] == "true"
 		inRecreation := false
+		// service keys are re-created without credentials gap (see replaceBinding); app bindings cannot be, since an app can be bound
+		// to a service instance only once
+		replaceable := (recreateOnParameterChange || recreateOnInstanceChange) && spec.Type == cfv1alpha1.ServiceBindingTypeKey
+
+		if cfbinding == nil && replaceable {
+			// the binding may have been deleted in favor of a replacement, which is promoted once the replaced binding is gone
+			replacement, err := client.GetBinding(ctx, map[string]string{"replacementOwner": string(serviceBinding.UID)})
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			switch {
+			case replacement == nil:
+				// no replacement in progress
+			case replacement.State == facade.BindingStateReady:
+				log.V(1).Info("Promoting replacement binding", "guid", replacement.Guid)
+				if err := client.PromoteBinding(ctx, replacement.Guid, ownerRefOf(serviceBinding)); err != nil {
+					return ctrl.Result{}, err
+				}
+				status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
+				return ctrl.Result{Requeue: true}, nil
+			case replacement.State == facade.BindingStateCreatedFailed || replacement.State == facade.BindingStateDeleteFailed:
+				log.V(1).Info("Deleting failed replacement binding", "guid", replacement.Guid)
+				if err := client.DeleteBinding(ctx, replacement.Guid, replacement.OwnerRef()); err != nil {
+					return ctrl.Result{}, err
+				}
+				return ctrl.Result{Requeue: true}, nil
+			default:
+				serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionSynced, cfv1alpha1.ConditionUnknown, serviceBindingConditionReasonReplacing, "Waiting for replacement binding")
+				return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+			}
+		}
 
 		if cfbinding == nil {
+			appGuid, err := getAppGuid(ctx, client, spec)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			// the service plan of unmanaged instances is unknown, so their binding parameters are left to the broker to validate
+			schemas := &facade.ServicePlanSchemas{}
+			if serviceInstance.Status.ServicePlanGuid != "" {
+				schemas, err = getServicePlanSchemas(ctx, client, serviceInstance.Status.ServicePlanGuid, serviceInstance.Status.SpaceGuid)
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			if err := validateParameters(schemas.BindingCreate, parameters); err != nil {
+				// the binding is not created (instead of letting the broker fail asynchronously)
+				serviceBinding.SetReadyCondition(cfv1alpha1.ConditionFalse, readyConditionReasonInvalidParameters, err.Error())
+				serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionSynced, cfv1alpha1.ConditionFalse, readyConditionReasonInvalidParameters, err.Error())
+				return ctrl.Result{RequeueAfter: invalidParametersRequeueInterval}, nil
+			}
 			log.V(1).Info("Creating binding")
-			if err := client.CreateBinding(
+			cfbinding, err = client.CreateBinding(
 				ctx,
 				spec.Name,
 				serviceInstance.Status.ServiceInstanceGuid,
+				appGuid,
 				parameters,
-				string(serviceBinding.UID),
+				getCFMetadata(spec.Metadata, false),
+				ownerRefOf(serviceBinding),
 				serviceBinding.Generation,
-			); err != nil {
+			)
+			if err != nil {
 				return ctrl.Result{}, err
 			}
 			status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
 		} else {
 			if cfbinding.State == facade.BindingStateDeleting {
 				// This is the re-creation case; nothing to, we just wait until it is gone
+			} else if replaceable && cfbinding.State == facade.BindingStateReady &&
+				((recreateOnParameterChange && cfbinding.ParameterHash != facade.ObjectHash(parameters)) ||
+					(recreateOnInstanceChange && status.ServiceInstanceDigest != serviceInstance.Status.ServiceInstanceDigest) ||
+					cfbinding.Name != spec.Name) {
+				// cloud foundry does not support renaming bindings; so a promoted replacement (see replaceBinding) is renamed
+				// back to spec.name by another replacement
+				return r.replaceBinding(ctx, client, serviceInstance, serviceBinding, cfbinding, parameters)
 			} else if (recreateOnParameterChange && cfbinding.ParameterHash != facade.ObjectHash(parameters)) ||
 				(recreateOnInstanceChange && status.ServiceInstanceDigest != serviceInstance.Status.ServiceInstanceDigest) ||
 				cfbinding.State == facade.BindingStateCreatedFailed || cfbinding.State == facade.BindingStateDeleteFailed {
 				// Re-create binding (unfortunately, cloud foundry does not support binding updates, other than metadata)
 				log.V(1).Info("Deleting binding for later re-creation")
-				if err := client.DeleteBinding(ctx, cfbinding.Guid); err != nil {
+				if err := client.DeleteBinding(ctx, cfbinding.Guid, cfbinding.OwnerRef()); err != nil {
 					return ctrl.Result{}, err
 				}
 				status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
@@ -309,8 +464,10 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 				if err := client.UpdateBinding(
 					ctx,
 					cfbinding.Guid,
+					withOwnerObject(cfbinding.OwnerRef(), serviceBinding),
 					serviceBinding.Generation,
 					nil,
+					getCFMetadata(spec.Metadata, cfbinding.CustomMetadata),
 				); err != nil {
 					return ctrl.Result{}, err
 				}
@@ -320,10 +477,11 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			}
 		}
 
+		previousServiceInstanceDigest := status.ServiceInstanceDigest
 		status.ServiceInstanceDigest = serviceInstance.Status.ServiceInstanceDigest
 
 		if cfbinding == nil {
-			// Re-retrieve cloud foundry binding by UID; this happens exactly if the binding was created or updated above
+			// Re-retrieve cloud foundry binding by UID; this happens exactly if the binding was updated or deleted above
 			log.V(1).Info("Retrieving binding")
 			cfbinding, err = client.GetBinding(ctx, bindingOpts)
 			if err != nil {
@@ -339,54 +497,149 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		}
 
 		// Update status
+		previousServiceBindingGuid := status.ServiceBindingGuid
 		status.SpaceGuid = serviceInstance.Status.SpaceGuid
 		status.ServiceInstanceGuid = serviceInstance.Status.ServiceInstanceGuid
+		status.AppGuid = cfbinding.AppGuid
+		status.Type = cfv1alpha1.ServiceBindingType(cfbinding.Type)
 		status.ServiceBindingGuid = cfbinding.Guid
+		status.LastOperation = getLastOperation(status.LastOperation, cfbinding.LastOperation)
 		switch cfbinding.State {
 		case facade.BindingStateReady:
 			serviceBinding.SetReadyCondition(cfv1alpha1.ConditionTrue, string(cfbinding.State), cfbinding.StateDescription)
-			withMetadata := r.EnableBindingMetadata
-			if serviceBinding.Annotations["service-operator.cf.cs.sap.com/with-sap-binding-metadata"] == "true" {
-				withMetadata = true
-			} else if serviceBinding.Annotations["service-operator.cf.cs.sap.com/with-sap-binding-metadata"] == "false" {
-				withMetadata = false
+			serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionSynced, cfv1alpha1.ConditionTrue, conditionReasonSynced, "Cloud Foundry binding reflects the current spec")
+			status.RetryCounter = 0 // Reset the retry counter
+			withMetadata := r.withBindingMetadata(serviceBinding)
+			secretName := types.NamespacedName{Namespace: getBindingSecretNamespace(serviceBinding), Name: spec.SecretName}
+			refreshInterval := getRefreshCredentialsInterval(serviceBinding.GetAnnotations())
+			refresh := refreshInterval > 0 && (status.LastCredentialsRefreshAt == nil || time.Since(status.LastCredentialsRefreshAt.Time) >= refreshInterval)
+			// credentials are only read from Cloud Foundry if the binding secret has to be (re)written, or if they are due for refresh
+			rewrite := refresh || previousServiceBindingGuid != cfbinding.Guid || previousServiceInstanceDigest != status.ServiceInstanceDigest
+			if !rewrite {
+				rewrite, err = r.isBindingSecretOutdated(ctx, serviceBinding, secretName)
+				if err != nil {
+					return ctrl.Result{}, err
+				}
 			}
-			err = r.storeBindingSecret(ctx, serviceInstance, serviceBinding, cfbinding.Credentials, spec.SecretName, spec.SecretKey, withMetadata)
-			if err != nil {
-				// TODO: implement error handling
-				return ctrl.Result{RequeueAfter: 10 * time.Minute}, nil
+			if rewrite {
+				log.V(1).Info("Reading binding credentials", "refresh", refresh)
+				credentials, err := client.GetBindingCredentials(ctx, cfbinding.Guid)
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+				if refresh {
+					status.LastCredentialsRefreshAt = &[]metav1.Time{metav1.Now()}[0]
+				}
+				credentialsDigest := facade.ObjectHash(map[string]interface{}{"uid": string(serviceBinding.UID), "credentials": credentials})
+				if status.CredentialsDigest != "" && status.CredentialsDigest != credentialsDigest && previousServiceBindingGuid == cfbinding.Guid {
+					log.Info("Detected rotation of binding credentials on the broker side; updating binding secret")
+					serviceBindingCredentialsRotations.Inc()
+				}
+				location, err := r.storeBindingCredentials(ctx, serviceInstance, serviceBinding, credentials, secretName, spec.SecretKey, withMetadata)
+				if err != nil {
+					serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionCredentialsReady, cfv1alpha1.ConditionFalse, conditionReasonError, err.Error())
+					// TODO: implement error handling
+					return ctrl.Result{RequeueAfter: 10 * time.Minute}, nil
+				}
+				serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionCredentialsReady, cfv1alpha1.ConditionTrue, conditionReasonSecretStored, "Credentials stored in "+location)
+				status.CredentialsDigest = credentialsDigest
+				status.CredentialKeys = binding.CredentialKeys(credentials)
+			}
+			// superseded immutable binding secrets are retained for a grace period, and deleted by a later reconcile
+			retainedFor := time.Duration(0)
+			if status.SecretName != "" {
+				retainedFor, err = r.deleteObsoleteBindingSecrets(ctx, serviceBinding, types.NamespacedName{Namespace: secretName.Namespace, Name: status.SecretName})
+				if err != nil {
+					return ctrl.Result{}, err
+				}
 			}
+			status.Tags = binding.Tags(serviceInstance)
 			// TODO: apply some increasing period, depending on the age of the last update
-			return getPollingInterval(serviceBinding.GetAnnotations(), "10m", cfv1alpha1.AnnotationPollingIntervalReady), nil
+			result := getPollingInterval(annotations, getDefaultPollingIntervalReady(r.Config, "ServiceBinding"), cfv1alpha1.AnnotationPollingIntervalReady)
+			if refreshInterval > 0 {
+				if nextRefresh := time.Until(status.LastCredentialsRefreshAt.Add(refreshInterval)); result.RequeueAfter == 0 || nextRefresh < result.RequeueAfter {
+					result.RequeueAfter = nextRefresh
+				}
+			}
+			if retainedFor > 0 && (result.RequeueAfter == 0 || retainedFor < result.RequeueAfter) {
+				result.RequeueAfter = retainedFor
+			}
+			return result, nil
 		case facade.BindingStateCreatedFailed, facade.BindingStateDeleteFailed:
 			serviceBinding.SetReadyCondition(cfv1alpha1.ConditionFalse, string(cfbinding.State), cfbinding.StateDescription)
-			// TODO: apply some increasing period, depending on the age of the last update
-			return ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
+			serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionSynced, cfv1alpha1.ConditionFalse, string(cfbinding.State), cfbinding.StateDescription)
+			// the failed binding is re-created with the next attempt
+			return ctrl.Result{}, RetryError
 		default:
 			serviceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, string(cfbinding.State), cfbinding.StateDescription)
+			serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionSynced, cfv1alpha1.ConditionUnknown, string(cfbinding.State), cfbinding.StateDescription)
 			// TODO: apply some increasing period, depending on the age of the last update
 			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 		}
 	} else if len(removeString(serviceBinding.Finalizers, serviceBindingFinalizer)) > 0 {
 		serviceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceBindingReadyConditionReasonDeletionBlocked, "Deletion blocked due to foreign finalizers")
+		serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionDeletionBlocked, cfv1alpha1.ConditionTrue, conditionReasonForeignFinalizers, "Deletion blocked due to foreign finalizers")
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 		// TODO: apply some increasing period, depending on the age of the last update
 	} else {
 		// Deletion case
-		exists, deleting, err := r.existsCredentialsSecret(ctx, types.NamespacedName{Namespace: serviceBinding.Namespace, Name: spec.SecretName})
+		secretName := types.NamespacedName{Namespace: getBindingSecretNamespace(serviceBinding), Name: spec.SecretName}
+		if err := r.deleteStoredCredentials(ctx, serviceBinding, secretName.Name); err != nil {
+			return ctrl.Result{}, err
+		}
+		if status.SecretName != "" {
+			secretName.Name = status.SecretName
+		}
+		exists, deleting, err := r.existsCredentialsSecret(ctx, serviceBinding, secretName)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
 		if exists {
+			deleted := false
 			if !deleting {
-				if err := r.deleteBindingSecret(ctx, serviceBinding.Namespace, spec.SecretName); err != nil {
+				if deleted, err = r.deleteBindingSecret(ctx, secretName.Namespace, secretName.Name); err != nil {
 					return ctrl.Result{}, err
 				}
 			}
-			serviceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceBindingReadyConditionReasonDeletionBlocked, "Waiting for deletion of binding secret")
-			// TODO: apply some increasing period, depending on the age of the last update
+			if !deleted {
+				serviceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceBindingReadyConditionReasonDeletionBlocked, "Waiting for deletion of binding secret")
+				serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionDeletionBlocked, cfv1alpha1.ConditionTrue, conditionReasonDependentsExist, "Waiting for deletion of binding secret")
+				// TODO: apply some increasing period, depending on the age of the last update
+				return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+			}
+		}
+		additionalSecretsDeleted := true
+		for _, additionalSecret := range spec.Secrets {
+			deleted, err := r.deleteAdditionalBindingSecret(ctx, serviceBinding, types.NamespacedName{Namespace: secretName.Namespace, Name: additionalSecret.Name})
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			additionalSecretsDeleted = additionalSecretsDeleted && deleted
+		}
+		if !additionalSecretsDeleted {
+			serviceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceBindingReadyConditionReasonDeletionBlocked, "Waiting for deletion of additional binding secrets")
+			serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionDeletionBlocked, cfv1alpha1.ConditionTrue, conditionReasonDependentsExist, "Waiting for deletion of additional binding secrets")
 			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 		}
+		serviceBinding.RemoveCondition(cfv1alpha1.ServiceBindingConditionDeletionBlocked)
+		if cfbinding == nil && client != nil && (serviceBinding.Annotations["service-operator.cf.cs.sap.com/rotate-on-parameter-change"] == "true" ||
+			serviceBinding.Annotations["service-operator.cf.cs.sap.com/rotate-on-instance-change"] == "true") {
+			// the replacement of an interrupted re-creation (see replaceBinding) is not owned (yet), so it has to be deleted explicitly
+			replacement, err := client.GetBinding(ctx, map[string]string{"replacementOwner": string(serviceBinding.UID)})
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if replacement != nil {
+				if replacement.State != facade.BindingStateDeleting {
+					log.V(1).Info("Deleting replacement binding", "guid", replacement.Guid)
+					if err := client.DeleteBinding(ctx, replacement.Guid, replacement.OwnerRef()); err != nil {
+						return ctrl.Result{}, err
+					}
+				}
+				serviceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, string(facade.BindingStateDeleting), "Waiting for deletion of replacement binding")
+				return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+			}
+		}
 		if cfbinding == nil {
 			if containsString(serviceBinding.Finalizers, serviceBindingFinalizer) {
 				controllerutil.RemoveFinalizer(serviceBinding, serviceBindingFinalizer)
@@ -402,7 +655,7 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		} else {
 			if cfbinding.State != facade.BindingStateDeleting {
 				log.V(1).Info("Deleting binding")
-				if err := client.DeleteBinding(ctx, cfbinding.Guid); err != nil {
+				if err := client.DeleteBinding(ctx, cfbinding.Guid, cfbinding.OwnerRef()); err != nil {
 					return ctrl.Result{}, err
 				}
 				status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
@@ -416,7 +669,241 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	}
 }
 
-func (r *ServiceBindingReconciler) existsCredentialsSecret(ctx context.Context, secretName types.NamespacedName) (bool, bool, error) {
+// replaceBinding re-creates the given (ready) service key without credentials gap: first, a replacement binding is created
+// (labeled as replacement of the owning object, instead of with the owner label); once it is ready, its credentials are stored
+// (so consumers switch to them), and the current binding is deleted; once that is gone, the replacement is promoted (see Reconcile).
+// A replacement named differently from spec.name is renamed after its promotion, by being replaced once more (see Reconcile).
+func (r *ServiceBindingReconciler) replaceBinding(ctx context.Context, client facade.SpaceClient, serviceInstance *cfv1alpha1.ServiceInstance, serviceBinding *cfv1alpha1.ServiceBinding, cfbinding *facade.Binding, parameters map[string]interface{}) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	spec := &serviceBinding.Spec
+	status := &serviceBinding.Status
+	owner := ownerRefOf(serviceBinding)
+	owner.Replacement = true
+
+	replacement, err := client.GetBinding(ctx, map[string]string{"replacementOwner": owner.UID})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if replacement == nil {
+		// binding names must be unique per service instance; the replacement takes spec.name, unless the replaced binding holds it
+		name := spec.Name
+		if cfbinding.Name == spec.Name {
+			name = fmt.Sprintf("%s-%s", spec.Name, facade.ObjectHash(map[string]interface{}{"replaces": cfbinding.Guid})[:8])
+		}
+		log.V(1).Info("Creating replacement binding", "name", name)
+		if _, err := client.CreateBinding(
+			ctx,
+			name,
+			serviceInstance.Status.ServiceInstanceGuid,
+			"",
+			parameters,
+			getCFMetadata(spec.Metadata, false),
+			owner,
+			serviceBinding.Generation,
+		); err != nil {
+			return ctrl.Result{}, err
+		}
+		status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
+		serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionSynced, cfv1alpha1.ConditionUnknown, serviceBindingConditionReasonReplacing, "Creating replacement binding")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	switch replacement.State {
+	case facade.BindingStateReady:
+		if replacement.ParameterHash != facade.ObjectHash(parameters) {
+			// parameters changed again in the meantime
+			break
+		}
+		log.V(1).Info("Reading credentials of replacement binding", "guid", replacement.Guid)
+		credentials, err := client.GetBindingCredentials(ctx, replacement.Guid)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		secretName := types.NamespacedName{Namespace: getBindingSecretNamespace(serviceBinding), Name: spec.SecretName}
+		location, err := r.storeBindingCredentials(ctx, serviceInstance, serviceBinding, credentials, secretName, spec.SecretKey, r.withBindingMetadata(serviceBinding))
+		if err != nil {
+			serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionCredentialsReady, cfv1alpha1.ConditionFalse, conditionReasonError, err.Error())
+			return ctrl.Result{}, err
+		}
+		serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionCredentialsReady, cfv1alpha1.ConditionTrue, conditionReasonSecretStored, "Credentials stored in "+location)
+		log.V(1).Info("Deleting binding replaced by replacement binding", "guid", replacement.Guid)
+		if err := client.DeleteBinding(ctx, cfbinding.Guid, cfbinding.OwnerRef()); err != nil {
+			return ctrl.Result{}, err
+		}
+		status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
+		serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionSynced, cfv1alpha1.ConditionUnknown, serviceBindingConditionReasonReplacing, "Deleting replaced binding")
+		return ctrl.Result{Requeue: true}, nil
+	case facade.BindingStateCreatedFailed, facade.BindingStateDeleteFailed:
+	default:
+		serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionSynced, cfv1alpha1.ConditionUnknown, serviceBindingConditionReasonReplacing, "Waiting for replacement binding")
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	// outdated or failed replacements are discarded, and created anew with the next attempt
+	log.V(1).Info("Deleting replacement binding", "guid", replacement.Guid, "state", replacement.State)
+	if err := client.DeleteBinding(ctx, replacement.Guid, replacement.OwnerRef()); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// withBindingMetadata returns whether SAP binding metadata are added to the binding secret of the given binding;
+// the operator-wide setting may be overridden by annotation, and by spec.sapBindingMetadata.enabled.
+func (r *ServiceBindingReconciler) withBindingMetadata(serviceBinding *cfv1alpha1.ServiceBinding) bool {
+	if metadata := serviceBinding.Spec.SAPBindingMetadata; metadata != nil && metadata.Enabled != nil {
+		return *metadata.Enabled
+	}
+	switch serviceBinding.Annotations["service-operator.cf.cs.sap.com/with-sap-binding-metadata"] {
+	case "true":
+		return true
+	case "false":
+		return false
+	default:
+		return r.EnableBindingMetadata
+	}
+}
+
+// newBinding returns the content of the binding secret of the given binding, with the SAP binding metadata fields selected by
+// spec.sapBindingMetadata.fields (if any).
+func newBinding(serviceInstance *cfv1alpha1.ServiceInstance, serviceBinding *cfv1alpha1.ServiceBinding, credentials map[string]interface{}) *binding.Binding {
+	result := binding.NewBinding(serviceInstance, serviceBinding, credentials)
+	if metadata := serviceBinding.Spec.SAPBindingMetadata; metadata != nil {
+		result = result.WithMetadataFields(metadata.Fields)
+	}
+	return result
+}
+
+// observeBinding reflects the state of the cloud foundry binding in the status of the given service binding, without changing anything
+// (observe-only mode); the binding is looked up by owner, or - if not (yet) owned - by the guid given by the adopt-cf-binding-guid annotation,
+// by the label selector given by the adopt-cf-label-selector annotation, or by name.
+func (r *ServiceBindingReconciler) observeBinding(ctx context.Context, serviceBinding *cfv1alpha1.ServiceBinding, annotations map[string]string, client facade.SpaceClient, spaceGuid string) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	status := &serviceBinding.Status
+
+	log.V(1).Info("Retrieving binding (observe-only)")
+	bindingOpts := map[string]string{"name": "", "owner": string(serviceBinding.UID)}
+	cfbinding, err := client.GetBinding(ctx, bindingOpts)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if cfbinding == nil {
+		if guid := serviceBinding.Annotations[cfv1alpha1.AnnotationAdoptCFBindingGuid]; guid != "" {
+			bindingOpts["guid"] = guid
+		} else if labelSelector := serviceBinding.Annotations[cfv1alpha1.AnnotationAdoptCFLabelSelector]; labelSelector != "" {
+			bindingOpts["labelSelector"] = labelSelector
+		} else {
+			bindingOpts["name"] = serviceBinding.Spec.Name
+		}
+		cfbinding, err = client.GetBinding(ctx, bindingOpts)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionSynced, cfv1alpha1.ConditionUnknown, conditionReasonObserveOnly, "Changes are not applied in observe-only mode")
+	serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionCredentialsReady, cfv1alpha1.ConditionUnknown, conditionReasonObserveOnly, "The binding secret is not written in observe-only mode")
+
+	if cfbinding == nil {
+		serviceBinding.SetReadyCondition(cfv1alpha1.ConditionFalse, readyConditionReasonNotFound, "Cloud Foundry binding not found (observe-only mode; it will not be created)")
+		return getPollingInterval(annotations, getDefaultPollingIntervalReady(r.Config, "ServiceBinding"), cfv1alpha1.AnnotationPollingIntervalReady), nil
+	}
+	status.SpaceGuid = spaceGuid
+	status.ServiceInstanceGuid = cfbinding.ServiceInstanceGuid
+	status.AppGuid = cfbinding.AppGuid
+	status.Type = cfv1alpha1.ServiceBindingType(cfbinding.Type)
+	status.ServiceBindingGuid = cfbinding.Guid
+	status.LastOperation = getLastOperation(status.LastOperation, cfbinding.LastOperation)
+	switch cfbinding.State {
+	case facade.BindingStateReady:
+		serviceBinding.SetReadyCondition(cfv1alpha1.ConditionTrue, string(cfbinding.State), cfbinding.StateDescription)
+	case facade.BindingStateCreatedFailed, facade.BindingStateDeleteFailed:
+		serviceBinding.SetReadyCondition(cfv1alpha1.ConditionFalse, string(cfbinding.State), cfbinding.StateDescription)
+	default:
+		serviceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, string(cfbinding.State), cfbinding.StateDescription)
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	return getPollingInterval(annotations, getDefaultPollingIntervalReady(r.Config, "ServiceBinding"), cfv1alpha1.AnnotationPollingIntervalReady), nil
+}
+
+// getAppGuid returns the guid of the application an app binding shall be bound to (as specified by spec.appGuid, or looked up by spec.appName
+// in the space of the client); for service keys, the empty string is returned.
+func getAppGuid(ctx context.Context, client facade.SpaceClient, spec *cfv1alpha1.ServiceBindingSpec) (string, error) {
+	if spec.Type != cfv1alpha1.ServiceBindingTypeApp {
+		return "", nil
+	}
+	if spec.AppName != "" {
+		ctrl.LoggerFrom(ctx).V(1).Info("Searching application", "name", spec.AppName)
+		return client.FindApp(ctx, spec.AppName)
+	}
+	if spec.AppGuid == "" {
+		return "", fmt.Errorf("no application specified for binding of type %s", spec.Type)
+	}
+	return spec.AppGuid, nil
+}
+
+// getServiceInstance returns the ServiceInstance object referenced by the given binding. If the binding refers to a Cloud Foundry instance
+// which is not managed by the operator (spec.serviceInstanceGuid), a stand-in object is returned, carrying the guid of the instance,
+// and referring to the space given by the binding (the space guid is filled in once the space is retrieved).
+func (r *ServiceBindingReconciler) getServiceInstance(ctx context.Context, serviceBinding *cfv1alpha1.ServiceBinding) (*cfv1alpha1.ServiceInstance, error) {
+	spec := &serviceBinding.Spec
+	if spec.ServiceInstanceGuid != "" {
+		return &cfv1alpha1.ServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: serviceBinding.Namespace},
+			Spec:       cfv1alpha1.ServiceInstanceSpec{SpaceName: spec.SpaceName, ClusterSpaceName: spec.ClusterSpaceName},
+			Status:     cfv1alpha1.ServiceInstanceStatus{ServiceInstanceGuid: spec.ServiceInstanceGuid},
+		}, nil
+	}
+
+	serviceInstanceName := types.NamespacedName{
+		Namespace: serviceBinding.Namespace,
+		Name:      spec.ServiceInstanceName,
+	}
+	serviceInstance := &cfv1alpha1.ServiceInstance{}
+	if err := r.Get(ctx, serviceInstanceName, serviceInstance); err != nil {
+		return nil, errors.Wrapf(err, "failed to get ServiceInstance, name: %s", spec.ServiceInstanceName)
+	}
+	// Call the defaulting webhook logic also here (because defaulting through the webhook might be incomplete in case of generateName usage)
+	serviceInstance.Default()
+	return serviceInstance, nil
+}
+
+// isBindingSecretOutdated tells whether the credentials of the given (ready) binding must be written again, although neither the
+// Cloud Foundry binding nor the service instance changed: this is the case if they were never stored successfully, if the spec changed
+// since (as recorded in the CredentialsReady condition), or if the binding secret or one of the additional binding secrets is missing.
+// Secrets stored in a secret store (other than through a PushSecret) are not checked.
+func (r *ServiceBindingReconciler) isBindingSecretOutdated(ctx context.Context, serviceBinding *cfv1alpha1.ServiceBinding, secretName types.NamespacedName) (bool, error) {
+	condition := serviceBinding.GetCondition(cfv1alpha1.ServiceBindingConditionCredentialsReady)
+	if serviceBinding.Status.CredentialsDigest == "" || condition == nil || condition.Status != cfv1alpha1.ConditionTrue || condition.ObservedGeneration != serviceBinding.Generation {
+		return true, nil
+	}
+	ref := serviceBinding.Spec.SecretStoreRef
+	if ref != nil && ref.Type != cfv1alpha1.SecretStoreTypePushSecret {
+		return false, nil
+	}
+	// the binding secret may carry a content hash suffix (immutable secrets); status.secretName holds the name last written
+	secretNames := []types.NamespacedName{{Namespace: secretName.Namespace, Name: serviceBinding.Status.SecretName}}
+	if ref == nil {
+		for _, additionalSecret := range serviceBinding.Spec.Secrets {
+			secretNames = append(secretNames, types.NamespacedName{Namespace: secretName.Namespace, Name: additionalSecret.Name})
+		}
+	}
+	for _, name := range secretNames {
+		if name.Name == "" {
+			return true, nil
+		}
+		exists, deleting, err := r.existsCredentialsSecret(ctx, serviceBinding, name)
+		if err != nil {
+			return false, err
+		}
+		if !exists || deleting {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// existsCredentialsSecret checks whether the given binding secret exists, and whether it is being deleted;
+// secrets in other namespaces than the binding's are only considered if they were written by the binding.
+func (r *ServiceBindingReconciler) existsCredentialsSecret(ctx context.Context, serviceBinding *cfv1alpha1.ServiceBinding, secretName types.NamespacedName) (bool, bool, error) {
 	secret := &corev1.Secret{}
 	if err := r.Get(ctx, secretName, secret); err != nil {
 		if err := client.IgnoreNotFound(err); err == nil {
@@ -424,71 +911,446 @@ func (r *ServiceBindingReconciler) existsCredentialsSecret(ctx context.Context,
 		}
 		return false, false, errors.Wrap(err, "failed to read binding secret")
 	}
+	if secret.Namespace != serviceBinding.Namespace && !isBindingSecretOf(secret, serviceBinding) {
+		return false, false, nil
+	}
 	return true, !secret.DeletionTimestamp.IsZero(), nil
 }
 
-func (r *ServiceBindingReconciler) storeBindingSecret(ctx context.Context, serviceInstance *cfv1alpha1.ServiceInstance, serviceBinding *cfv1alpha1.ServiceBinding, credentials map[string]interface{}, secretName string, secretKey string, withMetadata bool) error {
-	data, err := binding.NewBinding(serviceInstance, serviceBinding, credentials).SecretData(secretKey, withMetadata)
+// deleteAdditionalBindingSecret deletes the given additional binding secret (spec.secrets), and returns whether the secret is gone;
+// secrets which were not written by the binding (that is, neither controlled by the binding, nor labeled as written by it) are left untouched.
+func (r *ServiceBindingReconciler) deleteAdditionalBindingSecret(ctx context.Context, serviceBinding *cfv1alpha1.ServiceBinding, secretName types.NamespacedName) (bool, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, secretName, secret); err != nil {
+		if err := client.IgnoreNotFound(err); err != nil {
+			return false, errors.Wrap(err, "failed to read binding secret")
+		}
+		return true, nil
+	}
+	if !metav1.IsControlledBy(secret, serviceBinding) && !isBindingSecretOf(secret, serviceBinding) {
+		return true, nil
+	}
+	if !secret.DeletionTimestamp.IsZero() {
+		return false, nil
+	}
+	return r.deleteBindingSecret(ctx, secretName.Namespace, secretName.Name)
+}
+
+// storeBindingSecret creates or updates the binding secret, and deletes secrets previously written by the binding under another name or namespace
+// (except for superseded immutable secrets, which are retained for a grace period);
+// returns the name of the written secret, which is suffixed with a hash of the content if the binding requests an immutable secret.
+// Secrets in the namespace of the binding are owned (controlled) by the binding; secrets in other namespaces (which must be allowed by the configuration)
+// are identified by labels, and existing secrets there are only overwritten if they were written by the binding.
+func (r *ServiceBindingReconciler) storeBindingSecret(ctx context.Context, serviceInstance *cfv1alpha1.ServiceInstance, serviceBinding *cfv1alpha1.ServiceBinding, credentials map[string]interface{}, secretName types.NamespacedName, secretKey string, withMetadata bool) (string, error) {
+	crossNamespace := secretName.Namespace != serviceBinding.Namespace
+	if crossNamespace && (r.Config == nil || !slices.Contains(r.Config.SecretNamespaces, secretName.Namespace)) {
+		return "", fmt.Errorf("binding secret must not be stored in namespace %s: namespace not allowed by the operator configuration (secretNamespaces)", secretName.Namespace)
+	}
+
+	data, err := newBinding(serviceInstance, serviceBinding, credentials).SecretData(secretKey, withMetadata)
 	if err != nil {
-		return errors.Wrap(err, "failed to build binding secret")
+		return "", errors.Wrap(err, "failed to build binding secret")
+	}
+	secretType := corev1.SecretTypeOpaque
+	if serviceBinding.Spec.SecretType != "" {
+		secretType = corev1.SecretType(serviceBinding.Spec.SecretType)
+	}
+	immutable := serviceBinding.Spec.SecretImmutable
+	if immutable {
+		// immutable secrets cannot be updated, so every content gets its own secret
+		secretName.Name = fmt.Sprintf("%s-%s", secretName.Name, facade.ObjectHash(map[string]interface{}{"type": secretType, "data": data})[:10])
+	}
+	if err := r.writeBindingSecret(ctx, serviceInstance, serviceBinding, secretName, secretType, immutable, data); err != nil {
+		return "", err
+	}
+
+	if _, err := r.deleteObsoleteBindingSecrets(ctx, serviceBinding, secretName); err != nil {
+		return "", err
+	}
+	return secretName.Name, nil
+}
+
+// storeAdditionalBindingSecrets creates or updates the additional binding secrets (spec.secrets) in the namespace of the binding secret,
+// holding the selected keys of the given credentials; secrets which are no longer specified are deleted along with the next write of the binding secret.
+func (r *ServiceBindingReconciler) storeAdditionalBindingSecrets(ctx context.Context, serviceInstance *cfv1alpha1.ServiceInstance, serviceBinding *cfv1alpha1.ServiceBinding, credentials map[string]interface{}, namespace string) error {
+	for _, additionalSecret := range serviceBinding.Spec.Secrets {
+		data, err := binding.AdditionalSecretData(credentials, additionalSecret.Keys, additionalSecret.Format)
+		if err != nil {
+			return errors.Wrapf(err, "failed to build binding secret %s", additionalSecret.Name)
+		}
+		secretType := corev1.SecretTypeOpaque
+		if additionalSecret.Type != "" {
+			secretType = corev1.SecretType(additionalSecret.Type)
+		}
+		secretName := types.NamespacedName{Namespace: namespace, Name: additionalSecret.Name}
+		if err := r.writeBindingSecret(ctx, serviceInstance, serviceBinding, secretName, secretType, false, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeBindingSecret creates or updates the given secret of the binding (see storeBindingSecret).
+func (r *ServiceBindingReconciler) writeBindingSecret(ctx context.Context, serviceInstance *cfv1alpha1.ServiceInstance, serviceBinding *cfv1alpha1.ServiceBinding, secretName types.NamespacedName, secretType corev1.SecretType, immutable bool, data map[string][]byte) error {
+	crossNamespace := secretName.Namespace != serviceBinding.Namespace
+	labels := r.getBindingSecretLabels(serviceInstance, serviceBinding)
+	if crossNamespace {
+		labels[cfv1alpha1.LabelKeyServiceBindingNamespace] = serviceBinding.Namespace
 	}
 
 	secret := &corev1.Secret{}
-	if err := r.Get(ctx, types.NamespacedName{Namespace: serviceBinding.Namespace, Name: secretName}, secret); err != nil {
+	ownerReferences := []metav1.OwnerReference(nil)
+	if err := r.Get(ctx, secretName, secret); err != nil {
 		if err := client.IgnoreNotFound(err); err != nil {
 			return errors.Wrap(err, "failed to read binding secret")
 		}
-		secret.Namespace = serviceBinding.Namespace
-		secret.Name = secretName
-		if err := controllerutil.SetControllerReference(serviceBinding, secret, r.Scheme); err != nil {
-			return errors.Wrap(err, "failed to create binding secret")
+		secret = nil
+	} else {
+		ownerReferences = slices.Clone(secret.OwnerReferences)
+		if crossNamespace {
+			if !isBindingSecretOf(secret, serviceBinding) {
+				return fmt.Errorf("failed to update binding secret: secret %s exists and was not written by this binding", secretName)
+			}
+		} else if err := controllerutil.SetControllerReference(serviceBinding, secret, r.Scheme); err != nil {
+			return errors.Wrap(err, "failed to update binding secret")
+		}
+		// type and immutability of a secret cannot be changed, nor the data of an immutable secret; such secrets are recreated
+		existingType := secret.Type
+		if existingType == "" {
+			existingType = corev1.SecretTypeOpaque
+		}
+		existingImmutable := secret.Immutable != nil && *secret.Immutable
+		if existingType != secretType || existingImmutable != immutable || existingImmutable && !maps.EqualFunc(secret.Data, data, bytes.Equal) {
+			if err := r.Delete(ctx, secret, client.Preconditions{UID: &secret.UID}); client.IgnoreNotFound(err) != nil {
+				return errors.Wrap(err, "failed to delete binding secret for recreation")
+			}
+			secret = nil
+		}
+	}
+
+	if secret == nil {
+		secret = &corev1.Secret{}
+		secret.Namespace = secretName.Namespace
+		secret.Name = secretName.Name
+		if !crossNamespace {
+			if err := controllerutil.SetControllerReference(serviceBinding, secret, r.Scheme); err != nil {
+				return errors.Wrap(err, "failed to create binding secret")
+			}
+		}
+		secret.Labels = labels
+		secret.Type = secretType
+		if immutable {
+			secret.Immutable = &immutable
 		}
-		secret.Labels = map[string]string{cfv1alpha1.LabelKeyServiceBinding: serviceBinding.Name}
 		secret.Data = data
 		if err := r.Create(ctx, secret); err != nil {
 			return errors.Wrap(err, "failed to create binding secret")
 		}
-	} else {
-		if err := controllerutil.SetControllerReference(serviceBinding, secret, r.Scheme); err != nil {
-			return errors.Wrap(err, "failed to update binding secret")
-		}
-		secret.Labels = map[string]string{cfv1alpha1.LabelKeyServiceBinding: serviceBinding.Name}
+		serviceBindingSecretWrites.WithLabelValues("create").Inc()
+	} else if !maps.Equal(secret.Labels, labels) || !maps.EqualFunc(secret.Data, data, bytes.Equal) || !reflect.DeepEqual(secret.OwnerReferences, ownerReferences) {
+		// idempotent updates are skipped, since every update would wake up all watchers of the secret
+		secret.Labels = labels
 		secret.Data = data
-		// TODO: should we suppress idempotent secret updates ?
 		if err := r.Update(ctx, secret); err != nil {
 			return errors.Wrap(err, "failed to update binding secret")
 		}
+		serviceBindingSecretWrites.WithLabelValues("update").Inc()
 	}
+	return nil
+}
 
-	secretList := &corev1.SecretList{}
-	if err := client.NewNamespacedClient(r.Client, serviceBinding.Namespace).List(ctx, secretList, client.MatchingLabels{cfv1alpha1.LabelKeyServiceBinding: serviceBinding.Name}); err != nil {
-		return errors.Wrap(err, "failed to retrieve dependent secrets")
+// deleteObsoleteBindingSecrets deletes all secrets written by the binding before, except the given one and the additional binding secrets (spec.secrets)
+// in its namespace; that is, secrets in the binding's namespace carrying the binding label, and secrets in other namespaces carrying the binding label
+// and the binding namespace label. Immutable secrets superseded by the given secret are retained for a grace period (see retainSupersededBindingSecret);
+// returns the time until the next retained secret is due for deletion (zero if no secret is retained).
+func (r *ServiceBindingReconciler) deleteObsoleteBindingSecrets(ctx context.Context, serviceBinding *cfv1alpha1.ServiceBinding, secretName types.NamespacedName) (time.Duration, error) {
+	localSecrets := &corev1.SecretList{}
+	if err := r.List(ctx, localSecrets, client.InNamespace(serviceBinding.Namespace), client.MatchingLabels{cfv1alpha1.LabelKeyServiceBinding: serviceBinding.Name}); err != nil {
+		return 0, errors.Wrap(err, "failed to retrieve dependent secrets")
 	}
-	for _, secret := range secretList.Items {
-		if secret.Name != secretName {
-			if err := r.deleteBindingSecret(ctx, secret.Namespace, secret.Name); err != nil {
-				return errors.Wrap(err, "failed to delete obsolete secret")
+	foreignSecrets := &corev1.SecretList{}
+	if err := r.List(ctx, foreignSecrets, client.MatchingLabels{cfv1alpha1.LabelKeyServiceBinding: serviceBinding.Name, cfv1alpha1.LabelKeyServiceBindingNamespace: serviceBinding.Namespace}); err != nil {
+		return 0, errors.Wrap(err, "failed to retrieve dependent secrets")
+	}
+	retainedFor := time.Duration(0)
+	for _, secret := range append(localSecrets.Items, foreignSecrets.Items...) {
+		if secret.Namespace == secretName.Namespace && (secret.Name == secretName.Name || isAdditionalBindingSecret(serviceBinding, secret.Name)) || !isBindingSecretOf(&secret, serviceBinding) {
+			continue
+		}
+		if secretName.Name != "" && secret.Namespace == secretName.Namespace {
+			retain, err := r.retainSupersededBindingSecret(ctx, &secret)
+			if err != nil {
+				return 0, err
 			}
+			if retain > 0 {
+				if retainedFor == 0 || retain < retainedFor {
+					retainedFor = retain
+				}
+				continue
+			}
+		}
+		if _, err := r.deleteBindingSecret(ctx, secret.Namespace, secret.Name); err != nil {
+			return 0, errors.Wrap(err, "failed to delete obsolete secret")
 		}
 	}
 
-	return nil
+	return retainedFor, nil
+}
+
+// retainSupersededBindingSecret returns for how long the given binding secret (superseded by a binding secret with another name) is retained,
+// such that consumers get a grace period to follow status.secretName (zero if the secret can be deleted). Only immutable secrets are retained;
+// the grace period (configuration key supersededSecretGracePeriod) starts when the secret is first seen superseded, which is recorded in an annotation.
+func (r *ServiceBindingReconciler) retainSupersededBindingSecret(ctx context.Context, secret *corev1.Secret) (time.Duration, error) {
+	gracePeriod := time.Duration(0)
+	if r.Config != nil {
+		gracePeriod = r.Config.SupersededSecretGracePeriod.Duration
+	}
+	if gracePeriod <= 0 || secret.Immutable == nil || !*secret.Immutable {
+		return 0, nil
+	}
+	supersededAt, err := time.Parse(time.RFC3339, secret.Annotations[cfv1alpha1.AnnotationSupersededAt])
+	if err != nil {
+		// the metadata of immutable secrets may still be updated
+		if secret.Annotations == nil {
+			secret.Annotations = make(map[string]string)
+		}
+		secret.Annotations[cfv1alpha1.AnnotationSupersededAt] = time.Now().UTC().Format(time.RFC3339)
+		if err := r.Update(ctx, secret); err != nil {
+			return 0, errors.Wrap(err, "failed to mark superseded binding secret")
+		}
+		return gracePeriod, nil
+	}
+	return time.Until(supersededAt.Add(gracePeriod)), nil
+}
+
+// isAdditionalBindingSecret checks whether the given binding specifies an additional binding secret (spec.secrets) with the given name.
+func isAdditionalBindingSecret(serviceBinding *cfv1alpha1.ServiceBinding, name string) bool {
+	return slices.ContainsFunc(serviceBinding.Spec.Secrets, func(secret cfv1alpha1.BindingSecret) bool { return secret.Name == name })
+}
+
+// storeBindingCredentials writes the binding credentials to the binding secret, or to the secret store referenced by the binding, and returns
+// a description of where they were stored; status.secretName is updated accordingly. With a PushSecret, the binding secret is maintained as well
+// (it is the source of the PushSecret); with Vault, no binding secret exists, and binding secrets written before are deleted.
+// Only the credential keys selected by spec.secretKeys (if any) are stored in the binding secret; additional binding secrets (spec.secrets)
+// select their keys from all credentials.
+func (r *ServiceBindingReconciler) storeBindingCredentials(ctx context.Context, serviceInstance *cfv1alpha1.ServiceInstance, serviceBinding *cfv1alpha1.ServiceBinding, credentials map[string]interface{}, secretName types.NamespacedName, secretKey string, withMetadata bool) (string, error) {
+	allCredentials := credentials
+	credentials, err := binding.SelectCredentials(credentials, serviceBinding.Spec.SecretKeys)
+	if err != nil {
+		return "", err
+	}
+	ref := serviceBinding.Spec.SecretStoreRef
+	if ref == nil || ref.Type == cfv1alpha1.SecretStoreTypePushSecret {
+		name, err := r.storeBindingSecret(ctx, serviceInstance, serviceBinding, credentials, secretName, secretKey, withMetadata)
+		if err != nil {
+			return "", err
+		}
+		secretName.Name = name
+		serviceBinding.Status.SecretName = name
+	}
+	if ref == nil {
+		if err := r.storeAdditionalBindingSecrets(ctx, serviceInstance, serviceBinding, allCredentials, secretName.Namespace); err != nil {
+			return "", err
+		}
+		names := []string{secretName.Name}
+		for _, additionalSecret := range serviceBinding.Spec.Secrets {
+			names = append(names, additionalSecret.Name)
+		}
+		location := strings.Join(names, ", ")
+		if secretName.Namespace != serviceBinding.Namespace {
+			location = fmt.Sprintf("%s/%s", secretName.Namespace, location)
+		}
+		if len(names) > 1 {
+			return fmt.Sprintf("secrets %s", location), nil
+		}
+		return fmt.Sprintf("secret %s", location), nil
+	}
+
+	data, err := newBinding(serviceInstance, serviceBinding, credentials).SecretData(secretKey, withMetadata)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to build binding secret")
+	}
+	sink, err := binding.NewSink(ctx, r.Client, r.Scheme, serviceBinding, secretName.Name)
+	if err != nil {
+		return "", err
+	}
+	if err := sink.Store(ctx, data); err != nil {
+		return "", err
+	}
+	if ref.Type == cfv1alpha1.SecretStoreTypeVault {
+		if _, err := r.deleteObsoleteBindingSecrets(ctx, serviceBinding, types.NamespacedName{}); err != nil {
+			return "", err
+		}
+		serviceBinding.Status.SecretName = ""
+	}
+	return sink.Location(), nil
+}
+
+// deleteStoredCredentials removes the credentials from the secret store referenced by the binding (if any);
+// if the settings of the secret store are gone, there is nothing that could be done, and the credentials are left behind.
+func (r *ServiceBindingReconciler) deleteStoredCredentials(ctx context.Context, serviceBinding *cfv1alpha1.ServiceBinding, secretName string) error {
+	if serviceBinding.Spec.SecretStoreRef == nil {
+		return nil
+	}
+	sink, err := binding.NewSink(ctx, r.Client, r.Scheme, serviceBinding, secretName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			ctrl.LoggerFrom(ctx).Info("Secret store settings not found; skipping deletion of stored credentials", "secretStore", serviceBinding.Spec.SecretStoreRef.Name)
+			return nil
+		}
+		return err
+	}
+	return sink.Delete(ctx)
 }
 
-func (r *ServiceBindingReconciler) deleteBindingSecret(ctx context.Context, secretNamespace string, secretName string) error {
-	secret := &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: secretNamespace,
-			Name:      secretName,
-		},
+// getBindingSecretNamespace returns the namespace of the binding secret of the given binding.
+func getBindingSecretNamespace(serviceBinding *cfv1alpha1.ServiceBinding) string {
+	if serviceBinding.Spec.SecretNamespace != "" {
+		return serviceBinding.Spec.SecretNamespace
 	}
-	return client.IgnoreNotFound(r.Delete(ctx, secret, &client.DeleteOptions{PropagationPolicy: &[]metav1.DeletionPropagation{metav1.DeletePropagationForeground}[0]}))
+	return serviceBinding.Namespace
+}
+
+// isBindingSecretOf checks whether the given secret was written by the given binding, according to its labels;
+// secrets without binding namespace label belong to a binding in their own namespace.
+func isBindingSecretOf(secret *corev1.Secret, serviceBinding *cfv1alpha1.ServiceBinding) bool {
+	if secret.Labels[cfv1alpha1.LabelKeyServiceBinding] != serviceBinding.Name {
+		return false
+	}
+	if namespace, ok := secret.Labels[cfv1alpha1.LabelKeyServiceBindingNamespace]; ok {
+		return namespace == serviceBinding.Namespace
+	}
+	return secret.Namespace == serviceBinding.Namespace
+}
+
+// getBindingSecretLabels returns the labels of the binding secret; that is, the labels of the service instance and the service binding
+// allowed by the configuration (binding labels taking precedence over instance labels with the same key), and the label identifying
+// the service binding (which cannot be overridden).
+func (r *ServiceBindingReconciler) getBindingSecretLabels(serviceInstance *cfv1alpha1.ServiceInstance, serviceBinding *cfv1alpha1.ServiceBinding) map[string]string {
+	labels := make(map[string]string)
+	if r.Config != nil {
+		for _, source := range []map[string]string{serviceInstance.Labels, serviceBinding.Labels} {
+			for key, value := range source {
+				if matchesLabelAllowlist(key, r.Config.SecretLabels) {
+					labels[key] = value
+				}
+			}
+		}
+	}
+	labels[cfv1alpha1.LabelKeyServiceBinding] = serviceBinding.Name
+	return labels
+}
+
+// deleteBindingSecret deletes the given secret, using the configured propagation policy, and returns whether the secret is gone.
+// Foreground propagation is only used if the secret has dependents; otherwise waiting for the garbage collector
+// would just delay the deletion (for example of the namespace).
+func (r *ServiceBindingReconciler) deleteBindingSecret(ctx context.Context, secretNamespace string, secretName string) (bool, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: secretNamespace, Name: secretName}, secret); err != nil {
+		if err := client.IgnoreNotFound(err); err != nil {
+			return false, errors.Wrap(err, "failed to read binding secret")
+		}
+		return true, nil
+	}
+
+	propagationPolicy := metav1.DeletePropagationForeground
+	if r.Config != nil && r.Config.SecretDeletionPropagation != "" {
+		propagationPolicy = r.Config.SecretDeletionPropagation
+	}
+	if propagationPolicy == metav1.DeletePropagationForeground {
+		hasDependents, err := r.hasDependents(ctx, secret)
+		if err != nil {
+			return false, err
+		}
+		if !hasDependents {
+			propagationPolicy = metav1.DeletePropagationBackground
+		}
+	}
+
+	if err := r.Delete(ctx, secret, client.PropagationPolicy(propagationPolicy)); err != nil {
+		if err := client.IgnoreNotFound(err); err != nil {
+			return false, errors.Wrap(err, "failed to delete binding secret")
+		}
+		return true, nil
+	}
+	// unless deleted in foreground, or blocked by finalizers, the secret is removed immediately
+	return propagationPolicy != metav1.DeletePropagationForeground && len(secret.Finalizers) == 0, nil
+}
+
+// hasDependents checks whether the given secret owns other secrets (in its namespace).
+// Dependents of other kinds are not considered; they are still deleted by the garbage collector, but in background.
+func (r *ServiceBindingReconciler) hasDependents(ctx context.Context, secret *corev1.Secret) (bool, error) {
+	secretList := &corev1.SecretList{}
+	if err := r.List(ctx, secretList, client.InNamespace(secret.Namespace)); err != nil {
+		return false, errors.Wrap(err, "failed to retrieve dependents of binding secret")
+	}
+	for _, item := range secretList.Items {
+		for _, ownerRef := range item.OwnerReferences {
+			if ownerRef.UID == secret.UID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ServiceBindingReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	reconciler, options := limitConcurrency(traceReconciles(measureReconciles(labelReconciles(r, "ServiceBinding"), "ServiceBinding"), "ServiceBinding"), r.Config, endpointFromStatus(
+		mgr.GetCache(),
+		func() client.Object { return &cfv1alpha1.ServiceBinding{} },
+		func(obj client.Object) string { return obj.(*cfv1alpha1.ServiceBinding).Status.Endpoint },
+	))
+	reconciler, namespacePredicate := filterNamespaces(reconciler, mgr.GetClient(), r.Config)
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&cfv1alpha1.ServiceBinding{}).
-		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})).
-		Complete(r)
+		// label changes are watched, since labels may be copied to the binding secret
+		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}, predicate.LabelChangedPredicate{})).
+		WithEventFilter(namespacePredicate)
+	if r.EventBus != nil {
+		if err := mgr.GetFieldIndexer().IndexField(context.Background(), &cfv1alpha1.ServiceBinding{}, indexServiceBindingInstanceGuid, indexServiceBindingByInstanceGuid); err != nil {
+			return err
+		}
+		builder = builder.WatchesRawSource(&resourceEventSource{
+			bus:     r.EventBus,
+			filter:  isResourceEvent(events.ResourceTypeInstance, events.EventTypeUpdated, events.EventTypeDeleted),
+			mapFunc: serviceBindingsOfInstance(mgr.GetCache()),
+		}, &handler.EnqueueRequestForObject{})
+	}
+	return builder.WithOptions(options).Complete(reconciler)
+}
+
+// HandleError sets conditions and the result to handle the error.
+// Special handling for failed Cloud Foundry bindings (signaled by RetryError):
+// - retry after a certain time interval
+// - doubling the time interval for consecutive failures
+// - time interval is capped at a certain maximum value
+// - give up after the maximum number of retries (status.maxRetries)
+func (r *ServiceBindingReconciler) HandleError(serviceBinding *cfv1alpha1.ServiceBinding, annotations map[string]string, issue error, log logr.Logger) (ctrl.Result, error) {
+	if issue != RetryError {
+		readyReason, message := describeError(issue, serviceBindingReadyConditionReasonError)
+		syncedReason, _ := describeError(issue, conditionReasonError)
+		serviceBinding.SetReadyCondition(cfv1alpha1.ConditionFalse, readyReason, message)
+		serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionSynced, cfv1alpha1.ConditionFalse, syncedReason, message)
+		serviceBinding.Status.LastCFError = getCFError(issue)
+		return ctrl.Result{}, issue
+	}
+
+	// Check if the retry counter exceeds the maximum allowed retries.
+	serviceBinding.Status.RetryCounter++
+	if serviceBinding.Status.MaxRetries != serviceBindingDefaultMaxRetries && serviceBinding.Status.RetryCounter >= serviceBinding.Status.MaxRetries {
+		serviceBinding.SetReadyCondition(cfv1alpha1.ConditionFalse, serviceBindingReadyConditionReasonMaxRetriesExceeded, "The service binding has failed due to too many retries.")
+		return getPollingInterval(annotations, getDefaultPollingIntervalFail(r.Config, "ServiceBinding"), cfv1alpha1.AnnotationPollingIntervalFail), nil // finish reconcile loop
+	}
+	// double the requeue interval for every consecutive retry, and cap it if necessary
+	requeueAfter := serviceBindingDefaultRetryInterval
+	for i := 1; i < serviceBinding.Status.RetryCounter && requeueAfter < serviceBindingDefaultMaxRetryInterval; i++ {
+		requeueAfter *= 2
+	}
+	if requeueAfter > serviceBindingDefaultMaxRetryInterval {
+		requeueAfter = serviceBindingDefaultMaxRetryInterval
+	}
+
+	log.V(1).Info("Scheduling next reconcile", "RequeueAfter", requeueAfter.String())
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
@@ -0,0 +1,960 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+	"github.com/sap/cf-service-operator/internal/facade/facadefakes"
+)
+
+var _ = Describe("Delete binding secrets | deleteBindingSecret", func() {
+	ctx := context.Background()
+	secretKey := types.NamespacedName{Namespace: "ns", Name: "binding-secret"}
+
+	newReconciler := func(propagation metav1.DeletionPropagation, objects ...*corev1.Secret) *ServiceBindingReconciler {
+		builder := fake.NewClientBuilder()
+		for _, obj := range objects {
+			builder = builder.WithObjects(obj)
+		}
+		cfg := config.Defaults()
+		cfg.SecretDeletionPropagation = propagation
+		return &ServiceBindingReconciler{Client: builder.Build(), Config: cfg}
+	}
+
+	bindingSecret := func() *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: secretKey.Namespace, Name: secretKey.Name, UID: "binding-secret-uid"}}
+	}
+
+	It("should delete secrets without dependents immediately", func() {
+		reconciler := newReconciler(metav1.DeletePropagationForeground, bindingSecret())
+
+		deleted, err := reconciler.deleteBindingSecret(ctx, secretKey.Namespace, secretKey.Name)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(BeTrue())
+		err = reconciler.Get(ctx, secretKey, &corev1.Secret{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should wait for foreground deletion of secrets with dependents", func() {
+		dependent := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Namespace:       secretKey.Namespace,
+			Name:            "dependent",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "v1", Kind: "Secret", Name: secretKey.Name, UID: "binding-secret-uid"}},
+		}}
+		reconciler := newReconciler(metav1.DeletePropagationForeground, bindingSecret(), dependent)
+
+		deleted, err := reconciler.deleteBindingSecret(ctx, secretKey.Namespace, secretKey.Name)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(BeFalse())
+	})
+
+	It("should not wait with background propagation", func() {
+		dependent := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Namespace:       secretKey.Namespace,
+			Name:            "dependent",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "v1", Kind: "Secret", Name: secretKey.Name, UID: "binding-secret-uid"}},
+		}}
+		reconciler := newReconciler(metav1.DeletePropagationBackground, bindingSecret(), dependent)
+
+		deleted, err := reconciler.deleteBindingSecret(ctx, secretKey.Namespace, secretKey.Name)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(BeTrue())
+	})
+
+	It("should treat missing secrets as deleted", func() {
+		reconciler := newReconciler(metav1.DeletePropagationForeground)
+
+		deleted, err := reconciler.deleteBindingSecret(ctx, secretKey.Namespace, secretKey.Name)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(BeTrue())
+	})
+
+	It("should only delete additional binding secrets written by the binding", func() {
+		serviceBinding := &cfv1alpha1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "binding", UID: "binding-uid"}}
+		controller := true
+		owned := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "ns",
+			Name:            "owned",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "cf.cs.sap.com/v1alpha1", Kind: "ServiceBinding", Name: "binding", UID: "binding-uid", Controller: &controller}},
+		}}
+		labeled := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "labeled", Labels: map[string]string{cfv1alpha1.LabelKeyServiceBinding: "binding"}}}
+		foreign := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "foreign"}}
+		reconciler := newReconciler(metav1.DeletePropagationForeground, owned, labeled, foreign)
+
+		for _, name := range []string{"owned", "labeled", "foreign", "missing"} {
+			deleted, err := reconciler.deleteAdditionalBindingSecret(ctx, serviceBinding, types.NamespacedName{Namespace: "ns", Name: name})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deleted).To(BeTrue())
+		}
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "owned"}, &corev1.Secret{}))).To(BeTrue())
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "labeled"}, &corev1.Secret{}))).To(BeTrue())
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "foreign"}, &corev1.Secret{})).To(Succeed())
+	})
+})
+
+var _ = Describe("Copy labels to binding secrets | getBindingSecretLabels", func() {
+	It("should copy allowed labels, binding labels taking precedence", func() {
+		cfg := config.Defaults()
+		cfg.SecretLabels = []string{"team", "app.kubernetes.io/*"}
+		reconciler := &ServiceBindingReconciler{Config: cfg}
+		serviceInstance := &cfv1alpha1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+			"team":                      "a",
+			"app.kubernetes.io/part-of": "shop",
+			"other":                     "x",
+		}}}
+		serviceBinding := &cfv1alpha1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: "binding", Labels: map[string]string{
+			"team":                   "b",
+			"app.kubernetes.io/name": "db",
+			"teams":                  "y",
+		}}}
+
+		Expect(reconciler.getBindingSecretLabels(serviceInstance, serviceBinding)).To(Equal(map[string]string{
+			"team":                            "b",
+			"app.kubernetes.io/part-of":       "shop",
+			"app.kubernetes.io/name":          "db",
+			cfv1alpha1.LabelKeyServiceBinding: "binding",
+		}))
+	})
+
+	It("should only set the service binding label by default", func() {
+		reconciler := &ServiceBindingReconciler{Config: config.Defaults()}
+		serviceBinding := &cfv1alpha1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: "binding", Labels: map[string]string{"team": "b"}}}
+
+		Expect(reconciler.getBindingSecretLabels(&cfv1alpha1.ServiceInstance{}, serviceBinding)).To(Equal(map[string]string{
+			cfv1alpha1.LabelKeyServiceBinding: "binding",
+		}))
+	})
+})
+
+var _ = Describe("Store binding secrets in other namespaces | storeBindingSecret", func() {
+	ctx := context.Background()
+	credentials := map[string]interface{}{"user": "admin"}
+
+	var reconciler *ServiceBindingReconciler
+	var serviceInstance *cfv1alpha1.ServiceInstance
+	var serviceBinding *cfv1alpha1.ServiceBinding
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		cfg := config.Defaults()
+		cfg.SecretNamespaces = []string{"workload"}
+		reconciler = &ServiceBindingReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Scheme: scheme, Config: cfg}
+		serviceInstance = &cfv1alpha1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Namespace: "provisioning", Name: "instance"}}
+		serviceBinding = &cfv1alpha1.ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "provisioning", Name: "binding", UID: "binding-uid"},
+			Spec:       cfv1alpha1.ServiceBindingSpec{ServiceInstanceName: "instance", SecretName: "binding", SecretNamespace: "workload"},
+		}
+	})
+
+	It("should only write to allowed namespaces", func() {
+		_, err := reconciler.storeBindingSecret(ctx, serviceInstance, serviceBinding, credentials, types.NamespacedName{Namespace: "other", Name: "binding"}, "", false)
+		Expect(err).To(MatchError(ContainSubstring("not allowed")))
+	})
+
+	It("should label instead of own secrets in other namespaces", func() {
+		secretName := types.NamespacedName{Namespace: "workload", Name: "binding"}
+		Expect(reconciler.storeBindingSecret(ctx, serviceInstance, serviceBinding, credentials, secretName, "", false)).To(Equal("binding"))
+
+		secret := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, secretName, secret)).To(Succeed())
+		Expect(secret.OwnerReferences).To(BeEmpty())
+		Expect(secret.Labels).To(HaveKeyWithValue(cfv1alpha1.LabelKeyServiceBindingNamespace, "provisioning"))
+		Expect(secret.Data).To(HaveKeyWithValue("user", []byte("admin")))
+		exists, _, err := reconciler.existsCredentialsSecret(ctx, serviceBinding, secretName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeTrue())
+	})
+
+	It("should not overwrite secrets written by others", func() {
+		secretName := types.NamespacedName{Namespace: "workload", Name: "binding"}
+		Expect(reconciler.Create(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: secretName.Namespace, Name: secretName.Name}})).To(Succeed())
+
+		_, err := reconciler.storeBindingSecret(ctx, serviceInstance, serviceBinding, credentials, secretName, "", false)
+		Expect(err).To(MatchError(ContainSubstring("was not written by this binding")))
+		exists, _, err := reconciler.existsCredentialsSecret(ctx, serviceBinding, secretName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+	})
+
+	It("should delete the previous secret when the secret moves", func() {
+		previousName := types.NamespacedName{Namespace: "workload", Name: "binding"}
+		Expect(reconciler.storeBindingSecret(ctx, serviceInstance, serviceBinding, credentials, previousName, "", false)).To(Equal("binding"))
+		// a secret of a binding with the same name in the target namespace must be left alone
+		Expect(reconciler.Create(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "workload",
+			Name:      "local",
+			Labels:    map[string]string{cfv1alpha1.LabelKeyServiceBinding: "binding"},
+		}})).To(Succeed())
+
+		secretName := types.NamespacedName{Namespace: "provisioning", Name: "binding"}
+		Expect(reconciler.storeBindingSecret(ctx, serviceInstance, serviceBinding, credentials, secretName, "", false)).To(Equal("binding"))
+
+		secret := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, secretName, secret)).To(Succeed())
+		Expect(metav1.IsControlledBy(secret, serviceBinding)).To(BeTrue())
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, previousName, &corev1.Secret{}))).To(BeTrue())
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "workload", Name: "local"}, &corev1.Secret{})).To(Succeed())
+	})
+})
+
+var _ = Describe("Store binding secrets | storeBindingSecret", func() {
+	ctx := context.Background()
+
+	var reconciler *ServiceBindingReconciler
+	var serviceInstance *cfv1alpha1.ServiceInstance
+	var serviceBinding *cfv1alpha1.ServiceBinding
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		reconciler = &ServiceBindingReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Scheme: scheme, Config: config.Defaults()}
+		serviceInstance = &cfv1alpha1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "instance"}}
+		serviceBinding = &cfv1alpha1.ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "binding", UID: "binding-uid"},
+			Spec:       cfv1alpha1.ServiceBindingSpec{ServiceInstanceName: "instance", SecretName: "binding", SecretType: string(corev1.SecretTypeBasicAuth)},
+		}
+	})
+
+	It("should write a new secret whenever the content of an immutable secret changes", func() {
+		serviceBinding.Spec.SecretImmutable = true
+		secretName := types.NamespacedName{Namespace: "app", Name: "binding"}
+
+		firstName, err := reconciler.storeBindingSecret(ctx, serviceInstance, serviceBinding, map[string]interface{}{"username": "admin", "password": "a"}, secretName, "", false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(firstName).To(HavePrefix("binding-"))
+		Expect(reconciler.storeBindingSecret(ctx, serviceInstance, serviceBinding, map[string]interface{}{"username": "admin", "password": "a"}, secretName, "", false)).To(Equal(firstName))
+		secret := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "app", Name: firstName}, secret)).To(Succeed())
+		Expect(secret.Type).To(Equal(corev1.SecretTypeBasicAuth))
+		Expect(secret.Immutable).To(Equal(&[]bool{true}[0]))
+
+		secondName, err := reconciler.storeBindingSecret(ctx, serviceInstance, serviceBinding, map[string]interface{}{"username": "admin", "password": "b"}, secretName, "", false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secondName).To(HavePrefix("binding-"))
+		Expect(secondName).ToNot(Equal(firstName))
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "app", Name: secondName}, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("password", []byte("b")))
+
+		// the superseded secret is retained for the grace period
+		firstSecret := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "app", Name: firstName}, firstSecret)).To(Succeed())
+		Expect(firstSecret.Annotations).To(HaveKey(cfv1alpha1.AnnotationSupersededAt))
+		retainedFor, err := reconciler.deleteObsoleteBindingSecrets(ctx, serviceBinding, types.NamespacedName{Namespace: "app", Name: secondName})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retainedFor).To(BeNumerically("~", 5*time.Minute, time.Minute))
+
+		firstSecret.Annotations[cfv1alpha1.AnnotationSupersededAt] = time.Now().Add(-5 * time.Minute).UTC().Format(time.RFC3339)
+		Expect(reconciler.Update(ctx, firstSecret)).To(Succeed())
+		retainedFor, err = reconciler.deleteObsoleteBindingSecrets(ctx, serviceBinding, types.NamespacedName{Namespace: "app", Name: secondName})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retainedFor).To(BeZero())
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, types.NamespacedName{Namespace: "app", Name: firstName}, &corev1.Secret{}))).To(BeTrue())
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "app", Name: secondName}, &corev1.Secret{})).To(Succeed())
+	})
+
+	It("should delete superseded immutable secrets immediately without grace period", func() {
+		reconciler.Config.SupersededSecretGracePeriod = metav1.Duration{}
+		serviceBinding.Spec.SecretImmutable = true
+		secretName := types.NamespacedName{Namespace: "app", Name: "binding"}
+
+		firstName, err := reconciler.storeBindingSecret(ctx, serviceInstance, serviceBinding, map[string]interface{}{"username": "admin", "password": "a"}, secretName, "", false)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = reconciler.storeBindingSecret(ctx, serviceInstance, serviceBinding, map[string]interface{}{"username": "admin", "password": "b"}, secretName, "", false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, types.NamespacedName{Namespace: "app", Name: firstName}, &corev1.Secret{}))).To(BeTrue())
+	})
+
+	It("should not write unchanged secrets", func() {
+		secretName := types.NamespacedName{Namespace: "app", Name: "binding"}
+		credentials := map[string]interface{}{"username": "admin", "password": "a"}
+		creates := testutil.ToFloat64(serviceBindingSecretWrites.WithLabelValues("create"))
+		updates := testutil.ToFloat64(serviceBindingSecretWrites.WithLabelValues("update"))
+
+		Expect(reconciler.storeBindingSecret(ctx, serviceInstance, serviceBinding, credentials, secretName, "", false)).To(Equal("binding"))
+		secret := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, secretName, secret)).To(Succeed())
+		resourceVersion := secret.ResourceVersion
+		Expect(reconciler.storeBindingSecret(ctx, serviceInstance, serviceBinding, credentials, secretName, "", false)).To(Equal("binding"))
+		Expect(reconciler.Get(ctx, secretName, secret)).To(Succeed())
+		Expect(secret.ResourceVersion).To(Equal(resourceVersion))
+
+		credentials["password"] = "b"
+		Expect(reconciler.storeBindingSecret(ctx, serviceInstance, serviceBinding, credentials, secretName, "", false)).To(Equal("binding"))
+		Expect(reconciler.Get(ctx, secretName, secret)).To(Succeed())
+		Expect(secret.ResourceVersion).ToNot(Equal(resourceVersion))
+		Expect(testutil.ToFloat64(serviceBindingSecretWrites.WithLabelValues("create"))).To(Equal(creates + 1))
+		Expect(testutil.ToFloat64(serviceBindingSecretWrites.WithLabelValues("update"))).To(Equal(updates + 1))
+	})
+
+	It("should recreate the secret if its type changes", func() {
+		secretName := types.NamespacedName{Namespace: "app", Name: "binding"}
+		Expect(reconciler.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "binding", Labels: map[string]string{cfv1alpha1.LabelKeyServiceBinding: "binding"}},
+			Type:       corev1.SecretTypeOpaque,
+		})).To(Succeed())
+
+		Expect(reconciler.storeBindingSecret(ctx, serviceInstance, serviceBinding, map[string]interface{}{"username": "admin", "password": "a"}, secretName, "", false)).To(Equal("binding"))
+		secret := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, secretName, secret)).To(Succeed())
+		Expect(secret.Type).To(Equal(corev1.SecretTypeBasicAuth))
+		Expect(secret.Immutable).To(BeNil())
+		Expect(metav1.IsControlledBy(secret, serviceBinding)).To(BeTrue())
+	})
+
+	It("should only store the selected credential keys", func() {
+		serviceBinding.Spec.SecretKeys = []string{"username", "password"}
+		secretName := types.NamespacedName{Namespace: "app", Name: "binding"}
+		credentials := map[string]interface{}{"username": "admin", "password": "a", "certificate": "-----BEGIN CERTIFICATE-----"}
+
+		Expect(reconciler.storeBindingCredentials(ctx, serviceInstance, serviceBinding, credentials, secretName, "", false)).To(Equal("secret binding"))
+		secret := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, secretName, secret)).To(Succeed())
+		Expect(secret.Data).To(Equal(map[string][]byte{"username": []byte("admin"), "password": []byte("a")}))
+
+		delete(credentials, "password")
+		_, err := reconciler.storeBindingCredentials(ctx, serviceInstance, serviceBinding, credentials, secretName, "", false)
+		Expect(err).To(MatchError(ContainSubstring("selected keys missing in binding credentials: password")))
+	})
+
+	It("should split the credentials into additional secrets, and delete secrets no longer specified", func() {
+		serviceBinding.Spec.SecretKeys = []string{"username", "password"}
+		serviceBinding.Spec.Secrets = []cfv1alpha1.BindingSecret{
+			{Name: "binding-tls", Type: string(corev1.SecretTypeTLS), Keys: []cfv1alpha1.BindingSecretKey{{Key: "certificate", SecretKey: "tls.crt"}, {Key: "key", SecretKey: "tls.key"}}},
+			{Name: "binding-uri", Format: cfv1alpha1.BindingSecretFormatJSON, Keys: []cfv1alpha1.BindingSecretKey{{Key: "uri"}}},
+		}
+		secretName := types.NamespacedName{Namespace: "app", Name: "binding"}
+		credentials := map[string]interface{}{"username": "admin", "password": "a", "certificate": "cert", "key": "key", "uri": "postgres://db"}
+
+		Expect(reconciler.storeBindingCredentials(ctx, serviceInstance, serviceBinding, credentials, secretName, "", false)).To(Equal("secrets binding, binding-tls, binding-uri"))
+		secret := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, secretName, secret)).To(Succeed())
+		Expect(secret.Data).To(Equal(map[string][]byte{"username": []byte("admin"), "password": []byte("a")}))
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "app", Name: "binding-tls"}, secret)).To(Succeed())
+		Expect(secret.Type).To(Equal(corev1.SecretTypeTLS))
+		Expect(secret.Data).To(Equal(map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")}))
+		Expect(secret.Labels).To(HaveKeyWithValue(cfv1alpha1.LabelKeyServiceBinding, "binding"))
+		Expect(metav1.IsControlledBy(secret, serviceBinding)).To(BeTrue())
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "app", Name: "binding-uri"}, secret)).To(Succeed())
+		Expect(secret.Type).To(Equal(corev1.SecretTypeOpaque))
+		Expect(secret.Data).To(Equal(map[string][]byte{"credentials": []byte(`{"uri":"postgres://db"}`)}))
+
+		serviceBinding.Spec.Secrets = serviceBinding.Spec.Secrets[:1]
+		Expect(reconciler.storeBindingCredentials(ctx, serviceInstance, serviceBinding, credentials, secretName, "", false)).To(Equal("secrets binding, binding-tls"))
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "app", Name: "binding-tls"}, &corev1.Secret{})).To(Succeed())
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, types.NamespacedName{Namespace: "app", Name: "binding-uri"}, &corev1.Secret{}))).To(BeTrue())
+	})
+})
+
+var _ = Describe("Read credentials only if needed | isBindingSecretOutdated", func() {
+	ctx := context.Background()
+	secretName := types.NamespacedName{Namespace: "app", Name: "binding"}
+
+	var reconciler *ServiceBindingReconciler
+	var serviceInstance *cfv1alpha1.ServiceInstance
+	var serviceBinding *cfv1alpha1.ServiceBinding
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		reconciler = &ServiceBindingReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Scheme: scheme, Config: config.Defaults()}
+		serviceInstance = &cfv1alpha1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "instance"}}
+		serviceBinding = &cfv1alpha1.ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "binding", UID: "binding-uid", Generation: 1},
+			Spec:       cfv1alpha1.ServiceBindingSpec{ServiceInstanceName: "instance", SecretName: "binding"},
+		}
+	})
+
+	storeCredentials := func() {
+		_, err := reconciler.storeBindingCredentials(ctx, serviceInstance, serviceBinding, map[string]interface{}{"password": "a"}, secretName, "", false)
+		Expect(err).ToNot(HaveOccurred())
+		serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionCredentialsReady, cfv1alpha1.ConditionTrue, conditionReasonSecretStored, "Credentials stored")
+		serviceBinding.Status.CredentialsDigest = "digest"
+	}
+
+	It("should require credentials until they were stored", func() {
+		Expect(reconciler.isBindingSecretOutdated(ctx, serviceBinding, secretName)).To(BeTrue())
+		storeCredentials()
+		Expect(reconciler.isBindingSecretOutdated(ctx, serviceBinding, secretName)).To(BeFalse())
+	})
+
+	It("should require credentials after the spec changed", func() {
+		storeCredentials()
+		serviceBinding.Generation = 2
+		Expect(reconciler.isBindingSecretOutdated(ctx, serviceBinding, secretName)).To(BeTrue())
+	})
+
+	It("should require credentials if a binding secret is missing", func() {
+		storeCredentials()
+		Expect(reconciler.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "binding"}})).To(Succeed())
+		Expect(reconciler.isBindingSecretOutdated(ctx, serviceBinding, secretName)).To(BeTrue())
+
+		storeCredentials()
+		serviceBinding.Spec.Secrets = []cfv1alpha1.BindingSecret{{Name: "binding-admin"}}
+		Expect(reconciler.isBindingSecretOutdated(ctx, serviceBinding, secretName)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Add SAP binding metadata | withBindingMetadata", func() {
+	It("should let the binding spec take precedence over the annotation and the operator default", func() {
+		reconciler := &ServiceBindingReconciler{EnableBindingMetadata: true}
+		serviceBinding := &cfv1alpha1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: "binding"}}
+		Expect(reconciler.withBindingMetadata(serviceBinding)).To(BeTrue())
+
+		serviceBinding.Annotations = map[string]string{"service-operator.cf.cs.sap.com/with-sap-binding-metadata": "false"}
+		Expect(reconciler.withBindingMetadata(serviceBinding)).To(BeFalse())
+
+		serviceBinding.Spec.SAPBindingMetadata = &cfv1alpha1.SAPBindingMetadata{Fields: []cfv1alpha1.SAPBindingMetadataField{cfv1alpha1.SAPBindingMetadataFieldPlan}}
+		Expect(reconciler.withBindingMetadata(serviceBinding)).To(BeFalse())
+		serviceBinding.Spec.SAPBindingMetadata.Enabled = &[]bool{true}[0]
+		Expect(reconciler.withBindingMetadata(serviceBinding)).To(BeTrue())
+
+		data, err := newBinding(&cfv1alpha1.ServiceInstance{Spec: cfv1alpha1.ServiceInstanceSpec{ServicePlanName: "plan"}}, serviceBinding, nil).SecretData("", true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data).To(HaveKeyWithValue("plan", []byte("plan")))
+		Expect(data).ToNot(HaveKey("type"))
+	})
+})
+
+var _ = Describe("Bind unmanaged service instances | getServiceInstance", func() {
+	ctx := context.Background()
+	var reconciler *ServiceBindingReconciler
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		reconciler = &ServiceBindingReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(&cfv1alpha1.ServiceInstance{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "instance"},
+				Spec:       cfv1alpha1.ServiceInstanceSpec{SpaceName: "space"},
+			}).Build(),
+		}
+	})
+
+	It("should read the referenced ServiceInstance", func() {
+		serviceBinding := &cfv1alpha1.ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "binding"},
+			Spec:       cfv1alpha1.ServiceBindingSpec{ServiceInstanceName: "instance"},
+		}
+		serviceInstance, err := reconciler.getServiceInstance(ctx, serviceBinding)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(serviceInstance.Name).To(Equal("instance"))
+		Expect(serviceInstance.Spec.Name).To(Equal("instance"))
+
+		serviceBinding.Spec.ServiceInstanceName = "missing"
+		_, err = reconciler.getServiceInstance(ctx, serviceBinding)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should stand in for unmanaged instances referenced by guid", func() {
+		serviceBinding := &cfv1alpha1.ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "binding"},
+			Spec:       cfv1alpha1.ServiceBindingSpec{ServiceInstanceGuid: "instance-guid", ClusterSpaceName: "shared"},
+		}
+		serviceInstance, err := reconciler.getServiceInstance(ctx, serviceBinding)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(serviceInstance.Namespace).To(Equal("ns"))
+		Expect(serviceInstance.Spec.ClusterSpaceName).To(Equal("shared"))
+		Expect(serviceInstance.Status.ServiceInstanceGuid).To(Equal("instance-guid"))
+	})
+})
+
+var _ = Describe("Default binding secret names from a template | DefaultSecretName", func() {
+	It("should render the template, unless the secret name is specified", func() {
+		cfg := config.Defaults()
+		cfg.BindingSecretNameTemplate = "{{ .Spec.ServiceInstanceName }}-{{ .Name }}-creds"
+		secretNameTemplate, err := cfg.GetBindingSecretNameTemplate()
+		Expect(err).ToNot(HaveOccurred())
+
+		serviceBinding := &cfv1alpha1.ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "binding"},
+			Spec:       cfv1alpha1.ServiceBindingSpec{ServiceInstanceName: "instance"},
+		}
+		Expect(serviceBinding.DefaultSecretName(secretNameTemplate)).To(Succeed())
+		Expect(serviceBinding.Spec.SecretName).To(Equal("instance-binding-creds"))
+
+		serviceBinding.Spec.SecretName = "explicit"
+		Expect(serviceBinding.DefaultSecretName(secretNameTemplate)).To(Succeed())
+		Expect(serviceBinding.Spec.SecretName).To(Equal("explicit"))
+
+		serviceBinding.Spec.SecretName = ""
+		Expect(serviceBinding.DefaultSecretName(nil)).To(Succeed())
+		Expect(serviceBinding.Spec.SecretName).To(BeEmpty())
+	})
+
+	It("should reject secret names which are not valid object names", func() {
+		cfg := config.Defaults()
+		cfg.BindingSecretNameTemplate = "{{ .Name }}_creds"
+		secretNameTemplate, err := cfg.GetBindingSecretNameTemplate()
+		Expect(err).ToNot(HaveOccurred())
+
+		serviceBinding := &cfv1alpha1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "binding"}}
+		Expect(serviceBinding.DefaultSecretName(secretNameTemplate)).To(MatchError(ContainSubstring("invalid binding secret name")))
+		Expect(serviceBinding.Spec.SecretName).To(BeEmpty())
+	})
+
+	It("should fall back to the binding name if the template uses the instance name of a guid-referenced binding", func() {
+		cfg := config.Defaults()
+		cfg.BindingSecretNameTemplate = "creds-{{ .Name }}{{ .Spec.ServiceInstanceName }}"
+		secretNameTemplate, err := cfg.GetBindingSecretNameTemplate()
+		Expect(err).ToNot(HaveOccurred())
+
+		serviceBinding := &cfv1alpha1.ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "binding"},
+			Spec:       cfv1alpha1.ServiceBindingSpec{ServiceInstanceGuid: "instance-guid", SpaceName: "space"},
+		}
+		Expect(serviceBinding.DefaultSecretName(secretNameTemplate)).To(Succeed())
+		Expect(serviceBinding.Spec.SecretName).To(Equal("binding"))
+
+		// templates not using the instance name are rendered as usual
+		cfg.BindingSecretNameTemplate = "{{ .Name }}-creds"
+		secretNameTemplate, err = cfg.GetBindingSecretNameTemplate()
+		Expect(err).ToNot(HaveOccurred())
+		serviceBinding.Spec.SecretName = ""
+		Expect(serviceBinding.DefaultSecretName(secretNameTemplate)).To(Succeed())
+		Expect(serviceBinding.Spec.SecretName).To(Equal("binding-creds"))
+	})
+})
+
+var _ = Describe("Retry failed bindings | HandleError", func() {
+	var reconciler *ServiceBindingReconciler
+	var serviceBinding *cfv1alpha1.ServiceBinding
+
+	BeforeEach(func() {
+		reconciler = &ServiceBindingReconciler{Config: config.Defaults()}
+		serviceBinding = &cfv1alpha1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "binding"}}
+		serviceBinding.Status.MaxRetries = getMaxRetries(map[string]string{cfv1alpha1.AnnotationMaxRetries: "3"}, serviceBindingDefaultMaxRetries, logr.Discard())
+	})
+
+	It("should return other errors as is", func() {
+		issue := errors.New("some error")
+		_, err := reconciler.HandleError(serviceBinding, nil, issue, logr.Discard())
+		Expect(err).To(Equal(issue))
+		Expect(serviceBinding.Status.RetryCounter).To(Equal(0))
+		Expect(serviceBinding.GetReadyCondition().Reason).To(Equal(serviceBindingReadyConditionReasonError))
+	})
+
+	It("should retry with increasing intervals, and give up after the maximum number of retries", func() {
+		result, err := reconciler.HandleError(serviceBinding, nil, RetryError, logr.Discard())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(serviceBindingDefaultRetryInterval))
+		result, err = reconciler.HandleError(serviceBinding, nil, RetryError, logr.Discard())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(2 * serviceBindingDefaultRetryInterval))
+
+		annotations := map[string]string{cfv1alpha1.AnnotationPollingIntervalFail: "1h"}
+		result, err = reconciler.HandleError(serviceBinding, annotations, RetryError, logr.Discard())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(time.Hour))
+		Expect(serviceBinding.Status.RetryCounter).To(Equal(3))
+		Expect(serviceBinding.GetReadyCondition().Reason).To(Equal(serviceBindingReadyConditionReasonMaxRetriesExceeded))
+	})
+
+	It("should cap the retry interval, and retry infinitely by default", func() {
+		serviceBinding.Status.MaxRetries = getMaxRetries(nil, serviceBindingDefaultMaxRetries, logr.Discard())
+		serviceBinding.Status.RetryCounter = 100
+		result, err := reconciler.HandleError(serviceBinding, nil, RetryError, logr.Discard())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(serviceBindingDefaultMaxRetryInterval))
+	})
+})
+
+var _ = Describe("Re-create service keys without credentials gap | replaceBinding", func() {
+	ctx := context.Background()
+	parameters := map[string]interface{}{"key": "value"}
+
+	var reconciler *ServiceBindingReconciler
+	var spaceClient *facadefakes.FakeSpaceClient
+	var serviceInstance *cfv1alpha1.ServiceInstance
+	var serviceBinding *cfv1alpha1.ServiceBinding
+	var cfbinding *facade.Binding
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		reconciler = &ServiceBindingReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Scheme: scheme, Config: config.Defaults()}
+		spaceClient = &facadefakes.FakeSpaceClient{}
+		serviceInstance = &cfv1alpha1.ServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "instance"},
+			Status:     cfv1alpha1.ServiceInstanceStatus{ServiceInstanceGuid: "instance-guid"},
+		}
+		serviceBinding = &cfv1alpha1.ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "binding", UID: "binding-uid", Generation: 2},
+			Spec:       cfv1alpha1.ServiceBindingSpec{Name: "binding", ServiceInstanceName: "instance", SecretName: "binding"},
+		}
+		cfbinding = &facade.Binding{Guid: "binding-guid", Name: "binding", Owner: "binding-uid", State: facade.BindingStateReady}
+	})
+
+	It("should create a replacement binding first", func() {
+		result, err := reconciler.replaceBinding(ctx, spaceClient, serviceInstance, serviceBinding, cfbinding, parameters)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+
+		Expect(spaceClient.CreateBindingCallCount()).To(Equal(1))
+		_, name, serviceInstanceGuid, appGuid, _, _, owner, generation := spaceClient.CreateBindingArgsForCall(0)
+		Expect(name).To(HavePrefix("binding-"))
+		Expect(serviceInstanceGuid).To(Equal("instance-guid"))
+		Expect(appGuid).To(BeEmpty())
+		Expect(owner).To(Equal(facade.OwnerRef{UID: "binding-uid", Replacement: true, Namespace: "app", Name: "binding"}))
+		Expect(generation).To(Equal(int64(2)))
+		Expect(spaceClient.DeleteBindingCallCount()).To(BeZero())
+	})
+
+	It("should name the replacement of a promoted replacement like the binding", func() {
+		cfbinding.Name = "binding-0123abcd"
+		_, err := reconciler.replaceBinding(ctx, spaceClient, serviceInstance, serviceBinding, cfbinding, parameters)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(spaceClient.CreateBindingCallCount()).To(Equal(1))
+		_, name, _, _, _, _, _, _ := spaceClient.CreateBindingArgsForCall(0)
+		Expect(name).To(Equal("binding"))
+	})
+
+	It("should store the credentials of the ready replacement before deleting the replaced binding", func() {
+		spaceClient.GetBindingReturns(&facade.Binding{
+			Guid:          "replacement-guid",
+			Owner:         "binding-uid",
+			Replacement:   true,
+			ParameterHash: facade.ObjectHash(parameters),
+			State:         facade.BindingStateReady,
+		}, nil)
+		spaceClient.GetBindingCredentialsReturns(map[string]interface{}{"password": "new"}, nil)
+
+		_, err := reconciler.replaceBinding(ctx, spaceClient, serviceInstance, serviceBinding, cfbinding, parameters)
+		Expect(err).ToNot(HaveOccurred())
+
+		secret := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "app", Name: "binding"}, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("password", []byte("new")))
+		_, guid := spaceClient.GetBindingCredentialsArgsForCall(0)
+		Expect(guid).To(Equal("replacement-guid"))
+		Expect(spaceClient.DeleteBindingCallCount()).To(Equal(1))
+		_, guid, owner := spaceClient.DeleteBindingArgsForCall(0)
+		Expect(guid).To(Equal("binding-guid"))
+		Expect(owner).To(Equal(facade.OwnerRef{UID: "binding-uid"}))
+	})
+
+	It("should discard outdated replacements", func() {
+		spaceClient.GetBindingReturns(&facade.Binding{
+			Guid:          "replacement-guid",
+			Owner:         "binding-uid",
+			Replacement:   true,
+			ParameterHash: facade.ObjectHash(map[string]interface{}{"key": "other-value"}),
+			State:         facade.BindingStateReady,
+		}, nil)
+
+		_, err := reconciler.replaceBinding(ctx, spaceClient, serviceInstance, serviceBinding, cfbinding, parameters)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(spaceClient.DeleteBindingCallCount()).To(Equal(1))
+		_, guid, owner := spaceClient.DeleteBindingArgsForCall(0)
+		Expect(guid).To(Equal("replacement-guid"))
+		Expect(owner).To(Equal(facade.OwnerRef{UID: "binding-uid", Replacement: true}))
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, types.NamespacedName{Namespace: "app", Name: "binding"}, &corev1.Secret{}))).To(BeTrue())
+	})
+})
+
+var _ = Describe("Observe bindings without changing them | observeBinding", func() {
+	ctx := context.Background()
+	var client *facadefakes.FakeSpaceClient
+	var reconciler *ServiceBindingReconciler
+	var serviceBinding *cfv1alpha1.ServiceBinding
+
+	BeforeEach(func() {
+		client = &facadefakes.FakeSpaceClient{}
+		reconciler = &ServiceBindingReconciler{Config: config.Defaults()}
+		serviceBinding = &cfv1alpha1.ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "binding", UID: "binding-uid"},
+			Spec:       cfv1alpha1.ServiceBindingSpec{Name: "cf-binding", ServiceInstanceName: "instance"},
+		}
+	})
+
+	expectNoChanges := func() {
+		Expect(client.CreateBindingCallCount()).To(Equal(0))
+		Expect(client.UpdateBindingCallCount()).To(Equal(0))
+		Expect(client.DeleteBindingCallCount()).To(Equal(0))
+		Expect(serviceBinding.GetCondition(cfv1alpha1.ServiceBindingConditionSynced).Reason).To(Equal(conditionReasonObserveOnly))
+		Expect(serviceBinding.GetCondition(cfv1alpha1.ServiceBindingConditionCredentialsReady).Reason).To(Equal(conditionReasonObserveOnly))
+	}
+
+	It("should reflect the state of the owned binding in the status, without writing the binding secret", func() {
+		client.GetBindingReturns(&facade.Binding{Guid: "binding-guid", ServiceInstanceGuid: "instance-guid", AppGuid: "app-guid", Type: facade.BindingTypeApp, State: facade.BindingStateReady}, nil)
+
+		result, err := reconciler.observeBinding(ctx, serviceBinding, nil, client, "space-guid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(client.GetBindingCallCount()).To(Equal(1))
+		_, bindingOpts := client.GetBindingArgsForCall(0)
+		Expect(bindingOpts).To(Equal(map[string]string{"name": "", "owner": "binding-uid"}))
+		Expect(serviceBinding.Status.ServiceBindingGuid).To(Equal("binding-guid"))
+		Expect(serviceBinding.Status.ServiceInstanceGuid).To(Equal("instance-guid"))
+		Expect(serviceBinding.Status.AppGuid).To(Equal("app-guid"))
+		Expect(serviceBinding.Status.Type).To(Equal(cfv1alpha1.ServiceBindingType(facade.BindingTypeApp)))
+		Expect(serviceBinding.IsReady()).To(BeTrue())
+		Expect(client.GetBindingCredentialsCallCount()).To(Equal(0))
+		expectNoChanges()
+	})
+
+	It("should look up bindings not owned (yet) by adoption annotation, or by name", func() {
+		serviceBinding.Annotations = map[string]string{cfv1alpha1.AnnotationAdoptCFLabelSelector: "team=a"}
+		_, err := reconciler.observeBinding(ctx, serviceBinding, nil, client, "space-guid")
+		Expect(err).NotTo(HaveOccurred())
+		_, bindingOpts := client.GetBindingArgsForCall(1)
+		Expect(bindingOpts).To(HaveKeyWithValue("labelSelector", "team=a"))
+
+		serviceBinding.Annotations = nil
+		_, err = reconciler.observeBinding(ctx, serviceBinding, nil, client, "space-guid")
+		Expect(err).NotTo(HaveOccurred())
+		_, bindingOpts = client.GetBindingArgsForCall(3)
+		Expect(bindingOpts).To(HaveKeyWithValue("name", "cf-binding"))
+
+		condition := serviceBinding.GetReadyCondition()
+		Expect(condition.Status).To(Equal(cfv1alpha1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(readyConditionReasonNotFound))
+		expectNoChanges()
+	})
+
+	It("should poll bindings with operations in progress", func() {
+		client.GetBindingReturns(&facade.Binding{Guid: "binding-guid", State: facade.BindingStateCreating}, nil)
+		result, err := reconciler.observeBinding(ctx, serviceBinding, nil, client, "space-guid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(10 * time.Second))
+		Expect(serviceBinding.GetReadyCondition().Status).To(Equal(cfv1alpha1.ConditionUnknown))
+		expectNoChanges()
+	})
+})
+
+var _ = Describe("Bind service instances to applications | getAppGuid", func() {
+	ctx := context.Background()
+	var client *facadefakes.FakeSpaceClient
+
+	BeforeEach(func() {
+		client = &facadefakes.FakeSpaceClient{}
+	})
+
+	It("should return the application guid of app bindings, and nothing for service keys", func() {
+		Expect(getAppGuid(ctx, client, &cfv1alpha1.ServiceBindingSpec{Type: cfv1alpha1.ServiceBindingTypeApp, AppGuid: "app-guid"})).To(Equal("app-guid"))
+		Expect(getAppGuid(ctx, client, &cfv1alpha1.ServiceBindingSpec{Type: cfv1alpha1.ServiceBindingTypeKey})).To(BeEmpty())
+		Expect(client.FindAppCallCount()).To(Equal(0))
+
+		_, err := getAppGuid(ctx, client, &cfv1alpha1.ServiceBindingSpec{Type: cfv1alpha1.ServiceBindingTypeApp})
+		Expect(err).To(MatchError(ContainSubstring("no application specified")))
+	})
+
+	It("should look up applications by name", func() {
+		client.FindAppReturns("app-guid", nil)
+		Expect(getAppGuid(ctx, client, &cfv1alpha1.ServiceBindingSpec{Type: cfv1alpha1.ServiceBindingTypeApp, AppName: "my-app"})).To(Equal("app-guid"))
+		_, name := client.FindAppArgsForCall(0)
+		Expect(name).To(Equal("my-app"))
+
+		client.FindAppReturns("", errors.New("application not found"))
+		_, err := getAppGuid(ctx, client, &cfv1alpha1.ServiceBindingSpec{Type: cfv1alpha1.ServiceBindingTypeApp, AppName: "other-app"})
+		Expect(err).To(MatchError("application not found"))
+	})
+})
+
+var _ = Describe("Pause the reconciliation of bindings | Reconcile", func() {
+	ctx := context.Background()
+	bindingKey := types.NamespacedName{Namespace: "ns", Name: "binding"}
+	var clientBuilds int
+	var reconciler *ServiceBindingReconciler
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		serviceBinding := &cfv1alpha1.ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   bindingKey.Namespace,
+				Name:        bindingKey.Name,
+				Generation:  1,
+				Annotations: map[string]string{cfv1alpha1.AnnotationPaused: "true"},
+			},
+			Spec: cfv1alpha1.ServiceBindingSpec{ServiceInstanceName: "instance"},
+		}
+		serviceBinding.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceBindingReadyConditionReasonNew, "First seen")
+		clientBuilds = 0
+		reconciler = &ServiceBindingReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(serviceBinding).
+				WithStatusSubresource(&cfv1alpha1.ServiceBinding{}).
+				Build(),
+			Scheme: scheme,
+			ClientBuilder: func(string, string, string, string, *config.Config) (facade.SpaceClient, error) {
+				clientBuilds++
+				return &facadefakes.FakeSpaceClient{}, nil
+			},
+			Config: config.Defaults(),
+		}
+	})
+
+	It("should neither read nor change the binding while paused, and resume once the annotation is removed", func() {
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: bindingKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(ctrl.Result{}))
+
+		Expect(clientBuilds).To(BeZero())
+		serviceBinding := &cfv1alpha1.ServiceBinding{}
+		Expect(reconciler.Get(ctx, bindingKey, serviceBinding)).To(Succeed())
+		Expect(serviceBinding.Finalizers).To(BeEmpty())
+		Expect(serviceBinding.GetCondition(cfv1alpha1.ServiceBindingConditionPaused)).ToNot(BeNil())
+		Expect(serviceBinding.GetCondition(cfv1alpha1.ServiceBindingConditionPaused).Status).To(Equal(cfv1alpha1.ConditionTrue))
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, bindingKey, &corev1.Secret{}))).To(BeTrue())
+
+		// resume
+		delete(serviceBinding.Annotations, cfv1alpha1.AnnotationPaused)
+		Expect(reconciler.Update(ctx, serviceBinding)).To(Succeed())
+		result, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: bindingKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+		Expect(reconciler.Get(ctx, bindingKey, serviceBinding)).To(Succeed())
+		Expect(serviceBinding.GetCondition(cfv1alpha1.ServiceBindingConditionPaused)).To(BeNil())
+	})
+})
+
+var _ = Describe("Refresh credentials rotated on the broker side | Reconcile", func() {
+	ctx := context.Background()
+	bindingKey := types.NamespacedName{Namespace: "ns", Name: "binding"}
+	var spaceClient *facadefakes.FakeSpaceClient
+	var reconciler *ServiceBindingReconciler
+
+	credentialsDigest := func(credentials map[string]interface{}) string {
+		return facade.ObjectHash(map[string]interface{}{"uid": "binding-uid", "credentials": credentials})
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		space := &cfv1alpha1.Space{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space"},
+			Spec:       cfv1alpha1.SpaceSpec{Guid: "space-guid", AuthSecretName: "space-secret"},
+		}
+		space.SetReadyCondition(cfv1alpha1.ConditionTrue, "Ready", "")
+		serviceInstance := &cfv1alpha1.ServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "instance", Generation: 1},
+			Spec:       cfv1alpha1.ServiceInstanceSpec{SpaceName: "space", ServiceOfferingName: "offering", ServicePlanName: "plan"},
+			Status:     cfv1alpha1.ServiceInstanceStatus{SpaceGuid: "space-guid", ServiceInstanceGuid: "instance-guid"},
+		}
+		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionTrue, string(facade.InstanceStateReady), "")
+		// a ready binding whose credentials were stored before
+		serviceBinding := &cfv1alpha1.ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   bindingKey.Namespace,
+				Name:        bindingKey.Name,
+				UID:         "binding-uid",
+				Generation:  1,
+				Finalizers:  []string{serviceBindingFinalizer},
+				Annotations: map[string]string{cfv1alpha1.AnnotationRefreshCredentialsInterval: "1h", cfv1alpha1.AnnotationPollingIntervalReady: "2h"},
+			},
+			Spec: cfv1alpha1.ServiceBindingSpec{ServiceInstanceName: "instance"},
+			Status: cfv1alpha1.ServiceBindingStatus{
+				SpaceGuid:           "space-guid",
+				ServiceInstanceGuid: "instance-guid",
+				ServiceBindingGuid:  "binding-guid",
+				CredentialsDigest:   credentialsDigest(map[string]interface{}{"password": "old"}),
+			},
+		}
+		serviceBinding.SetReadyCondition(cfv1alpha1.ConditionTrue, string(facade.BindingStateReady), "")
+		spaceClient = &facadefakes.FakeSpaceClient{}
+		spaceClient.GetBindingReturns(&facade.Binding{Guid: "binding-guid", ServiceInstanceGuid: "instance-guid", Owner: "binding-uid", Generation: 1, State: facade.BindingStateReady, ParameterHash: facade.ObjectHash(nil)}, nil)
+		reconciler = &ServiceBindingReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(serviceBinding, serviceInstance, space, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space-secret"},
+					Data:       map[string][]byte{"url": []byte("https://api.cf.example.com")},
+				}).
+				WithStatusSubresource(&cfv1alpha1.ServiceBinding{}).
+				Build(),
+			Scheme: scheme,
+			ClientBuilder: func(string, string, string, string, *config.Config) (facade.SpaceClient, error) {
+				return spaceClient, nil
+			},
+			Config: config.Defaults(),
+		}
+	})
+
+	It("should re-read the credentials when due, count the rotation, and update the binding secret", func() {
+		rotations := testutil.ToFloat64(serviceBindingCredentialsRotations)
+		spaceClient.GetBindingCredentialsReturns(map[string]interface{}{"password": "new"}, nil)
+
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: bindingKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(spaceClient.GetBindingCredentialsCallCount()).To(Equal(1))
+		_, guid := spaceClient.GetBindingCredentialsArgsForCall(0)
+		Expect(guid).To(Equal("binding-guid"))
+		Expect(testutil.ToFloat64(serviceBindingCredentialsRotations)).To(Equal(rotations + 1))
+		// the next refresh is due before the next regular poll
+		Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+
+		serviceBinding := &cfv1alpha1.ServiceBinding{}
+		Expect(reconciler.Get(ctx, bindingKey, serviceBinding)).To(Succeed())
+		Expect(serviceBinding.Status.LastCredentialsRefreshAt).ToNot(BeNil())
+		Expect(serviceBinding.Status.CredentialsDigest).To(Equal(credentialsDigest(map[string]interface{}{"password": "new"})))
+		secret := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, bindingKey, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("password", []byte("new")))
+
+		// not yet due again
+		result, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: bindingKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(spaceClient.GetBindingCredentialsCallCount()).To(Equal(1))
+		Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+	})
+
+	It("should not count unchanged credentials as rotation", func() {
+		rotations := testutil.ToFloat64(serviceBindingCredentialsRotations)
+		spaceClient.GetBindingCredentialsReturns(map[string]interface{}{"password": "old"}, nil)
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: bindingKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(spaceClient.GetBindingCredentialsCallCount()).To(Equal(1))
+		Expect(testutil.ToFloat64(serviceBindingCredentialsRotations)).To(Equal(rotations))
+	})
+
+	It("should not re-read the credentials without the annotation", func() {
+		serviceBinding := &cfv1alpha1.ServiceBinding{}
+		Expect(reconciler.Get(ctx, bindingKey, serviceBinding)).To(Succeed())
+		delete(serviceBinding.Annotations, cfv1alpha1.AnnotationRefreshCredentialsInterval)
+		Expect(reconciler.Update(ctx, serviceBinding)).To(Succeed())
+		// the binding secret is in sync
+		Expect(reconciler.Create(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "binding"}})).To(Succeed())
+		serviceBinding.SetCondition(cfv1alpha1.ServiceBindingConditionCredentialsReady, cfv1alpha1.ConditionTrue, conditionReasonSecretStored, "Credentials stored")
+		serviceBinding.Status.SecretName = "binding"
+		Expect(reconciler.Status().Update(ctx, serviceBinding)).To(Succeed())
+
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: bindingKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(spaceClient.GetBindingCredentialsCallCount()).To(Equal(0))
+		Expect(result.RequeueAfter).To(Equal(2 * time.Hour))
+	})
+})
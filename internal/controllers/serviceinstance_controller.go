@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -19,12 +20,16 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/events"
 	"github.com/sap/cf-service-operator/internal/facade"
 )
 
@@ -39,9 +44,39 @@ const (
 	serviceInstanceReadyConditionReasonDeletionBlocked = "DeletionBlocked"
 	// Additionally, all of facade.InstanceState* may occur as Ready condition reason
 
+	// Event reasons
+	serviceInstanceEventReasonUpgradeAvailable  = "UpgradeAvailable"
+	serviceInstanceEventReasonUpgrading         = "Upgrading"
+	serviceInstanceEventReasonInvalidParameters = "InvalidParameters"
+	serviceInstanceEventReasonStalled           = "Stalled"
+	serviceInstanceEventReasonParameterDrift    = "ParameterDrift"
+	serviceInstanceEventReasonNameConflict      = "NameConflict"
+
+	// Reasons of the Stalled condition
+	serviceInstanceStalledConditionReasonTimeout = "OperationTimeout"
+
+	// Reasons of the PlanChangeBlocked condition
+	serviceInstancePlanChangeBlockedConditionReasonNotAllowed = "PlanChangeNotAllowed"
+
+	// Reasons of the NameConflict condition (also used as reason of the Ready and Synced conditions)
+	serviceInstanceNameConflictConditionReasonNameTaken = "NameTaken"
+
+	// Reasons of the ParameterDrift condition
+	serviceInstanceParameterDriftConditionReasonDiffer         = "ParametersDiffer"
+	serviceInstanceParameterDriftConditionReasonInSync         = "ParametersInSync"
+	serviceInstanceParameterDriftConditionReasonNotRetrievable = "ParametersNotRetrievable"
+
+	// Reasons of the DeletionBlocked condition (in addition to conditionReasonDependentsExist and conditionReasonForeignFinalizers)
+	serviceInstanceDeletionBlockedConditionReasonPreDeleteHookRunning = "PreDeleteHookRunning"
+	serviceInstanceDeletionBlockedConditionReasonPreDeleteHookFailed  = "PreDeleteHookFailed"
+	serviceInstanceDeletionBlockedConditionReasonProtected            = "DeletionProtected"
+
 	// Default values while waiting for ServiceInstance creation (state Progressing)
 	serviceInstanceDefaultReconcileInterval = 1 * time.Second
 
+	// Interval after which a name conflict is checked again
+	serviceInstanceNameConflictRequeueInterval = 1 * time.Minute
+
 	// Default values for error cases during ServiceInstance creation
 	serviceInstanceDefaultMaxRetries       = math.MaxInt32 // infinite number of retries
 	serviceInstanceDefaultRetryInterval    = 1 * time.Second
@@ -54,18 +89,39 @@ type ServiceInstanceReconciler struct {
 	Scheme                   *runtime.Scheme
 	ClusterResourceNamespace string
 	ClientBuilder            facade.SpaceClientBuilder
+	ReconcileTimeout         time.Duration
+	Config                   *config.Config
+	Recorder                 record.EventRecorder
+	// Optional; if set, service instances are reconciled when their space is deleted through the operator
+	EventBus *events.Bus
+	// Optional; reader serving lists by field index (set to the manager's cache by SetupWithManager, unless set)
+	CacheReader client.Reader
 }
 
 // RetryError is a special error to indicate that the operation should be retried.
 var RetryError = errors.New("retry")
 
+// retryError maps the given error to RetryError, unless the Cloud Foundry API endpoint is unavailable
+// (such errors are passed through, since they must not count as failed attempts).
+func retryError(err error) error {
+	if _, ok := cfUnavailableResult(err); ok {
+		return err
+	}
+	return RetryError
+}
+
 // +kubebuilder:rbac:groups=cf.cs.sap.com,resources=serviceinstances,verbs=get;list;watch;update
 // +kubebuilder:rbac:groups=cf.cs.sap.com,resources=serviceinstances/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cf.cs.sap.com,resources=serviceinstances/finalizers,verbs=update
 // +kubebuilder:rbac:groups=cf.cs.sap.com,resources=clusterspaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cf.cs.sap.com,resources=spaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cf.cs.sap.com,resources=servicebindings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=routebindings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cf.cs.sap.com,resources=clusterservicebindings,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;patch
 
 func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -80,13 +136,23 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		log.V(1).Info("Not found; ignoring")
 		return ctrl.Result{}, nil
 	}
+
+	// Bound the duration of this reconcile (unless overridden by annotation, as configured); the final status update (see below)
+	// is attempted even after expiry
+	reconcileCallTimeout := getReconcileCallTimeout(serviceInstance.GetAnnotations(), r.ReconcileTimeout)
+	ctx, cancel := withReconcileTimeout(ctx, reconcileCallTimeout)
+	defer cancel()
+	ctx, cfResponded := facade.WithResponseRecorder(ctx)
+
 	// Call the defaulting webhook logic also here (because defaulting through the webhook might be incomplete in case of generateName usage)
 	serviceInstance.Default()
 
 	spec := &serviceInstance.Spec
 	status := &serviceInstance.Status
-	status.ObservedGeneration = serviceInstance.Generation
 	status.LastReconciledAt = &[]metav1.Time{metav1.Now()}[0]
+	status.LastCFError = nil
+	// Annotations complemented by the overrides of the referenced space (once retrieved)
+	annotations := serviceInstance.GetAnnotations()
 
 	// Always attempt to update the status
 	skipStatusUpdate := false
@@ -94,29 +160,55 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		if skipStatusUpdate {
 			return
 		}
+		if cfResponded() {
+			serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionCFReachable, cfv1alpha1.ConditionTrue, conditionReasonReachable, "Cloud Foundry API is reachable")
+		}
 
+		err = reportReconcileTimeout(ctx, serviceInstance, cfv1alpha1.ServiceInstanceConditionTimeout, err, reconcileCallTimeout)
 		if err != nil {
-			result, err = r.HandleError(ctx, serviceInstance, err, log)
+			if unavailableResult, ok := cfUnavailableResult(err); ok {
+				// does not count as failed attempt
+				log.V(1).Info("Cloud Foundry API unavailable; requeuing", "requeueAfter", unavailableResult.RequeueAfter)
+				serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, readyConditionReasonCFUnavailable, err.Error())
+				serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionCFReachable, cfv1alpha1.ConditionFalse, readyConditionReasonCFUnavailable, err.Error())
+				result, err = unavailableResult, nil
+			} else {
+				if err != RetryError {
+					reason, message := describeError(err, conditionReasonError)
+					serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionSynced, cfv1alpha1.ConditionFalse, reason, message)
+					status.LastCFError = getCFError(err)
+				}
+				result, err = r.HandleError(ctx, serviceInstance, annotations, err, log)
+			}
 		}
 
 		// update service instance CR
-		if updateErr := r.Status().Update(ctx, serviceInstance); updateErr != nil {
+		if updateErr := r.Status().Update(context.WithoutCancel(ctx), serviceInstance); updateErr != nil {
 			err = utilerrors.NewAggregate([]error{err, updateErr})
 			result = ctrl.Result{}
 		}
 	}()
 
+	// While paused, nothing is read from or changed in Cloud Foundry; the annotation being removed triggers the next reconciliation
+	if isPaused(serviceInstance.Annotations) {
+		serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionPaused, cfv1alpha1.ConditionTrue, conditionReasonPaused, pausedMessage)
+		return ctrl.Result{}, nil
+	}
+	if serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionPaused) != nil {
+		// persist the removal of the condition first (and requeue), since updates of the object (e.g. adding finalizers) reset the status
+		serviceInstance.RemoveCondition(cfv1alpha1.ServiceInstanceConditionPaused)
+		return ctrl.Result{Requeue: true}, nil
+	}
+
 	// Set a first status (and requeue, because the status update itself will not trigger another reconciliation because of the event filter set)
 	if ready := serviceInstance.GetReadyCondition(); ready == nil {
 		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceInstanceReadyConditionReasonNew, "First seen")
-		setMaxRetries(serviceInstance, log)
+		setMaxRetries(serviceInstance, annotations, log)
 		return ctrl.Result{Requeue: true}, nil
 	}
 
 	// Retrieve referenced space
 	var space cfv1alpha1.GenericSpace
-	var spaceSecretName types.NamespacedName
-
 	if spec.SpaceName != "" {
 		spaceName := types.NamespacedName{
 			Namespace: serviceInstance.Namespace,
@@ -126,10 +218,6 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		if err := r.Get(ctx, spaceName, space); err != nil {
 			return ctrl.Result{}, errors.Wrapf(err, "failed to get Space, name: %s", spec.SpaceName)
 		}
-		spaceSecretName = types.NamespacedName{
-			Namespace: serviceInstance.Namespace,
-			Name:      space.GetSpec().AuthSecretName,
-		}
 	} else if spec.ClusterSpaceName != "" {
 		clusterSpaceName := types.NamespacedName{
 			Name: spec.ClusterSpaceName,
@@ -138,10 +226,14 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		if err := r.Get(ctx, clusterSpaceName, space); err != nil {
 			return ctrl.Result{}, errors.Wrapf(err, "failed to get ClusterSpace, name: %s", spec.ClusterSpaceName)
 		}
-		spaceSecretName = types.NamespacedName{
-			Namespace: r.ClusterResourceNamespace,
-			Name:      space.GetSpec().AuthSecretName,
-		}
+	} else {
+		// the default space of the namespace is applied by the mutating webhook
+		return ctrl.Result{}, fmt.Errorf("neither spec.spaceName nor spec.clusterSpaceName specified")
+	}
+
+	spaceSecretName, err := getSpaceSecretName(space, r.ClusterResourceNamespace, r.Config)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 
 	spaceGuid := space.GetSpec().Guid
@@ -149,6 +241,10 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		spaceGuid = space.GetStatus().SpaceGuid
 	}
 
+	// Apply the configuration overrides of the space
+	annotations = getEffectiveAnnotations(annotations, space)
+	setMaxRetries(serviceInstance, annotations, log)
+
 	spaceSecret := &corev1.Secret{}
 	if err := r.Get(ctx, spaceSecretName, spaceSecret); err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "failed to get Secret containing space credentials, secret name: %s", spaceSecretName)
@@ -156,21 +252,42 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 
 	// Find depending service bindings
 	serviceBindingList := &cfv1alpha1.ServiceBindingList{}
-	if err := client.NewNamespacedClient(r.Client, serviceInstance.Namespace).List(
+	if err := indexReader(r.CacheReader, r.Client).List(
 		ctx,
 		serviceBindingList,
-		client.MatchingLabels{cfv1alpha1.LabelKeyServiceInstance: serviceInstance.Name},
+		client.InNamespace(serviceInstance.Namespace),
+		client.MatchingFields{indexServiceInstanceName: serviceInstance.Name},
 	); err != nil {
 		return ctrl.Result{}, errors.Wrap(err, "failed to list depending service bindings")
 	}
+
+	// Find depending route bindings
+	routeBindingList := &cfv1alpha1.RouteBindingList{}
+	if err := indexReader(r.CacheReader, r.Client).List(
+		ctx,
+		routeBindingList,
+		client.InNamespace(serviceInstance.Namespace),
+		client.MatchingFields{indexServiceInstanceName: serviceInstance.Name},
+	); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to list depending route bindings")
+	}
+
+	// Find depending cluster service bindings
+	clusterServiceBindingList := &cfv1alpha1.ClusterServiceBindingList{}
+	if err := indexReader(r.CacheReader, r.Client).List(
+		ctx,
+		clusterServiceBindingList,
+		client.MatchingFields{indexClusterServiceBindingServiceInstance: serviceInstanceRefKey(serviceInstance.Namespace, serviceInstance.Name)},
+	); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to list depending cluster service bindings")
+	}
 	// Retrieve reconcileTimeout
 	reconcileTimeout := getReconcileTimeout(serviceInstance)
 
 	// Require readiness of space unless in deletion case
 	if serviceInstance.DeletionTimestamp.IsZero() {
 		if !space.IsReady() {
-			serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceInstanceReadyConditionReasonSpaceNotReady,
-				fmt.Sprintf("Referenced %s is not ready, name: %s", space.GetKind(), space.GetName()))
+			serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceInstanceReadyConditionReasonSpaceNotReady, getSpaceNotReadyMessage(space))
 			// TODO: apply some increasing period, depending on the age of the last update
 			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 		}
@@ -180,14 +297,32 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 
 	// Build cloud foundry client
+	endpoint := getEndpoint(space, spaceSecret, annotations)
+	status.Endpoint = endpoint
 	var client facade.SpaceClient
 	if spaceGuid != "" {
-		client, err = r.ClientBuilder(spaceGuid, string(spaceSecret.Data["url"]), string(spaceSecret.Data["username"]), string(spaceSecret.Data["password"]))
+		client, err = r.ClientBuilder(spaceGuid, endpoint, string(spaceSecret.Data["username"]), string(spaceSecret.Data["password"]), getClientConfig(getSpaceConfig(r.Config, space), spaceSecret))
 		if err != nil {
 			return ctrl.Result{}, errors.Wrapf(err, "failed to build the client from secret %s", spaceSecretName)
 		}
 	}
 
+	// In observe-only mode, the cloud foundry instance is only read; nothing is created, updated or deleted
+	if isObserveOnly(r.Config, serviceInstance.Annotations) {
+		if !serviceInstance.DeletionTimestamp.IsZero() {
+			// the cloud foundry instance is left untouched; just release the object (if it was managed before)
+			if containsString(serviceInstance.Finalizers, serviceInstanceFinalizer) {
+				controllerutil.RemoveFinalizer(serviceInstance, serviceInstanceFinalizer)
+				if err := r.Update(ctx, serviceInstance); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			skipStatusUpdate = true
+			return ctrl.Result{}, nil
+		}
+		return r.observeInstance(ctx, serviceInstance, annotations, client, spaceGuid)
+	}
+
 	// Retrieve cloud foundry instance
 	var cfinstance *facade.Instance
 	instanceOpts := map[string]string{"name": "", "owner": string(serviceInstance.UID)}
@@ -197,8 +332,40 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		if err != nil {
 			return ctrl.Result{}, err
 		}
+		// In dry-run mode, the changes which would be applied to the cloud foundry instance are only reported in the status
+		if serviceInstance.Annotations[cfv1alpha1.AnnotationDryRun] == "true" {
+			return r.planInstance(ctx, serviceInstance, annotations, client, cfinstance, spaceGuid)
+		}
+		status.PendingChanges = nil
 		orphan, exists := serviceInstance.Annotations[cfv1alpha1.AnnotationAdoptCFResources]
-		if exists && cfinstance == nil && orphan == "adopt" {
+		adoptGuid := serviceInstance.Annotations[cfv1alpha1.AnnotationAdoptCFInstanceGuid]
+		adoptLabelSelector := serviceInstance.Annotations[cfv1alpha1.AnnotationAdoptCFLabelSelector]
+		if cfinstance == nil && adoptGuid == "" && adoptLabelSelector != "" {
+			// find instance created by another tool by label selector; the lookup is restricted to the space of the service instance
+			instanceOpts["labelSelector"] = adoptLabelSelector
+			log.V(1).Info("Retrieving instance by label selector", "labelSelector", adoptLabelSelector)
+			cfinstance, err = client.GetInstance(ctx, instanceOpts)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if cfinstance == nil {
+				return ctrl.Result{}, fmt.Errorf("service instance to be adopted not found in space %s, label selector: %s", spaceGuid, adoptLabelSelector)
+			}
+		} else if cfinstance == nil && adoptGuid != "" {
+			// find orphaned instance by guid; the lookup is restricted to the space of the service instance
+			instanceOpts["guid"] = adoptGuid
+			log.V(1).Info("Retrieving instance by guid", "guid", adoptGuid)
+			cfinstance, err = client.GetInstance(ctx, instanceOpts)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if cfinstance == nil {
+				return ctrl.Result{}, fmt.Errorf("service instance to be adopted not found in space %s, guid: %s", spaceGuid, adoptGuid)
+			}
+			if err := checkAdoptable(ctx, r.Client, "service instance", cfinstance.Guid, cfinstance.Owner, serviceInstance); err != nil {
+				return ctrl.Result{}, err
+			}
+		} else if exists && cfinstance == nil && orphan == "adopt" {
 			// find orphaned instance by name
 			instanceOpts["name"] = serviceInstance.Name
 			log.V(1).Info("Retrieving instance by name")
@@ -206,7 +373,8 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 			if err != nil {
 				return ctrl.Result{}, err
 			}
-
+		}
+		if cfinstance != nil && (instanceOpts["name"] != "" || instanceOpts["guid"] != "" || instanceOpts["labelSelector"] != "") {
 			//Add parameters to adopt the orphaned instance
 			var parameterObjects []map[string]interface{}
 			paramMap := make(map[string]interface{})
@@ -217,15 +385,21 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 			if err != nil {
 				return ctrl.Result{}, errors.Wrap(err, "failed to unmarshal/merge parameters")
 			}
+			metadata, err := getAdoptionMetadata(instanceOpts["labelSelector"])
+			if err != nil {
+				return ctrl.Result{}, err
+			}
 			// update the orphaned cloud foundry instance
 			log.V(1).Info("Updating instance")
 			if err := client.UpdateInstance(
 				ctx,
 				cfinstance.Guid,
+				cfinstance.OwnerRef(),
 				spec.Name,
 				"",
 				parameters,
 				nil,
+				metadata,
 				serviceInstance.Generation,
 			); err != nil {
 				return ctrl.Result{}, err
@@ -255,37 +429,9 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 			}
 		}
 
-		var parameterObjects []map[string]interface{}
-		if spec.Parameters != nil {
-			obj, err := unmarshalObject(spec.Parameters.Raw)
-			if err != nil {
-				return ctrl.Result{}, errors.Wrap(err, "error decoding inline parameters")
-			}
-			parameterObjects = append(parameterObjects, obj)
-		}
-		for _, pf := range spec.ParametersFrom {
-			secretName := types.NamespacedName{
-				Namespace: serviceInstance.Namespace,
-				Name:      pf.SecretKeyRef.Name,
-			}
-			secret := &corev1.Secret{}
-			if err := r.Get(ctx, secretName, secret); err != nil {
-				return ctrl.Result{}, errors.Wrapf(err, "failed to get Secret containing service instance parameters, secret name: %s", secretName)
-			}
-			if raw, ok := secret.Data[pf.SecretKeyRef.Key]; ok {
-				obj, err := unmarshalObject(raw)
-				if err != nil {
-					return ctrl.Result{}, errors.Wrapf(err, "error decoding parameters from secret, secret name: %s, key: %s", secretName, pf.SecretKeyRef.Key)
-				}
-				parameterObjects = append(parameterObjects, obj)
-			} else {
-				return ctrl.Result{}, fmt.Errorf("secret key not found, secret name: %s, key: %s", secretName, pf.SecretKeyRef.Key)
-			}
-		}
-
-		parameters, err := mergeObjects(parameterObjects...)
+		parameters, err := r.getParameters(ctx, serviceInstance)
 		if err != nil {
-			return ctrl.Result{}, errors.Wrap(err, "failed to unmarshal/merge parameters")
+			return ctrl.Result{}, err
 		}
 
 		status.ServiceInstanceDigest = facade.ObjectHash(map[string]interface{}{"generation": serviceInstance.Generation, "parameters": parameters})
@@ -294,33 +440,58 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		inRecreation := false
 
 		if cfinstance == nil {
+			if !isAdoptionRequested(serviceInstance) {
+				conflicting, err := findNameConflict(ctx, client, spec.Name)
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+				if conflicting != nil {
+					return r.rejectName(serviceInstance, conflicting, spaceGuid), nil
+				}
+			}
+			serviceInstance.RemoveCondition(cfv1alpha1.ServiceInstanceConditionNameConflict)
+			schemas, err := getServicePlanSchemas(ctx, client, servicePlanGuid, spaceGuid)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if err := validateParameters(schemas.InstanceCreate, parameters); err != nil {
+				return r.rejectParameters(serviceInstance, err), nil
+			}
 			log.V(1).Info("Creating instance")
-			if err := client.CreateInstance(
+			cfinstance, err = client.CreateInstance(
 				ctx,
 				spec.Name,
 				servicePlanGuid,
 				parameters,
 				spec.Tags,
-				string(serviceInstance.UID),
+				getCFMetadata(spec.Metadata, false),
+				ownerRefOf(serviceInstance),
 				serviceInstance.Generation,
-			); err != nil {
-				return ctrl.Result{}, RetryError
+			)
+			if err != nil {
+				reason, message := describeError(err, conditionReasonError)
+				serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionSynced, cfv1alpha1.ConditionFalse, reason, message)
+				status.LastCFError = getCFError(err)
+				return ctrl.Result{}, retryError(err)
 			}
 			status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
+			recordParameterChange(serviceInstance, facade.ObjectHash(parameters))
 		} else {
 			if cfinstance.State == facade.InstanceStateDeleting {
 				// This is the re-creation case; nothing to, we just wait until it is gone
 			} else if recreateOnCreationFailure && (cfinstance.State == facade.InstanceStateCreatedFailed || cfinstance.State == facade.InstanceStateDeleteFailed) {
 				// Re-create instance
 				log.V(1).Info("Deleting instance for later re-creation")
-				if err := client.DeleteInstance(ctx, cfinstance.Guid); err != nil {
-					return ctrl.Result{}, RetryError
+				if err := client.DeleteInstance(ctx, cfinstance.Guid, cfinstance.OwnerRef()); err != nil {
+					status.LastCFError = getCFError(err)
+					return ctrl.Result{}, retryError(err)
 				}
 				status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
 				inRecreation = true
 				// Clear instance, so it will be re-read below
 				cfinstance = nil
 			} else if cfinstance.Generation < serviceInstance.Generation || cfinstance.ParameterHash != facade.ObjectHash(parameters) ||
+				cfinstance.Name != spec.Name || cfinstance.TagsHash != facade.TagsHash(spec.Tags) ||
 				cfinstance.State == facade.InstanceStateCreatedFailed || cfinstance.State == facade.InstanceStateUpdateFailed {
 				log.V(1).Info("Updating instance")
 				updateName := spec.Name
@@ -330,8 +501,21 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 				updateServicePlanGuid := servicePlanGuid
 				if updateServicePlanGuid == cfinstance.ServicePlanGuid {
 					updateServicePlanGuid = ""
+				} else if !spec.AllowPlanChange {
+					// plan changes may be destructive for some brokers, so they are only sent if explicitly allowed
+					log.V(1).Info("Skipping service plan change (not allowed)", "servicePlanGuid", servicePlanGuid)
+					updateServicePlanGuid = ""
 				}
 				updateParameters := parameters
+				if cfinstance.ParameterHash != facade.ObjectHash(parameters) {
+					schemas, err := getServicePlanSchemas(ctx, client, servicePlanGuid, spaceGuid)
+					if err != nil {
+						return ctrl.Result{}, err
+					}
+					if err := validateParameters(schemas.InstanceUpdate, updateParameters); err != nil {
+						return r.rejectParameters(serviceInstance, err), nil
+					}
+				}
 				// note: actually it would be best to pass an empty map (instead of nil) to the update call,
 				// in the case that no parameters have been defined in spec (neither spec.Parameters, nor spec.ParametersFrom);
 				// because then parameters would be cleared in the cloud foundry instance, which would match the expected behavior;
@@ -349,22 +533,36 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 				if err := client.UpdateInstance(
 					ctx,
 					cfinstance.Guid,
+					withOwnerObject(cfinstance.OwnerRef(), serviceInstance),
 					updateName,
 					updateServicePlanGuid,
 					updateParameters,
 					updateTags,
+					getCFMetadata(spec.Metadata, cfinstance.CustomMetadata),
 					serviceInstance.Generation,
 				); err != nil {
 					return ctrl.Result{}, err
 				}
 				status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
+				if updateParameters != nil {
+					recordParameterChange(serviceInstance, facade.ObjectHash(updateParameters))
+				}
+				// Clear instance, so it will be re-read below
+				cfinstance = nil
+			} else if upgrade := getRequestedUpgrade(serviceInstance, cfinstance); upgrade != nil {
+				log.V(1).Info("Upgrading instance", "version", upgrade.Version)
+				if err := client.UpgradeInstance(ctx, cfinstance.Guid, cfinstance.OwnerRef(), *upgrade); err != nil {
+					return ctrl.Result{}, err
+				}
+				r.Recorder.Eventf(serviceInstance, corev1.EventTypeNormal, serviceInstanceEventReasonUpgrading, "Applying maintenance upgrade to version %s", upgrade.Version)
+				status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
 				// Clear instance, so it will be re-read below
 				cfinstance = nil
 			}
 		}
 
 		if cfinstance == nil {
-			// Re-retrieve cloud foundry instance by UID; this happens exactly if the instance was created or updated above
+			// Re-retrieve cloud foundry instance by UID; this happens exactly if the instance was updated, upgraded or deleted above
 			log.V(1).Info("Retrieving instance")
 			cfinstance, err = client.GetInstance(ctx, instanceOpts)
 			if err != nil {
@@ -380,34 +578,63 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		}
 
 		// Update status
+		serviceInstance.RemoveCondition(cfv1alpha1.ServiceInstanceConditionNameConflict)
 		status.SpaceGuid = spaceGuid
 		status.ServicePlanGuid = servicePlanGuid
 		status.ServiceInstanceGuid = cfinstance.Guid
+		planChangeBlocked := updatePlanChangeBlockedCondition(serviceInstance, cfinstance, servicePlanGuid)
+		if planChangeBlocked {
+			status.ServicePlanGuid = cfinstance.ServicePlanGuid
+		}
+		r.updateAvailableUpgrade(serviceInstance, cfinstance)
+		status.LastOperation = getLastOperation(status.LastOperation, cfinstance.LastOperation)
+		r.updateStalledCondition(serviceInstance, cfinstance, annotations)
 		switch cfinstance.State {
 		case facade.InstanceStateReady:
 			serviceInstance.SetReadyCondition(cfv1alpha1.ConditionTrue, string(cfinstance.State), cfinstance.StateDescription)
+			if planChangeBlocked {
+				serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionSynced, cfv1alpha1.ConditionFalse, serviceInstancePlanChangeBlockedConditionReasonNotAllowed, "Service plan change is not applied, since spec.allowPlanChange is false")
+			} else {
+				serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionSynced, cfv1alpha1.ConditionTrue, conditionReasonSynced, "Cloud Foundry instance reflects the current spec")
+			}
 			serviceInstance.Status.RetryCounter = 0 // Reset the retry counter
-			return getPollingInterval(serviceInstance.GetAnnotations(), "10m", cfv1alpha1.AnnotationPollingIntervalReady), nil
+			r.updateParameterDriftCondition(ctx, serviceInstance, client, cfinstance, parameters, annotations)
+			return getPollingInterval(annotations, getDefaultPollingIntervalReady(r.Config, "ServiceInstance"), cfv1alpha1.AnnotationPollingIntervalReady), nil
 		case facade.InstanceStateCreatedFailed, facade.InstanceStateUpdateFailed, facade.InstanceStateDeleteFailed:
+			serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionSynced, cfv1alpha1.ConditionFalse, string(cfinstance.State), cfinstance.StateDescription)
 			// Check if the retry counter exceeds the maximum allowed retries.
 			// Check if the maximum retry limit is exceeded.
 			return ctrl.Result{}, RetryError
 		default:
 			// Processing case
 			serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, string(cfinstance.State), cfinstance.StateDescription)
+			serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionSynced, cfv1alpha1.ConditionUnknown, string(cfinstance.State), cfinstance.StateDescription)
 			// TODO: apply some increasing period, depending on the age of the last update
 			return ctrl.Result{RequeueAfter: reconcileTimeout}, nil
 		}
 	} else if len(serviceBindingList.Items) > 0 {
 		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceInstanceReadyConditionReasonDeletionBlocked, "Waiting for deletion of depending service bindings")
+		serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionDeletionBlocked, cfv1alpha1.ConditionTrue, conditionReasonDependentsExist, "Waiting for deletion of depending service bindings")
+		// TODO: apply some increasing period, depending on the age of the last update
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	} else if len(routeBindingList.Items) > 0 {
+		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceInstanceReadyConditionReasonDeletionBlocked, "Waiting for deletion of depending route bindings")
+		serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionDeletionBlocked, cfv1alpha1.ConditionTrue, conditionReasonDependentsExist, "Waiting for deletion of depending route bindings")
+		// TODO: apply some increasing period, depending on the age of the last update
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	} else if len(clusterServiceBindingList.Items) > 0 {
+		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceInstanceReadyConditionReasonDeletionBlocked, "Waiting for deletion of depending cluster service bindings")
+		serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionDeletionBlocked, cfv1alpha1.ConditionTrue, conditionReasonDependentsExist, "Waiting for deletion of depending cluster service bindings")
 		// TODO: apply some increasing period, depending on the age of the last update
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 	} else if len(removeString(serviceInstance.Finalizers, serviceInstanceFinalizer)) > 0 {
 		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceInstanceReadyConditionReasonDeletionBlocked, "Deletion blocked due to foreign finalizers")
+		serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionDeletionBlocked, cfv1alpha1.ConditionTrue, conditionReasonForeignFinalizers, "Deletion blocked due to foreign finalizers")
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 		// TODO: apply some increasing period, depending on the age of the last update
 	} else {
 		// Deletion case
+		serviceInstance.RemoveCondition(cfv1alpha1.ServiceInstanceConditionDeletionBlocked)
 		if cfinstance == nil {
 			if containsString(serviceInstance.Finalizers, serviceInstanceFinalizer) {
 				controllerutil.RemoveFinalizer(serviceInstance, serviceInstanceFinalizer)
@@ -422,8 +649,19 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 			return ctrl.Result{}, nil
 		} else {
 			if cfinstance.State != facade.InstanceStateDeleting {
+				if isDeletionProtected(serviceInstance.Annotations) {
+					message := fmt.Sprintf("Deletion of the Cloud Foundry instance is blocked by annotation %s (remove the annotation to proceed)", cfv1alpha1.AnnotationDeletionProtection)
+					log.V(1).Info("Not deleting instance due to deletion protection")
+					serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceInstanceReadyConditionReasonDeletionBlocked, message)
+					serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionDeletionBlocked, cfv1alpha1.ConditionTrue, serviceInstanceDeletionBlockedConditionReasonProtected, message)
+					// removing the annotation triggers a reconcile
+					return ctrl.Result{}, nil
+				}
+				if proceed, result, err := r.runPreDeleteHook(ctx, serviceInstance); !proceed {
+					return result, err
+				}
 				log.V(1).Info("Deleting instance")
-				if err := client.DeleteInstance(ctx, cfinstance.Guid); err != nil {
+				if err := client.DeleteInstance(ctx, cfinstance.Guid, cfinstance.OwnerRef()); err != nil {
 					return ctrl.Result{}, err
 				}
 				status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
@@ -437,12 +675,331 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 }
 
+// observeInstance reflects the state of the cloud foundry instance in the status of the given service instance, without changing anything
+// (observe-only mode); the instance is looked up by owner, or - if not (yet) owned - by the guid given by the adopt-cf-instance-guid annotation,
+// by the label selector given by the adopt-cf-label-selector annotation, or by name.
+func (r *ServiceInstanceReconciler) observeInstance(ctx context.Context, serviceInstance *cfv1alpha1.ServiceInstance, annotations map[string]string, client facade.SpaceClient, spaceGuid string) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	status := &serviceInstance.Status
+
+	log.V(1).Info("Retrieving instance (observe-only)")
+	instanceOpts := map[string]string{"name": "", "owner": string(serviceInstance.UID)}
+	cfinstance, err := client.GetInstance(ctx, instanceOpts)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if cfinstance == nil {
+		if guid := serviceInstance.Annotations[cfv1alpha1.AnnotationAdoptCFInstanceGuid]; guid != "" {
+			instanceOpts["guid"] = guid
+		} else if labelSelector := serviceInstance.Annotations[cfv1alpha1.AnnotationAdoptCFLabelSelector]; labelSelector != "" {
+			instanceOpts["labelSelector"] = labelSelector
+		} else {
+			instanceOpts["name"] = serviceInstance.Spec.Name
+		}
+		cfinstance, err = client.GetInstance(ctx, instanceOpts)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionSynced, cfv1alpha1.ConditionUnknown, conditionReasonObserveOnly, "Changes are not applied in observe-only mode")
+
+	if cfinstance == nil {
+		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionFalse, readyConditionReasonNotFound, "Cloud Foundry instance not found (observe-only mode; it will not be created)")
+		return getPollingInterval(annotations, getDefaultPollingIntervalReady(r.Config, "ServiceInstance"), cfv1alpha1.AnnotationPollingIntervalReady), nil
+	}
+	status.SpaceGuid = spaceGuid
+	status.ServicePlanGuid = cfinstance.ServicePlanGuid
+	status.ServiceInstanceGuid = cfinstance.Guid
+	r.updateAvailableUpgrade(serviceInstance, cfinstance)
+	status.LastOperation = getLastOperation(status.LastOperation, cfinstance.LastOperation)
+	r.updateStalledCondition(serviceInstance, cfinstance, annotations)
+	switch cfinstance.State {
+	case facade.InstanceStateReady:
+		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionTrue, string(cfinstance.State), cfinstance.StateDescription)
+	case facade.InstanceStateCreatedFailed, facade.InstanceStateUpdateFailed, facade.InstanceStateDeleteFailed:
+		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionFalse, string(cfinstance.State), cfinstance.StateDescription)
+	default:
+		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, string(cfinstance.State), cfinstance.StateDescription)
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	return getPollingInterval(annotations, getDefaultPollingIntervalReady(r.Config, "ServiceInstance"), cfv1alpha1.AnnotationPollingIntervalReady), nil
+}
+
+// rejectParameters reports that the parameters of the given service instance do not match the schema published by the service broker;
+// the instance is not created or updated in that case (instead of letting the broker fail asynchronously).
+func (r *ServiceInstanceReconciler) rejectParameters(serviceInstance *cfv1alpha1.ServiceInstance, err error) ctrl.Result {
+	serviceInstance.SetReadyCondition(cfv1alpha1.ConditionFalse, readyConditionReasonInvalidParameters, err.Error())
+	serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionSynced, cfv1alpha1.ConditionFalse, readyConditionReasonInvalidParameters, err.Error())
+	r.Recorder.Event(serviceInstance, corev1.EventTypeWarning, serviceInstanceEventReasonInvalidParameters, err.Error())
+	return ctrl.Result{RequeueAfter: invalidParametersRequeueInterval}
+}
+
+// isAdoptionRequested returns whether the given service instance requests the adoption of an existing Cloud Foundry instance
+// (through one of the annotations adopt-cf-resources, adopt-cf-instance-guid or adopt-cf-label-selector).
+func isAdoptionRequested(serviceInstance *cfv1alpha1.ServiceInstance) bool {
+	annotations := serviceInstance.GetAnnotations()
+	return annotations[cfv1alpha1.AnnotationAdoptCFResources] == "adopt" ||
+		annotations[cfv1alpha1.AnnotationAdoptCFInstanceGuid] != "" || annotations[cfv1alpha1.AnnotationAdoptCFLabelSelector] != ""
+}
+
+// findNameConflict returns the Cloud Foundry instance with the given name in the space of the client (nil if there is none);
+// it is called before an instance is created, that is, if no instance is owned by the service instance, so a returned instance
+// is owned by another object (or was not created by the operator), and the creation would be rejected by Cloud Foundry.
+func findNameConflict(ctx context.Context, client facade.SpaceClient, name string) (*facade.InstanceName, error) {
+	ctrl.LoggerFrom(ctx).V(1).Info("Checking instance name", "name", name)
+	return client.FindInstanceName(ctx, name)
+}
+
+// rejectName reports that the given service instance cannot be created, because the given (conflicting) Cloud Foundry instance
+// has the specified name already; the conflict is checked again periodically.
+func (r *ServiceInstanceReconciler) rejectName(serviceInstance *cfv1alpha1.ServiceInstance, conflicting *facade.InstanceName, spaceGuid string) ctrl.Result {
+	message := fmt.Sprintf("Cloud Foundry instance %s (guid %s) exists in space %s, and is not owned by this service instance; "+
+		"change spec.name, or set annotation %s to adopt it", conflicting.Name, conflicting.Guid, spaceGuid, cfv1alpha1.AnnotationAdoptCFInstanceGuid)
+	reason := serviceInstanceNameConflictConditionReasonNameTaken
+	if condition := serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionNameConflict); condition == nil || condition.Status != cfv1alpha1.ConditionTrue {
+		r.Recorder.Event(serviceInstance, corev1.EventTypeWarning, serviceInstanceEventReasonNameConflict, message)
+	}
+	serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionNameConflict, cfv1alpha1.ConditionTrue, reason, message)
+	serviceInstance.SetReadyCondition(cfv1alpha1.ConditionFalse, reason, message)
+	serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionSynced, cfv1alpha1.ConditionFalse, reason, message)
+	return ctrl.Result{RequeueAfter: serviceInstanceNameConflictRequeueInterval}
+}
+
+// planInstance determines the changes which would be applied to the given cloud foundry instance (which may be nil), and reports them
+// in the status of the given service instance, without changing anything (dry-run mode); besides the instance itself, the service plan
+// and the parameters are read, such that errors (e.g. missing parameter secrets) surface as they would in a regular reconciliation.
+func (r *ServiceInstanceReconciler) planInstance(ctx context.Context, serviceInstance *cfv1alpha1.ServiceInstance, annotations map[string]string, client facade.SpaceClient, cfinstance *facade.Instance, spaceGuid string) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	spec := &serviceInstance.Spec
+	status := &serviceInstance.Status
+
+	var servicePlanGuid string
+	var parameters map[string]interface{}
+	if serviceInstance.DeletionTimestamp.IsZero() {
+		servicePlanGuid = spec.ServicePlanGuid
+		if servicePlanGuid == "" {
+			log.V(1).Info("Searching service plan (dry-run)")
+			var err error
+			servicePlanGuid, err = client.FindServicePlan(ctx, spec.ServiceOfferingName, spec.ServicePlanName, spaceGuid)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		var err error
+		parameters, err = r.getParameters(ctx, serviceInstance)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	status.PendingChanges = computePendingChanges(serviceInstance, cfinstance, servicePlanGuid, parameters)
+	status.PendingChanges.ComputedAt = &[]metav1.Time{metav1.Now()}[0]
+	operation := status.PendingChanges.Operation
+	log.V(1).Info("Determined pending changes (dry-run)", "operation", operation, "changes", status.PendingChanges.Changes)
+
+	if cfinstance != nil {
+		status.SpaceGuid = spaceGuid
+		status.ServicePlanGuid = cfinstance.ServicePlanGuid
+		status.ServiceInstanceGuid = cfinstance.Guid
+		r.updateAvailableUpgrade(serviceInstance, cfinstance)
+		status.LastOperation = getLastOperation(status.LastOperation, cfinstance.LastOperation)
+		r.updateStalledCondition(serviceInstance, cfinstance, annotations)
+	}
+	if operation == cfv1alpha1.PendingOperationNone {
+		serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionSynced, cfv1alpha1.ConditionTrue, conditionReasonSynced, "Cloud Foundry instance reflects the current spec")
+	} else {
+		serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionSynced, cfv1alpha1.ConditionFalse, conditionReasonDryRun, fmt.Sprintf("Pending operation %s is not applied in dry-run mode", operation))
+	}
+	if cfinstance != nil && cfinstance.State == facade.InstanceStateReady && operation == cfv1alpha1.PendingOperationNone {
+		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionTrue, string(cfinstance.State), cfinstance.StateDescription)
+	} else {
+		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, conditionReasonDryRun, fmt.Sprintf("Pending operation %s is not applied in dry-run mode", operation))
+	}
+	return getPollingInterval(annotations, getDefaultPollingIntervalReady(r.Config, "ServiceInstance"), cfv1alpha1.AnnotationPollingIntervalReady), nil
+}
+
+// computePendingChanges returns the operation which a regular reconciliation would perform on the given cloud foundry instance
+// (nil if not existing), following the same decisions as Reconcile; servicePlanGuid and parameters are ignored for deleted objects.
+func computePendingChanges(serviceInstance *cfv1alpha1.ServiceInstance, cfinstance *facade.Instance, servicePlanGuid string, parameters map[string]interface{}) *cfv1alpha1.PendingChanges {
+	pendingChanges := &cfv1alpha1.PendingChanges{Operation: cfv1alpha1.PendingOperationNone}
+	if cfinstance != nil {
+		pendingChanges.CurrentParameterHash = cfinstance.ParameterHash
+	}
+
+	if !serviceInstance.DeletionTimestamp.IsZero() {
+		if cfinstance != nil && cfinstance.State != facade.InstanceStateDeleting {
+			pendingChanges.Operation = cfv1alpha1.PendingOperationDelete
+		}
+		return pendingChanges
+	}
+
+	parameterHash := facade.ObjectHash(parameters)
+	pendingChanges.ParameterHash = parameterHash
+	recreateOnCreationFailure := serviceInstance.Annotations[cfv1alpha1.AnnotationRecreate] == "true"
+	switch {
+	case cfinstance == nil:
+		pendingChanges.Operation = cfv1alpha1.PendingOperationCreate
+	case cfinstance.State == facade.InstanceStateDeleting:
+		// instance is being re-created; nothing to do until it is gone
+	case recreateOnCreationFailure && (cfinstance.State == facade.InstanceStateCreatedFailed || cfinstance.State == facade.InstanceStateDeleteFailed):
+		pendingChanges.Operation = cfv1alpha1.PendingOperationRecreate
+	case cfinstance.Generation < serviceInstance.Generation || cfinstance.ParameterHash != parameterHash ||
+		cfinstance.Name != serviceInstance.Spec.Name || cfinstance.TagsHash != facade.TagsHash(serviceInstance.Spec.Tags) ||
+		cfinstance.State == facade.InstanceStateCreatedFailed || cfinstance.State == facade.InstanceStateUpdateFailed:
+		pendingChanges.Operation = cfv1alpha1.PendingOperationUpdate
+		if serviceInstance.Spec.Name != cfinstance.Name {
+			pendingChanges.Changes = append(pendingChanges.Changes, "name")
+		}
+		if servicePlanGuid != cfinstance.ServicePlanGuid && serviceInstance.Spec.AllowPlanChange {
+			pendingChanges.Changes = append(pendingChanges.Changes, "servicePlan")
+		}
+		if parameterHash != cfinstance.ParameterHash {
+			pendingChanges.Changes = append(pendingChanges.Changes, "parameters")
+		}
+		if facade.TagsHash(serviceInstance.Spec.Tags) != cfinstance.TagsHash {
+			pendingChanges.Changes = append(pendingChanges.Changes, "tags")
+		}
+		if cfinstance.Generation < serviceInstance.Generation {
+			pendingChanges.Changes = append(pendingChanges.Changes, "generation")
+		}
+	default:
+		if upgrade := getRequestedUpgrade(serviceInstance, cfinstance); upgrade != nil {
+			pendingChanges.Operation = cfv1alpha1.PendingOperationUpgrade
+			pendingChanges.UpgradeToVersion = upgrade.Version
+		}
+	}
+	return pendingChanges
+}
+
+// getParameters returns the parameters of the given service instance, merged from spec.parameters and spec.parametersFrom.
+func (r *ServiceInstanceReconciler) getParameters(ctx context.Context, serviceInstance *cfv1alpha1.ServiceInstance) (map[string]interface{}, error) {
+	return getParameters(ctx, r.Client, serviceInstance, serviceInstance.Spec.Parameters, serviceInstance.Spec.ParametersFrom, serviceInstance.Spec.ParametersMergeStrategy)
+}
+
+// updateAvailableUpgrade exposes the maintenance upgrade offered by the service broker (if any) in the status,
+// and emits an event whenever a new version becomes available.
+func (r *ServiceInstanceReconciler) updateAvailableUpgrade(serviceInstance *cfv1alpha1.ServiceInstance, cfinstance *facade.Instance) {
+	var availableUpgrade *cfv1alpha1.MaintenanceInfo
+	if cfinstance.UpgradeAvailable && cfinstance.AvailableMaintenanceInfo != nil {
+		availableUpgrade = &cfv1alpha1.MaintenanceInfo{
+			Version:     cfinstance.AvailableMaintenanceInfo.Version,
+			Description: cfinstance.AvailableMaintenanceInfo.Description,
+		}
+	}
+	previousUpgrade := serviceInstance.Status.AvailableUpgrade
+	if availableUpgrade != nil && (previousUpgrade == nil || previousUpgrade.Version != availableUpgrade.Version) {
+		message := fmt.Sprintf("Maintenance upgrade to version %s available", availableUpgrade.Version)
+		if availableUpgrade.Description != "" {
+			message += ": " + availableUpgrade.Description
+		}
+		r.Recorder.Event(serviceInstance, corev1.EventTypeNormal, serviceInstanceEventReasonUpgradeAvailable, message)
+	}
+	serviceInstance.Status.AvailableUpgrade = availableUpgrade
+}
+
+// updateStalledCondition sets the Stalled condition if the last operation on the Cloud Foundry instance has been in progress
+// for longer than the stalled operation timeout (emitting a warning event when the operation is first detected as stalled),
+// and removes the condition otherwise.
+func (r *ServiceInstanceReconciler) updateStalledCondition(serviceInstance *cfv1alpha1.ServiceInstance, cfinstance *facade.Instance, annotations map[string]string) {
+	timeout := getStalledOperationTimeout(r.Config, annotations)
+	lastOperation := cfinstance.LastOperation
+	if timeout == 0 || lastOperation == nil || lastOperation.State != "in progress" || lastOperation.CreatedAt.IsZero() || time.Since(lastOperation.CreatedAt) < timeout {
+		serviceInstance.RemoveCondition(cfv1alpha1.ServiceInstanceConditionStalled)
+		return
+	}
+	message := fmt.Sprintf("Operation %s has been in progress since %s (for longer than %s)", lastOperation.Type, lastOperation.CreatedAt.UTC().Format(time.RFC3339), timeout)
+	if condition := serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionStalled); condition == nil || condition.Status != cfv1alpha1.ConditionTrue {
+		r.Recorder.Event(serviceInstance, corev1.EventTypeWarning, serviceInstanceEventReasonStalled, message)
+		serviceInstanceStalledOperations.WithLabelValues(lastOperation.Type).Inc()
+	}
+	serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionStalled, cfv1alpha1.ConditionTrue, serviceInstanceStalledConditionReasonTimeout, message)
+}
+
+// updatePlanChangeBlockedCondition reports (through the PlanChangeBlocked condition) whether the given cloud foundry instance
+// has another service plan than the specified one (servicePlanGuid), which is not changed because spec.allowPlanChange is false;
+// returns true in that case.
+func updatePlanChangeBlockedCondition(serviceInstance *cfv1alpha1.ServiceInstance, cfinstance *facade.Instance, servicePlanGuid string) bool {
+	if servicePlanGuid == cfinstance.ServicePlanGuid || serviceInstance.Spec.AllowPlanChange {
+		serviceInstance.RemoveCondition(cfv1alpha1.ServiceInstanceConditionPlanChangeBlocked)
+		return false
+	}
+	message := fmt.Sprintf("Service plan %s of the Cloud Foundry instance differs from the specified service plan %s; set spec.allowPlanChange to apply the change", cfinstance.ServicePlanGuid, servicePlanGuid)
+	serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionPlanChangeBlocked, cfv1alpha1.ConditionTrue, serviceInstancePlanChangeBlockedConditionReasonNotAllowed, message)
+	return true
+}
+
+// updateParameterDriftCondition compares the parameters of the given (ready) cloud foundry instance, as reported by the service broker,
+// with the desired parameters, and reports drift through the ParameterDrift condition; the check is only performed if requested by
+// the check-parameter-drift annotation (since not all brokers support retrieving parameters), and the condition is removed otherwise.
+// The condition only names the drifted keys, not their values.
+func (r *ServiceInstanceReconciler) updateParameterDriftCondition(ctx context.Context, serviceInstance *cfv1alpha1.ServiceInstance, client facade.SpaceClient, cfinstance *facade.Instance, parameters map[string]interface{}, annotations map[string]string) {
+	if annotations[cfv1alpha1.AnnotationCheckParameterDrift] != "true" {
+		serviceInstance.RemoveCondition(cfv1alpha1.ServiceInstanceConditionParameterDrift)
+		return
+	}
+	live, err := client.GetInstanceParameters(ctx, cfinstance.Guid)
+	if err != nil {
+		serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionParameterDrift, cfv1alpha1.ConditionUnknown, serviceInstanceParameterDriftConditionReasonNotRetrievable, err.Error())
+		return
+	}
+	drifted, err := getDriftedParameters(parameters, live)
+	if err != nil {
+		serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionParameterDrift, cfv1alpha1.ConditionUnknown, serviceInstanceParameterDriftConditionReasonNotRetrievable, err.Error())
+		return
+	}
+	if len(drifted) == 0 {
+		serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionParameterDrift, cfv1alpha1.ConditionFalse, serviceInstanceParameterDriftConditionReasonInSync, "Parameters of the Cloud Foundry instance match the specified parameters")
+		return
+	}
+	message := fmt.Sprintf("Parameters of the Cloud Foundry instance differ from the specified parameters: %s", strings.Join(drifted, ", "))
+	if condition := serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionParameterDrift); condition == nil || condition.Status != cfv1alpha1.ConditionTrue {
+		r.Recorder.Event(serviceInstance, corev1.EventTypeWarning, serviceInstanceEventReasonParameterDrift, message)
+	}
+	serviceInstance.SetCondition(cfv1alpha1.ServiceInstanceConditionParameterDrift, cfv1alpha1.ConditionTrue, serviceInstanceParameterDriftConditionReasonDiffer, message)
+}
+
+// getRequestedUpgrade returns the maintenance upgrade to be applied to the given (ready) instance, or nil if there is none;
+// offered upgrades are applied if the upgrade policy is Auto, or if requested through the upgrade-to-version annotation.
+func getRequestedUpgrade(serviceInstance *cfv1alpha1.ServiceInstance, cfinstance *facade.Instance) *facade.MaintenanceInfo {
+	if !cfinstance.UpgradeAvailable || cfinstance.AvailableMaintenanceInfo == nil || cfinstance.State != facade.InstanceStateReady {
+		return nil
+	}
+	if serviceInstance.Spec.UpgradePolicy == cfv1alpha1.UpgradePolicyAuto ||
+		serviceInstance.Annotations[cfv1alpha1.AnnotationUpgradeToVersion] == cfinstance.AvailableMaintenanceInfo.Version {
+		return cfinstance.AvailableMaintenanceInfo
+	}
+	return nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ServiceInstanceReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	reconciler, options := limitConcurrency(traceReconciles(measureReconciles(labelReconciles(r, "ServiceInstance"), "ServiceInstance"), "ServiceInstance"), r.Config, endpointFromStatus(
+		mgr.GetCache(),
+		func() client.Object { return &cfv1alpha1.ServiceInstance{} },
+		func(obj client.Object) string { return obj.(*cfv1alpha1.ServiceInstance).Status.Endpoint },
+	))
+	reconciler, namespacePredicate := filterNamespaces(reconciler, mgr.GetClient(), r.Config)
+	if r.CacheReader == nil {
+		r.CacheReader = mgr.GetCache()
+	}
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&cfv1alpha1.ServiceInstance{}).
 		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})).
-		Complete(r)
+		WithEventFilter(namespacePredicate)
+	if err := addServiceInstanceDependentIndexes(mgr); err != nil {
+		return err
+	}
+	if r.EventBus != nil {
+		if err := mgr.GetFieldIndexer().IndexField(context.Background(), &cfv1alpha1.ServiceInstance{}, indexServiceInstanceSpaceGuid, indexServiceInstanceBySpaceGuid); err != nil {
+			return err
+		}
+		builder = builder.WatchesRawSource(&resourceEventSource{
+			bus:     r.EventBus,
+			filter:  isResourceEvent(events.ResourceTypeSpace, events.EventTypeDeleted),
+			mapFunc: serviceInstancesInSpace(mgr.GetCache()),
+		}, &handler.EnqueueRequestForObject{})
+	}
+	return builder.WithOptions(options).Complete(reconciler)
 }
 
 // HandleError sets conditions and the context to handle the error.
@@ -450,9 +1007,10 @@ func (r *ServiceInstanceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 // - retry after certain time interval
 // - doubling time interval for consecutive errors
 // - time interval is capped at a certain maximum value
-func (r *ServiceInstanceReconciler) HandleError(ctx context.Context, serviceInstance *cfv1alpha1.ServiceInstance, issue error, log logr.Logger) (ctrl.Result, error) {
+func (r *ServiceInstanceReconciler) HandleError(ctx context.Context, serviceInstance *cfv1alpha1.ServiceInstance, annotations map[string]string, issue error, log logr.Logger) (ctrl.Result, error) {
 	if issue != RetryError {
-		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceInstanceReadyConditionReasonError, issue.Error())
+		reason, message := describeError(issue, serviceInstanceReadyConditionReasonError)
+		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, reason, message)
 		return ctrl.Result{}, issue
 	}
 
@@ -463,7 +1021,7 @@ func (r *ServiceInstanceReconciler) HandleError(ctx context.Context, serviceInst
 	if serviceInstance.Status.MaxRetries != serviceInstanceDefaultMaxRetries && serviceInstance.Status.RetryCounter >= serviceInstance.Status.MaxRetries {
 		// Update the instance's status to reflect the failure due to too many retries.
 		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionFalse, "MaximumRetriesExceeded", "The service instance has failed due to too many retries.")
-		return getPollingInterval(serviceInstance.GetAnnotations(), "", cfv1alpha1.AnnotationPollingIntervalFail), nil // finish reconcile loop
+		return getPollingInterval(annotations, getDefaultPollingIntervalFail(r.Config, "ServiceInstance"), cfv1alpha1.AnnotationPollingIntervalFail), nil // finish reconcile loop
 	}
 	// double the requeue interval
 	condition := serviceInstance.GetReadyCondition()
@@ -482,6 +1040,11 @@ func (r *ServiceInstanceReconciler) HandleError(ctx context.Context, serviceInst
 
 	log.V(1).Info("Scheduling next reconcile", "RequeueAfter", requeueAfter.String())
 
-	serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceInstanceReadyConditionReasonError, issue.Error())
+	// if the attempt failed because of the Cloud Foundry API, the Synced condition describes that error
+	reason, message := serviceInstanceReadyConditionReasonError, issue.Error()
+	if synced := serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionSynced); serviceInstance.Status.LastCFError != nil && synced != nil {
+		reason, message = synced.Reason, synced.Message
+	}
+	serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, reason, message)
 	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
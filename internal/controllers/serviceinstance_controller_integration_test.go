@@ -13,6 +13,7 @@ import (
 	"github.com/sap/cf-service-operator/api/v1alpha1"
 	"github.com/sap/cf-service-operator/internal/facade"
 	"github.com/sap/cf-service-operator/internal/facade/facadefakes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -29,6 +30,7 @@ const (
 	testK8sInstNameStateDeleteFailed         = "test-instance-state-delete-failed"
 	testK8sInstNameStateDeleteFailedInfinite = "test-instance-state-delete-failed-infinite"
 	testK8sInstNameRecreateInfinite          = "test-instance-recreate-infinite"
+	testK8sInstNameDependentBinding          = "test-instance-dependent-binding"
 	testSpaceNameInstances                   = "test-space-instances" // used for K8s CR and CF space
 )
 
@@ -42,6 +44,16 @@ var fakeInstanceReady = &facade.Instance{
 	StateDescription: string(facade.InstanceStateReady),
 }
 
+// CreateInstance returns the instance as requested, in state Creating, and without guid (which is only known once the instance is read again)
+var fakeInstanceCreating = &facade.Instance{
+	Name:             testCfInstName,
+	ServicePlanGuid:  testCfPlanGuid,
+	Owner:            testCfOwner,
+	Generation:       1,
+	State:            facade.InstanceStateCreating,
+	StateDescription: string(facade.InstanceStateCreating),
+}
+
 // -----------------------------------------------------------------------------------------------
 // Tests
 // -----------------------------------------------------------------------------------------------
@@ -62,7 +74,7 @@ var _ = Describe("Service Instance Controller Integration Tests", Ordered, func(
 			Generation: 1,
 		}
 
-		fakeOrgClient.CreateSpaceReturns(nil)
+		fakeOrgClient.CreateSpaceReturns(fakeSpace, nil)
 		fakeOrgClient.GetSpaceReturns(fakeSpace, nil)
 		// only the first call returns no resource to force the creation by the controller
 		fakeOrgClient.GetSpaceReturnsOnCall(0, nil, nil)
@@ -84,10 +96,10 @@ var _ = Describe("Service Instance Controller Integration Tests", Ordered, func(
 		It("should create instance", func() {
 			// prepare fake CF responses
 			fakeSpaceClient.FindServicePlanReturns(testCfPlanGuid, kNoError)
-			fakeSpaceClient.CreateInstanceReturns(kNoError)
+			fakeSpaceClient.CreateInstanceReturns(fakeInstanceCreating, kNoError)
 
 			// 0) GetInstance is called before CreateInstance to check existence => simulate non-existing instance
-			// 1) GetInstance is called after CreateInstance to check current state => simulate ready instance
+			// 1) GetInstance is called by the next reconcile (CreateInstance returns a creating instance) => simulate ready instance
 			fakeSpaceClient.GetInstanceReturnsOnCall(0, kNoInstance, kNoError)
 			fakeSpaceClient.GetInstanceReturns(fakeInstanceReady, kNoError)
 
@@ -108,7 +120,7 @@ var _ = Describe("Service Instance Controller Integration Tests", Ordered, func(
 			fakeInstanceFailed.StateDescription = string(facade.InstanceStateCreatedFailed)
 			fakeSpaceClient.FindServicePlanReturns(testCfPlanGuid, kNoError)
 			fakeSpaceClient.DeleteInstanceReturns(kNoError)
-			fakeSpaceClient.CreateInstanceReturns(kNoError)
+			fakeSpaceClient.CreateInstanceReturns(fakeInstanceCreating, kNoError)
 
 			// 0) simulate failed instance to force deletion by controller
 			fakeSpaceClient.GetInstanceReturnsOnCall(0, &fakeInstanceFailed, kNoError)
@@ -144,7 +156,7 @@ var _ = Describe("Service Instance Controller Integration Tests", Ordered, func(
 			fakeSpaceClient.DeleteInstanceReturns(kNoError)
 
 			// CreateInstance shall always fail directly
-			fakeSpaceClient.CreateInstanceReturns(errCreateInstanceFail)
+			fakeSpaceClient.CreateInstanceReturns(kNoInstance, errCreateInstanceFail)
 
 			// GetInstance shall return errors except for below cases
 			fakeSpaceClient.GetInstanceReturns(kNoInstance, errNotExpected)
@@ -198,7 +210,7 @@ var _ = Describe("Service Instance Controller Integration Tests", Ordered, func(
 			fakeSpaceClient.DeleteInstanceReturns(kNoError)
 
 			// CreateInstance shall always succeed, but the instance shall go to CreatedFailed state later on
-			fakeSpaceClient.CreateInstanceReturns(kNoError)
+			fakeSpaceClient.CreateInstanceReturns(fakeInstanceCreating, kNoError)
 
 			// GetInstance shall always return instance in state CreatedFailed
 			fakeSpaceClient.GetInstanceReturns(kNoInstance, errNotExpected)
@@ -253,7 +265,7 @@ var _ = Describe("Service Instance Controller Integration Tests", Ordered, func(
 			fakeSpaceClient.DeleteInstanceReturns(errDeleteInstanceFail)
 
 			// CreateInstance shall always succeed, but the instance shall go to DeleteFailed state later on
-			fakeSpaceClient.CreateInstanceReturns(kNoError)
+			fakeSpaceClient.CreateInstanceReturns(fakeInstanceCreating, kNoError)
 
 			// GetInstance shall always return instance in state DeleteFailed
 			fakeSpaceClient.GetInstanceReturns(&fakeInstanceFailed, kNoError)
@@ -281,7 +293,7 @@ var _ = Describe("Service Instance Controller Integration Tests", Ordered, func(
 			fakeSpaceClient.DeleteInstanceReturns(kNoError)
 
 			// CreateInstance shall always succeed, but the instance shall go to DeleteFailed state later on
-			fakeSpaceClient.CreateInstanceReturns(kNoError)
+			fakeSpaceClient.CreateInstanceReturns(fakeInstanceCreating, kNoError)
 
 			for i := 0; i <= 3; i++ {
 				fakeSpaceClient.GetInstanceReturnsOnCall(i, &fakeInstanceFailed, kNoError)
@@ -315,7 +327,7 @@ var _ = Describe("Service Instance Controller Integration Tests", Ordered, func(
 			fakeSpaceClient.DeleteInstanceReturns(kNoError)
 
 			// CreateInstance shall always fail directly
-			fakeSpaceClient.CreateInstanceReturns(errCreateInstanceFail)
+			fakeSpaceClient.CreateInstanceReturns(kNoInstance, errCreateInstanceFail)
 
 			// GetInstance shall return errors except for below cases
 			fakeSpaceClient.GetInstanceReturns(kNoInstance, errNotExpected)
@@ -325,7 +337,7 @@ var _ = Describe("Service Instance Controller Integration Tests", Ordered, func(
 			}
 
 			//simulate ready instance to finish the test
-			fakeSpaceClient.CreateInstanceReturnsOnCall(4, kNoError)
+			fakeSpaceClient.CreateInstanceReturnsOnCall(4, fakeInstanceCreating, kNoError)
 			fakeSpaceClient.GetInstanceReturnsOnCall(7, fakeInstanceReady, kNoError)
 
 			// perform actual test
@@ -338,7 +350,38 @@ var _ = Describe("Service Instance Controller Integration Tests", Ordered, func(
 			Expect(fakeSpaceClient.DeleteInstanceCallCount()).To(Equal(1))
 			Expect(fakeSpaceClient.CreateInstanceCallCount()).To(Equal(5))
 			Expect(fakeSpaceClient.GetInstanceCallCount()).To(Equal(8))
+		})
+
+		It("should wait for depending service bindings before deleting instance", func() {
+			// prepare fake CF responses
+			fakeSpaceClient.FindServicePlanReturns(testCfPlanGuid, kNoError)
+			fakeSpaceClient.CreateInstanceReturns(fakeInstanceCreating, kNoError)
+			fakeSpaceClient.GetInstanceReturnsOnCall(0, kNoInstance, kNoError)
+			fakeSpaceClient.GetInstanceReturns(fakeInstanceReady, kNoError)
+
+			infinite := false
+			instanceCR := createInstanceCR(ctx, testK8sInstNameDependentBinding, testSpaceNameInstances, infinite)
+			instanceKey := client.ObjectKeyFromObject(instanceCR)
+			waitForInstanceCR(ctx, instanceKey)
+
+			// the depending binding is looked up by field index, which the API server itself does not support
+			bindingCR := &v1alpha1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: testK8sInstNameDependentBinding, Namespace: testK8sNamespace},
+				Spec:       v1alpha1.ServiceBindingSpec{ServiceInstanceName: testK8sInstNameDependentBinding},
+			}
+			Expect(k8sClient.Create(ctx, bindingCR)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, instanceCR)).To(Succeed())
+			Eventually(func() string {
+				instanceCR := &v1alpha1.ServiceInstance{}
+				if err := k8sClient.Get(ctx, instanceKey, instanceCR); err != nil {
+					return err.Error()
+				}
+				return instanceCR.GetReadyCondition().Message
+			}, timeout, interval).Should(Equal("Waiting for deletion of depending service bindings"))
+			Expect(fakeSpaceClient.DeleteInstanceCallCount()).To(BeZero())
 
+			Expect(k8sClient.Delete(ctx, bindingCR)).To(Succeed())
+			Eventually(fakeSpaceClient.DeleteInstanceCallCount, timeout, interval).Should(BeNumerically(">=", 1))
 		})
 
 	})
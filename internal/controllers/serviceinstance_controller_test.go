@@ -0,0 +1,586 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+	"github.com/sap/cf-service-operator/internal/facade/facadefakes"
+)
+
+var _ = Describe("Expose maintenance upgrades | updateAvailableUpgrade", func() {
+	var recorder *record.FakeRecorder
+	var reconciler *ServiceInstanceReconciler
+	var serviceInstance *cfv1alpha1.ServiceInstance
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &ServiceInstanceReconciler{Recorder: recorder}
+		serviceInstance = &cfv1alpha1.ServiceInstance{}
+	})
+
+	upgradableInstance := func(version string) *facade.Instance {
+		return &facade.Instance{
+			UpgradeAvailable:         true,
+			AvailableMaintenanceInfo: &facade.MaintenanceInfo{Version: version, Description: "security fixes"},
+		}
+	}
+
+	It("should report a new upgrade once", func() {
+		reconciler.updateAvailableUpgrade(serviceInstance, upgradableInstance("1.1.0"))
+		Expect(serviceInstance.Status.AvailableUpgrade).To(Equal(&cfv1alpha1.MaintenanceInfo{Version: "1.1.0", Description: "security fixes"}))
+		Expect(recorder.Events).To(Receive(Equal("Normal UpgradeAvailable Maintenance upgrade to version 1.1.0 available: security fixes")))
+
+		reconciler.updateAvailableUpgrade(serviceInstance, upgradableInstance("1.1.0"))
+		Expect(recorder.Events).ToNot(Receive())
+
+		reconciler.updateAvailableUpgrade(serviceInstance, upgradableInstance("1.2.0"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("1.2.0")))
+	})
+
+	It("should clear the upgrade once applied", func() {
+		reconciler.updateAvailableUpgrade(serviceInstance, upgradableInstance("1.1.0"))
+		reconciler.updateAvailableUpgrade(serviceInstance, &facade.Instance{})
+		Expect(serviceInstance.Status.AvailableUpgrade).To(BeNil())
+	})
+})
+
+var _ = Describe("Detect stalled operations | updateStalledCondition", func() {
+	var recorder *record.FakeRecorder
+	var reconciler *ServiceInstanceReconciler
+	var serviceInstance *cfv1alpha1.ServiceInstance
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		cfg := config.Defaults()
+		cfg.StalledOperationTimeout = metav1.Duration{Duration: time.Hour}
+		reconciler = &ServiceInstanceReconciler{Recorder: recorder, Config: cfg}
+		serviceInstance = &cfv1alpha1.ServiceInstance{}
+	})
+
+	instanceInProgressSince := func(startedAt time.Time) *facade.Instance {
+		return &facade.Instance{
+			State:         facade.InstanceStateCreating,
+			LastOperation: &facade.LastOperation{Type: "create", State: "in progress", CreatedAt: startedAt, UpdatedAt: startedAt},
+		}
+	}
+
+	It("should report operations in progress for longer than the timeout once", func() {
+		cfinstance := instanceInProgressSince(time.Now().Add(-2 * time.Hour))
+		reconciler.updateStalledCondition(serviceInstance, cfinstance, nil)
+		Expect(serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionStalled).Status).To(Equal(cfv1alpha1.ConditionTrue))
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning Stalled Operation create has been in progress since")))
+
+		reconciler.updateStalledCondition(serviceInstance, cfinstance, nil)
+		Expect(recorder.Events).ToNot(Receive())
+
+		cfinstance.LastOperation.State = "succeeded"
+		reconciler.updateStalledCondition(serviceInstance, cfinstance, nil)
+		Expect(serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionStalled)).To(BeNil())
+	})
+
+	It("should honor the timeout annotation", func() {
+		cfinstance := instanceInProgressSince(time.Now().Add(-2 * time.Hour))
+		reconciler.updateStalledCondition(serviceInstance, cfinstance, map[string]string{cfv1alpha1.AnnotationStalledOperationTimeout: "3h"})
+		Expect(serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionStalled)).To(BeNil())
+
+		reconciler.updateStalledCondition(serviceInstance, cfinstance, map[string]string{cfv1alpha1.AnnotationStalledOperationTimeout: "0"})
+		Expect(serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionStalled)).To(BeNil())
+
+		reconciler.updateStalledCondition(serviceInstance, instanceInProgressSince(time.Now().Add(-20*time.Minute)), map[string]string{cfv1alpha1.AnnotationStalledOperationTimeout: "10m"})
+		Expect(serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionStalled)).ToNot(BeNil())
+	})
+})
+
+var _ = Describe("Detect parameter drift | updateParameterDriftCondition", func() {
+	ctx := context.Background()
+
+	var recorder *record.FakeRecorder
+	var reconciler *ServiceInstanceReconciler
+	var spaceClient *facadefakes.FakeSpaceClient
+	var serviceInstance *cfv1alpha1.ServiceInstance
+	cfinstance := &facade.Instance{Guid: "instance-guid", State: facade.InstanceStateReady}
+	annotations := map[string]string{cfv1alpha1.AnnotationCheckParameterDrift: "true"}
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &ServiceInstanceReconciler{Recorder: recorder}
+		spaceClient = &facadefakes.FakeSpaceClient{}
+		serviceInstance = &cfv1alpha1.ServiceInstance{}
+	})
+
+	It("should only check for drift if requested by the annotation", func() {
+		reconciler.updateParameterDriftCondition(ctx, serviceInstance, spaceClient, cfinstance, map[string]interface{}{"size": 1}, nil)
+		Expect(spaceClient.GetInstanceParametersCallCount()).To(Equal(0))
+		Expect(serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionParameterDrift)).To(BeNil())
+	})
+
+	It("should report drifted keys once, ignoring keys only present in the broker's parameters", func() {
+		spaceClient.GetInstanceParametersReturns(map[string]interface{}{"size": float64(1), "plan": "default", "password": "x"}, nil)
+		reconciler.updateParameterDriftCondition(ctx, serviceInstance, spaceClient, cfinstance, map[string]interface{}{"size": 1}, annotations)
+		Expect(serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionParameterDrift).Status).To(Equal(cfv1alpha1.ConditionFalse))
+		_, guid := spaceClient.GetInstanceParametersArgsForCall(0)
+		Expect(guid).To(Equal("instance-guid"))
+
+		desired := map[string]interface{}{"size": 2, "password": "secret-value", "region": "eu"}
+		reconciler.updateParameterDriftCondition(ctx, serviceInstance, spaceClient, cfinstance, desired, annotations)
+		condition := serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionParameterDrift)
+		Expect(condition.Status).To(Equal(cfv1alpha1.ConditionTrue))
+		Expect(condition.Message).To(HaveSuffix("password, region, size"))
+		Expect(condition.Message).ToNot(ContainSubstring("secret-value"))
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning ParameterDrift")))
+
+		reconciler.updateParameterDriftCondition(ctx, serviceInstance, spaceClient, cfinstance, desired, annotations)
+		Expect(recorder.Events).ToNot(Receive())
+	})
+
+	It("should report brokers which do not support retrieving parameters", func() {
+		spaceClient.GetInstanceParametersReturns(nil, errors.New("not retrievable"))
+		reconciler.updateParameterDriftCondition(ctx, serviceInstance, spaceClient, cfinstance, nil, annotations)
+		condition := serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionParameterDrift)
+		Expect(condition.Status).To(Equal(cfv1alpha1.ConditionUnknown))
+		Expect(condition.Reason).To(Equal("ParametersNotRetrievable"))
+	})
+})
+
+var _ = Describe("Guard service plan changes | updatePlanChangeBlockedCondition", func() {
+	var serviceInstance *cfv1alpha1.ServiceInstance
+	cfinstance := &facade.Instance{ServicePlanGuid: "plan-guid"}
+
+	BeforeEach(func() {
+		serviceInstance = &cfv1alpha1.ServiceInstance{}
+	})
+
+	It("should block plan changes unless allowed", func() {
+		Expect(updatePlanChangeBlockedCondition(serviceInstance, cfinstance, "other-plan-guid")).To(BeTrue())
+		condition := serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionPlanChangeBlocked)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(cfv1alpha1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(serviceInstancePlanChangeBlockedConditionReasonNotAllowed))
+		Expect(condition.Message).To(ContainSubstring("other-plan-guid"))
+
+		serviceInstance.Spec.AllowPlanChange = true
+		Expect(updatePlanChangeBlockedCondition(serviceInstance, cfinstance, "other-plan-guid")).To(BeFalse())
+		Expect(serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionPlanChangeBlocked)).To(BeNil())
+	})
+
+	It("should not report unchanged plans", func() {
+		Expect(updatePlanChangeBlockedCondition(serviceInstance, cfinstance, "plan-guid")).To(BeFalse())
+		Expect(serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionPlanChangeBlocked)).To(BeNil())
+	})
+})
+
+var _ = Describe("Apply maintenance upgrades | getRequestedUpgrade", func() {
+	upgradableInstance := func(state facade.InstanceState) *facade.Instance {
+		return &facade.Instance{
+			State:                    state,
+			UpgradeAvailable:         true,
+			AvailableMaintenanceInfo: &facade.MaintenanceInfo{Version: "1.1.0"},
+		}
+	}
+
+	It("should apply offered upgrades with upgrade policy Auto", func() {
+		serviceInstance := &cfv1alpha1.ServiceInstance{Spec: cfv1alpha1.ServiceInstanceSpec{UpgradePolicy: cfv1alpha1.UpgradePolicyAuto}}
+		Expect(getRequestedUpgrade(serviceInstance, upgradableInstance(facade.InstanceStateReady))).To(Equal(&facade.MaintenanceInfo{Version: "1.1.0"}))
+		Expect(getRequestedUpgrade(serviceInstance, upgradableInstance(facade.InstanceStateUpdating))).To(BeNil())
+		Expect(getRequestedUpgrade(serviceInstance, &facade.Instance{State: facade.InstanceStateReady})).To(BeNil())
+	})
+
+	It("should apply offered upgrades with upgrade policy Manual only if requested", func() {
+		serviceInstance := &cfv1alpha1.ServiceInstance{}
+		Expect(getRequestedUpgrade(serviceInstance, upgradableInstance(facade.InstanceStateReady))).To(BeNil())
+
+		serviceInstance.Annotations = map[string]string{cfv1alpha1.AnnotationUpgradeToVersion: "1.0.5"}
+		Expect(getRequestedUpgrade(serviceInstance, upgradableInstance(facade.InstanceStateReady))).To(BeNil())
+
+		serviceInstance.Annotations[cfv1alpha1.AnnotationUpgradeToVersion] = "1.1.0"
+		Expect(getRequestedUpgrade(serviceInstance, upgradableInstance(facade.InstanceStateReady))).To(Equal(&facade.MaintenanceInfo{Version: "1.1.0"}))
+	})
+})
+
+var _ = Describe("Dry-run mode | computePendingChanges", func() {
+	parameters := map[string]interface{}{"key": "value"}
+	serviceInstance := func() *cfv1alpha1.ServiceInstance {
+		return &cfv1alpha1.ServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec:       cfv1alpha1.ServiceInstanceSpec{Name: "instance"},
+		}
+	}
+	syncedInstance := func() *facade.Instance {
+		return &facade.Instance{
+			Name:            "instance",
+			ServicePlanGuid: "plan-guid",
+			Generation:      2,
+			ParameterHash:   facade.ObjectHash(parameters),
+			TagsHash:        facade.TagsHash(nil),
+			State:           facade.InstanceStateReady,
+		}
+	}
+
+	It("should report creation of missing instances", func() {
+		changes := computePendingChanges(serviceInstance(), nil, "plan-guid", parameters)
+		Expect(changes.Operation).To(Equal(cfv1alpha1.PendingOperationCreate))
+		Expect(changes.ParameterHash).To(Equal(facade.ObjectHash(parameters)))
+	})
+
+	It("should report nothing for synced instances", func() {
+		changes := computePendingChanges(serviceInstance(), syncedInstance(), "plan-guid", parameters)
+		Expect(changes.Operation).To(Equal(cfv1alpha1.PendingOperationNone))
+		Expect(changes.Changes).To(BeEmpty())
+	})
+
+	It("should report updates with the changed attributes", func() {
+		cfinstance := syncedInstance()
+		si := serviceInstance()
+		si.Spec.AllowPlanChange = true
+		changes := computePendingChanges(si, cfinstance, "other-plan-guid", map[string]interface{}{"key": "other-value"})
+		Expect(changes.Operation).To(Equal(cfv1alpha1.PendingOperationUpdate))
+		Expect(changes.Changes).To(Equal([]string{"servicePlan", "parameters"}))
+		Expect(changes.CurrentParameterHash).To(Equal(cfinstance.ParameterHash))
+
+		// plan changes which are not allowed are not applied
+		changes = computePendingChanges(serviceInstance(), cfinstance, "other-plan-guid", map[string]interface{}{"key": "other-value"})
+		Expect(changes.Changes).To(Equal([]string{"parameters"}))
+
+		cfinstance.Generation = 1
+		cfinstance.Name = "old-instance"
+		changes = computePendingChanges(serviceInstance(), cfinstance, "plan-guid", parameters)
+		Expect(changes.Operation).To(Equal(cfv1alpha1.PendingOperationUpdate))
+		Expect(changes.Changes).To(Equal([]string{"name", "generation"}))
+	})
+
+	It("should report updates of tags only", func() {
+		si := serviceInstance()
+		si.Spec.Tags = []string{"tag"}
+		changes := computePendingChanges(si, syncedInstance(), "plan-guid", parameters)
+		Expect(changes.Operation).To(Equal(cfv1alpha1.PendingOperationUpdate))
+		Expect(changes.Changes).To(Equal([]string{"tags"}))
+
+		si.Spec.Tags = []string{}
+		Expect(computePendingChanges(si, syncedInstance(), "plan-guid", parameters).Operation).To(Equal(cfv1alpha1.PendingOperationNone))
+	})
+
+	It("should report re-creation of failed instances only if requested", func() {
+		cfinstance := syncedInstance()
+		cfinstance.State = facade.InstanceStateCreatedFailed
+		si := serviceInstance()
+		Expect(computePendingChanges(si, cfinstance, "plan-guid", parameters).Operation).To(Equal(cfv1alpha1.PendingOperationUpdate))
+
+		si.Annotations = map[string]string{cfv1alpha1.AnnotationRecreate: "true"}
+		Expect(computePendingChanges(si, cfinstance, "plan-guid", parameters).Operation).To(Equal(cfv1alpha1.PendingOperationRecreate))
+	})
+
+	It("should report requested upgrades", func() {
+		cfinstance := syncedInstance()
+		cfinstance.UpgradeAvailable = true
+		cfinstance.AvailableMaintenanceInfo = &facade.MaintenanceInfo{Version: "1.1.0"}
+		si := serviceInstance()
+		si.Spec.UpgradePolicy = cfv1alpha1.UpgradePolicyAuto
+		changes := computePendingChanges(si, cfinstance, "plan-guid", parameters)
+		Expect(changes.Operation).To(Equal(cfv1alpha1.PendingOperationUpgrade))
+		Expect(changes.UpgradeToVersion).To(Equal("1.1.0"))
+	})
+
+	It("should report deletion of existing instances", func() {
+		si := serviceInstance()
+		si.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		Expect(computePendingChanges(si, syncedInstance(), "", nil).Operation).To(Equal(cfv1alpha1.PendingOperationDelete))
+		Expect(computePendingChanges(si, nil, "", nil).Operation).To(Equal(cfv1alpha1.PendingOperationNone))
+	})
+})
+
+var _ = Describe("Check instance names before creation | findNameConflict, rejectName", func() {
+	ctx := context.Background()
+
+	It("should look up instances by the specified name", func() {
+		spaceClient := &facadefakes.FakeSpaceClient{}
+		spaceClient.FindInstanceNameReturns(&facade.InstanceName{Guid: "other-guid", Name: "instance"}, nil)
+		conflicting, err := findNameConflict(ctx, spaceClient, "instance")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conflicting.Guid).To(Equal("other-guid"))
+		_, name := spaceClient.FindInstanceNameArgsForCall(0)
+		Expect(name).To(Equal("instance"))
+		Expect(spaceClient.GetInstanceCallCount()).To(Equal(0))
+	})
+
+	It("should report conflicts through the NameConflict condition, and emit an event once", func() {
+		recorder := record.NewFakeRecorder(10)
+		reconciler := &ServiceInstanceReconciler{Recorder: recorder}
+		serviceInstance := &cfv1alpha1.ServiceInstance{}
+		conflicting := &facade.InstanceName{Guid: "other-guid", Name: "instance"}
+
+		result := reconciler.rejectName(serviceInstance, conflicting, "space-guid")
+		Expect(result.RequeueAfter).To(Equal(serviceInstanceNameConflictRequeueInterval))
+		condition := serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionNameConflict)
+		Expect(condition.Status).To(Equal(cfv1alpha1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring("other-guid"))
+		Expect(serviceInstance.GetReadyCondition().Reason).To(Equal(serviceInstanceNameConflictConditionReasonNameTaken))
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning NameConflict")))
+
+		reconciler.rejectName(serviceInstance, conflicting, "space-guid")
+		Expect(recorder.Events).ToNot(Receive())
+	})
+
+	It("should not check names if adoption is requested", func() {
+		serviceInstance := &cfv1alpha1.ServiceInstance{}
+		Expect(isAdoptionRequested(serviceInstance)).To(BeFalse())
+		serviceInstance.Annotations = map[string]string{cfv1alpha1.AnnotationAdoptCFResources: "adopt"}
+		Expect(isAdoptionRequested(serviceInstance)).To(BeTrue())
+		serviceInstance.Annotations = map[string]string{cfv1alpha1.AnnotationAdoptCFInstanceGuid: "other-guid"}
+		Expect(isAdoptionRequested(serviceInstance)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Observe instances without changing them | observeInstance", func() {
+	ctx := context.Background()
+	var client *facadefakes.FakeSpaceClient
+	var reconciler *ServiceInstanceReconciler
+	var serviceInstance *cfv1alpha1.ServiceInstance
+
+	BeforeEach(func() {
+		client = &facadefakes.FakeSpaceClient{}
+		reconciler = &ServiceInstanceReconciler{Recorder: record.NewFakeRecorder(10), Config: config.Defaults()}
+		serviceInstance = &cfv1alpha1.ServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "instance", UID: "instance-uid", Generation: 2},
+			Spec:       cfv1alpha1.ServiceInstanceSpec{Name: "cf-instance"},
+		}
+	})
+
+	expectNoChanges := func() {
+		Expect(client.CreateInstanceCallCount()).To(Equal(0))
+		Expect(client.UpdateInstanceCallCount()).To(Equal(0))
+		Expect(client.DeleteInstanceCallCount()).To(Equal(0))
+		condition := serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionSynced)
+		Expect(condition.Status).To(Equal(cfv1alpha1.ConditionUnknown))
+		Expect(condition.Reason).To(Equal(conditionReasonObserveOnly))
+	}
+
+	It("should reflect the state of the owned instance in the status", func() {
+		client.GetInstanceReturns(&facade.Instance{Guid: "instance-guid", ServicePlanGuid: "plan-guid", State: facade.InstanceStateReady}, nil)
+
+		result, err := reconciler.observeInstance(ctx, serviceInstance, nil, client, "space-guid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(client.GetInstanceCallCount()).To(Equal(1))
+		_, instanceOpts := client.GetInstanceArgsForCall(0)
+		Expect(instanceOpts).To(Equal(map[string]string{"name": "", "owner": "instance-uid"}))
+		Expect(serviceInstance.Status.SpaceGuid).To(Equal("space-guid"))
+		Expect(serviceInstance.Status.ServiceInstanceGuid).To(Equal("instance-guid"))
+		Expect(serviceInstance.Status.ServicePlanGuid).To(Equal("plan-guid"))
+		Expect(serviceInstance.IsReady()).To(BeTrue())
+		Expect(serviceInstance.Status.ObservedGeneration).To(Equal(int64(2)))
+		expectNoChanges()
+	})
+
+	It("should look up instances not owned (yet) by adoption annotation, or by name", func() {
+		serviceInstance.Annotations = map[string]string{cfv1alpha1.AnnotationAdoptCFInstanceGuid: "instance-guid"}
+		client.GetInstanceReturnsOnCall(1, &facade.Instance{Guid: "instance-guid", State: facade.InstanceStateReady}, nil)
+		_, err := reconciler.observeInstance(ctx, serviceInstance, nil, client, "space-guid")
+		Expect(err).NotTo(HaveOccurred())
+		_, instanceOpts := client.GetInstanceArgsForCall(1)
+		Expect(instanceOpts).To(HaveKeyWithValue("guid", "instance-guid"))
+		Expect(serviceInstance.Status.ServiceInstanceGuid).To(Equal("instance-guid"))
+
+		serviceInstance.Annotations = nil
+		_, err = reconciler.observeInstance(ctx, serviceInstance, nil, client, "space-guid")
+		Expect(err).NotTo(HaveOccurred())
+		_, instanceOpts = client.GetInstanceArgsForCall(3)
+		Expect(instanceOpts).To(HaveKeyWithValue("name", "cf-instance"))
+		expectNoChanges()
+	})
+
+	It("should report missing instances, without creating them", func() {
+		_, err := reconciler.observeInstance(ctx, serviceInstance, nil, client, "space-guid")
+		Expect(err).NotTo(HaveOccurred())
+		condition := serviceInstance.GetReadyCondition()
+		Expect(condition.Status).To(Equal(cfv1alpha1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(readyConditionReasonNotFound))
+		Expect(serviceInstance.Status.ServiceInstanceGuid).To(BeEmpty())
+		expectNoChanges()
+	})
+
+	It("should poll instances with operations in progress, and report failed instances", func() {
+		client.GetInstanceReturns(&facade.Instance{Guid: "instance-guid", State: facade.InstanceStateUpdating, StateDescription: "in progress"}, nil)
+		result, err := reconciler.observeInstance(ctx, serviceInstance, nil, client, "space-guid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(10 * time.Second))
+		Expect(serviceInstance.GetReadyCondition().Status).To(Equal(cfv1alpha1.ConditionUnknown))
+
+		client.GetInstanceReturns(&facade.Instance{Guid: "instance-guid", State: facade.InstanceStateUpdateFailed, StateDescription: "failed"}, nil)
+		_, err = reconciler.observeInstance(ctx, serviceInstance, nil, client, "space-guid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(serviceInstance.GetReadyCondition().Status).To(Equal(cfv1alpha1.ConditionFalse))
+		Expect(serviceInstance.GetReadyCondition().Message).To(Equal("failed"))
+		expectNoChanges()
+	})
+
+	It("should pass through errors", func() {
+		client.GetInstanceReturns(nil, errors.New("connection refused"))
+		_, err := reconciler.observeInstance(ctx, serviceInstance, nil, client, "space-guid")
+		Expect(err).To(MatchError("connection refused"))
+	})
+})
+
+var _ = Describe("Pause the reconciliation of instances | Reconcile", func() {
+	ctx := context.Background()
+	instanceKey := types.NamespacedName{Namespace: "ns", Name: "instance"}
+	var clientBuilds int
+	var reconciler *ServiceInstanceReconciler
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		serviceInstance := &cfv1alpha1.ServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   instanceKey.Namespace,
+				Name:        instanceKey.Name,
+				Generation:  1,
+				Annotations: map[string]string{cfv1alpha1.AnnotationPaused: "true"},
+			},
+			Spec: cfv1alpha1.ServiceInstanceSpec{SpaceName: "space", ServiceOfferingName: "offering", ServicePlanName: "plan"},
+		}
+		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceInstanceReadyConditionReasonNew, "First seen")
+		clientBuilds = 0
+		reconciler = &ServiceInstanceReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(serviceInstance).
+				WithStatusSubresource(&cfv1alpha1.ServiceInstance{}).
+				Build(),
+			Scheme: scheme,
+			ClientBuilder: func(string, string, string, string, *config.Config) (facade.SpaceClient, error) {
+				clientBuilds++
+				return &facadefakes.FakeSpaceClient{}, nil
+			},
+			Config:   config.Defaults(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	It("should neither read nor change the instance while paused, and resume once the annotation is removed", func() {
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: instanceKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(ctrl.Result{}))
+
+		Expect(clientBuilds).To(BeZero())
+		serviceInstance := &cfv1alpha1.ServiceInstance{}
+		Expect(reconciler.Get(ctx, instanceKey, serviceInstance)).To(Succeed())
+		Expect(serviceInstance.Finalizers).To(BeEmpty())
+		Expect(serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionPaused)).ToNot(BeNil())
+		Expect(serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionPaused).Status).To(Equal(cfv1alpha1.ConditionTrue))
+		Expect(serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionPaused).Reason).To(Equal(conditionReasonPaused))
+
+		// resume
+		delete(serviceInstance.Annotations, cfv1alpha1.AnnotationPaused)
+		Expect(reconciler.Update(ctx, serviceInstance)).To(Succeed())
+		result, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: instanceKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+		Expect(reconciler.Get(ctx, instanceKey, serviceInstance)).To(Succeed())
+		Expect(serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionPaused)).To(BeNil())
+	})
+})
+
+var _ = Describe("Bound the duration of reconciles of instances | Reconcile", func() {
+	ctx := context.Background()
+	instanceKey := types.NamespacedName{Namespace: "ns", Name: "instance"}
+	var spaceClient *facadefakes.FakeSpaceClient
+	var reconciler *ServiceInstanceReconciler
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		space := &cfv1alpha1.Space{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space"},
+			Spec:       cfv1alpha1.SpaceSpec{Guid: "space-guid", AuthSecretName: "space-secret"},
+		}
+		space.SetReadyCondition(cfv1alpha1.ConditionTrue, "Ready", "")
+		serviceInstance := &cfv1alpha1.ServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: instanceKey.Namespace, Name: instanceKey.Name, UID: "instance-uid", Generation: 1},
+			Spec:       cfv1alpha1.ServiceInstanceSpec{SpaceName: "space", ServiceOfferingName: "offering", ServicePlanName: "plan"},
+		}
+		serviceInstance.SetReadyCondition(cfv1alpha1.ConditionUnknown, serviceInstanceReadyConditionReasonNew, "First seen")
+		spaceClient = &facadefakes.FakeSpaceClient{}
+		// simulate a Cloud Foundry API which does not respond
+		spaceClient.GetInstanceStub = func(ctx context.Context, _ map[string]string) (*facade.Instance, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		reconciler = &ServiceInstanceReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithIndex(&cfv1alpha1.ServiceBinding{}, indexServiceInstanceName,
+					indexByField(func(serviceBinding *cfv1alpha1.ServiceBinding) string { return serviceBinding.Spec.ServiceInstanceName })).
+				WithIndex(&cfv1alpha1.RouteBinding{}, indexServiceInstanceName,
+					indexByField(func(routeBinding *cfv1alpha1.RouteBinding) string { return routeBinding.Spec.ServiceInstanceName })).
+				WithIndex(&cfv1alpha1.ClusterServiceBinding{}, indexClusterServiceBindingServiceInstance,
+					indexByField(func(clusterServiceBinding *cfv1alpha1.ClusterServiceBinding) string {
+						return serviceInstanceRefKey(clusterServiceBinding.Spec.ServiceInstanceRef.Namespace, clusterServiceBinding.Spec.ServiceInstanceRef.Name)
+					})).
+				WithObjects(serviceInstance, space, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space-secret"},
+					Data:       map[string][]byte{"url": []byte("https://api.cf.example.com")},
+				}).
+				WithStatusSubresource(&cfv1alpha1.ServiceInstance{}).
+				Build(),
+			Scheme: scheme,
+			ClientBuilder: func(string, string, string, string, *config.Config) (facade.SpaceClient, error) {
+				return spaceClient, nil
+			},
+			ReconcileTimeout: 50 * time.Millisecond,
+			Config:           config.Defaults(),
+			Recorder:         record.NewFakeRecorder(10),
+		}
+	})
+
+	It("should give up once the timeout expires, and report the timeout in the status", func() {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: instanceKey})
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(err).To(MatchError(ContainSubstring("reconcile timed out after 50ms")))
+
+		serviceInstance := &cfv1alpha1.ServiceInstance{}
+		Expect(reconciler.Get(ctx, instanceKey, serviceInstance)).To(Succeed())
+		condition := serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionTimeout)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(cfv1alpha1.ConditionTrue))
+		Expect(serviceInstance.IsReady()).To(BeFalse())
+	})
+
+	It("should not bound reconciles if no timeout is configured", func() {
+		reconciler.ReconcileTimeout = 0
+		spaceClient.GetInstanceStub = func(ctx context.Context, _ map[string]string) (*facade.Instance, error) {
+			_, hasDeadline := ctx.Deadline()
+			Expect(hasDeadline).To(BeFalse())
+			return nil, errors.New("failed")
+		}
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: instanceKey})
+		Expect(err).To(MatchError(ContainSubstring("failed")))
+		Expect(spaceClient.GetInstanceCallCount()).To(Equal(1))
+
+		serviceInstance := &cfv1alpha1.ServiceInstance{}
+		Expect(reconciler.Get(ctx, instanceKey, serviceInstance)).To(Succeed())
+		Expect(serviceInstance.GetCondition(cfv1alpha1.ServiceInstanceConditionTimeout)).To(BeNil())
+	})
+})
@@ -0,0 +1,122 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+// maximum duration of the catalog lookup; admission requests time out after 10 seconds by default
+const servicePlanCheckTimeout = 5 * time.Second
+
+// ServicePlanChecker resolves the service offering and plan referenced (by name) by a service instance
+// against the service catalog visible in the referenced Cloud Foundry space.
+// Implements cfv1alpha1.ServicePlanChecker; used by the validating webhook.
+type ServicePlanChecker struct {
+	client.Client
+	ClusterResourceNamespace string
+	ClientBuilder            facade.SpaceClientBuilder
+	Config                   *config.Config
+}
+
+var _ cfv1alpha1.ServicePlanChecker = &ServicePlanChecker{}
+
+// CheckServicePlan returns an error if the referenced service offering or plan does not exist; if the check
+// cannot be performed (for example because the space is not ready yet, or Cloud Foundry is not reachable),
+// the service instance is admitted with a warning, and the plan is resolved later by the controller.
+func (c *ServicePlanChecker) CheckServicePlan(ctx context.Context, serviceInstance *cfv1alpha1.ServiceInstance) (admission.Warnings, error) {
+	spec := &serviceInstance.Spec
+	if spec.ServiceOfferingName == "" || spec.ServicePlanName == "" {
+		return nil, nil
+	}
+
+	var space cfv1alpha1.GenericSpace
+	if spec.SpaceName != "" {
+		space = &cfv1alpha1.Space{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: serviceInstance.Namespace, Name: spec.SpaceName}, space); err != nil {
+			return skippedServicePlanCheck(errors.Wrapf(err, "failed to get Space, name: %s", spec.SpaceName))
+		}
+	} else {
+		space = &cfv1alpha1.ClusterSpace{}
+		if err := c.Get(ctx, types.NamespacedName{Name: spec.ClusterSpaceName}, space); err != nil {
+			return skippedServicePlanCheck(errors.Wrapf(err, "failed to get ClusterSpace, name: %s", spec.ClusterSpaceName))
+		}
+	}
+	spaceSecretName, err := getSpaceSecretName(space, c.ClusterResourceNamespace, c.Config)
+	if err != nil {
+		return skippedServicePlanCheck(err)
+	}
+
+	spaceGuid := space.GetSpec().Guid
+	if spaceGuid == "" {
+		spaceGuid = space.GetStatus().SpaceGuid
+	}
+	if spaceGuid == "" {
+		return skippedServicePlanCheck(fmt.Errorf("referenced %s is not ready, name: %s", space.GetKind(), space.GetName()))
+	}
+
+	spaceSecret := &corev1.Secret{}
+	if err := c.Get(ctx, spaceSecretName, spaceSecret); err != nil {
+		return skippedServicePlanCheck(errors.Wrapf(err, "failed to get Secret containing space credentials, secret name: %s", spaceSecretName))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, servicePlanCheckTimeout)
+	defer cancel()
+
+	spaceClient, err := c.ClientBuilder(spaceGuid, getEndpoint(space, spaceSecret, serviceInstance.Annotations), string(spaceSecret.Data["username"]), string(spaceSecret.Data["password"]), getClientConfig(getSpaceConfig(c.Config, space), spaceSecret))
+	if err != nil {
+		return skippedServicePlanCheck(errors.Wrapf(err, "failed to build the client from secret %s", spaceSecretName))
+	}
+	if _, err := spaceClient.FindServicePlan(ctx, spec.ServiceOfferingName, spec.ServicePlanName, spaceGuid); err != nil {
+		var notFoundErr *facade.ServicePlanNotFoundError
+		if errors.As(err, &notFoundErr) {
+			message := fmt.Sprintf("invalid spec.serviceOfferingName or spec.servicePlanName: %s in %s %s", notFoundErr, space.GetKind(), space.GetName())
+			if notFoundErr.ServicePlanName != "" {
+				if planNames := availableServicePlanNames(ctx, spaceClient, spaceGuid, spec.ServiceOfferingName); len(planNames) > 0 {
+					message += fmt.Sprintf(" (available plans: %s)", strings.Join(planNames, ", "))
+				}
+			}
+			return nil, errors.New(message)
+		}
+		return skippedServicePlanCheck(err)
+	}
+	return nil, nil
+}
+
+// availableServicePlanNames returns the (sorted) names of the plans of the given service offering; errors are ignored,
+// since the result is only used to enrich the rejection message.
+func availableServicePlanNames(ctx context.Context, spaceClient facade.SpaceClient, spaceGuid string, serviceOfferingName string) []string {
+	servicePlans, err := spaceClient.ListServicePlans(ctx, spaceGuid)
+	if err != nil {
+		return nil
+	}
+	var planNames []string
+	for _, servicePlan := range servicePlans {
+		if servicePlan.ServiceOfferingName == serviceOfferingName {
+			planNames = append(planNames, servicePlan.Name)
+		}
+	}
+	sort.Strings(planNames)
+	return planNames
+}
+
+func skippedServicePlanCheck(err error) (admission.Warnings, error) {
+	return admission.Warnings{fmt.Sprintf("service plan could not be verified against the Cloud Foundry service catalog: %s", err)}, nil
+}
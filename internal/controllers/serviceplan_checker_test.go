@@ -0,0 +1,112 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+	"github.com/sap/cf-service-operator/internal/facade/facadefakes"
+)
+
+var _ = Describe("Validate service instances against the service catalog | CheckServicePlan", func() {
+	ctx := context.Background()
+	var spaceClient *facadefakes.FakeSpaceClient
+	var checker *ServicePlanChecker
+	var serviceInstance *cfv1alpha1.ServiceInstance
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		space := &cfv1alpha1.Space{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space"},
+			Spec:       cfv1alpha1.SpaceSpec{Guid: "space-guid", AuthSecretName: "space-secret"},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space-secret"},
+			Data:       map[string][]byte{"url": []byte("https://api.cf.example.com")},
+		}
+		spaceClient = &facadefakes.FakeSpaceClient{}
+		checker = &ServicePlanChecker{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(space, secret).Build(),
+			ClientBuilder: func(string, string, string, string, *config.Config) (facade.SpaceClient, error) {
+				return spaceClient, nil
+			},
+			Config: config.Defaults(),
+		}
+		serviceInstance = &cfv1alpha1.ServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "instance"},
+			Spec:       cfv1alpha1.ServiceInstanceSpec{SpaceName: "space", ServiceOfferingName: "offering", ServicePlanName: "plan"},
+		}
+	})
+
+	It("should admit existing plans", func() {
+		spaceClient.FindServicePlanReturns("plan-guid", nil)
+
+		warnings, err := checker.CheckServicePlan(ctx, serviceInstance)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+		_, offering, plan, spaceGuid := spaceClient.FindServicePlanArgsForCall(0)
+		Expect([]string{offering, plan, spaceGuid}).To(Equal([]string{"offering", "plan", "space-guid"}))
+	})
+
+	It("should reject non-existing plans", func() {
+		spaceClient.FindServicePlanReturns("", &facade.ServicePlanNotFoundError{ServiceOfferingName: "offering", ServicePlanName: "plan"})
+
+		_, err := checker.CheckServicePlan(ctx, serviceInstance)
+		Expect(err).To(MatchError(ContainSubstring("found no service plan with name: plan")))
+	})
+
+	It("should list the available plans of the offering when rejecting", func() {
+		spaceClient.FindServicePlanReturns("", &facade.ServicePlanNotFoundError{ServiceOfferingName: "offering", ServicePlanName: "plan"})
+		spaceClient.ListServicePlansReturns([]facade.ServicePlan{
+			{Guid: "guid-2", Name: "standard", ServiceOfferingName: "offering"},
+			{Guid: "guid-1", Name: "lite", ServiceOfferingName: "offering"},
+			{Guid: "guid-3", Name: "other", ServiceOfferingName: "other-offering"},
+		}, nil)
+
+		_, err := checker.CheckServicePlan(ctx, serviceInstance)
+		Expect(err).To(MatchError(ContainSubstring("(available plans: lite, standard)")))
+	})
+
+	It("should admit with warning if the catalog cannot be read", func() {
+		spaceClient.FindServicePlanReturns("", fmt.Errorf("connection refused"))
+
+		warnings, err := checker.CheckServicePlan(ctx, serviceInstance)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warnings).To(ConsistOf(ContainSubstring("connection refused")))
+	})
+
+	It("should admit with warning if the space does not exist", func() {
+		serviceInstance.Spec.SpaceName = "other-space"
+
+		warnings, err := checker.CheckServicePlan(ctx, serviceInstance)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warnings).To(HaveLen(1))
+		Expect(spaceClient.FindServicePlanCallCount()).To(BeZero())
+	})
+
+	It("should skip service instances referencing the plan by guid", func() {
+		serviceInstance.Spec = cfv1alpha1.ServiceInstanceSpec{SpaceName: "space", ServicePlanGuid: "plan-guid"}
+
+		warnings, err := checker.CheckServicePlan(ctx, serviceInstance)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+		Expect(spaceClient.FindServicePlanCallCount()).To(BeZero())
+	})
+})
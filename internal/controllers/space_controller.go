@@ -8,8 +8,12 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"math"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 
 	corev1 "k8s.io/api/core/v1"
@@ -23,6 +27,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
 	"github.com/sap/cf-service-operator/internal/facade"
 )
 
@@ -36,8 +41,25 @@ const (
 	spaceReadyConditionReasonSuccess         = "Success"
 	spaceReadyConditionReasonDeletionBlocked = "DeletionBlocked"
 	spaceReadyConditionDeleting              = "Deleting"
+	spaceReadyConditionReasonMaxRetries      = "MaximumRetriesExceeded"
 )
 
+// Default maximum number of retries for failed reconciliations of spaces
+const spaceDefaultMaxRetries = math.MaxInt32 // infinite number of retries
+
+// Reasons of the conditions reporting the results of the health probes
+const (
+	spaceConditionReasonProbeSucceeded = "ProbeSucceeded"
+	spaceConditionReasonProbeFailed    = "ProbeFailed"
+)
+
+// Conditions reporting the results of the health probes
+var spaceHealthProbeConditions = map[cfv1alpha1.SpaceHealthProbe]cfv1alpha1.SpaceConditionType{
+	cfv1alpha1.SpaceHealthProbeServicePlans:  cfv1alpha1.SpaceConditionServicePlansAvailable,
+	cfv1alpha1.SpaceHealthProbeQuota:         cfv1alpha1.SpaceConditionQuotaAvailable,
+	cfv1alpha1.SpaceHealthProbeDeveloperRole: cfv1alpha1.SpaceConditionDeveloperRoleAssigned,
+}
+
 // SpaceReconciler reconciles a (Cluster)Space object
 type SpaceReconciler struct {
 	Kind string
@@ -46,6 +68,14 @@ type SpaceReconciler struct {
 	ClusterResourceNamespace string
 	ClientBuilder            facade.OrganizationClientBuilder
 	HealthCheckerBuilder     facade.SpaceHealthCheckerBuilder
+	ReconcileTimeout         time.Duration
+	Config                   *config.Config
+	// Optional; required for spaces with spec.deletionPolicy Cascade
+	SpaceClientBuilder facade.SpaceClientBuilder
+	// Optional; required for failing over to the failoverUrl of the space secret
+	EndpointProber facade.EndpointProber
+	// Optional; reader serving lists by field index (set to the manager's cache by SetupWithManager, unless set)
+	CacheReader client.Reader
 }
 
 // +kubebuilder:rbac:groups=cf.cs.sap.com,resources=clusterspaces,verbs=get;list;watch;update
@@ -74,13 +104,21 @@ func (r *SpaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resu
 		log.V(1).Info("Not found; ignoring")
 		return ctrl.Result{}, nil
 	}
+
+	// Bound the duration of this reconcile (unless overridden by annotation, as configured); the final status update (see below)
+	// is attempted even after expiry
+	reconcileCallTimeout := getReconcileCallTimeout(space.GetAnnotations(), r.ReconcileTimeout)
+	ctx, cancel := withReconcileTimeout(ctx, reconcileCallTimeout)
+	defer cancel()
+	ctx, cfResponded := facade.WithResponseRecorder(ctx)
+
 	// Call the defaulting webhook logic also here (because defaulting through the webhook might be incomplete in case of generateName usage)
 	space.Default()
 
 	spec := space.GetSpec()
 	status := space.GetStatus()
-	status.ObservedGeneration = space.GetGeneration()
 	status.LastReconciledAt = &[]metav1.Time{metav1.Now()}[0]
+	status.ManagementMode = spec.GetManagementMode()
 
 	// Always attempt to update the status
 	skipStatusUpdate := false
@@ -88,66 +126,95 @@ func (r *SpaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resu
 		if skipStatusUpdate {
 			return
 		}
+		if cfResponded() {
+			space.SetCondition(cfv1alpha1.SpaceConditionCFReachable, cfv1alpha1.ConditionTrue, conditionReasonReachable, "Cloud Foundry API is reachable")
+		}
+		err = reportReconcileTimeout(ctx, space, cfv1alpha1.SpaceConditionTimeout, err, reconcileCallTimeout)
 		if err != nil {
-			space.SetReadyCondition(cfv1alpha1.ConditionFalse, spaceReadyConditionReasonError, err.Error())
+			if unavailableResult, ok := cfUnavailableResult(err); ok {
+				log.V(1).Info("Cloud Foundry API unavailable; requeuing", "requeueAfter", unavailableResult.RequeueAfter)
+				space.SetReadyCondition(cfv1alpha1.ConditionUnknown, readyConditionReasonCFUnavailable, err.Error())
+				space.SetCondition(cfv1alpha1.SpaceConditionCFReachable, cfv1alpha1.ConditionFalse, readyConditionReasonCFUnavailable, err.Error())
+				result, err = unavailableResult, nil
+			} else {
+				result, err = r.HandleError(space, err, log)
+			}
 		}
-		if updateErr := r.Status().Update(ctx, space); updateErr != nil {
+		if updateErr := r.Status().Update(context.WithoutCancel(ctx), space); updateErr != nil {
 			err = utilerrors.NewAggregate([]error{err, updateErr})
 			result = ctrl.Result{}
 		}
 	}()
 
+	// While paused, nothing is read from or changed in Cloud Foundry; the annotation being removed triggers the next reconciliation
+	if isPaused(space.GetAnnotations()) {
+		space.SetCondition(cfv1alpha1.SpaceConditionPaused, cfv1alpha1.ConditionTrue, conditionReasonPaused, pausedMessage)
+		return ctrl.Result{}, nil
+	}
+	if space.GetCondition(cfv1alpha1.SpaceConditionPaused) != nil {
+		// persist the removal of the condition first (and requeue), since updates of the object (e.g. adding finalizers) reset the status
+		space.RemoveCondition(cfv1alpha1.SpaceConditionPaused)
+		return ctrl.Result{Requeue: true}, nil
+	}
+
 	// Set a first status (no need to requeue, because the status update itself will trigger another reconciliation)
+	status.MaxRetries = getMaxRetries(space.GetAnnotations(), spaceDefaultMaxRetries, log)
 	if ready := space.GetReadyCondition(); ready == nil {
 		space.SetReadyCondition(cfv1alpha1.ConditionUnknown, spaceReadyConditionReasonNew, "First seen")
 		return ctrl.Result{Requeue: true}, nil
 	}
 
 	//  Retrieve referenced secret
-	secretName := types.NamespacedName{Name: spec.AuthSecretName}
-	if space.IsNamespaced() {
-		secretName.Namespace = space.GetNamespace()
-	} else {
-		secretName.Namespace = r.ClusterResourceNamespace
+	secretName, err := getSpaceSecretName(space, r.ClusterResourceNamespace, r.Config)
+	if err != nil {
+		space.SetCondition(cfv1alpha1.SpaceConditionCredentialsReady, cfv1alpha1.ConditionFalse, conditionReasonError, err.Error())
+		return ctrl.Result{}, err
 	}
 	secret := &corev1.Secret{}
 	if err := r.Get(ctx, secretName, secret); err != nil {
-		return ctrl.Result{}, errors.Wrapf(err, "failed to get Secret containing space credentials, secret name: %s", secretName)
+		err = errors.Wrapf(err, "failed to get Secret containing space credentials, secret name: %s", secretName)
+		space.SetCondition(cfv1alpha1.SpaceConditionCredentialsReady, cfv1alpha1.ConditionFalse, conditionReasonError, err.Error())
+		return ctrl.Result{}, err
 	}
+	status.Endpoint = r.selectEndpoint(ctx, space, secret)
 
-	// Find depending service instances
-	serviceInstanceList := &cfv1alpha1.ServiceInstanceList{}
-	if space.IsNamespaced() {
-		if err := client.NewNamespacedClient(r.Client, space.GetNamespace()).List(
-			ctx,
-			serviceInstanceList,
-			client.MatchingLabels{cfv1alpha1.LabelKeySpace: space.GetName()},
-		); err != nil {
-			return ctrl.Result{}, errors.Wrap(err, "failed to list depending service instances")
-		}
-	} else {
-		if err := r.Client.List(
-			ctx,
-			serviceInstanceList,
-			client.MatchingLabels{cfv1alpha1.LabelKeyClusterSpace: space.GetName()},
-		); err != nil {
-			return ctrl.Result{}, errors.Wrap(err, "failed to list depending service instances")
+	// In observe-only mode, the cloud foundry space is only read; nothing is created, updated or deleted, and no finalizers are added
+	if isObserveOnly(r.Config, space.GetAnnotations()) {
+		if !space.GetDeletionTimestamp().IsZero() {
+			// the cloud foundry space is left untouched; just release the objects (if the space was managed before)
+			if containsString(secret.GetFinalizers(), spaceFinalizer) {
+				controllerutil.RemoveFinalizer(secret, spaceFinalizer)
+				if err := r.Update(ctx, secret); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			if containsString(space.GetFinalizers(), spaceFinalizer) {
+				controllerutil.RemoveFinalizer(space, spaceFinalizer)
+				if err := r.Update(ctx, space); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			skipStatusUpdate = true
+			return ctrl.Result{}, nil
 		}
+		return r.observeSpace(ctx, space, secret, secretName)
+	}
+
+	// Find depending service instances, and service bindings referencing service instances of the space by guid
+	serviceInstanceList, err := r.listServiceInstances(ctx, space)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	serviceBindingList, err := r.listServiceBindingsByGuid(ctx, space)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 
 	var client facade.OrganizationClient
 	var cfspace *facade.Space
 	if spec.Guid == "" {
 		// Build cloud foundry client
-		url := string(secret.Data["url"])
-		username := string(secret.Data["org_username"])
-		password := string(secret.Data["org_password"])
-		if username == "" || password == "" {
-			username = string(secret.Data["username"])
-			password = string(secret.Data["password"])
-		}
-
-		client, err = r.ClientBuilder(spec.OrganizationName, url, username, password)
+		client, err = r.buildOrganizationClient(space, secret, status.Endpoint)
 		if err != nil {
 			return ctrl.Result{}, errors.Wrapf(err, "failed to build the client from secret %s", secretName)
 		}
@@ -177,12 +244,13 @@ func (r *SpaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resu
 		if spec.Guid == "" {
 			if cfspace == nil {
 				log.V(1).Info("Creating space")
-				if err := client.CreateSpace(
+				cfspace, err = client.CreateSpace(
 					ctx,
 					spec.Name,
-					string(space.GetUID()),
+					facade.OwnerRef{UID: string(space.GetUID())},
 					space.GetGeneration(),
-				); err != nil {
+				)
+				if err != nil {
 					return ctrl.Result{}, err
 				}
 				status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
@@ -196,6 +264,7 @@ func (r *SpaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resu
 					if err := client.UpdateSpace(
 						ctx,
 						cfspace.Guid,
+						cfspace.OwnerRef(),
 						updateName,
 						space.GetGeneration(),
 					); err != nil {
@@ -207,7 +276,7 @@ func (r *SpaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resu
 				}
 			}
 			if cfspace == nil {
-				// Re-retrieve cloud foundry space; this happens exactly if the space was created or updated above
+				// Re-retrieve cloud foundry space; this happens exactly if the space was updated above
 				log.V(1).Info("Retrieving space")
 				cfspace, err = client.GetSpace(ctx, string(space.GetUID()))
 				if err != nil {
@@ -217,43 +286,72 @@ func (r *SpaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resu
 					return ctrl.Result{}, fmt.Errorf("unexpected error; space not found in cloud foundry although it should exist")
 				}
 			}
-			// TODO: the following is not very clean; if the user referenced by the secret changes, we leave the previous one orphaned;
-			// maybe we should clean it up somehow (but how ... what if that previous user has already been taken over by another manager, such as CAM?)
 			log.V(1).Info("Adding developer")
-			if err := client.AddDeveloper(ctx, cfspace.Guid, string(secret.Data["username"])); err != nil {
+			if err := rotateSpaceUser(ctx, client, cfspace.Guid, spec, status, string(secret.Data["username"])); err != nil {
 				return ctrl.Result{}, err
 			}
 			status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
+			log.V(1).Info("Reconciling space roles")
+			if err := reconcileSpaceRoles(ctx, client, cfspace.Guid, spec, status, string(secret.Data["username"])); err != nil {
+				return ctrl.Result{}, err
+			}
 			status.SpaceGuid = cfspace.Guid
 		} else {
 			status.SpaceGuid = spec.Guid
 		}
 
-		url := string(secret.Data["url"])
 		username := string(secret.Data["username"])
 		password := string(secret.Data["password"])
-		checker, err := r.HealthCheckerBuilder(status.SpaceGuid, url, username, password)
+		checker, err := r.HealthCheckerBuilder(status.SpaceGuid, status.Endpoint, username, password, getClientConfig(getSpaceConfig(r.Config, space), secret))
 		if err != nil {
 			return ctrl.Result{}, errors.Wrapf(err, "failed to build the healthchecker from secret %s", secretName)
 		}
 
-		log.V(1).Info("Checking space")
-		if err := checker.Check(ctx); err != nil {
-			return ctrl.Result{}, errors.Wrap(err, "healthcheck failed")
+		// retrieving an externally managed space by guid already verifies that it is accessible
+		if spec.Guid != "" {
+			if err := verifySpaceGuid(ctx, space, checker, secretName); err != nil {
+				return ctrl.Result{}, err
+			}
+		} else {
+			log.V(1).Info("Checking space")
+			if err := checker.Check(ctx); err != nil {
+				err = errors.Wrap(err, "healthcheck failed")
+				if _, ok := cfUnavailableResult(err); !ok {
+					space.SetCondition(cfv1alpha1.SpaceConditionCredentialsReady, cfv1alpha1.ConditionFalse, conditionReasonError, err.Error())
+				}
+				return ctrl.Result{}, err
+			}
 		}
 
 		log.V(1).Info("Healthcheck successful")
-		space.SetReadyCondition(cfv1alpha1.ConditionTrue, spaceReadyConditionReasonSuccess, "Success")
-		return getPollingInterval(space.GetAnnotations(), "60s", cfv1alpha1.AnnotationPollingIntervalReady), nil
+		runSpaceHealthProbes(ctx, space, checker, username)
+		r.updateSpaceUsage(ctx, space, checker, serviceInstanceList.Items, serviceBindingList.Items)
+		space.SetCondition(cfv1alpha1.SpaceConditionCredentialsReady, cfv1alpha1.ConditionTrue, conditionReasonCredentialsValid, fmt.Sprintf("Space is accessible with the credentials of secret %s", secretName))
+		space.SetCondition(cfv1alpha1.SpaceConditionSynced, cfv1alpha1.ConditionTrue, conditionReasonSynced, "Cloud Foundry space reflects the current spec")
+		status.RetryCounter = 0 // Reset the retry counter
+		if status.ManagementMode == cfv1alpha1.SpaceManagementModeExternal {
+			space.SetReadyCondition(cfv1alpha1.ConditionTrue, spaceReadyConditionReasonSuccess, "Success (space is managed externally; it will not be modified or deleted by the operator)")
+		} else {
+			space.SetReadyCondition(cfv1alpha1.ConditionTrue, spaceReadyConditionReasonSuccess, "Success")
+		}
+		return getPollingInterval(space.GetAnnotations(), getDefaultPollingIntervalReady(r.Config, r.Kind), cfv1alpha1.AnnotationPollingIntervalReady), nil
 	} else if len(serviceInstanceList.Items) > 0 {
 		space.SetReadyCondition(cfv1alpha1.ConditionUnknown, spaceReadyConditionReasonDeletionBlocked, "Waiting for deletion of depending service instances")
+		space.SetCondition(cfv1alpha1.SpaceConditionDeletionBlocked, cfv1alpha1.ConditionTrue, conditionReasonDependentsExist, "Waiting for deletion of depending service instances")
+		// TODO: apply some increasing period, depending on the age of the last update
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	} else if len(serviceBindingList.Items) > 0 {
+		space.SetReadyCondition(cfv1alpha1.ConditionUnknown, spaceReadyConditionReasonDeletionBlocked, "Waiting for deletion of depending service bindings")
+		space.SetCondition(cfv1alpha1.SpaceConditionDeletionBlocked, cfv1alpha1.ConditionTrue, conditionReasonDependentsExist, "Waiting for deletion of depending service bindings")
 		// TODO: apply some increasing period, depending on the age of the last update
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 	} else if len(removeString(space.GetFinalizers(), spaceFinalizer)) > 0 {
 		space.SetReadyCondition(cfv1alpha1.ConditionUnknown, spaceReadyConditionReasonDeletionBlocked, "Deletion blocked due to foreign finalizers")
+		space.SetCondition(cfv1alpha1.SpaceConditionDeletionBlocked, cfv1alpha1.ConditionTrue, conditionReasonForeignFinalizers, "Deletion blocked due to foreign finalizers")
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 		// TODO: apply some increasing period, depending on the age of the last update
 	} else {
+		space.RemoveCondition(cfv1alpha1.SpaceConditionDeletionBlocked)
 		if cfspace == nil {
 			if containsString(secret.GetFinalizers(), spaceFinalizer) {
 				controllerutil.RemoveFinalizer(secret, spaceFinalizer)
@@ -273,18 +371,499 @@ func (r *SpaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resu
 			skipStatusUpdate = true
 			return ctrl.Result{}, nil
 		} else {
+			message := "Deletion triggered"
+			if spec.DeletionPolicy == cfv1alpha1.SpaceDeletionPolicyCascade {
+				remaining, foreign, err := r.deleteSpaceContents(ctx, space, cfspace.Guid, secret)
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+				if remaining > 0 {
+					message := fmt.Sprintf("Waiting for deletion of %d Cloud Foundry service instances and bindings in the space", remaining)
+					space.SetReadyCondition(cfv1alpha1.ConditionUnknown, spaceReadyConditionReasonDeletionBlocked, message)
+					space.SetCondition(cfv1alpha1.SpaceConditionDeletionBlocked, cfv1alpha1.ConditionTrue, conditionReasonDependentsExist, message)
+					// TODO: apply some increasing period, depending on the age of the last update
+					return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+				}
+				if len(foreign) > 0 {
+					// foreign leftovers do not block the deletion (but Cloud Foundry may refuse to delete the space because of them)
+					log.Info("Leaving Cloud Foundry resources not owned by this cluster in the space", "resources", foreign)
+					message = fmt.Sprintf("Deletion triggered; left %d Cloud Foundry resources not owned by this cluster in the space: %s", len(foreign), strings.Join(foreign, ", "))
+				}
+			}
 			log.V(1).Info("Deleting space")
-			if err := client.DeleteSpace(ctx, cfspace.Guid); err != nil {
+			if err := client.DeleteSpace(ctx, cfspace.Guid, cfspace.OwnerRef()); err != nil {
 				return ctrl.Result{}, err
 			}
 			status.LastModifiedAt = &[]metav1.Time{metav1.Now()}[0]
-			space.SetReadyCondition(cfv1alpha1.ConditionUnknown, spaceReadyConditionDeleting, "Deletion triggered")
+			space.SetReadyCondition(cfv1alpha1.ConditionUnknown, spaceReadyConditionDeleting, message)
 			// TODO: apply some increasing period, depending on the age of the last update
 			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 		}
 	}
 }
 
+// HandleError sets conditions and the result to handle the error.
+// Failed reconciliations are retried (with the exponential backoff of the controller) until the maximum number of retries
+// (status.maxRetries) is exceeded; after that, the space is only re-synced in the interval given by the polling-interval-fail
+// annotation (or the configured default), or when it is changed.
+func (r *SpaceReconciler) HandleError(space cfv1alpha1.GenericSpace, issue error, log logr.Logger) (ctrl.Result, error) {
+	status := space.GetStatus()
+	reason, message := describeError(issue, spaceReadyConditionReasonError)
+	syncedReason, _ := describeError(issue, conditionReasonError)
+	space.SetCondition(cfv1alpha1.SpaceConditionSynced, cfv1alpha1.ConditionFalse, syncedReason, message)
+
+	// Check if the retry counter exceeds the maximum allowed retries.
+	status.RetryCounter++
+	if status.MaxRetries != spaceDefaultMaxRetries && status.RetryCounter >= status.MaxRetries {
+		log.Info("Maximum number of retries exceeded", "retryCounter", status.RetryCounter, "error", issue.Error())
+		space.SetReadyCondition(cfv1alpha1.ConditionFalse, spaceReadyConditionReasonMaxRetries, fmt.Sprintf("The space has failed due to too many retries: %s", message))
+		return getPollingInterval(space.GetAnnotations(), getDefaultPollingIntervalFail(r.Config, r.Kind), cfv1alpha1.AnnotationPollingIntervalFail), nil // finish reconcile loop
+	}
+	space.SetReadyCondition(cfv1alpha1.ConditionFalse, reason, message)
+	return ctrl.Result{}, issue
+}
+
+// deleteSpaceContents deletes the service bindings and service instances created by the operator in the given Cloud Foundry space
+// (bindings first, since instances with bindings cannot be deleted), and returns the number of such resources still existing.
+// Only resources whose owner resolves to this cluster (see ownerResolver) are deleted; other resources (for example created by operators
+// in other clusters sharing the space) are left untouched, and returned as foreign leftovers (which do not count as remaining).
+// Failed deletions do not stop the deletion of the other resources; they are returned as (aggregated) error.
+func (r *SpaceReconciler) deleteSpaceContents(ctx context.Context, space cfv1alpha1.GenericSpace, guid string, secret *corev1.Secret) (int, []string, error) {
+	log := ctrl.LoggerFrom(ctx)
+	if r.SpaceClientBuilder == nil {
+		return 0, nil, fmt.Errorf("deletion policy %s is not supported by this controller", cfv1alpha1.SpaceDeletionPolicyCascade)
+	}
+	spaceClient, err := r.SpaceClientBuilder(guid, space.GetStatus().Endpoint, string(secret.Data["username"]), string(secret.Data["password"]), getClientConfig(getSpaceConfig(r.Config, space), secret))
+	if err != nil {
+		return 0, nil, errors.Wrapf(err, "failed to build the client from secret %s", secret.Name)
+	}
+	resolver, err := newOwnerResolver(ctx, r.Client, r.Config)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var foreign []string
+	var errs []error
+	bindings, err := spaceClient.ListBindings(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	remaining := 0
+	for _, binding := range bindings {
+		if ok, err := resolver.resolves(ctx, binding.OwnerRef(), binding.OwnerCluster); err != nil {
+			return 0, nil, err
+		} else if !ok {
+			foreign = append(foreign, fmt.Sprintf("binding %s (%s)", binding.Name, binding.Guid))
+			continue
+		}
+		remaining++
+		if binding.State == facade.BindingStateDeleting || binding.State == facade.BindingStateDeleted {
+			continue
+		}
+		log.V(1).Info("Deleting binding (cascading deletion of space)", "guid", binding.Guid, "name", binding.Name)
+		if err := spaceClient.DeleteBinding(ctx, binding.Guid, binding.OwnerRef()); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to delete service binding %s", binding.Guid))
+		}
+	}
+	if remaining > 0 || len(errs) > 0 {
+		return remaining, foreign, utilerrors.NewAggregate(errs)
+	}
+
+	instances, err := spaceClient.ListInstances(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, instance := range instances {
+		if ok, err := resolver.resolves(ctx, instance.OwnerRef(), instance.OwnerCluster); err != nil {
+			return 0, nil, err
+		} else if !ok {
+			foreign = append(foreign, fmt.Sprintf("instance %s (%s)", instance.Name, instance.Guid))
+			continue
+		}
+		remaining++
+		if instance.State == facade.InstanceStateDeleting || instance.State == facade.InstanceStateDeleted {
+			continue
+		}
+		log.V(1).Info("Deleting instance (cascading deletion of space)", "guid", instance.Guid, "name", instance.Name)
+		if err := spaceClient.DeleteInstance(ctx, instance.Guid, instance.OwnerRef()); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to delete service instance %s", instance.Guid))
+		}
+	}
+	return remaining, foreign, utilerrors.NewAggregate(errs)
+}
+
+// selectEndpoint returns the Cloud Foundry API endpoint to be used for the given space (recorded in status.endpoint); that is,
+// the url of the space secret, unless the secret specifies a failoverUrl, and the url is found unreachable while the failoverUrl
+// is reachable. Since the url is probed at every reconciliation, the space fails back to it once it is reachable again.
+// Annotation AnnotationCFEndpoint on the space pins either of both endpoints.
+func (r *SpaceReconciler) selectEndpoint(ctx context.Context, space cfv1alpha1.GenericSpace, secret *corev1.Secret) string {
+	log := ctrl.LoggerFrom(ctx)
+	url := string(secret.Data[cfv1alpha1.SpaceSecretKeyURL])
+	failoverURL := string(secret.Data[cfv1alpha1.SpaceSecretKeyFailoverURL])
+	switch space.GetAnnotations()[cfv1alpha1.AnnotationCFEndpoint] {
+	case cfv1alpha1.CFEndpointPrimary, cfv1alpha1.CFEndpointFailover:
+		return getEndpoint(space, secret, space.GetAnnotations())
+	}
+	if failoverURL == "" || r.EndpointProber == nil {
+		return url
+	}
+
+	previous := space.GetStatus().Endpoint
+	cfg := getClientConfig(getSpaceConfig(r.Config, space), secret)
+	err := r.EndpointProber(ctx, url, cfg)
+	if err == nil {
+		if previous == failoverURL {
+			log.Info("Cloud Foundry API endpoint reachable again; failing back", "url", url)
+		}
+		return url
+	}
+	if failoverErr := r.EndpointProber(ctx, failoverURL, cfg); failoverErr != nil {
+		// both endpoints are unreachable; keep using the url, such that the according errors are reported as usual
+		log.V(1).Info("Cloud Foundry API failover endpoint unreachable", "failoverUrl", failoverURL, "error", failoverErr.Error())
+		return url
+	}
+	if previous != failoverURL {
+		log.Info("Cloud Foundry API endpoint unreachable; failing over", "url", url, "failoverUrl", failoverURL, "error", err.Error())
+	}
+	return failoverURL
+}
+
+// buildOrganizationClient builds a client (using the given endpoint) for the organization of the given space, preferring
+// the organization credentials (keys org_username, org_password) of the space secret over the space credentials.
+func (r *SpaceReconciler) buildOrganizationClient(space cfv1alpha1.GenericSpace, secret *corev1.Secret, url string) (facade.OrganizationClient, error) {
+	username := string(secret.Data["org_username"])
+	password := string(secret.Data["org_password"])
+	if username == "" || password == "" {
+		username = string(secret.Data["username"])
+		password = string(secret.Data["password"])
+	}
+	return r.ClientBuilder(space.GetSpec().OrganizationName, url, username, password, getClientConfig(getSpaceConfig(r.Config, space), secret))
+}
+
+// observeSpace reflects the state of the cloud foundry space in the status of the given space, without changing anything
+// (observe-only mode); spaces managed by the operator are looked up by owner, so they can only be observed after they were created by the operator.
+func (r *SpaceReconciler) observeSpace(ctx context.Context, space cfv1alpha1.GenericSpace, secret *corev1.Secret, secretName types.NamespacedName) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	spec := space.GetSpec()
+	status := space.GetStatus()
+
+	space.SetCondition(cfv1alpha1.SpaceConditionSynced, cfv1alpha1.ConditionUnknown, conditionReasonObserveOnly, "Changes are not applied in observe-only mode")
+	status.SpaceGuid = spec.Guid
+	if spec.Guid == "" {
+		client, err := r.buildOrganizationClient(space, secret, status.Endpoint)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to build the client from secret %s", secretName)
+		}
+		log.V(1).Info("Retrieving space (observe-only)")
+		cfspace, err := client.GetSpace(ctx, string(space.GetUID()))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if cfspace == nil {
+			space.SetReadyCondition(cfv1alpha1.ConditionFalse, readyConditionReasonNotFound, "Cloud Foundry space not found (observe-only mode; it will not be created)")
+			return getPollingInterval(space.GetAnnotations(), getDefaultPollingIntervalReady(r.Config, r.Kind), cfv1alpha1.AnnotationPollingIntervalReady), nil
+		}
+		status.SpaceGuid = cfspace.Guid
+	}
+
+	checker, err := r.HealthCheckerBuilder(status.SpaceGuid, status.Endpoint, string(secret.Data["username"]), string(secret.Data["password"]), getClientConfig(getSpaceConfig(r.Config, space), secret))
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to build the healthchecker from secret %s", secretName)
+	}
+	if spec.Guid != "" {
+		if err := verifySpaceGuid(ctx, space, checker, secretName); err != nil {
+			return ctrl.Result{}, err
+		}
+	} else {
+		log.V(1).Info("Checking space")
+		if err := checker.Check(ctx); err != nil {
+			err = errors.Wrapf(err, "healthcheck of space %s failed (the space must exist, and be accessible with the credentials of secret %s)", status.SpaceGuid, secretName)
+			if _, ok := cfUnavailableResult(err); !ok {
+				space.SetCondition(cfv1alpha1.SpaceConditionCredentialsReady, cfv1alpha1.ConditionFalse, conditionReasonError, err.Error())
+			}
+			return ctrl.Result{}, err
+		}
+	}
+	runSpaceHealthProbes(ctx, space, checker, string(secret.Data["username"]))
+	serviceInstanceList, err := r.listServiceInstances(ctx, space)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	serviceBindingList, err := r.listServiceBindingsByGuid(ctx, space)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.updateSpaceUsage(ctx, space, checker, serviceInstanceList.Items, serviceBindingList.Items)
+	space.SetCondition(cfv1alpha1.SpaceConditionCredentialsReady, cfv1alpha1.ConditionTrue, conditionReasonCredentialsValid, fmt.Sprintf("Space is accessible with the credentials of secret %s", secretName))
+	space.SetReadyCondition(cfv1alpha1.ConditionTrue, spaceReadyConditionReasonSuccess, "Success (observe-only mode; the space will not be modified or deleted by the operator)")
+	return getPollingInterval(space.GetAnnotations(), getDefaultPollingIntervalReady(r.Config, r.Kind), cfv1alpha1.AnnotationPollingIntervalReady), nil
+}
+
+// listServiceInstances returns the service instances referencing the given space.
+func (r *SpaceReconciler) listServiceInstances(ctx context.Context, space cfv1alpha1.GenericSpace) (*cfv1alpha1.ServiceInstanceList, error) {
+	serviceInstanceList := &cfv1alpha1.ServiceInstanceList{}
+	if space.IsNamespaced() {
+		if err := indexReader(r.CacheReader, r.Client).List(
+			ctx,
+			serviceInstanceList,
+			client.InNamespace(space.GetNamespace()),
+			client.MatchingFields{indexServiceInstanceSpaceName: space.GetName()},
+		); err != nil {
+			return nil, errors.Wrap(err, "failed to list depending service instances")
+		}
+	} else {
+		if err := indexReader(r.CacheReader, r.Client).List(
+			ctx,
+			serviceInstanceList,
+			client.MatchingFields{indexServiceInstanceClusterSpaceName: space.GetName()},
+		); err != nil {
+			return nil, errors.Wrap(err, "failed to list depending service instances")
+		}
+	}
+	return serviceInstanceList, nil
+}
+
+// listServiceBindingsByGuid returns the service bindings referencing a service instance of the given space by guid
+// (spec.serviceInstanceGuid, together with spec.spaceName resp. spec.clusterSpaceName).
+func (r *SpaceReconciler) listServiceBindingsByGuid(ctx context.Context, space cfv1alpha1.GenericSpace) (*cfv1alpha1.ServiceBindingList, error) {
+	serviceBindingList := &cfv1alpha1.ServiceBindingList{}
+	listOptions := []client.ListOption{client.MatchingFields{indexServiceBindingClusterSpaceName: space.GetName()}}
+	if space.IsNamespaced() {
+		listOptions = []client.ListOption{client.InNamespace(space.GetNamespace()), client.MatchingFields{indexServiceBindingSpaceName: space.GetName()}}
+	}
+	if err := indexReader(r.CacheReader, r.Client).List(ctx, serviceBindingList, listOptions...); err != nil {
+		return nil, errors.Wrap(err, "failed to list depending service bindings")
+	}
+	return serviceBindingList, nil
+}
+
+// updateSpaceUsage reports the number of service instances and bindings managed through the given space, and the usage of the
+// service instances quota of the Cloud Foundry space, in the status of the space; failures to determine the number of bindings
+// or the quota usage are only logged (and the previously reported number of bindings is kept).
+func (r *SpaceReconciler) updateSpaceUsage(ctx context.Context, space cfv1alpha1.GenericSpace, checker facade.SpaceHealthChecker, serviceInstances []cfv1alpha1.ServiceInstance, guidServiceBindings []cfv1alpha1.ServiceBinding) {
+	log := ctrl.LoggerFrom(ctx)
+
+	usage := &cfv1alpha1.SpaceUsage{ManagedServiceInstances: len(serviceInstances), ManagedServiceBindings: len(guidServiceBindings)}
+	if len(serviceInstances) > 0 {
+		// count the bindings of all instances through a single (cached) list, instead of one list per instance
+		serviceInstanceNames := make(map[types.NamespacedName]bool, len(serviceInstances))
+		for _, serviceInstance := range serviceInstances {
+			serviceInstanceNames[types.NamespacedName{Namespace: serviceInstance.Namespace, Name: serviceInstance.Name}] = true
+		}
+		var listOptions []client.ListOption
+		if space.IsNamespaced() {
+			listOptions = append(listOptions, client.InNamespace(space.GetNamespace()))
+		}
+		serviceBindingList := &cfv1alpha1.ServiceBindingList{}
+		if err := indexReader(r.CacheReader, r.Client).List(ctx, serviceBindingList, listOptions...); err != nil {
+			log.Error(err, "Failed to determine the number of managed service bindings")
+			if previousUsage := space.GetStatus().Usage; previousUsage != nil {
+				usage.ManagedServiceBindings = previousUsage.ManagedServiceBindings
+			}
+		} else {
+			for _, serviceBinding := range serviceBindingList.Items {
+				if serviceInstanceNames[types.NamespacedName{Namespace: serviceBinding.Namespace, Name: serviceBinding.Spec.ServiceInstanceName}] {
+					usage.ManagedServiceBindings++
+				}
+			}
+		}
+	}
+
+	quotaUsage, err := checker.GetQuotaUsage(ctx)
+	if err != nil {
+		log.V(1).Info("Failed to determine quota usage", "error", err.Error())
+	} else if quotaUsage != nil {
+		usage.ServiceInstances = &quotaUsage.ServiceInstances
+		usage.QuotaName = quotaUsage.QuotaName
+		if quotaUsage.ServiceInstancesLimit >= 0 {
+			usage.ServiceInstancesLimit = &quotaUsage.ServiceInstancesLimit
+		}
+	}
+	space.GetStatus().Usage = usage
+}
+
+// verifySpaceGuid checks that the Cloud Foundry space referenced by spec.guid of the given space exists, and is accessible
+// with the credentials of the space secret; otherwise, the CredentialsReady condition is set accordingly, and an error is returned.
+func verifySpaceGuid(ctx context.Context, space cfv1alpha1.GenericSpace, checker facade.SpaceHealthChecker, secretName types.NamespacedName) error {
+	log := ctrl.LoggerFrom(ctx)
+	guid := space.GetSpec().Guid
+
+	log.V(1).Info("Retrieving space by guid", "guid", guid)
+	cfspace, err := checker.GetSpaceByGuid(ctx, guid)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve space %s", guid)
+	}
+	if cfspace == nil {
+		err := fmt.Errorf("space %s not found (the space must exist, and be accessible with the credentials of secret %s)", guid, secretName)
+		space.SetCondition(cfv1alpha1.SpaceConditionCredentialsReady, cfv1alpha1.ConditionFalse, readyConditionReasonNotFound, err.Error())
+		return err
+	}
+	return nil
+}
+
+// runSpaceHealthProbes runs the health probes enabled by spec.healthCheck of the given space (at most once per interval, unless a probe
+// was newly enabled), and reports their results as conditions; conditions of disabled probes are removed.
+// The probes are informational; failures do not affect the readiness of the space.
+func runSpaceHealthProbes(ctx context.Context, space cfv1alpha1.GenericSpace, checker facade.SpaceHealthChecker, username string) {
+	log := ctrl.LoggerFrom(ctx)
+	healthCheck := space.GetSpec().HealthCheck
+	status := space.GetStatus()
+
+	var probes []cfv1alpha1.SpaceHealthProbe
+	if healthCheck != nil {
+		probes = healthCheck.Probes
+	}
+	for probe, conditionType := range spaceHealthProbeConditions {
+		if !slices.Contains(probes, probe) {
+			space.RemoveCondition(conditionType)
+		}
+	}
+	if len(probes) == 0 {
+		status.LastHealthCheckAt = nil
+		return
+	}
+
+	interval := 10 * time.Minute
+	if healthCheck.Interval != nil {
+		interval = healthCheck.Interval.Duration
+	}
+	due := status.LastHealthCheckAt == nil || time.Since(status.LastHealthCheckAt.Time) >= interval
+	for _, probe := range probes {
+		if space.GetCondition(spaceHealthProbeConditions[probe]) == nil {
+			due = true
+		}
+	}
+	if !due {
+		return
+	}
+
+	threshold := 90
+	if healthCheck.QuotaThreshold != nil {
+		threshold = *healthCheck.QuotaThreshold
+	}
+	for _, probe := range probes {
+		var err error
+		var message string
+		switch probe {
+		case cfv1alpha1.SpaceHealthProbeServicePlans:
+			err = checker.CheckServicePlans(ctx)
+			message = "Service plans are available in the space"
+		case cfv1alpha1.SpaceHealthProbeQuota:
+			err = checker.CheckQuota(ctx, threshold)
+			message = fmt.Sprintf("Service instances quota of the space is used by no more than %d%%", threshold)
+		case cfv1alpha1.SpaceHealthProbeDeveloperRole:
+			err = checker.CheckDeveloperRole(ctx, username)
+			message = fmt.Sprintf("User %s has the space developer role", username)
+		default:
+			continue
+		}
+		if err != nil {
+			log.V(1).Info("Health probe failed", "probe", probe, "error", err.Error())
+			space.SetCondition(spaceHealthProbeConditions[probe], cfv1alpha1.ConditionFalse, spaceConditionReasonProbeFailed, err.Error())
+		} else {
+			space.SetCondition(spaceHealthProbeConditions[probe], cfv1alpha1.ConditionTrue, spaceConditionReasonProbeSucceeded, message)
+		}
+	}
+	status.LastHealthCheckAt = &[]metav1.Time{metav1.Now()}[0]
+}
+
+// Assign the space developer role to the user referenced by the space secret. If that user changed since the last reconcile
+// (credential rotation), the rotation is recorded in the status; with credential rotation policy Remove, the developer role
+// of the previous user is removed, unless that user is listed in spec.developers.
+func rotateSpaceUser(ctx context.Context, client facade.OrganizationClient, guid string, spec *cfv1alpha1.SpaceSpec, status *cfv1alpha1.SpaceStatus, username string) error {
+	if err := client.AddDeveloper(ctx, guid, username, ""); err != nil {
+		return err
+	}
+	previousUsername := status.Username
+	if previousUsername == "" || previousUsername == username {
+		status.Username = username
+		return nil
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+	log.Info("User of the space secret changed", "previousUsername", previousUsername, "username", username)
+	rotation := &cfv1alpha1.SpaceCredentialRotation{
+		PreviousUsername: previousUsername,
+		Username:         username,
+		RotatedAt:        metav1.Now(),
+	}
+	if spec.CredentialRotationPolicy == cfv1alpha1.SpaceCredentialRotationPolicyRemove && !containsSpaceUsername(spec.Developers, previousUsername) {
+		if err := client.RemoveDeveloper(ctx, guid, previousUsername, ""); err != nil {
+			// status.username still refers to the previous user, such that the removal is retried
+			return errors.Wrapf(err, "failed to remove space developer role of previous user %s", previousUsername)
+		}
+		rotation.PreviousUserRemoved = true
+	}
+	status.Username = username
+	status.LastCredentialRotation = rotation
+	return nil
+}
+
+// Assign the space roles listed in the spec, and remove the roles which were assigned earlier, but are no longer listed;
+// the developer role of the user referenced by the space secret (which is assigned without origin) is never removed, whatever origin it is listed with.
+func reconcileSpaceRoles(ctx context.Context, client facade.OrganizationClient, guid string, spec *cfv1alpha1.SpaceSpec, status *cfv1alpha1.SpaceStatus, username string) error {
+	var err error
+	status.Developers, err = reconcileSpaceRole(ctx, guid, spec.Developers, status.Developers, client.AddDeveloper, client.RemoveDeveloper, username)
+	if err != nil {
+		return errors.Wrap(err, "failed to reconcile space developers")
+	}
+	status.Auditors, err = reconcileSpaceRole(ctx, guid, spec.Auditors, status.Auditors, client.AddAuditor, client.RemoveAuditor, "")
+	if err != nil {
+		return errors.Wrap(err, "failed to reconcile space auditors")
+	}
+	status.Managers, err = reconcileSpaceRole(ctx, guid, spec.Managers, status.Managers, client.AddManager, client.RemoveManager, "")
+	if err != nil {
+		return errors.Wrap(err, "failed to reconcile space managers")
+	}
+	return nil
+}
+
+// Reconcile the members of one space role; returns the users which have the role assigned afterwards (as far as managed by the operator),
+// also in case of errors, such that the list can be stored in the status. Users are identified by user name and origin; the role of the
+// user with the given protected user name (if any) is not removed, regardless of its origin.
+func reconcileSpaceRole(ctx context.Context, guid string, desired []cfv1alpha1.SpaceUser, assigned []cfv1alpha1.SpaceUser,
+	add func(context.Context, string, string, string) error, remove func(context.Context, string, string, string) error, protectedUsername string) ([]cfv1alpha1.SpaceUser, error) {
+	var result []cfv1alpha1.SpaceUser
+	for _, user := range assigned {
+		if containsSpaceUser(desired, user) {
+			result = append(result, user)
+			continue
+		}
+		if protectedUsername == "" || user.Username != protectedUsername {
+			if err := remove(ctx, guid, user.Username, user.Origin); err != nil {
+				return append(result, user), err
+			}
+		}
+	}
+	for _, user := range desired {
+		if err := add(ctx, guid, user.Username, user.Origin); err != nil {
+			return result, err
+		}
+		if !containsSpaceUser(result, user) {
+			result = append(result, user)
+		}
+	}
+	return result, nil
+}
+
+func containsSpaceUsername(users []cfv1alpha1.SpaceUser, username string) bool {
+	for _, u := range users {
+		if u.Username == username {
+			return true
+		}
+	}
+	return false
+}
+
+func containsSpaceUser(users []cfv1alpha1.SpaceUser, user cfv1alpha1.SpaceUser) bool {
+	for _, u := range users {
+		if u == user {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *SpaceReconciler) newSpace() (cfv1alpha1.GenericSpace, error) {
 	spaceGVK := cfv1alpha1.GroupVersion.WithKind(r.Kind)
 	obj, err := r.Scheme.New(spaceGVK)
@@ -304,8 +883,25 @@ func (r *SpaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if err != nil {
 		return err
 	}
+	reconciler, options := limitConcurrency(traceReconciles(measureReconciles(labelReconciles(r, r.Kind), r.Kind), r.Kind), r.Config, endpointFromStatus(
+		mgr.GetCache(),
+		func() client.Object { return spaceType.DeepCopyObject().(client.Object) },
+		func(obj client.Object) string { return obj.(cfv1alpha1.GenericSpace).GetStatus().Endpoint },
+	))
+	reconciler, namespacePredicate := filterNamespaces(reconciler, mgr.GetClient(), r.Config)
+	if r.CacheReader == nil {
+		r.CacheReader = mgr.GetCache()
+	}
+	if err := addServiceInstanceSpaceIndex(mgr, r.Kind); err != nil {
+		return err
+	}
+	if err := addServiceBindingSpaceIndex(mgr, r.Kind); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(spaceType).
 		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})).
-		Complete(r)
+		WithEventFilter(namespacePredicate).
+		WithOptions(options).
+		Complete(reconciler)
 }
@@ -45,10 +45,11 @@ var _ = Describe("Space Controller Integration Tests", func() {
 				Owner:      testCfOwner,
 				Generation: 1,
 			}
-			fakeOrgClient.CreateSpaceReturns(kNoError)
+			// CreateSpace returns the created space, so it is not retrieved again right after the creation
+			fakeOrgClient.CreateSpaceReturns(fakeSpace, kNoError)
 
 			// 0) GetSpace is called before CreateSpace to check existence => simulate non-existing space
-			// 1) GetSpace is called after CreateSpace to check current state => simulate ready space
+			// other) GetSpace is called by subsequent reconciles => simulate ready space
 			fakeOrgClient.GetSpaceReturnsOnCall(0, kNoSpace, kNoError)
 			fakeOrgClient.GetSpaceReturns(fakeSpace, kNoError)
 
@@ -58,7 +59,7 @@ var _ = Describe("Space Controller Integration Tests", func() {
 
 			// check expectations on reconcile loop
 			Expect(fakeOrgClient.CreateSpaceCallCount()).To(Equal(1))
-			Expect(fakeOrgClient.GetSpaceCallCount()).To(BeNumerically(">=", 4))
+			Expect(fakeOrgClient.GetSpaceCallCount()).To(BeNumerically(">=", 3))
 			// TODO: check if number of calls to GetSpace can be reduced
 		})
 	})
@@ -0,0 +1,775 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+	"github.com/sap/cf-service-operator/internal/facade/facadefakes"
+)
+
+var _ = Describe("Space health probes | runSpaceHealthProbes", func() {
+	ctx := context.Background()
+	var checker *facadefakes.FakeSpaceHealthChecker
+	var space *cfv1alpha1.Space
+
+	BeforeEach(func() {
+		checker = &facadefakes.FakeSpaceHealthChecker{}
+		space = &cfv1alpha1.Space{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space"},
+			Spec: cfv1alpha1.SpaceSpec{Guid: "space-guid", AuthSecretName: "space-secret", HealthCheck: &cfv1alpha1.SpaceHealthCheck{
+				Probes: []cfv1alpha1.SpaceHealthProbe{cfv1alpha1.SpaceHealthProbeServicePlans, cfv1alpha1.SpaceHealthProbeDeveloperRole},
+			}},
+		}
+	})
+
+	It("should report the result of every enabled probe as condition", func() {
+		checker.CheckDeveloperRoleReturns(errors.New("user admin does not have the space developer role"))
+
+		runSpaceHealthProbes(ctx, space, checker, "admin")
+
+		Expect(space.GetCondition(cfv1alpha1.SpaceConditionServicePlansAvailable).Status).To(Equal(cfv1alpha1.ConditionTrue))
+		Expect(space.GetCondition(cfv1alpha1.SpaceConditionDeveloperRoleAssigned).Status).To(Equal(cfv1alpha1.ConditionFalse))
+		Expect(space.GetCondition(cfv1alpha1.SpaceConditionDeveloperRoleAssigned).Message).To(ContainSubstring("does not have the space developer role"))
+		Expect(space.GetCondition(cfv1alpha1.SpaceConditionQuotaAvailable)).To(BeNil())
+		Expect(checker.CheckQuotaCallCount()).To(Equal(0))
+		_, username := checker.CheckDeveloperRoleArgsForCall(0)
+		Expect(username).To(Equal("admin"))
+		Expect(space.Status.LastHealthCheckAt).NotTo(BeNil())
+		// the space itself stays ready
+		Expect(space.GetReadyCondition()).To(BeNil())
+	})
+
+	It("should run the probes at most once per interval, unless a probe is enabled", func() {
+		space.Spec.HealthCheck.Interval = &metav1.Duration{Duration: time.Hour}
+		runSpaceHealthProbes(ctx, space, checker, "admin")
+		runSpaceHealthProbes(ctx, space, checker, "admin")
+		Expect(checker.CheckServicePlansCallCount()).To(Equal(1))
+
+		space.Spec.HealthCheck.Probes = append(space.Spec.HealthCheck.Probes, cfv1alpha1.SpaceHealthProbeQuota)
+		space.Spec.HealthCheck.QuotaThreshold = &[]int{75}[0]
+		runSpaceHealthProbes(ctx, space, checker, "admin")
+		Expect(checker.CheckServicePlansCallCount()).To(Equal(2))
+		_, threshold := checker.CheckQuotaArgsForCall(0)
+		Expect(threshold).To(Equal(75))
+	})
+
+	It("should remove the conditions of disabled probes", func() {
+		runSpaceHealthProbes(ctx, space, checker, "admin")
+		space.Spec.HealthCheck = nil
+		runSpaceHealthProbes(ctx, space, checker, "admin")
+
+		Expect(space.Status.Conditions).To(BeEmpty())
+		Expect(space.Status.LastHealthCheckAt).To(BeNil())
+	})
+})
+
+var _ = Describe("Space usage in status | updateSpaceUsage", func() {
+	ctx := context.Background()
+	var checker *facadefakes.FakeSpaceHealthChecker
+	var reconciler *SpaceReconciler
+	var space *cfv1alpha1.Space
+	var serviceInstances []cfv1alpha1.ServiceInstance
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		checker = &facadefakes.FakeSpaceHealthChecker{}
+		serviceInstances = []cfv1alpha1.ServiceInstance{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "instance-1"}, Spec: cfv1alpha1.ServiceInstanceSpec{SpaceName: "space"}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "instance-2"}, Spec: cfv1alpha1.ServiceInstanceSpec{SpaceName: "space"}},
+		}
+		reconciler = &SpaceReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&cfv1alpha1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "binding-1"}, Spec: cfv1alpha1.ServiceBindingSpec{ServiceInstanceName: "instance-1"}},
+			&cfv1alpha1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "binding-2"}, Spec: cfv1alpha1.ServiceBindingSpec{ServiceInstanceName: "instance-1"}},
+			&cfv1alpha1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "binding-3"}, Spec: cfv1alpha1.ServiceBindingSpec{ServiceInstanceName: "other"}},
+			&cfv1alpha1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "other-ns", Name: "binding-4"}, Spec: cfv1alpha1.ServiceBindingSpec{ServiceInstanceName: "instance-2"}},
+		).Build()}
+		space = &cfv1alpha1.Space{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space"}}
+	})
+
+	It("should report managed instances and bindings, and the quota usage", func() {
+		checker.GetQuotaUsageReturns(&facade.QuotaUsage{QuotaName: "small", ServiceInstances: 5, ServiceInstancesLimit: 10}, nil)
+
+		reconciler.updateSpaceUsage(ctx, space, checker, serviceInstances, nil)
+		usage := space.Status.Usage
+		Expect(usage.ManagedServiceInstances).To(Equal(2))
+		Expect(usage.ManagedServiceBindings).To(Equal(2))
+		Expect(usage.QuotaName).To(Equal("small"))
+		Expect(*usage.ServiceInstances).To(Equal(5))
+		Expect(*usage.ServiceInstancesLimit).To(Equal(10))
+	})
+
+	It("should omit the limit if the quota is unlimited, and the quota usage if it cannot be determined", func() {
+		checker.GetQuotaUsageReturns(&facade.QuotaUsage{ServiceInstances: 5, ServiceInstancesLimit: -1}, nil)
+		reconciler.updateSpaceUsage(ctx, space, checker, serviceInstances, nil)
+		Expect(*space.Status.Usage.ServiceInstances).To(Equal(5))
+		Expect(space.Status.Usage.ServiceInstancesLimit).To(BeNil())
+
+		checker.GetQuotaUsageReturns(nil, errors.New("forbidden"))
+		reconciler.updateSpaceUsage(ctx, space, checker, serviceInstances, nil)
+		Expect(space.Status.Usage.ManagedServiceBindings).To(Equal(2))
+		Expect(space.Status.Usage.ServiceInstances).To(BeNil())
+	})
+
+	It("should keep the previous number of bindings if the bindings cannot be listed", func() {
+		reconciler.Client = interceptor.NewClient(reconciler.Client.(client.WithWatch), interceptor.Funcs{
+			List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+				return errors.New("list failed")
+			},
+		})
+		space.Status.Usage = &cfv1alpha1.SpaceUsage{ManagedServiceBindings: 7}
+		reconciler.updateSpaceUsage(ctx, space, checker, serviceInstances, nil)
+		Expect(space.Status.Usage.ManagedServiceInstances).To(Equal(2))
+		Expect(space.Status.Usage.ManagedServiceBindings).To(Equal(7))
+	})
+
+	It("should count and list service bindings referencing service instances of the space by guid", func() {
+		scheme := reconciler.Client.Scheme()
+		reconciler.Client = fake.NewClientBuilder().WithScheme(scheme).
+			WithIndex(&cfv1alpha1.ServiceBinding{}, indexServiceBindingSpaceName, indexServiceBindingBySpaceName).
+			WithObjects(
+				&cfv1alpha1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "binding-1"}, Spec: cfv1alpha1.ServiceBindingSpec{ServiceInstanceName: "instance-1"}},
+				&cfv1alpha1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "guid-binding-1"}, Spec: cfv1alpha1.ServiceBindingSpec{ServiceInstanceGuid: "guid-1", SpaceName: "space"}},
+				&cfv1alpha1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "guid-binding-2"}, Spec: cfv1alpha1.ServiceBindingSpec{ServiceInstanceGuid: "guid-2", SpaceName: "other-space"}},
+				&cfv1alpha1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "other-ns", Name: "guid-binding-3"}, Spec: cfv1alpha1.ServiceBindingSpec{ServiceInstanceGuid: "guid-3", SpaceName: "space"}},
+			).Build()
+
+		serviceBindingList, err := reconciler.listServiceBindingsByGuid(ctx, space)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(serviceBindingList.Items).To(HaveLen(1))
+		Expect(serviceBindingList.Items[0].Name).To(Equal("guid-binding-1"))
+
+		reconciler.updateSpaceUsage(ctx, space, checker, serviceInstances, serviceBindingList.Items)
+		Expect(space.Status.Usage.ManagedServiceBindings).To(Equal(2))
+	})
+})
+
+var _ = Describe("Cascading deletion of spaces | deleteSpaceContents", func() {
+	ctx := context.Background()
+	var spaceClient *facadefakes.FakeSpaceClient
+	var reconciler *SpaceReconciler
+	var space *cfv1alpha1.Space
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space-secret"}}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		spaceClient = &facadefakes.FakeSpaceClient{}
+		reconciler = &SpaceReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns"}},
+				&cfv1alpha1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "live", UID: "live-uid"}},
+			).Build(),
+			SpaceClientBuilder: func(string, string, string, string, *config.Config) (facade.SpaceClient, error) {
+				return spaceClient, nil
+			},
+		}
+		space = &cfv1alpha1.Space{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space"},
+			Spec:       cfv1alpha1.SpaceSpec{Name: "space", OrganizationName: "org", AuthSecretName: "space-secret", DeletionPolicy: cfv1alpha1.SpaceDeletionPolicyCascade},
+		}
+	})
+
+	It("should delete bindings first, and skip bindings being deleted", func() {
+		spaceClient.ListBindingsReturns([]*facade.Binding{
+			{Guid: "binding-1", State: facade.BindingStateReady, Owner: "uid-1", OwnerNamespace: "ns", OwnerName: "binding-1"},
+			{Guid: "binding-2", State: facade.BindingStateDeleting, Owner: "uid-2", OwnerNamespace: "ns", OwnerName: "binding-2"},
+		}, nil)
+
+		remaining, foreign, err := reconciler.deleteSpaceContents(ctx, space, "space-guid", secret)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remaining).To(Equal(2))
+		Expect(foreign).To(BeEmpty())
+		Expect(spaceClient.DeleteBindingCallCount()).To(Equal(1))
+		_, guid, _ := spaceClient.DeleteBindingArgsForCall(0)
+		Expect(guid).To(Equal("binding-1"))
+		Expect(spaceClient.ListInstancesCallCount()).To(Equal(0))
+	})
+
+	It("should delete instances once all bindings are gone", func() {
+		spaceClient.ListInstancesReturns([]*facade.Instance{
+			{Guid: "instance-1", State: facade.InstanceStateCreatedFailed, Owner: "uid-1", OwnerNamespace: "ns", OwnerName: "instance-1"},
+		}, nil)
+
+		remaining, _, err := reconciler.deleteSpaceContents(ctx, space, "space-guid", secret)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remaining).To(Equal(1))
+		Expect(spaceClient.DeleteInstanceCallCount()).To(Equal(1))
+
+		spaceClient.ListInstancesReturns(nil, nil)
+		remaining, _, err = reconciler.deleteSpaceContents(ctx, space, "space-guid", secret)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remaining).To(Equal(0))
+	})
+
+	It("should only delete resources whose owner resolves to this cluster, and report the others", func() {
+		spaceClient.ListBindingsReturns([]*facade.Binding{
+			// owner exists in this cluster
+			{Guid: "binding-1", Name: "live", State: facade.BindingStateReady, Owner: "live-uid"},
+			// owner namespace does not exist in this cluster
+			{Guid: "binding-2", Name: "foreign", State: facade.BindingStateReady, Owner: "uid-2", OwnerNamespace: "elsewhere", OwnerName: "foreign"},
+			// owner namespace not recorded
+			{Guid: "binding-3", Name: "unknown", State: facade.BindingStateReady, Owner: "uid-3", Replacement: true},
+			// owner recorded by another cluster
+			{Guid: "binding-4", Name: "other-cluster", State: facade.BindingStateReady, Owner: "uid-4", OwnerNamespace: "ns", OwnerName: "binding-4", OwnerCluster: "us10"},
+		}, nil)
+
+		remaining, foreign, err := reconciler.deleteSpaceContents(ctx, space, "space-guid", secret)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remaining).To(Equal(1))
+		Expect(foreign).To(ConsistOf("binding foreign (binding-2)", "binding unknown (binding-3)", "binding other-cluster (binding-4)"))
+		Expect(spaceClient.DeleteBindingCallCount()).To(Equal(1))
+		_, guid, _ := spaceClient.DeleteBindingArgsForCall(0)
+		Expect(guid).To(Equal("binding-1"))
+
+		// foreign leftovers do not block the deletion of instances
+		spaceClient.ListBindingsReturns([]*facade.Binding{
+			{Guid: "binding-2", Name: "foreign", State: facade.BindingStateReady, Owner: "uid-2", OwnerNamespace: "elsewhere", OwnerName: "foreign"},
+		}, nil)
+		remaining, foreign, err = reconciler.deleteSpaceContents(ctx, space, "space-guid", secret)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remaining).To(Equal(0))
+		Expect(foreign).To(HaveLen(1))
+		Expect(spaceClient.ListInstancesCallCount()).To(Equal(1))
+	})
+
+	It("should continue deleting after failures, and return the failures", func() {
+		spaceClient.ListBindingsReturns([]*facade.Binding{
+			{Guid: "binding-1", State: facade.BindingStateDeleteFailed, Owner: "uid-1", OwnerNamespace: "ns", OwnerName: "binding-1"},
+			{Guid: "binding-2", State: facade.BindingStateReady, Owner: "uid-2", OwnerNamespace: "ns", OwnerName: "binding-2"},
+		}, nil)
+		spaceClient.DeleteBindingReturnsOnCall(0, errors.New("broker unavailable"))
+
+		remaining, _, err := reconciler.deleteSpaceContents(ctx, space, "space-guid", secret)
+		Expect(err).To(MatchError(ContainSubstring("failed to delete service binding binding-1: broker unavailable")))
+		Expect(remaining).To(Equal(2))
+		Expect(spaceClient.DeleteBindingCallCount()).To(Equal(2))
+	})
+
+	It("should fail without space client builder", func() {
+		reconciler.SpaceClientBuilder = nil
+		_, _, err := reconciler.deleteSpaceContents(ctx, space, "space-guid", secret)
+		Expect(err).To(MatchError(ContainSubstring("not supported")))
+	})
+})
+
+var _ = Describe("Verify spaces referenced by guid | verifySpaceGuid", func() {
+	ctx := context.Background()
+	secretName := types.NamespacedName{Namespace: "ns", Name: "space-secret"}
+	var checker *facadefakes.FakeSpaceHealthChecker
+	var space *cfv1alpha1.Space
+
+	BeforeEach(func() {
+		checker = &facadefakes.FakeSpaceHealthChecker{}
+		space = &cfv1alpha1.Space{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space"},
+			Spec:       cfv1alpha1.SpaceSpec{Guid: "space-guid", AuthSecretName: "space-secret"},
+		}
+	})
+
+	It("should succeed if the space exists", func() {
+		checker.GetSpaceByGuidReturns(&facade.Space{Guid: "space-guid", Name: "space"}, nil)
+		Expect(verifySpaceGuid(ctx, space, checker, secretName)).To(Succeed())
+		_, guid := checker.GetSpaceByGuidArgsForCall(0)
+		Expect(guid).To(Equal("space-guid"))
+		Expect(space.GetCondition(cfv1alpha1.SpaceConditionCredentialsReady)).To(BeNil())
+	})
+
+	It("should fail if the space does not exist", func() {
+		Expect(verifySpaceGuid(ctx, space, checker, secretName)).To(MatchError(ContainSubstring("space space-guid not found")))
+		condition := space.GetCondition(cfv1alpha1.SpaceConditionCredentialsReady)
+		Expect(condition.Status).To(Equal(cfv1alpha1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(readyConditionReasonNotFound))
+	})
+
+	It("should pass through other errors", func() {
+		checker.GetSpaceByGuidReturns(nil, errors.New("connection refused"))
+		Expect(verifySpaceGuid(ctx, space, checker, secretName)).To(MatchError(ContainSubstring("connection refused")))
+		Expect(space.GetCondition(cfv1alpha1.SpaceConditionCredentialsReady)).To(BeNil())
+	})
+})
+
+var _ = Describe("Fail over to the failoverUrl of the space secret | selectEndpoint", func() {
+	ctx := context.Background()
+	const url = "https://api.cf.example.com"
+	const failoverURL = "https://api.cf2.example.com"
+	var unreachable map[string]bool
+	var reconciler *SpaceReconciler
+	var space *cfv1alpha1.Space
+	var secret *corev1.Secret
+
+	BeforeEach(func() {
+		unreachable = make(map[string]bool)
+		reconciler = &SpaceReconciler{EndpointProber: func(_ context.Context, url string, _ *config.Config) error {
+			if unreachable[url] {
+				return errors.New("connection refused")
+			}
+			return nil
+		}}
+		space = &cfv1alpha1.Space{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space"}}
+		secret = &corev1.Secret{Data: map[string][]byte{cfv1alpha1.SpaceSecretKeyURL: []byte(url), cfv1alpha1.SpaceSecretKeyFailoverURL: []byte(failoverURL)}}
+	})
+
+	It("should fail over while the url is unreachable, and fail back afterwards", func() {
+		Expect(reconciler.selectEndpoint(ctx, space, secret)).To(Equal(url))
+		unreachable[url] = true
+		Expect(reconciler.selectEndpoint(ctx, space, secret)).To(Equal(failoverURL))
+		unreachable[failoverURL] = true
+		Expect(reconciler.selectEndpoint(ctx, space, secret)).To(Equal(url))
+		unreachable[failoverURL] = false
+		space.Status.Endpoint = failoverURL
+		Expect(reconciler.selectEndpoint(ctx, space, secret)).To(Equal(failoverURL))
+		unreachable[url] = false
+		Expect(reconciler.selectEndpoint(ctx, space, secret)).To(Equal(url))
+	})
+
+	It("should not fail over without failoverUrl, and respect the endpoint pinned by annotation", func() {
+		unreachable[url] = true
+		space.Annotations = map[string]string{cfv1alpha1.AnnotationCFEndpoint: cfv1alpha1.CFEndpointPrimary}
+		Expect(reconciler.selectEndpoint(ctx, space, secret)).To(Equal(url))
+		space.Annotations[cfv1alpha1.AnnotationCFEndpoint] = cfv1alpha1.CFEndpointFailover
+		unreachable[url] = false
+		Expect(reconciler.selectEndpoint(ctx, space, secret)).To(Equal(failoverURL))
+
+		space.Annotations = nil
+		unreachable[url] = true
+		delete(secret.Data, cfv1alpha1.SpaceSecretKeyFailoverURL)
+		Expect(reconciler.selectEndpoint(ctx, space, secret)).To(Equal(url))
+	})
+})
+
+var _ = Describe("Build the organization client of a space | buildOrganizationClient", func() {
+	var reconciler *SpaceReconciler
+	var builtFor []string
+	var builtWith *config.Config
+
+	BeforeEach(func() {
+		builtFor, builtWith = nil, nil
+		reconciler = &SpaceReconciler{
+			Config: config.Defaults(),
+			ClientBuilder: func(organizationName string, url string, username string, password string, cfg *config.Config) (facade.OrganizationClient, error) {
+				builtFor = []string{organizationName, url, username, password}
+				builtWith = cfg
+				return &facadefakes.FakeOrganizationClient{}, nil
+			},
+		}
+	})
+
+	It("should prefer the organization credentials of the space secret", func() {
+		space := &cfv1alpha1.Space{Spec: cfv1alpha1.SpaceSpec{OrganizationName: "org"}}
+		secret := &corev1.Secret{Data: map[string][]byte{"username": []byte("user"), "password": []byte("pass")}}
+		_, err := reconciler.buildOrganizationClient(space, secret, "https://api.cf.example.com")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(builtFor).To(Equal([]string{"org", "https://api.cf.example.com", "user", "pass"}))
+
+		secret.Data["org_username"] = []byte("org-user")
+		secret.Data["org_password"] = []byte("org-pass")
+		_, err = reconciler.buildOrganizationClient(space, secret, "https://api.cf.example.com")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(builtFor).To(Equal([]string{"org", "https://api.cf.example.com", "org-user", "org-pass"}))
+	})
+
+	It("should apply the configuration overrides of the space", func() {
+		space := &cfv1alpha1.ClusterSpace{Spec: cfv1alpha1.SpaceSpec{
+			OrganizationName: "org",
+			ConfigOverrides:  &cfv1alpha1.SpaceConfigOverrides{ResourceCacheTimeout: &metav1.Duration{Duration: 30 * time.Second}},
+		}}
+		_, err := reconciler.buildOrganizationClient(space, &corev1.Secret{}, "https://api.cf.example.com")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(builtWith.SpaceCacheTimeout.Duration).To(Equal(30 * time.Second))
+		Expect(reconciler.Config.SpaceCacheTimeout.Duration).To(BeZero())
+	})
+})
+
+var _ = Describe("Space credentials in other namespaces | getSpaceSecretName", func() {
+	It("should default the namespace of the secret to the namespace of the space, resp. the cluster resource namespace", func() {
+		space := &cfv1alpha1.Space{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "space"},
+			Spec:       cfv1alpha1.SpaceSpec{AuthSecretName: "space-secret"},
+		}
+		Expect(getSpaceSecretName(space, "cluster-ns", nil)).To(Equal(types.NamespacedName{Namespace: "app", Name: "space-secret"}))
+
+		clusterSpace := &cfv1alpha1.ClusterSpace{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-space"},
+			Spec:       cfv1alpha1.SpaceSpec{AuthSecretRef: &cfv1alpha1.SecretReference{Name: "space-secret"}},
+		}
+		Expect(getSpaceSecretName(clusterSpace, "cluster-ns", nil)).To(Equal(types.NamespacedName{Namespace: "cluster-ns", Name: "space-secret"}))
+	})
+
+	It("should only read secrets from other namespaces if allowed by the configuration", func() {
+		space := &cfv1alpha1.Space{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "space"},
+			Spec:       cfv1alpha1.SpaceSpec{AuthSecretRef: &cfv1alpha1.SecretReference{Namespace: "credentials", Name: "space-secret"}},
+		}
+		_, err := getSpaceSecretName(space, "cluster-ns", config.Defaults())
+		Expect(err).To(MatchError(ContainSubstring("namespace not allowed by the operator configuration (credentialNamespaces)")))
+
+		cfg := config.Defaults()
+		cfg.CredentialNamespaces = []string{"credentials"}
+		Expect(getSpaceSecretName(space, "cluster-ns", cfg)).To(Equal(types.NamespacedName{Namespace: "credentials", Name: "space-secret"}))
+
+		clusterSpace := &cfv1alpha1.ClusterSpace{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-space"},
+			Spec:       cfv1alpha1.SpaceSpec{AuthSecretRef: &cfv1alpha1.SecretReference{Namespace: "credentials", Name: "space-secret"}},
+		}
+		_, err = getSpaceSecretName(clusterSpace, "cluster-ns", cfg)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Rotation of the space secret user | rotateSpaceUser", func() {
+	ctx := context.Background()
+	var client *facadefakes.FakeOrganizationClient
+	var spec *cfv1alpha1.SpaceSpec
+	var status *cfv1alpha1.SpaceStatus
+
+	BeforeEach(func() {
+		client = &facadefakes.FakeOrganizationClient{}
+		spec = &cfv1alpha1.SpaceSpec{Name: "space", OrganizationName: "org", AuthSecretName: "space-secret"}
+		status = &cfv1alpha1.SpaceStatus{Username: "old-user"}
+	})
+
+	It("should assign the developer role to the user, and not record a rotation if the user did not change", func() {
+		Expect(rotateSpaceUser(ctx, client, "space-guid", spec, status, "old-user")).To(Succeed())
+		Expect(client.AddDeveloperCallCount()).To(Equal(1))
+		Expect(status.Username).To(Equal("old-user"))
+		Expect(status.LastCredentialRotation).To(BeNil())
+	})
+
+	It("should record a rotation, and keep the previous user by default", func() {
+		Expect(rotateSpaceUser(ctx, client, "space-guid", spec, status, "new-user")).To(Succeed())
+		_, guid, username, _ := client.AddDeveloperArgsForCall(0)
+		Expect(guid).To(Equal("space-guid"))
+		Expect(username).To(Equal("new-user"))
+		Expect(client.RemoveDeveloperCallCount()).To(Equal(0))
+		Expect(status.Username).To(Equal("new-user"))
+		Expect(status.LastCredentialRotation.PreviousUsername).To(Equal("old-user"))
+		Expect(status.LastCredentialRotation.Username).To(Equal("new-user"))
+		Expect(status.LastCredentialRotation.PreviousUserRemoved).To(BeFalse())
+	})
+
+	It("should remove the developer role of the previous user with policy Remove", func() {
+		spec.CredentialRotationPolicy = cfv1alpha1.SpaceCredentialRotationPolicyRemove
+		Expect(rotateSpaceUser(ctx, client, "space-guid", spec, status, "new-user")).To(Succeed())
+		Expect(client.RemoveDeveloperCallCount()).To(Equal(1))
+		_, _, username, _ := client.RemoveDeveloperArgsForCall(0)
+		Expect(username).To(Equal("old-user"))
+		Expect(status.LastCredentialRotation.PreviousUserRemoved).To(BeTrue())
+	})
+
+	It("should not remove the previous user if it is listed in spec.developers", func() {
+		spec.CredentialRotationPolicy = cfv1alpha1.SpaceCredentialRotationPolicyRemove
+		spec.Developers = []cfv1alpha1.SpaceUser{{Username: "old-user"}}
+		Expect(rotateSpaceUser(ctx, client, "space-guid", spec, status, "new-user")).To(Succeed())
+		Expect(client.RemoveDeveloperCallCount()).To(Equal(0))
+		Expect(status.LastCredentialRotation.PreviousUserRemoved).To(BeFalse())
+	})
+
+	It("should retry the rotation if removing the previous user fails", func() {
+		spec.CredentialRotationPolicy = cfv1alpha1.SpaceCredentialRotationPolicyRemove
+		client.RemoveDeveloperReturns(errors.New("some error"))
+		Expect(rotateSpaceUser(ctx, client, "space-guid", spec, status, "new-user")).To(MatchError(ContainSubstring("previous user old-user")))
+		Expect(status.Username).To(Equal("old-user"))
+		Expect(status.LastCredentialRotation).To(BeNil())
+	})
+})
+
+var _ = Describe("Declarative space roles | reconcileSpaceRoles", func() {
+	ctx := context.Background()
+	var client *facadefakes.FakeOrganizationClient
+	var spec *cfv1alpha1.SpaceSpec
+	var status *cfv1alpha1.SpaceStatus
+
+	BeforeEach(func() {
+		client = &facadefakes.FakeOrganizationClient{}
+		spec = &cfv1alpha1.SpaceSpec{Name: "space", OrganizationName: "org", AuthSecretName: "space-secret"}
+		status = &cfv1alpha1.SpaceStatus{}
+	})
+
+	It("should assign the listed roles, and record them in the status", func() {
+		spec.Developers = []cfv1alpha1.SpaceUser{{Username: "dev"}}
+		spec.Auditors = []cfv1alpha1.SpaceUser{{Username: "auditor", Origin: "ldap"}}
+		spec.Managers = []cfv1alpha1.SpaceUser{{Username: "manager"}}
+		Expect(reconcileSpaceRoles(ctx, client, "space-guid", spec, status, "technical-user")).To(Succeed())
+
+		Expect(client.AddDeveloperCallCount()).To(Equal(1))
+		Expect(client.AddAuditorCallCount()).To(Equal(1))
+		_, guid, username, origin := client.AddAuditorArgsForCall(0)
+		Expect(guid).To(Equal("space-guid"))
+		Expect(username).To(Equal("auditor"))
+		Expect(origin).To(Equal("ldap"))
+		Expect(client.AddManagerCallCount()).To(Equal(1))
+		Expect(status.Developers).To(Equal(spec.Developers))
+		Expect(status.Auditors).To(Equal(spec.Auditors))
+		Expect(status.Managers).To(Equal(spec.Managers))
+	})
+
+	It("should remove roles which are no longer listed", func() {
+		status.Managers = []cfv1alpha1.SpaceUser{{Username: "manager"}, {Username: "former-manager"}}
+		spec.Managers = []cfv1alpha1.SpaceUser{{Username: "manager"}}
+		Expect(reconcileSpaceRoles(ctx, client, "space-guid", spec, status, "technical-user")).To(Succeed())
+
+		Expect(client.RemoveManagerCallCount()).To(Equal(1))
+		_, _, username, _ := client.RemoveManagerArgsForCall(0)
+		Expect(username).To(Equal("former-manager"))
+		Expect(status.Managers).To(Equal(spec.Managers))
+	})
+
+	It("should distinguish users with the same name by origin", func() {
+		status.Auditors = []cfv1alpha1.SpaceUser{{Username: "auditor", Origin: "uaa"}}
+		spec.Auditors = []cfv1alpha1.SpaceUser{{Username: "auditor", Origin: "ldap"}}
+		Expect(reconcileSpaceRoles(ctx, client, "space-guid", spec, status, "technical-user")).To(Succeed())
+
+		Expect(client.RemoveAuditorCallCount()).To(Equal(1))
+		_, _, username, origin := client.RemoveAuditorArgsForCall(0)
+		Expect(username).To(Equal("auditor"))
+		Expect(origin).To(Equal("uaa"))
+		Expect(status.Auditors).To(Equal(spec.Auditors))
+	})
+
+	It("should never remove the developer role of the user referenced by the space secret, whatever origin it was listed with", func() {
+		status.Developers = []cfv1alpha1.SpaceUser{{Username: "technical-user"}, {Username: "technical-user", Origin: "uaa"}, {Username: "dev"}}
+		Expect(reconcileSpaceRoles(ctx, client, "space-guid", spec, status, "technical-user")).To(Succeed())
+
+		Expect(client.RemoveDeveloperCallCount()).To(Equal(1))
+		_, _, username, _ := client.RemoveDeveloperArgsForCall(0)
+		Expect(username).To(Equal("dev"))
+		Expect(status.Developers).To(BeEmpty())
+	})
+
+	It("should remove other roles of the user referenced by the space secret", func() {
+		status.Auditors = []cfv1alpha1.SpaceUser{{Username: "technical-user"}}
+		Expect(reconcileSpaceRoles(ctx, client, "space-guid", spec, status, "technical-user")).To(Succeed())
+
+		Expect(client.RemoveAuditorCallCount()).To(Equal(1))
+		Expect(status.Auditors).To(BeEmpty())
+	})
+
+	It("should keep users whose removal failed in the status", func() {
+		status.Managers = []cfv1alpha1.SpaceUser{{Username: "former-manager"}}
+		client.RemoveManagerReturns(errors.New("some error"))
+		Expect(reconcileSpaceRoles(ctx, client, "space-guid", spec, status, "technical-user")).To(MatchError(ContainSubstring("failed to reconcile space managers")))
+		Expect(status.Managers).To(Equal([]cfv1alpha1.SpaceUser{{Username: "former-manager"}}))
+	})
+})
+
+var _ = Describe("Retry failed reconciliations of spaces | HandleError", func() {
+	var reconciler *SpaceReconciler
+	var space *cfv1alpha1.Space
+
+	BeforeEach(func() {
+		reconciler = &SpaceReconciler{Kind: "Space"}
+		space = &cfv1alpha1.Space{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space"}}
+	})
+
+	It("should retry indefinitely by default", func() {
+		space.Status.MaxRetries = spaceDefaultMaxRetries
+		for i := 0; i < 5; i++ {
+			_, err := reconciler.HandleError(space, errors.New("space creation failed"), logr.Discard())
+			Expect(err).To(MatchError("space creation failed"))
+		}
+		Expect(space.Status.RetryCounter).To(Equal(5))
+		Expect(space.GetReadyCondition().Reason).To(Equal(spaceReadyConditionReasonError))
+		Expect(space.GetCondition(cfv1alpha1.SpaceConditionSynced).Status).To(Equal(cfv1alpha1.ConditionFalse))
+	})
+
+	It("should give up once the maximum number of retries is exceeded", func() {
+		space.Annotations = map[string]string{cfv1alpha1.AnnotationPollingIntervalFail: "1h"}
+		space.Status.MaxRetries = 2
+		_, err := reconciler.HandleError(space, errors.New("space creation failed"), logr.Discard())
+		Expect(err).To(HaveOccurred())
+
+		result, err := reconciler.HandleError(space, errors.New("space creation failed"), logr.Discard())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(time.Hour))
+		Expect(space.GetReadyCondition().Status).To(Equal(cfv1alpha1.ConditionFalse))
+		Expect(space.GetReadyCondition().Reason).To(Equal(spaceReadyConditionReasonMaxRetries))
+		Expect(space.GetReadyCondition().Message).To(ContainSubstring("space creation failed"))
+	})
+})
+
+var _ = Describe("Observe spaces without changing them | observeSpace", func() {
+	ctx := context.Background()
+	secretName := types.NamespacedName{Namespace: "ns", Name: "space-secret"}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space-secret"},
+		Data:       map[string][]byte{"username": []byte("user"), "password": []byte("pass")},
+	}
+	var orgClient *facadefakes.FakeOrganizationClient
+	var checker *facadefakes.FakeSpaceHealthChecker
+	var checkerSpaceGuid string
+	var reconciler *SpaceReconciler
+	var space *cfv1alpha1.Space
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		orgClient = &facadefakes.FakeOrganizationClient{}
+		checker = &facadefakes.FakeSpaceHealthChecker{}
+		checkerSpaceGuid = ""
+		reconciler = &SpaceReconciler{
+			Kind:   "Space",
+			Config: config.Defaults(),
+			Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithIndex(&cfv1alpha1.ServiceInstance{}, indexServiceInstanceSpaceName,
+					indexByField(func(serviceInstance *cfv1alpha1.ServiceInstance) string { return serviceInstance.Spec.SpaceName })).
+				WithIndex(&cfv1alpha1.ServiceBinding{}, indexServiceBindingSpaceName, indexServiceBindingBySpaceName).
+				WithObjects(
+					&cfv1alpha1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "instance"}, Spec: cfv1alpha1.ServiceInstanceSpec{SpaceName: "space"}},
+				).Build(),
+			ClientBuilder: func(string, string, string, string, *config.Config) (facade.OrganizationClient, error) {
+				return orgClient, nil
+			},
+			HealthCheckerBuilder: func(spaceGuid string, _ string, _ string, _ string, _ *config.Config) (facade.SpaceHealthChecker, error) {
+				checkerSpaceGuid = spaceGuid
+				return checker, nil
+			},
+		}
+		space = &cfv1alpha1.Space{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space", UID: "space-uid"},
+			Spec:       cfv1alpha1.SpaceSpec{Name: "space", OrganizationName: "org", AuthSecretName: "space-secret"},
+		}
+	})
+
+	expectNoChanges := func() {
+		Expect(orgClient.CreateSpaceCallCount()).To(Equal(0))
+		Expect(orgClient.UpdateSpaceCallCount()).To(Equal(0))
+		Expect(orgClient.DeleteSpaceCallCount()).To(Equal(0))
+		Expect(orgClient.AddDeveloperCallCount()).To(Equal(0))
+		condition := space.GetCondition(cfv1alpha1.SpaceConditionSynced)
+		Expect(condition.Status).To(Equal(cfv1alpha1.ConditionUnknown))
+		Expect(condition.Reason).To(Equal(conditionReasonObserveOnly))
+	}
+
+	It("should look up managed spaces by owner, check them, and report the usage", func() {
+		orgClient.GetSpaceReturns(&facade.Space{Guid: "space-guid", Name: "space"}, nil)
+
+		result, err := reconciler.observeSpace(ctx, space, secret, secretName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		_, owner := orgClient.GetSpaceArgsForCall(0)
+		Expect(owner).To(Equal("space-uid"))
+		Expect(checkerSpaceGuid).To(Equal("space-guid"))
+		Expect(checker.CheckCallCount()).To(Equal(1))
+		Expect(space.Status.SpaceGuid).To(Equal("space-guid"))
+		Expect(space.Status.Usage.ManagedServiceInstances).To(Equal(1))
+		Expect(space.GetCondition(cfv1alpha1.SpaceConditionCredentialsReady).Status).To(Equal(cfv1alpha1.ConditionTrue))
+		Expect(space.IsReady()).To(BeTrue())
+		expectNoChanges()
+	})
+
+	It("should report managed spaces which do not exist, without creating them", func() {
+		_, err := reconciler.observeSpace(ctx, space, secret, secretName)
+		Expect(err).NotTo(HaveOccurred())
+		condition := space.GetReadyCondition()
+		Expect(condition.Status).To(Equal(cfv1alpha1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(readyConditionReasonNotFound))
+		Expect(checker.CheckCallCount()).To(Equal(0))
+		expectNoChanges()
+	})
+
+	It("should verify spaces referenced by guid, without building an organization client", func() {
+		reconciler.ClientBuilder = nil
+		space.Spec = cfv1alpha1.SpaceSpec{Guid: "space-guid", AuthSecretName: "space-secret"}
+		checker.GetSpaceByGuidReturns(&facade.Space{Guid: "space-guid", Name: "space"}, nil)
+
+		_, err := reconciler.observeSpace(ctx, space, secret, secretName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checkerSpaceGuid).To(Equal("space-guid"))
+		Expect(checker.GetSpaceByGuidCallCount()).To(Equal(1))
+		Expect(checker.CheckCallCount()).To(Equal(0))
+		Expect(space.Status.SpaceGuid).To(Equal("space-guid"))
+		Expect(space.IsReady()).To(BeTrue())
+		expectNoChanges()
+	})
+
+	It("should report failed checks in the CredentialsReady condition", func() {
+		orgClient.GetSpaceReturns(&facade.Space{Guid: "space-guid", Name: "space"}, nil)
+		checker.CheckReturns(errors.New("not authorized"))
+
+		_, err := reconciler.observeSpace(ctx, space, secret, secretName)
+		Expect(err).To(MatchError(ContainSubstring("not authorized")))
+		Expect(space.GetCondition(cfv1alpha1.SpaceConditionCredentialsReady).Status).To(Equal(cfv1alpha1.ConditionFalse))
+		expectNoChanges()
+	})
+})
+
+var _ = Describe("Pause the reconciliation of spaces | Reconcile", func() {
+	ctx := context.Background()
+
+	It("should neither read nor change spaces and cluster spaces while paused, and resume once the annotation is removed", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		paused := map[string]string{cfv1alpha1.AnnotationPaused: "true"}
+		spaceSpec := cfv1alpha1.SpaceSpec{Name: "space", OrganizationName: "org", AuthSecretName: "space-secret"}
+		spaces := map[string]cfv1alpha1.GenericSpace{
+			"Space":        &cfv1alpha1.Space{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space", Generation: 1, Annotations: paused}, Spec: spaceSpec},
+			"ClusterSpace": &cfv1alpha1.ClusterSpace{ObjectMeta: metav1.ObjectMeta{Name: "cluster-space", Generation: 1, Annotations: paused}, Spec: spaceSpec},
+		}
+
+		for kind, space := range spaces {
+			space.SetReadyCondition(cfv1alpha1.ConditionUnknown, spaceReadyConditionReasonNew, "First seen")
+			clientBuilds := 0
+			reconciler := &SpaceReconciler{
+				Kind: kind,
+				Client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(space).
+					WithStatusSubresource(space).
+					Build(),
+				Scheme: scheme,
+				ClientBuilder: func(string, string, string, string, *config.Config) (facade.OrganizationClient, error) {
+					clientBuilds++
+					return &facadefakes.FakeOrganizationClient{}, nil
+				},
+				Config: config.Defaults(),
+			}
+			spaceKey := client.ObjectKeyFromObject(space)
+
+			result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: spaceKey})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+
+			Expect(clientBuilds).To(BeZero())
+			Expect(reconciler.Get(ctx, spaceKey, space)).To(Succeed())
+			Expect(space.GetFinalizers()).To(BeEmpty())
+			Expect(space.GetCondition(cfv1alpha1.SpaceConditionPaused)).ToNot(BeNil())
+			Expect(space.GetCondition(cfv1alpha1.SpaceConditionPaused).Status).To(Equal(cfv1alpha1.ConditionTrue))
+
+			// resume
+			space.SetAnnotations(nil)
+			Expect(reconciler.Update(ctx, space)).To(Succeed())
+			result, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: spaceKey})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+			Expect(reconciler.Get(ctx, spaceKey, space)).To(Succeed())
+			Expect(space.GetCondition(cfv1alpha1.SpaceConditionPaused)).To(BeNil())
+		}
+	})
+})
@@ -16,6 +16,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
 	"github.com/sap/cf-service-operator/internal/facade"
 	"github.com/sap/cf-service-operator/internal/facade/facadefakes"
 
@@ -29,6 +30,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
@@ -107,6 +109,20 @@ var _ = BeforeSuite(func() {
 	By("creating K8s manager")
 	k8sManager, err := ctrl.NewManager(k8sConfig, ctrl.Options{
 		Scheme: scheme.Scheme,
+		// same as in main.go; in particular, lists by field index must not go through the (uncached) client
+		Client: client.Options{
+			Cache: &client.CacheOptions{
+				DisableFor: []client.Object{
+					&v1alpha1.Space{},
+					&v1alpha1.ClusterSpace{},
+					&v1alpha1.ServiceInstance{},
+					&v1alpha1.ServiceBinding{},
+					&v1alpha1.ServiceOperatorReport{},
+					// pre-delete hook jobs are only read while service instances are deleted; caching would need a cluster-wide informer
+					&batchv1.Job{},
+				},
+			},
+		},
 		Metrics: metricsserver.Options{
 			BindAddress: "0",
 		},
@@ -204,10 +220,10 @@ func addControllers(k8sManager ctrl.Manager) {
 		Client:                   k8sManager.GetClient(),
 		Scheme:                   k8sManager.GetScheme(),
 		ClusterResourceNamespace: testK8sNamespace,
-		ClientBuilder: func(organizationName string, url string, username string, password string) (facade.OrganizationClient, error) {
+		ClientBuilder: func(organizationName string, url string, username string, password string, cfg *config.Config) (facade.OrganizationClient, error) {
 			return fakeOrgClient, nil
 		},
-		HealthCheckerBuilder: func(spaceGuid string, url string, username string, password string) (facade.SpaceHealthChecker, error) {
+		HealthCheckerBuilder: func(spaceGuid string, url string, username string, password string, cfg *config.Config) (facade.SpaceHealthChecker, error) {
 			return fakeSpaceHealthChecker, nil
 		},
 	}
@@ -218,7 +234,8 @@ func addControllers(k8sManager ctrl.Manager) {
 		Client:                   k8sManager.GetClient(),
 		Scheme:                   k8sManager.GetScheme(),
 		ClusterResourceNamespace: testK8sNamespace,
-		ClientBuilder: func(organizationName string, url string, username string, password string) (facade.SpaceClient, error) {
+		Recorder:                 k8sManager.GetEventRecorderFor("serviceinstance-controller"),
+		ClientBuilder: func(organizationName string, url string, username string, password string, cfg *config.Config) (facade.SpaceClient, error) {
 			return fakeSpaceClient, nil
 		},
 	}
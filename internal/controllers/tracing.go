@@ -0,0 +1,37 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/sap/cf-service-operator/internal/tracing"
+)
+
+// traceReconciles wraps the given reconciler, such that every reconcile creates a span (named after the given kind);
+// calls of the Cloud Foundry clients made during the reconcile are recorded as child spans.
+// The trace id is added to the logger passed to the reconciler, to correlate logs and traces.
+func traceReconciles(r reconcile.Reconciler, kind string) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+		ctx, span := tracing.Start(ctx, "Reconcile "+kind,
+			attribute.String("k8s.namespace.name", req.Namespace),
+			attribute.String("k8s.object.name", req.Name),
+		)
+		defer func() {
+			span.SetAttributes(attribute.Bool("reconcile.requeue", result.Requeue || result.RequeueAfter > 0))
+			tracing.End(span, err)
+		}()
+		if spanContext := span.SpanContext(); spanContext.IsSampled() {
+			ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues("traceID", spanContext.TraceID().String()))
+		}
+		return r.Reconcile(ctx, req)
+	})
+}
@@ -0,0 +1,46 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Trace reconciles | traceReconciles", func() {
+	It("should create a span for every reconcile", func() {
+		recorder := tracetest.NewSpanRecorder()
+		tracerProvider := otel.GetTracerProvider()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		defer otel.SetTracerProvider(tracerProvider)
+
+		var reconcileSpan trace.SpanContext
+		r := traceReconciles(reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+			reconcileSpan = trace.SpanContextFromContext(ctx)
+			return ctrl.Result{}, errors.New("space not ready")
+		}), "ServiceInstance")
+
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "a", Name: "instance"}})
+		Expect(err).To(MatchError("space not ready"))
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Name()).To(Equal("Reconcile ServiceInstance"))
+		Expect(spans[0].SpanContext()).To(Equal(reconcileSpan))
+		Expect(spans[0].Status().Code).To(Equal(codes.Error))
+	})
+})
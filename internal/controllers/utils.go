@@ -8,6 +8,14 @@ package controllers
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
 )
 
 // Helper functions to check and remove string from a slice of strings.
@@ -57,3 +65,31 @@ func mergeObjects(objs ...map[string]interface{}) (map[string]interface{}, error
 	}
 	return result, nil
 }
+
+// ownerRefOf returns the given object as owner of the Cloud Foundry resources it creates
+// (recording its namespace and name in the labels of the resource).
+func ownerRefOf(obj client.Object) facade.OwnerRef {
+	return withOwnerObject(facade.OwnerRef{UID: string(obj.GetUID())}, obj)
+}
+
+// withOwnerObject returns the given owner (as recorded in Cloud Foundry), with the namespace and name of the given object,
+// such that updates of adopted (or older) resources record the object currently owning them.
+func withOwnerObject(owner facade.OwnerRef, obj client.Object) facade.OwnerRef {
+	owner.Namespace = obj.GetNamespace()
+	owner.Name = obj.GetName()
+	return owner
+}
+
+// getSpaceSecretName returns the name of the secret containing the credentials of the given Space or ClusterSpace object;
+// secrets of Space objects may only reside in other namespaces if allowed by the operator configuration (credentialNamespaces).
+func getSpaceSecretName(space cfv1alpha1.GenericSpace, clusterResourceNamespace string, cfg *config.Config) (types.NamespacedName, error) {
+	namespace := clusterResourceNamespace
+	if space.IsNamespaced() {
+		namespace = space.GetNamespace()
+	}
+	secretName := space.GetSpec().GetAuthSecretName(namespace)
+	if secretName.Namespace != namespace && (!space.IsNamespaced() || cfg == nil || !slices.Contains(cfg.CredentialNamespaces, secretName.Namespace)) {
+		return types.NamespacedName{}, fmt.Errorf("space credentials must not be read from namespace %s: namespace not allowed by the operator configuration (credentialNamespaces)", secretName.Namespace)
+	}
+	return secretName, nil
+}
@@ -0,0 +1,127 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package debug provides an HTTP server exposing runtime diagnostics (profiles, and the contents of the Cloud Foundry caches)
+for live debugging of memory or requeue issues.
+*/
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+// Server serves the net/http/pprof handlers below /debug/pprof/, and a JSON dump of the cached Cloud Foundry clients
+// and resources at /debug/cf/caches. If a Tracer is set, execution traces are started and stopped on demand by POST requests
+// to /debug/trace/start (with optional query parameter duration, such as 30s) and /debug/trace/stop. Since profiles and cache contents are sensitive, the server must not be exposed
+// outside of the pod (for example, bind it to localhost, and use kubectl port-forward).
+type Server struct {
+	BindAddress string
+	CacheDump   facade.CacheDumpProvider
+	Tracer      *Tracer
+}
+
+// Handler returns the handler serving the diagnostics endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/cf/caches", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(s.CacheDump()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+	if s.Tracer != nil {
+		mux.HandleFunc("/debug/trace/start", s.startTrace)
+		mux.HandleFunc("/debug/trace/stop", s.stopTrace)
+	}
+	return mux
+}
+
+func (s *Server) startTrace(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var duration time.Duration
+	if value := r.URL.Query().Get("duration"); value != "" {
+		var err error
+		if duration, err = time.ParseDuration(value); err != nil {
+			http.Error(w, fmt.Sprintf("invalid duration %q: %s", value, err), http.StatusBadRequest)
+			return
+		}
+	}
+	name, err := s.Tracer.StartTrace(duration)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	fmt.Fprintf(w, "started trace %s\n", name)
+}
+
+func (s *Server) stopTrace(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	name, err := s.Tracer.StopTrace()
+	switch {
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	case name == "":
+		http.Error(w, "no trace active", http.StatusConflict)
+	default:
+		fmt.Fprintf(w, "stopped trace %s\n", name)
+	}
+}
+
+// Start serves the diagnostics endpoints until the context is cancelled.
+// Implements manager.Runnable.
+func (s *Server) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithName("debug").WithValues("addr", s.BindAddress)
+
+	listener, err := net.Listen("tcp", s.BindAddress)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		if err := server.Shutdown(context.Background()); err != nil {
+			log.Error(err, "error shutting down debug server")
+		}
+	}()
+
+	log.Info("starting debug server")
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; all replicas serve diagnostics.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,55 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+func TestDebug(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Debug Test Suite")
+}
+
+var _ = Describe("Debug server", func() {
+	var handler http.Handler
+
+	BeforeEach(func() {
+		handler = (&Server{CacheDump: func() facade.CacheDump {
+			return facade.CacheDump{
+				Statistics: facade.ResourceCacheStatistics{Instances: 1},
+				Clients:    []facade.ClientCacheDump{{URL: "https://api.cf.example.com", Username: "admin"}},
+			}
+		}}).Handler()
+	})
+
+	It("should serve the cache dump", func() {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/cf/caches", nil))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+
+		dump := facade.CacheDump{}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &dump)).To(Succeed())
+		Expect(dump.Statistics.Instances).To(Equal(1))
+		Expect(dump.Clients[0].URL).To(Equal("https://api.cf.example.com"))
+	})
+
+	It("should serve the pprof index", func() {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(ContainSubstring("goroutine"))
+	})
+})
@@ -0,0 +1,162 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/trace"
+	"sort"
+	"sync"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// Tracer writes execution traces (see runtime/trace) into files named after Path, suffixed by the start time of the trace.
+// Traces are started periodically (when run as manager.Runnable), or on demand (see StartTrace); at most one trace is active at a time.
+type Tracer struct {
+	// Path prefix of the trace files
+	Path string
+	// Duration of a trace; zero means that the trace is written until stopped (or until the operator terminates)
+	Duration time.Duration
+	// Interval in which a new trace is started when run as manager.Runnable; zero means that only one trace is started (at startup)
+	Interval time.Duration
+	// Maximum number of trace files kept; older files are removed when a new trace is started. Zero means no limit.
+	MaxFiles int
+
+	mutex sync.Mutex
+	file  *os.File
+	timer *time.Timer
+	// used to derive the names of trace files; overridden by tests
+	now func() time.Time
+}
+
+// StartTrace starts writing a trace into a new file, stopping it after the given duration (if positive);
+// returns the name of the file. Fails if a trace is active already.
+func (t *Tracer) StartTrace(duration time.Duration) (string, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.file != nil {
+		return "", fmt.Errorf("trace %s is active already", t.file.Name())
+	}
+	now := time.Now
+	if t.now != nil {
+		now = t.now
+	}
+	name := t.Path + "." + now().UTC().Format("20060102-150405")
+	file, err := os.Create(name)
+	if err != nil {
+		return "", err
+	}
+	if err := trace.Start(file); err != nil {
+		file.Close()
+		os.Remove(name)
+		return "", err
+	}
+	t.file = file
+	if duration > 0 {
+		t.timer = time.AfterFunc(duration, func() {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			// the trace may have been stopped (and another one started) in the meantime
+			if t.file == file {
+				_, _ = t.stop()
+			}
+		})
+	}
+	t.removeOldFiles()
+	return name, nil
+}
+
+// StopTrace stops the active trace; returns the name of the written file (empty if no trace was active).
+func (t *Tracer) StopTrace() (string, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.stop()
+}
+
+// stop stops the active trace (if any); must be called with the mutex locked.
+func (t *Tracer) stop() (string, error) {
+	if t.file == nil {
+		return "", nil
+	}
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+	trace.Stop()
+	name := t.file.Name()
+	err := t.file.Close()
+	t.file = nil
+	return name, err
+}
+
+// removeOldFiles removes the oldest trace files, such that at most MaxFiles are kept.
+// Must be called with the mutex locked.
+func (t *Tracer) removeOldFiles() {
+	if t.MaxFiles <= 0 {
+		return
+	}
+	names, err := filepath.Glob(t.Path + ".*")
+	if err != nil {
+		return
+	}
+	// the time suffix sorts chronologically
+	sort.Strings(names)
+	for len(names) > t.MaxFiles {
+		os.Remove(names[0])
+		names = names[1:]
+	}
+}
+
+// Start writes a trace at startup, and then every interval (if positive), until the context is cancelled.
+// Implements manager.Runnable.
+func (t *Tracer) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithName("trace")
+
+	start := func() {
+		if name, err := t.StartTrace(t.Duration); err != nil {
+			log.Error(err, "unable to start trace")
+		} else {
+			log.V(1).Info("started trace", "file", name)
+		}
+	}
+	start()
+	defer func() {
+		if _, err := t.StopTrace(); err != nil {
+			log.Error(err, "unable to stop trace")
+		}
+	}()
+
+	if t.Interval <= 0 {
+		<-ctx.Done()
+		return nil
+	}
+	ticker := time.NewTicker(t.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		// a trace still running (for example, started on demand without duration) is rotated
+		if _, err := t.StopTrace(); err != nil {
+			log.Error(err, "unable to stop trace")
+		}
+		start()
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; all replicas are traced.
+func (t *Tracer) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,86 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package debug
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Execution traces", func() {
+	var tracer *Tracer
+	var now time.Time
+
+	BeforeEach(func() {
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		tracer = &Tracer{Path: filepath.Join(GinkgoT().TempDir(), "trace"), MaxFiles: 2, now: func() time.Time { return now }}
+		DeferCleanup(func() { _, _ = tracer.StopTrace() })
+	})
+
+	It("should start and stop traces", func() {
+		name, err := tracer.StartTrace(0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(name).To(Equal(tracer.Path + ".20240101-000000"))
+		_, err = tracer.StartTrace(0)
+		Expect(err).To(MatchError(ContainSubstring("active already")))
+
+		Expect(tracer.StopTrace()).To(Equal(name))
+		Expect(tracer.StopTrace()).To(BeEmpty())
+		Expect(name).To(BeARegularFile())
+	})
+
+	It("should stop traces after the duration", func() {
+		_, err := tracer.StartTrace(10 * time.Millisecond)
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(tracer.StopTrace).Should(BeEmpty())
+	})
+
+	It("should only keep the newest files", func() {
+		for i := 0; i < 3; i++ {
+			_, err := tracer.StartTrace(0)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = tracer.StopTrace()
+			Expect(err).ToNot(HaveOccurred())
+			now = now.Add(time.Minute)
+		}
+		names, err := filepath.Glob(tracer.Path + ".*")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(names).To(Equal([]string{tracer.Path + ".20240101-000100", tracer.Path + ".20240101-000200"}))
+		_, err = os.Stat(tracer.Path + ".20240101-000000")
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("should start and stop traces on demand", func() {
+		handler := (&Server{Tracer: tracer}).Handler()
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/trace/start", nil))
+		Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/debug/trace/start?duration=1h", nil))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(ContainSubstring("started trace"))
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/debug/trace/stop", nil))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/debug/trace/stop", nil))
+		Expect(recorder.Code).To(Equal(http.StatusConflict))
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/debug/trace/start?duration=soon", nil))
+		Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+	})
+})
@@ -0,0 +1,124 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package events provides a lightweight in-process publish/subscribe mechanism, through which the Cloud Foundry layer
+notifies interested parties (such as controllers) about changes of Cloud Foundry resources.
+*/
+package events
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// ResourceType is the type of the Cloud Foundry resource an event refers to.
+type ResourceType string
+
+const (
+	ResourceTypeSpace    ResourceType = "space"
+	ResourceTypeInstance ResourceType = "instance"
+	ResourceTypeBinding  ResourceType = "binding"
+)
+
+// EventType describes what happened to a Cloud Foundry resource.
+type EventType string
+
+const (
+	// The resource was created through the operator.
+	EventTypeCreated EventType = "Created"
+	// The resource was updated through the operator.
+	EventTypeUpdated EventType = "Updated"
+	// The resource was deleted through the operator.
+	EventTypeDeleted EventType = "Deleted"
+	// The resource was (re-)read from Cloud Foundry, because it was not (or no longer) cached.
+	EventTypeRefreshed EventType = "Refreshed"
+)
+
+// Event is a change notification about a Cloud Foundry resource.
+// Depending on the operation, Guid (not known for resources created asynchronously) or Owner (not known for updated or deleted resources) may be empty.
+type Event struct {
+	Type         EventType
+	ResourceType ResourceType
+	// Guid of the resource
+	Guid string
+	// Owner of the resource (that is, the uid of the owning Kubernetes object)
+	Owner string
+	// Guid of the space containing the resource (empty for spaces)
+	SpaceGuid string
+}
+
+// Filter selects the events delivered to a subscriber.
+type Filter func(event Event) bool
+
+// Bus distributes published events to all subscribers.
+// Publishing never blocks; events are dropped for subscribers whose buffer is full.
+// A nil bus is valid; events published on it are discarded.
+type Bus struct {
+	mutex       sync.RWMutex
+	subscribers map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	ch     chan Event
+	filter Filter
+}
+
+var eventsDropped = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "cf_events_dropped_total",
+		Help: "The number of Cloud Foundry resource events not delivered to a subscriber because its buffer was full",
+	},
+)
+
+func init() {
+	metrics.Registry.MustRegister(eventsDropped)
+}
+
+// NewBus returns a bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[*subscriber]struct{})}
+}
+
+// Publish delivers the given event to all subscribers whose filter accepts it.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for s := range b.subscribers {
+		if s.filter != nil && !s.filter(event) {
+			continue
+		}
+		select {
+		case s.ch <- event:
+		default:
+			eventsDropped.Inc()
+		}
+	}
+}
+
+// Subscribe registers a subscriber for all events accepted by filter (all events if filter is nil), buffering up to bufferSize events.
+// The returned function cancels the subscription; it closes the returned channel, and must be called exactly once.
+func (b *Bus) Subscribe(bufferSize int, filter Filter) (<-chan Event, func()) {
+	s := &subscriber{ch: make(chan Event, bufferSize), filter: filter}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.subscribers[s] = struct{}{}
+	return s.ch, func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+
+		delete(b.subscribers, s)
+		close(s.ch)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package events
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestEvents(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Events Test Suite")
+}
+
+// -----------------------------------------------------------------------------------------------
+// Tests
+// -----------------------------------------------------------------------------------------------
+
+var _ = Describe("Bus tests", func() {
+	var bus *Bus
+
+	BeforeEach(func() {
+		bus = NewBus()
+	})
+
+	It("should deliver events to all matching subscribers", func() {
+		all, unsubscribeAll := bus.Subscribe(10, nil)
+		defer unsubscribeAll()
+		instances, unsubscribeInstances := bus.Subscribe(10, func(event Event) bool { return event.ResourceType == ResourceTypeInstance })
+		defer unsubscribeInstances()
+
+		bus.Publish(Event{Type: EventTypeDeleted, ResourceType: ResourceTypeSpace, Guid: "space-guid"})
+		bus.Publish(Event{Type: EventTypeUpdated, ResourceType: ResourceTypeInstance, Guid: "instance-guid"})
+
+		Expect(all).To(HaveLen(2))
+		Expect(instances).To(HaveLen(1))
+		Expect(<-instances).To(Equal(Event{Type: EventTypeUpdated, ResourceType: ResourceTypeInstance, Guid: "instance-guid"}))
+	})
+
+	It("should drop events for subscribers whose buffer is full", func() {
+		ch, unsubscribe := bus.Subscribe(1, nil)
+		defer unsubscribe()
+
+		bus.Publish(Event{Type: EventTypeCreated, ResourceType: ResourceTypeBinding, Owner: "1"})
+		bus.Publish(Event{Type: EventTypeCreated, ResourceType: ResourceTypeBinding, Owner: "2"})
+
+		Expect(ch).To(HaveLen(1))
+		Expect((<-ch).Owner).To(Equal("1"))
+	})
+
+	It("should stop delivering events after unsubscribing", func() {
+		ch, unsubscribe := bus.Subscribe(10, nil)
+		unsubscribe()
+
+		bus.Publish(Event{Type: EventTypeDeleted, ResourceType: ResourceTypeSpace, Guid: "space-guid"})
+		Expect(ch).To(BeClosed())
+	})
+
+	It("should discard events published on a nil bus", func() {
+		var bus *Bus
+		Expect(func() { bus.Publish(Event{Type: EventTypeDeleted}) }).ToNot(Panic())
+	})
+})
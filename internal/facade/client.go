@@ -5,7 +5,25 @@ SPDX-License-Identifier: Apache-2.0
 
 package facade
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/sap/cf-service-operator/internal/config"
+)
+
+// OwnerRef identifies the Kubernetes object owning a Cloud Foundry resource, by its uid (as recorded in the owner label of the resource).
+// All mutating calls take the owner of the affected resource, since the resource cache is indexed by owner;
+// the zero value is passed for resources whose owner is unknown (the cache is then searched by guid).
+type OwnerRef struct {
+	UID string
+	// Whether the resource is a replacement of the resource owned by UID (labeled as such, instead of with the owner label)
+	Replacement bool
+	// Namespace and name of the owning object; if set, recorded in the owner-namespace and owner-name labels of the resource
+	// when it is created or updated (for reference only, the resource remains identified by UID)
+	Namespace string
+	Name      string
+}
 
 type Space struct {
 	Guid       string
@@ -15,14 +33,52 @@ type Space struct {
 }
 
 type Instance struct {
-	Guid             string
-	Name             string
-	ServicePlanGuid  string
-	Owner            string
-	Generation       int64
-	ParameterHash    string
+	Guid            string
+	Name            string
+	ServicePlanGuid string
+	Owner           string
+	// Namespace and name of the owning object, as recorded in the owner-namespace and owner-name labels (empty if not recorded)
+	OwnerNamespace string
+	OwnerName      string
+	// Identifier of the owning cluster, as recorded in the owner-cluster label (empty if not recorded)
+	OwnerCluster  string
+	Generation    int64
+	ParameterHash string
+	TagsHash      string
+	// Whether custom labels or annotations (spec.metadata) were applied to the instance by the operator
+	CustomMetadata   bool
 	State            InstanceState
 	StateDescription string
+	// Last operation performed on the instance, as reported by Cloud Foundry (nil if not reported)
+	LastOperation *LastOperation
+	// Current maintenance version of the instance (if provided by the broker)
+	MaintenanceInfo *MaintenanceInfo
+	// Whether the broker offers a maintenance upgrade; if true, AvailableMaintenanceInfo describes the offered version
+	UpgradeAvailable         bool
+	AvailableMaintenanceInfo *MaintenanceInfo
+}
+
+// InstanceName identifies a Cloud Foundry service instance by guid and name; unlike Instance, it may describe any instance
+// (including user-provided instances, and instances not created by the operator).
+type InstanceName struct {
+	Guid string
+	Name string
+	// Uid of the owning object, as recorded in the owner label (empty if not recorded)
+	Owner string
+}
+
+// LastOperation describes the last operation performed by Cloud Foundry on a service instance or binding.
+type LastOperation struct {
+	Type        string
+	State       string
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type MaintenanceInfo struct {
+	Version     string
+	Description string
 }
 
 type InstanceState string
@@ -39,17 +95,84 @@ const (
 	InstanceStateDeleted       InstanceState = "Deleted"
 )
 
+// Metadata holds custom labels and annotations of a Cloud Foundry resource (besides the ones maintained by the operator).
+type Metadata struct {
+	Labels      map[string]string
+	Annotations map[string]string
+	// Labels to be removed, although they were not applied by the operator (such as the labels of another tool, on adoption)
+	RemovedLabels []string
+}
+
+type ServicePlan struct {
+	Guid                string
+	Name                string
+	Description         string
+	ServiceOfferingGuid string
+	ServiceOfferingName string
+}
+
+// ServicePlanSchemas holds the JSON schemas of the parameters of a service plan, as published by the service broker;
+// a schema is nil if the broker does not publish one for the respective operation.
+type ServicePlanSchemas struct {
+	InstanceCreate []byte
+	InstanceUpdate []byte
+	BindingCreate  []byte
+}
+
 type Binding struct {
-	Guid             string
-	Name             string
-	Owner            string
-	Generation       int64
-	ParameterHash    string
+	Guid                string
+	Name                string
+	ServiceInstanceGuid string
+	AppGuid             string
+	// Type of the binding, key (service key) or app (app binding)
+	Type  BindingType
+	Owner string
+	// Namespace and name of the owning object, as recorded in the owner-namespace and owner-name labels (empty if not recorded)
+	OwnerNamespace string
+	OwnerName      string
+	// Identifier of the owning cluster, as recorded in the owner-cluster label (empty if not recorded)
+	OwnerCluster string
+	// Whether the binding is a replacement of the binding owned by Owner, which was not yet promoted
+	Replacement   bool
+	Generation    int64
+	ParameterHash string
+	// Whether custom labels or annotations (spec.metadata) were applied to the binding by the operator
+	CustomMetadata   bool
 	State            BindingState
 	StateDescription string
-	Credentials      map[string]interface{}
+	// Last operation performed on the binding, as reported by Cloud Foundry (nil if not reported)
+	LastOperation *LastOperation
 }
 
+type Route struct {
+	Guid       string
+	Host       string
+	Path       string
+	DomainGuid string
+	URL        string
+	Owner      string
+	Generation int64
+}
+
+type RouteBinding struct {
+	Guid                string
+	RouteGuid           string
+	ServiceInstanceGuid string
+	RouteServiceURL     string
+	Owner               string
+	Generation          int64
+	ParameterHash       string
+	State               BindingState
+	StateDescription    string
+}
+
+type BindingType string
+
+const (
+	BindingTypeKey BindingType = "key"
+	BindingTypeApp BindingType = "app"
+)
+
 type BindingState string
 
 const (
@@ -62,32 +185,85 @@ const (
 	BindingStateDeleted       BindingState = "Deleted"
 )
 
+// OwnerRef returns the owner of the space (as recorded in its owner label).
+func (s *Space) OwnerRef() OwnerRef {
+	return OwnerRef{UID: s.Owner}
+}
+
+// OwnerRef returns the owner of the instance (as recorded in its owner label).
+func (i *Instance) OwnerRef() OwnerRef {
+	return OwnerRef{UID: i.Owner, Namespace: i.OwnerNamespace, Name: i.OwnerName}
+}
+
+// OwnerRef returns the owner of the binding (as recorded in its owner label).
+func (b *Binding) OwnerRef() OwnerRef {
+	return OwnerRef{UID: b.Owner, Replacement: b.Replacement, Namespace: b.OwnerNamespace, Name: b.OwnerName}
+}
+
 //counterfeiter:generate . OrganizationClient
 type OrganizationClient interface {
 	GetSpace(ctx context.Context, owner string) (*Space, error)
-	CreateSpace(ctx context.Context, name string, owner string, generation int64) error
-	UpdateSpace(ctx context.Context, guid string, name string, generation int64) error
-	DeleteSpace(ctx context.Context, guid string) error
-	AddAuditor(ctx context.Context, guid string, username string) error
-	AddDeveloper(ctx context.Context, guid string, username string) error
-	AddManager(ctx context.Context, guid string, username string) error
+	// CreateSpace creates a space, and returns it (spaces are created synchronously).
+	CreateSpace(ctx context.Context, name string, owner OwnerRef, generation int64) (*Space, error)
+	UpdateSpace(ctx context.Context, guid string, owner OwnerRef, name string, generation int64) error
+	DeleteSpace(ctx context.Context, guid string, owner OwnerRef) error
+	AddAuditor(ctx context.Context, guid string, username string, origin string) error
+	AddDeveloper(ctx context.Context, guid string, username string, origin string) error
+	AddManager(ctx context.Context, guid string, username string, origin string) error
+	RemoveAuditor(ctx context.Context, guid string, username string, origin string) error
+	RemoveDeveloper(ctx context.Context, guid string, username string, origin string) error
+	RemoveManager(ctx context.Context, guid string, username string, origin string) error
 }
 
-type OrganizationClientBuilder func(string, string, string, string) (OrganizationClient, error)
+type OrganizationClientBuilder func(string, string, string, string, *config.Config) (OrganizationClient, error)
 
 //counterfeiter:generate . SpaceClient
 type SpaceClient interface {
 	GetInstance(ctx context.Context, instanceOpts map[string]string) (*Instance, error)
-	CreateInstance(ctx context.Context, name string, servicePlanGuid string, parameters map[string]interface{}, tags []string, owner string, generation int64) error
-	UpdateInstance(ctx context.Context, guid string, name string, servicePlanGuid string, parameters map[string]interface{}, tags []string, generation int64) error
-	DeleteInstance(ctx context.Context, guid string) error
+	// CreateInstance creates an instance, and returns it in state Creating; since managed instances are created asynchronously,
+	// Cloud Foundry does not return them, so the guid of the returned instance is empty (it is known once the instance is read again).
+	CreateInstance(ctx context.Context, name string, servicePlanGuid string, parameters map[string]interface{}, tags []string, metadata *Metadata, owner OwnerRef, generation int64) (*Instance, error)
+	UpdateInstance(ctx context.Context, guid string, owner OwnerRef, name string, servicePlanGuid string, parameters map[string]interface{}, tags []string, metadata *Metadata, generation int64) error
+	UpgradeInstance(ctx context.Context, guid string, owner OwnerRef, maintenanceInfo MaintenanceInfo) error
+	DeleteInstance(ctx context.Context, guid string, owner OwnerRef) error
+	ListInstances(ctx context.Context) ([]*Instance, error)
+	// FindInstanceName returns the instance with the given name in the space (nil if there is none), no matter of which type
+	// it is, and whom it is owned by.
+	FindInstanceName(ctx context.Context, name string) (*InstanceName, error)
+	// GetInstanceParameters returns the parameters of the instance with the given guid, as reported by the service broker;
+	// fails if the broker does not support retrieving instance parameters.
+	GetInstanceParameters(ctx context.Context, guid string) (map[string]interface{}, error)
 
 	GetBinding(ctx context.Context, bindingOpts map[string]string) (*Binding, error)
-	CreateBinding(ctx context.Context, name string, serviceInstanceGuid string, parameters map[string]interface{}, owner string, generation int64) error
-	UpdateBinding(ctx context.Context, guid string, generation int64, parameters map[string]interface{}) error
-	DeleteBinding(ctx context.Context, guid string) error
+	GetBindingCredentials(ctx context.Context, guid string) (map[string]interface{}, error)
+	// CreateBinding creates a binding, and returns it; bindings of user-provided instances are created synchronously, and returned as read
+	// from Cloud Foundry, all other bindings are created asynchronously, and returned in state Creating, with empty guid.
+	CreateBinding(ctx context.Context, name string, serviceInstanceGuid string, appGuid string, parameters map[string]interface{}, metadata *Metadata, owner OwnerRef, generation int64) (*Binding, error)
+	UpdateBinding(ctx context.Context, guid string, owner OwnerRef, generation int64, parameters map[string]interface{}, metadata *Metadata) error
+	DeleteBinding(ctx context.Context, guid string, owner OwnerRef) error
+	PromoteBinding(ctx context.Context, guid string, owner OwnerRef) error
+	ListBindings(ctx context.Context) ([]*Binding, error)
+
+	// RefreshCache reads the service instances and bindings of the space which are owned by some object into the resource cache
+	// (no-op if the resource cache is disabled).
+	RefreshCache(ctx context.Context) error
 
 	FindServicePlan(ctx context.Context, serviceOfferingName string, servicePlanName string, spaceGuid string) (string, error)
+	ListServicePlans(ctx context.Context, spaceGuid string) ([]ServicePlan, error)
+	GetServicePlanSchemas(ctx context.Context, servicePlanGuid string, spaceGuid string) (*ServicePlanSchemas, error)
+
+	FindApp(ctx context.Context, name string) (string, error)
+
+	FindDomain(ctx context.Context, name string) (string, error)
+	GetRoute(ctx context.Context, owner string) (*Route, error)
+	CreateRoute(ctx context.Context, domainGuid string, host string, path string, owner string, generation int64) error
+	UpdateRoute(ctx context.Context, guid string, generation int64) error
+	DeleteRoute(ctx context.Context, guid string) error
+
+	GetRouteBinding(ctx context.Context, owner string) (*RouteBinding, error)
+	CreateRouteBinding(ctx context.Context, routeGuid string, serviceInstanceGuid string, parameters map[string]interface{}, owner string, generation int64) error
+	UpdateRouteBinding(ctx context.Context, guid string, generation int64) error
+	DeleteRouteBinding(ctx context.Context, guid string) error
 }
 
-type SpaceClientBuilder func(string, string, string, string) (SpaceClient, error)
+type SpaceClientBuilder func(string, string, string, string, *config.Config) (SpaceClient, error)
@@ -0,0 +1,123 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	cfresource "github.com/cloudfoundry-community/go-cfclient/v3/resource"
+)
+
+// UnavailableError indicates that requests to a Cloud Foundry API endpoint are suspended
+// (after repeated server or connection errors) until the given time.
+type UnavailableError struct {
+	URL   string
+	Until time.Time
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("Cloud Foundry API %s is unavailable; requests suspended until %s", e.URL, e.Until.UTC().Format(time.RFC3339))
+}
+
+// ServicePlanNotFoundError indicates that a service offering, or a service plan of that offering,
+// does not exist (or is not visible in the respective space); if ServicePlanName is empty, the offering was not found.
+type ServicePlanNotFoundError struct {
+	ServiceOfferingName string
+	ServicePlanName     string
+}
+
+func (e *ServicePlanNotFoundError) Error() string {
+	if e.ServicePlanName == "" {
+		return fmt.Sprintf("found no service offering with name: %s", e.ServiceOfferingName)
+	}
+	return fmt.Sprintf("found no service plan with name: %s (service offering: %s)", e.ServicePlanName, e.ServiceOfferingName)
+}
+
+// Reasons classifying errors returned by the Cloud Foundry API (see CFError.Reason)
+const (
+	CFErrorReasonQuotaExceeded           = "QuotaExceeded"
+	CFErrorReasonServicePlanNotAvailable = "ServicePlanNotAvailable"
+	CFErrorReasonServiceBrokerTimeout    = "ServiceBrokerTimeout"
+	CFErrorReasonServiceBrokerError      = "ServiceBrokerError"
+	CFErrorReasonResourceNotFound        = "ResourceNotFound"
+	CFErrorReasonNotAuthorized           = "NotAuthorized"
+	CFErrorReasonOther                   = "CFError"
+)
+
+// reasons of Cloud Foundry API errors, by error code
+var cfErrorReasons = map[int]string{
+	10000:  CFErrorReasonResourceNotFound,        // CF-NotFound
+	10002:  CFErrorReasonNotAuthorized,           // CF-NotAuthenticated
+	10003:  CFErrorReasonNotAuthorized,           // CF-NotAuthorized
+	10010:  CFErrorReasonResourceNotFound,        // CF-ResourceNotFound
+	60005:  CFErrorReasonQuotaExceeded,           // CF-ServiceInstanceQuotaExceeded
+	60006:  CFErrorReasonQuotaExceeded,           // CF-PreviouslyUsedAs_ServiceInstancePaidQuotaExceeded
+	60007:  CFErrorReasonServicePlanNotAvailable, // CF-ServiceInstanceServicePlanNotAllowed
+	60012:  CFErrorReasonQuotaExceeded,           // CF-ServiceInstanceSpaceQuotaExceeded
+	60013:  CFErrorReasonServicePlanNotAvailable, // CF-ServiceInstanceServicePlanNotAllowedBySpaceQuota
+	110003: CFErrorReasonServicePlanNotAvailable, // CF-ServicePlanNotFound
+	290006: CFErrorReasonServiceBrokerTimeout,    // CF-JobTimeout
+}
+
+// CFError is an error returned by the Cloud Foundry API; it wraps the original error (as returned by the client library,
+// possibly wrapped with context), and carries the error code, title and detail reported by Cloud Foundry.
+type CFError struct {
+	Code   int
+	Title  string
+	Detail string
+	Err    error
+}
+
+func (e *CFError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CFError) Unwrap() error {
+	return e.Err
+}
+
+// ClassifyError returns the error returned by the Cloud Foundry API contained in the given error, as CFError (classified by its Reason),
+// such that callers need not depend on the client library; the result is nil if the given error does not contain such an error.
+func ClassifyError(err error) *CFError {
+	var cfErr *CFError
+	if errors.As(err, &cfErr) {
+		return cfErr
+	}
+	var cfclientErr cfresource.CloudFoundryError
+	if !errors.As(err, &cfclientErr) {
+		return nil
+	}
+	return &CFError{Code: cfclientErr.Code, Title: cfclientErr.Title, Detail: cfclientErr.Detail, Err: err}
+}
+
+// Message returns a description of the error suitable for condition messages, that is, the detail reported by
+// Cloud Foundry, followed by title and code (omitting the context the error was wrapped with).
+func (e *CFError) Message() string {
+	return fmt.Sprintf("%s (%s, code %d)", e.Detail, e.Title, e.Code)
+}
+
+// Reason classifies the error, returning one of the CFErrorReason constants.
+func (e *CFError) Reason() string {
+	if reason, ok := cfErrorReasons[e.Code]; ok {
+		return reason
+	}
+	switch {
+	case strings.Contains(e.Title, "QuotaExceeded"):
+		return CFErrorReasonQuotaExceeded
+	case strings.Contains(e.Title, "Timeout"):
+		return CFErrorReasonServiceBrokerTimeout
+	case strings.HasPrefix(e.Title, "CF-ServiceBroker"), e.Title == "CF-ServiceGatewayError":
+		return CFErrorReasonServiceBrokerError
+	case e.Code == 10008 && strings.Contains(e.Detail, "Invalid service plan"):
+		// the plan does not exist, is not available, or not visible in the space
+		return CFErrorReasonServicePlanNotAvailable
+	default:
+		return CFErrorReasonOther
+	}
+}
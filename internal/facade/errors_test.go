@@ -0,0 +1,47 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	cfresource "github.com/cloudfoundry-community/go-cfclient/v3/resource"
+	. "github.com/onsi/gomega"
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestClassifyError(t *testing.T) {
+	g := NewWithT(t)
+
+	original := pkgerrors.Wrap(cfresource.NewServiceInstanceQuotaExceededError(), "failed to create instance")
+	cfErr := ClassifyError(original)
+	g.Expect(cfErr).NotTo(BeNil())
+	g.Expect(cfErr).To(MatchError(original.Error()))
+	g.Expect(errors.Is(cfErr, original)).To(BeTrue())
+	g.Expect(cfresource.IsServiceInstanceQuotaExceededError(cfErr)).To(BeTrue())
+	g.Expect(ClassifyError(pkgerrors.Wrap(cfErr, "reconcile failed"))).To(BeIdenticalTo(cfErr))
+
+	g.Expect(ClassifyError(nil)).To(BeNil())
+	g.Expect(ClassifyError(fmt.Errorf("connection refused"))).To(BeNil())
+}
+
+func TestCFErrorReason(t *testing.T) {
+	g := NewWithT(t)
+
+	reason := func(cfErr cfresource.CloudFoundryError) string {
+		return ClassifyError(pkgerrors.Wrap(cfErr, "failed")).Reason()
+	}
+	g.Expect(reason(cfresource.NewServiceInstanceQuotaExceededError())).To(Equal(CFErrorReasonQuotaExceeded))
+	g.Expect(reason(cfresource.NewServiceInstanceSpaceQuotaExceededError())).To(Equal(CFErrorReasonQuotaExceeded))
+	g.Expect(reason(cfresource.NewServiceInstanceServicePlanNotAllowedBySpaceQuotaError())).To(Equal(CFErrorReasonServicePlanNotAvailable))
+	g.Expect(reason(cfresource.CloudFoundryError{Code: 10008, Title: "CF-UnprocessableEntity", Detail: "Invalid service plan. Ensure that the service plan exists, is available, and you have access to it."})).To(Equal(CFErrorReasonServicePlanNotAvailable))
+	g.Expect(reason(cfresource.CloudFoundryError{Code: 10001, Title: "CF-ServiceBrokerApiTimeout", Detail: "The request to the service broker timed out"})).To(Equal(CFErrorReasonServiceBrokerTimeout))
+	g.Expect(reason(cfresource.NewServiceBrokerRequestRejectedError())).To(Equal(CFErrorReasonServiceBrokerError))
+	g.Expect(reason(cfresource.NewResourceNotFoundError())).To(Equal(CFErrorReasonResourceNotFound))
+	g.Expect(reason(cfresource.NewNotAuthorizedError())).To(Equal(CFErrorReasonNotAuthorized))
+	g.Expect(reason(cfresource.NewUnprocessableEntityError())).To(Equal(CFErrorReasonOther))
+}
@@ -13,12 +13,13 @@ import (
 )
 
 type FakeOrganizationClient struct {
-	AddAuditorStub        func(context.Context, string, string) error
+	AddAuditorStub        func(context.Context, string, string, string) error
 	addAuditorMutex       sync.RWMutex
 	addAuditorArgsForCall []struct {
 		arg1 context.Context
 		arg2 string
 		arg3 string
+		arg4 string
 	}
 	addAuditorReturns struct {
 		result1 error
@@ -26,12 +27,13 @@ type FakeOrganizationClient struct {
 	addAuditorReturnsOnCall map[int]struct {
 		result1 error
 	}
-	AddDeveloperStub        func(context.Context, string, string) error
+	AddDeveloperStub        func(context.Context, string, string, string) error
 	addDeveloperMutex       sync.RWMutex
 	addDeveloperArgsForCall []struct {
 		arg1 context.Context
 		arg2 string
 		arg3 string
+		arg4 string
 	}
 	addDeveloperReturns struct {
 		result1 error
@@ -39,12 +41,13 @@ type FakeOrganizationClient struct {
 	addDeveloperReturnsOnCall map[int]struct {
 		result1 error
 	}
-	AddManagerStub        func(context.Context, string, string) error
+	AddManagerStub        func(context.Context, string, string, string) error
 	addManagerMutex       sync.RWMutex
 	addManagerArgsForCall []struct {
 		arg1 context.Context
 		arg2 string
 		arg3 string
+		arg4 string
 	}
 	addManagerReturns struct {
 		result1 error
@@ -52,25 +55,28 @@ type FakeOrganizationClient struct {
 	addManagerReturnsOnCall map[int]struct {
 		result1 error
 	}
-	CreateSpaceStub        func(context.Context, string, string, int64) error
+	CreateSpaceStub        func(context.Context, string, facade.OwnerRef, int64) (*facade.Space, error)
 	createSpaceMutex       sync.RWMutex
 	createSpaceArgsForCall []struct {
 		arg1 context.Context
 		arg2 string
-		arg3 string
+		arg3 facade.OwnerRef
 		arg4 int64
 	}
 	createSpaceReturns struct {
-		result1 error
+		result1 *facade.Space
+		result2 error
 	}
 	createSpaceReturnsOnCall map[int]struct {
-		result1 error
+		result1 *facade.Space
+		result2 error
 	}
-	DeleteSpaceStub        func(context.Context, string) error
+	DeleteSpaceStub        func(context.Context, string, facade.OwnerRef) error
 	deleteSpaceMutex       sync.RWMutex
 	deleteSpaceArgsForCall []struct {
 		arg1 context.Context
 		arg2 string
+		arg3 facade.OwnerRef
 	}
 	deleteSpaceReturns struct {
 		result1 error
@@ -92,13 +98,56 @@ type FakeOrganizationClient struct {
 		result1 *facade.Space
 		result2 error
 	}
-	UpdateSpaceStub        func(context.Context, string, string, int64) error
+	RemoveAuditorStub        func(context.Context, string, string, string) error
+	removeAuditorMutex       sync.RWMutex
+	removeAuditorArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	removeAuditorReturns struct {
+		result1 error
+	}
+	removeAuditorReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RemoveDeveloperStub        func(context.Context, string, string, string) error
+	removeDeveloperMutex       sync.RWMutex
+	removeDeveloperArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	removeDeveloperReturns struct {
+		result1 error
+	}
+	removeDeveloperReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RemoveManagerStub        func(context.Context, string, string, string) error
+	removeManagerMutex       sync.RWMutex
+	removeManagerArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	removeManagerReturns struct {
+		result1 error
+	}
+	removeManagerReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateSpaceStub        func(context.Context, string, facade.OwnerRef, string, int64) error
 	updateSpaceMutex       sync.RWMutex
 	updateSpaceArgsForCall []struct {
 		arg1 context.Context
 		arg2 string
-		arg3 string
-		arg4 int64
+		arg3 facade.OwnerRef
+		arg4 string
+		arg5 int64
 	}
 	updateSpaceReturns struct {
 		result1 error
@@ -110,20 +159,21 @@ type FakeOrganizationClient struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeOrganizationClient) AddAuditor(arg1 context.Context, arg2 string, arg3 string) error {
+func (fake *FakeOrganizationClient) AddAuditor(arg1 context.Context, arg2 string, arg3 string, arg4 string) error {
 	fake.addAuditorMutex.Lock()
 	ret, specificReturn := fake.addAuditorReturnsOnCall[len(fake.addAuditorArgsForCall)]
 	fake.addAuditorArgsForCall = append(fake.addAuditorArgsForCall, struct {
 		arg1 context.Context
 		arg2 string
 		arg3 string
-	}{arg1, arg2, arg3})
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.AddAuditorStub
 	fakeReturns := fake.addAuditorReturns
-	fake.recordInvocation("AddAuditor", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("AddAuditor", []interface{}{arg1, arg2, arg3, arg4})
 	fake.addAuditorMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -137,17 +187,17 @@ func (fake *FakeOrganizationClient) AddAuditorCallCount() int {
 	return len(fake.addAuditorArgsForCall)
 }
 
-func (fake *FakeOrganizationClient) AddAuditorCalls(stub func(context.Context, string, string) error) {
+func (fake *FakeOrganizationClient) AddAuditorCalls(stub func(context.Context, string, string, string) error) {
 	fake.addAuditorMutex.Lock()
 	defer fake.addAuditorMutex.Unlock()
 	fake.AddAuditorStub = stub
 }
 
-func (fake *FakeOrganizationClient) AddAuditorArgsForCall(i int) (context.Context, string, string) {
+func (fake *FakeOrganizationClient) AddAuditorArgsForCall(i int) (context.Context, string, string, string) {
 	fake.addAuditorMutex.RLock()
 	defer fake.addAuditorMutex.RUnlock()
 	argsForCall := fake.addAuditorArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeOrganizationClient) AddAuditorReturns(result1 error) {
@@ -173,20 +223,21 @@ func (fake *FakeOrganizationClient) AddAuditorReturnsOnCall(i int, result1 error
 	}{result1}
 }
 
-func (fake *FakeOrganizationClient) AddDeveloper(arg1 context.Context, arg2 string, arg3 string) error {
+func (fake *FakeOrganizationClient) AddDeveloper(arg1 context.Context, arg2 string, arg3 string, arg4 string) error {
 	fake.addDeveloperMutex.Lock()
 	ret, specificReturn := fake.addDeveloperReturnsOnCall[len(fake.addDeveloperArgsForCall)]
 	fake.addDeveloperArgsForCall = append(fake.addDeveloperArgsForCall, struct {
 		arg1 context.Context
 		arg2 string
 		arg3 string
-	}{arg1, arg2, arg3})
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.AddDeveloperStub
 	fakeReturns := fake.addDeveloperReturns
-	fake.recordInvocation("AddDeveloper", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("AddDeveloper", []interface{}{arg1, arg2, arg3, arg4})
 	fake.addDeveloperMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -200,17 +251,17 @@ func (fake *FakeOrganizationClient) AddDeveloperCallCount() int {
 	return len(fake.addDeveloperArgsForCall)
 }
 
-func (fake *FakeOrganizationClient) AddDeveloperCalls(stub func(context.Context, string, string) error) {
+func (fake *FakeOrganizationClient) AddDeveloperCalls(stub func(context.Context, string, string, string) error) {
 	fake.addDeveloperMutex.Lock()
 	defer fake.addDeveloperMutex.Unlock()
 	fake.AddDeveloperStub = stub
 }
 
-func (fake *FakeOrganizationClient) AddDeveloperArgsForCall(i int) (context.Context, string, string) {
+func (fake *FakeOrganizationClient) AddDeveloperArgsForCall(i int) (context.Context, string, string, string) {
 	fake.addDeveloperMutex.RLock()
 	defer fake.addDeveloperMutex.RUnlock()
 	argsForCall := fake.addDeveloperArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeOrganizationClient) AddDeveloperReturns(result1 error) {
@@ -236,20 +287,21 @@ func (fake *FakeOrganizationClient) AddDeveloperReturnsOnCall(i int, result1 err
 	}{result1}
 }
 
-func (fake *FakeOrganizationClient) AddManager(arg1 context.Context, arg2 string, arg3 string) error {
+func (fake *FakeOrganizationClient) AddManager(arg1 context.Context, arg2 string, arg3 string, arg4 string) error {
 	fake.addManagerMutex.Lock()
 	ret, specificReturn := fake.addManagerReturnsOnCall[len(fake.addManagerArgsForCall)]
 	fake.addManagerArgsForCall = append(fake.addManagerArgsForCall, struct {
 		arg1 context.Context
 		arg2 string
 		arg3 string
-	}{arg1, arg2, arg3})
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.AddManagerStub
 	fakeReturns := fake.addManagerReturns
-	fake.recordInvocation("AddManager", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("AddManager", []interface{}{arg1, arg2, arg3, arg4})
 	fake.addManagerMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -263,17 +315,17 @@ func (fake *FakeOrganizationClient) AddManagerCallCount() int {
 	return len(fake.addManagerArgsForCall)
 }
 
-func (fake *FakeOrganizationClient) AddManagerCalls(stub func(context.Context, string, string) error) {
+func (fake *FakeOrganizationClient) AddManagerCalls(stub func(context.Context, string, string, string) error) {
 	fake.addManagerMutex.Lock()
 	defer fake.addManagerMutex.Unlock()
 	fake.AddManagerStub = stub
 }
 
-func (fake *FakeOrganizationClient) AddManagerArgsForCall(i int) (context.Context, string, string) {
+func (fake *FakeOrganizationClient) AddManagerArgsForCall(i int) (context.Context, string, string, string) {
 	fake.addManagerMutex.RLock()
 	defer fake.addManagerMutex.RUnlock()
 	argsForCall := fake.addManagerArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeOrganizationClient) AddManagerReturns(result1 error) {
@@ -299,13 +351,13 @@ func (fake *FakeOrganizationClient) AddManagerReturnsOnCall(i int, result1 error
 	}{result1}
 }
 
-func (fake *FakeOrganizationClient) CreateSpace(arg1 context.Context, arg2 string, arg3 string, arg4 int64) error {
+func (fake *FakeOrganizationClient) CreateSpace(arg1 context.Context, arg2 string, arg3 facade.OwnerRef, arg4 int64) (*facade.Space, error) {
 	fake.createSpaceMutex.Lock()
 	ret, specificReturn := fake.createSpaceReturnsOnCall[len(fake.createSpaceArgsForCall)]
 	fake.createSpaceArgsForCall = append(fake.createSpaceArgsForCall, struct {
 		arg1 context.Context
 		arg2 string
-		arg3 string
+		arg3 facade.OwnerRef
 		arg4 int64
 	}{arg1, arg2, arg3, arg4})
 	stub := fake.CreateSpaceStub
@@ -316,9 +368,9 @@ func (fake *FakeOrganizationClient) CreateSpace(arg1 context.Context, arg2 strin
 		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeOrganizationClient) CreateSpaceCallCount() int {
@@ -327,55 +379,59 @@ func (fake *FakeOrganizationClient) CreateSpaceCallCount() int {
 	return len(fake.createSpaceArgsForCall)
 }
 
-func (fake *FakeOrganizationClient) CreateSpaceCalls(stub func(context.Context, string, string, int64) error) {
+func (fake *FakeOrganizationClient) CreateSpaceCalls(stub func(context.Context, string, facade.OwnerRef, int64) (*facade.Space, error)) {
 	fake.createSpaceMutex.Lock()
 	defer fake.createSpaceMutex.Unlock()
 	fake.CreateSpaceStub = stub
 }
 
-func (fake *FakeOrganizationClient) CreateSpaceArgsForCall(i int) (context.Context, string, string, int64) {
+func (fake *FakeOrganizationClient) CreateSpaceArgsForCall(i int) (context.Context, string, facade.OwnerRef, int64) {
 	fake.createSpaceMutex.RLock()
 	defer fake.createSpaceMutex.RUnlock()
 	argsForCall := fake.createSpaceArgsForCall[i]
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
-func (fake *FakeOrganizationClient) CreateSpaceReturns(result1 error) {
+func (fake *FakeOrganizationClient) CreateSpaceReturns(result1 *facade.Space, result2 error) {
 	fake.createSpaceMutex.Lock()
 	defer fake.createSpaceMutex.Unlock()
 	fake.CreateSpaceStub = nil
 	fake.createSpaceReturns = struct {
-		result1 error
-	}{result1}
+		result1 *facade.Space
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeOrganizationClient) CreateSpaceReturnsOnCall(i int, result1 error) {
+func (fake *FakeOrganizationClient) CreateSpaceReturnsOnCall(i int, result1 *facade.Space, result2 error) {
 	fake.createSpaceMutex.Lock()
 	defer fake.createSpaceMutex.Unlock()
 	fake.CreateSpaceStub = nil
 	if fake.createSpaceReturnsOnCall == nil {
 		fake.createSpaceReturnsOnCall = make(map[int]struct {
-			result1 error
+			result1 *facade.Space
+			result2 error
 		})
 	}
 	fake.createSpaceReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+		result1 *facade.Space
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeOrganizationClient) DeleteSpace(arg1 context.Context, arg2 string) error {
+func (fake *FakeOrganizationClient) DeleteSpace(arg1 context.Context, arg2 string, arg3 facade.OwnerRef) error {
 	fake.deleteSpaceMutex.Lock()
 	ret, specificReturn := fake.deleteSpaceReturnsOnCall[len(fake.deleteSpaceArgsForCall)]
 	fake.deleteSpaceArgsForCall = append(fake.deleteSpaceArgsForCall, struct {
 		arg1 context.Context
 		arg2 string
-	}{arg1, arg2})
+		arg3 facade.OwnerRef
+	}{arg1, arg2, arg3})
 	stub := fake.DeleteSpaceStub
 	fakeReturns := fake.deleteSpaceReturns
-	fake.recordInvocation("DeleteSpace", []interface{}{arg1, arg2})
+	fake.recordInvocation("DeleteSpace", []interface{}{arg1, arg2, arg3})
 	fake.deleteSpaceMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -389,17 +445,17 @@ func (fake *FakeOrganizationClient) DeleteSpaceCallCount() int {
 	return len(fake.deleteSpaceArgsForCall)
 }
 
-func (fake *FakeOrganizationClient) DeleteSpaceCalls(stub func(context.Context, string) error) {
+func (fake *FakeOrganizationClient) DeleteSpaceCalls(stub func(context.Context, string, facade.OwnerRef) error) {
 	fake.deleteSpaceMutex.Lock()
 	defer fake.deleteSpaceMutex.Unlock()
 	fake.DeleteSpaceStub = stub
 }
 
-func (fake *FakeOrganizationClient) DeleteSpaceArgsForCall(i int) (context.Context, string) {
+func (fake *FakeOrganizationClient) DeleteSpaceArgsForCall(i int) (context.Context, string, facade.OwnerRef) {
 	fake.deleteSpaceMutex.RLock()
 	defer fake.deleteSpaceMutex.RUnlock()
 	argsForCall := fake.deleteSpaceArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeOrganizationClient) DeleteSpaceReturns(result1 error) {
@@ -490,21 +546,214 @@ func (fake *FakeOrganizationClient) GetSpaceReturnsOnCall(i int, result1 *facade
 	}{result1, result2}
 }
 
-func (fake *FakeOrganizationClient) UpdateSpace(arg1 context.Context, arg2 string, arg3 string, arg4 int64) error {
+func (fake *FakeOrganizationClient) RemoveAuditor(arg1 context.Context, arg2 string, arg3 string, arg4 string) error {
+	fake.removeAuditorMutex.Lock()
+	ret, specificReturn := fake.removeAuditorReturnsOnCall[len(fake.removeAuditorArgsForCall)]
+	fake.removeAuditorArgsForCall = append(fake.removeAuditorArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.RemoveAuditorStub
+	fakeReturns := fake.removeAuditorReturns
+	fake.recordInvocation("RemoveAuditor", []interface{}{arg1, arg2, arg3, arg4})
+	fake.removeAuditorMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeOrganizationClient) RemoveAuditorCallCount() int {
+	fake.removeAuditorMutex.RLock()
+	defer fake.removeAuditorMutex.RUnlock()
+	return len(fake.removeAuditorArgsForCall)
+}
+
+func (fake *FakeOrganizationClient) RemoveAuditorCalls(stub func(context.Context, string, string, string) error) {
+	fake.removeAuditorMutex.Lock()
+	defer fake.removeAuditorMutex.Unlock()
+	fake.RemoveAuditorStub = stub
+}
+
+func (fake *FakeOrganizationClient) RemoveAuditorArgsForCall(i int) (context.Context, string, string, string) {
+	fake.removeAuditorMutex.RLock()
+	defer fake.removeAuditorMutex.RUnlock()
+	argsForCall := fake.removeAuditorArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeOrganizationClient) RemoveAuditorReturns(result1 error) {
+	fake.removeAuditorMutex.Lock()
+	defer fake.removeAuditorMutex.Unlock()
+	fake.RemoveAuditorStub = nil
+	fake.removeAuditorReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOrganizationClient) RemoveAuditorReturnsOnCall(i int, result1 error) {
+	fake.removeAuditorMutex.Lock()
+	defer fake.removeAuditorMutex.Unlock()
+	fake.RemoveAuditorStub = nil
+	if fake.removeAuditorReturnsOnCall == nil {
+		fake.removeAuditorReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeAuditorReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOrganizationClient) RemoveDeveloper(arg1 context.Context, arg2 string, arg3 string, arg4 string) error {
+	fake.removeDeveloperMutex.Lock()
+	ret, specificReturn := fake.removeDeveloperReturnsOnCall[len(fake.removeDeveloperArgsForCall)]
+	fake.removeDeveloperArgsForCall = append(fake.removeDeveloperArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.RemoveDeveloperStub
+	fakeReturns := fake.removeDeveloperReturns
+	fake.recordInvocation("RemoveDeveloper", []interface{}{arg1, arg2, arg3, arg4})
+	fake.removeDeveloperMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeOrganizationClient) RemoveDeveloperCallCount() int {
+	fake.removeDeveloperMutex.RLock()
+	defer fake.removeDeveloperMutex.RUnlock()
+	return len(fake.removeDeveloperArgsForCall)
+}
+
+func (fake *FakeOrganizationClient) RemoveDeveloperCalls(stub func(context.Context, string, string, string) error) {
+	fake.removeDeveloperMutex.Lock()
+	defer fake.removeDeveloperMutex.Unlock()
+	fake.RemoveDeveloperStub = stub
+}
+
+func (fake *FakeOrganizationClient) RemoveDeveloperArgsForCall(i int) (context.Context, string, string, string) {
+	fake.removeDeveloperMutex.RLock()
+	defer fake.removeDeveloperMutex.RUnlock()
+	argsForCall := fake.removeDeveloperArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeOrganizationClient) RemoveDeveloperReturns(result1 error) {
+	fake.removeDeveloperMutex.Lock()
+	defer fake.removeDeveloperMutex.Unlock()
+	fake.RemoveDeveloperStub = nil
+	fake.removeDeveloperReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOrganizationClient) RemoveDeveloperReturnsOnCall(i int, result1 error) {
+	fake.removeDeveloperMutex.Lock()
+	defer fake.removeDeveloperMutex.Unlock()
+	fake.RemoveDeveloperStub = nil
+	if fake.removeDeveloperReturnsOnCall == nil {
+		fake.removeDeveloperReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeDeveloperReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOrganizationClient) RemoveManager(arg1 context.Context, arg2 string, arg3 string, arg4 string) error {
+	fake.removeManagerMutex.Lock()
+	ret, specificReturn := fake.removeManagerReturnsOnCall[len(fake.removeManagerArgsForCall)]
+	fake.removeManagerArgsForCall = append(fake.removeManagerArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.RemoveManagerStub
+	fakeReturns := fake.removeManagerReturns
+	fake.recordInvocation("RemoveManager", []interface{}{arg1, arg2, arg3, arg4})
+	fake.removeManagerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeOrganizationClient) RemoveManagerCallCount() int {
+	fake.removeManagerMutex.RLock()
+	defer fake.removeManagerMutex.RUnlock()
+	return len(fake.removeManagerArgsForCall)
+}
+
+func (fake *FakeOrganizationClient) RemoveManagerCalls(stub func(context.Context, string, string, string) error) {
+	fake.removeManagerMutex.Lock()
+	defer fake.removeManagerMutex.Unlock()
+	fake.RemoveManagerStub = stub
+}
+
+func (fake *FakeOrganizationClient) RemoveManagerArgsForCall(i int) (context.Context, string, string, string) {
+	fake.removeManagerMutex.RLock()
+	defer fake.removeManagerMutex.RUnlock()
+	argsForCall := fake.removeManagerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeOrganizationClient) RemoveManagerReturns(result1 error) {
+	fake.removeManagerMutex.Lock()
+	defer fake.removeManagerMutex.Unlock()
+	fake.RemoveManagerStub = nil
+	fake.removeManagerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOrganizationClient) RemoveManagerReturnsOnCall(i int, result1 error) {
+	fake.removeManagerMutex.Lock()
+	defer fake.removeManagerMutex.Unlock()
+	fake.RemoveManagerStub = nil
+	if fake.removeManagerReturnsOnCall == nil {
+		fake.removeManagerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeManagerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOrganizationClient) UpdateSpace(arg1 context.Context, arg2 string, arg3 facade.OwnerRef, arg4 string, arg5 int64) error {
 	fake.updateSpaceMutex.Lock()
 	ret, specificReturn := fake.updateSpaceReturnsOnCall[len(fake.updateSpaceArgsForCall)]
 	fake.updateSpaceArgsForCall = append(fake.updateSpaceArgsForCall, struct {
 		arg1 context.Context
 		arg2 string
-		arg3 string
-		arg4 int64
-	}{arg1, arg2, arg3, arg4})
+		arg3 facade.OwnerRef
+		arg4 string
+		arg5 int64
+	}{arg1, arg2, arg3, arg4, arg5})
 	stub := fake.UpdateSpaceStub
 	fakeReturns := fake.updateSpaceReturns
-	fake.recordInvocation("UpdateSpace", []interface{}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("UpdateSpace", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.updateSpaceMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1
@@ -518,17 +767,17 @@ func (fake *FakeOrganizationClient) UpdateSpaceCallCount() int {
 	return len(fake.updateSpaceArgsForCall)
 }
 
-func (fake *FakeOrganizationClient) UpdateSpaceCalls(stub func(context.Context, string, string, int64) error) {
+func (fake *FakeOrganizationClient) UpdateSpaceCalls(stub func(context.Context, string, facade.OwnerRef, string, int64) error) {
 	fake.updateSpaceMutex.Lock()
 	defer fake.updateSpaceMutex.Unlock()
 	fake.UpdateSpaceStub = stub
 }
 
-func (fake *FakeOrganizationClient) UpdateSpaceArgsForCall(i int) (context.Context, string, string, int64) {
+func (fake *FakeOrganizationClient) UpdateSpaceArgsForCall(i int) (context.Context, string, facade.OwnerRef, string, int64) {
 	fake.updateSpaceMutex.RLock()
 	defer fake.updateSpaceMutex.RUnlock()
 	argsForCall := fake.updateSpaceArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeOrganizationClient) UpdateSpaceReturns(result1 error) {
@@ -569,6 +818,12 @@ func (fake *FakeOrganizationClient) Invocations() map[string][][]interface{} {
 	defer fake.deleteSpaceMutex.RUnlock()
 	fake.getSpaceMutex.RLock()
 	defer fake.getSpaceMutex.RUnlock()
+	fake.removeAuditorMutex.RLock()
+	defer fake.removeAuditorMutex.RUnlock()
+	fake.removeDeveloperMutex.RLock()
+	defer fake.removeDeveloperMutex.RUnlock()
+	fake.removeManagerMutex.RLock()
+	defer fake.removeManagerMutex.RUnlock()
 	fake.updateSpaceMutex.RLock()
 	defer fake.updateSpaceMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
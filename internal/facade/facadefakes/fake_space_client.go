@@ -13,23 +13,27 @@ import (
 )
 
 type FakeSpaceClient struct {
-	CreateBindingStub        func(context.Context, string, string, map[string]interface{}, string, int64) error
+	CreateBindingStub        func(context.Context, string, string, string, map[string]interface{}, *facade.Metadata, facade.OwnerRef, int64) (*facade.Binding, error)
 	createBindingMutex       sync.RWMutex
 	createBindingArgsForCall []struct {
 		arg1 context.Context
 		arg2 string
 		arg3 string
-		arg4 map[string]interface{}
-		arg5 string
-		arg6 int64
+		arg4 string
+		arg5 map[string]interface{}
+		arg6 *facade.Metadata
+		arg7 facade.OwnerRef
+		arg8 int64
 	}
 	createBindingReturns struct {
-		result1 error
+		result1 *facade.Binding
+		result2 error
 	}
 	createBindingReturnsOnCall map[int]struct {
-		result1 error
+		result1 *facade.Binding
+		result2 error
 	}
-	CreateInstanceStub        func(context.Context, string, string, map[string]interface{}, []string, string, int64) error
+	CreateInstanceStub        func(context.Context, string, string, map[string]interface{}, []string, *facade.Metadata, facade.OwnerRef, int64) (*facade.Instance, error)
 	createInstanceMutex       sync.RWMutex
 	createInstanceArgsForCall []struct {
 		arg1 context.Context
@@ -37,20 +41,56 @@ type FakeSpaceClient struct {
 		arg3 string
 		arg4 map[string]interface{}
 		arg5 []string
-		arg6 string
-		arg7 int64
+		arg6 *facade.Metadata
+		arg7 facade.OwnerRef
+		arg8 int64
 	}
 	createInstanceReturns struct {
-		result1 error
+		result1 *facade.Instance
+		result2 error
 	}
 	createInstanceReturnsOnCall map[int]struct {
+		result1 *facade.Instance
+		result2 error
+	}
+	CreateRouteStub        func(context.Context, string, string, string, string, int64) error
+	createRouteMutex       sync.RWMutex
+	createRouteArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 string
+		arg6 int64
+	}
+	createRouteReturns struct {
+		result1 error
+	}
+	createRouteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateRouteBindingStub        func(context.Context, string, string, map[string]interface{}, string, int64) error
+	createRouteBindingMutex       sync.RWMutex
+	createRouteBindingArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 map[string]interface{}
+		arg5 string
+		arg6 int64
+	}
+	createRouteBindingReturns struct {
+		result1 error
+	}
+	createRouteBindingReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteBindingStub        func(context.Context, string) error
+	DeleteBindingStub        func(context.Context, string, facade.OwnerRef) error
 	deleteBindingMutex       sync.RWMutex
 	deleteBindingArgsForCall []struct {
 		arg1 context.Context
 		arg2 string
+		arg3 facade.OwnerRef
 	}
 	deleteBindingReturns struct {
 		result1 error
@@ -58,11 +98,12 @@ type FakeSpaceClient struct {
 	deleteBindingReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteInstanceStub        func(context.Context, string) error
+	DeleteInstanceStub        func(context.Context, string, facade.OwnerRef) error
 	deleteInstanceMutex       sync.RWMutex
 	deleteInstanceArgsForCall []struct {
 		arg1 context.Context
 		arg2 string
+		arg3 facade.OwnerRef
 	}
 	deleteInstanceReturns struct {
 		result1 error
@@ -70,6 +111,72 @@ type FakeSpaceClient struct {
 	deleteInstanceReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DeleteRouteStub        func(context.Context, string) error
+	deleteRouteMutex       sync.RWMutex
+	deleteRouteArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	deleteRouteReturns struct {
+		result1 error
+	}
+	deleteRouteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteRouteBindingStub        func(context.Context, string) error
+	deleteRouteBindingMutex       sync.RWMutex
+	deleteRouteBindingArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	deleteRouteBindingReturns struct {
+		result1 error
+	}
+	deleteRouteBindingReturnsOnCall map[int]struct {
+		result1 error
+	}
+	FindAppStub        func(context.Context, string) (string, error)
+	findAppMutex       sync.RWMutex
+	findAppArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	findAppReturns struct {
+		result1 string
+		result2 error
+	}
+	findAppReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	FindDomainStub        func(context.Context, string) (string, error)
+	findDomainMutex       sync.RWMutex
+	findDomainArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	findDomainReturns struct {
+		result1 string
+		result2 error
+	}
+	findDomainReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	FindInstanceNameStub        func(context.Context, string) (*facade.InstanceName, error)
+	findInstanceNameMutex       sync.RWMutex
+	findInstanceNameArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	findInstanceNameReturns struct {
+		result1 *facade.InstanceName
+		result2 error
+	}
+	findInstanceNameReturnsOnCall map[int]struct {
+		result1 *facade.InstanceName
+		result2 error
+	}
 	FindServicePlanStub        func(context.Context, string, string, string) (string, error)
 	findServicePlanMutex       sync.RWMutex
 	findServicePlanArgsForCall []struct {
@@ -100,6 +207,20 @@ type FakeSpaceClient struct {
 		result1 *facade.Binding
 		result2 error
 	}
+	GetBindingCredentialsStub        func(context.Context, string) (map[string]interface{}, error)
+	getBindingCredentialsMutex       sync.RWMutex
+	getBindingCredentialsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getBindingCredentialsReturns struct {
+		result1 map[string]interface{}
+		result2 error
+	}
+	getBindingCredentialsReturnsOnCall map[int]struct {
+		result1 map[string]interface{}
+		result2 error
+	}
 	GetInstanceStub        func(context.Context, map[string]string) (*facade.Instance, error)
 	getInstanceMutex       sync.RWMutex
 	getInstanceArgsForCall []struct {
@@ -114,13 +235,136 @@ type FakeSpaceClient struct {
 		result1 *facade.Instance
 		result2 error
 	}
-	UpdateBindingStub        func(context.Context, string, int64, map[string]interface{}) error
+	GetInstanceParametersStub        func(context.Context, string) (map[string]interface{}, error)
+	getInstanceParametersMutex       sync.RWMutex
+	getInstanceParametersArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getInstanceParametersReturns struct {
+		result1 map[string]interface{}
+		result2 error
+	}
+	getInstanceParametersReturnsOnCall map[int]struct {
+		result1 map[string]interface{}
+		result2 error
+	}
+	GetRouteStub        func(context.Context, string) (*facade.Route, error)
+	getRouteMutex       sync.RWMutex
+	getRouteArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getRouteReturns struct {
+		result1 *facade.Route
+		result2 error
+	}
+	getRouteReturnsOnCall map[int]struct {
+		result1 *facade.Route
+		result2 error
+	}
+	GetRouteBindingStub        func(context.Context, string) (*facade.RouteBinding, error)
+	getRouteBindingMutex       sync.RWMutex
+	getRouteBindingArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getRouteBindingReturns struct {
+		result1 *facade.RouteBinding
+		result2 error
+	}
+	getRouteBindingReturnsOnCall map[int]struct {
+		result1 *facade.RouteBinding
+		result2 error
+	}
+	GetServicePlanSchemasStub        func(context.Context, string, string) (*facade.ServicePlanSchemas, error)
+	getServicePlanSchemasMutex       sync.RWMutex
+	getServicePlanSchemasArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	getServicePlanSchemasReturns struct {
+		result1 *facade.ServicePlanSchemas
+		result2 error
+	}
+	getServicePlanSchemasReturnsOnCall map[int]struct {
+		result1 *facade.ServicePlanSchemas
+		result2 error
+	}
+	ListBindingsStub        func(context.Context) ([]*facade.Binding, error)
+	listBindingsMutex       sync.RWMutex
+	listBindingsArgsForCall []struct {
+		arg1 context.Context
+	}
+	listBindingsReturns struct {
+		result1 []*facade.Binding
+		result2 error
+	}
+	listBindingsReturnsOnCall map[int]struct {
+		result1 []*facade.Binding
+		result2 error
+	}
+	ListInstancesStub        func(context.Context) ([]*facade.Instance, error)
+	listInstancesMutex       sync.RWMutex
+	listInstancesArgsForCall []struct {
+		arg1 context.Context
+	}
+	listInstancesReturns struct {
+		result1 []*facade.Instance
+		result2 error
+	}
+	listInstancesReturnsOnCall map[int]struct {
+		result1 []*facade.Instance
+		result2 error
+	}
+	ListServicePlansStub        func(context.Context, string) ([]facade.ServicePlan, error)
+	listServicePlansMutex       sync.RWMutex
+	listServicePlansArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	listServicePlansReturns struct {
+		result1 []facade.ServicePlan
+		result2 error
+	}
+	listServicePlansReturnsOnCall map[int]struct {
+		result1 []facade.ServicePlan
+		result2 error
+	}
+	PromoteBindingStub        func(context.Context, string, facade.OwnerRef) error
+	promoteBindingMutex       sync.RWMutex
+	promoteBindingArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 facade.OwnerRef
+	}
+	promoteBindingReturns struct {
+		result1 error
+	}
+	promoteBindingReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RefreshCacheStub        func(context.Context) error
+	refreshCacheMutex       sync.RWMutex
+	refreshCacheArgsForCall []struct {
+		arg1 context.Context
+	}
+	refreshCacheReturns struct {
+		result1 error
+	}
+	refreshCacheReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateBindingStub        func(context.Context, string, facade.OwnerRef, int64, map[string]interface{}, *facade.Metadata) error
 	updateBindingMutex       sync.RWMutex
 	updateBindingArgsForCall []struct {
 		arg1 context.Context
 		arg2 string
-		arg3 int64
-		arg4 map[string]interface{}
+		arg3 facade.OwnerRef
+		arg4 int64
+		arg5 map[string]interface{}
+		arg6 *facade.Metadata
 	}
 	updateBindingReturns struct {
 		result1 error
@@ -128,16 +372,18 @@ type FakeSpaceClient struct {
 	updateBindingReturnsOnCall map[int]struct {
 		result1 error
 	}
-	UpdateInstanceStub        func(context.Context, string, string, string, map[string]interface{}, []string, int64) error
+	UpdateInstanceStub        func(context.Context, string, facade.OwnerRef, string, string, map[string]interface{}, []string, *facade.Metadata, int64) error
 	updateInstanceMutex       sync.RWMutex
 	updateInstanceArgsForCall []struct {
 		arg1 context.Context
 		arg2 string
-		arg3 string
+		arg3 facade.OwnerRef
 		arg4 string
-		arg5 map[string]interface{}
-		arg6 []string
-		arg7 int64
+		arg5 string
+		arg6 map[string]interface{}
+		arg7 []string
+		arg8 *facade.Metadata
+		arg9 int64
 	}
 	updateInstanceReturns struct {
 		result1 error
@@ -145,32 +391,74 @@ type FakeSpaceClient struct {
 	updateInstanceReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UpdateRouteStub        func(context.Context, string, int64) error
+	updateRouteMutex       sync.RWMutex
+	updateRouteArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int64
+	}
+	updateRouteReturns struct {
+		result1 error
+	}
+	updateRouteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateRouteBindingStub        func(context.Context, string, int64) error
+	updateRouteBindingMutex       sync.RWMutex
+	updateRouteBindingArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int64
+	}
+	updateRouteBindingReturns struct {
+		result1 error
+	}
+	updateRouteBindingReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpgradeInstanceStub        func(context.Context, string, facade.OwnerRef, facade.MaintenanceInfo) error
+	upgradeInstanceMutex       sync.RWMutex
+	upgradeInstanceArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 facade.OwnerRef
+		arg4 facade.MaintenanceInfo
+	}
+	upgradeInstanceReturns struct {
+		result1 error
+	}
+	upgradeInstanceReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeSpaceClient) CreateBinding(arg1 context.Context, arg2 string, arg3 string, arg4 map[string]interface{}, arg5 string, arg6 int64) error {
+func (fake *FakeSpaceClient) CreateBinding(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 map[string]interface{}, arg6 *facade.Metadata, arg7 facade.OwnerRef, arg8 int64) (*facade.Binding, error) {
 	fake.createBindingMutex.Lock()
 	ret, specificReturn := fake.createBindingReturnsOnCall[len(fake.createBindingArgsForCall)]
 	fake.createBindingArgsForCall = append(fake.createBindingArgsForCall, struct {
 		arg1 context.Context
 		arg2 string
 		arg3 string
-		arg4 map[string]interface{}
-		arg5 string
-		arg6 int64
-	}{arg1, arg2, arg3, arg4, arg5, arg6})
+		arg4 string
+		arg5 map[string]interface{}
+		arg6 *facade.Metadata
+		arg7 facade.OwnerRef
+		arg8 int64
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
 	stub := fake.CreateBindingStub
 	fakeReturns := fake.createBindingReturns
-	fake.recordInvocation("CreateBinding", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.recordInvocation("CreateBinding", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
 	fake.createBindingMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeSpaceClient) CreateBindingCallCount() int {
@@ -179,43 +467,46 @@ func (fake *FakeSpaceClient) CreateBindingCallCount() int {
 	return len(fake.createBindingArgsForCall)
 }
 
-func (fake *FakeSpaceClient) CreateBindingCalls(stub func(context.Context, string, string, map[string]interface{}, string, int64) error) {
+func (fake *FakeSpaceClient) CreateBindingCalls(stub func(context.Context, string, string, string, map[string]interface{}, *facade.Metadata, facade.OwnerRef, int64) (*facade.Binding, error)) {
 	fake.createBindingMutex.Lock()
 	defer fake.createBindingMutex.Unlock()
 	fake.CreateBindingStub = stub
 }
 
-func (fake *FakeSpaceClient) CreateBindingArgsForCall(i int) (context.Context, string, string, map[string]interface{}, string, int64) {
+func (fake *FakeSpaceClient) CreateBindingArgsForCall(i int) (context.Context, string, string, string, map[string]interface{}, *facade.Metadata, facade.OwnerRef, int64) {
 	fake.createBindingMutex.RLock()
 	defer fake.createBindingMutex.RUnlock()
 	argsForCall := fake.createBindingArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8
 }
 
-func (fake *FakeSpaceClient) CreateBindingReturns(result1 error) {
+func (fake *FakeSpaceClient) CreateBindingReturns(result1 *facade.Binding, result2 error) {
 	fake.createBindingMutex.Lock()
 	defer fake.createBindingMutex.Unlock()
 	fake.CreateBindingStub = nil
 	fake.createBindingReturns = struct {
-		result1 error
-	}{result1}
+		result1 *facade.Binding
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeSpaceClient) CreateBindingReturnsOnCall(i int, result1 error) {
+func (fake *FakeSpaceClient) CreateBindingReturnsOnCall(i int, result1 *facade.Binding, result2 error) {
 	fake.createBindingMutex.Lock()
 	defer fake.createBindingMutex.Unlock()
 	fake.CreateBindingStub = nil
 	if fake.createBindingReturnsOnCall == nil {
 		fake.createBindingReturnsOnCall = make(map[int]struct {
-			result1 error
+			result1 *facade.Binding
+			result2 error
 		})
 	}
 	fake.createBindingReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+		result1 *facade.Binding
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeSpaceClient) CreateInstance(arg1 context.Context, arg2 string, arg3 string, arg4 map[string]interface{}, arg5 []string, arg6 string, arg7 int64) error {
+func (fake *FakeSpaceClient) CreateInstance(arg1 context.Context, arg2 string, arg3 string, arg4 map[string]interface{}, arg5 []string, arg6 *facade.Metadata, arg7 facade.OwnerRef, arg8 int64) (*facade.Instance, error) {
 	var arg5Copy []string
 	if arg5 != nil {
 		arg5Copy = make([]string, len(arg5))
@@ -229,20 +520,21 @@ func (fake *FakeSpaceClient) CreateInstance(arg1 context.Context, arg2 string, a
 		arg3 string
 		arg4 map[string]interface{}
 		arg5 []string
-		arg6 string
-		arg7 int64
-	}{arg1, arg2, arg3, arg4, arg5Copy, arg6, arg7})
+		arg6 *facade.Metadata
+		arg7 facade.OwnerRef
+		arg8 int64
+	}{arg1, arg2, arg3, arg4, arg5Copy, arg6, arg7, arg8})
 	stub := fake.CreateInstanceStub
 	fakeReturns := fake.createInstanceReturns
-	fake.recordInvocation("CreateInstance", []interface{}{arg1, arg2, arg3, arg4, arg5Copy, arg6, arg7})
+	fake.recordInvocation("CreateInstance", []interface{}{arg1, arg2, arg3, arg4, arg5Copy, arg6, arg7, arg8})
 	fake.createInstanceMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeSpaceClient) CreateInstanceCallCount() int {
@@ -251,55 +543,62 @@ func (fake *FakeSpaceClient) CreateInstanceCallCount() int {
 	return len(fake.createInstanceArgsForCall)
 }
 
-func (fake *FakeSpaceClient) CreateInstanceCalls(stub func(context.Context, string, string, map[string]interface{}, []string, string, int64) error) {
+func (fake *FakeSpaceClient) CreateInstanceCalls(stub func(context.Context, string, string, map[string]interface{}, []string, *facade.Metadata, facade.OwnerRef, int64) (*facade.Instance, error)) {
 	fake.createInstanceMutex.Lock()
 	defer fake.createInstanceMutex.Unlock()
 	fake.CreateInstanceStub = stub
 }
 
-func (fake *FakeSpaceClient) CreateInstanceArgsForCall(i int) (context.Context, string, string, map[string]interface{}, []string, string, int64) {
+func (fake *FakeSpaceClient) CreateInstanceArgsForCall(i int) (context.Context, string, string, map[string]interface{}, []string, *facade.Metadata, facade.OwnerRef, int64) {
 	fake.createInstanceMutex.RLock()
 	defer fake.createInstanceMutex.RUnlock()
 	argsForCall := fake.createInstanceArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8
 }
 
-func (fake *FakeSpaceClient) CreateInstanceReturns(result1 error) {
+func (fake *FakeSpaceClient) CreateInstanceReturns(result1 *facade.Instance, result2 error) {
 	fake.createInstanceMutex.Lock()
 	defer fake.createInstanceMutex.Unlock()
 	fake.CreateInstanceStub = nil
 	fake.createInstanceReturns = struct {
-		result1 error
-	}{result1}
+		result1 *facade.Instance
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeSpaceClient) CreateInstanceReturnsOnCall(i int, result1 error) {
+func (fake *FakeSpaceClient) CreateInstanceReturnsOnCall(i int, result1 *facade.Instance, result2 error) {
 	fake.createInstanceMutex.Lock()
 	defer fake.createInstanceMutex.Unlock()
 	fake.CreateInstanceStub = nil
 	if fake.createInstanceReturnsOnCall == nil {
 		fake.createInstanceReturnsOnCall = make(map[int]struct {
-			result1 error
+			result1 *facade.Instance
+			result2 error
 		})
 	}
 	fake.createInstanceReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+		result1 *facade.Instance
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeSpaceClient) DeleteBinding(arg1 context.Context, arg2 string) error {
-	fake.deleteBindingMutex.Lock()
-	ret, specificReturn := fake.deleteBindingReturnsOnCall[len(fake.deleteBindingArgsForCall)]
-	fake.deleteBindingArgsForCall = append(fake.deleteBindingArgsForCall, struct {
+func (fake *FakeSpaceClient) CreateRoute(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 string, arg6 int64) error {
+	fake.createRouteMutex.Lock()
+	ret, specificReturn := fake.createRouteReturnsOnCall[len(fake.createRouteArgsForCall)]
+	fake.createRouteArgsForCall = append(fake.createRouteArgsForCall, struct {
 		arg1 context.Context
 		arg2 string
-	}{arg1, arg2})
-	stub := fake.DeleteBindingStub
-	fakeReturns := fake.deleteBindingReturns
-	fake.recordInvocation("DeleteBinding", []interface{}{arg1, arg2})
-	fake.deleteBindingMutex.Unlock()
+		arg3 string
+		arg4 string
+		arg5 string
+		arg6 int64
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
+	stub := fake.CreateRouteStub
+	fakeReturns := fake.createRouteReturns
+	fake.recordInvocation("CreateRoute", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.createRouteMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
 	}
 	if specificReturn {
 		return ret.result1
@@ -307,61 +606,191 @@ func (fake *FakeSpaceClient) DeleteBinding(arg1 context.Context, arg2 string) er
 	return fakeReturns.result1
 }
 
-func (fake *FakeSpaceClient) DeleteBindingCallCount() int {
-	fake.deleteBindingMutex.RLock()
-	defer fake.deleteBindingMutex.RUnlock()
-	return len(fake.deleteBindingArgsForCall)
+func (fake *FakeSpaceClient) CreateRouteCallCount() int {
+	fake.createRouteMutex.RLock()
+	defer fake.createRouteMutex.RUnlock()
+	return len(fake.createRouteArgsForCall)
 }
 
-func (fake *FakeSpaceClient) DeleteBindingCalls(stub func(context.Context, string) error) {
-	fake.deleteBindingMutex.Lock()
-	defer fake.deleteBindingMutex.Unlock()
-	fake.DeleteBindingStub = stub
+func (fake *FakeSpaceClient) CreateRouteCalls(stub func(context.Context, string, string, string, string, int64) error) {
+	fake.createRouteMutex.Lock()
+	defer fake.createRouteMutex.Unlock()
+	fake.CreateRouteStub = stub
 }
 
-func (fake *FakeSpaceClient) DeleteBindingArgsForCall(i int) (context.Context, string) {
-	fake.deleteBindingMutex.RLock()
-	defer fake.deleteBindingMutex.RUnlock()
-	argsForCall := fake.deleteBindingArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+func (fake *FakeSpaceClient) CreateRouteArgsForCall(i int) (context.Context, string, string, string, string, int64) {
+	fake.createRouteMutex.RLock()
+	defer fake.createRouteMutex.RUnlock()
+	argsForCall := fake.createRouteArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
 }
 
-func (fake *FakeSpaceClient) DeleteBindingReturns(result1 error) {
-	fake.deleteBindingMutex.Lock()
-	defer fake.deleteBindingMutex.Unlock()
-	fake.DeleteBindingStub = nil
-	fake.deleteBindingReturns = struct {
+func (fake *FakeSpaceClient) CreateRouteReturns(result1 error) {
+	fake.createRouteMutex.Lock()
+	defer fake.createRouteMutex.Unlock()
+	fake.CreateRouteStub = nil
+	fake.createRouteReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeSpaceClient) DeleteBindingReturnsOnCall(i int, result1 error) {
-	fake.deleteBindingMutex.Lock()
-	defer fake.deleteBindingMutex.Unlock()
-	fake.DeleteBindingStub = nil
-	if fake.deleteBindingReturnsOnCall == nil {
-		fake.deleteBindingReturnsOnCall = make(map[int]struct {
+func (fake *FakeSpaceClient) CreateRouteReturnsOnCall(i int, result1 error) {
+	fake.createRouteMutex.Lock()
+	defer fake.createRouteMutex.Unlock()
+	fake.CreateRouteStub = nil
+	if fake.createRouteReturnsOnCall == nil {
+		fake.createRouteReturnsOnCall = make(map[int]struct {
 			result1 error
 		})
 	}
-	fake.deleteBindingReturnsOnCall[i] = struct {
+	fake.createRouteReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeSpaceClient) DeleteInstance(arg1 context.Context, arg2 string) error {
-	fake.deleteInstanceMutex.Lock()
-	ret, specificReturn := fake.deleteInstanceReturnsOnCall[len(fake.deleteInstanceArgsForCall)]
-	fake.deleteInstanceArgsForCall = append(fake.deleteInstanceArgsForCall, struct {
+func (fake *FakeSpaceClient) CreateRouteBinding(arg1 context.Context, arg2 string, arg3 string, arg4 map[string]interface{}, arg5 string, arg6 int64) error {
+	fake.createRouteBindingMutex.Lock()
+	ret, specificReturn := fake.createRouteBindingReturnsOnCall[len(fake.createRouteBindingArgsForCall)]
+	fake.createRouteBindingArgsForCall = append(fake.createRouteBindingArgsForCall, struct {
 		arg1 context.Context
 		arg2 string
-	}{arg1, arg2})
+		arg3 string
+		arg4 map[string]interface{}
+		arg5 string
+		arg6 int64
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
+	stub := fake.CreateRouteBindingStub
+	fakeReturns := fake.createRouteBindingReturns
+	fake.recordInvocation("CreateRouteBinding", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.createRouteBindingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeSpaceClient) CreateRouteBindingCallCount() int {
+	fake.createRouteBindingMutex.RLock()
+	defer fake.createRouteBindingMutex.RUnlock()
+	return len(fake.createRouteBindingArgsForCall)
+}
+
+func (fake *FakeSpaceClient) CreateRouteBindingCalls(stub func(context.Context, string, string, map[string]interface{}, string, int64) error) {
+	fake.createRouteBindingMutex.Lock()
+	defer fake.createRouteBindingMutex.Unlock()
+	fake.CreateRouteBindingStub = stub
+}
+
+func (fake *FakeSpaceClient) CreateRouteBindingArgsForCall(i int) (context.Context, string, string, map[string]interface{}, string, int64) {
+	fake.createRouteBindingMutex.RLock()
+	defer fake.createRouteBindingMutex.RUnlock()
+	argsForCall := fake.createRouteBindingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+}
+
+func (fake *FakeSpaceClient) CreateRouteBindingReturns(result1 error) {
+	fake.createRouteBindingMutex.Lock()
+	defer fake.createRouteBindingMutex.Unlock()
+	fake.CreateRouteBindingStub = nil
+	fake.createRouteBindingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceClient) CreateRouteBindingReturnsOnCall(i int, result1 error) {
+	fake.createRouteBindingMutex.Lock()
+	defer fake.createRouteBindingMutex.Unlock()
+	fake.CreateRouteBindingStub = nil
+	if fake.createRouteBindingReturnsOnCall == nil {
+		fake.createRouteBindingReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createRouteBindingReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceClient) DeleteBinding(arg1 context.Context, arg2 string, arg3 facade.OwnerRef) error {
+	fake.deleteBindingMutex.Lock()
+	ret, specificReturn := fake.deleteBindingReturnsOnCall[len(fake.deleteBindingArgsForCall)]
+	fake.deleteBindingArgsForCall = append(fake.deleteBindingArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 facade.OwnerRef
+	}{arg1, arg2, arg3})
+	stub := fake.DeleteBindingStub
+	fakeReturns := fake.deleteBindingReturns
+	fake.recordInvocation("DeleteBinding", []interface{}{arg1, arg2, arg3})
+	fake.deleteBindingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeSpaceClient) DeleteBindingCallCount() int {
+	fake.deleteBindingMutex.RLock()
+	defer fake.deleteBindingMutex.RUnlock()
+	return len(fake.deleteBindingArgsForCall)
+}
+
+func (fake *FakeSpaceClient) DeleteBindingCalls(stub func(context.Context, string, facade.OwnerRef) error) {
+	fake.deleteBindingMutex.Lock()
+	defer fake.deleteBindingMutex.Unlock()
+	fake.DeleteBindingStub = stub
+}
+
+func (fake *FakeSpaceClient) DeleteBindingArgsForCall(i int) (context.Context, string, facade.OwnerRef) {
+	fake.deleteBindingMutex.RLock()
+	defer fake.deleteBindingMutex.RUnlock()
+	argsForCall := fake.deleteBindingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeSpaceClient) DeleteBindingReturns(result1 error) {
+	fake.deleteBindingMutex.Lock()
+	defer fake.deleteBindingMutex.Unlock()
+	fake.DeleteBindingStub = nil
+	fake.deleteBindingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceClient) DeleteBindingReturnsOnCall(i int, result1 error) {
+	fake.deleteBindingMutex.Lock()
+	defer fake.deleteBindingMutex.Unlock()
+	fake.DeleteBindingStub = nil
+	if fake.deleteBindingReturnsOnCall == nil {
+		fake.deleteBindingReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteBindingReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceClient) DeleteInstance(arg1 context.Context, arg2 string, arg3 facade.OwnerRef) error {
+	fake.deleteInstanceMutex.Lock()
+	ret, specificReturn := fake.deleteInstanceReturnsOnCall[len(fake.deleteInstanceArgsForCall)]
+	fake.deleteInstanceArgsForCall = append(fake.deleteInstanceArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 facade.OwnerRef
+	}{arg1, arg2, arg3})
 	stub := fake.DeleteInstanceStub
 	fakeReturns := fake.deleteInstanceReturns
-	fake.recordInvocation("DeleteInstance", []interface{}{arg1, arg2})
+	fake.recordInvocation("DeleteInstance", []interface{}{arg1, arg2, arg3})
 	fake.deleteInstanceMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -375,17 +804,17 @@ func (fake *FakeSpaceClient) DeleteInstanceCallCount() int {
 	return len(fake.deleteInstanceArgsForCall)
 }
 
-func (fake *FakeSpaceClient) DeleteInstanceCalls(stub func(context.Context, string) error) {
+func (fake *FakeSpaceClient) DeleteInstanceCalls(stub func(context.Context, string, facade.OwnerRef) error) {
 	fake.deleteInstanceMutex.Lock()
 	defer fake.deleteInstanceMutex.Unlock()
 	fake.DeleteInstanceStub = stub
 }
 
-func (fake *FakeSpaceClient) DeleteInstanceArgsForCall(i int) (context.Context, string) {
+func (fake *FakeSpaceClient) DeleteInstanceArgsForCall(i int) (context.Context, string, facade.OwnerRef) {
 	fake.deleteInstanceMutex.RLock()
 	defer fake.deleteInstanceMutex.RUnlock()
 	argsForCall := fake.deleteInstanceArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeSpaceClient) DeleteInstanceReturns(result1 error) {
@@ -411,6 +840,325 @@ func (fake *FakeSpaceClient) DeleteInstanceReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeSpaceClient) DeleteRoute(arg1 context.Context, arg2 string) error {
+	fake.deleteRouteMutex.Lock()
+	ret, specificReturn := fake.deleteRouteReturnsOnCall[len(fake.deleteRouteArgsForCall)]
+	fake.deleteRouteArgsForCall = append(fake.deleteRouteArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DeleteRouteStub
+	fakeReturns := fake.deleteRouteReturns
+	fake.recordInvocation("DeleteRoute", []interface{}{arg1, arg2})
+	fake.deleteRouteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeSpaceClient) DeleteRouteCallCount() int {
+	fake.deleteRouteMutex.RLock()
+	defer fake.deleteRouteMutex.RUnlock()
+	return len(fake.deleteRouteArgsForCall)
+}
+
+func (fake *FakeSpaceClient) DeleteRouteCalls(stub func(context.Context, string) error) {
+	fake.deleteRouteMutex.Lock()
+	defer fake.deleteRouteMutex.Unlock()
+	fake.DeleteRouteStub = stub
+}
+
+func (fake *FakeSpaceClient) DeleteRouteArgsForCall(i int) (context.Context, string) {
+	fake.deleteRouteMutex.RLock()
+	defer fake.deleteRouteMutex.RUnlock()
+	argsForCall := fake.deleteRouteArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSpaceClient) DeleteRouteReturns(result1 error) {
+	fake.deleteRouteMutex.Lock()
+	defer fake.deleteRouteMutex.Unlock()
+	fake.DeleteRouteStub = nil
+	fake.deleteRouteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceClient) DeleteRouteReturnsOnCall(i int, result1 error) {
+	fake.deleteRouteMutex.Lock()
+	defer fake.deleteRouteMutex.Unlock()
+	fake.DeleteRouteStub = nil
+	if fake.deleteRouteReturnsOnCall == nil {
+		fake.deleteRouteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteRouteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceClient) DeleteRouteBinding(arg1 context.Context, arg2 string) error {
+	fake.deleteRouteBindingMutex.Lock()
+	ret, specificReturn := fake.deleteRouteBindingReturnsOnCall[len(fake.deleteRouteBindingArgsForCall)]
+	fake.deleteRouteBindingArgsForCall = append(fake.deleteRouteBindingArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DeleteRouteBindingStub
+	fakeReturns := fake.deleteRouteBindingReturns
+	fake.recordInvocation("DeleteRouteBinding", []interface{}{arg1, arg2})
+	fake.deleteRouteBindingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeSpaceClient) DeleteRouteBindingCallCount() int {
+	fake.deleteRouteBindingMutex.RLock()
+	defer fake.deleteRouteBindingMutex.RUnlock()
+	return len(fake.deleteRouteBindingArgsForCall)
+}
+
+func (fake *FakeSpaceClient) DeleteRouteBindingCalls(stub func(context.Context, string) error) {
+	fake.deleteRouteBindingMutex.Lock()
+	defer fake.deleteRouteBindingMutex.Unlock()
+	fake.DeleteRouteBindingStub = stub
+}
+
+func (fake *FakeSpaceClient) DeleteRouteBindingArgsForCall(i int) (context.Context, string) {
+	fake.deleteRouteBindingMutex.RLock()
+	defer fake.deleteRouteBindingMutex.RUnlock()
+	argsForCall := fake.deleteRouteBindingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSpaceClient) DeleteRouteBindingReturns(result1 error) {
+	fake.deleteRouteBindingMutex.Lock()
+	defer fake.deleteRouteBindingMutex.Unlock()
+	fake.DeleteRouteBindingStub = nil
+	fake.deleteRouteBindingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceClient) DeleteRouteBindingReturnsOnCall(i int, result1 error) {
+	fake.deleteRouteBindingMutex.Lock()
+	defer fake.deleteRouteBindingMutex.Unlock()
+	fake.DeleteRouteBindingStub = nil
+	if fake.deleteRouteBindingReturnsOnCall == nil {
+		fake.deleteRouteBindingReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteRouteBindingReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceClient) FindApp(arg1 context.Context, arg2 string) (string, error) {
+	fake.findAppMutex.Lock()
+	ret, specificReturn := fake.findAppReturnsOnCall[len(fake.findAppArgsForCall)]
+	fake.findAppArgsForCall = append(fake.findAppArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.FindAppStub
+	fakeReturns := fake.findAppReturns
+	fake.recordInvocation("FindApp", []interface{}{arg1, arg2})
+	fake.findAppMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSpaceClient) FindAppCallCount() int {
+	fake.findAppMutex.RLock()
+	defer fake.findAppMutex.RUnlock()
+	return len(fake.findAppArgsForCall)
+}
+
+func (fake *FakeSpaceClient) FindAppCalls(stub func(context.Context, string) (string, error)) {
+	fake.findAppMutex.Lock()
+	defer fake.findAppMutex.Unlock()
+	fake.FindAppStub = stub
+}
+
+func (fake *FakeSpaceClient) FindAppArgsForCall(i int) (context.Context, string) {
+	fake.findAppMutex.RLock()
+	defer fake.findAppMutex.RUnlock()
+	argsForCall := fake.findAppArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSpaceClient) FindAppReturns(result1 string, result2 error) {
+	fake.findAppMutex.Lock()
+	defer fake.findAppMutex.Unlock()
+	fake.FindAppStub = nil
+	fake.findAppReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) FindAppReturnsOnCall(i int, result1 string, result2 error) {
+	fake.findAppMutex.Lock()
+	defer fake.findAppMutex.Unlock()
+	fake.FindAppStub = nil
+	if fake.findAppReturnsOnCall == nil {
+		fake.findAppReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.findAppReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) FindDomain(arg1 context.Context, arg2 string) (string, error) {
+	fake.findDomainMutex.Lock()
+	ret, specificReturn := fake.findDomainReturnsOnCall[len(fake.findDomainArgsForCall)]
+	fake.findDomainArgsForCall = append(fake.findDomainArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.FindDomainStub
+	fakeReturns := fake.findDomainReturns
+	fake.recordInvocation("FindDomain", []interface{}{arg1, arg2})
+	fake.findDomainMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSpaceClient) FindDomainCallCount() int {
+	fake.findDomainMutex.RLock()
+	defer fake.findDomainMutex.RUnlock()
+	return len(fake.findDomainArgsForCall)
+}
+
+func (fake *FakeSpaceClient) FindDomainCalls(stub func(context.Context, string) (string, error)) {
+	fake.findDomainMutex.Lock()
+	defer fake.findDomainMutex.Unlock()
+	fake.FindDomainStub = stub
+}
+
+func (fake *FakeSpaceClient) FindDomainArgsForCall(i int) (context.Context, string) {
+	fake.findDomainMutex.RLock()
+	defer fake.findDomainMutex.RUnlock()
+	argsForCall := fake.findDomainArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSpaceClient) FindDomainReturns(result1 string, result2 error) {
+	fake.findDomainMutex.Lock()
+	defer fake.findDomainMutex.Unlock()
+	fake.FindDomainStub = nil
+	fake.findDomainReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) FindDomainReturnsOnCall(i int, result1 string, result2 error) {
+	fake.findDomainMutex.Lock()
+	defer fake.findDomainMutex.Unlock()
+	fake.FindDomainStub = nil
+	if fake.findDomainReturnsOnCall == nil {
+		fake.findDomainReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.findDomainReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) FindInstanceName(arg1 context.Context, arg2 string) (*facade.InstanceName, error) {
+	fake.findInstanceNameMutex.Lock()
+	ret, specificReturn := fake.findInstanceNameReturnsOnCall[len(fake.findInstanceNameArgsForCall)]
+	fake.findInstanceNameArgsForCall = append(fake.findInstanceNameArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.FindInstanceNameStub
+	fakeReturns := fake.findInstanceNameReturns
+	fake.recordInvocation("FindInstanceName", []interface{}{arg1, arg2})
+	fake.findInstanceNameMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSpaceClient) FindInstanceNameCallCount() int {
+	fake.findInstanceNameMutex.RLock()
+	defer fake.findInstanceNameMutex.RUnlock()
+	return len(fake.findInstanceNameArgsForCall)
+}
+
+func (fake *FakeSpaceClient) FindInstanceNameCalls(stub func(context.Context, string) (*facade.InstanceName, error)) {
+	fake.findInstanceNameMutex.Lock()
+	defer fake.findInstanceNameMutex.Unlock()
+	fake.FindInstanceNameStub = stub
+}
+
+func (fake *FakeSpaceClient) FindInstanceNameArgsForCall(i int) (context.Context, string) {
+	fake.findInstanceNameMutex.RLock()
+	defer fake.findInstanceNameMutex.RUnlock()
+	argsForCall := fake.findInstanceNameArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSpaceClient) FindInstanceNameReturns(result1 *facade.InstanceName, result2 error) {
+	fake.findInstanceNameMutex.Lock()
+	defer fake.findInstanceNameMutex.Unlock()
+	fake.FindInstanceNameStub = nil
+	fake.findInstanceNameReturns = struct {
+		result1 *facade.InstanceName
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) FindInstanceNameReturnsOnCall(i int, result1 *facade.InstanceName, result2 error) {
+	fake.findInstanceNameMutex.Lock()
+	defer fake.findInstanceNameMutex.Unlock()
+	fake.FindInstanceNameStub = nil
+	if fake.findInstanceNameReturnsOnCall == nil {
+		fake.findInstanceNameReturnsOnCall = make(map[int]struct {
+			result1 *facade.InstanceName
+			result2 error
+		})
+	}
+	fake.findInstanceNameReturnsOnCall[i] = struct {
+		result1 *facade.InstanceName
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeSpaceClient) FindServicePlan(arg1 context.Context, arg2 string, arg3 string, arg4 string) (string, error) {
 	fake.findServicePlanMutex.Lock()
 	ret, specificReturn := fake.findServicePlanReturnsOnCall[len(fake.findServicePlanArgsForCall)]
@@ -425,7 +1173,527 @@ func (fake *FakeSpaceClient) FindServicePlan(arg1 context.Context, arg2 string,
 	fake.recordInvocation("FindServicePlan", []interface{}{arg1, arg2, arg3, arg4})
 	fake.findServicePlanMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4)
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSpaceClient) FindServicePlanCallCount() int {
+	fake.findServicePlanMutex.RLock()
+	defer fake.findServicePlanMutex.RUnlock()
+	return len(fake.findServicePlanArgsForCall)
+}
+
+func (fake *FakeSpaceClient) FindServicePlanCalls(stub func(context.Context, string, string, string) (string, error)) {
+	fake.findServicePlanMutex.Lock()
+	defer fake.findServicePlanMutex.Unlock()
+	fake.FindServicePlanStub = stub
+}
+
+func (fake *FakeSpaceClient) FindServicePlanArgsForCall(i int) (context.Context, string, string, string) {
+	fake.findServicePlanMutex.RLock()
+	defer fake.findServicePlanMutex.RUnlock()
+	argsForCall := fake.findServicePlanArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeSpaceClient) FindServicePlanReturns(result1 string, result2 error) {
+	fake.findServicePlanMutex.Lock()
+	defer fake.findServicePlanMutex.Unlock()
+	fake.FindServicePlanStub = nil
+	fake.findServicePlanReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) FindServicePlanReturnsOnCall(i int, result1 string, result2 error) {
+	fake.findServicePlanMutex.Lock()
+	defer fake.findServicePlanMutex.Unlock()
+	fake.FindServicePlanStub = nil
+	if fake.findServicePlanReturnsOnCall == nil {
+		fake.findServicePlanReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.findServicePlanReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) GetBinding(arg1 context.Context, arg2 map[string]string) (*facade.Binding, error) {
+	fake.getBindingMutex.Lock()
+	ret, specificReturn := fake.getBindingReturnsOnCall[len(fake.getBindingArgsForCall)]
+	fake.getBindingArgsForCall = append(fake.getBindingArgsForCall, struct {
+		arg1 context.Context
+		arg2 map[string]string
+	}{arg1, arg2})
+	stub := fake.GetBindingStub
+	fakeReturns := fake.getBindingReturns
+	fake.recordInvocation("GetBinding", []interface{}{arg1, arg2})
+	fake.getBindingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSpaceClient) GetBindingCallCount() int {
+	fake.getBindingMutex.RLock()
+	defer fake.getBindingMutex.RUnlock()
+	return len(fake.getBindingArgsForCall)
+}
+
+func (fake *FakeSpaceClient) GetBindingCalls(stub func(context.Context, map[string]string) (*facade.Binding, error)) {
+	fake.getBindingMutex.Lock()
+	defer fake.getBindingMutex.Unlock()
+	fake.GetBindingStub = stub
+}
+
+func (fake *FakeSpaceClient) GetBindingArgsForCall(i int) (context.Context, map[string]string) {
+	fake.getBindingMutex.RLock()
+	defer fake.getBindingMutex.RUnlock()
+	argsForCall := fake.getBindingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSpaceClient) GetBindingReturns(result1 *facade.Binding, result2 error) {
+	fake.getBindingMutex.Lock()
+	defer fake.getBindingMutex.Unlock()
+	fake.GetBindingStub = nil
+	fake.getBindingReturns = struct {
+		result1 *facade.Binding
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) GetBindingReturnsOnCall(i int, result1 *facade.Binding, result2 error) {
+	fake.getBindingMutex.Lock()
+	defer fake.getBindingMutex.Unlock()
+	fake.GetBindingStub = nil
+	if fake.getBindingReturnsOnCall == nil {
+		fake.getBindingReturnsOnCall = make(map[int]struct {
+			result1 *facade.Binding
+			result2 error
+		})
+	}
+	fake.getBindingReturnsOnCall[i] = struct {
+		result1 *facade.Binding
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) GetBindingCredentials(arg1 context.Context, arg2 string) (map[string]interface{}, error) {
+	fake.getBindingCredentialsMutex.Lock()
+	ret, specificReturn := fake.getBindingCredentialsReturnsOnCall[len(fake.getBindingCredentialsArgsForCall)]
+	fake.getBindingCredentialsArgsForCall = append(fake.getBindingCredentialsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetBindingCredentialsStub
+	fakeReturns := fake.getBindingCredentialsReturns
+	fake.recordInvocation("GetBindingCredentials", []interface{}{arg1, arg2})
+	fake.getBindingCredentialsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSpaceClient) GetBindingCredentialsCallCount() int {
+	fake.getBindingCredentialsMutex.RLock()
+	defer fake.getBindingCredentialsMutex.RUnlock()
+	return len(fake.getBindingCredentialsArgsForCall)
+}
+
+func (fake *FakeSpaceClient) GetBindingCredentialsCalls(stub func(context.Context, string) (map[string]interface{}, error)) {
+	fake.getBindingCredentialsMutex.Lock()
+	defer fake.getBindingCredentialsMutex.Unlock()
+	fake.GetBindingCredentialsStub = stub
+}
+
+func (fake *FakeSpaceClient) GetBindingCredentialsArgsForCall(i int) (context.Context, string) {
+	fake.getBindingCredentialsMutex.RLock()
+	defer fake.getBindingCredentialsMutex.RUnlock()
+	argsForCall := fake.getBindingCredentialsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSpaceClient) GetBindingCredentialsReturns(result1 map[string]interface{}, result2 error) {
+	fake.getBindingCredentialsMutex.Lock()
+	defer fake.getBindingCredentialsMutex.Unlock()
+	fake.GetBindingCredentialsStub = nil
+	fake.getBindingCredentialsReturns = struct {
+		result1 map[string]interface{}
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) GetBindingCredentialsReturnsOnCall(i int, result1 map[string]interface{}, result2 error) {
+	fake.getBindingCredentialsMutex.Lock()
+	defer fake.getBindingCredentialsMutex.Unlock()
+	fake.GetBindingCredentialsStub = nil
+	if fake.getBindingCredentialsReturnsOnCall == nil {
+		fake.getBindingCredentialsReturnsOnCall = make(map[int]struct {
+			result1 map[string]interface{}
+			result2 error
+		})
+	}
+	fake.getBindingCredentialsReturnsOnCall[i] = struct {
+		result1 map[string]interface{}
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) GetInstance(arg1 context.Context, arg2 map[string]string) (*facade.Instance, error) {
+	fake.getInstanceMutex.Lock()
+	ret, specificReturn := fake.getInstanceReturnsOnCall[len(fake.getInstanceArgsForCall)]
+	fake.getInstanceArgsForCall = append(fake.getInstanceArgsForCall, struct {
+		arg1 context.Context
+		arg2 map[string]string
+	}{arg1, arg2})
+	stub := fake.GetInstanceStub
+	fakeReturns := fake.getInstanceReturns
+	fake.recordInvocation("GetInstance", []interface{}{arg1, arg2})
+	fake.getInstanceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSpaceClient) GetInstanceCallCount() int {
+	fake.getInstanceMutex.RLock()
+	defer fake.getInstanceMutex.RUnlock()
+	return len(fake.getInstanceArgsForCall)
+}
+
+func (fake *FakeSpaceClient) GetInstanceCalls(stub func(context.Context, map[string]string) (*facade.Instance, error)) {
+	fake.getInstanceMutex.Lock()
+	defer fake.getInstanceMutex.Unlock()
+	fake.GetInstanceStub = stub
+}
+
+func (fake *FakeSpaceClient) GetInstanceArgsForCall(i int) (context.Context, map[string]string) {
+	fake.getInstanceMutex.RLock()
+	defer fake.getInstanceMutex.RUnlock()
+	argsForCall := fake.getInstanceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSpaceClient) GetInstanceReturns(result1 *facade.Instance, result2 error) {
+	fake.getInstanceMutex.Lock()
+	defer fake.getInstanceMutex.Unlock()
+	fake.GetInstanceStub = nil
+	fake.getInstanceReturns = struct {
+		result1 *facade.Instance
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) GetInstanceReturnsOnCall(i int, result1 *facade.Instance, result2 error) {
+	fake.getInstanceMutex.Lock()
+	defer fake.getInstanceMutex.Unlock()
+	fake.GetInstanceStub = nil
+	if fake.getInstanceReturnsOnCall == nil {
+		fake.getInstanceReturnsOnCall = make(map[int]struct {
+			result1 *facade.Instance
+			result2 error
+		})
+	}
+	fake.getInstanceReturnsOnCall[i] = struct {
+		result1 *facade.Instance
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) GetInstanceParameters(arg1 context.Context, arg2 string) (map[string]interface{}, error) {
+	fake.getInstanceParametersMutex.Lock()
+	ret, specificReturn := fake.getInstanceParametersReturnsOnCall[len(fake.getInstanceParametersArgsForCall)]
+	fake.getInstanceParametersArgsForCall = append(fake.getInstanceParametersArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetInstanceParametersStub
+	fakeReturns := fake.getInstanceParametersReturns
+	fake.recordInvocation("GetInstanceParameters", []interface{}{arg1, arg2})
+	fake.getInstanceParametersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSpaceClient) GetInstanceParametersCallCount() int {
+	fake.getInstanceParametersMutex.RLock()
+	defer fake.getInstanceParametersMutex.RUnlock()
+	return len(fake.getInstanceParametersArgsForCall)
+}
+
+func (fake *FakeSpaceClient) GetInstanceParametersCalls(stub func(context.Context, string) (map[string]interface{}, error)) {
+	fake.getInstanceParametersMutex.Lock()
+	defer fake.getInstanceParametersMutex.Unlock()
+	fake.GetInstanceParametersStub = stub
+}
+
+func (fake *FakeSpaceClient) GetInstanceParametersArgsForCall(i int) (context.Context, string) {
+	fake.getInstanceParametersMutex.RLock()
+	defer fake.getInstanceParametersMutex.RUnlock()
+	argsForCall := fake.getInstanceParametersArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSpaceClient) GetInstanceParametersReturns(result1 map[string]interface{}, result2 error) {
+	fake.getInstanceParametersMutex.Lock()
+	defer fake.getInstanceParametersMutex.Unlock()
+	fake.GetInstanceParametersStub = nil
+	fake.getInstanceParametersReturns = struct {
+		result1 map[string]interface{}
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) GetInstanceParametersReturnsOnCall(i int, result1 map[string]interface{}, result2 error) {
+	fake.getInstanceParametersMutex.Lock()
+	defer fake.getInstanceParametersMutex.Unlock()
+	fake.GetInstanceParametersStub = nil
+	if fake.getInstanceParametersReturnsOnCall == nil {
+		fake.getInstanceParametersReturnsOnCall = make(map[int]struct {
+			result1 map[string]interface{}
+			result2 error
+		})
+	}
+	fake.getInstanceParametersReturnsOnCall[i] = struct {
+		result1 map[string]interface{}
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) GetRoute(arg1 context.Context, arg2 string) (*facade.Route, error) {
+	fake.getRouteMutex.Lock()
+	ret, specificReturn := fake.getRouteReturnsOnCall[len(fake.getRouteArgsForCall)]
+	fake.getRouteArgsForCall = append(fake.getRouteArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetRouteStub
+	fakeReturns := fake.getRouteReturns
+	fake.recordInvocation("GetRoute", []interface{}{arg1, arg2})
+	fake.getRouteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSpaceClient) GetRouteCallCount() int {
+	fake.getRouteMutex.RLock()
+	defer fake.getRouteMutex.RUnlock()
+	return len(fake.getRouteArgsForCall)
+}
+
+func (fake *FakeSpaceClient) GetRouteCalls(stub func(context.Context, string) (*facade.Route, error)) {
+	fake.getRouteMutex.Lock()
+	defer fake.getRouteMutex.Unlock()
+	fake.GetRouteStub = stub
+}
+
+func (fake *FakeSpaceClient) GetRouteArgsForCall(i int) (context.Context, string) {
+	fake.getRouteMutex.RLock()
+	defer fake.getRouteMutex.RUnlock()
+	argsForCall := fake.getRouteArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSpaceClient) GetRouteReturns(result1 *facade.Route, result2 error) {
+	fake.getRouteMutex.Lock()
+	defer fake.getRouteMutex.Unlock()
+	fake.GetRouteStub = nil
+	fake.getRouteReturns = struct {
+		result1 *facade.Route
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) GetRouteReturnsOnCall(i int, result1 *facade.Route, result2 error) {
+	fake.getRouteMutex.Lock()
+	defer fake.getRouteMutex.Unlock()
+	fake.GetRouteStub = nil
+	if fake.getRouteReturnsOnCall == nil {
+		fake.getRouteReturnsOnCall = make(map[int]struct {
+			result1 *facade.Route
+			result2 error
+		})
+	}
+	fake.getRouteReturnsOnCall[i] = struct {
+		result1 *facade.Route
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) GetRouteBinding(arg1 context.Context, arg2 string) (*facade.RouteBinding, error) {
+	fake.getRouteBindingMutex.Lock()
+	ret, specificReturn := fake.getRouteBindingReturnsOnCall[len(fake.getRouteBindingArgsForCall)]
+	fake.getRouteBindingArgsForCall = append(fake.getRouteBindingArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetRouteBindingStub
+	fakeReturns := fake.getRouteBindingReturns
+	fake.recordInvocation("GetRouteBinding", []interface{}{arg1, arg2})
+	fake.getRouteBindingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSpaceClient) GetRouteBindingCallCount() int {
+	fake.getRouteBindingMutex.RLock()
+	defer fake.getRouteBindingMutex.RUnlock()
+	return len(fake.getRouteBindingArgsForCall)
+}
+
+func (fake *FakeSpaceClient) GetRouteBindingCalls(stub func(context.Context, string) (*facade.RouteBinding, error)) {
+	fake.getRouteBindingMutex.Lock()
+	defer fake.getRouteBindingMutex.Unlock()
+	fake.GetRouteBindingStub = stub
+}
+
+func (fake *FakeSpaceClient) GetRouteBindingArgsForCall(i int) (context.Context, string) {
+	fake.getRouteBindingMutex.RLock()
+	defer fake.getRouteBindingMutex.RUnlock()
+	argsForCall := fake.getRouteBindingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSpaceClient) GetRouteBindingReturns(result1 *facade.RouteBinding, result2 error) {
+	fake.getRouteBindingMutex.Lock()
+	defer fake.getRouteBindingMutex.Unlock()
+	fake.GetRouteBindingStub = nil
+	fake.getRouteBindingReturns = struct {
+		result1 *facade.RouteBinding
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) GetRouteBindingReturnsOnCall(i int, result1 *facade.RouteBinding, result2 error) {
+	fake.getRouteBindingMutex.Lock()
+	defer fake.getRouteBindingMutex.Unlock()
+	fake.GetRouteBindingStub = nil
+	if fake.getRouteBindingReturnsOnCall == nil {
+		fake.getRouteBindingReturnsOnCall = make(map[int]struct {
+			result1 *facade.RouteBinding
+			result2 error
+		})
+	}
+	fake.getRouteBindingReturnsOnCall[i] = struct {
+		result1 *facade.RouteBinding
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) GetServicePlanSchemas(arg1 context.Context, arg2 string, arg3 string) (*facade.ServicePlanSchemas, error) {
+	fake.getServicePlanSchemasMutex.Lock()
+	ret, specificReturn := fake.getServicePlanSchemasReturnsOnCall[len(fake.getServicePlanSchemasArgsForCall)]
+	fake.getServicePlanSchemasArgsForCall = append(fake.getServicePlanSchemasArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.GetServicePlanSchemasStub
+	fakeReturns := fake.getServicePlanSchemasReturns
+	fake.recordInvocation("GetServicePlanSchemas", []interface{}{arg1, arg2, arg3})
+	fake.getServicePlanSchemasMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSpaceClient) GetServicePlanSchemasCallCount() int {
+	fake.getServicePlanSchemasMutex.RLock()
+	defer fake.getServicePlanSchemasMutex.RUnlock()
+	return len(fake.getServicePlanSchemasArgsForCall)
+}
+
+func (fake *FakeSpaceClient) GetServicePlanSchemasCalls(stub func(context.Context, string, string) (*facade.ServicePlanSchemas, error)) {
+	fake.getServicePlanSchemasMutex.Lock()
+	defer fake.getServicePlanSchemasMutex.Unlock()
+	fake.GetServicePlanSchemasStub = stub
+}
+
+func (fake *FakeSpaceClient) GetServicePlanSchemasArgsForCall(i int) (context.Context, string, string) {
+	fake.getServicePlanSchemasMutex.RLock()
+	defer fake.getServicePlanSchemasMutex.RUnlock()
+	argsForCall := fake.getServicePlanSchemasArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeSpaceClient) GetServicePlanSchemasReturns(result1 *facade.ServicePlanSchemas, result2 error) {
+	fake.getServicePlanSchemasMutex.Lock()
+	defer fake.getServicePlanSchemasMutex.Unlock()
+	fake.GetServicePlanSchemasStub = nil
+	fake.getServicePlanSchemasReturns = struct {
+		result1 *facade.ServicePlanSchemas
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) GetServicePlanSchemasReturnsOnCall(i int, result1 *facade.ServicePlanSchemas, result2 error) {
+	fake.getServicePlanSchemasMutex.Lock()
+	defer fake.getServicePlanSchemasMutex.Unlock()
+	fake.GetServicePlanSchemasStub = nil
+	if fake.getServicePlanSchemasReturnsOnCall == nil {
+		fake.getServicePlanSchemasReturnsOnCall = make(map[int]struct {
+			result1 *facade.ServicePlanSchemas
+			result2 error
+		})
+	}
+	fake.getServicePlanSchemasReturnsOnCall[i] = struct {
+		result1 *facade.ServicePlanSchemas
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceClient) ListBindings(arg1 context.Context) ([]*facade.Binding, error) {
+	fake.listBindingsMutex.Lock()
+	ret, specificReturn := fake.listBindingsReturnsOnCall[len(fake.listBindingsArgsForCall)]
+	fake.listBindingsArgsForCall = append(fake.listBindingsArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.ListBindingsStub
+	fakeReturns := fake.listBindingsReturns
+	fake.recordInvocation("ListBindings", []interface{}{arg1})
+	fake.listBindingsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -433,64 +1701,63 @@ func (fake *FakeSpaceClient) FindServicePlan(arg1 context.Context, arg2 string,
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeSpaceClient) FindServicePlanCallCount() int {
-	fake.findServicePlanMutex.RLock()
-	defer fake.findServicePlanMutex.RUnlock()
-	return len(fake.findServicePlanArgsForCall)
+func (fake *FakeSpaceClient) ListBindingsCallCount() int {
+	fake.listBindingsMutex.RLock()
+	defer fake.listBindingsMutex.RUnlock()
+	return len(fake.listBindingsArgsForCall)
 }
 
-func (fake *FakeSpaceClient) FindServicePlanCalls(stub func(context.Context, string, string, string) (string, error)) {
-	fake.findServicePlanMutex.Lock()
-	defer fake.findServicePlanMutex.Unlock()
-	fake.FindServicePlanStub = stub
+func (fake *FakeSpaceClient) ListBindingsCalls(stub func(context.Context) ([]*facade.Binding, error)) {
+	fake.listBindingsMutex.Lock()
+	defer fake.listBindingsMutex.Unlock()
+	fake.ListBindingsStub = stub
 }
 
-func (fake *FakeSpaceClient) FindServicePlanArgsForCall(i int) (context.Context, string, string, string) {
-	fake.findServicePlanMutex.RLock()
-	defer fake.findServicePlanMutex.RUnlock()
-	argsForCall := fake.findServicePlanArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+func (fake *FakeSpaceClient) ListBindingsArgsForCall(i int) context.Context {
+	fake.listBindingsMutex.RLock()
+	defer fake.listBindingsMutex.RUnlock()
+	argsForCall := fake.listBindingsArgsForCall[i]
+	return argsForCall.arg1
 }
 
-func (fake *FakeSpaceClient) FindServicePlanReturns(result1 string, result2 error) {
-	fake.findServicePlanMutex.Lock()
-	defer fake.findServicePlanMutex.Unlock()
-	fake.FindServicePlanStub = nil
-	fake.findServicePlanReturns = struct {
-		result1 string
+func (fake *FakeSpaceClient) ListBindingsReturns(result1 []*facade.Binding, result2 error) {
+	fake.listBindingsMutex.Lock()
+	defer fake.listBindingsMutex.Unlock()
+	fake.ListBindingsStub = nil
+	fake.listBindingsReturns = struct {
+		result1 []*facade.Binding
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeSpaceClient) FindServicePlanReturnsOnCall(i int, result1 string, result2 error) {
-	fake.findServicePlanMutex.Lock()
-	defer fake.findServicePlanMutex.Unlock()
-	fake.FindServicePlanStub = nil
-	if fake.findServicePlanReturnsOnCall == nil {
-		fake.findServicePlanReturnsOnCall = make(map[int]struct {
-			result1 string
+func (fake *FakeSpaceClient) ListBindingsReturnsOnCall(i int, result1 []*facade.Binding, result2 error) {
+	fake.listBindingsMutex.Lock()
+	defer fake.listBindingsMutex.Unlock()
+	fake.ListBindingsStub = nil
+	if fake.listBindingsReturnsOnCall == nil {
+		fake.listBindingsReturnsOnCall = make(map[int]struct {
+			result1 []*facade.Binding
 			result2 error
 		})
 	}
-	fake.findServicePlanReturnsOnCall[i] = struct {
-		result1 string
+	fake.listBindingsReturnsOnCall[i] = struct {
+		result1 []*facade.Binding
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeSpaceClient) GetBinding(arg1 context.Context, arg2 map[string]string) (*facade.Binding, error) {
-	fake.getBindingMutex.Lock()
-	ret, specificReturn := fake.getBindingReturnsOnCall[len(fake.getBindingArgsForCall)]
-	fake.getBindingArgsForCall = append(fake.getBindingArgsForCall, struct {
+func (fake *FakeSpaceClient) ListInstances(arg1 context.Context) ([]*facade.Instance, error) {
+	fake.listInstancesMutex.Lock()
+	ret, specificReturn := fake.listInstancesReturnsOnCall[len(fake.listInstancesArgsForCall)]
+	fake.listInstancesArgsForCall = append(fake.listInstancesArgsForCall, struct {
 		arg1 context.Context
-		arg2 map[string]string
-	}{arg1, arg2})
-	stub := fake.GetBindingStub
-	fakeReturns := fake.getBindingReturns
-	fake.recordInvocation("GetBinding", []interface{}{arg1, arg2})
-	fake.getBindingMutex.Unlock()
+	}{arg1})
+	stub := fake.ListInstancesStub
+	fakeReturns := fake.listInstancesReturns
+	fake.recordInvocation("ListInstances", []interface{}{arg1})
+	fake.listInstancesMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -498,62 +1765,62 @@ func (fake *FakeSpaceClient) GetBinding(arg1 context.Context, arg2 map[string]st
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeSpaceClient) GetBindingCallCount() int {
-	fake.getBindingMutex.RLock()
-	defer fake.getBindingMutex.RUnlock()
-	return len(fake.getBindingArgsForCall)
+func (fake *FakeSpaceClient) ListInstancesCallCount() int {
+	fake.listInstancesMutex.RLock()
+	defer fake.listInstancesMutex.RUnlock()
+	return len(fake.listInstancesArgsForCall)
 }
 
-func (fake *FakeSpaceClient) GetBindingCalls(stub func(context.Context, map[string]string) (*facade.Binding, error)) {
-	fake.getBindingMutex.Lock()
-	defer fake.getBindingMutex.Unlock()
-	fake.GetBindingStub = stub
+func (fake *FakeSpaceClient) ListInstancesCalls(stub func(context.Context) ([]*facade.Instance, error)) {
+	fake.listInstancesMutex.Lock()
+	defer fake.listInstancesMutex.Unlock()
+	fake.ListInstancesStub = stub
 }
 
-func (fake *FakeSpaceClient) GetBindingArgsForCall(i int) (context.Context, map[string]string) {
-	fake.getBindingMutex.RLock()
-	defer fake.getBindingMutex.RUnlock()
-	argsForCall := fake.getBindingArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+func (fake *FakeSpaceClient) ListInstancesArgsForCall(i int) context.Context {
+	fake.listInstancesMutex.RLock()
+	defer fake.listInstancesMutex.RUnlock()
+	argsForCall := fake.listInstancesArgsForCall[i]
+	return argsForCall.arg1
 }
 
-func (fake *FakeSpaceClient) GetBindingReturns(result1 *facade.Binding, result2 error) {
-	fake.getBindingMutex.Lock()
-	defer fake.getBindingMutex.Unlock()
-	fake.GetBindingStub = nil
-	fake.getBindingReturns = struct {
-		result1 *facade.Binding
+func (fake *FakeSpaceClient) ListInstancesReturns(result1 []*facade.Instance, result2 error) {
+	fake.listInstancesMutex.Lock()
+	defer fake.listInstancesMutex.Unlock()
+	fake.ListInstancesStub = nil
+	fake.listInstancesReturns = struct {
+		result1 []*facade.Instance
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeSpaceClient) GetBindingReturnsOnCall(i int, result1 *facade.Binding, result2 error) {
-	fake.getBindingMutex.Lock()
-	defer fake.getBindingMutex.Unlock()
-	fake.GetBindingStub = nil
-	if fake.getBindingReturnsOnCall == nil {
-		fake.getBindingReturnsOnCall = make(map[int]struct {
-			result1 *facade.Binding
+func (fake *FakeSpaceClient) ListInstancesReturnsOnCall(i int, result1 []*facade.Instance, result2 error) {
+	fake.listInstancesMutex.Lock()
+	defer fake.listInstancesMutex.Unlock()
+	fake.ListInstancesStub = nil
+	if fake.listInstancesReturnsOnCall == nil {
+		fake.listInstancesReturnsOnCall = make(map[int]struct {
+			result1 []*facade.Instance
 			result2 error
 		})
 	}
-	fake.getBindingReturnsOnCall[i] = struct {
-		result1 *facade.Binding
+	fake.listInstancesReturnsOnCall[i] = struct {
+		result1 []*facade.Instance
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeSpaceClient) GetInstance(arg1 context.Context, arg2 map[string]string) (*facade.Instance, error) {
-	fake.getInstanceMutex.Lock()
-	ret, specificReturn := fake.getInstanceReturnsOnCall[len(fake.getInstanceArgsForCall)]
-	fake.getInstanceArgsForCall = append(fake.getInstanceArgsForCall, struct {
+func (fake *FakeSpaceClient) ListServicePlans(arg1 context.Context, arg2 string) ([]facade.ServicePlan, error) {
+	fake.listServicePlansMutex.Lock()
+	ret, specificReturn := fake.listServicePlansReturnsOnCall[len(fake.listServicePlansArgsForCall)]
+	fake.listServicePlansArgsForCall = append(fake.listServicePlansArgsForCall, struct {
 		arg1 context.Context
-		arg2 map[string]string
+		arg2 string
 	}{arg1, arg2})
-	stub := fake.GetInstanceStub
-	fakeReturns := fake.getInstanceReturns
-	fake.recordInvocation("GetInstance", []interface{}{arg1, arg2})
-	fake.getInstanceMutex.Unlock()
+	stub := fake.ListServicePlansStub
+	fakeReturns := fake.listServicePlansReturns
+	fake.recordInvocation("ListServicePlans", []interface{}{arg1, arg2})
+	fake.listServicePlansMutex.Unlock()
 	if stub != nil {
 		return stub(arg1, arg2)
 	}
@@ -563,66 +1830,192 @@ func (fake *FakeSpaceClient) GetInstance(arg1 context.Context, arg2 map[string]s
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeSpaceClient) GetInstanceCallCount() int {
-	fake.getInstanceMutex.RLock()
-	defer fake.getInstanceMutex.RUnlock()
-	return len(fake.getInstanceArgsForCall)
+func (fake *FakeSpaceClient) ListServicePlansCallCount() int {
+	fake.listServicePlansMutex.RLock()
+	defer fake.listServicePlansMutex.RUnlock()
+	return len(fake.listServicePlansArgsForCall)
 }
 
-func (fake *FakeSpaceClient) GetInstanceCalls(stub func(context.Context, map[string]string) (*facade.Instance, error)) {
-	fake.getInstanceMutex.Lock()
-	defer fake.getInstanceMutex.Unlock()
-	fake.GetInstanceStub = stub
+func (fake *FakeSpaceClient) ListServicePlansCalls(stub func(context.Context, string) ([]facade.ServicePlan, error)) {
+	fake.listServicePlansMutex.Lock()
+	defer fake.listServicePlansMutex.Unlock()
+	fake.ListServicePlansStub = stub
 }
 
-func (fake *FakeSpaceClient) GetInstanceArgsForCall(i int) (context.Context, map[string]string) {
-	fake.getInstanceMutex.RLock()
-	defer fake.getInstanceMutex.RUnlock()
-	argsForCall := fake.getInstanceArgsForCall[i]
+func (fake *FakeSpaceClient) ListServicePlansArgsForCall(i int) (context.Context, string) {
+	fake.listServicePlansMutex.RLock()
+	defer fake.listServicePlansMutex.RUnlock()
+	argsForCall := fake.listServicePlansArgsForCall[i]
 	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeSpaceClient) GetInstanceReturns(result1 *facade.Instance, result2 error) {
-	fake.getInstanceMutex.Lock()
-	defer fake.getInstanceMutex.Unlock()
-	fake.GetInstanceStub = nil
-	fake.getInstanceReturns = struct {
-		result1 *facade.Instance
+func (fake *FakeSpaceClient) ListServicePlansReturns(result1 []facade.ServicePlan, result2 error) {
+	fake.listServicePlansMutex.Lock()
+	defer fake.listServicePlansMutex.Unlock()
+	fake.ListServicePlansStub = nil
+	fake.listServicePlansReturns = struct {
+		result1 []facade.ServicePlan
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeSpaceClient) GetInstanceReturnsOnCall(i int, result1 *facade.Instance, result2 error) {
-	fake.getInstanceMutex.Lock()
-	defer fake.getInstanceMutex.Unlock()
-	fake.GetInstanceStub = nil
-	if fake.getInstanceReturnsOnCall == nil {
-		fake.getInstanceReturnsOnCall = make(map[int]struct {
-			result1 *facade.Instance
+func (fake *FakeSpaceClient) ListServicePlansReturnsOnCall(i int, result1 []facade.ServicePlan, result2 error) {
+	fake.listServicePlansMutex.Lock()
+	defer fake.listServicePlansMutex.Unlock()
+	fake.ListServicePlansStub = nil
+	if fake.listServicePlansReturnsOnCall == nil {
+		fake.listServicePlansReturnsOnCall = make(map[int]struct {
+			result1 []facade.ServicePlan
 			result2 error
 		})
 	}
-	fake.getInstanceReturnsOnCall[i] = struct {
-		result1 *facade.Instance
+	fake.listServicePlansReturnsOnCall[i] = struct {
+		result1 []facade.ServicePlan
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeSpaceClient) UpdateBinding(arg1 context.Context, arg2 string, arg3 int64, arg4 map[string]interface{}) error {
+func (fake *FakeSpaceClient) PromoteBinding(arg1 context.Context, arg2 string, arg3 facade.OwnerRef) error {
+	fake.promoteBindingMutex.Lock()
+	ret, specificReturn := fake.promoteBindingReturnsOnCall[len(fake.promoteBindingArgsForCall)]
+	fake.promoteBindingArgsForCall = append(fake.promoteBindingArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 facade.OwnerRef
+	}{arg1, arg2, arg3})
+	stub := fake.PromoteBindingStub
+	fakeReturns := fake.promoteBindingReturns
+	fake.recordInvocation("PromoteBinding", []interface{}{arg1, arg2, arg3})
+	fake.promoteBindingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeSpaceClient) PromoteBindingCallCount() int {
+	fake.promoteBindingMutex.RLock()
+	defer fake.promoteBindingMutex.RUnlock()
+	return len(fake.promoteBindingArgsForCall)
+}
+
+func (fake *FakeSpaceClient) PromoteBindingCalls(stub func(context.Context, string, facade.OwnerRef) error) {
+	fake.promoteBindingMutex.Lock()
+	defer fake.promoteBindingMutex.Unlock()
+	fake.PromoteBindingStub = stub
+}
+
+func (fake *FakeSpaceClient) PromoteBindingArgsForCall(i int) (context.Context, string, facade.OwnerRef) {
+	fake.promoteBindingMutex.RLock()
+	defer fake.promoteBindingMutex.RUnlock()
+	argsForCall := fake.promoteBindingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeSpaceClient) PromoteBindingReturns(result1 error) {
+	fake.promoteBindingMutex.Lock()
+	defer fake.promoteBindingMutex.Unlock()
+	fake.PromoteBindingStub = nil
+	fake.promoteBindingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceClient) PromoteBindingReturnsOnCall(i int, result1 error) {
+	fake.promoteBindingMutex.Lock()
+	defer fake.promoteBindingMutex.Unlock()
+	fake.PromoteBindingStub = nil
+	if fake.promoteBindingReturnsOnCall == nil {
+		fake.promoteBindingReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.promoteBindingReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceClient) RefreshCache(arg1 context.Context) error {
+	fake.refreshCacheMutex.Lock()
+	ret, specificReturn := fake.refreshCacheReturnsOnCall[len(fake.refreshCacheArgsForCall)]
+	fake.refreshCacheArgsForCall = append(fake.refreshCacheArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.RefreshCacheStub
+	fakeReturns := fake.refreshCacheReturns
+	fake.recordInvocation("RefreshCache", []interface{}{arg1})
+	fake.refreshCacheMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeSpaceClient) RefreshCacheCallCount() int {
+	fake.refreshCacheMutex.RLock()
+	defer fake.refreshCacheMutex.RUnlock()
+	return len(fake.refreshCacheArgsForCall)
+}
+
+func (fake *FakeSpaceClient) RefreshCacheCalls(stub func(context.Context) error) {
+	fake.refreshCacheMutex.Lock()
+	defer fake.refreshCacheMutex.Unlock()
+	fake.RefreshCacheStub = stub
+}
+
+func (fake *FakeSpaceClient) RefreshCacheArgsForCall(i int) context.Context {
+	fake.refreshCacheMutex.RLock()
+	defer fake.refreshCacheMutex.RUnlock()
+	argsForCall := fake.refreshCacheArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSpaceClient) RefreshCacheReturns(result1 error) {
+	fake.refreshCacheMutex.Lock()
+	defer fake.refreshCacheMutex.Unlock()
+	fake.RefreshCacheStub = nil
+	fake.refreshCacheReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceClient) RefreshCacheReturnsOnCall(i int, result1 error) {
+	fake.refreshCacheMutex.Lock()
+	defer fake.refreshCacheMutex.Unlock()
+	fake.RefreshCacheStub = nil
+	if fake.refreshCacheReturnsOnCall == nil {
+		fake.refreshCacheReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.refreshCacheReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceClient) UpdateBinding(arg1 context.Context, arg2 string, arg3 facade.OwnerRef, arg4 int64, arg5 map[string]interface{}, arg6 *facade.Metadata) error {
 	fake.updateBindingMutex.Lock()
 	ret, specificReturn := fake.updateBindingReturnsOnCall[len(fake.updateBindingArgsForCall)]
 	fake.updateBindingArgsForCall = append(fake.updateBindingArgsForCall, struct {
 		arg1 context.Context
 		arg2 string
-		arg3 int64
-		arg4 map[string]interface{}
-	}{arg1, arg2, arg3, arg4})
+		arg3 facade.OwnerRef
+		arg4 int64
+		arg5 map[string]interface{}
+		arg6 *facade.Metadata
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
 	stub := fake.UpdateBindingStub
 	fakeReturns := fake.updateBindingReturns
-	fake.recordInvocation("UpdateBinding", []interface{}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("UpdateBinding", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
 	fake.updateBindingMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
 	}
 	if specificReturn {
 		return ret.result1
@@ -636,17 +2029,17 @@ func (fake *FakeSpaceClient) UpdateBindingCallCount() int {
 	return len(fake.updateBindingArgsForCall)
 }
 
-func (fake *FakeSpaceClient) UpdateBindingCalls(stub func(context.Context, string, int64, map[string]interface{}) error) {
+func (fake *FakeSpaceClient) UpdateBindingCalls(stub func(context.Context, string, facade.OwnerRef, int64, map[string]interface{}, *facade.Metadata) error) {
 	fake.updateBindingMutex.Lock()
 	defer fake.updateBindingMutex.Unlock()
 	fake.UpdateBindingStub = stub
 }
 
-func (fake *FakeSpaceClient) UpdateBindingArgsForCall(i int) (context.Context, string, int64, map[string]interface{}) {
+func (fake *FakeSpaceClient) UpdateBindingArgsForCall(i int) (context.Context, string, facade.OwnerRef, int64, map[string]interface{}, *facade.Metadata) {
 	fake.updateBindingMutex.RLock()
 	defer fake.updateBindingMutex.RUnlock()
 	argsForCall := fake.updateBindingArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
 }
 
 func (fake *FakeSpaceClient) UpdateBindingReturns(result1 error) {
@@ -672,29 +2065,31 @@ func (fake *FakeSpaceClient) UpdateBindingReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeSpaceClient) UpdateInstance(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 map[string]interface{}, arg6 []string, arg7 int64) error {
-	var arg6Copy []string
-	if arg6 != nil {
-		arg6Copy = make([]string, len(arg6))
-		copy(arg6Copy, arg6)
+func (fake *FakeSpaceClient) UpdateInstance(arg1 context.Context, arg2 string, arg3 facade.OwnerRef, arg4 string, arg5 string, arg6 map[string]interface{}, arg7 []string, arg8 *facade.Metadata, arg9 int64) error {
+	var arg7Copy []string
+	if arg7 != nil {
+		arg7Copy = make([]string, len(arg7))
+		copy(arg7Copy, arg7)
 	}
 	fake.updateInstanceMutex.Lock()
 	ret, specificReturn := fake.updateInstanceReturnsOnCall[len(fake.updateInstanceArgsForCall)]
 	fake.updateInstanceArgsForCall = append(fake.updateInstanceArgsForCall, struct {
 		arg1 context.Context
 		arg2 string
-		arg3 string
+		arg3 facade.OwnerRef
 		arg4 string
-		arg5 map[string]interface{}
-		arg6 []string
-		arg7 int64
-	}{arg1, arg2, arg3, arg4, arg5, arg6Copy, arg7})
+		arg5 string
+		arg6 map[string]interface{}
+		arg7 []string
+		arg8 *facade.Metadata
+		arg9 int64
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7Copy, arg8, arg9})
 	stub := fake.UpdateInstanceStub
 	fakeReturns := fake.updateInstanceReturns
-	fake.recordInvocation("UpdateInstance", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6Copy, arg7})
+	fake.recordInvocation("UpdateInstance", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7Copy, arg8, arg9})
 	fake.updateInstanceMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9)
 	}
 	if specificReturn {
 		return ret.result1
@@ -708,17 +2103,17 @@ func (fake *FakeSpaceClient) UpdateInstanceCallCount() int {
 	return len(fake.updateInstanceArgsForCall)
 }
 
-func (fake *FakeSpaceClient) UpdateInstanceCalls(stub func(context.Context, string, string, string, map[string]interface{}, []string, int64) error) {
+func (fake *FakeSpaceClient) UpdateInstanceCalls(stub func(context.Context, string, facade.OwnerRef, string, string, map[string]interface{}, []string, *facade.Metadata, int64) error) {
 	fake.updateInstanceMutex.Lock()
 	defer fake.updateInstanceMutex.Unlock()
 	fake.UpdateInstanceStub = stub
 }
 
-func (fake *FakeSpaceClient) UpdateInstanceArgsForCall(i int) (context.Context, string, string, string, map[string]interface{}, []string, int64) {
+func (fake *FakeSpaceClient) UpdateInstanceArgsForCall(i int) (context.Context, string, facade.OwnerRef, string, string, map[string]interface{}, []string, *facade.Metadata, int64) {
 	fake.updateInstanceMutex.RLock()
 	defer fake.updateInstanceMutex.RUnlock()
 	argsForCall := fake.updateInstanceArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8, argsForCall.arg9
 }
 
 func (fake *FakeSpaceClient) UpdateInstanceReturns(result1 error) {
@@ -744,6 +2139,196 @@ func (fake *FakeSpaceClient) UpdateInstanceReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeSpaceClient) UpdateRoute(arg1 context.Context, arg2 string, arg3 int64) error {
+	fake.updateRouteMutex.Lock()
+	ret, specificReturn := fake.updateRouteReturnsOnCall[len(fake.updateRouteArgsForCall)]
+	fake.updateRouteArgsForCall = append(fake.updateRouteArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int64
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateRouteStub
+	fakeReturns := fake.updateRouteReturns
+	fake.recordInvocation("UpdateRoute", []interface{}{arg1, arg2, arg3})
+	fake.updateRouteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeSpaceClient) UpdateRouteCallCount() int {
+	fake.updateRouteMutex.RLock()
+	defer fake.updateRouteMutex.RUnlock()
+	return len(fake.updateRouteArgsForCall)
+}
+
+func (fake *FakeSpaceClient) UpdateRouteCalls(stub func(context.Context, string, int64) error) {
+	fake.updateRouteMutex.Lock()
+	defer fake.updateRouteMutex.Unlock()
+	fake.UpdateRouteStub = stub
+}
+
+func (fake *FakeSpaceClient) UpdateRouteArgsForCall(i int) (context.Context, string, int64) {
+	fake.updateRouteMutex.RLock()
+	defer fake.updateRouteMutex.RUnlock()
+	argsForCall := fake.updateRouteArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeSpaceClient) UpdateRouteReturns(result1 error) {
+	fake.updateRouteMutex.Lock()
+	defer fake.updateRouteMutex.Unlock()
+	fake.UpdateRouteStub = nil
+	fake.updateRouteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceClient) UpdateRouteReturnsOnCall(i int, result1 error) {
+	fake.updateRouteMutex.Lock()
+	defer fake.updateRouteMutex.Unlock()
+	fake.UpdateRouteStub = nil
+	if fake.updateRouteReturnsOnCall == nil {
+		fake.updateRouteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateRouteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceClient) UpdateRouteBinding(arg1 context.Context, arg2 string, arg3 int64) error {
+	fake.updateRouteBindingMutex.Lock()
+	ret, specificReturn := fake.updateRouteBindingReturnsOnCall[len(fake.updateRouteBindingArgsForCall)]
+	fake.updateRouteBindingArgsForCall = append(fake.updateRouteBindingArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int64
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateRouteBindingStub
+	fakeReturns := fake.updateRouteBindingReturns
+	fake.recordInvocation("UpdateRouteBinding", []interface{}{arg1, arg2, arg3})
+	fake.updateRouteBindingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeSpaceClient) UpdateRouteBindingCallCount() int {
+	fake.updateRouteBindingMutex.RLock()
+	defer fake.updateRouteBindingMutex.RUnlock()
+	return len(fake.updateRouteBindingArgsForCall)
+}
+
+func (fake *FakeSpaceClient) UpdateRouteBindingCalls(stub func(context.Context, string, int64) error) {
+	fake.updateRouteBindingMutex.Lock()
+	defer fake.updateRouteBindingMutex.Unlock()
+	fake.UpdateRouteBindingStub = stub
+}
+
+func (fake *FakeSpaceClient) UpdateRouteBindingArgsForCall(i int) (context.Context, string, int64) {
+	fake.updateRouteBindingMutex.RLock()
+	defer fake.updateRouteBindingMutex.RUnlock()
+	argsForCall := fake.updateRouteBindingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeSpaceClient) UpdateRouteBindingReturns(result1 error) {
+	fake.updateRouteBindingMutex.Lock()
+	defer fake.updateRouteBindingMutex.Unlock()
+	fake.UpdateRouteBindingStub = nil
+	fake.updateRouteBindingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceClient) UpdateRouteBindingReturnsOnCall(i int, result1 error) {
+	fake.updateRouteBindingMutex.Lock()
+	defer fake.updateRouteBindingMutex.Unlock()
+	fake.UpdateRouteBindingStub = nil
+	if fake.updateRouteBindingReturnsOnCall == nil {
+		fake.updateRouteBindingReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateRouteBindingReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceClient) UpgradeInstance(arg1 context.Context, arg2 string, arg3 facade.OwnerRef, arg4 facade.MaintenanceInfo) error {
+	fake.upgradeInstanceMutex.Lock()
+	ret, specificReturn := fake.upgradeInstanceReturnsOnCall[len(fake.upgradeInstanceArgsForCall)]
+	fake.upgradeInstanceArgsForCall = append(fake.upgradeInstanceArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 facade.OwnerRef
+		arg4 facade.MaintenanceInfo
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.UpgradeInstanceStub
+	fakeReturns := fake.upgradeInstanceReturns
+	fake.recordInvocation("UpgradeInstance", []interface{}{arg1, arg2, arg3, arg4})
+	fake.upgradeInstanceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeSpaceClient) UpgradeInstanceCallCount() int {
+	fake.upgradeInstanceMutex.RLock()
+	defer fake.upgradeInstanceMutex.RUnlock()
+	return len(fake.upgradeInstanceArgsForCall)
+}
+
+func (fake *FakeSpaceClient) UpgradeInstanceCalls(stub func(context.Context, string, facade.OwnerRef, facade.MaintenanceInfo) error) {
+	fake.upgradeInstanceMutex.Lock()
+	defer fake.upgradeInstanceMutex.Unlock()
+	fake.UpgradeInstanceStub = stub
+}
+
+func (fake *FakeSpaceClient) UpgradeInstanceArgsForCall(i int) (context.Context, string, facade.OwnerRef, facade.MaintenanceInfo) {
+	fake.upgradeInstanceMutex.RLock()
+	defer fake.upgradeInstanceMutex.RUnlock()
+	argsForCall := fake.upgradeInstanceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeSpaceClient) UpgradeInstanceReturns(result1 error) {
+	fake.upgradeInstanceMutex.Lock()
+	defer fake.upgradeInstanceMutex.Unlock()
+	fake.UpgradeInstanceStub = nil
+	fake.upgradeInstanceReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceClient) UpgradeInstanceReturnsOnCall(i int, result1 error) {
+	fake.upgradeInstanceMutex.Lock()
+	defer fake.upgradeInstanceMutex.Unlock()
+	fake.UpgradeInstanceStub = nil
+	if fake.upgradeInstanceReturnsOnCall == nil {
+		fake.upgradeInstanceReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.upgradeInstanceReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeSpaceClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -751,20 +2336,60 @@ func (fake *FakeSpaceClient) Invocations() map[string][][]interface{} {
 	defer fake.createBindingMutex.RUnlock()
 	fake.createInstanceMutex.RLock()
 	defer fake.createInstanceMutex.RUnlock()
+	fake.createRouteMutex.RLock()
+	defer fake.createRouteMutex.RUnlock()
+	fake.createRouteBindingMutex.RLock()
+	defer fake.createRouteBindingMutex.RUnlock()
 	fake.deleteBindingMutex.RLock()
 	defer fake.deleteBindingMutex.RUnlock()
 	fake.deleteInstanceMutex.RLock()
 	defer fake.deleteInstanceMutex.RUnlock()
+	fake.deleteRouteMutex.RLock()
+	defer fake.deleteRouteMutex.RUnlock()
+	fake.deleteRouteBindingMutex.RLock()
+	defer fake.deleteRouteBindingMutex.RUnlock()
+	fake.findAppMutex.RLock()
+	defer fake.findAppMutex.RUnlock()
+	fake.findDomainMutex.RLock()
+	defer fake.findDomainMutex.RUnlock()
+	fake.findInstanceNameMutex.RLock()
+	defer fake.findInstanceNameMutex.RUnlock()
 	fake.findServicePlanMutex.RLock()
 	defer fake.findServicePlanMutex.RUnlock()
 	fake.getBindingMutex.RLock()
 	defer fake.getBindingMutex.RUnlock()
+	fake.getBindingCredentialsMutex.RLock()
+	defer fake.getBindingCredentialsMutex.RUnlock()
 	fake.getInstanceMutex.RLock()
 	defer fake.getInstanceMutex.RUnlock()
+	fake.getInstanceParametersMutex.RLock()
+	defer fake.getInstanceParametersMutex.RUnlock()
+	fake.getRouteMutex.RLock()
+	defer fake.getRouteMutex.RUnlock()
+	fake.getRouteBindingMutex.RLock()
+	defer fake.getRouteBindingMutex.RUnlock()
+	fake.getServicePlanSchemasMutex.RLock()
+	defer fake.getServicePlanSchemasMutex.RUnlock()
+	fake.listBindingsMutex.RLock()
+	defer fake.listBindingsMutex.RUnlock()
+	fake.listInstancesMutex.RLock()
+	defer fake.listInstancesMutex.RUnlock()
+	fake.listServicePlansMutex.RLock()
+	defer fake.listServicePlansMutex.RUnlock()
+	fake.promoteBindingMutex.RLock()
+	defer fake.promoteBindingMutex.RUnlock()
+	fake.refreshCacheMutex.RLock()
+	defer fake.refreshCacheMutex.RUnlock()
 	fake.updateBindingMutex.RLock()
 	defer fake.updateBindingMutex.RUnlock()
 	fake.updateInstanceMutex.RLock()
 	defer fake.updateInstanceMutex.RUnlock()
+	fake.updateRouteMutex.RLock()
+	defer fake.updateRouteMutex.RUnlock()
+	fake.updateRouteBindingMutex.RLock()
+	defer fake.updateRouteBindingMutex.RUnlock()
+	fake.upgradeInstanceMutex.RLock()
+	defer fake.upgradeInstanceMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -24,6 +24,68 @@ type FakeSpaceHealthChecker struct {
 	checkReturnsOnCall map[int]struct {
 		result1 error
 	}
+	CheckDeveloperRoleStub        func(context.Context, string) error
+	checkDeveloperRoleMutex       sync.RWMutex
+	checkDeveloperRoleArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	checkDeveloperRoleReturns struct {
+		result1 error
+	}
+	checkDeveloperRoleReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CheckQuotaStub        func(context.Context, int) error
+	checkQuotaMutex       sync.RWMutex
+	checkQuotaArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+	}
+	checkQuotaReturns struct {
+		result1 error
+	}
+	checkQuotaReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CheckServicePlansStub        func(context.Context) error
+	checkServicePlansMutex       sync.RWMutex
+	checkServicePlansArgsForCall []struct {
+		arg1 context.Context
+	}
+	checkServicePlansReturns struct {
+		result1 error
+	}
+	checkServicePlansReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetQuotaUsageStub        func(context.Context) (*facade.QuotaUsage, error)
+	getQuotaUsageMutex       sync.RWMutex
+	getQuotaUsageArgsForCall []struct {
+		arg1 context.Context
+	}
+	getQuotaUsageReturns struct {
+		result1 *facade.QuotaUsage
+		result2 error
+	}
+	getQuotaUsageReturnsOnCall map[int]struct {
+		result1 *facade.QuotaUsage
+		result2 error
+	}
+	GetSpaceByGuidStub        func(context.Context, string) (*facade.Space, error)
+	getSpaceByGuidMutex       sync.RWMutex
+	getSpaceByGuidArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getSpaceByGuidReturns struct {
+		result1 *facade.Space
+		result2 error
+	}
+	getSpaceByGuidReturnsOnCall map[int]struct {
+		result1 *facade.Space
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -89,11 +151,335 @@ func (fake *FakeSpaceHealthChecker) CheckReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeSpaceHealthChecker) CheckDeveloperRole(arg1 context.Context, arg2 string) error {
+	fake.checkDeveloperRoleMutex.Lock()
+	ret, specificReturn := fake.checkDeveloperRoleReturnsOnCall[len(fake.checkDeveloperRoleArgsForCall)]
+	fake.checkDeveloperRoleArgsForCall = append(fake.checkDeveloperRoleArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.CheckDeveloperRoleStub
+	fakeReturns := fake.checkDeveloperRoleReturns
+	fake.recordInvocation("CheckDeveloperRole", []interface{}{arg1, arg2})
+	fake.checkDeveloperRoleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeSpaceHealthChecker) CheckDeveloperRoleCallCount() int {
+	fake.checkDeveloperRoleMutex.RLock()
+	defer fake.checkDeveloperRoleMutex.RUnlock()
+	return len(fake.checkDeveloperRoleArgsForCall)
+}
+
+func (fake *FakeSpaceHealthChecker) CheckDeveloperRoleCalls(stub func(context.Context, string) error) {
+	fake.checkDeveloperRoleMutex.Lock()
+	defer fake.checkDeveloperRoleMutex.Unlock()
+	fake.CheckDeveloperRoleStub = stub
+}
+
+func (fake *FakeSpaceHealthChecker) CheckDeveloperRoleArgsForCall(i int) (context.Context, string) {
+	fake.checkDeveloperRoleMutex.RLock()
+	defer fake.checkDeveloperRoleMutex.RUnlock()
+	argsForCall := fake.checkDeveloperRoleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSpaceHealthChecker) CheckDeveloperRoleReturns(result1 error) {
+	fake.checkDeveloperRoleMutex.Lock()
+	defer fake.checkDeveloperRoleMutex.Unlock()
+	fake.CheckDeveloperRoleStub = nil
+	fake.checkDeveloperRoleReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceHealthChecker) CheckDeveloperRoleReturnsOnCall(i int, result1 error) {
+	fake.checkDeveloperRoleMutex.Lock()
+	defer fake.checkDeveloperRoleMutex.Unlock()
+	fake.CheckDeveloperRoleStub = nil
+	if fake.checkDeveloperRoleReturnsOnCall == nil {
+		fake.checkDeveloperRoleReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.checkDeveloperRoleReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceHealthChecker) CheckQuota(arg1 context.Context, arg2 int) error {
+	fake.checkQuotaMutex.Lock()
+	ret, specificReturn := fake.checkQuotaReturnsOnCall[len(fake.checkQuotaArgsForCall)]
+	fake.checkQuotaArgsForCall = append(fake.checkQuotaArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.CheckQuotaStub
+	fakeReturns := fake.checkQuotaReturns
+	fake.recordInvocation("CheckQuota", []interface{}{arg1, arg2})
+	fake.checkQuotaMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeSpaceHealthChecker) CheckQuotaCallCount() int {
+	fake.checkQuotaMutex.RLock()
+	defer fake.checkQuotaMutex.RUnlock()
+	return len(fake.checkQuotaArgsForCall)
+}
+
+func (fake *FakeSpaceHealthChecker) CheckQuotaCalls(stub func(context.Context, int) error) {
+	fake.checkQuotaMutex.Lock()
+	defer fake.checkQuotaMutex.Unlock()
+	fake.CheckQuotaStub = stub
+}
+
+func (fake *FakeSpaceHealthChecker) CheckQuotaArgsForCall(i int) (context.Context, int) {
+	fake.checkQuotaMutex.RLock()
+	defer fake.checkQuotaMutex.RUnlock()
+	argsForCall := fake.checkQuotaArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSpaceHealthChecker) CheckQuotaReturns(result1 error) {
+	fake.checkQuotaMutex.Lock()
+	defer fake.checkQuotaMutex.Unlock()
+	fake.CheckQuotaStub = nil
+	fake.checkQuotaReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceHealthChecker) CheckQuotaReturnsOnCall(i int, result1 error) {
+	fake.checkQuotaMutex.Lock()
+	defer fake.checkQuotaMutex.Unlock()
+	fake.CheckQuotaStub = nil
+	if fake.checkQuotaReturnsOnCall == nil {
+		fake.checkQuotaReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.checkQuotaReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceHealthChecker) CheckServicePlans(arg1 context.Context) error {
+	fake.checkServicePlansMutex.Lock()
+	ret, specificReturn := fake.checkServicePlansReturnsOnCall[len(fake.checkServicePlansArgsForCall)]
+	fake.checkServicePlansArgsForCall = append(fake.checkServicePlansArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.CheckServicePlansStub
+	fakeReturns := fake.checkServicePlansReturns
+	fake.recordInvocation("CheckServicePlans", []interface{}{arg1})
+	fake.checkServicePlansMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeSpaceHealthChecker) CheckServicePlansCallCount() int {
+	fake.checkServicePlansMutex.RLock()
+	defer fake.checkServicePlansMutex.RUnlock()
+	return len(fake.checkServicePlansArgsForCall)
+}
+
+func (fake *FakeSpaceHealthChecker) CheckServicePlansCalls(stub func(context.Context) error) {
+	fake.checkServicePlansMutex.Lock()
+	defer fake.checkServicePlansMutex.Unlock()
+	fake.CheckServicePlansStub = stub
+}
+
+func (fake *FakeSpaceHealthChecker) CheckServicePlansArgsForCall(i int) context.Context {
+	fake.checkServicePlansMutex.RLock()
+	defer fake.checkServicePlansMutex.RUnlock()
+	argsForCall := fake.checkServicePlansArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSpaceHealthChecker) CheckServicePlansReturns(result1 error) {
+	fake.checkServicePlansMutex.Lock()
+	defer fake.checkServicePlansMutex.Unlock()
+	fake.CheckServicePlansStub = nil
+	fake.checkServicePlansReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceHealthChecker) CheckServicePlansReturnsOnCall(i int, result1 error) {
+	fake.checkServicePlansMutex.Lock()
+	defer fake.checkServicePlansMutex.Unlock()
+	fake.CheckServicePlansStub = nil
+	if fake.checkServicePlansReturnsOnCall == nil {
+		fake.checkServicePlansReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.checkServicePlansReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpaceHealthChecker) GetQuotaUsage(arg1 context.Context) (*facade.QuotaUsage, error) {
+	fake.getQuotaUsageMutex.Lock()
+	ret, specificReturn := fake.getQuotaUsageReturnsOnCall[len(fake.getQuotaUsageArgsForCall)]
+	fake.getQuotaUsageArgsForCall = append(fake.getQuotaUsageArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.GetQuotaUsageStub
+	fakeReturns := fake.getQuotaUsageReturns
+	fake.recordInvocation("GetQuotaUsage", []interface{}{arg1})
+	fake.getQuotaUsageMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSpaceHealthChecker) GetQuotaUsageCallCount() int {
+	fake.getQuotaUsageMutex.RLock()
+	defer fake.getQuotaUsageMutex.RUnlock()
+	return len(fake.getQuotaUsageArgsForCall)
+}
+
+func (fake *FakeSpaceHealthChecker) GetQuotaUsageCalls(stub func(context.Context) (*facade.QuotaUsage, error)) {
+	fake.getQuotaUsageMutex.Lock()
+	defer fake.getQuotaUsageMutex.Unlock()
+	fake.GetQuotaUsageStub = stub
+}
+
+func (fake *FakeSpaceHealthChecker) GetQuotaUsageArgsForCall(i int) context.Context {
+	fake.getQuotaUsageMutex.RLock()
+	defer fake.getQuotaUsageMutex.RUnlock()
+	argsForCall := fake.getQuotaUsageArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSpaceHealthChecker) GetQuotaUsageReturns(result1 *facade.QuotaUsage, result2 error) {
+	fake.getQuotaUsageMutex.Lock()
+	defer fake.getQuotaUsageMutex.Unlock()
+	fake.GetQuotaUsageStub = nil
+	fake.getQuotaUsageReturns = struct {
+		result1 *facade.QuotaUsage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceHealthChecker) GetQuotaUsageReturnsOnCall(i int, result1 *facade.QuotaUsage, result2 error) {
+	fake.getQuotaUsageMutex.Lock()
+	defer fake.getQuotaUsageMutex.Unlock()
+	fake.GetQuotaUsageStub = nil
+	if fake.getQuotaUsageReturnsOnCall == nil {
+		fake.getQuotaUsageReturnsOnCall = make(map[int]struct {
+			result1 *facade.QuotaUsage
+			result2 error
+		})
+	}
+	fake.getQuotaUsageReturnsOnCall[i] = struct {
+		result1 *facade.QuotaUsage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceHealthChecker) GetSpaceByGuid(arg1 context.Context, arg2 string) (*facade.Space, error) {
+	fake.getSpaceByGuidMutex.Lock()
+	ret, specificReturn := fake.getSpaceByGuidReturnsOnCall[len(fake.getSpaceByGuidArgsForCall)]
+	fake.getSpaceByGuidArgsForCall = append(fake.getSpaceByGuidArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetSpaceByGuidStub
+	fakeReturns := fake.getSpaceByGuidReturns
+	fake.recordInvocation("GetSpaceByGuid", []interface{}{arg1, arg2})
+	fake.getSpaceByGuidMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSpaceHealthChecker) GetSpaceByGuidCallCount() int {
+	fake.getSpaceByGuidMutex.RLock()
+	defer fake.getSpaceByGuidMutex.RUnlock()
+	return len(fake.getSpaceByGuidArgsForCall)
+}
+
+func (fake *FakeSpaceHealthChecker) GetSpaceByGuidCalls(stub func(context.Context, string) (*facade.Space, error)) {
+	fake.getSpaceByGuidMutex.Lock()
+	defer fake.getSpaceByGuidMutex.Unlock()
+	fake.GetSpaceByGuidStub = stub
+}
+
+func (fake *FakeSpaceHealthChecker) GetSpaceByGuidArgsForCall(i int) (context.Context, string) {
+	fake.getSpaceByGuidMutex.RLock()
+	defer fake.getSpaceByGuidMutex.RUnlock()
+	argsForCall := fake.getSpaceByGuidArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSpaceHealthChecker) GetSpaceByGuidReturns(result1 *facade.Space, result2 error) {
+	fake.getSpaceByGuidMutex.Lock()
+	defer fake.getSpaceByGuidMutex.Unlock()
+	fake.GetSpaceByGuidStub = nil
+	fake.getSpaceByGuidReturns = struct {
+		result1 *facade.Space
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSpaceHealthChecker) GetSpaceByGuidReturnsOnCall(i int, result1 *facade.Space, result2 error) {
+	fake.getSpaceByGuidMutex.Lock()
+	defer fake.getSpaceByGuidMutex.Unlock()
+	fake.GetSpaceByGuidStub = nil
+	if fake.getSpaceByGuidReturnsOnCall == nil {
+		fake.getSpaceByGuidReturnsOnCall = make(map[int]struct {
+			result1 *facade.Space
+			result2 error
+		})
+	}
+	fake.getSpaceByGuidReturnsOnCall[i] = struct {
+		result1 *facade.Space
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeSpaceHealthChecker) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.checkMutex.RLock()
 	defer fake.checkMutex.RUnlock()
+	fake.checkDeveloperRoleMutex.RLock()
+	defer fake.checkDeveloperRoleMutex.RUnlock()
+	fake.checkQuotaMutex.RLock()
+	defer fake.checkQuotaMutex.RUnlock()
+	fake.checkServicePlansMutex.RLock()
+	defer fake.checkServicePlansMutex.RUnlock()
+	fake.getQuotaUsageMutex.RLock()
+	defer fake.getQuotaUsageMutex.RUnlock()
+	fake.getSpaceByGuidMutex.RLock()
+	defer fake.getSpaceByGuidMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
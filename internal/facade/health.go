@@ -5,11 +5,58 @@ SPDX-License-Identifier: Apache-2.0
 
 package facade
 
-import "context"
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/sap/cf-service-operator/internal/config"
+)
 
 //counterfeiter:generate . SpaceHealthChecker
 type SpaceHealthChecker interface {
+	// Check verifies that the space exists and is accessible.
 	Check(ctx context.Context) error
+	// GetSpaceByGuid returns the space with the given guid, or nil if it does not exist (or is not accessible with the used credentials).
+	GetSpaceByGuid(ctx context.Context, guid string) (*Space, error)
+	// CheckServicePlans verifies that service plans are available in the space.
+	CheckServicePlans(ctx context.Context) error
+	// CheckQuota verifies that the service instances quota of the space (if any) is used by no more than threshold percent.
+	CheckQuota(ctx context.Context, threshold int) error
+	// GetQuotaUsage returns the number of service instances in the space, and the limit imposed by the space quota (if any).
+	GetQuotaUsage(ctx context.Context) (*QuotaUsage, error)
+	// CheckDeveloperRole verifies that the given user has the space developer role.
+	CheckDeveloperRole(ctx context.Context, username string) error
+}
+
+// QuotaUsage describes the usage of the service instances quota of a space.
+type QuotaUsage struct {
+	// Name of the space quota; empty if no quota is assigned to the space (the organization quota is not considered)
+	QuotaName string
+	// Number of service instances in the space
+	ServiceInstances int
+	// Maximum number of service instances allowed by the space quota; negative if unlimited, or if no quota is assigned
+	ServiceInstancesLimit int
 }
 
-type SpaceHealthCheckerBuilder func(string, string, string, string) (SpaceHealthChecker, error)
+type SpaceHealthCheckerBuilder func(string, string, string, string, *config.Config) (SpaceHealthChecker, error)
+
+// EndpointProber checks whether the Cloud Foundry API endpoint with the given url is reachable (returning nil if so).
+type EndpointProber func(context.Context, string, *config.Config) error
+
+type responseRecorderKey struct{}
+
+// WithResponseRecorder returns a context recording whether the Cloud Foundry API responded to any request sent with it
+// (or with a derived context); the returned function reports whether this happened so far. Responses served from caches
+// do not count, nor do server (5xx) errors.
+func WithResponseRecorder(ctx context.Context) (context.Context, func() bool) {
+	responded := &atomic.Bool{}
+	return context.WithValue(ctx, responseRecorderKey{}, responded), responded.Load
+}
+
+// RecordResponse records that the Cloud Foundry API responded to a request sent with the given context
+// (if the context was returned by WithResponseRecorder).
+func RecordResponse(ctx context.Context) {
+	if responded, ok := ctx.Value(responseRecorderKey{}).(*atomic.Bool); ok {
+		responded.Store(true)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package facade
+
+import "time"
+
+// ResourceCacheStatistics summarizes the Cloud Foundry resource cache (over all clients).
+type ResourceCacheStatistics struct {
+	Spaces    int
+	Instances int
+	Bindings  int
+	Hits      int64
+	Misses    int64
+}
+
+type ResourceCacheStatisticsProvider func() ResourceCacheStatistics
+
+// CacheDump describes the cached Cloud Foundry clients and the contents of their resource caches (for debugging purposes);
+// credentials and parameters of the cached resources are omitted.
+type CacheDump struct {
+	Statistics ResourceCacheStatistics `json:"statistics"`
+	Clients    []ClientCacheDump       `json:"clients"`
+}
+
+// ClientCacheDump describes one cached Cloud Foundry client (one per API endpoint and user).
+type ClientCacheDump struct {
+	URL        string    `json:"url"`
+	Username   string    `json:"username"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	// Partitions of the resource cache of the client (empty if resource caching is disabled)
+	Partitions []ResourcePartitionDump `json:"partitions,omitempty"`
+}
+
+// ResourcePartitionDump describes the cached resources of one scope (organization or space).
+type ResourcePartitionDump struct {
+	Scope     string           `json:"scope"`
+	TTL       string           `json:"ttl"`
+	Spaces    []CachedResource `json:"spaces,omitempty"`
+	Instances []CachedResource `json:"instances,omitempty"`
+	Bindings  []CachedResource `json:"bindings,omitempty"`
+}
+
+// CachedResource identifies a cached Cloud Foundry resource.
+type CachedResource struct {
+	Owner string `json:"owner"`
+	Guid  string `json:"guid"`
+	Name  string `json:"name"`
+	State string `json:"state,omitempty"`
+}
+
+type CacheDumpProvider func() CacheDump
@@ -20,3 +20,11 @@ func ObjectHash(obj map[string]interface{}) string {
 	sum := sha256.Sum256(raw)
 	return hex.EncodeToString(sum[:])
 }
+
+// TagsHash returns a hash of the given service instance tags; nil and empty tags yield the same hash.
+func TagsHash(tags []string) string {
+	if tags == nil {
+		tags = []string{}
+	}
+	return ObjectHash(map[string]interface{}{"tags": tags})
+}
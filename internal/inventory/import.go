@@ -0,0 +1,157 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+// Importer generates manifests adopting existing Cloud Foundry service instances and bindings of one space, given by guid,
+// such as instances created manually (without owner label).
+type Importer struct {
+	// Client of the Cloud Foundry space containing the imported resources
+	SpaceClient facade.SpaceClient
+	SpaceGuid   string
+	// Namespace of the generated objects
+	Namespace string
+	// Space resp. ClusterSpace object (representing the Cloud Foundry space) referenced by the generated objects; exactly one must be set
+	SpaceName        string
+	ClusterSpaceName string
+}
+
+// ImportManifests returns ServiceInstance and ServiceBinding objects adopting the service instances and bindings with the given guids
+// (by the annotations adopt-cf-instance-guid resp. adopt-cf-binding-guid). Names, service plans and instance parameters are read from
+// Cloud Foundry, such that the adopted resources are not changed by the first reconciliation. Bindings of instances which are not imported
+// reference their instance by guid. Resources which cannot be imported are reported as warnings.
+func (i *Importer) ImportManifests(ctx context.Context, guids []string) ([]client.Object, []string, error) {
+	if (i.SpaceName == "") == (i.ClusterSpaceName == "") {
+		return nil, nil, fmt.Errorf("exactly one of space name and cluster space name must be specified")
+	}
+
+	var instances []*facade.Instance
+	var bindings []*facade.Binding
+	var warnings []string
+	importedInstanceNames := make(map[string]string)
+	for _, guid := range guids {
+		instance, err := i.SpaceClient.GetInstance(ctx, map[string]string{"owner": "", "guid": guid})
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to get service instance %s", guid)
+		}
+		if instance != nil {
+			instances = append(instances, instance)
+			importedInstanceNames[instance.Guid] = instance.Name
+			continue
+		}
+		binding, err := i.SpaceClient.GetBinding(ctx, map[string]string{"owner": "", "guid": guid})
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to get service binding %s", guid)
+		}
+		if binding == nil {
+			warnings = append(warnings, fmt.Sprintf("skipping %s: neither a service instance in the space, nor a service binding", guid))
+			continue
+		}
+		bindings = append(bindings, binding)
+	}
+
+	servicePlans := make(map[string]facade.ServicePlan)
+	if len(instances) > 0 {
+		plans, err := i.SpaceClient.ListServicePlans(ctx, i.SpaceGuid)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to list service plans")
+		}
+		for _, plan := range plans {
+			servicePlans[plan.Guid] = plan
+		}
+	}
+
+	var objects []client.Object
+	for _, instance := range instances {
+		if errs := validation.IsDNS1123Subdomain(instance.Name); len(errs) > 0 {
+			warnings = append(warnings, fmt.Sprintf("skipping %s %s (%s): name is not a valid object name", ResourceKindServiceInstance, instance.Name, instance.Guid))
+			delete(importedInstanceNames, instance.Guid)
+			continue
+		}
+		serviceInstance := &cfv1alpha1.ServiceInstance{
+			TypeMeta:   metav1.TypeMeta{APIVersion: cfv1alpha1.GroupVersion.String(), Kind: string(ResourceKindServiceInstance)},
+			ObjectMeta: i.objectMeta(instance.Name, cfv1alpha1.AnnotationAdoptCFInstanceGuid, instance.Guid),
+			Spec: cfv1alpha1.ServiceInstanceSpec{
+				Name:             instance.Name,
+				SpaceName:        i.SpaceName,
+				ClusterSpaceName: i.ClusterSpaceName,
+			},
+		}
+		// prefer the (readable) service offering and plan names; the plan guid is used if the plan is not visible in the space
+		if plan, ok := servicePlans[instance.ServicePlanGuid]; ok {
+			serviceInstance.Spec.ServiceOfferingName = plan.ServiceOfferingName
+			serviceInstance.Spec.ServicePlanName = plan.Name
+		} else {
+			serviceInstance.Spec.ServicePlanGuid = instance.ServicePlanGuid
+		}
+		parameters, err := i.SpaceClient.GetInstanceParameters(ctx, instance.Guid)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s %s (%s): parameters not imported: %s", ResourceKindServiceInstance, instance.Name, instance.Guid, err))
+		} else if len(parameters) > 0 {
+			raw, err := json.Marshal(parameters)
+			if err != nil {
+				return nil, nil, err
+			}
+			serviceInstance.Spec.Parameters = &apiextensionsv1.JSON{Raw: raw}
+		}
+		objects = append(objects, serviceInstance)
+	}
+
+	for _, binding := range bindings {
+		if errs := validation.IsDNS1123Subdomain(binding.Name); len(errs) > 0 {
+			warnings = append(warnings, fmt.Sprintf("skipping %s %s (%s): name is not a valid object name", ResourceKindServiceBinding, binding.Name, binding.Guid))
+			continue
+		}
+		serviceBinding := &cfv1alpha1.ServiceBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: cfv1alpha1.GroupVersion.String(), Kind: string(ResourceKindServiceBinding)},
+			ObjectMeta: i.objectMeta(binding.Name, cfv1alpha1.AnnotationAdoptCFBindingGuid, binding.Guid),
+			Spec: cfv1alpha1.ServiceBindingSpec{
+				Name:    binding.Name,
+				AppGuid: binding.AppGuid,
+			},
+		}
+		if serviceInstanceName, ok := importedInstanceNames[binding.ServiceInstanceGuid]; ok {
+			serviceBinding.Spec.ServiceInstanceName = serviceInstanceName
+		} else {
+			// bindings are not restricted to the space by the lookup; so ensure that the service instance belongs to it
+			instance, err := i.SpaceClient.GetInstance(ctx, map[string]string{"owner": "", "guid": binding.ServiceInstanceGuid})
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "failed to get service instance %s", binding.ServiceInstanceGuid)
+			}
+			if instance == nil {
+				warnings = append(warnings, fmt.Sprintf("skipping %s %s (%s): service instance %s is not in the space", ResourceKindServiceBinding, binding.Name, binding.Guid, binding.ServiceInstanceGuid))
+				continue
+			}
+			serviceBinding.Spec.ServiceInstanceGuid = binding.ServiceInstanceGuid
+			serviceBinding.Spec.SpaceName = i.SpaceName
+			serviceBinding.Spec.ClusterSpaceName = i.ClusterSpaceName
+		}
+		objects = append(objects, serviceBinding)
+	}
+	return objects, warnings, nil
+}
+
+func (i *Importer) objectMeta(name string, annotation string, guid string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        name,
+		Namespace:   i.Namespace,
+		Annotations: map[string]string{annotation: guid},
+	}
+}
@@ -0,0 +1,93 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package inventory
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/facade"
+	"github.com/sap/cf-service-operator/internal/facade/facadefakes"
+)
+
+var _ = Describe("Import tests", func() {
+	ctx := context.Background()
+	var spaceClient *facadefakes.FakeSpaceClient
+	var importer *Importer
+
+	BeforeEach(func() {
+		spaceClient = &facadefakes.FakeSpaceClient{}
+		importer = &Importer{SpaceClient: spaceClient, SpaceGuid: "space-guid", Namespace: "ns", SpaceName: "space"}
+
+		instances := map[string]*facade.Instance{
+			"instance-guid":       {Guid: "instance-guid", Name: "instance", ServicePlanGuid: "plan-guid"},
+			"other-instance-guid": {Guid: "other-instance-guid", Name: "other-instance", ServicePlanGuid: "invisible-plan-guid"},
+		}
+		spaceClient.GetInstanceStub = func(_ context.Context, instanceOpts map[string]string) (*facade.Instance, error) {
+			return instances[instanceOpts["guid"]], nil
+		}
+		bindings := map[string]*facade.Binding{
+			"binding-guid":         {Guid: "binding-guid", Name: "binding", ServiceInstanceGuid: "instance-guid"},
+			"other-binding-guid":   {Guid: "other-binding-guid", Name: "other-binding", ServiceInstanceGuid: "other-instance-guid"},
+			"foreign-binding-guid": {Guid: "foreign-binding-guid", Name: "foreign-binding", ServiceInstanceGuid: "foreign-instance-guid"},
+		}
+		spaceClient.GetBindingStub = func(_ context.Context, bindingOpts map[string]string) (*facade.Binding, error) {
+			return bindings[bindingOpts["guid"]], nil
+		}
+		spaceClient.ListServicePlansReturns([]facade.ServicePlan{{Guid: "plan-guid", Name: "plan", ServiceOfferingName: "offering"}}, nil)
+		spaceClient.GetInstanceParametersReturns(map[string]interface{}{"size": "small"}, nil)
+	})
+
+	It("should generate manifests adopting the given resources", func() {
+		objects, warnings, err := importer.ImportManifests(ctx, []string{"binding-guid", "instance-guid", "other-binding-guid", "foreign-binding-guid", "unknown-guid"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warnings).To(ConsistOf(ContainSubstring("foreign-binding"), ContainSubstring("unknown-guid")))
+		Expect(objects).To(HaveLen(3))
+
+		serviceInstance := objects[0].(*cfv1alpha1.ServiceInstance)
+		Expect(serviceInstance.Namespace).To(Equal("ns"))
+		Expect(serviceInstance.Name).To(Equal("instance"))
+		Expect(serviceInstance.Annotations).To(Equal(map[string]string{cfv1alpha1.AnnotationAdoptCFInstanceGuid: "instance-guid"}))
+		Expect(serviceInstance.Spec).To(Equal(cfv1alpha1.ServiceInstanceSpec{
+			Name:                "instance",
+			SpaceName:           "space",
+			ServiceOfferingName: "offering",
+			ServicePlanName:     "plan",
+			Parameters:          &apiextensionsv1.JSON{Raw: []byte(`{"size":"small"}`)},
+		}))
+
+		serviceBinding := objects[1].(*cfv1alpha1.ServiceBinding)
+		Expect(serviceBinding.Annotations).To(Equal(map[string]string{cfv1alpha1.AnnotationAdoptCFBindingGuid: "binding-guid"}))
+		Expect(serviceBinding.Spec).To(Equal(cfv1alpha1.ServiceBindingSpec{Name: "binding", ServiceInstanceName: "instance"}))
+
+		// the instance of this binding is not imported
+		serviceBinding = objects[2].(*cfv1alpha1.ServiceBinding)
+		Expect(serviceBinding.Spec).To(Equal(cfv1alpha1.ServiceBindingSpec{Name: "other-binding", ServiceInstanceGuid: "other-instance-guid", SpaceName: "space"}))
+	})
+
+	It("should fall back to the service plan guid, and skip parameters which cannot be retrieved", func() {
+		spaceClient.GetInstanceParametersReturns(nil, errors.New("not retrievable"))
+
+		objects, warnings, err := importer.ImportManifests(ctx, []string{"other-instance-guid"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warnings).To(ConsistOf(ContainSubstring("parameters not imported: not retrievable")))
+		Expect(objects[0].(*cfv1alpha1.ServiceInstance).Spec).To(Equal(cfv1alpha1.ServiceInstanceSpec{
+			Name:            "other-instance",
+			SpaceName:       "space",
+			ServicePlanGuid: "invisible-plan-guid",
+		}))
+	})
+
+	It("should require exactly one space reference", func() {
+		importer.ClusterSpaceName = "cluster-space"
+		_, _, err := importer.ImportManifests(ctx, []string{"instance-guid"})
+		Expect(err).To(MatchError(ContainSubstring("exactly one")))
+	})
+})
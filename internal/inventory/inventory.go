@@ -0,0 +1,345 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package inventory lists the Cloud Foundry service instances and bindings managed through cf-service-operator,
+detects orphaned ones (existing in Cloud Foundry with an owner label, but without owning Kubernetes object),
+and generates manifests to adopt them.
+*/
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+	"github.com/sap/cf-service-operator/pkg/client/clientset/versioned"
+)
+
+// ResourceKind is the kind of Kubernetes object managing a Cloud Foundry resource.
+type ResourceKind string
+
+const (
+	ResourceKindServiceInstance ResourceKind = "ServiceInstance"
+	ResourceKindServiceBinding  ResourceKind = "ServiceBinding"
+)
+
+// SpaceReference identifies the Space or ClusterSpace object through which a Cloud Foundry resource was found.
+type SpaceReference struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Guid      string
+}
+
+// Resource is a Cloud Foundry service instance or binding carrying the owner label of cf-service-operator.
+type Resource struct {
+	Kind  ResourceKind
+	Guid  string
+	Name  string
+	State string
+	// Uid of the owning Kubernetes object, as recorded in Cloud Foundry
+	Owner string
+	// Owning Kubernetes object; nil if the resource is orphaned
+	OwnerObject *types.NamespacedName
+	// Namespace and name of the owning Kubernetes object, as recorded in Cloud Foundry; nil if not recorded
+	// (such as for resources created by older versions of the operator); tells which object owned an orphaned resource
+	RecordedOwnerObject *types.NamespacedName
+	Space               SpaceReference
+	// Service plan of service instances
+	ServicePlanGuid string
+	// Service instance of service bindings
+	ServiceInstanceGuid string
+	// Application of service bindings (app bindings only)
+	AppGuid string
+}
+
+// IsOrphaned returns true if the owning Kubernetes object of the resource does not exist (anymore).
+func (r *Resource) IsOrphaned() bool {
+	return r.OwnerObject == nil
+}
+
+// Inventory is the result of Collect().
+type Inventory struct {
+	Resources []Resource
+	// Spaces which could not be inspected, and why
+	Warnings []string
+}
+
+// Orphans returns the orphaned resources of the inventory.
+func (inv *Inventory) Orphans() []Resource {
+	var orphans []Resource
+	for _, resource := range inv.Resources {
+		if resource.IsOrphaned() {
+			orphans = append(orphans, resource)
+		}
+	}
+	return orphans
+}
+
+// Collector reads the managed Cloud Foundry resources of all spaces known to the cluster (through Space and ClusterSpace objects).
+type Collector struct {
+	Clientset                versioned.Interface
+	KubeClient               kubernetes.Interface
+	ClusterResourceNamespace string
+	ClientBuilder            facade.SpaceClientBuilder
+	Config                   *config.Config
+}
+
+// Collect lists the service instances and bindings carrying an owner label in all spaces referenced by Space or ClusterSpace objects,
+// and matches them against the existing ServiceInstance and ServiceBinding objects. Spaces which cannot be inspected are reported as warnings.
+// Each Cloud Foundry space is inspected once, even if referenced by multiple objects.
+func (c *Collector) Collect(ctx context.Context) (*Inventory, error) {
+	spaces, err := c.listSpaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	owners, err := c.listOwners(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	inv := &Inventory{}
+	for _, space := range spaces {
+		spaceClient, err := c.newSpaceClient(ctx, space)
+		if err != nil {
+			inv.Warnings = append(inv.Warnings, fmt.Sprintf("skipping %s %s: %s", space.Kind, spaceName(space), err))
+			continue
+		}
+		instances, err := spaceClient.ListInstances(ctx)
+		if err != nil {
+			inv.Warnings = append(inv.Warnings, fmt.Sprintf("skipping %s %s: %s", space.Kind, spaceName(space), err))
+			continue
+		}
+		bindings, err := spaceClient.ListBindings(ctx)
+		if err != nil {
+			inv.Warnings = append(inv.Warnings, fmt.Sprintf("skipping %s %s: %s", space.Kind, spaceName(space), err))
+			continue
+		}
+		for _, instance := range instances {
+			inv.Resources = append(inv.Resources, Resource{
+				Kind:                ResourceKindServiceInstance,
+				Guid:                instance.Guid,
+				Name:                instance.Name,
+				State:               string(instance.State),
+				Owner:               instance.Owner,
+				OwnerObject:         owners[instance.Owner],
+				RecordedOwnerObject: recordedOwnerObject(instance.OwnerNamespace, instance.OwnerName),
+				Space:               space.SpaceReference,
+				ServicePlanGuid:     instance.ServicePlanGuid,
+			})
+		}
+		for _, binding := range bindings {
+			inv.Resources = append(inv.Resources, Resource{
+				Kind:                ResourceKindServiceBinding,
+				Guid:                binding.Guid,
+				Name:                binding.Name,
+				State:               string(binding.State),
+				Owner:               binding.Owner,
+				OwnerObject:         owners[binding.Owner],
+				RecordedOwnerObject: recordedOwnerObject(binding.OwnerNamespace, binding.OwnerName),
+				Space:               space.SpaceReference,
+				ServiceInstanceGuid: binding.ServiceInstanceGuid,
+				AppGuid:             binding.AppGuid,
+			})
+		}
+	}
+	return inv, nil
+}
+
+// recordedOwnerObject returns the owning object recorded in Cloud Foundry; nil unless both namespace and name were recorded.
+func recordedOwnerObject(namespace string, name string) *types.NamespacedName {
+	if namespace == "" || name == "" {
+		return nil
+	}
+	return &types.NamespacedName{Namespace: namespace, Name: name}
+}
+
+type managedSpace struct {
+	SpaceReference
+	authSecretNamespace string
+	authSecretName      string
+	// endpoint in use for the space (status.endpoint)
+	endpoint string
+}
+
+// listSpaces returns all ready Space and ClusterSpace objects, skipping objects referring to an already listed Cloud Foundry space.
+func (c *Collector) listSpaces(ctx context.Context) ([]managedSpace, error) {
+	var spaces []managedSpace
+	seen := make(map[string]bool)
+	add := func(kind string, namespace string, name string, spec *cfv1alpha1.SpaceSpec, status *cfv1alpha1.SpaceStatus) {
+		guid := spec.Guid
+		if guid == "" {
+			guid = status.SpaceGuid
+		}
+		if guid == "" || seen[guid] {
+			return
+		}
+		seen[guid] = true
+		authSecretNamespace := namespace
+		if kind == "ClusterSpace" {
+			authSecretNamespace = c.ClusterResourceNamespace
+		}
+		authSecretName := spec.GetAuthSecretName(authSecretNamespace)
+		spaces = append(spaces, managedSpace{
+			SpaceReference:      SpaceReference{Kind: kind, Namespace: namespace, Name: name, Guid: guid},
+			authSecretNamespace: authSecretName.Namespace,
+			authSecretName:      authSecretName.Name,
+			endpoint:            status.Endpoint,
+		})
+	}
+
+	spaceList, err := c.Clientset.CfV1alpha1().Spaces(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list Spaces")
+	}
+	for i := range spaceList.Items {
+		space := &spaceList.Items[i]
+		add("Space", space.Namespace, space.Name, &space.Spec, &space.Status)
+	}
+	clusterSpaceList, err := c.Clientset.CfV1alpha1().ClusterSpaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list ClusterSpaces")
+	}
+	for i := range clusterSpaceList.Items {
+		clusterSpace := &clusterSpaceList.Items[i]
+		add("ClusterSpace", "", clusterSpace.Name, &clusterSpace.Spec, &clusterSpace.Status)
+	}
+	return spaces, nil
+}
+
+// listOwners returns all ServiceInstance and ServiceBinding objects, by uid.
+func (c *Collector) listOwners(ctx context.Context) (map[string]*types.NamespacedName, error) {
+	owners := make(map[string]*types.NamespacedName)
+	serviceInstanceList, err := c.Clientset.CfV1alpha1().ServiceInstances(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list ServiceInstances")
+	}
+	for _, serviceInstance := range serviceInstanceList.Items {
+		owners[string(serviceInstance.UID)] = &types.NamespacedName{Namespace: serviceInstance.Namespace, Name: serviceInstance.Name}
+	}
+	serviceBindingList, err := c.Clientset.CfV1alpha1().ServiceBindings(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list ServiceBindings")
+	}
+	for _, serviceBinding := range serviceBindingList.Items {
+		owners[string(serviceBinding.UID)] = &types.NamespacedName{Namespace: serviceBinding.Namespace, Name: serviceBinding.Name}
+	}
+	return owners, nil
+}
+
+func (c *Collector) newSpaceClient(ctx context.Context, space managedSpace) (facade.SpaceClient, error) {
+	secret, err := c.KubeClient.CoreV1().Secrets(space.authSecretNamespace).Get(ctx, space.authSecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get Secret containing space credentials, secret name: %s/%s", space.authSecretNamespace, space.authSecretName)
+	}
+	cfg := c.Config.WithConnectionOverrides(string(secret.Data["ca.crt"]), string(secret.Data["proxy"]))
+	url := (&cfv1alpha1.SpaceStatus{Endpoint: space.endpoint}).GetEndpoint(secret.Data, nil)
+	return c.ClientBuilder(space.Guid, url, string(secret.Data["username"]), string(secret.Data["password"]), cfg)
+}
+
+// AdoptionManifests returns ServiceInstance and ServiceBinding objects adopting the given orphaned resources (other resources are ignored);
+// resources found through a ClusterSpace are adopted in the given namespace. The objects are named like the Cloud Foundry resources,
+// since adoption matches by name; resources which cannot be adopted are reported as warnings.
+func AdoptionManifests(inv *Inventory, namespace string) ([]client.Object, []string) {
+	var objects []client.Object
+	var warnings []string
+
+	instancesByGuid := make(map[string]*Resource)
+	for i := range inv.Resources {
+		if resource := &inv.Resources[i]; resource.Kind == ResourceKindServiceInstance {
+			instancesByGuid[resource.Guid] = resource
+		}
+	}
+	targetNamespace := func(resource *Resource) string {
+		if resource.Space.Kind == "ClusterSpace" {
+			return namespace
+		}
+		return resource.Space.Namespace
+	}
+
+	for i := range inv.Resources {
+		resource := &inv.Resources[i]
+		if !resource.IsOrphaned() {
+			continue
+		}
+		if errs := validation.IsDNS1123Subdomain(resource.Name); len(errs) > 0 {
+			warnings = append(warnings, fmt.Sprintf("skipping %s %s (%s): name is not a valid object name", resource.Kind, resource.Name, resource.Guid))
+			continue
+		}
+		objectMeta := metav1.ObjectMeta{
+			Name:      resource.Name,
+			Namespace: targetNamespace(resource),
+			Annotations: map[string]string{
+				cfv1alpha1.AnnotationAdoptCFResources: "adopt",
+			},
+		}
+
+		switch resource.Kind {
+		case ResourceKindServiceInstance:
+			serviceInstance := &cfv1alpha1.ServiceInstance{
+				TypeMeta:   metav1.TypeMeta{APIVersion: cfv1alpha1.GroupVersion.String(), Kind: string(ResourceKindServiceInstance)},
+				ObjectMeta: objectMeta,
+				Spec: cfv1alpha1.ServiceInstanceSpec{
+					Name:            resource.Name,
+					ServicePlanGuid: resource.ServicePlanGuid,
+				},
+			}
+			if resource.Space.Kind == "ClusterSpace" {
+				serviceInstance.Spec.ClusterSpaceName = resource.Space.Name
+			} else {
+				serviceInstance.Spec.SpaceName = resource.Space.Name
+			}
+			objects = append(objects, serviceInstance)
+		case ResourceKindServiceBinding:
+			instance, ok := instancesByGuid[resource.ServiceInstanceGuid]
+			if !ok {
+				warnings = append(warnings, fmt.Sprintf("skipping %s %s (%s): service instance %s is not managed", resource.Kind, resource.Name, resource.Guid, resource.ServiceInstanceGuid))
+				continue
+			}
+			serviceInstanceName := instance.Name
+			if !instance.IsOrphaned() {
+				if instance.OwnerObject.Namespace != objectMeta.Namespace {
+					warnings = append(warnings, fmt.Sprintf("skipping %s %s (%s): service instance is owned by %s", resource.Kind, resource.Name, resource.Guid, instance.OwnerObject))
+					continue
+				}
+				serviceInstanceName = instance.OwnerObject.Name
+			}
+			objects = append(objects, &cfv1alpha1.ServiceBinding{
+				TypeMeta:   metav1.TypeMeta{APIVersion: cfv1alpha1.GroupVersion.String(), Kind: string(ResourceKindServiceBinding)},
+				ObjectMeta: objectMeta,
+				Spec: cfv1alpha1.ServiceBindingSpec{
+					Name:                resource.Name,
+					ServiceInstanceName: serviceInstanceName,
+					AppGuid:             resource.AppGuid,
+				},
+			})
+		}
+	}
+
+	// service instances first, such that they exist when the bindings are applied
+	sort.SliceStable(objects, func(i, j int) bool {
+		return objects[i].GetObjectKind().GroupVersionKind().Kind == string(ResourceKindServiceInstance) &&
+			objects[j].GetObjectKind().GroupVersionKind().Kind != string(ResourceKindServiceInstance)
+	})
+	return objects, warnings
+}
+
+func spaceName(space managedSpace) string {
+	if space.Namespace == "" {
+		return space.Name
+	}
+	return space.Namespace + "/" + space.Name
+}
@@ -0,0 +1,120 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package inventory
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+	"github.com/sap/cf-service-operator/internal/facade/facadefakes"
+	"github.com/sap/cf-service-operator/pkg/client/clientset/versioned/fake"
+)
+
+func TestInventory(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Inventory Test Suite")
+}
+
+// -----------------------------------------------------------------------------------------------
+// Tests
+// -----------------------------------------------------------------------------------------------
+
+var _ = Describe("Inventory tests", func() {
+	ctx := context.Background()
+	var spaceClient *facadefakes.FakeSpaceClient
+	var collector *Collector
+
+	BeforeEach(func() {
+		spaceClient = &facadefakes.FakeSpaceClient{}
+		collector = &Collector{
+			Clientset: fake.NewSimpleClientset(
+				&cfv1alpha1.Space{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space"},
+					Spec:       cfv1alpha1.SpaceSpec{Guid: "space-guid", AuthSecretName: "space-secret"},
+				},
+				// refers to the same Cloud Foundry space
+				&cfv1alpha1.Space{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "other-ns", Name: "space"},
+					Spec:       cfv1alpha1.SpaceSpec{Guid: "space-guid", AuthSecretName: "space-secret"},
+				},
+				&cfv1alpha1.ServiceInstance{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "instance", UID: "instance-uid"},
+				},
+			),
+			KubeClient: kubefake.NewSimpleClientset(
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "space-secret"},
+					Data:       map[string][]byte{"url": []byte("https://api.cf.example.com"), "username": []byte("user"), "password": []byte("pass")},
+				},
+			),
+			ClientBuilder: func(spaceGuid string, url string, username string, password string, cfg *config.Config) (facade.SpaceClient, error) {
+				Expect([]string{spaceGuid, url, username, password}).To(Equal([]string{"space-guid", "https://api.cf.example.com", "user", "pass"}))
+				return spaceClient, nil
+			},
+			Config: config.Defaults(),
+		}
+		spaceClient.ListInstancesReturns([]*facade.Instance{
+			{Guid: "instance-guid", Name: "instance", Owner: "instance-uid", State: facade.InstanceStateReady},
+			{Guid: "orphaned-instance-guid", Name: "orphaned-instance", ServicePlanGuid: "plan-guid", Owner: "deleted-uid", OwnerNamespace: "ns", OwnerName: "deleted-instance", State: facade.InstanceStateReady},
+		}, nil)
+		spaceClient.ListBindingsReturns([]*facade.Binding{
+			{Guid: "binding-guid", Name: "binding", ServiceInstanceGuid: "instance-guid", AppGuid: "app-guid", Owner: "deleted-uid-2", State: facade.BindingStateReady},
+			{Guid: "foreign-binding-guid", Name: "foreign-binding", ServiceInstanceGuid: "unmanaged-instance-guid", Owner: "deleted-uid-3", State: facade.BindingStateReady},
+		}, nil)
+	})
+
+	It("should list managed resources and detect orphans", func() {
+		inv, err := collector.Collect(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inv.Warnings).To(BeEmpty())
+		Expect(spaceClient.ListInstancesCallCount()).To(Equal(1))
+
+		Expect(inv.Resources).To(HaveLen(4))
+		Expect(inv.Resources[0].OwnerObject).To(Equal(&types.NamespacedName{Namespace: "ns", Name: "instance"}))
+		Expect(inv.Resources[0].Space).To(Equal(SpaceReference{Kind: "Space", Namespace: "ns", Name: "space", Guid: "space-guid"}))
+		Expect(inv.Orphans()).To(HaveLen(3))
+		Expect(inv.Resources[0].RecordedOwnerObject).To(BeNil())
+		Expect(inv.Resources[1].RecordedOwnerObject).To(Equal(&types.NamespacedName{Namespace: "ns", Name: "deleted-instance"}))
+	})
+
+	It("should generate adoption manifests for orphans", func() {
+		inv, err := collector.Collect(ctx)
+		Expect(err).ToNot(HaveOccurred())
+
+		objects, warnings := AdoptionManifests(inv, "default")
+		Expect(warnings).To(ConsistOf(ContainSubstring("foreign-binding")))
+		Expect(objects).To(HaveLen(2))
+
+		serviceInstance := objects[0].(*cfv1alpha1.ServiceInstance)
+		Expect(serviceInstance.Namespace).To(Equal("ns"))
+		Expect(serviceInstance.Name).To(Equal("orphaned-instance"))
+		Expect(serviceInstance.Annotations).To(HaveKeyWithValue(cfv1alpha1.AnnotationAdoptCFResources, "adopt"))
+		Expect(serviceInstance.Spec).To(Equal(cfv1alpha1.ServiceInstanceSpec{Name: "orphaned-instance", SpaceName: "space", ServicePlanGuid: "plan-guid"}))
+
+		serviceBinding := objects[1].(*cfv1alpha1.ServiceBinding)
+		Expect(serviceBinding.Namespace).To(Equal("ns"))
+		// app bindings are adopted as app bindings (the binding type is defaulted from spec.appGuid)
+		Expect(serviceBinding.Spec).To(Equal(cfv1alpha1.ServiceBindingSpec{Name: "binding", ServiceInstanceName: "instance", AppGuid: "app-guid"}))
+	})
+
+	It("should skip spaces which cannot be inspected", func() {
+		collector.KubeClient = kubefake.NewSimpleClientset()
+
+		inv, err := collector.Collect(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inv.Resources).To(BeEmpty())
+		Expect(inv.Warnings).To(ConsistOf(ContainSubstring("skipping Space ns/space")))
+	})
+})
@@ -0,0 +1,279 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package migration converts resources of other service operators into equivalent cf-service-operator resources.
+*/
+package migration
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+)
+
+// API group of the SAP BTP service operator (github.com/SAP/sap-btp-service-operator)
+const btpGroup = "services.cloud.sap.com"
+
+// Options control the conversion of SAP BTP service operator resources.
+type Options struct {
+	// Name of the Space (in the namespace of the converted resources) referenced by the generated service instances.
+	SpaceName string
+	// Name of the ClusterSpace referenced by the generated service instances; exactly one of SpaceName and ClusterSpaceName must be set.
+	ClusterSpaceName string
+}
+
+// Result holds the generated resources, and warnings about settings which could not be converted.
+type Result struct {
+	Objects  []client.Object
+	Warnings []string
+}
+
+// subset of the SAP BTP service operator API (services.cloud.sap.com/v1)
+type btpParametersFromSource struct {
+	SecretKeyRef *cfv1alpha1.SecretKeyReference `json:"secretKeyRef,omitempty"`
+}
+
+type btpServiceInstanceSpec struct {
+	ServiceOfferingName string                    `json:"serviceOfferingName"`
+	ServicePlanName     string                    `json:"servicePlanName"`
+	ExternalName        string                    `json:"externalName,omitempty"`
+	Parameters          *apiextensionsv1.JSON     `json:"parameters,omitempty"`
+	ParametersFrom      []btpParametersFromSource `json:"parametersFrom,omitempty"`
+	CustomTags          []string                  `json:"customTags,omitempty"`
+	Shared              *bool                     `json:"shared,omitempty"`
+}
+
+type btpServiceBindingSpec struct {
+	ServiceInstanceName string                    `json:"serviceInstanceName"`
+	ExternalName        string                    `json:"externalName,omitempty"`
+	SecretName          string                    `json:"secretName,omitempty"`
+	SecretKey           *string                   `json:"secretKey,omitempty"`
+	SecretRootKey       *string                   `json:"secretRootKey,omitempty"`
+	SecretTemplate      string                    `json:"secretTemplate,omitempty"`
+	Parameters          *apiextensionsv1.JSON     `json:"parameters,omitempty"`
+	ParametersFrom      []btpParametersFromSource `json:"parametersFrom,omitempty"`
+	CredRotationPolicy  *json.RawMessage          `json:"credRotationPolicy,omitempty"`
+}
+
+// ReadObjects reads all Kubernetes objects from the given YAML or JSON stream (which may contain multiple documents, and List objects).
+func ReadObjects(r io.Reader) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bufio.NewReader(r), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, errors.Wrap(err, "error decoding input")
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if obj.IsList() {
+			if err := obj.EachListItem(func(item runtime.Object) error {
+				objects = append(objects, item.(*unstructured.Unstructured))
+				return nil
+			}); err != nil {
+				return nil, errors.Wrap(err, "error decoding list")
+			}
+			continue
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// WriteObjects writes the given objects as multi-document YAML stream, omitting status and server-populated metadata.
+func WriteObjects(w io.Writer, objects []client.Object) error {
+	for i, obj := range objects {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return errors.Wrapf(err, "error converting %s", obj.GetName())
+		}
+		delete(content, "status")
+		unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+		raw, err := yaml.Marshal(content)
+		if err != nil {
+			return errors.Wrapf(err, "error encoding %s", obj.GetName())
+		}
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConvertBTPObjects converts SAP BTP service operator ServiceInstance and ServiceBinding objects into
+// cf-service-operator ServiceInstance and ServiceBinding objects; other objects are skipped.
+// The generated objects carry the adopt-cf-resources annotation, such that the operator takes over
+// the existing Cloud Foundry instances and bindings (matched by name) instead of creating new ones.
+func ConvertBTPObjects(objects []*unstructured.Unstructured, options Options) (*Result, error) {
+	if (options.SpaceName == "") == (options.ClusterSpaceName == "") {
+		return nil, fmt.Errorf("exactly one of space name and cluster space name must be specified")
+	}
+
+	result := &Result{}
+	for _, obj := range objects {
+		gvk := obj.GroupVersionKind()
+		if gvk.Group != btpGroup {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("skipping %s %s: not a SAP BTP service operator resource", gvk.Kind, objectName(obj)))
+			continue
+		}
+		switch gvk.Kind {
+		case "ServiceInstance":
+			serviceInstance, warnings, err := convertBTPServiceInstance(obj, options)
+			if err != nil {
+				return nil, err
+			}
+			result.Objects = append(result.Objects, serviceInstance)
+			result.Warnings = append(result.Warnings, warnings...)
+		case "ServiceBinding":
+			serviceBinding, warnings, err := convertBTPServiceBinding(obj)
+			if err != nil {
+				return nil, err
+			}
+			result.Objects = append(result.Objects, serviceBinding)
+			result.Warnings = append(result.Warnings, warnings...)
+		default:
+			result.Warnings = append(result.Warnings, fmt.Sprintf("skipping %s %s: unsupported kind", gvk.Kind, objectName(obj)))
+		}
+	}
+	return result, nil
+}
+
+func convertBTPServiceInstance(obj *unstructured.Unstructured, options Options) (*cfv1alpha1.ServiceInstance, []string, error) {
+	spec := &btpServiceInstanceSpec{}
+	if err := decodeSpec(obj, spec); err != nil {
+		return nil, nil, err
+	}
+
+	var warnings []string
+	if spec.Shared != nil && *spec.Shared {
+		warnings = append(warnings, fmt.Sprintf("ServiceInstance %s: spec.shared is not supported and was dropped", objectName(obj)))
+	}
+
+	name := spec.ExternalName
+	if name == "" {
+		name = obj.GetName()
+	}
+	serviceInstance := &cfv1alpha1.ServiceInstance{
+		TypeMeta:   metav1.TypeMeta{APIVersion: cfv1alpha1.GroupVersion.String(), Kind: "ServiceInstance"},
+		ObjectMeta: convertObjectMeta(obj),
+		Spec: cfv1alpha1.ServiceInstanceSpec{
+			Name:                name,
+			SpaceName:           options.SpaceName,
+			ClusterSpaceName:    options.ClusterSpaceName,
+			ServiceOfferingName: spec.ServiceOfferingName,
+			ServicePlanName:     spec.ServicePlanName,
+			Parameters:          spec.Parameters,
+			ParametersFrom:      convertParametersFrom(spec.ParametersFrom),
+			Tags:                spec.CustomTags,
+		},
+	}
+	return serviceInstance, warnings, nil
+}
+
+func convertBTPServiceBinding(obj *unstructured.Unstructured) (*cfv1alpha1.ServiceBinding, []string, error) {
+	spec := &btpServiceBindingSpec{}
+	if err := decodeSpec(obj, spec); err != nil {
+		return nil, nil, err
+	}
+
+	var warnings []string
+	if spec.SecretRootKey != nil {
+		warnings = append(warnings, fmt.Sprintf("ServiceBinding %s: spec.secretRootKey is not supported; using it as secret key, without binding metadata", objectName(obj)))
+	}
+	if spec.SecretTemplate != "" {
+		warnings = append(warnings, fmt.Sprintf("ServiceBinding %s: spec.secretTemplate is not supported and was dropped", objectName(obj)))
+	}
+	if spec.CredRotationPolicy != nil {
+		warnings = append(warnings, fmt.Sprintf("ServiceBinding %s: spec.credRotationPolicy is not supported and was dropped", objectName(obj)))
+	}
+
+	name := spec.ExternalName
+	if name == "" {
+		name = obj.GetName()
+	}
+	secretKey := ""
+	if spec.SecretKey != nil {
+		secretKey = *spec.SecretKey
+	} else if spec.SecretRootKey != nil {
+		secretKey = *spec.SecretRootKey
+	}
+	serviceBinding := &cfv1alpha1.ServiceBinding{
+		TypeMeta:   metav1.TypeMeta{APIVersion: cfv1alpha1.GroupVersion.String(), Kind: "ServiceBinding"},
+		ObjectMeta: convertObjectMeta(obj),
+		Spec: cfv1alpha1.ServiceBindingSpec{
+			Name:                name,
+			ServiceInstanceName: spec.ServiceInstanceName,
+			Parameters:          spec.Parameters,
+			ParametersFrom:      convertParametersFrom(spec.ParametersFrom),
+			SecretName:          spec.SecretName,
+			SecretKey:           secretKey,
+		},
+	}
+	return serviceBinding, warnings, nil
+}
+
+func decodeSpec(obj *unstructured.Unstructured, spec any) error {
+	rawSpec, ok := obj.Object["spec"]
+	if !ok {
+		return fmt.Errorf("%s %s has no spec", obj.GetKind(), objectName(obj))
+	}
+	raw, err := json.Marshal(rawSpec)
+	if err != nil {
+		return errors.Wrapf(err, "error encoding spec of %s %s", obj.GetKind(), objectName(obj))
+	}
+	if err := json.Unmarshal(raw, spec); err != nil {
+		return errors.Wrapf(err, "error decoding spec of %s %s", obj.GetKind(), objectName(obj))
+	}
+	return nil
+}
+
+// convertObjectMeta keeps name, namespace and labels, and adds the annotation to adopt the existing Cloud Foundry resource.
+func convertObjectMeta(obj *unstructured.Unstructured) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Labels:    obj.GetLabels(),
+		Annotations: map[string]string{
+			cfv1alpha1.AnnotationAdoptCFResources: "adopt",
+		},
+	}
+}
+
+func convertParametersFrom(parametersFrom []btpParametersFromSource) []cfv1alpha1.ParametersFromSource {
+	var result []cfv1alpha1.ParametersFromSource
+	for _, pf := range parametersFrom {
+		result = append(result, cfv1alpha1.ParametersFromSource{SecretKeyRef: pf.SecretKeyRef})
+	}
+	return result
+}
+
+func objectName(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
@@ -0,0 +1,128 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+package migration
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+)
+
+func TestMigration(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Migration Test Suite")
+}
+
+// -----------------------------------------------------------------------------------------------
+// Tests
+// -----------------------------------------------------------------------------------------------
+
+const btpObjects = `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: services.cloud.sap.com/v1
+  kind: ServiceInstance
+  metadata:
+    name: my-instance
+    namespace: demo
+    labels:
+      app: demo
+  spec:
+    serviceOfferingName: xsuaa
+    servicePlanName: application
+    externalName: my-cf-instance
+    parameters:
+      xsappname: demo
+    parametersFrom:
+    - secretKeyRef:
+        name: instance-parameters
+        key: parameters
+    customTags:
+    - demo
+    shared: true
+  status:
+    ready: "True"
+---
+apiVersion: services.cloud.sap.com/v1
+kind: ServiceBinding
+metadata:
+  name: my-binding
+  namespace: demo
+spec:
+  serviceInstanceName: my-instance
+  secretName: my-secret
+  secretRootKey: credentials
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other
+  namespace: demo
+`
+
+var _ = Describe("BTP migration tests", func() {
+	It("should convert service instances and bindings", func() {
+		objects, err := ReadObjects(strings.NewReader(btpObjects))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(objects).To(HaveLen(3))
+
+		result, err := ConvertBTPObjects(objects, Options{SpaceName: "my-space"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Objects).To(HaveLen(2))
+		Expect(result.Warnings).To(HaveLen(3))
+
+		serviceInstance := result.Objects[0].(*cfv1alpha1.ServiceInstance)
+		Expect(serviceInstance.Namespace).To(Equal("demo"))
+		Expect(serviceInstance.Name).To(Equal("my-instance"))
+		Expect(serviceInstance.Labels).To(Equal(map[string]string{"app": "demo"}))
+		Expect(serviceInstance.Annotations).To(HaveKeyWithValue(cfv1alpha1.AnnotationAdoptCFResources, "adopt"))
+		Expect(serviceInstance.Spec.Name).To(Equal("my-cf-instance"))
+		Expect(serviceInstance.Spec.SpaceName).To(Equal("my-space"))
+		Expect(serviceInstance.Spec.ServiceOfferingName).To(Equal("xsuaa"))
+		Expect(serviceInstance.Spec.ServicePlanName).To(Equal("application"))
+		Expect(string(serviceInstance.Spec.Parameters.Raw)).To(MatchJSON(`{"xsappname":"demo"}`))
+		Expect(serviceInstance.Spec.ParametersFrom).To(Equal([]cfv1alpha1.ParametersFromSource{
+			{SecretKeyRef: &cfv1alpha1.SecretKeyReference{Name: "instance-parameters", Key: "parameters"}},
+		}))
+		Expect(serviceInstance.Spec.Tags).To(Equal([]string{"demo"}))
+
+		serviceBinding := result.Objects[1].(*cfv1alpha1.ServiceBinding)
+		Expect(serviceBinding.Spec.Name).To(Equal("my-binding"))
+		Expect(serviceBinding.Spec.ServiceInstanceName).To(Equal("my-instance"))
+		Expect(serviceBinding.Spec.SecretName).To(Equal("my-secret"))
+		Expect(serviceBinding.Spec.SecretKey).To(Equal("credentials"))
+	})
+
+	It("should require exactly one space reference", func() {
+		_, err := ConvertBTPObjects(nil, Options{})
+		Expect(err).To(HaveOccurred())
+		_, err = ConvertBTPObjects(nil, Options{SpaceName: "space", ClusterSpaceName: "space"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should write the generated objects without status", func() {
+		objects, err := ReadObjects(strings.NewReader(btpObjects))
+		Expect(err).ToNot(HaveOccurred())
+		result, err := ConvertBTPObjects(objects, Options{ClusterSpaceName: "my-space"})
+		Expect(err).ToNot(HaveOccurred())
+
+		buf := &bytes.Buffer{}
+		Expect(WriteObjects(buf, result.Objects)).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("kind: ServiceInstance\n"))
+		Expect(buf.String()).To(ContainSubstring("\n---\n"))
+		Expect(buf.String()).ToNot(ContainSubstring("status:"))
+		Expect(buf.String()).ToNot(ContainSubstring("creationTimestamp"))
+
+		written, err := ReadObjects(buf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(written).To(HaveLen(2))
+	})
+})
@@ -0,0 +1,188 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhookcert
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	caValidity          = 10 * 365 * 24 * time.Hour
+	caRenewBefore       = 365 * 24 * time.Hour
+	certificateValidity = 365 * 24 * time.Hour
+	// renewing the serving certificate well before expiry leaves time for all replicas to pick it up
+	certificateRenewBefore = 30 * 24 * time.Hour
+)
+
+// renew returns the data of a self-signed certificate secret, based on the given secret (which may be nil);
+// the CA is renewed if it expires within caRenewBefore, the serving certificate if it expires within certificateRenewBefore,
+// was not issued by the current CA, or does not match the given DNS names. The returned flag tells whether anything changed.
+// After a CA renewal, the CA bundle (ca.crt) contains the previous CA as well (as long as it is valid), such that clients
+// still accept the previous serving certificate until all replicas switched to the new one.
+func renew(secret *corev1.Secret, dnsNames []string, now time.Time) (map[string][]byte, bool, error) {
+	var data map[string][]byte
+	if secret != nil {
+		data = secret.Data
+	}
+
+	ca, caKey := parseCA(data)
+	caBundle := data[secretKeyCACertificate]
+	caRenewed := false
+	if ca == nil || now.Add(caRenewBefore).After(ca.NotAfter) {
+		newCA, newCAKey, err := generateCA(now)
+		if err != nil {
+			return nil, false, err
+		}
+		newCABundle := encodeCertificate(newCA)
+		if ca != nil && now.Before(ca.NotAfter) {
+			newCABundle = append(newCABundle, encodeCertificate(ca)...)
+		}
+		ca, caKey, caBundle, caRenewed = newCA, newCAKey, newCABundle, true
+	}
+
+	certificate := parseCertificate(data[secretKeyCertificate])
+	if !caRenewed && certificate != nil && certificate.CheckSignatureFrom(ca) == nil &&
+		now.Add(certificateRenewBefore).Before(certificate.NotAfter) && slices.Equal(certificate.DNSNames, dnsNames) {
+		return data, false, nil
+	}
+
+	certificatePEM, keyPEM, err := generateCertificate(ca, caKey, dnsNames, now)
+	if err != nil {
+		return nil, false, err
+	}
+	caKeyPEM, err := encodePrivateKey(caKey)
+	if err != nil {
+		return nil, false, err
+	}
+	return map[string][]byte{
+		secretKeyCertificate:   certificatePEM,
+		secretKeyPrivateKey:    keyPEM,
+		secretKeyCACertificate: caBundle,
+		secretKeyCAPrivateKey:  caKeyPEM,
+	}, true, nil
+}
+
+// parseCA returns the CA (the first certificate of ca.crt) and its private key, or nil if they are missing or invalid.
+func parseCA(data map[string][]byte) (*x509.Certificate, crypto.Signer) {
+	ca := parseCertificate(data[secretKeyCACertificate])
+	block, _ := pem.Decode(data[secretKeyCAPrivateKey])
+	if ca == nil || block == nil {
+		return nil, nil
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil || !key.PublicKey.Equal(ca.PublicKey) {
+		return nil, nil
+	}
+	return ca, key
+}
+
+// parseCertificate returns the first certificate of the given PEM data, or nil if there is none.
+func parseCertificate(data []byte) *x509.Certificate {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil
+	}
+	return certificate
+}
+
+func generateCA(now time.Time) (*x509.Certificate, crypto.Signer, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate CA private key")
+	}
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: fmt.Sprintf("cf-service-operator-webhook-ca@%d", now.Unix())},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create CA certificate")
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse CA certificate")
+	}
+	return ca, key, nil
+}
+
+func generateCertificate(ca *x509.Certificate, caKey crypto.Signer, dnsNames []string, now time.Time) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate private key")
+	}
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(certificateValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, key.Public(), caKey)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create certificate")
+	}
+	keyPEM, err := encodePrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM, nil
+}
+
+func newSerialNumber() (*big.Int, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate serial number")
+	}
+	return serialNumber, nil
+}
+
+func encodeCertificate(certificate *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})
+}
+
+func encodePrivateKey(key crypto.Signer) ([]byte, error) {
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	der, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode private key")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
@@ -0,0 +1,270 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package webhookcert provides the serving certificate of the webhook server, either self-signed (generated and rotated
+by the operator), or issued by cert-manager; in both cases, the certificate is read from a secret, written to the
+certificate directory of the webhook server, and the CA bundle of the webhook configurations is kept up to date.
+*/
+package webhookcert
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations;mutatingwebhookconfigurations,verbs=get;list;watch;update;patch
+
+// Mode defines where the serving certificate of the webhook server comes from.
+type Mode string
+
+const (
+	// The certificate is provided as files in the certificate directory (e.g. by mounting a secret); nothing is done by the operator.
+	ModeFiles Mode = "files"
+	// The operator generates a self-signed CA and serving certificate, stores them in a secret, and rotates them before expiry.
+	ModeSelfSigned Mode = "self-signed"
+	// The certificate is read from the secret maintained by cert-manager for a Certificate object.
+	ModeCertManager Mode = "cert-manager"
+)
+
+// ParseMode checks that the given string denotes a supported mode.
+func ParseMode(s string) (Mode, error) {
+	switch mode := Mode(s); mode {
+	case ModeFiles, ModeSelfSigned, ModeCertManager:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid webhook certificate mode %q: must be one of %s, %s, %s", s, ModeFiles, ModeSelfSigned, ModeCertManager)
+	}
+}
+
+const (
+	// keys of the certificate secret (compatible with secrets of type kubernetes.io/tls, as created by cert-manager)
+	secretKeyCertificate   = corev1.TLSCertKey
+	secretKeyPrivateKey    = corev1.TLSPrivateKeyKey
+	secretKeyCACertificate = "ca.crt"
+	// additional key of self-signed certificate secrets, to issue further serving certificates with the same CA
+	secretKeyCAPrivateKey = "ca.key"
+
+	defaultCheckInterval = 10 * time.Minute
+)
+
+// Provisioner keeps the serving certificate of the webhook server up to date.
+// Setup must be called before the webhook server is started (since the server requires the certificate files to exist);
+// afterwards, the provisioner is added to the manager as runnable, which repeats the setup in regular intervals.
+type Provisioner struct {
+	// Client used to read and write the secret and the webhook configurations; must not be a cached client,
+	// since Setup is called before the manager (and its cache) is started.
+	Client client.Client
+	Mode   Mode
+	// Namespace of the certificate secret and the webhook service.
+	Namespace string
+	// Name of the secret containing the certificate (created by the operator in self-signed mode).
+	SecretName string
+	// Name of the service through which the webhooks are called; used as DNS name of self-signed certificates,
+	// and to identify the webhooks whose CA bundle is maintained.
+	ServiceName string
+	// Directory to which the certificate is written, as tls.crt and tls.key.
+	CertDir string
+	// Interval in which the certificate is checked; defaults to 10 minutes.
+	CheckInterval time.Duration
+
+	// for testing
+	now func() time.Time
+}
+
+// Setup provides the certificate in the certificate directory, generating (or renewing) it first if required,
+// and updates the CA bundle of the webhook configurations. Nothing is done in ModeFiles.
+func (p *Provisioner) Setup(ctx context.Context) error {
+	if p.Mode == ModeFiles || p.Mode == "" {
+		return nil
+	}
+
+	secret, err := p.getSecret(ctx)
+	if err != nil {
+		return err
+	}
+	if err := p.writeFiles(secret); err != nil {
+		return err
+	}
+	if caBundle := secret.Data[secretKeyCACertificate]; len(caBundle) > 0 {
+		if err := p.updateCABundles(ctx, caBundle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start repeats the setup in regular intervals, until the context is cancelled.
+// Implements manager.Runnable; errors are logged, and the previous certificate stays in use.
+func (p *Provisioner) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("webhookcert")
+	interval := p.CheckInterval
+	if interval <= 0 {
+		interval = defaultCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := p.Setup(ctx); err != nil {
+				log.Error(err, "failed to update webhook certificate")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; all replicas serve webhooks, and therefore need the certificate.
+func (p *Provisioner) NeedLeaderElection() bool {
+	return false
+}
+
+// getSecret returns the certificate secret; in self-signed mode, the secret is created (or renewed) if required.
+// Concurrent updates by other replicas are resolved by re-reading the secret.
+func (p *Provisioner) getSecret(ctx context.Context) (*corev1.Secret, error) {
+	secretName := types.NamespacedName{Namespace: p.Namespace, Name: p.SecretName}
+	for attempt := 0; ; attempt++ {
+		secret := &corev1.Secret{}
+		if err := p.Client.Get(ctx, secretName, secret); err != nil {
+			if !apierrors.IsNotFound(err) || p.Mode != ModeSelfSigned {
+				return nil, errors.Wrapf(err, "failed to get webhook certificate secret %s", secretName)
+			}
+			secret = nil
+		}
+		if p.Mode != ModeSelfSigned {
+			if len(secret.Data[secretKeyCertificate]) == 0 || len(secret.Data[secretKeyPrivateKey]) == 0 {
+				return nil, fmt.Errorf("webhook certificate secret %s does not contain %s and %s (yet)", secretName, secretKeyCertificate, secretKeyPrivateKey)
+			}
+			return secret, nil
+		}
+
+		data, changed, err := renew(secret, p.dnsNames(), p.currentTime())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to generate webhook certificate")
+		}
+		if !changed {
+			return secret, nil
+		}
+		if secret == nil {
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: p.Namespace, Name: p.SecretName},
+				Type:       corev1.SecretTypeTLS,
+				Data:       data,
+			}
+			err = p.Client.Create(ctx, secret)
+		} else {
+			secret.Data = data
+			err = p.Client.Update(ctx, secret)
+		}
+		if err == nil {
+			log.FromContext(ctx).Info("Issued new self-signed webhook certificate", "secret", secretName.String())
+			return secret, nil
+		}
+		if !(apierrors.IsAlreadyExists(err) || apierrors.IsConflict(err)) || attempt > 0 {
+			return nil, errors.Wrapf(err, "failed to store webhook certificate secret %s", secretName)
+		}
+		// another replica was faster; use its certificate
+	}
+}
+
+// writeFiles writes certificate and private key to the certificate directory (if they changed).
+func (p *Provisioner) writeFiles(secret *corev1.Secret) error {
+	if err := os.MkdirAll(p.CertDir, 0o755); err != nil {
+		return errors.Wrapf(err, "failed to create webhook certificate directory %s", p.CertDir)
+	}
+	// the private key is written first: the webhook server reloads the certificate on changes of the certificate file
+	for _, key := range []string{secretKeyPrivateKey, secretKeyCertificate} {
+		path := filepath.Join(p.CertDir, key)
+		if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, secret.Data[key]) {
+			continue
+		}
+		if err := os.WriteFile(path, secret.Data[key], 0o600); err != nil {
+			return errors.Wrapf(err, "failed to write webhook certificate file %s", path)
+		}
+	}
+	return nil
+}
+
+// updateCABundles sets the CA bundle of all webhooks called through the webhook service.
+func (p *Provisioner) updateCABundles(ctx context.Context, caBundle []byte) error {
+	validatingWebhookConfigurations := &admissionregistrationv1.ValidatingWebhookConfigurationList{}
+	if err := p.Client.List(ctx, validatingWebhookConfigurations); err != nil {
+		return errors.Wrap(err, "failed to list validating webhook configurations")
+	}
+	for i := range validatingWebhookConfigurations.Items {
+		configuration := &validatingWebhookConfigurations.Items[i]
+		patch := client.MergeFromWithOptions(configuration.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		changed := false
+		for j := range configuration.Webhooks {
+			changed = p.setCABundle(&configuration.Webhooks[j].ClientConfig, caBundle) || changed
+		}
+		if changed {
+			if err := p.Client.Patch(ctx, configuration, patch); err != nil {
+				return errors.Wrapf(err, "failed to update CA bundle of validating webhook configuration %s", configuration.Name)
+			}
+		}
+	}
+
+	mutatingWebhookConfigurations := &admissionregistrationv1.MutatingWebhookConfigurationList{}
+	if err := p.Client.List(ctx, mutatingWebhookConfigurations); err != nil {
+		return errors.Wrap(err, "failed to list mutating webhook configurations")
+	}
+	for i := range mutatingWebhookConfigurations.Items {
+		configuration := &mutatingWebhookConfigurations.Items[i]
+		patch := client.MergeFromWithOptions(configuration.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		changed := false
+		for j := range configuration.Webhooks {
+			changed = p.setCABundle(&configuration.Webhooks[j].ClientConfig, caBundle) || changed
+		}
+		if changed {
+			if err := p.Client.Patch(ctx, configuration, patch); err != nil {
+				return errors.Wrapf(err, "failed to update CA bundle of mutating webhook configuration %s", configuration.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// setCABundle sets the CA bundle of the given client configuration if it refers to the webhook service, and returns whether it changed.
+func (p *Provisioner) setCABundle(clientConfig *admissionregistrationv1.WebhookClientConfig, caBundle []byte) bool {
+	service := clientConfig.Service
+	if service == nil || service.Namespace != p.Namespace || service.Name != p.ServiceName || bytes.Equal(clientConfig.CABundle, caBundle) {
+		return false
+	}
+	clientConfig.CABundle = caBundle
+	return true
+}
+
+// dnsNames returns the DNS names under which the webhook service is reached.
+func (p *Provisioner) dnsNames() []string {
+	return []string{
+		p.ServiceName,
+		fmt.Sprintf("%s.%s", p.ServiceName, p.Namespace),
+		fmt.Sprintf("%s.%s.svc", p.ServiceName, p.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", p.ServiceName, p.Namespace),
+	}
+}
+
+func (p *Provisioner) currentTime() time.Time {
+	if p.now != nil {
+		return p.now()
+	}
+	return time.Now()
+}
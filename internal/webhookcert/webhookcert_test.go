@@ -0,0 +1,161 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhookcert
+
+import (
+	"context"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWebhookCert(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Webhook Certificate Test Suite")
+}
+
+var _ = Describe("Webhook certificates | Provisioner", func() {
+	ctx := context.Background()
+	secretName := types.NamespacedName{Namespace: "operator", Name: "webhook-tls"}
+
+	var c client.Client
+	var p *Provisioner
+	var now time.Time
+
+	webhookConfiguration := func(name string, serviceName string) *admissionregistrationv1.ValidatingWebhookConfiguration {
+		return &admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+				Name: name + ".example.com",
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{Namespace: "operator", Name: serviceName},
+				},
+			}},
+		}
+	}
+
+	getSecret := func() *corev1.Secret {
+		secret := &corev1.Secret{}
+		Expect(c.Get(ctx, secretName, secret)).To(Succeed())
+		return secret
+	}
+
+	BeforeEach(func() {
+		c = fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(
+			webhookConfiguration("own", "webhook-service"),
+			webhookConfiguration("other", "other-service"),
+		).Build()
+		now = time.Now()
+		p = &Provisioner{
+			Client:      c,
+			Mode:        ModeSelfSigned,
+			Namespace:   secretName.Namespace,
+			SecretName:  secretName.Name,
+			ServiceName: "webhook-service",
+			CertDir:     GinkgoT().TempDir(),
+			now:         func() time.Time { return now },
+		}
+	})
+
+	It("should issue a self-signed certificate and update the CA bundle", func() {
+		Expect(p.Setup(ctx)).To(Succeed())
+
+		secret := getSecret()
+		Expect(secret.Type).To(Equal(corev1.SecretTypeTLS))
+		certificate := parseCertificate(secret.Data[secretKeyCertificate])
+		Expect(certificate).NotTo(BeNil())
+		Expect(certificate.DNSNames).To(ContainElement("webhook-service.operator.svc"))
+		roots := x509.NewCertPool()
+		Expect(roots.AppendCertsFromPEM(secret.Data[secretKeyCACertificate])).To(BeTrue())
+		_, err := certificate.Verify(x509.VerifyOptions{DNSName: "webhook-service.operator.svc", Roots: roots, CurrentTime: now})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, key := range []string{secretKeyCertificate, secretKeyPrivateKey} {
+			Expect(os.ReadFile(filepath.Join(p.CertDir, key))).To(Equal(secret.Data[key]))
+		}
+
+		own := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		Expect(c.Get(ctx, types.NamespacedName{Name: "own"}, own)).To(Succeed())
+		Expect(own.Webhooks[0].ClientConfig.CABundle).To(Equal(secret.Data[secretKeyCACertificate]))
+		other := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		Expect(c.Get(ctx, types.NamespacedName{Name: "other"}, other)).To(Succeed())
+		Expect(other.Webhooks[0].ClientConfig.CABundle).To(BeEmpty())
+	})
+
+	It("should renew the certificate only shortly before expiry", func() {
+		Expect(p.Setup(ctx)).To(Succeed())
+		issued := getSecret()
+
+		now = now.Add(30 * 24 * time.Hour)
+		Expect(p.Setup(ctx)).To(Succeed())
+		Expect(getSecret().Data).To(Equal(issued.Data))
+
+		now = now.Add(certificateValidity - certificateRenewBefore)
+		Expect(p.Setup(ctx)).To(Succeed())
+		renewed := getSecret()
+		Expect(renewed.Data[secretKeyCertificate]).NotTo(Equal(issued.Data[secretKeyCertificate]))
+		Expect(renewed.Data[secretKeyCACertificate]).To(Equal(issued.Data[secretKeyCACertificate]))
+		Expect(os.ReadFile(filepath.Join(p.CertDir, secretKeyCertificate))).To(Equal(renewed.Data[secretKeyCertificate]))
+	})
+
+	It("should keep trusting the previous CA after a CA renewal", func() {
+		Expect(p.Setup(ctx)).To(Succeed())
+		issued := getSecret()
+
+		now = now.Add(caValidity - caRenewBefore)
+		Expect(p.Setup(ctx)).To(Succeed())
+		renewed := getSecret()
+		Expect(parseCertificate(renewed.Data[secretKeyCACertificate]).Equal(parseCertificate(issued.Data[secretKeyCACertificate]))).To(BeFalse())
+
+		roots := x509.NewCertPool()
+		Expect(roots.AppendCertsFromPEM(renewed.Data[secretKeyCACertificate])).To(BeTrue())
+		for _, data := range []map[string][]byte{issued.Data, renewed.Data} {
+			certificate := parseCertificate(data[secretKeyCertificate])
+			_, err := certificate.Verify(x509.VerifyOptions{DNSName: "webhook-service", Roots: roots, CurrentTime: certificate.NotBefore.Add(time.Hour)})
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	It("should consume certificates issued by cert-manager", func() {
+		p.Mode = ModeCertManager
+		Expect(p.Setup(ctx)).NotTo(Succeed())
+
+		data, _, err := renew(nil, p.dnsNames(), now)
+		Expect(err).NotTo(HaveOccurred())
+		delete(data, secretKeyCAPrivateKey)
+		Expect(c.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: secretName.Namespace, Name: secretName.Name},
+			Data:       map[string][]byte{secretKeyCACertificate: data[secretKeyCACertificate]},
+		})).To(Succeed())
+		Expect(p.Setup(ctx)).NotTo(Succeed())
+
+		secret := getSecret()
+		secret.Data = data
+		Expect(c.Update(ctx, secret)).To(Succeed())
+		Expect(p.Setup(ctx)).To(Succeed())
+		Expect(getSecret().Data).To(Equal(data))
+		Expect(os.ReadFile(filepath.Join(p.CertDir, secretKeyPrivateKey))).To(Equal(data[secretKeyPrivateKey]))
+	})
+
+	It("should do nothing if the certificate is provided as files", func() {
+		p.Mode = ModeFiles
+		Expect(p.Setup(ctx)).To(Succeed())
+		Expect(c.Get(ctx, secretName, &corev1.Secret{})).NotTo(Succeed())
+		Expect(ParseMode("unknown")).Error().To(HaveOccurred())
+	})
+})
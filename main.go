@@ -6,29 +6,46 @@ SPDX-License-Identifier: Apache-2.0
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
+	"hash/fnv"
 	"net"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/internal/audit"
 	"github.com/sap/cf-service-operator/internal/cf"
+	"github.com/sap/cf-service-operator/internal/config"
 	"github.com/sap/cf-service-operator/internal/controllers"
+	"github.com/sap/cf-service-operator/internal/debug"
+	"github.com/sap/cf-service-operator/internal/events"
+	"github.com/sap/cf-service-operator/internal/tracing"
+	"github.com/sap/cf-service-operator/internal/webhookcert"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -50,20 +67,80 @@ func init() {
 func main() {
 	var metricsAddr string
 	var probeAddr string
+	var pprofAddr string
+	var performanceTrace string
+	var performanceTraceDuration time.Duration
+	var performanceTraceInterval time.Duration
+	var performanceTraceMaxFiles int
 	var webhookAddr string
 	var webhookCertDir string
+	var webhookCertificates string
+	var webhookCertificateSecret string
+	var webhookServiceName string
 	var enableLeaderElection bool
 	var enableWebhooks bool
 	var clusterResourceNamespace string
 	var enableBindingMetadata bool
+	var configPath string
+	var configReloadInterval time.Duration
+	var reconcileTimeout time.Duration
+	var catalogValidation bool
+	var watchNamespaces string
+	var ignoreNamespaces string
+	var namespaceLabelSelector string
+	var shardCount int
+	var shardIndex int
+	var reconcileClusterSpaces bool
+	var tracingEndpoint string
+	var tracingSampleRate float64
+	var tracingServiceName string
+	var cfMetricsLabels string
+	var pollingIntervalsReady map[string]metav1.Duration
+	var pollingIntervalsFail map[string]metav1.Duration
+	var stalledOperationTimeout time.Duration
+	var bindingSecretNameTemplate string
+	var auditLogPath string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&pprofAddr, "pprof-bind-address", "", "The address the diagnostics endpoints (pprof, and a dump of the Cloud Foundry caches) bind to, such as localhost:6060; disabled if empty.")
+	flag.StringVar(&performanceTrace, "performance-trace", "", "Path prefix of files into which execution traces are written (suffixed by the start time of the trace); disabled if empty.")
+	flag.DurationVar(&performanceTraceDuration, "performance-trace-duration", 0, "Duration of an execution trace; 0 traces until the operator terminates (or until the next trace is started).")
+	flag.DurationVar(&performanceTraceInterval, "performance-trace-interval", 0, "Interval in which a new execution trace is started; 0 only starts one trace at startup.")
+	flag.IntVar(&performanceTraceMaxFiles, "performance-trace-max-files", 5, "Maximum number of execution trace files kept; older files are removed. 0 keeps all files.")
 	flag.StringVar(&webhookAddr, "webhook-bind-address", ":9443", "The address the webhook endpoint binds to.")
 	flag.StringVar(&webhookCertDir, "webhook-tls-directory", "", "The directory containing TLS server key and certificate, as tls.key and tls.crt; defaults to $TMPDIR/k8s-webhook-server/serving-certs.")
+	flag.StringVar(&webhookCertificates, "webhook-certificates", string(webhookcert.ModeFiles), "Where the webhook serving certificate comes from: files (provided in the TLS directory), self-signed (generated and rotated by the operator), or cert-manager (read from the secret of a cert-manager Certificate).")
+	flag.StringVar(&webhookCertificateSecret, "webhook-certificate-secret", "", "The secret (in the operator namespace) holding the webhook serving certificate; required unless the certificate is provided as files.")
+	flag.StringVar(&webhookServiceName, "webhook-service-name", "", "The service (in the operator namespace) through which the webhooks are called; required unless the certificate is provided as files.")
 	flag.BoolVar(&enableWebhooks, "enableWebhooks", true, "Enable webhooks in controller. May be disabled for local development.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false, "Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
 	flag.StringVar(&clusterResourceNamespace, "cluster-resource-namespace", "", "The namespace for secrets in which cluster-scoped resources are found.")
 	flag.BoolVar(&enableBindingMetadata, "sap-binding-metadata", false, "Enhance binding secrets by SAP binding metadata by default.")
+	flag.DurationVar(&reconcileTimeout, "reconcile-timeout", 5*time.Minute, "Maximum duration of a single reconcile call; 0 disables the timeout.")
+	flag.BoolVar(&catalogValidation, "catalog-validation", true, "Validate service offerings and plans of new service instances against the Cloud Foundry service catalog; may be disabled for air-gapped clusters.")
+	flag.StringVar(&configPath, "config", "", "Path to a YAML file containing the operator configuration; environment variables and command line flags take precedence.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "", "Comma-separated list of namespaces whose objects are reconciled; defaults to all namespaces.")
+	flag.StringVar(&ignoreNamespaces, "ignore-namespaces", "", "Comma-separated list of namespaces whose objects are not reconciled.")
+	flag.StringVar(&namespaceLabelSelector, "namespace-label-selector", "", "Label selector restricting the namespaces whose objects are reconciled.")
+	flag.IntVar(&shardCount, "shard-count", 0, "Number of shards the namespaces are distributed to (by consistent hashing), such that multiple operator deployments can split the load; 0 or 1 disables sharding.")
+	flag.IntVar(&shardIndex, "shard-index", 0, "Shard (0 to shard-count - 1) whose namespaces are reconciled by this operator deployment.")
+	flag.BoolVar(&reconcileClusterSpaces, "reconcile-cluster-spaces", true, "Reconcile ClusterSpace objects; if sharding is enabled, they are only reconciled by shard 0.")
+	flag.StringVar(&tracingEndpoint, "tracing-endpoint", "", "URL of an OTLP/HTTP endpoint to which traces are exported (such as http://otel-collector:4318); tracing is disabled if empty.")
+	flag.Float64Var(&tracingSampleRate, "tracing-sample-rate", 1, "Fraction (between 0 and 1) of reconciles which are traced.")
+	flag.StringVar(&tracingServiceName, "tracing-service-name", "cf-service-operator", "Service name reported with exported traces.")
+	flag.StringVar(&cfMetricsLabels, "cf-metrics-labels", "", "Comma-separated list of additional labels (organization, space, controller) of the metrics of requests against the Cloud Foundry API.")
+	flag.Func("polling-intervals-ready", "Comma-separated list of default intervals in which ready objects are polled, by kind (such as ServiceInstance=30m,Space=5m).", func(value string) (err error) {
+		pollingIntervalsReady, err = config.ParseDurationMap(value)
+		return err
+	})
+	flag.Func("polling-intervals-fail", "Comma-separated list of default intervals in which failed objects (which exceeded their maximum number of retries) are polled, by kind (such as ServiceInstance=1h).", func(value string) (err error) {
+		pollingIntervalsFail, err = config.ParseDurationMap(value)
+		return err
+	})
+	flag.DurationVar(&stalledOperationTimeout, "stalled-operation-timeout", 0, "Time after which an operation on a Cloud Foundry instance which is still in progress is considered stalled; 0 disables the detection.")
+	flag.StringVar(&bindingSecretNameTemplate, "binding-secret-name-template", "", "Go template from which the secret name of service bindings is defaulted (such as {{ .Spec.ServiceInstanceName }}-{{ .Name }}-creds); defaults to the binding name if empty.")
+	flag.StringVar(&auditLogPath, "audit-log", "", "Path of a file to which every mutating call against the Cloud Foundry API is recorded (as one JSON object per line); - means standard output; disabled if empty.")
+	flag.DurationVar(&configReloadInterval, "config-reload-interval", 10*time.Second, "Interval in which the configuration file is checked for changes of settings which can be applied at runtime; 0 disables reloading.")
 
 	opts := zap.Options{
 		Development: false,
@@ -73,9 +150,66 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	if clusterResourceNamespace == "" {
-		var err error
-		clusterResourceNamespace, err = getInClusterNamespace()
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		setupLog.Error(err, "unable to load configuration")
+		os.Exit(1)
+	}
+	// Remember the configuration as loaded, to detect changes made to the configuration file at runtime
+	loadedCfg := *cfg
+	// Explicitly specified command line flags take precedence over configuration file and environment
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "cluster-resource-namespace":
+			cfg.ClusterResourceNamespace = clusterResourceNamespace
+		case "sap-binding-metadata":
+			cfg.EnableBindingMetadata = enableBindingMetadata
+		case "reconcile-timeout":
+			cfg.ReconcileTimeout.Duration = reconcileTimeout
+		case "catalog-validation":
+			cfg.CatalogValidation = catalogValidation
+		case "watch-namespaces":
+			cfg.WatchNamespaces = splitList(watchNamespaces)
+		case "ignore-namespaces":
+			cfg.IgnoreNamespaces = splitList(ignoreNamespaces)
+		case "namespace-label-selector":
+			cfg.NamespaceLabelSelector = namespaceLabelSelector
+		case "shard-count":
+			cfg.ShardCount = shardCount
+		case "shard-index":
+			cfg.ShardIndex = shardIndex
+		case "reconcile-cluster-spaces":
+			cfg.ReconcileClusterSpaces = reconcileClusterSpaces
+		case "tracing-endpoint":
+			cfg.TracingEndpoint = tracingEndpoint
+		case "tracing-sample-rate":
+			cfg.TracingSampleRate = tracingSampleRate
+		case "tracing-service-name":
+			cfg.TracingServiceName = tracingServiceName
+		case "cf-metrics-labels":
+			cfg.CFMetricsLabels = splitList(cfMetricsLabels)
+		case "polling-intervals-ready":
+			cfg.PollingIntervalsReady = pollingIntervalsReady
+		case "polling-intervals-fail":
+			cfg.PollingIntervalsFail = pollingIntervalsFail
+		case "stalled-operation-timeout":
+			cfg.StalledOperationTimeout.Duration = stalledOperationTimeout
+		case "binding-secret-name-template":
+			cfg.BindingSecretNameTemplate = bindingSecretNameTemplate
+		}
+	})
+	if err := cfg.Validate(); err != nil {
+		setupLog.Error(err, "invalid configuration")
+		os.Exit(1)
+	}
+	secretNameTemplate, err := cfg.GetBindingSecretNameTemplate()
+	if err != nil {
+		setupLog.Error(err, "invalid configuration")
+		os.Exit(1)
+	}
+
+	if cfg.ClusterResourceNamespace == "" {
+		cfg.ClusterResourceNamespace, err = getInClusterNamespace()
 		if err != nil {
 			if errors.Is(err, errNotInCluster) {
 				setupLog.Error(err, "please supply --cluster-resource-namespace")
@@ -90,7 +224,9 @@ func main() {
 		"Starting",
 		"enable-leader-election", enableLeaderElection,
 		"metrics-addr", metricsAddr,
-		"cluster-resource-namespace", clusterResourceNamespace,
+		"cluster-resource-namespace", cfg.ClusterResourceNamespace,
+		"watch-namespaces", cfg.WatchNamespaces,
+		"shard", fmt.Sprintf("%d/%d", cfg.ShardIndex, max(cfg.ShardCount, 1)),
 	)
 
 	webhookHost, webhookPort, err := parseAddress(webhookAddr)
@@ -98,6 +234,19 @@ func main() {
 		setupLog.Error(err, "unable to parse webhook bind address", "controller", "Space")
 		os.Exit(1)
 	}
+	webhookCertificateMode, err := webhookcert.ParseMode(webhookCertificates)
+	if err != nil {
+		setupLog.Error(err, "unable to parse webhook certificate mode")
+		os.Exit(1)
+	}
+	if webhookCertificateMode != webhookcert.ModeFiles && (webhookCertificateSecret == "" || webhookServiceName == "") {
+		setupLog.Error(errors.New("missing flag"), "please supply --webhook-certificate-secret and --webhook-service-name", "mode", webhookCertificateMode)
+		os.Exit(1)
+	}
+	if webhookCertDir == "" {
+		// default of the webhook server
+		webhookCertDir = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
+	}
 
 	options := ctrl.Options{
 		Scheme: scheme,
@@ -109,17 +258,37 @@ func main() {
 					&cfv1alpha1.ClusterSpace{},
 					&cfv1alpha1.ServiceInstance{},
 					&cfv1alpha1.ServiceBinding{},
+					&cfv1alpha1.ServiceOperatorReport{},
+					// pre-delete hook jobs are only read while service instances are deleted; caching would need a cluster-wide informer
+					&batchv1.Job{},
 				},
 			},
 		},
 		LeaderElection:                enableLeaderElection,
-		LeaderElectionID:              LeaderElectionID,
+		LeaderElectionID:              leaderElectionID(cfg),
 		LeaderElectionReleaseOnCancel: true,
 		Metrics: metricsserver.Options{
 			BindAddress: metricsAddr,
 		},
 		HealthProbeBindAddress: probeAddr,
 	}
+	if len(cfg.WatchNamespaces) > 0 {
+		// only watch the given namespaces; secrets are needed from the cluster resource namespace as well (credentials of cluster spaces),
+		// from the namespaces binding secrets may be written to, and from the namespaces space credentials may be read from
+		options.Cache.DefaultNamespaces = make(map[string]cache.Config)
+		secretNamespaces := map[string]cache.Config{cfg.ClusterResourceNamespace: {}}
+		for _, namespace := range cfg.WatchNamespaces {
+			options.Cache.DefaultNamespaces[namespace] = cache.Config{}
+			secretNamespaces[namespace] = cache.Config{}
+		}
+		for _, namespace := range cfg.SecretNamespaces {
+			secretNamespaces[namespace] = cache.Config{}
+		}
+		for _, namespace := range cfg.CredentialNamespaces {
+			secretNamespaces[namespace] = cache.Config{}
+		}
+		options.Cache.ByObject = map[client.Object]cache.ByObject{&corev1.Secret{}: {Namespaces: secretNamespaces}}
+	}
 	if enableWebhooks {
 		options.WebhookServer = webhook.NewServer(webhook.Options{
 			Host:    webhookHost,
@@ -127,19 +296,95 @@ func main() {
 			CertDir: webhookCertDir,
 		})
 	}
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
+	restConfig := ctrl.GetConfigOrDie()
+	mgr, err := ctrl.NewManager(restConfig, options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
+	ctx := ctrl.SetupSignalHandler()
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+
+	if enableWebhooks && webhookCertificateMode != webhookcert.ModeFiles {
+		// the certificate must exist before the webhook server is started; so it is provided through an uncached client
+		certClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+		if err != nil {
+			setupLog.Error(err, "unable to create client for webhook certificates")
+			os.Exit(1)
+		}
+		namespace, err := getInClusterNamespace()
+		if err != nil {
+			namespace = cfg.ClusterResourceNamespace
+		}
+		certProvisioner := &webhookcert.Provisioner{
+			Client:      certClient,
+			Mode:        webhookCertificateMode,
+			Namespace:   namespace,
+			SecretName:  webhookCertificateSecret,
+			ServiceName: webhookServiceName,
+			CertDir:     webhookCertDir,
+		}
+		if err := certProvisioner.Setup(ctx); err != nil {
+			setupLog.Error(err, "unable to provide webhook certificate")
+			os.Exit(1)
+		}
+		if err := mgr.Add(certProvisioner); err != nil {
+			setupLog.Error(err, "unable to add webhook certificate provisioner")
+			os.Exit(1)
+		}
+	}
+
+	// settings of the OperatorConfig object override the configuration file; both may change at runtime
+	operatorConfigReconciler := controllers.NewOperatorConfigReconciler(mgr.GetClient(), cfg, func(effective *config.Config) {
+		cf.Reconfigure(effective)
+		controllers.Reconfigure(effective)
+	})
+	if err = operatorConfigReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OperatorConfig")
+		os.Exit(1)
+	}
+
+	if configPath != "" && configReloadInterval > 0 {
+		if err = mgr.Add(config.NewWatcher(configPath, configReloadInterval, &loadedCfg, operatorConfigReconciler.SetConfig)); err != nil {
+			setupLog.Error(err, "unable to add configuration watcher")
+			os.Exit(1)
+		}
+	}
+
+	// changes of Cloud Foundry resources made through the operator are propagated to the controllers of dependent objects
+	eventBus := events.NewBus()
+	cf.SetEventBus(eventBus)
+
+	switch auditLogPath {
+	case "":
+	case "-":
+		cf.SetAuditLog(audit.NewLog(os.Stdout))
+	default:
+		auditLogFile, err := os.OpenFile(auditLogPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o640)
+		if err != nil {
+			setupLog.Error(err, "unable to open audit log", "path", auditLogPath)
+			os.Exit(1)
+		}
+		defer auditLogFile.Close()
+		cf.SetAuditLog(audit.NewLog(auditLogFile))
+	}
 
 	if err = (&controllers.SpaceReconciler{
 		Kind:                     "Space",
 		Client:                   mgr.GetClient(),
 		Scheme:                   mgr.GetScheme(),
-		ClusterResourceNamespace: clusterResourceNamespace,
+		ClusterResourceNamespace: cfg.ClusterResourceNamespace,
+		ReconcileTimeout:         cfg.ReconcileTimeout.Duration,
+		Config:                   cfg,
 		ClientBuilder:            cf.NewOrganizationClient,
 		HealthCheckerBuilder:     cf.NewSpaceHealthChecker,
+		SpaceClientBuilder:       cf.NewSpaceClient,
+		EndpointProber:           cf.ProbeEndpoint,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Space")
 		os.Exit(1)
@@ -148,9 +393,13 @@ func main() {
 		Kind:                     "ClusterSpace",
 		Client:                   mgr.GetClient(),
 		Scheme:                   mgr.GetScheme(),
-		ClusterResourceNamespace: clusterResourceNamespace,
+		ClusterResourceNamespace: cfg.ClusterResourceNamespace,
+		ReconcileTimeout:         cfg.ReconcileTimeout.Duration,
+		Config:                   cfg,
 		ClientBuilder:            cf.NewOrganizationClient,
 		HealthCheckerBuilder:     cf.NewSpaceHealthChecker,
+		SpaceClientBuilder:       cf.NewSpaceClient,
+		EndpointProber:           cf.ProbeEndpoint,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ClusterSpace")
 		os.Exit(1)
@@ -158,8 +407,12 @@ func main() {
 	if err = (&controllers.ServiceInstanceReconciler{
 		Client:                   mgr.GetClient(),
 		Scheme:                   mgr.GetScheme(),
-		ClusterResourceNamespace: clusterResourceNamespace,
+		ClusterResourceNamespace: cfg.ClusterResourceNamespace,
+		ReconcileTimeout:         cfg.ReconcileTimeout.Duration,
+		Config:                   cfg,
 		ClientBuilder:            cf.NewSpaceClient,
+		Recorder:                 mgr.GetEventRecorderFor("serviceinstance-controller"),
+		EventBus:                 eventBus,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ServiceInstance")
 		os.Exit(1)
@@ -167,15 +420,53 @@ func main() {
 	if err = (&controllers.ServiceBindingReconciler{
 		Client:                   mgr.GetClient(),
 		Scheme:                   mgr.GetScheme(),
-		ClusterResourceNamespace: clusterResourceNamespace,
-		EnableBindingMetadata:    enableBindingMetadata,
+		ClusterResourceNamespace: cfg.ClusterResourceNamespace,
+		ReconcileTimeout:         cfg.ReconcileTimeout.Duration,
+		Config:                   cfg,
+		EnableBindingMetadata:    cfg.EnableBindingMetadata,
+		SecretNameTemplate:       secretNameTemplate,
 		ClientBuilder:            cf.NewSpaceClient,
+		EventBus:                 eventBus,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ServiceBinding")
 		os.Exit(1)
 	}
+	if err = (&controllers.RouteReconciler{
+		Client:                   mgr.GetClient(),
+		Scheme:                   mgr.GetScheme(),
+		ClusterResourceNamespace: cfg.ClusterResourceNamespace,
+		ReconcileTimeout:         cfg.ReconcileTimeout.Duration,
+		Config:                   cfg,
+		ClientBuilder:            cf.NewSpaceClient,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Route")
+		os.Exit(1)
+	}
+	if err = (&controllers.RouteBindingReconciler{
+		Client:                   mgr.GetClient(),
+		Scheme:                   mgr.GetScheme(),
+		ClusterResourceNamespace: cfg.ClusterResourceNamespace,
+		ReconcileTimeout:         cfg.ReconcileTimeout.Duration,
+		Config:                   cfg,
+		ClientBuilder:            cf.NewSpaceClient,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RouteBinding")
+		os.Exit(1)
+	}
+	if err = (&controllers.ClusterServiceBindingReconciler{
+		Client:                   mgr.GetClient(),
+		Scheme:                   mgr.GetScheme(),
+		ClusterResourceNamespace: cfg.ClusterResourceNamespace,
+		ReconcileTimeout:         cfg.ReconcileTimeout.Duration,
+		Config:                   cfg,
+		EnableBindingMetadata:    cfg.EnableBindingMetadata,
+		ClientBuilder:            cf.NewSpaceClient,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterServiceBinding")
+		os.Exit(1)
+	}
 	if enableWebhooks {
-		if err = (&cfv1alpha1.Space{}).SetupWebhookWithManager(mgr); err != nil {
+		if err = (&cfv1alpha1.Space{}).SetupWebhookWithManager(mgr, cfg.CredentialNamespaces); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Space")
 			os.Exit(1)
 		}
@@ -183,14 +474,111 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterSpace")
 			os.Exit(1)
 		}
-		if err = (&cfv1alpha1.ServiceInstance{}).SetupWebhookWithManager(mgr); err != nil {
+		var planChecker cfv1alpha1.ServicePlanChecker
+		if cfg.CatalogValidation {
+			planChecker = &controllers.ServicePlanChecker{
+				Client:                   mgr.GetClient(),
+				ClusterResourceNamespace: cfg.ClusterResourceNamespace,
+				ClientBuilder:            cf.NewSpaceClient,
+				Config:                   cfg,
+			}
+		}
+		if err = (&cfv1alpha1.ServiceInstance{}).SetupWebhookWithManager(mgr, planChecker, &controllers.DefaultSpaceResolver{Client: mgr.GetClient()}); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "ServiceInstance")
 			os.Exit(1)
 		}
-		if err = (&cfv1alpha1.ServiceBinding{}).SetupWebhookWithManager(mgr); err != nil {
+		if err = (&cfv1alpha1.ServiceBinding{}).SetupWebhookWithManager(mgr, secretNameTemplate); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "ServiceBinding")
 			os.Exit(1)
 		}
+		if err = (&cfv1alpha1.Route{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Route")
+			os.Exit(1)
+		}
+		if err = (&cfv1alpha1.RouteBinding{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "RouteBinding")
+			os.Exit(1)
+		}
+		if err = (&cfv1alpha1.ClusterServiceBinding{}).SetupWebhookWithManager(mgr, cfg.SecretNamespaces); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterServiceBinding")
+			os.Exit(1)
+		}
+	}
+	if err := ctrlmetrics.Registry.Register(&controllers.ResourceStateCollector{Client: mgr.GetCache(), Config: cfg}); err != nil {
+		setupLog.Error(err, "unable to register resource state metrics")
+		os.Exit(1)
+	}
+	if cfg.ReportInterval.Duration > 0 {
+		if err = mgr.Add(&controllers.ServiceOperatorReporter{
+			Client:                   mgr.GetClient(),
+			ClusterResourceNamespace: cfg.ClusterResourceNamespace,
+			Config:                   cfg,
+			Interval:                 cfg.ReportInterval.Duration,
+			CacheStatistics:          cf.GetResourceCacheStatistics,
+		}); err != nil {
+			setupLog.Error(err, "unable to add report runnable")
+			os.Exit(1)
+		}
+	}
+	if cfg.ClientCacheTimeout.Duration > 0 {
+		if err = mgr.Add(&cf.ClientCacheJanitor{
+			Timeout:  cfg.ClientCacheTimeout.Duration,
+			Interval: time.Minute,
+		}); err != nil {
+			setupLog.Error(err, "unable to add client cache janitor runnable")
+			os.Exit(1)
+		}
+	}
+	if cfg.IsResourceCacheEnabled {
+		if err = mgr.Add(&controllers.CacheWarmer{
+			Client:                   mgr.GetClient(),
+			ClusterResourceNamespace: cfg.ClusterResourceNamespace,
+			ClientBuilder:            cf.NewSpaceClient,
+			Config:                   cfg,
+			Invalidate:               cf.ClearResourceCaches,
+		}); err != nil {
+			setupLog.Error(err, "unable to add cache warmer runnable")
+			os.Exit(1)
+		}
+	}
+	if cfg.OrphanScanInterval.Duration > 0 {
+		if err = mgr.Add(&controllers.OrphanCollector{
+			Client:                   mgr.GetClient(),
+			ClusterResourceNamespace: cfg.ClusterResourceNamespace,
+			ClientBuilder:            cf.NewSpaceClient,
+			Config:                   cfg,
+			Recorder:                 mgr.GetEventRecorderFor("orphan-collector"),
+			Interval:                 cfg.OrphanScanInterval.Duration,
+			Policy:                   cfg.OrphanPolicy,
+		}); err != nil {
+			setupLog.Error(err, "unable to add orphan collector runnable")
+			os.Exit(1)
+		}
+	}
+	tracer := &debug.Tracer{
+		Path:     performanceTrace,
+		Duration: performanceTraceDuration,
+		Interval: performanceTraceInterval,
+		MaxFiles: performanceTraceMaxFiles,
+	}
+	if performanceTrace != "" {
+		if err = mgr.Add(tracer); err != nil {
+			setupLog.Error(err, "unable to add performance trace runnable")
+			os.Exit(1)
+		}
+	} else {
+		// traces started on demand (through the diagnostics endpoints)
+		tracer.Path = filepath.Join(os.TempDir(), "cf-service-operator.trace")
+	}
+	if pprofAddr != "" {
+		if err = mgr.Add(&debug.Server{
+			BindAddress: pprofAddr,
+			CacheDump:   cf.DumpCaches,
+			Tracer:      tracer,
+		}); err != nil {
+			setupLog.Error(err, "unable to add debug server runnable")
+			os.Exit(1)
+		}
 	}
 	// +kubebuilder:scaffold:builder
 
@@ -202,12 +590,51 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	// not ready if no Cloud Foundry API endpoint is reachable at all; deliberately not part of the liveness check,
+	// since restarting the operator does not help if Cloud Foundry is unreachable
+	if err := mgr.AddReadyzCheck("cf", cf.CheckAnyEndpoint); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
 
 	setupLog.Info("Starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	// flush pending spans (the signal context is done at this point)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		setupLog.Error(err, "unable to flush traces")
+	}
+}
+
+// leaderElectionID returns the leader election ID for the given configuration; operator deployments reconciling
+// different shards or namespaces (and therefore running concurrently) use different IDs.
+func leaderElectionID(cfg *config.Config) string {
+	id := LeaderElectionID
+	if len(cfg.WatchNamespaces) > 0 || len(cfg.IgnoreNamespaces) > 0 || cfg.NamespaceLabelSelector != "" {
+		h := fnv.New32a()
+		h.Write([]byte(strings.Join(cfg.WatchNamespaces, ",") + ";" + strings.Join(cfg.IgnoreNamespaces, ",") + ";" + cfg.NamespaceLabelSelector))
+		id = fmt.Sprintf("%08x.%s", h.Sum32(), id)
+	}
+	if cfg.ShardCount > 1 {
+		id = fmt.Sprintf("shard-%d-of-%d.%s", cfg.ShardIndex, cfg.ShardCount, id)
+	}
+	return id
+}
+
+// splitList splits the given comma-separated list, ignoring empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
 
 const inClusterNamespacePath = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
@@ -0,0 +1,16 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package cftest provides an in-memory fake of the Cloud Foundry v3 API (including the UAA token endpoint), serving the
+// endpoints used by the cf-service-operator: organizations, spaces, users and space roles, service offerings and plans,
+// managed service instances, service credential bindings (service keys and app bindings), apps (read only) and jobs.
+// It allows to run end-to-end tests of the operator (or of other programs talking to Cloud Foundry through go-cfclient)
+// without a real Cloud Foundry foundation, and without stubbing single requests.
+//
+// Asynchronous operations (creating, updating and deleting service instances and bindings) complete immediately by default;
+// with SetAsync(true), they stay in progress until CompleteOperations (or FailOperations) is called.
+//
+// Routes, domains and route bindings are not served.
+package cftest
@@ -0,0 +1,798 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cftest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-community/go-cfclient/v3/resource"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// error codes and titles of the Cloud Foundry API
+const (
+	codeInvalidAuthToken     = 1000
+	codeMessageParseError    = 1001
+	codeBadQueryParameter    = 10005
+	codeAssociationNotEmpty  = 10006
+	codeUnprocessableEntity  = 10008
+	codeResourceNotFound     = 10010
+	codeNameTaken            = 60002
+	codeOperationInProgress  = 60016
+	titleInvalidAuthToken    = "CF-InvalidAuthToken"
+	titleMessageParseError   = "CF-MessageParseError"
+	titleBadQueryParameter   = "CF-BadQueryParameter"
+	titleAssociationNotEmpty = "CF-AssociationNotEmpty"
+	titleUnprocessable       = "CF-UnprocessableEntity"
+	titleResourceNotFound    = "CF-ResourceNotFound"
+	titleNameTaken           = "CF-ServiceInstanceNameTaken"
+	titleOperationInProgress = "CF-AsyncServiceInstanceOperationInProgress"
+)
+
+const defaultPerPage = 50
+
+// handlerFunc serves an API request; it is called with mutex locked.
+type handlerFunc func(w http.ResponseWriter, r *http.Request)
+
+func (s *Server) newHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleRoot)
+	mux.HandleFunc("POST /uaa/oauth/token", s.handleToken)
+
+	s.handle(mux, "GET /v3/organizations", s.listOrganizations)
+	s.handle(mux, "GET /v3/spaces", s.listSpaces)
+	s.handle(mux, "GET /v3/spaces/{guid}", s.getSpace)
+	s.handle(mux, "POST /v3/spaces", s.createSpace)
+	s.handle(mux, "PATCH /v3/spaces/{guid}", s.updateSpace)
+	s.handle(mux, "DELETE /v3/spaces/{guid}", s.deleteSpaceHandler)
+	s.handle(mux, "GET /v3/users", s.listUsers)
+	s.handle(mux, "GET /v3/roles", s.listRoles)
+	s.handle(mux, "POST /v3/roles", s.createRole)
+	s.handle(mux, "DELETE /v3/roles/{guid}", s.deleteRole)
+	s.handle(mux, "GET /v3/apps", s.listApps)
+	s.handle(mux, "GET /v3/service_offerings", s.listServiceOfferings)
+	s.handle(mux, "GET /v3/service_plans", s.listServicePlans)
+	s.handle(mux, "GET /v3/service_plans/{guid}", s.getServicePlan)
+	s.handle(mux, "GET /v3/service_instances", s.listServiceInstances)
+	s.handle(mux, "GET /v3/service_instances/{guid}", s.getServiceInstance)
+	s.handle(mux, "GET /v3/service_instances/{guid}/parameters", s.getServiceInstanceParameters)
+	s.handle(mux, "POST /v3/service_instances", s.createServiceInstance)
+	s.handle(mux, "PATCH /v3/service_instances/{guid}", s.updateServiceInstance)
+	s.handle(mux, "DELETE /v3/service_instances/{guid}", s.deleteServiceInstanceHandler)
+	s.handle(mux, "GET /v3/service_credential_bindings", s.listBindings)
+	s.handle(mux, "GET /v3/service_credential_bindings/{guid}", s.getBinding)
+	s.handle(mux, "GET /v3/service_credential_bindings/{guid}/details", s.getBindingDetails)
+	s.handle(mux, "POST /v3/service_credential_bindings", s.createBinding)
+	s.handle(mux, "PATCH /v3/service_credential_bindings/{guid}", s.updateBinding)
+	s.handle(mux, "DELETE /v3/service_credential_bindings/{guid}", s.deleteBindingHandler)
+	s.handle(mux, "GET /v3/jobs/{guid}", s.getJob)
+	return mux
+}
+
+// handle registers the given handler for the given pattern; requests must carry an access token issued by the token endpoint.
+func (s *Server) handle(mux *http.ServeMux, pattern string, handler handlerFunc) {
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "bearer ")
+		if !ok {
+			token, ok = strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		}
+		if !ok || !s.tokens[token] {
+			writeError(w, http.StatusUnauthorized, codeInvalidAuthToken, titleInvalidAuthToken, "Invalid Auth Token")
+			return
+		}
+		handler(w, r)
+	})
+}
+
+func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
+	root := resource.Root{}
+	root.Links.Self.Href = s.URL()
+	root.Links.Login.Href = s.URL() + "/login"
+	root.Links.Uaa.Href = s.URL() + "/uaa"
+	writeJSON(w, http.StatusOK, root)
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	switch r.PostForm.Get("grant_type") {
+	case "password":
+		if s.username != "" && (r.PostForm.Get("username") != s.username || r.PostForm.Get("password") != s.password) {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized", "error_description": "Bad credentials"})
+			return
+		}
+	case "refresh_token":
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+	token := newGuid()
+	s.tokens[token] = true
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token":  token,
+		"token_type":    "bearer",
+		"refresh_token": newGuid(),
+		"expires_in":    3600,
+	})
+}
+
+func (s *Server) listOrganizations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var result []*resource.Organization
+	for _, organization := range sorted(s.organizations, func(organization *resource.Organization) time.Time { return organization.CreatedAt }) {
+		if matchesFilter(query, "names", organization.Name) && matchesFilter(query, "guids", organization.GUID) {
+			result = append(result, organization)
+		}
+	}
+	writeList(w, r, s.URL(), result)
+}
+
+func (s *Server) listSpaces(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	selector, ok := labelSelectorOf(w, query)
+	if !ok {
+		return
+	}
+	var result []*resource.Space
+	for _, space := range sorted(s.spaces, func(space *resource.Space) time.Time { return space.CreatedAt }) {
+		if matchesFilter(query, "names", space.Name) && matchesFilter(query, "guids", space.GUID) &&
+			matchesFilter(query, "organization_guids", space.Relationships.Organization.Data.GUID) && matchesLabels(selector, space.Metadata) {
+			result = append(result, space)
+		}
+	}
+	writeList(w, r, s.URL(), result)
+}
+
+func (s *Server) getSpace(w http.ResponseWriter, r *http.Request) {
+	space, ok := s.spaces[r.PathValue("guid")]
+	if !ok {
+		writeNotFound(w, "Space")
+		return
+	}
+	writeJSON(w, http.StatusOK, space)
+}
+
+func (s *Server) createSpace(w http.ResponseWriter, r *http.Request) {
+	req := &resource.SpaceCreate{}
+	if !readJSON(w, r, req) {
+		return
+	}
+	organizationGuid := ""
+	if req.Relationships != nil && req.Relationships.Organization != nil && req.Relationships.Organization.Data != nil {
+		organizationGuid = req.Relationships.Organization.Data.GUID
+	}
+	if _, ok := s.organizations[organizationGuid]; !ok {
+		writeUnprocessable(w, "Invalid organization. Ensure the organization exists and you have access to it.")
+		return
+	}
+	for _, space := range s.spaces {
+		if space.Relationships.Organization.Data.GUID == organizationGuid && space.Name == req.Name {
+			writeUnprocessable(w, fmt.Sprintf("Organization '%s' already contains a space with name '%s'.", s.organizations[organizationGuid].Name, req.Name))
+			return
+		}
+	}
+	writeJSON(w, http.StatusCreated, s.addSpace(organizationGuid, req.Name, req.Metadata))
+}
+
+func (s *Server) updateSpace(w http.ResponseWriter, r *http.Request) {
+	space, ok := s.spaces[r.PathValue("guid")]
+	if !ok {
+		writeNotFound(w, "Space")
+		return
+	}
+	req := &resource.SpaceUpdate{}
+	if !readJSON(w, r, req) {
+		return
+	}
+	if req.Name != "" {
+		space.Name = req.Name
+	}
+	space.Metadata = mergeMetadata(space.Metadata, req.Metadata)
+	space.UpdatedAt = s.now()
+	writeJSON(w, http.StatusOK, space)
+}
+
+func (s *Server) deleteSpaceHandler(w http.ResponseWriter, r *http.Request) {
+	guid := r.PathValue("guid")
+	if _, ok := s.spaces[guid]; !ok {
+		writeNotFound(w, "Space")
+		return
+	}
+	s.deleteSpace(guid)
+	s.writeJob(w, "space.delete")
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var result []*resource.User
+	for _, user := range sorted(s.users, func(user *resource.User) time.Time { return user.CreatedAt }) {
+		if matchesFilter(query, "usernames", user.Username) && matchesFilter(query, "origins", user.Origin) && matchesFilter(query, "guids", user.GUID) {
+			result = append(result, user)
+		}
+	}
+	writeList(w, r, s.URL(), result)
+}
+
+func (s *Server) listRoles(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var result []*resource.Role
+	for _, role := range sorted(s.roles, func(role *resource.Role) time.Time { return role.CreatedAt }) {
+		if matchesFilter(query, "space_guids", role.Relationships.Space.Data.GUID) && matchesFilter(query, "user_guids", role.Relationships.User.Data.GUID) &&
+			matchesFilter(query, "types", role.Type) && matchesFilter(query, "guids", role.GUID) {
+			result = append(result, role)
+		}
+	}
+	writeList(w, r, s.URL(), result)
+}
+
+func (s *Server) createRole(w http.ResponseWriter, r *http.Request) {
+	req := &resource.RoleSpaceCreate{}
+	if !readJSON(w, r, req) {
+		return
+	}
+	if req.Relationships.Space.Data == nil || req.Relationships.User.Data == nil {
+		writeUnprocessable(w, "Only space roles are supported.")
+		return
+	}
+	space, ok := s.spaces[req.Relationships.Space.Data.GUID]
+	if !ok {
+		writeUnprocessable(w, "Invalid space. Ensure that the space exists and you have access to it.")
+		return
+	}
+	user, ok := s.users[req.Relationships.User.Data.GUID]
+	if !ok {
+		writeUnprocessable(w, "Invalid user. Ensure that the user exists and you have access to it.")
+		return
+	}
+	for _, role := range s.roles {
+		if role.Relationships.Space.Data.GUID == space.GUID && role.Relationships.User.Data.GUID == user.GUID && role.Type == req.RoleType {
+			writeUnprocessable(w, fmt.Sprintf("User '%s' already has '%s' role in space '%s'.", user.Username, req.RoleType, space.Name))
+			return
+		}
+	}
+	now := s.now()
+	role := &resource.Role{GUID: newGuid(), Type: req.RoleType, CreatedAt: now, UpdatedAt: now}
+	role.Relationships.Space.Data = &resource.Relationship{GUID: space.GUID}
+	role.Relationships.User.Data = &resource.Relationship{GUID: user.GUID}
+	role.Relationships.Org.Data = &resource.Relationship{GUID: space.Relationships.Organization.Data.GUID}
+	s.roles[role.GUID] = role
+	writeJSON(w, http.StatusCreated, role)
+}
+
+func (s *Server) deleteRole(w http.ResponseWriter, r *http.Request) {
+	guid := r.PathValue("guid")
+	if _, ok := s.roles[guid]; !ok {
+		writeNotFound(w, "Role")
+		return
+	}
+	delete(s.roles, guid)
+	s.writeJob(w, "role.delete")
+}
+
+func (s *Server) listApps(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var result []*resource.App
+	for _, app := range sorted(s.apps, func(app *resource.App) time.Time { return app.CreatedAt }) {
+		if matchesFilter(query, "names", app.Name) && matchesFilter(query, "guids", app.GUID) && matchesFilter(query, "space_guids", app.Relationships.Space.Data.GUID) {
+			result = append(result, app)
+		}
+	}
+	writeList(w, r, s.URL(), result)
+}
+
+func (s *Server) listServiceOfferings(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var result []*resource.ServiceOffering
+	for _, serviceOffering := range sorted(s.serviceOfferings, func(serviceOffering *resource.ServiceOffering) time.Time { return serviceOffering.CreatedAt }) {
+		// service offerings are visible in all spaces, so the space_guids filter is ignored
+		if matchesFilter(query, "names", serviceOffering.Name) && matchesFilter(query, "guids", serviceOffering.GUID) {
+			result = append(result, serviceOffering)
+		}
+	}
+	writeList(w, r, s.URL(), result)
+}
+
+func (s *Server) listServicePlans(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var result []*resource.ServicePlan
+	for _, servicePlan := range sorted(s.servicePlans, func(servicePlan *resource.ServicePlan) time.Time { return servicePlan.CreatedAt }) {
+		// service plans are visible in all spaces, so the space_guids filter is ignored
+		if matchesFilter(query, "names", servicePlan.Name) && matchesFilter(query, "guids", servicePlan.GUID) &&
+			matchesFilter(query, "service_offering_guids", servicePlan.Relationships.ServiceOffering.Data.GUID) {
+			result = append(result, servicePlan)
+		}
+	}
+	writeList(w, r, s.URL(), result)
+}
+
+func (s *Server) getServicePlan(w http.ResponseWriter, r *http.Request) {
+	servicePlan, ok := s.servicePlans[r.PathValue("guid")]
+	if !ok {
+		writeNotFound(w, "Service plan")
+		return
+	}
+	writeJSON(w, http.StatusOK, servicePlan)
+}
+
+func (s *Server) listServiceInstances(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	selector, ok := labelSelectorOf(w, query)
+	if !ok {
+		return
+	}
+	var result []*resource.ServiceInstance
+	for _, instance := range sorted(s.serviceInstances, func(instance *resource.ServiceInstance) time.Time { return instance.CreatedAt }) {
+		if matchesFilter(query, "names", instance.Name) && matchesFilter(query, "guids", instance.GUID) &&
+			matchesFilter(query, "space_guids", instance.Relationships.Space.Data.GUID) &&
+			matchesFilter(query, "service_plan_guids", instance.Relationships.ServicePlan.Data.GUID) && matchesLabels(selector, instance.Metadata) {
+			result = append(result, s.serviceInstanceView(instance))
+		}
+	}
+	writeList(w, r, s.URL(), result)
+}
+
+func (s *Server) getServiceInstance(w http.ResponseWriter, r *http.Request) {
+	instance, ok := s.serviceInstances[r.PathValue("guid")]
+	if !ok {
+		writeNotFound(w, "Service instance")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.serviceInstanceView(instance))
+}
+
+func (s *Server) getServiceInstanceParameters(w http.ResponseWriter, r *http.Request) {
+	guid := r.PathValue("guid")
+	if _, ok := s.serviceInstances[guid]; !ok {
+		writeNotFound(w, "Service instance")
+		return
+	}
+	parameters, ok := s.instanceParameters[guid]
+	if !ok {
+		parameters = json.RawMessage("{}")
+	}
+	writeJSON(w, http.StatusOK, parameters)
+}
+
+func (s *Server) createServiceInstance(w http.ResponseWriter, r *http.Request) {
+	req := &resource.ServiceInstanceCreate{}
+	if !readJSON(w, r, req) {
+		return
+	}
+	if req.Type != "managed" {
+		writeUnprocessable(w, "Only managed service instances are supported.")
+		return
+	}
+	spaceGuid := ""
+	if req.Relationships.Space != nil && req.Relationships.Space.Data != nil {
+		spaceGuid = req.Relationships.Space.Data.GUID
+	}
+	if _, ok := s.spaces[spaceGuid]; !ok {
+		writeUnprocessable(w, "Invalid space. Ensure that the space exists and you have access to it.")
+		return
+	}
+	servicePlanGuid := ""
+	if req.Relationships.ServicePlan != nil && req.Relationships.ServicePlan.Data != nil {
+		servicePlanGuid = req.Relationships.ServicePlan.Data.GUID
+	}
+	servicePlan, ok := s.servicePlans[servicePlanGuid]
+	if !ok {
+		writeUnprocessable(w, "Invalid service plan. Ensure that the service plan exists, is available, and you have access to it.")
+		return
+	}
+	if s.isServiceInstanceNameTaken(spaceGuid, req.Name, "") {
+		writeError(w, http.StatusUnprocessableEntity, codeNameTaken, titleNameTaken, "The service instance name is taken: "+req.Name)
+		return
+	}
+
+	now := s.now()
+	instance := &resource.ServiceInstance{
+		GUID:      newGuid(),
+		Name:      req.Name,
+		Type:      "managed",
+		Tags:      append([]string{}, req.Tags...),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Relationships: resource.ServiceInstanceRelationships{
+			ServicePlan: &resource.ToOneRelationship{Data: &resource.Relationship{GUID: servicePlanGuid}},
+			Space:       &resource.ToOneRelationship{Data: &resource.Relationship{GUID: spaceGuid}},
+		},
+		Metadata:        mergeMetadata(nil, req.Metadata),
+		MaintenanceInfo: &resource.ServiceInstanceMaintenanceInfo{Version: servicePlan.MaintenanceInfo.Version, Description: servicePlan.MaintenanceInfo.Description},
+	}
+	if req.Parameters != nil {
+		s.instanceParameters[instance.GUID] = *req.Parameters
+	}
+	s.startOperation(&instance.LastOperation, "create")
+	s.serviceInstances[instance.GUID] = instance
+	s.writeJob(w, "service_instance.create")
+}
+
+// updateServiceInstance updates the given service instance; updates of the parameters, the service plan or the maintenance info
+// are passed to the service broker (asynchronously), all other updates are applied synchronously.
+func (s *Server) updateServiceInstance(w http.ResponseWriter, r *http.Request) {
+	instance, ok := s.serviceInstances[r.PathValue("guid")]
+	if !ok {
+		writeNotFound(w, "Service instance")
+		return
+	}
+	req := &resource.ServiceInstanceManagedUpdate{}
+	if !readJSON(w, r, req) {
+		return
+	}
+	if instance.LastOperation.State == stateInProgress {
+		writeError(w, http.StatusConflict, codeOperationInProgress, titleOperationInProgress, "An operation for service instance "+instance.Name+" is in progress.")
+		return
+	}
+	if req.Name != nil && *req.Name != instance.Name && s.isServiceInstanceNameTaken(instance.Relationships.Space.Data.GUID, *req.Name, instance.GUID) {
+		writeError(w, http.StatusUnprocessableEntity, codeNameTaken, titleNameTaken, "The service instance name is taken: "+*req.Name)
+		return
+	}
+	if req.Relationships != nil && req.Relationships.ServicePlan != nil && req.Relationships.ServicePlan.Data != nil {
+		if _, ok := s.servicePlans[req.Relationships.ServicePlan.Data.GUID]; !ok {
+			writeUnprocessable(w, "Invalid service plan. Ensure that the service plan exists, is available, and you have access to it.")
+			return
+		}
+		instance.Relationships.ServicePlan = &resource.ToOneRelationship{Data: &resource.Relationship{GUID: req.Relationships.ServicePlan.Data.GUID}}
+	}
+
+	if req.Name != nil {
+		instance.Name = *req.Name
+	}
+	if req.Tags != nil {
+		instance.Tags = append([]string{}, req.Tags...)
+	}
+	if req.MaintenanceInfo != nil {
+		instance.MaintenanceInfo = &resource.ServiceInstanceMaintenanceInfo{Version: req.MaintenanceInfo.Version, Description: req.MaintenanceInfo.Description}
+	}
+	if req.Parameters != nil {
+		s.instanceParameters[instance.GUID] = *req.Parameters
+	}
+	instance.Metadata = mergeMetadata(instance.Metadata, req.Metadata)
+	instance.UpdatedAt = s.now()
+	if req.Parameters == nil && req.MaintenanceInfo == nil && (req.Relationships == nil || req.Relationships.ServicePlan == nil) {
+		writeJSON(w, http.StatusOK, s.serviceInstanceView(instance))
+		return
+	}
+	s.startOperation(&instance.LastOperation, "update")
+	s.writeJob(w, "service_instance.update")
+}
+
+func (s *Server) deleteServiceInstanceHandler(w http.ResponseWriter, r *http.Request) {
+	guid := r.PathValue("guid")
+	instance, ok := s.serviceInstances[guid]
+	if !ok {
+		writeNotFound(w, "Service instance")
+		return
+	}
+	for _, binding := range s.bindings {
+		if binding.Relationships.ServiceInstance.Data.GUID == guid {
+			writeError(w, http.StatusUnprocessableEntity, codeAssociationNotEmpty, titleAssociationNotEmpty,
+				"Please delete the service_bindings, service_keys, and routes associations for your service_instances.")
+			return
+		}
+	}
+	s.startOperation(&instance.LastOperation, "delete")
+	if instance.LastOperation.State == stateSucceeded {
+		s.deleteServiceInstance(guid)
+	}
+	s.writeJob(w, "service_instance.delete")
+}
+
+// Must be called with mutex locked.
+func (s *Server) isServiceInstanceNameTaken(spaceGuid string, name string, exceptGuid string) bool {
+	for _, instance := range s.serviceInstances {
+		if instance.Relationships.Space.Data.GUID == spaceGuid && instance.Name == name && instance.GUID != exceptGuid {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) listBindings(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	selector, ok := labelSelectorOf(w, query)
+	if !ok {
+		return
+	}
+	var result []*resource.ServiceCredentialBinding
+	for _, binding := range sorted(s.bindings, func(binding *resource.ServiceCredentialBinding) time.Time { return binding.CreatedAt }) {
+		appGuid := ""
+		if binding.Relationships.App != nil {
+			appGuid = binding.Relationships.App.Data.GUID
+		}
+		if matchesFilter(query, "names", binding.Name) && matchesFilter(query, "guids", binding.GUID) && matchesFilter(query, "type", binding.Type) &&
+			matchesFilter(query, "service_instance_guids", binding.Relationships.ServiceInstance.Data.GUID) &&
+			matchesFilter(query, "app_guids", appGuid) && matchesLabels(selector, binding.Metadata) {
+			result = append(result, binding)
+		}
+	}
+	writeList(w, r, s.URL(), result)
+}
+
+func (s *Server) getBinding(w http.ResponseWriter, r *http.Request) {
+	binding, ok := s.bindings[r.PathValue("guid")]
+	if !ok {
+		writeNotFound(w, "Service credential binding")
+		return
+	}
+	writeJSON(w, http.StatusOK, binding)
+}
+
+func (s *Server) getBindingDetails(w http.ResponseWriter, r *http.Request) {
+	guid := r.PathValue("guid")
+	binding, ok := s.bindings[guid]
+	if !ok || binding.LastOperation.Type != "create" || binding.LastOperation.State != stateSucceeded {
+		writeNotFound(w, "Service credential binding")
+		return
+	}
+	writeJSON(w, http.StatusOK, resource.ServiceCredentialBindingDetails{Credentials: s.credentials[guid], VolumeMounts: []string{}})
+}
+
+func (s *Server) createBinding(w http.ResponseWriter, r *http.Request) {
+	req := &resource.ServiceCredentialBindingCreate{}
+	if !readJSON(w, r, req) {
+		return
+	}
+	serviceInstanceGuid := ""
+	if req.Relationships.ServiceInstance != nil && req.Relationships.ServiceInstance.Data != nil {
+		serviceInstanceGuid = req.Relationships.ServiceInstance.Data.GUID
+	}
+	instance, ok := s.serviceInstances[serviceInstanceGuid]
+	if !ok {
+		writeUnprocessable(w, "The service instance could not be found: "+serviceInstanceGuid)
+		return
+	}
+	name := ""
+	if req.Name != nil {
+		name = *req.Name
+	}
+	now := s.now()
+	binding := &resource.ServiceCredentialBinding{
+		GUID:      newGuid(),
+		Name:      name,
+		Type:      req.Type,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Metadata:  mergeMetadata(nil, req.Metadata),
+		Relationships: resource.ServiceCredentialBindingRelationships{
+			ServiceInstance: &resource.ToOneRelationship{Data: &resource.Relationship{GUID: serviceInstanceGuid}},
+		},
+	}
+	switch req.Type {
+	case "key":
+		if name == "" {
+			writeUnprocessable(w, "Name can't be blank")
+			return
+		}
+		for _, other := range s.bindings {
+			if other.Type == "key" && other.Relationships.ServiceInstance.Data.GUID == serviceInstanceGuid && other.Name == name {
+				writeUnprocessable(w, fmt.Sprintf("The binding name is invalid. Key binding names must be unique. The service instance already has a key binding with name '%s'.", name))
+				return
+			}
+		}
+	case "app":
+		appGuid := ""
+		if req.Relationships.App != nil && req.Relationships.App.Data != nil {
+			appGuid = req.Relationships.App.Data.GUID
+		}
+		if _, ok := s.apps[appGuid]; !ok {
+			writeUnprocessable(w, "The app could not be found: "+appGuid)
+			return
+		}
+		for _, other := range s.bindings {
+			if other.Type == "app" && other.Relationships.ServiceInstance.Data.GUID == serviceInstanceGuid && other.Relationships.App.Data.GUID == appGuid {
+				writeUnprocessable(w, "The app is already bound to the service instance.")
+				return
+			}
+		}
+		binding.Relationships.App = &resource.ToOneRelationship{Data: &resource.Relationship{GUID: appGuid}}
+	default:
+		writeUnprocessable(w, "Type must be one of 'app', 'key'")
+		return
+	}
+
+	s.credentials[binding.GUID] = map[string]any{
+		"uri":      "https://" + instance.Name + ".service.example.com",
+		"username": binding.GUID,
+		"password": newGuid(),
+	}
+	s.startOperation(&binding.LastOperation, "create")
+	s.bindings[binding.GUID] = binding
+	s.writeJob(w, "service_bindings.create")
+}
+
+func (s *Server) updateBinding(w http.ResponseWriter, r *http.Request) {
+	binding, ok := s.bindings[r.PathValue("guid")]
+	if !ok {
+		writeNotFound(w, "Service credential binding")
+		return
+	}
+	req := &resource.ServiceCredentialBindingUpdate{}
+	if !readJSON(w, r, req) {
+		return
+	}
+	binding.Metadata = mergeMetadata(binding.Metadata, req.Metadata)
+	binding.UpdatedAt = s.now()
+	writeJSON(w, http.StatusOK, binding)
+}
+
+func (s *Server) deleteBindingHandler(w http.ResponseWriter, r *http.Request) {
+	guid := r.PathValue("guid")
+	binding, ok := s.bindings[guid]
+	if !ok {
+		writeNotFound(w, "Service credential binding")
+		return
+	}
+	s.startOperation(&binding.LastOperation, "delete")
+	if binding.LastOperation.State == stateSucceeded {
+		s.deleteBinding(guid)
+	}
+	s.writeJob(w, "service_bindings.delete")
+}
+
+func (s *Server) getJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.jobs[r.PathValue("guid")]
+	if !ok {
+		writeNotFound(w, "Job")
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// writeJob answers a request with 202 Accepted, referring to a job for the given operation (which is complete right away;
+// the progress of operations on service instances and bindings is reported by their last operation).
+// Must be called with mutex locked.
+func (s *Server) writeJob(w http.ResponseWriter, operation string) {
+	now := s.now()
+	job := &resource.Job{GUID: newGuid(), Operation: operation, State: resource.JobStateComplete, CreatedAt: now, UpdatedAt: now}
+	s.jobs[job.GUID] = job
+	w.Header().Set("Location", s.URL()+"/v3/jobs/"+job.GUID)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// writeList answers a list request (served from the given URL) with the requested page (query parameters page and per_page)
+// of the given resources.
+func writeList[R any](w http.ResponseWriter, r *http.Request, baseURL string, resources []R) {
+	query := r.URL.Query()
+	page, ok := positiveIntOf(w, query, "page", 1)
+	if !ok {
+		return
+	}
+	perPage, ok := positiveIntOf(w, query, "per_page", defaultPerPage)
+	if !ok {
+		return
+	}
+	totalPages := max((len(resources)+perPage-1)/perPage, 1)
+
+	pageLink := func(page int) resource.Link {
+		pageQuery := url.Values{}
+		for key, values := range query {
+			pageQuery[key] = values
+		}
+		pageQuery.Set("page", strconv.Itoa(page))
+		pageQuery.Set("per_page", strconv.Itoa(perPage))
+		return resource.Link{Href: baseURL + r.URL.Path + "?" + pageQuery.Encode()}
+	}
+	pagination := resource.Pagination{
+		TotalResults: len(resources),
+		TotalPages:   totalPages,
+		First:        pageLink(1),
+		Last:         pageLink(totalPages),
+	}
+	if page < totalPages {
+		pagination.Next = pageLink(page + 1)
+	}
+	if page > 1 {
+		pagination.Previous = pageLink(page - 1)
+	}
+	start := min((page-1)*perPage, len(resources))
+	end := min(start+perPage, len(resources))
+	writeJSON(w, http.StatusOK, map[string]any{
+		"pagination": pagination,
+		"resources":  append([]R{}, resources[start:end]...),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, code int, title string, detail string) {
+	writeJSON(w, status, resource.CloudFoundryErrors{Errors: []resource.CloudFoundryError{{Code: code, Title: title, Detail: detail}}})
+}
+
+func writeNotFound(w http.ResponseWriter, kind string) {
+	writeError(w, http.StatusNotFound, codeResourceNotFound, titleResourceNotFound, kind+" not found")
+}
+
+func writeUnprocessable(w http.ResponseWriter, detail string) {
+	writeError(w, http.StatusUnprocessableEntity, codeUnprocessableEntity, titleUnprocessable, detail)
+}
+
+// readJSON decodes the request body into the given value; if the body cannot be decoded, the request is answered with an error,
+// and false is returned.
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, codeMessageParseError, titleMessageParseError, "Request invalid due to parse error: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// matchesFilter tells whether the given value is one of the (comma-separated) values of the given query parameter;
+// true if the query parameter is not set.
+func matchesFilter(query url.Values, key string, value string) bool {
+	if !query.Has(key) {
+		return true
+	}
+	return slices.Contains(strings.Split(query.Get(key), ","), value)
+}
+
+// labelSelectorOf parses the label_selector query parameter; if it cannot be parsed, the request is answered with an error,
+// and false is returned.
+func labelSelectorOf(w http.ResponseWriter, query url.Values) (labels.Selector, bool) {
+	selector, err := labels.Parse(query.Get("label_selector"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadQueryParameter, titleBadQueryParameter, "Invalid label_selector value: "+err.Error())
+		return nil, false
+	}
+	return selector, true
+}
+
+func matchesLabels(selector labels.Selector, metadata *resource.Metadata) bool {
+	set := labels.Set{}
+	if metadata != nil {
+		for key, value := range metadata.Labels {
+			if value != nil {
+				set[key] = *value
+			}
+		}
+	}
+	return selector.Matches(set)
+}
+
+// positiveIntOf parses the given query parameter (returning the given default value if it is not set); if it is no positive integer,
+// the request is answered with an error, and false is returned.
+func positiveIntOf(w http.ResponseWriter, query url.Values, key string, defaultValue int) (int, bool) {
+	if !query.Has(key) {
+		return defaultValue, true
+	}
+	value, err := strconv.Atoi(query.Get(key))
+	if err != nil || value < 1 {
+		writeError(w, http.StatusBadRequest, codeBadQueryParameter, titleBadQueryParameter, "The query parameter is invalid: "+key+" must be a positive integer")
+		return 0, false
+	}
+	return value, true
+}
+
+// sorted returns the values of the given map, ordered by creation.
+func sorted[R any](resources map[string]*R, createdAt func(*R) time.Time) []*R {
+	result := make([]*R, 0, len(resources))
+	for _, r := range resources {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool { return createdAt(result[i]).Before(createdAt(result[j])) })
+	return result
+}
@@ -0,0 +1,472 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cftest
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-community/go-cfclient/v3/resource"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+// last operation states of service instances and bindings
+const (
+	stateInProgress = "in progress"
+	stateSucceeded  = "succeeded"
+	stateFailed     = "failed"
+)
+
+// Server is a fake Cloud Foundry API server; all methods are safe for concurrent use.
+type Server struct {
+	httpServer *httptest.Server
+
+	mutex sync.Mutex
+	// credentials accepted by the token endpoint; any credentials are accepted if username is empty
+	username string
+	password string
+	// access tokens issued by the token endpoint
+	tokens map[string]bool
+	// whether operations stay in progress until CompleteOperations or FailOperations is called
+	async bool
+	// requests received (method and path), in order
+	requests []string
+	// time of the last change; used to give every resource a distinct creation timestamp
+	lastChange time.Time
+
+	organizations    map[string]*resource.Organization
+	spaces           map[string]*resource.Space
+	users            map[string]*resource.User
+	roles            map[string]*resource.Role
+	apps             map[string]*resource.App
+	serviceOfferings map[string]*resource.ServiceOffering
+	servicePlans     map[string]*resource.ServicePlan
+	serviceInstances map[string]*resource.ServiceInstance
+	bindings         map[string]*resource.ServiceCredentialBinding
+	jobs             map[string]*resource.Job
+	// parameters of service instances (as last passed to the service broker), by instance guid
+	instanceParameters map[string]json.RawMessage
+	// credentials of service credential bindings, by binding guid
+	credentials map[string]map[string]any
+}
+
+// NewServer starts a fake Cloud Foundry API server without any resources; the caller should call Close when finished.
+// Clients may authenticate with any credentials, unless RequireCredentials is called.
+func NewServer() *Server {
+	s := &Server{
+		tokens:             make(map[string]bool),
+		organizations:      make(map[string]*resource.Organization),
+		spaces:             make(map[string]*resource.Space),
+		users:              make(map[string]*resource.User),
+		roles:              make(map[string]*resource.Role),
+		apps:               make(map[string]*resource.App),
+		serviceOfferings:   make(map[string]*resource.ServiceOffering),
+		servicePlans:       make(map[string]*resource.ServicePlan),
+		serviceInstances:   make(map[string]*resource.ServiceInstance),
+		bindings:           make(map[string]*resource.ServiceCredentialBinding),
+		jobs:               make(map[string]*resource.Job),
+		instanceParameters: make(map[string]json.RawMessage),
+		credentials:        make(map[string]map[string]any),
+	}
+	s.httpServer = httptest.NewServer(s.newHandler())
+	return s
+}
+
+// URL returns the API URL of the server (to be used as URL of the Cloud Foundry API endpoint).
+func (s *Server) URL() string {
+	return s.httpServer.URL
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// RequireCredentials restricts the token endpoint to the given username and password.
+func (s *Server) RequireCredentials(username string, password string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.username = username
+	s.password = password
+}
+
+// SetAsync controls whether asynchronous operations (on service instances and bindings) stay in progress
+// until CompleteOperations or FailOperations is called (true), or complete immediately (false, the default).
+func (s *Server) SetAsync(async bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.async = async
+}
+
+// CompleteOperations lets all operations in progress succeed; service instances and bindings being deleted are removed.
+func (s *Server) CompleteOperations() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for guid, binding := range s.bindings {
+		if binding.LastOperation.State == stateInProgress {
+			s.finishOperation(&binding.LastOperation, stateSucceeded, "")
+			if binding.LastOperation.Type == "delete" {
+				s.deleteBinding(guid)
+			}
+		}
+	}
+	for guid, instance := range s.serviceInstances {
+		if instance.LastOperation.State == stateInProgress {
+			s.finishOperation(&instance.LastOperation, stateSucceeded, "")
+			if instance.LastOperation.Type == "delete" {
+				s.deleteServiceInstance(guid)
+			}
+		}
+	}
+}
+
+// FailOperations lets all operations in progress fail with the given description.
+func (s *Server) FailOperations(description string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, binding := range s.bindings {
+		if binding.LastOperation.State == stateInProgress {
+			s.finishOperation(&binding.LastOperation, stateFailed, description)
+		}
+	}
+	for _, instance := range s.serviceInstances {
+		if instance.LastOperation.State == stateInProgress {
+			s.finishOperation(&instance.LastOperation, stateFailed, description)
+		}
+	}
+}
+
+// AddOrganization adds an organization with the given name, and returns its guid.
+func (s *Server) AddOrganization(name string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	organization := &resource.Organization{GUID: newGuid(), Name: name, CreatedAt: now, UpdatedAt: now, Metadata: newMetadata()}
+	s.organizations[organization.GUID] = organization
+	return organization.GUID
+}
+
+// AddSpace adds a space with the given name to the organization with the given guid, and returns its guid.
+func (s *Server) AddSpace(organizationGuid string, name string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.addSpace(organizationGuid, name, nil).GUID
+}
+
+// AddUser adds a user with the given name and origin (such as uaa), and returns its guid.
+func (s *Server) AddUser(username string, origin string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	user := &resource.User{GUID: newGuid(), Username: username, PresentationName: username, Origin: origin, CreatedAt: now, UpdatedAt: now, Metadata: newMetadata()}
+	s.users[user.GUID] = user
+	return user.GUID
+}
+
+// AddApp adds an app with the given name to the space with the given guid, and returns its guid.
+func (s *Server) AddApp(spaceGuid string, name string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	app := &resource.App{GUID: newGuid(), Name: name, State: "STARTED", CreatedAt: now, UpdatedAt: now, Metadata: newMetadata()}
+	app.Relationships.Space.Data = &resource.Relationship{GUID: spaceGuid}
+	s.apps[app.GUID] = app
+	return app.GUID
+}
+
+// AddServiceOffering adds a service offering with the given name, and returns its guid.
+func (s *Server) AddServiceOffering(name string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	serviceOffering := &resource.ServiceOffering{GUID: newGuid(), Name: name, Available: true, CreatedAt: now, UpdatedAt: now, Metadata: newMetadata()}
+	s.serviceOfferings[serviceOffering.GUID] = serviceOffering
+	return serviceOffering.GUID
+}
+
+// AddServicePlan adds a service plan with the given name to the service offering with the given guid, and returns its guid.
+// Service plans are visible in all spaces.
+func (s *Server) AddServicePlan(serviceOfferingGuid string, name string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	servicePlan := &resource.ServicePlan{GUID: newGuid(), Name: name, Available: true, VisibilityType: "public", CreatedAt: now, UpdatedAt: now, Metadata: newMetadata()}
+	servicePlan.Relationships.ServiceOffering.Data = &resource.Relationship{GUID: serviceOfferingGuid}
+	s.servicePlans[servicePlan.GUID] = servicePlan
+	return servicePlan.GUID
+}
+
+// SetServicePlanMaintenanceInfo sets the version offered by the service plan with the given guid; service instances of the plan
+// with another version report that an upgrade is available.
+func (s *Server) SetServicePlanMaintenanceInfo(servicePlanGuid string, version string, description string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if servicePlan, ok := s.servicePlans[servicePlanGuid]; ok {
+		servicePlan.MaintenanceInfo = resource.ServicePlanMaintenanceInfo{Version: version, Description: description}
+		servicePlan.UpdatedAt = s.now()
+	}
+}
+
+// SetBindingCredentials replaces the credentials of the service credential binding with the given guid
+// (by default, bindings get generated credentials).
+func (s *Server) SetBindingCredentials(bindingGuid string, credentials map[string]any) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.bindings[bindingGuid]; ok {
+		s.credentials[bindingGuid] = credentials
+	}
+}
+
+// Spaces returns (copies of) all spaces.
+func (s *Server) Spaces() []*resource.Space {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return cloneAll(sorted(s.spaces, func(space *resource.Space) time.Time { return space.CreatedAt }))
+}
+
+// Roles returns (copies of) all space roles.
+func (s *Server) Roles() []*resource.Role {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return cloneAll(sorted(s.roles, func(role *resource.Role) time.Time { return role.CreatedAt }))
+}
+
+// ServiceInstance returns (a copy of) the service instance with the given guid, or nil if there is none.
+func (s *Server) ServiceInstance(guid string) *resource.ServiceInstance {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	instance, ok := s.serviceInstances[guid]
+	if !ok {
+		return nil
+	}
+	return s.serviceInstanceView(instance)
+}
+
+// ServiceInstances returns (copies of) all service instances.
+func (s *Server) ServiceInstances() []*resource.ServiceInstance {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var result []*resource.ServiceInstance
+	for _, instance := range sorted(s.serviceInstances, func(instance *resource.ServiceInstance) time.Time { return instance.CreatedAt }) {
+		result = append(result, s.serviceInstanceView(instance))
+	}
+	return result
+}
+
+// ServiceInstanceParameters returns the parameters last passed to the service broker for the service instance with the given guid
+// (nil if there are none).
+func (s *Server) ServiceInstanceParameters(guid string) map[string]any {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var parameters map[string]any
+	if rawParameters, ok := s.instanceParameters[guid]; ok {
+		_ = json.Unmarshal(rawParameters, &parameters)
+	}
+	return parameters
+}
+
+// ServiceCredentialBinding returns (a copy of) the service credential binding with the given guid, or nil if there is none.
+func (s *Server) ServiceCredentialBinding(guid string) *resource.ServiceCredentialBinding {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	binding, ok := s.bindings[guid]
+	if !ok {
+		return nil
+	}
+	return clone(binding)
+}
+
+// ServiceCredentialBindings returns (copies of) all service credential bindings.
+func (s *Server) ServiceCredentialBindings() []*resource.ServiceCredentialBinding {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return cloneAll(sorted(s.bindings, func(binding *resource.ServiceCredentialBinding) time.Time { return binding.CreatedAt }))
+}
+
+// Requests returns the method and path (such as "POST /v3/service_instances") of all API requests received so far, in order;
+// requests to the root and token endpoints are not included.
+func (s *Server) Requests() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+// Must be called with mutex locked.
+func (s *Server) addSpace(organizationGuid string, name string, metadata *resource.Metadata) *resource.Space {
+	now := s.now()
+	space := &resource.Space{
+		GUID:      newGuid(),
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Relationships: &resource.SpaceRelationships{
+			Organization: &resource.ToOneRelationship{Data: &resource.Relationship{GUID: organizationGuid}},
+		},
+		Metadata: mergeMetadata(nil, metadata),
+	}
+	s.spaces[space.GUID] = space
+	return space
+}
+
+// Must be called with mutex locked.
+func (s *Server) deleteSpace(guid string) {
+	for instanceGuid, instance := range s.serviceInstances {
+		if instance.Relationships.Space.Data.GUID == guid {
+			s.deleteServiceInstance(instanceGuid)
+		}
+	}
+	for roleGuid, role := range s.roles {
+		if role.Relationships.Space.Data.GUID == guid {
+			delete(s.roles, roleGuid)
+		}
+	}
+	for appGuid, app := range s.apps {
+		if app.Relationships.Space.Data.GUID == guid {
+			delete(s.apps, appGuid)
+		}
+	}
+	delete(s.spaces, guid)
+}
+
+// Must be called with mutex locked.
+func (s *Server) deleteServiceInstance(guid string) {
+	for bindingGuid, binding := range s.bindings {
+		if binding.Relationships.ServiceInstance.Data.GUID == guid {
+			s.deleteBinding(bindingGuid)
+		}
+	}
+	delete(s.serviceInstances, guid)
+	delete(s.instanceParameters, guid)
+}
+
+// Must be called with mutex locked.
+func (s *Server) deleteBinding(guid string) {
+	delete(s.bindings, guid)
+	delete(s.credentials, guid)
+}
+
+// startOperation starts an operation of the given type (create, update or delete) on a service instance or binding;
+// the operation completes immediately, unless the server is asynchronous.
+// Must be called with mutex locked.
+func (s *Server) startOperation(lastOperation *resource.LastOperation, operationType string) {
+	now := s.now()
+	*lastOperation = resource.LastOperation{Type: operationType, State: stateInProgress, CreatedAt: now, UpdatedAt: now}
+	if !s.async {
+		lastOperation.State = stateSucceeded
+	}
+}
+
+// Must be called with mutex locked.
+func (s *Server) finishOperation(lastOperation *resource.LastOperation, state string, description string) {
+	lastOperation.State = state
+	lastOperation.Description = description
+	lastOperation.UpdatedAt = s.now()
+}
+
+// serviceInstanceView returns a copy of the given service instance, telling whether an upgrade is available.
+// Must be called with mutex locked.
+func (s *Server) serviceInstanceView(instance *resource.ServiceInstance) *resource.ServiceInstance {
+	result := clone(instance)
+	upgradeAvailable := false
+	if servicePlan, ok := s.servicePlans[instance.Relationships.ServicePlan.Data.GUID]; ok && servicePlan.MaintenanceInfo.Version != "" {
+		upgradeAvailable = instance.MaintenanceInfo == nil || instance.MaintenanceInfo.Version != servicePlan.MaintenanceInfo.Version
+	}
+	result.UpgradeAvailable = &upgradeAvailable
+	return result
+}
+
+// now returns the current time, but at least one nanosecond after the previous result (such that resources are ordered by creation).
+// Must be called with mutex locked.
+func (s *Server) now() time.Time {
+	now := time.Now().UTC()
+	if !now.After(s.lastChange) {
+		now = s.lastChange.Add(time.Nanosecond)
+	}
+	s.lastChange = now
+	return now
+}
+
+func newGuid() string {
+	return string(uuid.NewUUID())
+}
+
+func newMetadata() *resource.Metadata {
+	return &resource.Metadata{Labels: map[string]*string{}, Annotations: map[string]*string{}}
+}
+
+// mergeMetadata applies the given metadata update to the given metadata (both may be nil), and returns the result;
+// labels and annotations with null value are removed.
+func mergeMetadata(metadata *resource.Metadata, update *resource.Metadata) *resource.Metadata {
+	result := newMetadata()
+	if metadata != nil {
+		for key, value := range metadata.Labels {
+			result.Labels[key] = value
+		}
+		for key, value := range metadata.Annotations {
+			result.Annotations[key] = value
+		}
+	}
+	if update != nil {
+		for key, value := range update.Labels {
+			if value == nil {
+				delete(result.Labels, key)
+			} else {
+				result.Labels[key] = value
+			}
+		}
+		for key, value := range update.Annotations {
+			if value == nil {
+				delete(result.Annotations, key)
+			} else {
+				result.Annotations[key] = value
+			}
+		}
+	}
+	return result
+}
+
+// clone returns a deep copy of the given resource.
+func clone[R any](r *R) *R {
+	data, err := json.Marshal(r)
+	if err != nil {
+		panic(err)
+	}
+	result := new(R)
+	if err := json.Unmarshal(data, result); err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func cloneAll[R any](resources []*R) []*R {
+	result := make([]*R, len(resources))
+	for i, r := range resources {
+		result[i] = clone(r)
+	}
+	return result
+}
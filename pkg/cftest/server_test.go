@@ -0,0 +1,218 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cftest
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sap/cf-service-operator/internal/cf"
+	"github.com/sap/cf-service-operator/internal/config"
+	"github.com/sap/cf-service-operator/internal/facade"
+)
+
+var _ = Describe("Fake Cloud Foundry API server | Server", func() {
+	const (
+		username = "operator"
+		password = "secret"
+	)
+	var ctx context.Context
+	var server *Server
+	var cfg *config.Config
+	var organizationGuid string
+	var spaceGuid string
+	var servicePlanGuid string
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		server = NewServer()
+		DeferCleanup(server.Close)
+		server.RequireCredentials(username, password)
+		cfg = config.Defaults()
+		// small pages, such that paging is exercised as well
+		cfg.ListPageSize = 2
+
+		organizationGuid = server.AddOrganization("org")
+		spaceGuid = server.AddSpace(organizationGuid, "space")
+		serviceOfferingGuid := server.AddServiceOffering("database")
+		server.AddServicePlan(serviceOfferingGuid, "small")
+		servicePlanGuid = server.AddServicePlan(serviceOfferingGuid, "large")
+		server.AddServicePlan(server.AddServiceOffering("cache"), "large")
+	})
+
+	newSpaceClient := func() facade.SpaceClient {
+		client, err := cf.NewSpaceClient(spaceGuid, server.URL(), username, password, cfg)
+		Expect(err).ToNot(HaveOccurred())
+		return client
+	}
+
+	It("should reject clients with wrong credentials", func() {
+		client, err := cf.NewSpaceClient(spaceGuid, server.URL(), username, "wrong", nil)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.GetInstance(ctx, map[string]string{"owner": "owner-uid"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should manage spaces and space roles through the organization client", func() {
+		server.AddUser("developer", "uaa")
+		client, err := cf.NewOrganizationClient("org", server.URL(), username, password, cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		owner := facade.OwnerRef{UID: "space-uid", Namespace: "app", Name: "space"}
+		Expect(client.CreateSpace(ctx, "new-space", owner, 1)).Error().NotTo(HaveOccurred())
+		space, err := client.GetSpace(ctx, owner.UID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(space).ToNot(BeNil())
+		Expect(space.Name).To(Equal("new-space"))
+		Expect(space.Generation).To(Equal(int64(1)))
+
+		Expect(client.AddDeveloper(ctx, space.Guid, "developer", "uaa")).To(Succeed())
+		Expect(client.AddDeveloper(ctx, space.Guid, "developer", "uaa")).To(Succeed())
+		Expect(server.Roles()).To(HaveLen(1))
+		Expect(client.RemoveDeveloper(ctx, space.Guid, "developer", "uaa")).To(Succeed())
+		Expect(server.Roles()).To(BeEmpty())
+
+		Expect(client.UpdateSpace(ctx, space.Guid, owner, "renamed-space", 2)).To(Succeed())
+		space, err = client.GetSpace(ctx, owner.UID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(space.Name).To(Equal("renamed-space"))
+		Expect(space.Generation).To(Equal(int64(2)))
+
+		Expect(client.DeleteSpace(ctx, space.Guid, owner)).To(Succeed())
+		Expect(server.Spaces()).To(HaveLen(1))
+	})
+
+	It("should cache created spaces, and look up created instances again", func() {
+		cfg.IsResourceCacheEnabled = true
+		organizationClient, err := cf.NewOrganizationClient("org", server.URL(), username, password, cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		owner := facade.OwnerRef{UID: "space-uid"}
+		created, err := organizationClient.CreateSpace(ctx, "new-space", owner, 1)
+		Expect(err).ToNot(HaveOccurred())
+		space, err := organizationClient.GetSpace(ctx, owner.UID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(space.Name).To(Equal("new-space"))
+		Expect(space).To(Equal(created))
+		Expect(server.Requests()).ToNot(ContainElement("GET /v3/spaces"))
+
+		client := newSpaceClient()
+		Expect(client.CreateInstance(ctx, "instance", servicePlanGuid, nil, nil, nil, facade.OwnerRef{UID: "instance-uid"}, 1)).Error().NotTo(HaveOccurred())
+		instance, err := client.GetInstance(ctx, map[string]string{"owner": "instance-uid"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instance).ToNot(BeNil())
+	})
+
+	It("should manage service instances and bindings through the space client", func() {
+		client := newSpaceClient()
+		planGuid, err := client.FindServicePlan(ctx, "database", "large", spaceGuid)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(planGuid).To(Equal(servicePlanGuid))
+
+		owner := facade.OwnerRef{UID: "instance-uid", Namespace: "app", Name: "instance"}
+		Expect(client.CreateInstance(ctx, "instance", servicePlanGuid, map[string]interface{}{"size": 1}, []string{"tag"}, nil, owner, 1)).Error().NotTo(HaveOccurred())
+		instance, err := client.GetInstance(ctx, map[string]string{"owner": owner.UID})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instance).ToNot(BeNil())
+		Expect(instance.State).To(Equal(facade.InstanceStateReady))
+		Expect(instance.OwnerName).To(Equal("instance"))
+		Expect(client.GetInstanceParameters(ctx, instance.Guid)).To(Equal(map[string]interface{}{"size": float64(1)}))
+
+		// names are unique per space
+		Expect(client.CreateInstance(ctx, "instance", servicePlanGuid, nil, nil, nil, facade.OwnerRef{UID: "other-uid"}, 1)).Error().To(MatchError(ContainSubstring("name is taken")))
+
+		bindingOwner := facade.OwnerRef{UID: "binding-uid"}
+		Expect(client.CreateBinding(ctx, "binding", instance.Guid, "", nil, nil, bindingOwner, 1)).Error().NotTo(HaveOccurred())
+		binding, err := client.GetBinding(ctx, map[string]string{"owner": bindingOwner.UID})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(binding).ToNot(BeNil())
+		Expect(binding.State).To(Equal(facade.BindingStateReady))
+		Expect(client.GetBindingCredentials(ctx, binding.Guid)).To(HaveKey("password"))
+
+		server.SetBindingCredentials(binding.Guid, map[string]any{"password": "rotated"})
+		Expect(client.GetBindingCredentials(ctx, binding.Guid)).To(Equal(map[string]interface{}{"password": "rotated"}))
+
+		// instances with bindings cannot be deleted
+		Expect(client.DeleteInstance(ctx, instance.Guid, owner)).To(HaveOccurred())
+		Expect(client.DeleteBinding(ctx, binding.Guid, bindingOwner)).To(Succeed())
+		Expect(client.DeleteInstance(ctx, instance.Guid, owner)).To(Succeed())
+		Expect(server.ServiceInstances()).To(BeEmpty())
+		Expect(server.ServiceCredentialBindings()).To(BeEmpty())
+	})
+
+	It("should keep operations in progress until they are completed", func() {
+		server.SetAsync(true)
+		client := newSpaceClient()
+
+		owner := facade.OwnerRef{UID: "instance-uid"}
+		created, err := client.CreateInstance(ctx, "instance", servicePlanGuid, nil, nil, nil, owner, 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(created.State).To(Equal(facade.InstanceStateCreating))
+		Expect(created.Guid).To(BeEmpty())
+		instance, err := client.GetInstance(ctx, map[string]string{"name": "instance"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instance.State).To(Equal(facade.InstanceStateCreating))
+
+		// updates are rejected while an operation is in progress
+		Expect(client.UpdateInstance(ctx, instance.Guid, owner, "", "", map[string]interface{}{"size": 2}, nil, nil, 2)).To(HaveOccurred())
+
+		server.CompleteOperations()
+		instance, err = client.GetInstance(ctx, map[string]string{"name": "instance"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instance.State).To(Equal(facade.InstanceStateReady))
+
+		Expect(client.UpdateInstance(ctx, instance.Guid, owner, "", "", map[string]interface{}{"size": 2}, nil, nil, 2)).To(Succeed())
+		server.FailOperations("broker unavailable")
+		instance, err = client.GetInstance(ctx, map[string]string{"name": "instance"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instance.State).To(Equal(facade.InstanceStateUpdateFailed))
+		Expect(instance.StateDescription).To(Equal("broker unavailable"))
+
+		Expect(client.DeleteInstance(ctx, instance.Guid, owner)).To(Succeed())
+		Expect(server.ServiceInstance(instance.Guid).LastOperation.Type).To(Equal("delete"))
+		server.CompleteOperations()
+		Expect(server.ServiceInstance(instance.Guid)).To(BeNil())
+	})
+
+	It("should offer upgrades of service instances", func() {
+		client := newSpaceClient()
+		owner := facade.OwnerRef{UID: "instance-uid"}
+		Expect(client.CreateInstance(ctx, "instance", servicePlanGuid, nil, nil, nil, owner, 1)).Error().NotTo(HaveOccurred())
+		server.SetServicePlanMaintenanceInfo(servicePlanGuid, "2.0.0", "new version")
+
+		instance, err := client.GetInstance(ctx, map[string]string{"name": "instance"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instance.UpgradeAvailable).To(BeTrue())
+		Expect(instance.AvailableMaintenanceInfo.Version).To(Equal("2.0.0"))
+
+		Expect(client.UpgradeInstance(ctx, instance.Guid, owner, *instance.AvailableMaintenanceInfo)).To(Succeed())
+		Expect(*server.ServiceInstance(instance.Guid).UpgradeAvailable).To(BeFalse())
+	})
+
+	It("should list all pages of service instances and bindings", func() {
+		client := newSpaceClient()
+		for _, name := range []string{"a", "b", "c", "d", "e"} {
+			Expect(client.CreateInstance(ctx, name, servicePlanGuid, nil, nil, nil, facade.OwnerRef{UID: name + "-uid"}, 1)).Error().NotTo(HaveOccurred())
+		}
+		otherSpaceGuid := server.AddSpace(organizationGuid, "other-space")
+		otherClient, err := cf.NewSpaceClient(otherSpaceGuid, server.URL(), username, password, cfg)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(otherClient.CreateInstance(ctx, "a", servicePlanGuid, nil, nil, nil, facade.OwnerRef{UID: "other-uid"}, 1)).Error().NotTo(HaveOccurred())
+
+		instances, err := client.ListInstances(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instances).To(HaveLen(5))
+		for _, instance := range instances {
+			Expect(client.CreateBinding(ctx, "binding", instance.Guid, "", nil, nil, facade.OwnerRef{UID: instance.Owner + "-binding"}, 1)).Error().NotTo(HaveOccurred())
+		}
+		bindings, err := client.ListBindings(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bindings).To(HaveLen(5))
+		Expect(server.Requests()).To(ContainElement("GET /v3/service_credential_bindings"))
+	})
+})
@@ -0,0 +1,18 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cftest
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCFTest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Fake Cloud Foundry Server Suite")
+}
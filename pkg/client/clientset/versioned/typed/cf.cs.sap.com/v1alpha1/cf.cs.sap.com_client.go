@@ -17,9 +17,14 @@ import (
 
 type CfV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	ClusterServiceBindingsGetter
 	ClusterSpacesGetter
+	OperatorConfigsGetter
+	RoutesGetter
+	RouteBindingsGetter
 	ServiceBindingsGetter
 	ServiceInstancesGetter
+	ServiceOperatorReportsGetter
 	SpacesGetter
 }
 
@@ -28,10 +33,26 @@ type CfV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *CfV1alpha1Client) ClusterServiceBindings() ClusterServiceBindingInterface {
+	return newClusterServiceBindings(c)
+}
+
 func (c *CfV1alpha1Client) ClusterSpaces() ClusterSpaceInterface {
 	return newClusterSpaces(c)
 }
 
+func (c *CfV1alpha1Client) OperatorConfigs() OperatorConfigInterface {
+	return newOperatorConfigs(c)
+}
+
+func (c *CfV1alpha1Client) Routes(namespace string) RouteInterface {
+	return newRoutes(c, namespace)
+}
+
+func (c *CfV1alpha1Client) RouteBindings(namespace string) RouteBindingInterface {
+	return newRouteBindings(c, namespace)
+}
+
 func (c *CfV1alpha1Client) ServiceBindings(namespace string) ServiceBindingInterface {
 	return newServiceBindings(c, namespace)
 }
@@ -40,6 +61,10 @@ func (c *CfV1alpha1Client) ServiceInstances(namespace string) ServiceInstanceInt
 	return newServiceInstances(c, namespace)
 }
 
+func (c *CfV1alpha1Client) ServiceOperatorReports() ServiceOperatorReportInterface {
+	return newServiceOperatorReports(c)
+}
+
 func (c *CfV1alpha1Client) Spaces(namespace string) SpaceInterface {
 	return newSpaces(c, namespace)
 }
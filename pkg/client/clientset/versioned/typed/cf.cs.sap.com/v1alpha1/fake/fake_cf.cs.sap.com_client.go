@@ -17,10 +17,26 @@ type FakeCfV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeCfV1alpha1) ClusterServiceBindings() v1alpha1.ClusterServiceBindingInterface {
+	return &FakeClusterServiceBindings{c}
+}
+
 func (c *FakeCfV1alpha1) ClusterSpaces() v1alpha1.ClusterSpaceInterface {
 	return &FakeClusterSpaces{c}
 }
 
+func (c *FakeCfV1alpha1) OperatorConfigs() v1alpha1.OperatorConfigInterface {
+	return &FakeOperatorConfigs{c}
+}
+
+func (c *FakeCfV1alpha1) Routes(namespace string) v1alpha1.RouteInterface {
+	return &FakeRoutes{c, namespace}
+}
+
+func (c *FakeCfV1alpha1) RouteBindings(namespace string) v1alpha1.RouteBindingInterface {
+	return &FakeRouteBindings{c, namespace}
+}
+
 func (c *FakeCfV1alpha1) ServiceBindings(namespace string) v1alpha1.ServiceBindingInterface {
 	return &FakeServiceBindings{c, namespace}
 }
@@ -29,6 +45,10 @@ func (c *FakeCfV1alpha1) ServiceInstances(namespace string) v1alpha1.ServiceInst
 	return &FakeServiceInstances{c, namespace}
 }
 
+func (c *FakeCfV1alpha1) ServiceOperatorReports() v1alpha1.ServiceOperatorReportInterface {
+	return &FakeServiceOperatorReports{c}
+}
+
 func (c *FakeCfV1alpha1) Spaces(namespace string) v1alpha1.SpaceInterface {
 	return &FakeSpaces{c, namespace}
 }
@@ -7,10 +7,20 @@ SPDX-License-Identifier: Apache-2.0
 
 package v1alpha1
 
+type ClusterServiceBindingExpansion interface{}
+
 type ClusterSpaceExpansion interface{}
 
+type OperatorConfigExpansion interface{}
+
+type RouteExpansion interface{}
+
+type RouteBindingExpansion interface{}
+
 type ServiceBindingExpansion interface{}
 
 type ServiceInstanceExpansion interface{}
 
+type ServiceOperatorReportExpansion interface{}
+
 type SpaceExpansion interface{}
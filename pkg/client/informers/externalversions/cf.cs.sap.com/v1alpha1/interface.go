@@ -13,12 +13,22 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// ClusterServiceBindings returns a ClusterServiceBindingInformer.
+	ClusterServiceBindings() ClusterServiceBindingInformer
 	// ClusterSpaces returns a ClusterSpaceInformer.
 	ClusterSpaces() ClusterSpaceInformer
+	// OperatorConfigs returns a OperatorConfigInformer.
+	OperatorConfigs() OperatorConfigInformer
+	// Routes returns a RouteInformer.
+	Routes() RouteInformer
+	// RouteBindings returns a RouteBindingInformer.
+	RouteBindings() RouteBindingInformer
 	// ServiceBindings returns a ServiceBindingInformer.
 	ServiceBindings() ServiceBindingInformer
 	// ServiceInstances returns a ServiceInstanceInformer.
 	ServiceInstances() ServiceInstanceInformer
+	// ServiceOperatorReports returns a ServiceOperatorReportInformer.
+	ServiceOperatorReports() ServiceOperatorReportInformer
 	// Spaces returns a SpaceInformer.
 	Spaces() SpaceInformer
 }
@@ -34,11 +44,31 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// ClusterServiceBindings returns a ClusterServiceBindingInformer.
+func (v *version) ClusterServiceBindings() ClusterServiceBindingInformer {
+	return &clusterServiceBindingInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // ClusterSpaces returns a ClusterSpaceInformer.
 func (v *version) ClusterSpaces() ClusterSpaceInformer {
 	return &clusterSpaceInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// OperatorConfigs returns a OperatorConfigInformer.
+func (v *version) OperatorConfigs() OperatorConfigInformer {
+	return &operatorConfigInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// Routes returns a RouteInformer.
+func (v *version) Routes() RouteInformer {
+	return &routeInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// RouteBindings returns a RouteBindingInformer.
+func (v *version) RouteBindings() RouteBindingInformer {
+	return &routeBindingInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // ServiceBindings returns a ServiceBindingInformer.
 func (v *version) ServiceBindings() ServiceBindingInformer {
 	return &serviceBindingInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -49,6 +79,11 @@ func (v *version) ServiceInstances() ServiceInstanceInformer {
 	return &serviceInstanceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// ServiceOperatorReports returns a ServiceOperatorReportInformer.
+func (v *version) ServiceOperatorReports() ServiceOperatorReportInformer {
+	return &serviceOperatorReportInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // Spaces returns a SpaceInformer.
 func (v *version) Spaces() SpaceInformer {
 	return &spaceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -42,12 +42,22 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=cf.cs.sap.com, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("clusterservicebindings"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cf().V1alpha1().ClusterServiceBindings().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("clusterspaces"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cf().V1alpha1().ClusterSpaces().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("operatorconfigs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cf().V1alpha1().OperatorConfigs().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("routes"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cf().V1alpha1().Routes().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("routebindings"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cf().V1alpha1().RouteBindings().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("servicebindings"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cf().V1alpha1().ServiceBindings().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("serviceinstances"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cf().V1alpha1().ServiceInstances().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("serviceoperatorreports"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cf().V1alpha1().ServiceOperatorReports().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("spaces"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cf().V1alpha1().Spaces().Informer()}, nil
 
@@ -7,10 +7,34 @@ SPDX-License-Identifier: Apache-2.0
 
 package v1alpha1
 
+// ClusterServiceBindingListerExpansion allows custom methods to be added to
+// ClusterServiceBindingLister.
+type ClusterServiceBindingListerExpansion interface{}
+
 // ClusterSpaceListerExpansion allows custom methods to be added to
 // ClusterSpaceLister.
 type ClusterSpaceListerExpansion interface{}
 
+// OperatorConfigListerExpansion allows custom methods to be added to
+// OperatorConfigLister.
+type OperatorConfigListerExpansion interface{}
+
+// RouteListerExpansion allows custom methods to be added to
+// RouteLister.
+type RouteListerExpansion interface{}
+
+// RouteNamespaceListerExpansion allows custom methods to be added to
+// RouteNamespaceLister.
+type RouteNamespaceListerExpansion interface{}
+
+// RouteBindingListerExpansion allows custom methods to be added to
+// RouteBindingLister.
+type RouteBindingListerExpansion interface{}
+
+// RouteBindingNamespaceListerExpansion allows custom methods to be added to
+// RouteBindingNamespaceLister.
+type RouteBindingNamespaceListerExpansion interface{}
+
 // ServiceBindingListerExpansion allows custom methods to be added to
 // ServiceBindingLister.
 type ServiceBindingListerExpansion interface{}
@@ -27,6 +51,10 @@ type ServiceInstanceListerExpansion interface{}
 // ServiceInstanceNamespaceLister.
 type ServiceInstanceNamespaceListerExpansion interface{}
 
+// ServiceOperatorReportListerExpansion allows custom methods to be added to
+// ServiceOperatorReportLister.
+type ServiceOperatorReportListerExpansion interface{}
+
 // SpaceListerExpansion allows custom methods to be added to
 // SpaceLister.
 type SpaceListerExpansion interface{}
@@ -0,0 +1,12 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package clientutil provides helpers for programs (such as other operators) which manage the resources of the cf-service-operator
+// through the typed client (package github.com/sap/cf-service-operator/pkg/client/clientset/versioned), for example waiting
+// until a service instance is ready, or until the credentials of a service binding were written to its secret.
+//
+// Typed informers and listers are generated as well (packages github.com/sap/cf-service-operator/pkg/client/informers/externalversions
+// and github.com/sap/cf-service-operator/pkg/client/listers); see the package examples.
+package clientutil
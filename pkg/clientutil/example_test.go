@@ -0,0 +1,70 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package clientutil_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/pkg/client/clientset/versioned"
+	"github.com/sap/cf-service-operator/pkg/client/informers/externalversions"
+	"github.com/sap/cf-service-operator/pkg/clientutil"
+)
+
+// Waits for the credentials of a service binding.
+func ExampleWaitForBindingSecret() {
+	config, err := clientcmd.BuildConfigFromFlags("", clientcmd.RecommendedHomeFile)
+	if err != nil {
+		panic(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	secret, err := clientutil.WaitForBindingSecret(ctx, versioned.NewForConfigOrDie(config), kubernetes.NewForConfigOrDie(config), "default", "example-binding")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(secret.Name)
+}
+
+// Lists service instances from an informer cache, and watches them for changes.
+func Example_informers() {
+	config, err := clientcmd.BuildConfigFromFlags("", clientcmd.RecommendedHomeFile)
+	if err != nil {
+		panic(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	factory := externalversions.NewSharedInformerFactoryWithOptions(versioned.NewForConfigOrDie(config), 10*time.Minute, externalversions.WithNamespace("default"))
+	informer := factory.Cf().V1alpha1().ServiceInstances()
+	if _, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if serviceInstance := newObj.(*cfv1alpha1.ServiceInstance); serviceInstance.IsReady() {
+				fmt.Println("ready:", serviceInstance.Name)
+			}
+		},
+	}); err != nil {
+		panic(err)
+	}
+	factory.Start(ctx.Done())
+	factory.WaitForCacheSync(ctx.Done())
+
+	serviceInstances, err := informer.Lister().ServiceInstances("default").List(labels.Everything())
+	if err != nil {
+		panic(err)
+	}
+	for _, serviceInstance := range serviceInstances {
+		fmt.Println(serviceInstance.Name, serviceInstance.Status.State)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package clientutil
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/pkg/client/clientset/versioned"
+)
+
+// Interval in which the waiting helpers poll the API server
+var pollInterval = 2 * time.Second
+
+// WaitForServiceInstanceReady waits until the given service instance is ready, that is, until its Ready condition is True
+// for its current generation, and returns the ready object. Objects which do not exist (yet) are waited for as well.
+// Waiting ends with an error if the context is done, or if the service instance failed and its retries are exhausted;
+// callers should therefore pass a context with deadline.
+func WaitForServiceInstanceReady(ctx context.Context, c versioned.Interface, namespace string, name string) (*cfv1alpha1.ServiceInstance, error) {
+	var serviceInstance *cfv1alpha1.ServiceInstance
+	err := wait.PollUntilContextCancel(ctx, pollInterval, true, func(ctx context.Context) (bool, error) {
+		obj, err := c.CfV1alpha1().ServiceInstances(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		serviceInstance = obj
+		if serviceInstance.IsReady() {
+			return true, nil
+		}
+		status := &serviceInstance.Status
+		if status.State == cfv1alpha1.ServiceInstanceStateError && status.MaxRetries > 0 && status.RetryCounter >= status.MaxRetries {
+			return false, fmt.Errorf("service instance %s/%s failed: %s", namespace, name, getServiceInstanceMessage(serviceInstance))
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return serviceInstance, nil
+}
+
+// WaitForBindingSecret waits until the given service binding is ready, and its credentials were written to the binding secret,
+// and returns that secret (which is read from the namespace given by spec.secretNamespace if set). Bindings which store their
+// credentials in an external secret store (without binding secret) never satisfy the condition.
+// Waiting ends with an error if the context is done; callers should therefore pass a context with deadline.
+func WaitForBindingSecret(ctx context.Context, c versioned.Interface, kc kubernetes.Interface, namespace string, name string) (*corev1.Secret, error) {
+	var secret *corev1.Secret
+	err := wait.PollUntilContextCancel(ctx, pollInterval, true, func(ctx context.Context) (bool, error) {
+		serviceBinding, err := c.CfV1alpha1().ServiceBindings(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		if !serviceBinding.IsReady() || serviceBinding.Status.SecretName == "" {
+			return false, nil
+		}
+		if condition := serviceBinding.GetCondition(cfv1alpha1.ServiceBindingConditionCredentialsReady); condition != nil && condition.Status != cfv1alpha1.ConditionTrue {
+			return false, nil
+		}
+		secretNamespace := serviceBinding.Spec.SecretNamespace
+		if secretNamespace == "" {
+			secretNamespace = serviceBinding.Namespace
+		}
+		obj, err := kc.CoreV1().Secrets(secretNamespace).Get(ctx, serviceBinding.Status.SecretName, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		secret = obj
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+func getServiceInstanceMessage(serviceInstance *cfv1alpha1.ServiceInstance) string {
+	if condition := serviceInstance.GetReadyCondition(); condition != nil && condition.Message != "" {
+		return condition.Message
+	}
+	return string(serviceInstance.Status.State)
+}
@@ -0,0 +1,98 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package clientutil
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	cfv1alpha1 "github.com/sap/cf-service-operator/api/v1alpha1"
+	"github.com/sap/cf-service-operator/pkg/client/clientset/versioned/fake"
+)
+
+var _ = Describe("Wait for resources | WaitForServiceInstanceReady, WaitForBindingSecret", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+
+	BeforeEach(func() {
+		DeferCleanup(func(interval time.Duration) { pollInterval = interval }, pollInterval)
+		pollInterval = 10 * time.Millisecond
+		ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		DeferCleanup(func() { cancel() })
+	})
+
+	readyCondition := func(status cfv1alpha1.ConditionStatus) []cfv1alpha1.ServiceInstanceCondition {
+		return []cfv1alpha1.ServiceInstanceCondition{{Type: cfv1alpha1.ServiceInstanceConditionReady, Status: status, Message: "broker failed"}}
+	}
+
+	It("should wait until the service instance is ready for its current generation", func() {
+		serviceInstance := &cfv1alpha1.ServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "instance", Generation: 2},
+			Status:     cfv1alpha1.ServiceInstanceStatus{ObservedGeneration: 1, Conditions: readyCondition(cfv1alpha1.ConditionTrue)},
+		}
+		c := fake.NewSimpleClientset(serviceInstance)
+		go func() {
+			defer GinkgoRecover()
+			time.Sleep(50 * time.Millisecond)
+			serviceInstance.Status.ObservedGeneration = 2
+			_, err := c.CfV1alpha1().ServiceInstances("app").UpdateStatus(context.Background(), serviceInstance, metav1.UpdateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+		}()
+
+		ready, err := WaitForServiceInstanceReady(ctx, c, "app", "instance")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ready.Status.ObservedGeneration).To(Equal(int64(2)))
+	})
+
+	It("should give up on failed service instances whose retries are exhausted", func() {
+		c := fake.NewSimpleClientset(&cfv1alpha1.ServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "instance"},
+			Status: cfv1alpha1.ServiceInstanceStatus{
+				State: cfv1alpha1.ServiceInstanceStateError, RetryCounter: 3, MaxRetries: 3, Conditions: readyCondition(cfv1alpha1.ConditionFalse),
+			},
+		})
+		_, err := WaitForServiceInstanceReady(ctx, c, "app", "instance")
+		Expect(err).To(MatchError("service instance app/instance failed: broker failed"))
+	})
+
+	It("should return the binding secret once the binding is ready", func() {
+		c := fake.NewSimpleClientset(&cfv1alpha1.ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "binding"},
+			Spec:       cfv1alpha1.ServiceBindingSpec{SecretNamespace: "workload"},
+			Status: cfv1alpha1.ServiceBindingStatus{
+				SecretName: "binding-creds",
+				Conditions: []cfv1alpha1.ServiceBindingCondition{{Type: cfv1alpha1.ServiceBindingConditionReady, Status: cfv1alpha1.ConditionTrue}},
+			},
+		})
+		kc := kubefake.NewSimpleClientset()
+		go func() {
+			defer GinkgoRecover()
+			time.Sleep(50 * time.Millisecond)
+			_, err := kc.CoreV1().Secrets("workload").Create(context.Background(), &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "workload", Name: "binding-creds"},
+				Data:       map[string][]byte{"password": []byte("secret")},
+			}, metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+		}()
+
+		secret, err := WaitForBindingSecret(ctx, c, kc, "app", "binding")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secret.Data).To(HaveKeyWithValue("password", []byte("secret")))
+	})
+
+	It("should end waiting when the context is done", func() {
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		_, err := WaitForBindingSecret(ctx, fake.NewSimpleClientset(), kubefake.NewSimpleClientset(), "app", "missing")
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+	})
+})
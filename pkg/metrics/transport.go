@@ -6,9 +6,11 @@ SPDX-License-Identifier: Apache-2.0
 package metrics
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -19,6 +21,44 @@ const (
 	metricsSubsystem = "http_client"
 )
 
+// Optional labels of the HTTP client metrics, whose values are taken from the request context (see WithLabelValues).
+const (
+	// Name of the Cloud Foundry organization a request is sent for
+	LabelOrganization = "organization"
+	// GUID of the Cloud Foundry space a request is sent for
+	LabelSpace = "space"
+	// Name of the controller sending a request
+	LabelController = "controller"
+)
+
+// ContextLabels are the optional labels which may be passed to AddMetricsToTransport.
+var ContextLabels = []string{LabelOrganization, LabelSpace, LabelController}
+
+type labelValuesKey struct{}
+
+// WithLabelValues returns a copy of ctx carrying the given label values (in addition to the ones already carried by ctx);
+// requests sent with the returned context are attributed to these values, if the according labels were passed to AddMetricsToTransport.
+func WithLabelValues(ctx context.Context, values map[string]string) context.Context {
+	merged := make(map[string]string)
+	if existing, ok := ctx.Value(labelValuesKey{}).(map[string]string); ok {
+		for name, value := range existing {
+			merged[name] = value
+		}
+	}
+	for name, value := range values {
+		merged[name] = value
+	}
+	return context.WithValue(ctx, labelValuesKey{}, merged)
+}
+
+// labelValueFromContext returns a function reading the value of the given label from a context (empty if not set).
+func labelValueFromContext(name string) promhttp.LabelValueFromCtx {
+	return func(ctx context.Context) string {
+		values, _ := ctx.Value(labelValuesKey{}).(map[string]string)
+		return values[name]
+	}
+}
+
 // IndependentExecutionGeneral runs several operations independently of each other and only propagates the error value
 func IndependentExecutionGeneral(fns ...func() error) error {
 	var combinedError error
@@ -38,11 +78,20 @@ func IndependentExecutionGeneral(fns ...func() error) error {
 	return combinedError
 }
 
-// AddMetricsToTransport injects the Prometheus metrics to the HTTP transport
-func AddMetricsToTransport(transport http.RoundTripper, registry prometheus.Registerer, target string, host string) (http.RoundTripper, error) {
+// AddMetricsToTransport injects the Prometheus metrics to the HTTP transport;
+// the request counter and latency histogram are additionally labeled by the given context labels (see ContextLabels).
+// Note that every context label multiplies the number of time series, so labels should only be enabled if their values are bounded.
+func AddMetricsToTransport(transport http.RoundTripper, registry prometheus.Registerer, target string, host string, contextLabels ...string) (http.RoundTripper, error) {
 	if transport == nil {
 		transport = http.DefaultTransport
 	}
+	var options []promhttp.Option
+	for _, label := range contextLabels {
+		if !slices.Contains(ContextLabels, label) {
+			return nil, fmt.Errorf("unsupported metrics label %s", label)
+		}
+		options = append(options, promhttp.WithLabelFromCtx(label, labelValueFromContext(label)))
+	}
 	constLabels := prometheus.Labels{
 		"target": target,
 		"host":   host,
@@ -64,7 +113,7 @@ func AddMetricsToTransport(transport http.RoundTripper, registry prometheus.Regi
 			Help:        "The number of HTTP requests from corresponding HTTP client",
 			ConstLabels: constLabels,
 		},
-		[]string{"code", "method"},
+		append([]string{"code", "method"}, contextLabels...),
 	)
 	histVec := prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -75,7 +124,7 @@ func AddMetricsToTransport(transport http.RoundTripper, registry prometheus.Regi
 			Buckets:     prometheus.DefBuckets,
 			ConstLabels: constLabels,
 		},
-		[]string{},
+		append([]string{}, contextLabels...),
 	)
 
 	// register created metrics or replace them by already registered ones
@@ -125,8 +174,8 @@ func AddMetricsToTransport(transport http.RoundTripper, registry prometheus.Regi
 	)
 
 	transport = promhttp.InstrumentRoundTripperInFlight(inFlightGauge, transport)
-	transport = promhttp.InstrumentRoundTripperCounter(counter, transport)
-	transport = promhttp.InstrumentRoundTripperDuration(histVec, transport)
+	transport = promhttp.InstrumentRoundTripperCounter(counter, transport, options...)
+	transport = promhttp.InstrumentRoundTripperDuration(histVec, transport, options...)
 
 	return transport, err
 }
@@ -0,0 +1,113 @@
+/*
+SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and cf-service-operator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+var _ = Describe("Context labels of HTTP client metrics | AddMetricsToTransport, WithLabelValues", func() {
+	var registry *prometheus.Registry
+	var transport http.RoundTripper
+
+	BeforeEach(func() {
+		registry = prometheus.NewRegistry()
+		transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}")), Request: req}, nil
+		})
+	})
+
+	send := func(transport http.RoundTripper, ctx context.Context) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.cf.example.com/v3/spaces", nil)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := transport.RoundTrip(req)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+	}
+
+	// requestCounts returns the request counts by the values of the given labels (joined by comma)
+	requestCounts := func(labels ...string) map[string]float64 {
+		families, err := registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+		counts := make(map[string]float64)
+		for _, family := range families {
+			if family.GetName() != "http_client_requests_total" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				values := make([]string, len(labels))
+				for i, label := range labels {
+					values[i] = labelValue(metric, label)
+				}
+				counts[strings.Join(values, ",")] += metric.GetCounter().GetValue()
+			}
+		}
+		return counts
+	}
+
+	It("should label requests by the values carried by the request context", func() {
+		instrumented, err := AddMetricsToTransport(transport, registry, "cf-api", "https://api.cf.example.com", LabelSpace, LabelController)
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx := WithLabelValues(context.Background(), map[string]string{LabelController: "ServiceInstance"})
+		send(instrumented, WithLabelValues(ctx, map[string]string{LabelSpace: "space-1"}))
+		send(instrumented, WithLabelValues(ctx, map[string]string{LabelSpace: "space-1"}))
+		send(instrumented, WithLabelValues(ctx, map[string]string{LabelSpace: "space-2", LabelOrganization: "org"}))
+		send(instrumented, context.Background())
+
+		Expect(requestCounts(LabelSpace, LabelController, LabelOrganization, "host")).To(Equal(map[string]float64{
+			"space-1,ServiceInstance,,https://api.cf.example.com": 2,
+			"space-2,ServiceInstance,,https://api.cf.example.com": 1,
+			",,,https://api.cf.example.com":                       1,
+		}))
+	})
+
+	It("should not add context labels unless requested", func() {
+		instrumented, err := AddMetricsToTransport(transport, registry, "cf-api", "https://api.cf.example.com")
+		Expect(err).NotTo(HaveOccurred())
+		send(instrumented, WithLabelValues(context.Background(), map[string]string{LabelSpace: "space-1"}))
+
+		Expect(requestCounts(LabelSpace)).To(Equal(map[string]float64{"": 1}))
+	})
+
+	It("should reject unsupported labels", func() {
+		_, err := AddMetricsToTransport(transport, registry, "cf-api", "https://api.cf.example.com", "namespace")
+		Expect(err).To(MatchError(ContainSubstring("unsupported metrics label namespace")))
+	})
+
+	It("should let inner label values take precedence, without changing the outer context", func() {
+		outer := WithLabelValues(context.Background(), map[string]string{LabelSpace: "space-1", LabelController: "Space"})
+		inner := WithLabelValues(outer, map[string]string{LabelSpace: "space-2"})
+
+		Expect(labelValueFromContext(LabelSpace)(inner)).To(Equal("space-2"))
+		Expect(labelValueFromContext(LabelController)(inner)).To(Equal("Space"))
+		Expect(labelValueFromContext(LabelSpace)(outer)).To(Equal("space-1"))
+		Expect(labelValueFromContext(LabelOrganization)(context.Background())).To(BeEmpty())
+	})
+})
+
+func labelValue(metric *dto.Metric, name string) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+	return ""
+}